@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
 	"cloud.google.com/go/firestore"
 	"github.com/gin-gonic/gin"
 
@@ -20,8 +21,14 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
+	events "github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
+var ErrAnomalyNotFound = errors.New("anomaly not found")
+
+const dispatchAnomalyPathTemplate = "/tasks/zapier/anomalies/%s/%s"
+
 // swagger:parameters idOfAnomalies
 type AnomalyParams struct {
 	// Min value for the anomaly detection time
@@ -609,3 +616,80 @@ func GetAnomaly(ctx *gin.Context, conn *connection.Connection) {
 		AbortMsg(ctx, http.StatusNotFound, nil, ErrorNotFound)
 	}
 }
+
+// DispatchAnomaly sends the cost anomaly detected event to the webhook subscriptions of the anomaly customer
+func DispatchAnomaly(ctx *gin.Context, conn *connection.Connection, eventDispatcher dispatch.Dispatcher) error {
+	l := logger.FromContext(ctx)
+	fs := conn.Firestore(ctx)
+
+	customerID := ctx.Param("customerID")
+	customerRef := fs.Collection("customers").Doc(customerID)
+
+	alertID := ctx.Param("anomalyID")
+
+	docSnaps, err := fs.CollectionGroup("billingAnomalies").Where("customer", "==", customerRef).Where("metadata.alert_id", "==", alertID).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	if len(docSnaps) == 0 {
+		return ErrAnomalyNotFound
+	}
+
+	anomaly, err := prepareAnomaly(ctx, fs, docSnaps[0], l)
+	if err != nil {
+		return err
+	}
+
+	return eventDispatcher.Dispatch(ctx, AnomalyItem{ID: alertID, Anomaly: *anomaly}, customerRef, customerID, events.CostAnomalyDetected)
+}
+
+// EnqueueAnomaliesDispatch creates a dispatch task for every anomaly recorded in the previous full hour.
+// It is scheduled hourly, so every anomaly is sent once to the webhook subscriptions of its customer.
+func EnqueueAnomaliesDispatch(ctx context.Context, conn *connection.Connection, now time.Time) error {
+	l := logger.FromContext(ctx)
+
+	end := now.UTC().Truncate(time.Hour)
+	start := end.Add(-time.Hour)
+
+	docSnaps, err := conn.Firestore(ctx).CollectionGroup("billingAnomalies").
+		Where("timestamp", ">=", start).
+		Where("timestamp", "<", end).
+		Select("customer", "metadata.alert_id").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, docSnap := range docSnaps {
+		var anomaly struct {
+			Customer *firestore.DocumentRef `firestore:"customer"`
+			MetaData struct {
+				AlertID string `firestore:"alert_id"`
+			} `firestore:"metadata"`
+		}
+
+		if err := docSnap.DataTo(&anomaly); err != nil {
+			return err
+		}
+
+		if anomaly.Customer == nil || anomaly.MetaData.AlertID == "" {
+			l.Warningf("skipping anomaly %s without customer or alert id", docSnap.Ref.Path)
+			continue
+		}
+
+		config := common.CloudTaskConfig{
+			Method: cloudtaskspb.HttpMethod_POST,
+			Path:   fmt.Sprintf(dispatchAnomalyPathTemplate, anomaly.Customer.ID, anomaly.MetaData.AlertID),
+			Queue:  common.TaskQueueZapierWebhooks,
+		}
+
+		if _, err := conn.CloudTaskClient.CreateTask(ctx, config.Config(nil)); err != nil {
+			return err
+		}
+	}
+
+	l.Infof("enqueued %d anomalies recorded between %s and %s", len(docSnaps), start.Format(time.RFC3339), end.Format(time.RFC3339))
+
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
 	"github.com/doitintl/hello/scheduled-tasks/slice"
 )
 
@@ -80,6 +81,9 @@ type Budget struct {
 	// List of slack channels to notify when reaching alert threshold
 	// default: []
 	RecipientsSlackChannels []common.SlackChannel `json:"recipientsSlackChannels"`
+	// List of Microsoft Teams channels to notify when reaching alert threshold
+	// default: []
+	RecipientsMSTeamsChannels []common.MSTeamsChannel `json:"recipientsMSTeamsChannels"`
 	// List of attributions that defines that budget scope
 	// required: true
 	Scope []string `json:"scope"`
@@ -143,6 +147,9 @@ type BudgetCreateUpdateRequest struct {
 	// List of slack channels to notify when reaching alert threshold
 	// default: []
 	RecipientsSlackChannels []common.SlackChannel `json:"recipientsSlackChannels"`
+	// List of Microsoft Teams channels to notify when reaching alert threshold
+	// default: []
+	RecipientsMSTeamsChannels []common.MSTeamsChannel `json:"recipientsMSTeamsChannels"`
 	// List of attributions that defines that budget scope
 	Scope []string `json:"scope"`
 	// Budget period amount
@@ -344,6 +351,11 @@ func (s *APIV1Service) mapRequestToInternalBudget(ctx context.Context, b Budget,
 		recipientsSlackChannels = b.RecipientsSlackChannels
 	}
 
+	recipientsMSTeamsChannels, err := s.validateMSTeamsChannels(b.RecipientsMSTeamsChannels)
+	if err != nil {
+		return nil, err
+	}
+
 	description := s.validateDescription(b.Description)
 
 	if b.Name == nil || *b.Name == "" {
@@ -403,13 +415,14 @@ func (s *APIV1Service) mapRequestToInternalBudget(ctx context.Context, b Budget,
 			Collaborators: b.Collaborators,
 			Public:        b.Public,
 		},
-		Name:                    *name,
-		Description:             description,
-		TimeModified:            time.Now().UTC(),
-		Customer:                customerRef,
-		Recipients:              recipients,
-		IsValid:                 true,
-		RecipientsSlackChannels: recipientsSlackChannels,
+		Name:                      *name,
+		Description:               description,
+		TimeModified:              time.Now().UTC(),
+		Customer:                  customerRef,
+		Recipients:                recipients,
+		IsValid:                   true,
+		RecipientsSlackChannels:   recipientsSlackChannels,
+		RecipientsMSTeamsChannels: recipientsMSTeamsChannels,
 	}
 	if b.CreateTime != 0 {
 		internalBudget.TimeCreated = time.Unix(b.CreateTime, 0)
@@ -476,29 +489,30 @@ func (s *APIV1Service) mapInternalBudgetToResponseBudget(internalBudget *budget.
 	}
 
 	b := Budget{
-		ID:                      &internalBudget.ID,
-		Name:                    &internalBudget.Name,
-		Description:             &internalBudget.Description,
-		Public:                  internalBudget.Public,
-		Alerts:                  &alerts,
-		Collaborators:           internalBudget.Collaborators,
-		Recipients:              internalBudget.Recipients,
-		RecipientsSlackChannels: internalBudget.RecipientsSlackChannels,
-		Scope:                   scope,
-		Amount:                  &internalBudget.Config.Amount,
-		Currency:                &currency,
-		GrowthPerPeriod:         &internalBudget.Config.GrowthPerPeriod,
-		TimeInterval:            &timeInterval,
-		CurrentUtilization:      currentUtilization,
-		ForecastedUtilization:   forcastedUtilization,
-		Metric:                  &metric,
-		Type:                    &budgetType,
-		EndPeriod:               &endPeriod,
-		StartPeriod:             &startPeriod,
-		CreateTime:              internalBudget.TimeCreated.UnixMilli(),
-		UpdateTime:              internalBudget.TimeModified.UnixMilli(),
-		UsePrevSpend:            &internalBudget.Config.UsePrevSpend,
-		DataSource:              dataSource,
+		ID:                        &internalBudget.ID,
+		Name:                      &internalBudget.Name,
+		Description:               &internalBudget.Description,
+		Public:                    internalBudget.Public,
+		Alerts:                    &alerts,
+		Collaborators:             internalBudget.Collaborators,
+		Recipients:                internalBudget.Recipients,
+		RecipientsSlackChannels:   internalBudget.RecipientsSlackChannels,
+		RecipientsMSTeamsChannels: internalBudget.RecipientsMSTeamsChannels,
+		Scope:                     scope,
+		Amount:                    &internalBudget.Config.Amount,
+		Currency:                  &currency,
+		GrowthPerPeriod:           &internalBudget.Config.GrowthPerPeriod,
+		TimeInterval:              &timeInterval,
+		CurrentUtilization:        currentUtilization,
+		ForecastedUtilization:     forcastedUtilization,
+		Metric:                    &metric,
+		Type:                      &budgetType,
+		EndPeriod:                 &endPeriod,
+		StartPeriod:               &startPeriod,
+		CreateTime:                internalBudget.TimeCreated.UnixMilli(),
+		UpdateTime:                internalBudget.TimeModified.UnixMilli(),
+		UsePrevSpend:              &internalBudget.Config.UsePrevSpend,
+		DataSource:                dataSource,
 	}
 
 	return &b, nil
@@ -733,6 +747,18 @@ func (s *APIV1Service) getBudgetUpdates(ctx context.Context, budget Budget, curr
 		)
 	}
 
+	if budget.RecipientsMSTeamsChannels != nil {
+		recipientsMSTeamsChannels, err := s.validateMSTeamsChannels(budget.RecipientsMSTeamsChannels)
+		if err != nil {
+			return nil, err
+		}
+
+		updates = append(updates, firestore.Update{
+			Path:  "recipientsMSTeamsChannels",
+			Value: recipientsMSTeamsChannels,
+		})
+	}
+
 	if budget.Recipients != nil {
 		recipients, err := s.getRecipientsUpdates(budget, currentBudget)
 		if err != nil {
@@ -1039,6 +1065,20 @@ func (s *APIV1Service) validateDescription(description *string) string {
 	return *description
 }
 
+func (s *APIV1Service) validateMSTeamsChannels(channels []common.MSTeamsChannel) ([]common.MSTeamsChannel, error) {
+	for _, channel := range channels {
+		if err := msteams.ValidateWebhookURL(channel.WebhookURL); err != nil {
+			return nil, errors.New(ErrorBudgetInvalidMSTeamsChannel)
+		}
+	}
+
+	if channels == nil {
+		return make([]common.MSTeamsChannel, 0), nil
+	}
+
+	return channels, nil
+}
+
 func (s *APIV1Service) validateRecipients(recipients []string, collaborators []collab.Collaborator) ([]string, error) {
 	owner, err := s.getOwnerEmailFromCollaborators(collaborators)
 	if err != nil {
@@ -41,6 +41,7 @@ const (
 	ErrorBudgetRecurringWithEndPeriod     = "recurring budget can not have end period"
 	ErrorBudgetFixedWithoutEndPeriod      = "fixed budget must have end period"
 	ErrorBudgetInvalidDataSource          = "invalid budget data source"
+	ErrorBudgetInvalidMSTeamsChannel      = "invalid Microsoft Teams channel webhook url"
 )
 
 // Limit for API list query parameter: maxResults
@@ -2,6 +2,7 @@ package bq
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"cloud.google.com/go/bigquery"
@@ -33,3 +34,27 @@ func (d *BigqueryDAL) RunAggregatedJobStatisticsQuery(
 
 	return doitBQ.LoadRows[bqmodels.AggregatedJobStatistic](iter)
 }
+
+func (d *BigqueryDAL) RunSlotsConsumptionQuery(
+	ctx context.Context,
+	bq *bigquery.Client,
+	projectID string,
+	location string,
+	lookbackDays int,
+) ([]bqmodels.SlotsConsumption, error) {
+	replacer := strings.NewReplacer(
+		"{projectIdPlaceHolder}", projectID,
+		"{datasetIdPlaceHolder}", bqLensDomain.DoitCmpDatasetID,
+		"{locationPlaceHolder}", location,
+		"{lookbackDaysPlaceHolder}", strconv.Itoa(lookbackDays),
+	)
+
+	query := replacer.Replace(bqmodels.SlotsConsumptionQuery)
+
+	iter, err := d.RunQuery(ctx, bq, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return doitBQ.LoadRows[bqmodels.SlotsConsumption](iter)
+}
@@ -43,6 +43,14 @@ type Bigquery interface {
 		location string,
 	) ([]bqmodels.AggregatedJobStatistic, error)
 
+	RunSlotsConsumptionQuery(
+		ctx context.Context,
+		bq *bigquery.Client,
+		projectID string,
+		location string,
+		lookbackDays int,
+	) ([]bqmodels.SlotsConsumption, error)
+
 	RunTableStorageTBQuery(
 		ctx context.Context,
 		query string,
@@ -183,6 +191,22 @@ type Bigquery interface {
 		_ bqmodels.TimeRange,
 	) ([]bqmodels.OnDemandSlotsExplorerResult, error)
 
+	RunQueryAntiPatternsQuery(
+		ctx context.Context,
+		query string,
+		replacements domain.Replacements,
+		bq *bigquery.Client,
+		_ bqmodels.TimeRange,
+	) ([]bqmodels.QueryAntiPatternsResult, error)
+
+	RunRepeatedQueriesQuery(
+		ctx context.Context,
+		query string,
+		replacements domain.Replacements,
+		bq *bigquery.Client,
+		_ bqmodels.TimeRange,
+	) ([]bqmodels.RepeatedQueriesResult, error)
+
 	RunBillingProjectsWithEditionsQuery(
 		ctx context.Context,
 		query string,
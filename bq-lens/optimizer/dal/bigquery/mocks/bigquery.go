@@ -867,6 +867,66 @@ func (_m *Bigquery) RunQuery(ctx context.Context, bq *bigquery.Client, query str
 	return r0, r1
 }
 
+// RunQueryAntiPatternsQuery provides a mock function with given fields: ctx, query, replacements, bq, _a4
+func (_m *Bigquery) RunQueryAntiPatternsQuery(ctx context.Context, query string, replacements domain.Replacements, bq *bigquery.Client, _a4 bqmodels.TimeRange) ([]bqmodels.QueryAntiPatternsResult, error) {
+	ret := _m.Called(ctx, query, replacements, bq, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunQueryAntiPatternsQuery")
+	}
+
+	var r0 []bqmodels.QueryAntiPatternsResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Replacements, *bigquery.Client, bqmodels.TimeRange) ([]bqmodels.QueryAntiPatternsResult, error)); ok {
+		return rf(ctx, query, replacements, bq, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Replacements, *bigquery.Client, bqmodels.TimeRange) []bqmodels.QueryAntiPatternsResult); ok {
+		r0 = rf(ctx, query, replacements, bq, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bqmodels.QueryAntiPatternsResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Replacements, *bigquery.Client, bqmodels.TimeRange) error); ok {
+		r1 = rf(ctx, query, replacements, bq, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunRepeatedQueriesQuery provides a mock function with given fields: ctx, query, replacements, bq, _a4
+func (_m *Bigquery) RunRepeatedQueriesQuery(ctx context.Context, query string, replacements domain.Replacements, bq *bigquery.Client, _a4 bqmodels.TimeRange) ([]bqmodels.RepeatedQueriesResult, error) {
+	ret := _m.Called(ctx, query, replacements, bq, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunRepeatedQueriesQuery")
+	}
+
+	var r0 []bqmodels.RepeatedQueriesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Replacements, *bigquery.Client, bqmodels.TimeRange) ([]bqmodels.RepeatedQueriesResult, error)); ok {
+		return rf(ctx, query, replacements, bq, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Replacements, *bigquery.Client, bqmodels.TimeRange) []bqmodels.RepeatedQueriesResult); ok {
+		r0 = rf(ctx, query, replacements, bq, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bqmodels.RepeatedQueriesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Replacements, *bigquery.Client, bqmodels.TimeRange) error); ok {
+		r1 = rf(ctx, query, replacements, bq, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunScheduledQueriesMovementQuery provides a mock function with given fields: ctx, query, replacements, bq, timeRange
 func (_m *Bigquery) RunScheduledQueriesMovementQuery(ctx context.Context, query string, replacements domain.Replacements, bq *bigquery.Client, timeRange bqmodels.TimeRange) ([]bqmodels.ScheduledQueriesMovementResult, error) {
 	ret := _m.Called(ctx, query, replacements, bq, timeRange)
@@ -897,6 +957,36 @@ func (_m *Bigquery) RunScheduledQueriesMovementQuery(ctx context.Context, query
 	return r0, r1
 }
 
+// RunSlotsConsumptionQuery provides a mock function with given fields: ctx, bq, projectID, location, lookbackDays
+func (_m *Bigquery) RunSlotsConsumptionQuery(ctx context.Context, bq *bigquery.Client, projectID string, location string, lookbackDays int) ([]bqmodels.SlotsConsumption, error) {
+	ret := _m.Called(ctx, bq, projectID, location, lookbackDays)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunSlotsConsumptionQuery")
+	}
+
+	var r0 []bqmodels.SlotsConsumption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *bigquery.Client, string, string, int) ([]bqmodels.SlotsConsumption, error)); ok {
+		return rf(ctx, bq, projectID, location, lookbackDays)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *bigquery.Client, string, string, int) []bqmodels.SlotsConsumption); ok {
+		r0 = rf(ctx, bq, projectID, location, lookbackDays)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bqmodels.SlotsConsumption)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *bigquery.Client, string, string, int) error); ok {
+		r1 = rf(ctx, bq, projectID, location, lookbackDays)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunStandardBillingProjectSlots provides a mock function with given fields: ctx, _a1, replacements, bq, timeRange
 func (_m *Bigquery) RunStandardBillingProjectSlots(ctx context.Context, _a1 string, replacements domain.Replacements, bq *bigquery.Client, timeRange bqmodels.TimeRange) (*bqmodels.RunStandardBillingProjectResult, error) {
 	ret := _m.Called(ctx, _a1, replacements, bq, timeRange)
@@ -36,6 +36,36 @@ func (d *BigqueryDAL) RunOnDemandSlotsExplorerQuery(
 	return doitBQ.LoadRows[bqmodels.OnDemandSlotsExplorerResult](iter)
 }
 
+func (d *BigqueryDAL) RunQueryAntiPatternsQuery(
+	ctx context.Context,
+	query string,
+	_ domain.Replacements,
+	bq *bigquery.Client,
+	_ bqmodels.TimeRange,
+) ([]bqmodels.QueryAntiPatternsResult, error) {
+	iter, err := d.RunQuery(ctx, bq, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return doitBQ.LoadRows[bqmodels.QueryAntiPatternsResult](iter)
+}
+
+func (d *BigqueryDAL) RunRepeatedQueriesQuery(
+	ctx context.Context,
+	query string,
+	_ domain.Replacements,
+	bq *bigquery.Client,
+	_ bqmodels.TimeRange,
+) ([]bqmodels.RepeatedQueriesResult, error) {
+	iter, err := d.RunQuery(ctx, bq, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return doitBQ.LoadRows[bqmodels.RepeatedQueriesResult](iter)
+}
+
 func (d *BigqueryDAL) RunOnDemandBillingProjectQuery(
 	ctx context.Context,
 	_ string,
@@ -52,6 +52,8 @@ const (
 	slots                     = "slots"
 	jobsSinks                 = "jobs-sinks"
 	jobsSinksMetadata         = "jobsSinksMetadata"
+	queryAntiPatterns         = "queryAntiPatterns"
+	repeatedQueries           = "repeatedQueries"
 )
 
 type RecommendationSummary map[dm.QueryName]TimeRangeRecommendation
@@ -321,6 +323,12 @@ func (d *OptimizerDAL) getDocumentRefBasedOnQuery(customerID, timeFrame string,
 	case dm.SlotsExplorerOnDemand:
 		return d.recommenderOnDemandCollection().Doc(customerID).Collection(explorer).Doc(timeFrame), nil
 
+	case dm.QueryAntiPatterns:
+		return d.recommenderOnDemandCollection().Doc(customerID).Collection(queryAntiPatterns).Doc(timeFrame), nil
+
+	case dm.RepeatedQueries:
+		return d.recommenderOnDemandCollection().Doc(customerID).Collection(repeatedQueries).Doc(timeFrame), nil
+
 	case dm.BillingProjectScanPrice, dm.BillingProjectTopUsersScanPrice, dm.BillingProjectTopQueriesScanPrice:
 		return d.recommenderOnDemandCollection().Doc(customerID).Collection(rollUps).Doc(timeFrame).Collection(billingProject).Doc(scanPrice), nil
 
@@ -49,6 +49,8 @@ const (
 	PartitionTables       QueryName = "partitionTables"
 	ClusterTables         QueryName = "clusterTables"
 	SlotsExplorerOnDemand QueryName = "slotsExplorer-onDemand"
+	QueryAntiPatterns     QueryName = "queryAntiPatterns"
+	RepeatedQueries       QueryName = "repeatedQueries"
 
 	BillingProject           QueryName = "billingProject"
 	BillingProjectTopUsers   QueryName = "billingProjectTopUsers"
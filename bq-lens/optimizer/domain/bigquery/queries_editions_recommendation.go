@@ -1,5 +1,7 @@
 package bqmodels
 
+import "time"
+
 type AggregatedJobStatistic struct {
 	Location         string `bigquery:"location"`
 	ProjectID        string `bigquery:"projectId"`
@@ -7,3 +9,8 @@ type AggregatedJobStatistic struct {
 	TotalSlotsMS     int    `bigquery:"totalSlotMs"`
 	TotalBilledBytes int    `bigquery:"totalBilledBytes"`
 }
+
+type SlotsConsumption struct {
+	Minute time.Time `bigquery:"minute"`
+	Slots  float64   `bigquery:"slots"`
+}
@@ -72,6 +72,8 @@ var OnDemandQueries = map[QueryName]string{
 	TableTopQueries:       tableTopQueries,
 	TableTopUsers:         tableTopUsers,
 	PhysicalStorage:       physicalStorage,
+	QueryAntiPatterns:     queryAntiPatterns,
+	RepeatedQueries:       repeatedQueries,
 }
 
 var UserSlotsQueries = map[QueryName]string{
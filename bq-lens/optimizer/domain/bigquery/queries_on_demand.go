@@ -35,6 +35,37 @@ type UsePartitionFieldResult struct {
 	DDL              string  `bigquery:"ddl"`
 }
 
+type QueryAntiPatternsResult struct {
+	JobID            string                  `bigquery:"jobId"`
+	Location         string                  `bigquery:"location"`
+	BillingProjectID string                  `bigquery:"billingProjectId"`
+	UserID           string                  `bigquery:"userId"`
+	Query            string                  `bigquery:"query"`
+	ExecutedQueries  int64                   `bigquery:"executedQueries"`
+	TotalScanTB      float64                 `bigquery:"totalScanTB"`
+	ReferencedTables []ReferencedTableResult `bigquery:"referencedTables"`
+}
+
+type ReferencedTableResult struct {
+	TableID        string              `bigquery:"tableId"`
+	PartitionField bigquery.NullString `bigquery:"partitionField"`
+	DDL            bigquery.NullString `bigquery:"ddl"`
+}
+
+type RepeatedQueriesResult struct {
+	JobID            string    `bigquery:"jobId"`
+	Location         string    `bigquery:"location"`
+	BillingProjectID string    `bigquery:"billingProjectId"`
+	UserID           string    `bigquery:"userId"`
+	Query            string    `bigquery:"query"`
+	ExecutedQueries  int64     `bigquery:"executedQueries"`
+	FirstExecution   time.Time `bigquery:"firstExecution"`
+	LastExecution    time.Time `bigquery:"lastExecution"`
+	TotalScanTB      float64   `bigquery:"totalScanTB"`
+	TotalSlotMs      int64     `bigquery:"totalSlotMs"`
+	TablesUnchanged  bool      `bigquery:"tablesUnchanged"`
+}
+
 type PartitionTablesResult struct {
 	QueryHash       string  `bigquery:"queryHash"`
 	Query           string  `bigquery:"query"`
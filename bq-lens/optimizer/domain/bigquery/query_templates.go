@@ -1360,3 +1360,199 @@ WHERE
 ORDER BY
   totalScanTB DESC
 `
+
+const queryAntiPatterns = `
+WITH
+{jobsDeduplicatedWithClause}
+unnested AS (
+  SELECT
+    queryHash,
+    tRef.projectId,
+    tRef.datasetId,
+    tRef.tableId
+  FROM
+    jobsDeduplicated,
+    UNNEST(referencedTables) tRef
+),
+user_tables AS (
+  SELECT
+    project_id AS projectId,
+    dataset_id AS datasetId,
+    table_id AS tableId,
+    partition_info AS partitionField,
+    ddl,
+    ROW_NUMBER() OVER(PARTITION BY project_id, dataset_id, table_id ORDER BY ts DESC) AS _rnk
+  FROM
+    ` + "`{projectIdPlaceHolder}.{datasetIdPlaceHolder}.{tablesDiscoveryTable}`" + `
+  WHERE
+    DATE(_PARTITIONTIME) >= DATE(DATETIME_SUB(CURRENT_DATETIME(), INTERVAL 1 DAY)) ),
+topQueries AS (
+  SELECT
+    queryHash,
+    MAX(CONCAT(jobId, '&', location, '&', billingProjectId, '&', user_email)) AS jobInfo,
+    MAX(query) AS query,
+    CAST(COUNT(jobId) AS INT64) AS executedQueries,
+    ROUND(SUM(totalBilledBytes / POW(1024,4)), 4) AS totalScanTB
+  FROM
+    jobsDeduplicated
+  WHERE
+    totalBilledBytes > 0
+    AND query IS NOT NULL
+  GROUP BY
+    queryHash
+  ORDER BY
+    totalScanTB DESC
+  LIMIT
+    100 ),
+queryTables AS (
+  SELECT DISTINCT
+    u.queryHash,
+    CONCAT(u.projectId, ".", u.datasetId, ".", u.tableId) AS tableId,
+    t.partitionField,
+    t.ddl
+  FROM
+    unnested u
+  JOIN
+    topQueries q
+  ON
+    u.queryHash = q.queryHash
+  LEFT JOIN
+    user_tables t
+  ON
+    u.projectId = t.projectId
+    AND u.datasetId = t.datasetId
+    AND u.tableId = t.tableId
+    AND t._rnk = 1 )
+SELECT
+  SPLIT(q.jobInfo, '&')[OFFSET(0)] AS jobId,
+  SPLIT(q.jobInfo, '&')[OFFSET(1)] AS location,
+  SPLIT(q.jobInfo, '&')[OFFSET(2)] AS billingProjectId,
+  SPLIT(q.jobInfo, '&')[OFFSET(3)] AS userId,
+  q.query,
+  q.executedQueries,
+  q.totalScanTB,
+  ARRAY(
+    SELECT AS STRUCT
+      tableId,
+      partitionField,
+      ddl
+    FROM
+      queryTables t
+    WHERE
+      t.queryHash = q.queryHash) AS referencedTables
+FROM
+  topQueries q
+ORDER BY
+  q.totalScanTB DESC
+`
+
+const repeatedQueries = `
+WITH
+{jobsDeduplicatedWithClause}
+fingerprintedJobs AS (
+  SELECT
+    *,
+    -- comments, literals, lists of literals, whitespace, backticks and letter case are dropped, so the
+    -- runs of the same query with different literals are ranked together
+    REGEXP_REPLACE(
+      REGEXP_REPLACE(
+        REGEXP_REPLACE(
+          REGEXP_REPLACE(LOWER(query), r'--[^\n]*|#[^\n]*|/\*(?s:.*?)\*/', ' '),
+          r"""'[^']*'|"[^"]*"|\b\d+(?:\.\d+)?\b""", '?'),
+        r'\?(?:\s*,\s*\?)+', '?'),
+      r'[\s\x60]+', ' ') AS fingerprint
+  FROM
+    jobsDeduplicated
+  WHERE
+    query IS NOT NULL ),
+topFingerprints AS (
+  SELECT
+    fingerprint
+  FROM
+    fingerprintedJobs
+  GROUP BY
+    fingerprint
+  ORDER BY
+    SUM(totalBilledBytes) DESC,
+    IFNULL(SUM(totalSlotMs), 0) DESC
+  LIMIT
+    1000 ),
+queries AS (
+  SELECT
+    queryHash,
+    MAX(CONCAT(jobId, '&', location, '&', billingProjectId, '&', user_email)) AS jobInfo,
+    MAX(query) AS query,
+    CAST(COUNT(jobId) AS INT64) AS executedQueries,
+    MIN(startTime) AS firstExecution,
+    MAX(startTime) AS lastExecution,
+    ROUND(SUM(totalBilledBytes / POW(1024,4)), 4) AS totalScanTB,
+    IFNULL(SUM(totalSlotMs), 0) AS totalSlotMs
+  FROM
+    fingerprintedJobs
+  JOIN
+    topFingerprints
+  USING
+    (fingerprint)
+  GROUP BY
+    queryHash ),
+referenced AS (
+  SELECT DISTINCT
+    queryHash,
+    tRef.projectId,
+    tRef.datasetId,
+    tRef.tableId
+  FROM
+    jobsDeduplicated,
+    UNNEST(referencedTables) tRef ),
+user_tables AS (
+  SELECT
+    project_id AS projectId,
+    dataset_id AS datasetId,
+    table_id AS tableId,
+    storage_last_modified_time AS lastModifiedTime,
+    ROW_NUMBER() OVER(PARTITION BY project_id, dataset_id, table_id ORDER BY ts DESC) AS _rnk
+  FROM
+    ` + "`{projectIdPlaceHolder}.{datasetIdPlaceHolder}.{tablesDiscoveryTable}`" + `
+  WHERE
+    DATE(_PARTITIONTIME) >= DATE(DATETIME_SUB(CURRENT_DATETIME(), INTERVAL 1 DAY)) ),
+tablesChanges AS (
+  SELECT
+    r.queryHash,
+    -- tables were not modified since the first run of the query in the time range
+    LOGICAL_AND(t.lastModifiedTime IS NOT NULL AND t.lastModifiedTime < q.firstExecution) AS tablesUnchanged
+  FROM
+    referenced r
+  JOIN
+    queries q
+  ON
+    r.queryHash = q.queryHash
+  LEFT JOIN
+    user_tables t
+  ON
+    r.projectId = t.projectId
+    AND r.datasetId = t.datasetId
+    AND r.tableId = t.tableId
+    AND t._rnk = 1
+  GROUP BY
+    r.queryHash )
+SELECT
+  SPLIT(q.jobInfo, '&')[OFFSET(0)] AS jobId,
+  SPLIT(q.jobInfo, '&')[OFFSET(1)] AS location,
+  SPLIT(q.jobInfo, '&')[OFFSET(2)] AS billingProjectId,
+  SPLIT(q.jobInfo, '&')[OFFSET(3)] AS userId,
+  q.query,
+  q.executedQueries,
+  q.firstExecution,
+  q.lastExecution,
+  q.totalScanTB,
+  q.totalSlotMs,
+  IFNULL(c.tablesUnchanged, FALSE) AS tablesUnchanged
+FROM
+  queries q
+LEFT JOIN
+  tablesChanges c
+ON
+  q.queryHash = c.queryHash
+ORDER BY
+  q.totalScanTB DESC
+`
@@ -20,3 +20,42 @@ SELECT
 	  AND protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.reservation  = "unreserved"
     GROUP BY location, projectId
 `
+
+// SlotsConsumptionQuery spreads the slots of every job evenly over the minutes it ran and returns
+// the average slots consumed in every minute of the lookback window
+const SlotsConsumptionQuery = `
+WITH
+  jobs AS (
+  SELECT
+    protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.startTime AS startTime,
+    protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.endTime AS endTime,
+    protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.totalSlotMs AS totalSlotMs
+  FROM
+    {projectIdPlaceHolder}.{datasetIdPlaceHolder}.cloudaudit_googleapis_com_data_access
+  WHERE
+    protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobName.jobId IS NOT NULL
+    AND protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobName.jobId NOT LIKE 'script_job_%' -- filter BQ script child jobs
+    AND protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.eventName = 'query_job_completed'
+    AND protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobName.location = "{locationPlaceHolder}"
+    AND protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.totalSlotMs > 0
+    AND protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.endTime > protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.startTime
+    AND DATE(protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobStatistics.startTime) >= DATE_SUB(CURRENT_DATE(), INTERVAL {lookbackDaysPlaceHolder} DAY)
+    AND DATE(timestamp) >= DATE_SUB(CURRENT_DATE(), INTERVAL {lookbackDaysPlaceHolder} DAY) ),
+  jobMinutes AS (
+  SELECT
+    minute,
+    -- the slot milliseconds of the job in proportion to the time it ran in the minute
+    totalSlotMs * TIMESTAMP_DIFF(LEAST(endTime, TIMESTAMP_ADD(minute, INTERVAL 1 MINUTE)), GREATEST(startTime, minute), MILLISECOND) / TIMESTAMP_DIFF(endTime, startTime, MILLISECOND) AS slotMs
+  FROM
+    jobs,
+    UNNEST(GENERATE_TIMESTAMP_ARRAY(TIMESTAMP_TRUNC(startTime, MINUTE), TIMESTAMP_TRUNC(endTime, MINUTE), INTERVAL 1 MINUTE)) AS minute )
+SELECT
+  minute,
+  SUM(slotMs) / 60000 AS slots
+FROM
+  jobMinutes
+GROUP BY
+  minute
+ORDER BY
+  minute
+`
@@ -52,6 +52,15 @@ var (
 		"totalPhysicalCost":          "Physical cost",
 		"compressionRatio":           "Compression ratio",
 		"savings":                    "Potential Savings",
+		"antiPattern":                "Anti-Pattern",
+		"details":                    "Details",
+		"executedQueries":            "Query Executions",
+		"fingerprint":                "Fingerprint",
+		"normalizedQuery":            "Normalized Query",
+		"variants":                   "Query Variants",
+		"runsPerDay":                 "Runs Per Day",
+		"totalSlotMs":                "Total Slot Ms",
+		"recommendation":             "Recommendation",
 	}
 
 	ColumnsSigns = map[string]string{
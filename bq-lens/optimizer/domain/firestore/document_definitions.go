@@ -50,6 +50,16 @@ type RecommendationsDocument struct {
 	LastUpdate           time.Time            `firestore:"lastUpdate,omitempty"`
 }
 
+type QueryAntiPatternsDocument struct {
+	Data       QueryAntiPatterns `firestore:"queryAntiPatterns"`
+	LastUpdate time.Time         `firestore:"lastUpdate"`
+}
+
+type RepeatedQueriesDocument struct {
+	Data       RepeatedQueries `firestore:"repeatedQueries"`
+	LastUpdate time.Time       `firestore:"lastUpdate"`
+}
+
 type BillingProjectScanPriceDocument map[string]BillingProjectScanPrice
 type BillingProjectScanTBDocument map[string]BillingProjectScanTB
 type DatasetScanPriceDocument map[string]DatasetScanPrice
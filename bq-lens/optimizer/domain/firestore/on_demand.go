@@ -113,6 +113,49 @@ type PhysicalStorageDetailTable struct {
 	Savings           float64 `firestore:"savings"`
 }
 
+type QueryAntiPatterns struct {
+	DetailedTable              []QueryAntiPatternDetailTable `firestore:"detailedTable"`
+	DetailedTableFieldsMapping map[string]FieldDetail        `firestore:"detailedTableFieldsMapping"`
+	CommonRecommendation
+}
+
+type QueryAntiPatternDetailTable struct {
+	JobID            string  `firestore:"jobId"`
+	Location         string  `firestore:"location"`
+	BillingProjectID string  `firestore:"billingProjectId"`
+	UserID           string  `firestore:"userId"`
+	AntiPattern      string  `firestore:"antiPattern"`
+	Details          string  `firestore:"details"`
+	TableID          string  `firestore:"tableId"`
+	ExecutedQueries  int64   `firestore:"executedQueries"`
+	ScanTB           float64 `firestore:"scanTB"`
+	ScanPrice        float64 `firestore:"scanPrice"`
+	PotentialSavings float64 `firestore:"potentialSavings"`
+}
+
+type RepeatedQueries struct {
+	DetailedTable              []RepeatedQueryDetailTable `firestore:"detailedTable"`
+	DetailedTableFieldsMapping map[string]FieldDetail     `firestore:"detailedTableFieldsMapping"`
+	CommonRecommendation
+}
+
+type RepeatedQueryDetailTable struct {
+	Fingerprint      string  `firestore:"fingerprint"`
+	NormalizedQuery  string  `firestore:"normalizedQuery"`
+	JobID            string  `firestore:"jobId"`
+	Location         string  `firestore:"location"`
+	BillingProjectID string  `firestore:"billingProjectId"`
+	UserID           string  `firestore:"userId"`
+	Variants         int64   `firestore:"variants"`
+	ExecutedQueries  int64   `firestore:"executedQueries"`
+	RunsPerDay       float64 `firestore:"runsPerDay"`
+	TotalSlotMs      int64   `firestore:"totalSlotMs"`
+	ScanTB           float64 `firestore:"scanTB"`
+	ScanPrice        float64 `firestore:"scanPrice"`
+	Recommendation   string  `firestore:"recommendation"`
+	PotentialSavings float64 `firestore:"potentialSavings"`
+}
+
 type BillingProjectScanPrice struct {
 	BillingProjectID string                                 `firestore:"billingProjectId,omitempty"`
 	ScanPrice        float64                                `firestore:"scanPrice,omitempty"`
@@ -151,6 +151,9 @@ func getQueryPlaceholder(queryID bqmodels.QueryName) string {
 	case bqmodels.ClusterTables, bqmodels.PartitionTables, bqmodels.UsePartitionField:
 		return "protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobConfiguration.queryValue.queryValue"
 
+	case bqmodels.QueryAntiPatterns, bqmodels.RepeatedQueries:
+		return "protopayload_auditlog.servicedata_v1_bigquery.jobCompletedEvent.job.jobConfiguration.query.query"
+
 	default:
 		return "NULL"
 	}
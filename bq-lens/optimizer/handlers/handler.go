@@ -10,12 +10,18 @@ import (
 
 	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
 	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/autoscaling"
 	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/iface"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 )
 
+type editionsSimulationRequest struct {
+	BillingProjectWithReservation []domain.BillingProjectWithReservation `json:"billingProjectWithReservation" validate:"dive"`
+	Configurations                []autoscaling.Configuration            `json:"configurations" validate:"dive"`
+}
+
 type Optimizer struct {
 	loggerProvider logger.Provider
 	conn           *connection.Connection
@@ -94,3 +100,44 @@ func (h *Optimizer) AllCustomersOptimizer(ctx *gin.Context) error {
 
 	return web.Respond(ctx, nil, http.StatusOK)
 }
+
+func (h *Optimizer) SimulateEditionsAutoscaling(ctx *gin.Context) error {
+	customerID := ctx.Param("customerID")
+	if customerID == "" {
+		return web.NewRequestError(ErrMissingCustomerID, http.StatusBadRequest)
+	}
+
+	l := h.loggerProvider(ctx)
+
+	l.SetLabels(map[string]string{
+		"house":    "adoption",
+		"feature":  "bq-lens",
+		"module":   "optimizer",
+		"service":  "editions-simulation",
+		"customer": customerID,
+	})
+
+	var input editionsSimulationRequest
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	if err := validator.New().Struct(&input); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	for _, configuration := range input.Configurations {
+		if err := configuration.Validate(); err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+	}
+
+	results, err := h.service.SimulateEditionsAutoscaling(ctx, customerID, input.BillingProjectWithReservation, input.Configurations)
+	if err != nil {
+		l.Error(err)
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, results, http.StatusOK)
+}
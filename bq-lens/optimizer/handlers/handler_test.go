@@ -15,7 +15,9 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/autoscaling"
 	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/mocks"
+	pricebookDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/domain"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	loggerMocks "github.com/doitintl/hello/scheduled-tasks/logger/mocks"
@@ -249,3 +251,139 @@ func TestAllCustomersOptimizer(t *testing.T) {
 		})
 	}
 }
+
+func TestSimulateEditionsAutoscaling(t *testing.T) {
+	customerID := "test-customer-1"
+
+	billingProjects := []domain.BillingProjectWithReservation{
+		{
+			Project:  "doitintl-cmp-dev",
+			Location: "US",
+		},
+	}
+
+	configurations := []autoscaling.Configuration{
+		{
+			Edition:  pricebookDomain.Enterprise,
+			Baseline: 100,
+			MaxSlots: 400,
+		},
+	}
+
+	results := []autoscaling.Result{
+		{
+			Configuration: configurations[0],
+			Cost:          100,
+			QueueingRisk:  autoscaling.RiskNone,
+		},
+	}
+
+	simulationErr := errors.New("simulation error")
+
+	type fields struct {
+		service     *mocks.OptimizerService
+		loggerMocks *loggerMocks.ILogger
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		on           func(*fields, *gin.Context)
+		wantedStatus int
+	}{
+		{
+			name: "simulates the configurations",
+			body: `{
+				"billingProjectWithReservation": [{"project": "doitintl-cmp-dev", "location": "US"}],
+				"configurations": [{"edition": "enterprise", "baseline": 100, "maxSlots": 400}]
+			}`,
+			on: func(f *fields, ctx *gin.Context) {
+				f.loggerMocks.On("SetLabels", mock.Anything)
+				f.service.On("SimulateEditionsAutoscaling", ctx, customerID, billingProjects, configurations).
+					Return(results, nil).Once()
+			},
+			wantedStatus: http.StatusOK,
+		},
+		{
+			name: "simulates the candidates without configurations",
+			body: `{"billingProjectWithReservation": [{"project": "doitintl-cmp-dev", "location": "US"}]}`,
+			on: func(f *fields, ctx *gin.Context) {
+				f.loggerMocks.On("SetLabels", mock.Anything)
+				f.service.On("SimulateEditionsAutoscaling", ctx, customerID, billingProjects, []autoscaling.Configuration(nil)).
+					Return(results, nil).Once()
+			},
+			wantedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid edition",
+			body: `{"configurations": [{"edition": "legacy_flat_rate", "baseline": 100, "maxSlots": 400}]}`,
+			on: func(f *fields, ctx *gin.Context) {
+				f.loggerMocks.On("SetLabels", mock.Anything)
+			},
+			wantedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "max slots below the baseline",
+			body: `{"configurations": [{"edition": "enterprise", "baseline": 400, "maxSlots": 100}]}`,
+			on: func(f *fields, ctx *gin.Context) {
+				f.loggerMocks.On("SetLabels", mock.Anything)
+			},
+			wantedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "simulation failed",
+			body: `{
+				"billingProjectWithReservation": [{"project": "doitintl-cmp-dev", "location": "US"}],
+				"configurations": [{"edition": "enterprise", "baseline": 100, "maxSlots": 400}]
+			}`,
+			on: func(f *fields, ctx *gin.Context) {
+				f.loggerMocks.On("SetLabels", mock.Anything)
+				f.service.On("SimulateEditionsAutoscaling", ctx, customerID, billingProjects, configurations).
+					Return(nil, simulationErr).Once()
+				f.loggerMocks.On("Error", simulationErr).Once()
+			},
+			wantedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+
+			fields := fields{
+				service:     mocks.NewOptimizerService(t),
+				loggerMocks: loggerMocks.NewILogger(t),
+			}
+
+			h := &Optimizer{
+				service: fields.service,
+				loggerProvider: func(ctx context.Context) logger.ILogger {
+					return fields.loggerMocks
+				},
+			}
+
+			if tt.on != nil {
+				tt.on(&fields, ctx)
+			}
+
+			ctx.Params = []gin.Param{
+				{Key: "customerID", Value: customerID},
+			}
+			ctx.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/optimizer/%s/editions-simulation", customerID), strings.NewReader(tt.body))
+
+			err := h.SimulateEditionsAutoscaling(ctx)
+			if err == nil {
+				assert.Equal(t, tt.wantedStatus, recorder.Code)
+			} else {
+				var reqErr *web.Error
+
+				if errors.As(err, &reqErr) {
+					assert.Equal(t, tt.wantedStatus, reqErr.Status)
+				} else {
+					t.Fatalf("Unexpected error type: %v", err)
+				}
+			}
+		})
+	}
+}
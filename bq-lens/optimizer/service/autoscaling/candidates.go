@@ -0,0 +1,107 @@
+package autoscaling
+
+import (
+	"math"
+	"sort"
+
+	pricebookDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/domain"
+)
+
+var (
+	editions = []pricebookDomain.Edition{
+		pricebookDomain.Standard,
+		pricebookDomain.Enterprise,
+		pricebookDomain.EnterprisePlus,
+	}
+
+	// the baselines cover no, the median and the usual consumption, the max slots the busy hours
+	// and the peak of the consumption
+	baselinePercentiles = []float64{0, 0.5, 0.9}
+	maxSlotsPercentiles = []float64{0.95, 0.99, 1}
+)
+
+// Candidates returns the configurations worth simulating when the customer does not provide any,
+// based on the percentiles of the slots consumption of the samples
+func Candidates(samples []Sample) []Configuration {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	slots := make([]float64, len(samples))
+	for i, sample := range samples {
+		slots[i] = sample.Slots
+	}
+
+	sort.Float64s(slots)
+
+	var configurations []Configuration
+
+	seen := make(map[Configuration]bool)
+
+	for _, edition := range editions {
+		for _, baselinePercentile := range baselinePercentiles {
+			baseline := roundUpToIncrement(percentile(slots, baselinePercentile))
+
+			for _, maxSlotsPercentile := range maxSlotsPercentiles {
+				configuration := Configuration{
+					Edition:  edition,
+					Baseline: baseline,
+					MaxSlots: max(roundUpToIncrement(percentile(slots, maxSlotsPercentile)), baseline, SlotsIncrement),
+				}
+
+				if edition == pricebookDomain.Standard {
+					configuration.Baseline = min(configuration.Baseline, StandardMaxSlots)
+					configuration.MaxSlots = min(configuration.MaxSlots, StandardMaxSlots)
+				}
+
+				if seen[configuration] {
+					continue
+				}
+
+				seen[configuration] = true
+				configurations = append(configurations, configuration)
+			}
+		}
+	}
+
+	return configurations
+}
+
+// PricesForRegion returns the slot hour prices of every edition in the region
+func PricesForRegion(pricebooks pricebookDomain.PriceBooksByEdition, region string) map[pricebookDomain.Edition]Prices {
+	pricesByEdition := make(map[pricebookDomain.Edition]Prices)
+
+	for _, edition := range editions {
+		pricebook, ok := pricebooks[edition]
+		if !ok || pricebook == nil {
+			continue
+		}
+
+		prices := make(Prices)
+
+		for usageType, regions := range *pricebook {
+			if price, ok := regions[region]; ok {
+				prices[pricebookDomain.UsageType(usageType)] = price
+			}
+		}
+
+		pricesByEdition[edition] = prices
+	}
+
+	return pricesByEdition
+}
+
+// percentile returns the nearest-rank percentile of the sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[max(rank, 0)]
+}
+
+func roundUpToIncrement(slots float64) int64 {
+	return int64(math.Ceil(slots/SlotsIncrement)) * SlotsIncrement
+}
@@ -0,0 +1,281 @@
+package autoscaling
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	pricebookDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/domain"
+)
+
+const (
+	// SlotsIncrement is the number of slots the autoscaler adds or removes at a time
+	SlotsIncrement = 50
+	// MinimumAutoscaleDuration is the time autoscaled slots are billed for at least once they are allocated
+	MinimumAutoscaleDuration = 60 * time.Second
+	// StandardMaxSlots is the largest reservation of the Standard edition
+	StandardMaxSlots = 1600
+)
+
+type RiskLevel string
+
+const (
+	RiskNone   RiskLevel = "none"
+	RiskLow    RiskLevel = "low"
+	RiskMedium RiskLevel = "medium"
+	RiskHigh   RiskLevel = "high"
+
+	lowRiskThrottledRatio    = 0.01
+	mediumRiskThrottledRatio = 0.05
+)
+
+var (
+	ErrUnsupportedEdition    = errors.New("unsupported edition")
+	ErrInvalidMaxSlots       = errors.New("max slots must be at least the baseline")
+	ErrInvalidSlotsIncrement = fmt.Errorf("slots must be a multiple of %d", SlotsIncrement)
+	ErrStandardMaxSlots      = fmt.Errorf("the Standard edition supports up to %d slots", StandardMaxSlots)
+	ErrInvalidStep           = errors.New("step must be positive")
+)
+
+// Configuration is a candidate reservation. The reservation always has Baseline slots and the
+// autoscaler adds slots up to MaxSlots when the baseline and the idle slots are not enough.
+type Configuration struct {
+	Edition         pricebookDomain.Edition `json:"edition" validate:"required,oneof=standard enterprise enterprise_plus"`
+	Baseline        int64                   `json:"baseline" validate:"min=0"`
+	MaxSlots        int64                   `json:"maxSlots" validate:"required"`
+	IgnoreIdleSlots bool                    `json:"ignoreIdleSlots"`
+}
+
+// Sample is the average number of slots consumed during a step starting at Time
+type Sample struct {
+	Time  time.Time
+	Slots float64
+}
+
+// Commitment is a capacity commitment of the customer. A zero Start or End leaves the commitment
+// open on that side.
+type Commitment struct {
+	Edition   pricebookDomain.Edition
+	UsageType pricebookDomain.UsageType
+	Slots     int64
+	Start     time.Time
+	End       time.Time
+}
+
+// Prices holds the slot hour price of every usage type of an edition
+type Prices map[pricebookDomain.UsageType]float64
+
+// Result is a point of the cost and queueing-risk curve
+type Result struct {
+	Configuration Configuration `json:"configuration"`
+
+	Cost           float64 `json:"cost"`
+	DailyCost      float64 `json:"dailyCost"`
+	CommitmentCost float64 `json:"commitmentCost"`
+	BaselineCost   float64 `json:"baselineCost"`
+	AutoscaleCost  float64 `json:"autoscaleCost"`
+
+	AutoscaleSlotHours float64 `json:"autoscaleSlotHours"`
+	IdleSlotHours      float64 `json:"idleSlotHours"`
+	UnservedSlotHours  float64 `json:"unservedSlotHours"`
+	PeakSlots          float64 `json:"peakSlots"`
+
+	// ThrottledRatio is the share of the steps in which the slots consumed exceed the slots the
+	// configuration can provide, so queries queue or run slower
+	ThrottledRatio float64   `json:"throttledRatio"`
+	QueueingRisk   RiskLevel `json:"queueingRisk"`
+}
+
+// Validate checks the configuration can be created in BigQuery
+func (c Configuration) Validate() error {
+	switch c.Edition {
+	case pricebookDomain.Standard, pricebookDomain.Enterprise, pricebookDomain.EnterprisePlus:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedEdition, c.Edition)
+	}
+
+	if c.MaxSlots < c.Baseline {
+		return ErrInvalidMaxSlots
+	}
+
+	if c.Baseline%SlotsIncrement != 0 || c.MaxSlots%SlotsIncrement != 0 {
+		return ErrInvalidSlotsIncrement
+	}
+
+	if c.Edition == pricebookDomain.Standard && c.MaxSlots > StandardMaxSlots {
+		return ErrStandardMaxSlots
+	}
+
+	return nil
+}
+
+// Simulate replays the slots consumption of the samples, each lasting step, against every
+// configuration and returns the results in the order of the configurations.
+func Simulate(
+	samples []Sample,
+	step time.Duration,
+	commitments []Commitment,
+	pricesByEdition map[pricebookDomain.Edition]Prices,
+	configurations []Configuration,
+) ([]Result, error) {
+	if step <= 0 {
+		return nil, ErrInvalidStep
+	}
+
+	sorted := make([]Sample, len(samples))
+	copy(sorted, samples)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	results := make([]Result, 0, len(configurations))
+
+	for _, configuration := range configurations {
+		if err := configuration.Validate(); err != nil {
+			return nil, err
+		}
+
+		results = append(results, simulate(sorted, step, commitments, pricesByEdition[configuration.Edition], configuration))
+	}
+
+	return results, nil
+}
+
+func simulate(samples []Sample, step time.Duration, commitments []Commitment, prices Prices, configuration Configuration) Result {
+	result := Result{
+		Configuration: configuration,
+		QueueingRisk:  RiskNone,
+	}
+
+	if len(samples) == 0 {
+		return result
+	}
+
+	stepHours := step.Hours()
+	payAsYouGoPrice := prices[pricebookDomain.OnDemand]
+	autoscaleSlots := requiredAutoscaleSlots(samples, commitments, configuration)
+	allocatedSlots := holdMinimumDuration(samples, autoscaleSlots)
+
+	var throttledSteps int
+
+	for i, sample := range samples {
+		committedSlots, commitmentCost := activeCommitments(commitments, prices, configuration.Edition, sample.Time)
+		idleSlots := idleSlots(configuration, committedSlots)
+		baselineSlots := float64(configuration.Baseline)
+
+		// commitments pay for the baseline, only the slots above them are billed at the pay as you go price
+		result.CommitmentCost += commitmentCost * stepHours
+		result.BaselineCost += math.Max(baselineSlots-float64(committedSlots), 0) * payAsYouGoPrice * stepHours
+		result.AutoscaleCost += allocatedSlots[i] * payAsYouGoPrice * stepHours
+		result.AutoscaleSlotHours += allocatedSlots[i] * stepHours
+
+		capacity := baselineSlots + idleSlots + allocatedSlots[i]
+		if sample.Slots > capacity {
+			throttledSteps++
+			result.UnservedSlotHours += (sample.Slots - capacity) * stepHours
+		}
+
+		result.IdleSlotHours += math.Min(math.Max(sample.Slots-baselineSlots, 0), idleSlots) * stepHours
+		result.PeakSlots = math.Max(result.PeakSlots, sample.Slots)
+	}
+
+	result.Cost = result.CommitmentCost + result.BaselineCost + result.AutoscaleCost
+	result.ThrottledRatio = float64(throttledSteps) / float64(len(samples))
+	result.QueueingRisk = queueingRisk(result.ThrottledRatio)
+
+	days := samples[len(samples)-1].Time.Add(step).Sub(samples[0].Time).Hours() / 24
+	if days > 0 {
+		result.DailyCost = result.Cost / days
+	}
+
+	return result
+}
+
+// requiredAutoscaleSlots returns for every sample the slots the autoscaler adds on top of the
+// baseline and the idle slots, in increments and up to the max slots of the configuration
+func requiredAutoscaleSlots(samples []Sample, commitments []Commitment, configuration Configuration) []float64 {
+	autoscaleSlots := make([]float64, len(samples))
+	maxAutoscaleSlots := float64(configuration.MaxSlots - configuration.Baseline)
+
+	for i, sample := range samples {
+		committedSlots, _ := activeCommitments(commitments, nil, configuration.Edition, sample.Time)
+		missingSlots := sample.Slots - float64(configuration.Baseline) - idleSlots(configuration, committedSlots)
+
+		if missingSlots <= 0 {
+			continue
+		}
+
+		autoscaleSlots[i] = math.Min(math.Ceil(missingSlots/SlotsIncrement)*SlotsIncrement, maxAutoscaleSlots)
+	}
+
+	return autoscaleSlots
+}
+
+// holdMinimumDuration keeps the autoscaled slots allocated for the minimum autoscale duration, so a
+// sample is billed for the largest allocation of the samples that started within that duration
+func holdMinimumDuration(samples []Sample, autoscaleSlots []float64) []float64 {
+	allocatedSlots := make([]float64, len(autoscaleSlots))
+
+	for i := range autoscaleSlots {
+		for j := i; j >= 0 && samples[i].Time.Sub(samples[j].Time) < MinimumAutoscaleDuration; j-- {
+			allocatedSlots[i] = math.Max(allocatedSlots[i], autoscaleSlots[j])
+		}
+	}
+
+	return allocatedSlots
+}
+
+// activeCommitments returns the slots of the commitments of the edition active at t, and their
+// hourly cost when prices are given
+func activeCommitments(commitments []Commitment, prices Prices, edition pricebookDomain.Edition, t time.Time) (int64, float64) {
+	var (
+		slots int64
+		cost  float64
+	)
+
+	// the Standard edition does not support commitments
+	if edition == pricebookDomain.Standard {
+		return 0, 0
+	}
+
+	for _, commitment := range commitments {
+		if commitment.Edition != edition {
+			continue
+		}
+
+		if (!commitment.Start.IsZero() && t.Before(commitment.Start)) || (!commitment.End.IsZero() && !t.Before(commitment.End)) {
+			continue
+		}
+
+		slots += commitment.Slots
+		cost += float64(commitment.Slots) * prices[commitment.UsageType]
+	}
+
+	return slots, cost
+}
+
+// idleSlots returns the committed slots no baseline uses. The reservation borrows them before
+// autoscaling unless it ignores idle slots, which is always the case in the Standard edition.
+func idleSlots(configuration Configuration, committedSlots int64) float64 {
+	if configuration.IgnoreIdleSlots || configuration.Edition == pricebookDomain.Standard {
+		return 0
+	}
+
+	return math.Max(float64(committedSlots-configuration.Baseline), 0)
+}
+
+func queueingRisk(throttledRatio float64) RiskLevel {
+	switch {
+	case throttledRatio == 0:
+		return RiskNone
+	case throttledRatio < lowRiskThrottledRatio:
+		return RiskLow
+	case throttledRatio < mediumRiskThrottledRatio:
+		return RiskMedium
+	default:
+		return RiskHigh
+	}
+}
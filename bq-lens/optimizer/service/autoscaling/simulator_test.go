@@ -0,0 +1,302 @@
+package autoscaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pricebookDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/domain"
+)
+
+func TestSimulate(t *testing.T) {
+	var (
+		start   = time.Date(2024, 6, 17, 10, 0, 0, 0, time.UTC)
+		samples = []Sample{
+			{Time: start.Add(3 * time.Minute), Slots: 0},
+			{Time: start, Slots: 50},
+			{Time: start.Add(time.Minute), Slots: 250},
+			{Time: start.Add(2 * time.Minute), Slots: 120},
+		}
+		prices = map[pricebookDomain.Edition]Prices{
+			pricebookDomain.Standard: {
+				pricebookDomain.OnDemand: 0.04,
+			},
+			pricebookDomain.Enterprise: {
+				pricebookDomain.OnDemand:  0.06,
+				pricebookDomain.Commit1Yr: 0.048,
+			},
+		}
+		commitments = []Commitment{
+			{
+				Edition:   pricebookDomain.Enterprise,
+				UsageType: pricebookDomain.Commit1Yr,
+				Slots:     200,
+			},
+			{
+				Edition:   pricebookDomain.Enterprise,
+				UsageType: pricebookDomain.Commit1Yr,
+				Slots:     100,
+				End:       start,
+			},
+		}
+	)
+
+	tests := []struct {
+		name          string
+		samples       []Sample
+		step          time.Duration
+		commitments   []Commitment
+		configuration Configuration
+		want          Result
+		wantErr       error
+	}{
+		{
+			name:          "baseline and autoscaling cover the consumption",
+			samples:       samples,
+			step:          time.Minute,
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, Baseline: 100, MaxSlots: 300},
+			want: Result{
+				Configuration:      Configuration{Edition: pricebookDomain.Enterprise, Baseline: 100, MaxSlots: 300},
+				Cost:               0.6,
+				DailyCost:          216,
+				BaselineCost:       0.4,
+				AutoscaleCost:      0.2,
+				AutoscaleSlotHours: 200.0 / 60,
+				PeakSlots:          250,
+				QueueingRisk:       RiskNone,
+			},
+		},
+		{
+			name:          "max slots below the peak throttles the queries",
+			samples:       samples,
+			step:          time.Minute,
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, Baseline: 100, MaxSlots: 200},
+			want: Result{
+				Configuration:      Configuration{Edition: pricebookDomain.Enterprise, Baseline: 100, MaxSlots: 200},
+				Cost:               0.55,
+				DailyCost:          198,
+				BaselineCost:       0.4,
+				AutoscaleCost:      0.15,
+				AutoscaleSlotHours: 150.0 / 60,
+				UnservedSlotHours:  50.0 / 60,
+				PeakSlots:          250,
+				ThrottledRatio:     0.25,
+				QueueingRisk:       RiskHigh,
+			},
+		},
+		{
+			name:          "idle committed slots are used before autoscaling",
+			samples:       samples,
+			step:          time.Minute,
+			commitments:   commitments,
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, MaxSlots: 100},
+			want: Result{
+				Configuration:      Configuration{Edition: pricebookDomain.Enterprise, MaxSlots: 100},
+				Cost:               0.69,
+				DailyCost:          248.4,
+				CommitmentCost:     0.64,
+				AutoscaleCost:      0.05,
+				AutoscaleSlotHours: 50.0 / 60,
+				IdleSlotHours:      370.0 / 60,
+				PeakSlots:          250,
+				QueueingRisk:       RiskNone,
+			},
+		},
+		{
+			name:          "ignoring idle slots",
+			samples:       samples,
+			step:          time.Minute,
+			commitments:   commitments,
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, MaxSlots: 100, IgnoreIdleSlots: true},
+			want: Result{
+				Configuration:      Configuration{Edition: pricebookDomain.Enterprise, MaxSlots: 100, IgnoreIdleSlots: true},
+				Cost:               0.89,
+				DailyCost:          320.4,
+				CommitmentCost:     0.64,
+				AutoscaleCost:      0.25,
+				AutoscaleSlotHours: 250.0 / 60,
+				UnservedSlotHours:  170.0 / 60,
+				PeakSlots:          250,
+				ThrottledRatio:     0.5,
+				QueueingRisk:       RiskHigh,
+			},
+		},
+		{
+			name: "autoscaled slots are billed for at least 60 seconds",
+			samples: []Sample{
+				{Time: start, Slots: 80},
+				{Time: start.Add(10 * time.Second)},
+				{Time: start.Add(20 * time.Second)},
+				{Time: start.Add(30 * time.Second)},
+				{Time: start.Add(40 * time.Second)},
+				{Time: start.Add(50 * time.Second)},
+				{Time: start.Add(60 * time.Second)},
+				{Time: start.Add(70 * time.Second)},
+			},
+			step:          10 * time.Second,
+			commitments:   commitments,
+			configuration: Configuration{Edition: pricebookDomain.Standard, MaxSlots: 100},
+			want: Result{
+				Configuration:      Configuration{Edition: pricebookDomain.Standard, MaxSlots: 100},
+				Cost:               100 * 60 * 0.04 / 3600,
+				DailyCost:          100 * 60 * 0.04 / 3600 * 1080,
+				AutoscaleCost:      100 * 60 * 0.04 / 3600,
+				AutoscaleSlotHours: 100.0 * 60 / 3600,
+				PeakSlots:          80,
+				QueueingRisk:       RiskNone,
+			},
+		},
+		{
+			name: "autoscaled slots are not held over a gap between the samples",
+			samples: []Sample{
+				{Time: start, Slots: 80},
+				{Time: start.Add(5 * time.Minute)},
+			},
+			step:          10 * time.Second,
+			configuration: Configuration{Edition: pricebookDomain.Standard, MaxSlots: 100},
+			want: Result{
+				Configuration:      Configuration{Edition: pricebookDomain.Standard, MaxSlots: 100},
+				Cost:               100 * 10 * 0.04 / 3600,
+				DailyCost:          100 * 10 * 0.04 / 3600 * 86400 / 310,
+				AutoscaleCost:      100 * 10 * 0.04 / 3600,
+				AutoscaleSlotHours: 100.0 * 10 / 3600,
+				PeakSlots:          80,
+				QueueingRisk:       RiskNone,
+			},
+		},
+		{
+			name:          "no samples",
+			step:          time.Minute,
+			configuration: Configuration{Edition: pricebookDomain.EnterprisePlus, MaxSlots: 100},
+			want: Result{
+				Configuration: Configuration{Edition: pricebookDomain.EnterprisePlus, MaxSlots: 100},
+				QueueingRisk:  RiskNone,
+			},
+		},
+		{
+			name:          "invalid configuration",
+			samples:       samples,
+			step:          time.Minute,
+			configuration: Configuration{Edition: pricebookDomain.Standard, MaxSlots: 2000},
+			wantErr:       ErrStandardMaxSlots,
+		},
+		{
+			name:          "invalid step",
+			samples:       samples,
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, MaxSlots: 100},
+			wantErr:       ErrInvalidStep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Simulate(tt.samples, tt.step, tt.commitments, prices, []Configuration{tt.configuration})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want.Configuration, got[0].Configuration)
+			assert.InDelta(t, tt.want.Cost, got[0].Cost, 1e-9)
+			assert.InDelta(t, tt.want.DailyCost, got[0].DailyCost, 1e-6)
+			assert.InDelta(t, tt.want.CommitmentCost, got[0].CommitmentCost, 1e-9)
+			assert.InDelta(t, tt.want.BaselineCost, got[0].BaselineCost, 1e-9)
+			assert.InDelta(t, tt.want.AutoscaleCost, got[0].AutoscaleCost, 1e-9)
+			assert.InDelta(t, tt.want.AutoscaleSlotHours, got[0].AutoscaleSlotHours, 1e-9)
+			assert.InDelta(t, tt.want.IdleSlotHours, got[0].IdleSlotHours, 1e-9)
+			assert.InDelta(t, tt.want.UnservedSlotHours, got[0].UnservedSlotHours, 1e-9)
+			assert.Equal(t, tt.want.PeakSlots, got[0].PeakSlots)
+			assert.Equal(t, tt.want.ThrottledRatio, got[0].ThrottledRatio)
+			assert.Equal(t, tt.want.QueueingRisk, got[0].QueueingRisk)
+		})
+	}
+}
+
+func TestConfiguration_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration Configuration
+		wantErr       error
+	}{
+		{
+			name:          "valid",
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, Baseline: 100, MaxSlots: 400},
+		},
+		{
+			name:          "unsupported edition",
+			configuration: Configuration{Edition: pricebookDomain.LegacyFlatRate, MaxSlots: 100},
+			wantErr:       ErrUnsupportedEdition,
+		},
+		{
+			name:          "max slots below the baseline",
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, Baseline: 200, MaxSlots: 100},
+			wantErr:       ErrInvalidMaxSlots,
+		},
+		{
+			name:          "slots not in increments",
+			configuration: Configuration{Edition: pricebookDomain.Enterprise, Baseline: 10, MaxSlots: 100},
+			wantErr:       ErrInvalidSlotsIncrement,
+		},
+		{
+			name:          "standard edition above its max slots",
+			configuration: Configuration{Edition: pricebookDomain.Standard, MaxSlots: 1650},
+			wantErr:       ErrStandardMaxSlots,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.configuration.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	start := time.Date(2024, 6, 17, 10, 0, 0, 0, time.UTC)
+
+	samples := make([]Sample, 100)
+	for i := range samples {
+		samples[i] = Sample{Time: start.Add(time.Duration(i) * time.Minute), Slots: float64(100 - i)}
+	}
+
+	var want []Configuration
+
+	for _, edition := range []pricebookDomain.Edition{pricebookDomain.Standard, pricebookDomain.Enterprise, pricebookDomain.EnterprisePlus} {
+		want = append(want,
+			Configuration{Edition: edition, Baseline: 0, MaxSlots: 100},
+			Configuration{Edition: edition, Baseline: 50, MaxSlots: 100},
+			Configuration{Edition: edition, Baseline: 100, MaxSlots: 100},
+		)
+	}
+
+	assert.Equal(t, want, Candidates(samples))
+	assert.Nil(t, Candidates(nil))
+}
+
+func TestPricesForRegion(t *testing.T) {
+	pricebooks := pricebookDomain.PriceBooksByEdition{
+		pricebookDomain.Enterprise: &pricebookDomain.PricebookDocument{
+			string(pricebookDomain.OnDemand):  {"us": 0.06, "europe-west1": 0.066},
+			string(pricebookDomain.Commit1Yr): {"us": 0.048},
+		},
+		pricebookDomain.LegacyFlatRate: &pricebookDomain.PricebookDocument{
+			string(pricebookDomain.Commit1Mo): {"us": 2000},
+		},
+	}
+
+	assert.Equal(t, map[pricebookDomain.Edition]Prices{
+		pricebookDomain.Enterprise: {
+			pricebookDomain.OnDemand:  0.06,
+			pricebookDomain.Commit1Yr: 0.048,
+		},
+	}, PricesForRegion(pricebooks, "us"))
+}
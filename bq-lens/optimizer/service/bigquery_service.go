@@ -60,6 +60,10 @@ func (s *BigQueryService) GetAggregatedJobStatistics(ctx context.Context, bq *bi
 	return s.bqDAL.RunAggregatedJobStatisticsQuery(ctx, bq, projectID, location)
 }
 
+func (s *BigQueryService) GetSlotsConsumption(ctx context.Context, bq *bigquery.Client, projectID, location string, lookbackDays int) ([]bqmodels.SlotsConsumption, error) {
+	return s.bqDAL.RunSlotsConsumptionQuery(ctx, bq, projectID, location, lookbackDays)
+}
+
 func (s *BigQueryService) GetTableDiscoveryMetadata(ctx context.Context, bq *bigquery.Client) (*bigquery.TableMetadata, error) {
 	return s.bqDAL.GetTableDiscoveryMetadata(ctx, bq)
 }
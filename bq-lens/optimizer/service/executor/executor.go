@@ -160,6 +160,10 @@ func (s *Executor) getExecutors(queriesPerMode map[bqmodels.Mode]map[bqmodels.Qu
 				executors[queryValue] = wrapQueryExecutorFunc(s.dal.RunFlatRateSlotsExplorerQuery)
 			case bqmodels.SlotsExplorerOnDemand:
 				executors[queryValue] = wrapQueryExecutorFunc(s.dal.RunOnDemandSlotsExplorerQuery)
+			case bqmodels.QueryAntiPatterns:
+				executors[queryValue] = wrapQueryExecutorFunc(s.dal.RunQueryAntiPatternsQuery)
+			case bqmodels.RepeatedQueries:
+				executors[queryValue] = wrapQueryExecutorFunc(s.dal.RunRepeatedQueriesQuery)
 			case bqmodels.StandardScheduledQueriesMovement:
 				executors[queryValue] = wrapQueryExecutorFunc(s.dal.RunStandardScheduledQueriesMovementQuery)
 			case bqmodels.StandardSlotsExplorer:
@@ -219,6 +219,11 @@ func TestExecutor_Execute(t *testing.T) {
 		bqmodels.OnDemand: bqmodels.OnDemandQueries,
 	}
 
+	// the transformers of the query anti-patterns and the repeated queries have their own tests, an empty
+	// result makes a document without rows
+	emptyQueryAntiPatterns := TransformQueryAntiPatterns(bqmodels.TimeRangeDay, 0, nil, nil, mockTime)[bqmodels.QueryAntiPatterns][bqmodels.TimeRangeDay]
+	emptyRepeatedQueries := TransformRepeatedQueries(bqmodels.TimeRangeDay, 0, nil, nil, mockTime)[bqmodels.RepeatedQueries][bqmodels.TimeRangeDay]
+
 	singleQuery := map[bqmodels.Mode]map[bqmodels.QueryName]string{
 		bqmodels.Hybrid: {bqmodels.CostFromTableTypes: bqmodels.QueriesPerMode[bqmodels.Hybrid][bqmodels.CostFromTableTypes]},
 	}
@@ -778,6 +783,23 @@ func TestExecutor_Execute(t *testing.T) {
 					mock.AnythingOfType("bqmodels.TimeRange"),
 				).Return(nil, nil)
 
+				f.dal.On(
+					"RunQueryAntiPatternsQuery",
+					ctx,
+					mock.AnythingOfType("string"),
+					replacements,
+					mockCustomerBQ,
+					mock.AnythingOfType("bqmodels.TimeRange"),
+				).Return([]bqmodels.QueryAntiPatternsResult{}, nil)
+
+				f.dal.On(
+					"RunRepeatedQueriesQuery",
+					ctx,
+					mock.AnythingOfType("string"),
+					replacements,
+					mockCustomerBQ,
+					mock.AnythingOfType("bqmodels.TimeRange"),
+				).Return([]bqmodels.RepeatedQueriesResult{}, nil)
 			},
 			want: dal.RecommendationSummary{
 				bqmodels.SlotsExplorerOnDemand: {
@@ -805,6 +827,16 @@ func TestExecutor_Execute(t *testing.T) {
 					bqmodels.TimeRangeWeek:  fsModels.UserScanTBDocument{},
 					bqmodels.TimeRangeMonth: fsModels.UserScanTBDocument{},
 				},
+				bqmodels.QueryAntiPatterns: {
+					bqmodels.TimeRangeDay:   emptyQueryAntiPatterns,
+					bqmodels.TimeRangeWeek:  emptyQueryAntiPatterns,
+					bqmodels.TimeRangeMonth: emptyQueryAntiPatterns,
+				},
+				bqmodels.RepeatedQueries: {
+					bqmodels.TimeRangeDay:   emptyRepeatedQueries,
+					bqmodels.TimeRangeWeek:  emptyRepeatedQueries,
+					bqmodels.TimeRangeMonth: emptyRepeatedQueries,
+				},
 			},
 		},
 		{
@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"time"
+
+	dal "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/dal/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	bqmodels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/bigquery"
+	fsModels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/linter"
+)
+
+const queryAntiPatternsRecommendation = "Rewrite the most expensive queries to avoid SQL anti-patterns"
+
+var antiPatternTitles = map[linter.Rule]string{
+	linter.RuleSelectStar:             "SELECT * on a wide table",
+	linter.RuleMissingPartitionFilter: "Missing partition filter",
+	linter.RuleOrderByWithoutLimit:    "ORDER BY without LIMIT",
+	linter.RuleCrossJoin:              "Cross join",
+	linter.RuleRepeatedSubquery:       "Repeated subquery",
+}
+
+// TransformQueryAntiPatterns lints the SQL of the top queries and estimates the savings of fixing
+// every anti-pattern from the bytes the query scanned in the time range
+func TransformQueryAntiPatterns(
+	timeRange bqmodels.TimeRange,
+	customerDiscount float64,
+	totalScanPricePerPeriod domain.PeriodTotalPrice,
+	data []bqmodels.QueryAntiPatternsResult,
+	now time.Time,
+) dal.RecommendationSummary {
+	recommendationItem := fsModels.QueryAntiPatterns{
+		DetailedTable: []fsModels.QueryAntiPatternDetailTable{},
+		CommonRecommendation: fsModels.CommonRecommendation{
+			Recommendation: queryAntiPatternsRecommendation,
+		},
+	}
+
+	for _, row := range data {
+		findings := linter.Lint(row.Query, toLinterTables(row.ReferencedTables))
+		if len(findings) == 0 {
+			continue
+		}
+
+		scanPrice := getScanPrice(customerDiscount, row.TotalScanTB)
+
+		for _, finding := range findings {
+			recommendationItem.DetailedTable = append(recommendationItem.DetailedTable, fsModels.QueryAntiPatternDetailTable{
+				JobID:            row.JobID,
+				Location:         row.Location,
+				BillingProjectID: row.BillingProjectID,
+				UserID:           row.UserID,
+				AntiPattern:      antiPatternTitles[finding.Rule],
+				Details:          finding.Details,
+				TableID:          finding.TableID,
+				ExecutedQueries:  row.ExecutedQueries,
+				ScanTB:           row.TotalScanTB,
+				ScanPrice:        scanPrice,
+				PotentialSavings: scanPrice * finding.SavingsRatio,
+			})
+		}
+
+		// findings of the same query save from the same scanned bytes, so they are not summed up
+		recommendationItem.SavingsPrice += scanPrice * linter.CombinedSavingsRatio(findings)
+	}
+
+	if totalScanPricePerPeriod[timeRange].TotalScanPrice > 0 {
+		recommendationItem.SavingsPercentage = (recommendationItem.SavingsPrice * 100) / totalScanPricePerPeriod[timeRange].TotalScanPrice
+	}
+
+	columns := []string{
+		"jobId",
+		"location",
+		"billingProjectId",
+		"userId",
+		"antiPattern",
+		"details",
+		"tableId",
+		"executedQueries",
+		"scanTB",
+		"scanPrice",
+		"potentialSavings",
+	}
+
+	recommendationItem.DetailedTableFieldsMapping = getDetailedTableFieldsMapping(columns)
+
+	document := fsModels.QueryAntiPatternsDocument{Data: recommendationItem, LastUpdate: now}
+
+	return dal.RecommendationSummary{bqmodels.QueryAntiPatterns: {timeRange: document}}
+}
+
+func toLinterTables(referencedTables []bqmodels.ReferencedTableResult) []linter.Table {
+	tables := make([]linter.Table, len(referencedTables))
+
+	for i, table := range referencedTables {
+		tables[i] = linter.Table{
+			ID:             table.TableID,
+			PartitionField: table.PartitionField.StringVal,
+			Columns:        linter.ColumnsFromDDL(table.DDL.StringVal),
+		}
+	}
+
+	return tables
+}
+
+// getDetailedTableFieldsMapping assigns to each column of a detailed table its order, sign, title and visibility
+func getDetailedTableFieldsMapping(columns []string) map[string]fsModels.FieldDetail {
+	fieldsMapping := make(map[string]fsModels.FieldDetail, len(columns))
+
+	for i, column := range columns {
+		title := column
+		if t, ok := domain.ColumnTitles[column]; ok {
+			title = t
+		}
+
+		sign := ""
+		if s, ok := domain.ColumnsSigns[column]; ok {
+			sign = s
+		}
+
+		fieldsMapping[column] = fsModels.FieldDetail{
+			Order:   i,
+			Title:   title,
+			Sign:    sign,
+			Visible: !domain.ColumnVisibility[column],
+		}
+	}
+
+	return fieldsMapping
+}
@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	bqmodels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/bigquery"
+	fsModels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/firestore"
+)
+
+func TestTransformQueryAntiPatterns(t *testing.T) {
+	columns := make([]string, 40)
+	for i := range columns {
+		columns[i] = fmt.Sprintf("column%d INT64", i)
+	}
+
+	var (
+		now       = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		wideTable = bqmodels.ReferencedTableResult{
+			TableID:        "project.dataset.events",
+			PartitionField: bigquery.NullString{StringVal: "event_date", Valid: true},
+			DDL:            bigquery.NullString{StringVal: "CREATE TABLE `project.dataset.events`\n(" + strings.Join(columns, ",\n") + "\n);", Valid: true},
+		}
+		data = []bqmodels.QueryAntiPatternsResult{
+			{
+				JobID:            "job1",
+				Location:         "US",
+				BillingProjectID: "billing-project",
+				UserID:           "user@doit.com",
+				Query:            "SELECT * FROM `project.dataset.events` WHERE column1 > 1",
+				ExecutedQueries:  3,
+				TotalScanTB:      2,
+				ReferencedTables: []bqmodels.ReferencedTableResult{wideTable},
+			},
+			{
+				JobID:            "job2",
+				Location:         "US",
+				BillingProjectID: "billing-project",
+				UserID:           "user@doit.com",
+				Query:            "SELECT column1 FROM `project.dataset.events` WHERE event_date = CURRENT_DATE()",
+				ExecutedQueries:  1,
+				TotalScanTB:      1,
+				ReferencedTables: []bqmodels.ReferencedTableResult{wideTable},
+			},
+		}
+		periodTotalPriceMapping = domain.PeriodTotalPrice{
+			bqmodels.TimeRangeMonth: {TotalScanPrice: 10},
+		}
+	)
+
+	got := TransformQueryAntiPatterns(bqmodels.TimeRangeMonth, 0.5, periodTotalPriceMapping, data, now)
+
+	document, ok := got[bqmodels.QueryAntiPatterns][bqmodels.TimeRangeMonth].(fsModels.QueryAntiPatternsDocument)
+	assert.True(t, ok)
+	assert.Equal(t, now, document.LastUpdate)
+	assert.Equal(t, []fsModels.QueryAntiPatternDetailTable{
+		{
+			JobID:            "job1",
+			Location:         "US",
+			BillingProjectID: "billing-project",
+			UserID:           "user@doit.com",
+			AntiPattern:      "SELECT * on a wide table",
+			Details:          "SELECT * reads all 40 columns of the table",
+			TableID:          "project.dataset.events",
+			ExecutedQueries:  3,
+			ScanTB:           2,
+			ScanPrice:        6.25,
+			PotentialSavings: 4.6875,
+		},
+		{
+			JobID:            "job1",
+			Location:         "US",
+			BillingProjectID: "billing-project",
+			UserID:           "user@doit.com",
+			AntiPattern:      "Missing partition filter",
+			Details:          "no filter on the partition field event_date, all the partitions of the table are scanned",
+			TableID:          "project.dataset.events",
+			ExecutedQueries:  3,
+			ScanTB:           2,
+			ScanPrice:        6.25,
+			PotentialSavings: 3.125,
+		},
+	}, document.Data.DetailedTable)
+	assert.Equal(t, queryAntiPatternsRecommendation, document.Data.Recommendation)
+	assert.Equal(t, 5.46875, document.Data.SavingsPrice)
+	assert.Equal(t, 54.6875, document.Data.SavingsPercentage)
+	assert.Equal(t, fsModels.FieldDetail{Order: 4, Title: "Anti-Pattern", Visible: true}, document.Data.DetailedTableFieldsMapping["antiPattern"])
+	assert.Equal(t, fsModels.FieldDetail{Order: 10, Title: "Savings Potential", Sign: "$", Visible: true}, document.Data.DetailedTableFieldsMapping["potentialSavings"])
+}
+
+func TestTransformQueryAntiPatterns_noFindings(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	got := TransformQueryAntiPatterns(bqmodels.TimeRangeDay, 1, domain.PeriodTotalPrice{}, nil, now)
+
+	assert.Len(t, got, 1)
+
+	document := got[bqmodels.QueryAntiPatterns][bqmodels.TimeRangeDay].(fsModels.QueryAntiPatternsDocument)
+	assert.Empty(t, document.Data.DetailedTable)
+	assert.Zero(t, document.Data.SavingsPrice)
+	assert.Zero(t, document.Data.SavingsPercentage)
+	assert.Len(t, document.Data.DetailedTableFieldsMapping, 11)
+}
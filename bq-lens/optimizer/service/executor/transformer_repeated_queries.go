@@ -0,0 +1,175 @@
+package executor
+
+import (
+	"sort"
+	"time"
+
+	dal "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/dal/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	bqmodels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/bigquery"
+	fsModels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/linter"
+)
+
+const (
+	repeatedQueriesRecommendation = "Avoid recomputing queries that run repeatedly"
+
+	useCachedResultsRecommendation        = "Use cached results, the query runs with the same text on tables that did not change"
+	materializedViewRecommendation        = "Create a materialized view, the query runs with different literals on tables that did not change"
+	reduceScheduleFrequencyRecommendation = "Reduce the schedule frequency to at most once an hour"
+
+	// frequentRunsPerDay is the number of runs per day from which a query runs very often
+	frequentRunsPerDay = 24
+	// materializedViewSavingsRatio is a conservative estimate of the share of the scanned bytes a materialized view saves
+	materializedViewSavingsRatio = 0.5
+
+	maxRepeatedQueriesRows   = 100
+	maxNormalizedQueryLength = 1000
+)
+
+type fingerprintGroup struct {
+	row             fsModels.RepeatedQueryDetailTable
+	sampleScanTB    float64
+	tablesUnchanged bool
+}
+
+// TransformRepeatedQueries groups the queries by fingerprint, so the runs of the same query with
+// different literals add up, and recommends how to avoid recomputing the queries that run very often
+func TransformRepeatedQueries(
+	timeRange bqmodels.TimeRange,
+	customerDiscount float64,
+	totalScanPricePerPeriod domain.PeriodTotalPrice,
+	data []bqmodels.RepeatedQueriesResult,
+	now time.Time,
+) dal.RecommendationSummary {
+	recommendationItem := fsModels.RepeatedQueries{
+		DetailedTable: []fsModels.RepeatedQueryDetailTable{},
+		CommonRecommendation: fsModels.CommonRecommendation{
+			Recommendation: repeatedQueriesRecommendation,
+		},
+	}
+
+	days, _ := domain.GetDayBasedOnTimeRange(timeRange)
+
+	for _, group := range groupByFingerprint(data) {
+		if group.row.ExecutedQueries < 2 {
+			continue
+		}
+
+		row := group.row
+		row.ScanPrice = getScanPrice(customerDiscount, row.ScanTB)
+
+		if days > 0 {
+			row.RunsPerDay = float64(row.ExecutedQueries) / float64(days)
+		}
+
+		var savingsRatio float64
+
+		row.Recommendation, savingsRatio = recommendRepeatedQuery(row, group.tablesUnchanged, days)
+		row.PotentialSavings = row.ScanPrice * savingsRatio
+
+		recommendationItem.SavingsPrice += row.PotentialSavings
+		recommendationItem.DetailedTable = append(recommendationItem.DetailedTable, row)
+	}
+
+	sort.SliceStable(recommendationItem.DetailedTable, func(i, j int) bool {
+		return recommendationItem.DetailedTable[i].ScanPrice > recommendationItem.DetailedTable[j].ScanPrice
+	})
+
+	if len(recommendationItem.DetailedTable) > maxRepeatedQueriesRows {
+		recommendationItem.DetailedTable = recommendationItem.DetailedTable[:maxRepeatedQueriesRows]
+	}
+
+	if totalScanPricePerPeriod[timeRange].TotalScanPrice > 0 {
+		recommendationItem.SavingsPercentage = (recommendationItem.SavingsPrice * 100) / totalScanPricePerPeriod[timeRange].TotalScanPrice
+	}
+
+	columns := []string{
+		"fingerprint",
+		"normalizedQuery",
+		"jobId",
+		"location",
+		"billingProjectId",
+		"userId",
+		"variants",
+		"executedQueries",
+		"runsPerDay",
+		"totalSlotMs",
+		"scanTB",
+		"scanPrice",
+		"recommendation",
+		"potentialSavings",
+	}
+
+	recommendationItem.DetailedTableFieldsMapping = getDetailedTableFieldsMapping(columns)
+
+	document := fsModels.RepeatedQueriesDocument{Data: recommendationItem, LastUpdate: now}
+
+	return dal.RecommendationSummary{bqmodels.RepeatedQueries: {timeRange: document}}
+}
+
+// groupByFingerprint adds up the cost, slots and runs of the queries with the same fingerprint. The
+// job of the query that scanned the most represents the group.
+func groupByFingerprint(data []bqmodels.RepeatedQueriesResult) []*fingerprintGroup {
+	var groups []*fingerprintGroup
+
+	byFingerprint := make(map[string]*fingerprintGroup)
+
+	for _, result := range data {
+		fingerprint := linter.Fingerprint(result.Query)
+
+		group, ok := byFingerprint[fingerprint]
+		if !ok {
+			normalizedQuery := []rune(linter.Normalize(result.Query))
+			if len(normalizedQuery) > maxNormalizedQueryLength {
+				normalizedQuery = normalizedQuery[:maxNormalizedQueryLength]
+			}
+
+			group = &fingerprintGroup{
+				row: fsModels.RepeatedQueryDetailTable{
+					Fingerprint:     fingerprint,
+					NormalizedQuery: string(normalizedQuery),
+				},
+				sampleScanTB:    -1,
+				tablesUnchanged: true,
+			}
+
+			byFingerprint[fingerprint] = group
+			groups = append(groups, group)
+		}
+
+		group.row.Variants++
+		group.row.ExecutedQueries += result.ExecutedQueries
+		group.row.TotalSlotMs += result.TotalSlotMs
+		group.row.ScanTB += result.TotalScanTB
+		group.tablesUnchanged = group.tablesUnchanged && result.TablesUnchanged
+
+		if result.TotalScanTB > group.sampleScanTB {
+			group.sampleScanTB = result.TotalScanTB
+			group.row.JobID = result.JobID
+			group.row.Location = result.Location
+			group.row.BillingProjectID = result.BillingProjectID
+			group.row.UserID = result.UserID
+		}
+	}
+
+	return groups
+}
+
+// recommendRepeatedQuery returns the recommendation for a query that runs very often, and the share
+// of its scanned bytes the recommendation saves
+func recommendRepeatedQuery(row fsModels.RepeatedQueryDetailTable, tablesUnchanged bool, days int) (string, float64) {
+	if row.RunsPerDay < frequentRunsPerDay {
+		return "", 0
+	}
+
+	switch {
+	case tablesUnchanged && row.Variants == 1:
+		// all the runs but the first could read the cached results
+		return useCachedResultsRecommendation, float64(row.ExecutedQueries-1) / float64(row.ExecutedQueries)
+	case tablesUnchanged:
+		return materializedViewRecommendation, materializedViewSavingsRatio
+	default:
+		return reduceScheduleFrequencyRecommendation, 1 - float64(frequentRunsPerDay*days)/float64(row.ExecutedQueries)
+	}
+}
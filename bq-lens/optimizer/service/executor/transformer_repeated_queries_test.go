@@ -0,0 +1,150 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	bqmodels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/bigquery"
+	fsModels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/linter"
+)
+
+func TestTransformRepeatedQueries(t *testing.T) {
+	var (
+		now            = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		dashboardQuery = "SELECT SUM(cost) FROM `project.dataset.costs` WHERE day = '2024-04-01'"
+		cachedQuery    = "SELECT COUNT(*) FROM `project.dataset.users`"
+		scheduledQuery = "INSERT INTO dataset.daily SELECT * FROM dataset.events WHERE ts > @since"
+		data           = []bqmodels.RepeatedQueriesResult{
+			{
+				JobID:           "job1",
+				UserID:          "user1@doit.com",
+				Query:           dashboardQuery,
+				ExecutedQueries: 20,
+				TotalScanTB:     1,
+				TotalSlotMs:     100,
+				TablesUnchanged: true,
+			},
+			{
+				JobID:           "job2",
+				UserID:          "user2@doit.com",
+				Query:           "select sum(cost)\nfrom `project.dataset.costs`\nwhere day = '2024-04-02'",
+				ExecutedQueries: 10,
+				TotalScanTB:     3,
+				TotalSlotMs:     200,
+				TablesUnchanged: true,
+			},
+			{
+				JobID:           "job3",
+				Query:           cachedQuery,
+				ExecutedQueries: 40,
+				TotalScanTB:     0.8,
+				TotalSlotMs:     300,
+				TablesUnchanged: true,
+			},
+			{
+				JobID:           "job4",
+				Query:           scheduledQuery,
+				ExecutedQueries: 96,
+				TotalScanTB:     0.4,
+				TotalSlotMs:     400,
+			},
+			{
+				JobID:           "job5",
+				Query:           "SELECT 1 FROM dataset.rare",
+				ExecutedQueries: 5,
+				TotalScanTB:     10,
+				TablesUnchanged: true,
+			},
+			{
+				JobID:           "job6",
+				Query:           "SELECT 2 FROM dataset.once",
+				ExecutedQueries: 1,
+				TotalScanTB:     20,
+			},
+		}
+		periodTotalPriceMapping = domain.PeriodTotalPrice{
+			bqmodels.TimeRangeDay: {TotalScanPrice: 100},
+		}
+	)
+
+	got := TransformRepeatedQueries(bqmodels.TimeRangeDay, 0, periodTotalPriceMapping, data, now)
+
+	document, ok := got[bqmodels.RepeatedQueries][bqmodels.TimeRangeDay].(fsModels.RepeatedQueriesDocument)
+	assert.True(t, ok)
+	assert.Equal(t, now, document.LastUpdate)
+	assert.Equal(t, []fsModels.RepeatedQueryDetailTable{
+		{
+			Fingerprint:      linter.Fingerprint("SELECT 1 FROM dataset.rare"),
+			NormalizedQuery:  "select ? from dataset.rare",
+			JobID:            "job5",
+			Variants:         1,
+			ExecutedQueries:  5,
+			RunsPerDay:       5,
+			ScanTB:           10,
+			ScanPrice:        62.5,
+			PotentialSavings: 0,
+		},
+		{
+			Fingerprint:      linter.Fingerprint(dashboardQuery),
+			NormalizedQuery:  "select sum ( cost ) from project.dataset.costs where day = ?",
+			JobID:            "job2",
+			UserID:           "user2@doit.com",
+			Variants:         2,
+			ExecutedQueries:  30,
+			RunsPerDay:       30,
+			TotalSlotMs:      300,
+			ScanTB:           4,
+			ScanPrice:        25,
+			Recommendation:   materializedViewRecommendation,
+			PotentialSavings: 12.5,
+		},
+		{
+			Fingerprint:      linter.Fingerprint(cachedQuery),
+			NormalizedQuery:  "select count ( * ) from project.dataset.users",
+			JobID:            "job3",
+			Variants:         1,
+			ExecutedQueries:  40,
+			RunsPerDay:       40,
+			TotalSlotMs:      300,
+			ScanTB:           0.8,
+			ScanPrice:        5,
+			Recommendation:   useCachedResultsRecommendation,
+			PotentialSavings: 4.875,
+		},
+		{
+			Fingerprint:      linter.Fingerprint(scheduledQuery),
+			NormalizedQuery:  "insert into dataset.daily select * from dataset.events where ts > @ since",
+			JobID:            "job4",
+			Variants:         1,
+			ExecutedQueries:  96,
+			RunsPerDay:       96,
+			TotalSlotMs:      400,
+			ScanTB:           0.4,
+			ScanPrice:        2.5,
+			Recommendation:   reduceScheduleFrequencyRecommendation,
+			PotentialSavings: 1.875,
+		},
+	}, document.Data.DetailedTable)
+	assert.Equal(t, repeatedQueriesRecommendation, document.Data.Recommendation)
+	assert.Equal(t, 19.25, document.Data.SavingsPrice)
+	assert.Equal(t, 19.25, document.Data.SavingsPercentage)
+	assert.Len(t, document.Data.DetailedTableFieldsMapping, 14)
+}
+
+func TestGroupByFingerprint_truncatesNormalizedQueryByRunes(t *testing.T) {
+	query := "SELECT 'ü' AS `" + strings.Repeat("ü", 2*maxNormalizedQueryLength) + "` FROM dataset.t"
+
+	groups := groupByFingerprint([]bqmodels.RepeatedQueriesResult{{Query: query, ExecutedQueries: 2}})
+
+	if assert.Len(t, groups, 1) {
+		normalizedQuery := groups[0].row.NormalizedQuery
+		assert.True(t, utf8.ValidString(normalizedQuery))
+		assert.Equal(t, maxNormalizedQueryLength, utf8.RuneCountInString(normalizedQuery))
+	}
+}
@@ -54,6 +54,10 @@ func (s *Executor) assignTransformer(timeRange bqmodels.TimeRange, queryResult i
 		return TransformFlatRateSlotsExplorer(timeRange, res, now)
 	case []bqmodels.OnDemandSlotsExplorerResult:
 		return TransformOnDemandSlotsExplorer(timeRange, res, now)
+	case []bqmodels.QueryAntiPatternsResult:
+		return TransformQueryAntiPatterns(timeRange, tctx.Discount, tctx.TotalScanPricePerPeriod, res, now), nil
+	case []bqmodels.RepeatedQueriesResult:
+		return TransformRepeatedQueries(timeRange, tctx.Discount, tctx.TotalScanPricePerPeriod, res, now), nil
 	case []bqmodels.StandardSlotsExplorerResult:
 		return TransformStandardSlotsExplorer(timeRange, res, now)
 	case []bqmodels.EnterpriseSlotsExplorerResult:
@@ -17,6 +17,7 @@ type Bigquery interface {
 	GetDatasetLocationAndProjectID(ctx context.Context, bq *bigquery.Client, datasetID string) (string, string, error)
 	GetTableDiscoveryMetadata(ctx context.Context, bq *bigquery.Client) (*bigquery.TableMetadata, error)
 	GetAggregatedJobStatistics(ctx context.Context, bq *bigquery.Client, projectID, location string) ([]bqmodels.AggregatedJobStatistic, error)
+	GetSlotsConsumption(ctx context.Context, bq *bigquery.Client, projectID, location string, lookbackDays int) ([]bqmodels.SlotsConsumption, error)
 	GetMinAndMaxDates(ctx context.Context, bq *bigquery.Client, projectID string, location string) (*bqmodels.CheckCompleteDaysResult, error)
 	GenerateStorageRecommendation(ctx context.Context, customerID string, bq *bigquery.Client, discount float64, replacements domain.Replacements, now time.Time, hasTableDiscovery bool) (domain.PeriodTotalPrice, dal.RecommendationSummary, error)
 	GetBillingProjectsWithEditions(ctx context.Context, bq *bigquery.Client) (map[string][]domain.BillingProjectWithReservation, error)
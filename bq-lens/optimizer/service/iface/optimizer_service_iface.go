@@ -5,9 +5,16 @@ import (
 	"context"
 
 	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/autoscaling"
 )
 
 type OptimizerService interface {
 	SingleCustomerOptimizer(ctx context.Context, customerID string, payload domain.Payload) error
 	Schedule(ctx context.Context) (taskErrors []error, _ error)
+	SimulateEditionsAutoscaling(
+		ctx context.Context,
+		customerID string,
+		billingProjectsWithReservations []domain.BillingProjectWithReservation,
+		configurations []autoscaling.Configuration,
+	) ([]autoscaling.Result, error)
 }
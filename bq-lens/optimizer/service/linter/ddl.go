@@ -0,0 +1,55 @@
+package linter
+
+// ColumnsFromDDL returns the number of top level columns in a CREATE TABLE statement, or 0 if the
+// statement has no column list, as for views
+func ColumnsFromDDL(ddl string) int {
+	tokens := tokenize(ddl)
+
+	start := -1
+
+	for i, tok := range tokens {
+		if tok.is("TABLE") && i+2 < len(tokens) && tokens[i+2].isPunct("(") {
+			start = i + 3
+			break
+		}
+	}
+
+	if start < 0 {
+		return 0
+	}
+
+	var (
+		columns  int
+		depth    int
+		newEntry = true
+	)
+
+	for _, tok := range tokens[start:] {
+		switch {
+		case tok.isPunct("(") || tok.isPunct("<"):
+			depth++
+		case tok.isPunct(">"):
+			depth--
+		case tok.isPunct(")"):
+			if depth == 0 {
+				return columns
+			}
+
+			depth--
+		case depth == 0 && tok.isPunct(","):
+			newEntry = true
+			continue
+		}
+
+		if newEntry {
+			// table constraints are entries of the column list, but not columns
+			if !tok.is("PRIMARY") && !tok.is("FOREIGN") && !tok.is("CONSTRAINT") {
+				columns++
+			}
+
+			newEntry = false
+		}
+	}
+
+	return columns
+}
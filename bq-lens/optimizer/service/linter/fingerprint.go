@@ -0,0 +1,61 @@
+package linter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	literalPlaceholder = "?"
+	fingerprintLength  = 16
+)
+
+// Normalize returns the query with its literals replaced by placeholders and its comments,
+// whitespace, backticks and letter case dropped, so the runs of the same query with different
+// literals normalize the same. Lists of literals in IN clauses are collapsed into a single placeholder.
+func Normalize(query string) string {
+	var (
+		parts []string
+		// inLists holds for every open parenthesis if it opens the list of an IN clause
+		inLists []bool
+		prev    token
+	)
+
+	for _, tok := range tokenize(query) {
+		part := strings.ToLower(tok.text)
+
+		switch tok.kind {
+		case tokenString, tokenNumber:
+			part = literalPlaceholder
+
+			n := len(parts)
+			if len(inLists) > 0 && inLists[len(inLists)-1] && n >= 2 && parts[n-1] == "," && parts[n-2] == literalPlaceholder {
+				parts = parts[:n-1]
+				prev = tok
+
+				continue
+			}
+		case tokenPunct:
+			switch tok.text {
+			case "(":
+				inLists = append(inLists, prev.is("IN"))
+			case ")":
+				if len(inLists) > 0 {
+					inLists = inLists[:len(inLists)-1]
+				}
+			}
+		}
+
+		parts = append(parts, part)
+		prev = tok
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Fingerprint returns a stable identifier of the normalized query
+func Fingerprint(query string) string {
+	sum := sha256.Sum256([]byte(Normalize(query)))
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
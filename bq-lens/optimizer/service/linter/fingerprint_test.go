@@ -0,0 +1,45 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "literals, comments and whitespace",
+			query: "SELECT name -- the user name\nFROM   `project.dataset.users`\nWHERE id = 42 AND country = 'US' /* filter */",
+			want:  "select name from project.dataset.users where id = ? and country = ?",
+		},
+		{
+			name:  "in lists are collapsed",
+			query: "SELECT a FROM t WHERE b IN (1, 2, 3) AND c IN ('x') AND d = IF(e, 1, 2)",
+			want:  "select a from t where b in ( ? ) and c in ( ? ) and d = if ( e , ? , ? )",
+		},
+		{
+			name:  "triple quoted and escaped strings",
+			query: `SELECT """multi ' line""", 'it\'s', 1.5e3`,
+			want:  "select ? , ? , ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.query))
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := Fingerprint("SELECT * FROM t WHERE day = '2024-05-01' AND id IN (1, 2)")
+
+	assert.Len(t, fingerprint, 16)
+	assert.Equal(t, fingerprint, Fingerprint("select *\nfrom `t`\nwhere day = '2024-05-02' and id in (3)"))
+	assert.NotEqual(t, fingerprint, Fingerprint("SELECT * FROM t WHERE month = '2024-05-01' AND id IN (1, 2)"))
+}
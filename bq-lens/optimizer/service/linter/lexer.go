@@ -0,0 +1,211 @@
+package linter
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	// tokenIdent is a keyword, an identifier or a path such as project.dataset.table
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenStar
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	// text of identifiers is the path with the backticks removed
+	text string
+	// quoted is set for identifiers with a backtick quoted part, which can not be keywords
+	quoted bool
+	// qualified is set for stars that follow a path, as in alias.*
+	qualified bool
+}
+
+// keyword returns the upper case text of the token if it can be a keyword, or an empty string
+func (t token) keyword() string {
+	if t.kind != tokenIdent || t.quoted || strings.Contains(t.text, ".") {
+		return ""
+	}
+
+	return strings.ToUpper(t.text)
+}
+
+func (t token) is(keyword string) bool {
+	return t.keyword() == keyword
+}
+
+func (t token) isPunct(punct string) bool {
+	return t.kind == tokenPunct && t.text == punct
+}
+
+// isName checks if the token is an identifier or path that is not a reserved keyword
+func (t token) isName() bool {
+	if t.kind != tokenIdent {
+		return false
+	}
+
+	_, reserved := reservedKeywords[t.keyword()]
+
+	return !reserved
+}
+
+// firstSegment returns the first part of a path, which is the alias in alias.column
+func (t token) firstSegment() string {
+	segment, _, _ := strings.Cut(t.text, ".")
+	return strings.ToLower(segment)
+}
+
+// lastSegment returns the last part of a path, which is the column in alias.column
+func (t token) lastSegment() string {
+	return strings.ToLower(t.text[strings.LastIndex(t.text, ".")+1:])
+}
+
+// tokenize splits BigQuery Standard SQL into tokens, dropping whitespace and comments
+func tokenize(sql string) []token {
+	var tokens []token
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+
+		switch {
+		case isSpace(c):
+			i++
+		case c == '#' || strings.HasPrefix(sql[i:], "--"):
+			i = skipUntil(sql, i, "\n")
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipUntil(sql, i+2, "*/")
+		case c == '\'' || c == '"':
+			next := skipString(sql, i)
+			tokens = append(tokens, token{kind: tokenString, text: sql[i:next]})
+			i = next
+		case c == '`' || isIdentStart(c):
+			var tok token
+
+			tok, i = readPath(sql, i)
+			tokens = append(tokens, tok)
+
+			if strings.HasPrefix(sql[i:], ".*") {
+				tokens = append(tokens, token{kind: tokenStar, text: "*", qualified: true})
+				i += 2
+			}
+		case isDigit(c):
+			start := i
+			for i < len(sql) && (isIdentPart(sql[i]) || sql[i] == '.') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: sql[start:i]})
+		case c == '*':
+			tokens = append(tokens, token{kind: tokenStar, text: "*"})
+			i++
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func readPath(sql string, i int) (token, int) {
+	tok := token{kind: tokenIdent}
+
+	var parts []string
+
+	for i < len(sql) {
+		if sql[i] == '`' {
+			end := strings.IndexByte(sql[i+1:], '`')
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+
+			parts = append(parts, sql[i+1:i+1+end])
+			tok.quoted = true
+			i += end + 2
+		} else {
+			start := i
+			for i < len(sql) && isIdentPart(sql[i]) {
+				i++
+			}
+
+			parts = append(parts, sql[start:i])
+		}
+
+		if i+1 < len(sql) && sql[i] == '.' && (sql[i+1] == '`' || isIdentStart(sql[i+1])) {
+			i++
+			continue
+		}
+
+		break
+	}
+
+	tok.text = strings.Join(parts, ".")
+
+	return tok, min(i, len(sql))
+}
+
+// skipString returns the index following the quoted, possibly triple quoted, string that starts at i
+func skipString(sql string, i int) int {
+	quote := sql[i : i+1]
+
+	if triple := strings.Repeat(quote, 3); strings.HasPrefix(sql[i:], triple) {
+		return skipUntil(sql, i+3, triple)
+	}
+
+	for j := i + 1; j < len(sql); j++ {
+		switch sql[j] {
+		case '\\':
+			j++
+		case quote[0]:
+			return j + 1
+		}
+	}
+
+	return len(sql)
+}
+
+// skipUntil returns the index following the first occurrence of end at or after i
+func skipUntil(sql string, i int, end string) int {
+	idx := strings.Index(sql[i:], end)
+	if idx < 0 {
+		return len(sql)
+	}
+
+	return i + idx + len(end)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+// reservedKeywords are the reserved keywords of BigQuery Standard SQL, which can not be aliases
+var reservedKeywords = map[string]struct{}{
+	"ALL": {}, "AND": {}, "ANY": {}, "ARRAY": {}, "AS": {}, "ASC": {}, "ASSERT_ROWS_MODIFIED": {}, "AT": {},
+	"BETWEEN": {}, "BY": {}, "CASE": {}, "CAST": {}, "COLLATE": {}, "CONTAINS": {}, "CREATE": {}, "CROSS": {},
+	"CUBE": {}, "CURRENT": {}, "DEFAULT": {}, "DEFINE": {}, "DESC": {}, "DISTINCT": {}, "ELSE": {}, "END": {},
+	"ENUM": {}, "ESCAPE": {}, "EXCEPT": {}, "EXCLUDE": {}, "EXISTS": {}, "EXTRACT": {}, "FALSE": {}, "FETCH": {},
+	"FOLLOWING": {}, "FOR": {}, "FROM": {}, "FULL": {}, "GROUP": {}, "GROUPING": {}, "GROUPS": {}, "HASH": {},
+	"HAVING": {}, "IF": {}, "IGNORE": {}, "IN": {}, "INNER": {}, "INTERSECT": {}, "INTERVAL": {}, "INTO": {},
+	"IS": {}, "JOIN": {}, "LATERAL": {}, "LEFT": {}, "LIKE": {}, "LIMIT": {}, "LOOKUP": {}, "MERGE": {},
+	"NATURAL": {}, "NEW": {}, "NO": {}, "NOT": {}, "NULL": {}, "NULLS": {}, "OF": {}, "ON": {}, "OR": {},
+	"ORDER": {}, "OUTER": {}, "OVER": {}, "PARTITION": {}, "PRECEDING": {}, "PROTO": {}, "QUALIFY": {},
+	"RANGE": {}, "RECURSIVE": {}, "RESPECT": {}, "RIGHT": {}, "ROLLUP": {}, "ROWS": {}, "SELECT": {}, "SET": {},
+	"SOME": {}, "STRUCT": {}, "TABLESAMPLE": {}, "THEN": {}, "TO": {}, "TREAT": {}, "TRUE": {}, "UNBOUNDED": {},
+	"UNION": {}, "UNNEST": {}, "USING": {}, "WHEN": {}, "WHERE": {}, "WINDOW": {}, "WITH": {}, "WITHIN": {},
+}
@@ -0,0 +1,425 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Rule string
+
+const (
+	RuleSelectStar             Rule = "selectStar"
+	RuleMissingPartitionFilter Rule = "missingPartitionFilter"
+	RuleOrderByWithoutLimit    Rule = "orderByWithoutLimit"
+	RuleCrossJoin              Rule = "crossJoin"
+	RuleRepeatedSubquery       Rule = "repeatedSubquery"
+)
+
+const (
+	// WideTableColumns is the number of columns from which a SELECT * on a table is flagged
+	WideTableColumns = 20
+	// selectedColumnsEstimate is the number of columns a query is assumed to need instead of SELECT *
+	selectedColumnsEstimate = 10
+	// partitionFilterSavingsRatio is a conservative estimate of the share of the scanned bytes of a job
+	// that filtering on the partition field saves
+	partitionFilterSavingsRatio = 0.5
+)
+
+// Table is a table referenced by a query
+type Table struct {
+	// ID is the fully qualified table id, project.dataset.table
+	ID             string
+	PartitionField string
+	Columns        int
+}
+
+// Finding is an anti-pattern found in a query
+type Finding struct {
+	Rule    Rule
+	TableID string
+	Details string
+	// SavingsRatio is the estimated share of the scanned bytes that fixing the finding saves
+	SavingsRatio float64
+}
+
+type scopeKind int
+
+const (
+	scopeQuery scopeKind = iota
+	scopeWindow
+	scopeExpression
+)
+
+type scope struct {
+	kind   scopeKind
+	start  int
+	clause string
+
+	hasOrderBy bool
+	hasLimit   bool
+	hasStar    bool
+
+	fromPaths     []string
+	aliases       map[string]bool
+	filterColumns map[string]bool
+	expectTable   bool
+	expectAlias   bool
+}
+
+type linter struct {
+	tokens   []token
+	tables   []Table
+	findings []Finding
+
+	// unfiltered are the partitioned tables read by a query scope without a partition filter
+	unfiltered map[string]bool
+	// outerFilterColumns are the columns filtered by scopes that read CTEs or subqueries, which
+	// BigQuery can push down to the tables
+	outerFilterColumns map[string]bool
+	subqueries         map[string]int
+	subqueryOrder      []string
+}
+
+// Lint returns the anti-patterns found in a BigQuery Standard SQL query. The tables are the tables
+// the query references, and are used by the rules that depend on the table schema.
+func Lint(query string, tables []Table) []Finding {
+	l := &linter{
+		tokens:             tokenize(query),
+		tables:             tables,
+		unfiltered:         make(map[string]bool),
+		outerFilterColumns: make(map[string]bool),
+		subqueries:         make(map[string]int),
+	}
+
+	l.run()
+	l.checkPartitionFilters()
+	l.checkRepeatedSubqueries()
+
+	return l.findings
+}
+
+// CombinedSavingsRatio returns the share of the scanned bytes saved by fixing all the findings,
+// assuming every fix applies to the bytes left by the previous ones
+func CombinedSavingsRatio(findings []Finding) float64 {
+	remaining := 1.0
+
+	for _, f := range findings {
+		remaining *= 1 - f.SavingsRatio
+	}
+
+	return 1 - remaining
+}
+
+func (l *linter) run() {
+	stack := []*scope{newScope(scopeQuery, 0)}
+
+	for i, tok := range l.tokens {
+		cur := stack[len(stack)-1]
+		prev, next := l.at(i-1), l.at(i+1)
+
+		if filtering := filteringScope(stack); filtering != nil && tok.kind == tokenIdent {
+			filtering.filterColumns[tok.lastSegment()] = true
+		}
+
+		switch {
+		case tok.isPunct("("):
+			kind := scopeExpression
+			if prev.is("OVER") {
+				kind = scopeWindow
+			} else if next.is("SELECT") || next.is("WITH") {
+				kind = scopeQuery
+			}
+
+			if cur.kind == scopeQuery && cur.clause == "FROM" {
+				cur.expectTable = false
+			}
+
+			stack = append(stack, newScope(kind, i+1))
+
+			continue
+		case tok.isPunct(")"):
+			if len(stack) == 1 {
+				continue
+			}
+
+			closed := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			l.closeScope(closed, l.tokens[closed.start:i])
+
+			if parent := stack[len(stack)-1]; parent.kind == scopeQuery && parent.clause == "FROM" {
+				parent.expectAlias = true
+			}
+
+			continue
+		}
+
+		if cur.kind != scopeQuery {
+			continue
+		}
+
+		l.visit(cur, tok, prev, next)
+	}
+
+	for len(stack) > 0 {
+		l.closeScope(stack[len(stack)-1], nil)
+		stack = stack[:len(stack)-1]
+	}
+}
+
+func newScope(kind scopeKind, start int) *scope {
+	return &scope{
+		kind:          kind,
+		start:         start,
+		aliases:       make(map[string]bool),
+		filterColumns: make(map[string]bool),
+	}
+}
+
+// visit handles a token at the top level of a query scope
+func (l *linter) visit(cur *scope, tok, prev, next token) {
+	switch tok.keyword() {
+	case "SELECT":
+		cur.clause = "SELECT"
+		return
+	case "FROM":
+		cur.clause = "FROM"
+		cur.expectTable = true
+
+		return
+	case "JOIN":
+		cur.clause = "FROM"
+		cur.expectTable = true
+
+		if prev.is("CROSS") {
+			l.addFinding(RuleCrossJoin, "", "CROSS JOIN multiplies the rows of both sides of the join")
+		}
+
+		return
+	case "WHERE", "GROUP", "HAVING", "QUALIFY", "WINDOW":
+		cur.clause = tok.keyword()
+		return
+	case "ON", "USING":
+		cur.clause = "ON"
+		return
+	case "ORDER":
+		if next.is("BY") {
+			cur.clause = "ORDER"
+			cur.hasOrderBy = true
+		}
+
+		return
+	case "LIMIT":
+		cur.clause = "LIMIT"
+		cur.hasLimit = true
+
+		return
+	case "UNION", "INTERSECT", "EXCEPT":
+		// EXCEPT following a star excludes columns instead of rows
+		if prev.kind != tokenStar {
+			l.nextQuery(cur)
+		}
+
+		return
+	case "UNNEST":
+		cur.expectTable = false
+		return
+	case "AS":
+		return
+	}
+
+	if tok.isPunct(";") {
+		l.nextQuery(cur)
+		return
+	}
+
+	switch cur.clause {
+	case "SELECT":
+		if tok.kind == tokenStar && !next.is("EXCEPT") &&
+			(tok.qualified || prev.is("SELECT") || prev.is("DISTINCT") || prev.is("ALL") || prev.isPunct(",")) {
+			cur.hasStar = true
+		}
+	case "FROM":
+		l.visitFrom(cur, tok, next)
+	}
+}
+
+func (l *linter) visitFrom(cur *scope, tok, next token) {
+	switch {
+	case tok.isPunct(","):
+		if !next.is("UNNEST") && !(next.isName() && cur.aliases[next.firstSegment()]) {
+			l.addFinding(RuleCrossJoin, "", "comma join without a join condition multiplies the rows of both sides of the join")
+		}
+
+		cur.expectTable = true
+		cur.expectAlias = false
+	case tok.isName() && cur.expectTable:
+		cur.fromPaths = append(cur.fromPaths, tok.text)
+		cur.aliases[tok.lastSegment()] = true
+		cur.expectTable = false
+		cur.expectAlias = true
+	case tok.isName() && cur.expectAlias:
+		cur.aliases[strings.ToLower(tok.text)] = true
+		cur.expectAlias = false
+	default:
+		cur.expectAlias = false
+	}
+}
+
+func (l *linter) closeScope(s *scope, body []token) {
+	if s.kind != scopeQuery {
+		return
+	}
+
+	if s.hasOrderBy && !s.hasLimit {
+		l.addFinding(RuleOrderByWithoutLimit, "", "ORDER BY without LIMIT sorts the whole result on a single worker")
+	}
+
+	if len(body) > 0 && len(s.fromPaths) > 0 {
+		key := normalize(body)
+		if l.subqueries[key] == 0 {
+			l.subqueryOrder = append(l.subqueryOrder, key)
+		}
+
+		l.subqueries[key]++
+	}
+
+	l.closeQuery(s)
+}
+
+// nextQuery closes the query of a scope when another query follows it, after a set operation or
+// in the next statement of a script
+func (l *linter) nextQuery(s *scope) {
+	l.closeQuery(s)
+
+	s.clause = ""
+	s.hasStar = false
+	s.fromPaths = nil
+	s.aliases = make(map[string]bool)
+}
+
+// closeQuery checks the tables read by a query of a scope, which holds several queries when they
+// are combined with set operations
+func (l *linter) closeQuery(s *scope) {
+	readsTables := false
+
+	for _, path := range s.fromPaths {
+		table, ok := l.findTable(path)
+		if !ok {
+			continue
+		}
+
+		readsTables = true
+
+		if s.hasStar && table.Columns >= WideTableColumns {
+			l.findings = append(l.findings, Finding{
+				Rule:         RuleSelectStar,
+				TableID:      table.ID,
+				Details:      fmt.Sprintf("SELECT * reads all %d columns of the table", table.Columns),
+				SavingsRatio: 1 - float64(selectedColumnsEstimate)/float64(table.Columns),
+			})
+		}
+
+		if table.PartitionField != "" && !isFiltered(s.filterColumns, table.PartitionField) {
+			l.unfiltered[table.ID] = true
+		}
+	}
+
+	if !readsTables {
+		for column := range s.filterColumns {
+			l.outerFilterColumns[column] = true
+		}
+	}
+
+	s.filterColumns = make(map[string]bool)
+}
+
+func (l *linter) checkPartitionFilters() {
+	for _, table := range l.tables {
+		if !l.unfiltered[table.ID] || isFiltered(l.outerFilterColumns, table.PartitionField) {
+			continue
+		}
+
+		l.findings = append(l.findings, Finding{
+			Rule:         RuleMissingPartitionFilter,
+			TableID:      table.ID,
+			Details:      fmt.Sprintf("no filter on the partition field %s, all the partitions of the table are scanned", table.PartitionField),
+			SavingsRatio: partitionFilterSavingsRatio,
+		})
+	}
+}
+
+func (l *linter) checkRepeatedSubqueries() {
+	for _, key := range l.subqueryOrder {
+		if count := l.subqueries[key]; count > 1 {
+			l.addFinding(RuleRepeatedSubquery, "", fmt.Sprintf("identical subquery is evaluated %d times", count))
+		}
+	}
+}
+
+// addFinding adds a finding that costs slots rather than scanned bytes, so fixing it saves no bytes
+func (l *linter) addFinding(rule Rule, tableID, details string) {
+	l.findings = append(l.findings, Finding{
+		Rule:    rule,
+		TableID: tableID,
+		Details: details,
+	})
+}
+
+func (l *linter) at(i int) token {
+	if i < 0 || i >= len(l.tokens) {
+		return token{kind: tokenPunct}
+	}
+
+	return l.tokens[i]
+}
+
+// filteringScope returns the innermost query scope if the current token is in its WHERE clause
+func filteringScope(stack []*scope) *scope {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].kind == scopeQuery {
+			if stack[i].clause == "WHERE" {
+				return stack[i]
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func isFiltered(filterColumns map[string]bool, partitionField string) bool {
+	field := strings.ToLower(partitionField)
+
+	if field == "_partitiontime" || field == "_partitiondate" {
+		return filterColumns["_partitiontime"] || filterColumns["_partitiondate"]
+	}
+
+	return filterColumns[field]
+}
+
+func (l *linter) findTable(path string) (Table, bool) {
+	for _, table := range l.tables {
+		if matchesTable(path, table.ID) {
+			return table, true
+		}
+	}
+
+	return Table{}, false
+}
+
+// matchesTable checks if a table path of a query, which may omit the project and dataset, is the table id
+func matchesTable(path, tableID string) bool {
+	path, tableID = strings.ToLower(path), strings.ToLower(tableID)
+	return path == tableID || strings.HasSuffix(tableID, "."+path)
+}
+
+func normalize(tokens []token) string {
+	parts := make([]string, len(tokens))
+
+	for i, tok := range tokens {
+		parts[i] = strings.ToLower(tok.text)
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,160 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	var (
+		wideTable = Table{ID: "project.dataset.wide", Columns: 40}
+		narrow    = Table{ID: "project.dataset.narrow", Columns: 5}
+		events    = Table{ID: "project.dataset.events", PartitionField: "event_date", Columns: 8}
+		ingested  = Table{ID: "project.dataset.ingested", PartitionField: "_PARTITIONTIME", Columns: 8}
+	)
+
+	tests := []struct {
+		name   string
+		query  string
+		tables []Table
+		want   []Finding
+	}{
+		{
+			name:   "select star on a wide table",
+			query:  "SELECT * FROM `project.dataset.wide`",
+			tables: []Table{wideTable},
+			want: []Finding{
+				{Rule: RuleSelectStar, TableID: wideTable.ID, Details: "SELECT * reads all 40 columns of the table", SavingsRatio: 0.75},
+			},
+		},
+		{
+			name:   "qualified select star on a wide table",
+			query:  "SELECT w.*, n.id FROM dataset.wide w JOIN dataset.narrow n ON w.id = n.id",
+			tables: []Table{wideTable, narrow},
+			want: []Finding{
+				{Rule: RuleSelectStar, TableID: wideTable.ID, Details: "SELECT * reads all 40 columns of the table", SavingsRatio: 0.75},
+			},
+		},
+		{
+			name:   "select star on a narrow table, with EXCEPT and in COUNT",
+			query:  "SELECT * FROM narrow UNION ALL SELECT * EXCEPT (a) FROM wide UNION ALL SELECT COUNT(*), a * 2 FROM wide",
+			tables: []Table{wideTable, narrow},
+		},
+		{
+			name:   "missing partition filter",
+			query:  "SELECT id FROM `project`.dataset.events WHERE name = 'event_date' -- event_date",
+			tables: []Table{events},
+			want: []Finding{
+				{
+					Rule:         RuleMissingPartitionFilter,
+					TableID:      events.ID,
+					Details:      "no filter on the partition field event_date, all the partitions of the table are scanned",
+					SavingsRatio: 0.5,
+				},
+			},
+		},
+		{
+			name:   "partition filters",
+			query:  "SELECT id FROM dataset.events e WHERE DATE(e.event_date) > '2024-01-01'; SELECT id FROM ingested WHERE _PARTITIONDATE = CURRENT_DATE()",
+			tables: []Table{events, ingested},
+		},
+		{
+			name:   "partition field filtered only in a subquery",
+			query:  "SELECT id FROM dataset.events WHERE id IN (SELECT id FROM dataset.events WHERE event_date > '2024-01-01')",
+			tables: []Table{events},
+			want: []Finding{
+				{
+					Rule:         RuleMissingPartitionFilter,
+					TableID:      events.ID,
+					Details:      "no filter on the partition field event_date, all the partitions of the table are scanned",
+					SavingsRatio: 0.5,
+				},
+			},
+		},
+		{
+			name:   "partition field filtered on a CTE",
+			query:  "WITH e AS (SELECT * FROM dataset.events) SELECT id FROM e WHERE event_date > '2024-01-01'",
+			tables: []Table{events},
+		},
+		{
+			name:  "order by without limit",
+			query: "SELECT a FROM (SELECT a FROM t ORDER BY a LIMIT 10) ORDER BY a",
+			want: []Finding{
+				{Rule: RuleOrderByWithoutLimit, Details: "ORDER BY without LIMIT sorts the whole result on a single worker"},
+			},
+		},
+		{
+			name:  "order by in window functions and aggregations",
+			query: "SELECT ROW_NUMBER() OVER (PARTITION BY a ORDER BY b), ARRAY_AGG(c ORDER BY d) FROM t GROUP BY a",
+		},
+		{
+			name:  "cross joins",
+			query: "SELECT * FROM a CROSS JOIN b, `project.dataset.c`",
+			want: []Finding{
+				{Rule: RuleCrossJoin, Details: "CROSS JOIN multiplies the rows of both sides of the join"},
+				{Rule: RuleCrossJoin, Details: "comma join without a join condition multiplies the rows of both sides of the join"},
+			},
+		},
+		{
+			name:  "comma joins with arrays",
+			query: "SELECT * FROM t, UNNEST(t.items) AS item, t.tags tag",
+		},
+		{
+			name: "repeated subquery",
+			query: `SELECT
+				(SELECT MAX(ts) FROM logs WHERE level = 'ERROR') AS lastError,
+				(select max(ts) from logs where level = 'ERROR') - 1 AS beforeLastError,
+				(SELECT MAX(ts) FROM logs WHERE level = "ERROR )") AS other
+			`,
+			want: []Finding{
+				{Rule: RuleRepeatedSubquery, Details: "identical subquery is evaluated 2 times"},
+			},
+		},
+		{
+			name:  "comments and strings are ignored",
+			query: "/* SELECT * FROM a, b ORDER BY x */ SELECT a FROM t # CROSS JOIN u\nWHERE b = 'ORDER BY' LIMIT 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lint(tt.query, tt.tables))
+		})
+	}
+}
+
+func TestColumnsFromDDL(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want int
+	}{
+		{
+			name: "columns with nested types and options",
+			ddl: "CREATE TABLE `project.dataset.table`\n(\n  id STRING NOT NULL OPTIONS(description=\"a, b\"),\n" +
+				"  attrs ARRAY<STRUCT<key STRING, value NUMERIC(10, 2)>>,\n  ts TIMESTAMP,\n" +
+				"  PRIMARY KEY (id) NOT ENFORCED\n)\nPARTITION BY DATE(ts);",
+			want: 3,
+		},
+		{
+			name: "view",
+			ddl:  "CREATE VIEW `project.dataset.view` AS SELECT 1 AS a",
+			want: 0,
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ColumnsFromDDL(tt.ddl))
+		})
+	}
+}
+
+func TestCombinedSavingsRatio(t *testing.T) {
+	assert.Equal(t, 0.0, CombinedSavingsRatio(nil))
+	assert.InDelta(t, 0.55, CombinedSavingsRatio([]Finding{{SavingsRatio: 0.5}, {SavingsRatio: 0.1}}), 1e-9)
+}
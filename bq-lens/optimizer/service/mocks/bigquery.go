@@ -246,6 +246,36 @@ func (_m *Bigquery) GetMinAndMaxDates(ctx context.Context, bq *bigquery.Client,
 	return r0, r1
 }
 
+// GetSlotsConsumption provides a mock function with given fields: ctx, bq, projectID, location, lookbackDays
+func (_m *Bigquery) GetSlotsConsumption(ctx context.Context, bq *bigquery.Client, projectID string, location string, lookbackDays int) ([]bqmodels.SlotsConsumption, error) {
+	ret := _m.Called(ctx, bq, projectID, location, lookbackDays)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSlotsConsumption")
+	}
+
+	var r0 []bqmodels.SlotsConsumption
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *bigquery.Client, string, string, int) ([]bqmodels.SlotsConsumption, error)); ok {
+		return rf(ctx, bq, projectID, location, lookbackDays)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *bigquery.Client, string, string, int) []bqmodels.SlotsConsumption); ok {
+		r0 = rf(ctx, bq, projectID, location, lookbackDays)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bqmodels.SlotsConsumption)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *bigquery.Client, string, string, int) error); ok {
+		r1 = rf(ctx, bq, projectID, location, lookbackDays)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTableDiscoveryMetadata provides a mock function with given fields: ctx, bq
 func (_m *Bigquery) GetTableDiscoveryMetadata(ctx context.Context, bq *bigquery.Client) (*bigquery.TableMetadata, error) {
 	ret := _m.Called(ctx, bq)
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	autoscaling "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/autoscaling"
+
 	domain "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -45,6 +47,36 @@ func (_m *OptimizerService) Schedule(ctx context.Context) ([]error, error) {
 	return r0, r1
 }
 
+// SimulateEditionsAutoscaling provides a mock function with given fields: ctx, customerID, billingProjectsWithReservations, configurations
+func (_m *OptimizerService) SimulateEditionsAutoscaling(ctx context.Context, customerID string, billingProjectsWithReservations []domain.BillingProjectWithReservation, configurations []autoscaling.Configuration) ([]autoscaling.Result, error) {
+	ret := _m.Called(ctx, customerID, billingProjectsWithReservations, configurations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateEditionsAutoscaling")
+	}
+
+	var r0 []autoscaling.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.BillingProjectWithReservation, []autoscaling.Configuration) ([]autoscaling.Result, error)); ok {
+		return rf(ctx, customerID, billingProjectsWithReservations, configurations)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.BillingProjectWithReservation, []autoscaling.Configuration) []autoscaling.Result); ok {
+		r0 = rf(ctx, customerID, billingProjectsWithReservations, configurations)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]autoscaling.Result)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []domain.BillingProjectWithReservation, []autoscaling.Configuration) error); ok {
+		r1 = rf(ctx, customerID, billingProjectsWithReservations, configurations)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SingleCustomerOptimizer provides a mock function with given fields: ctx, customerID, payload
 func (_m *OptimizerService) SingleCustomerOptimizer(ctx context.Context, customerID string, payload domain.Payload) error {
 	ret := _m.Called(ctx, customerID, payload)
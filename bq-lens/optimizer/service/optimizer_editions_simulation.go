@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery/reservation/apiv1/reservationpb"
+
+	bqLensDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/domain"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	bqmodels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/bigquery"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/autoscaling"
+	pricebookDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/domain"
+)
+
+const (
+	// editionsSimulationLookbackDays is the window of slots consumption replayed by the simulation
+	editionsSimulationLookbackDays = 30
+	editionsSimulationStep         = time.Minute
+)
+
+// SimulateEditionsAutoscaling replays the slots consumption of the customer in the location of the
+// BQ Lens dataset against every editions configuration, or against candidates derived from the
+// consumption when no configuration is given, and returns a cost and queueing-risk point per configuration.
+func (s *OptimizerService) SimulateEditionsAutoscaling(
+	ctx context.Context,
+	customerID string,
+	billingProjectsWithReservations []domain.BillingProjectWithReservation,
+	configurations []autoscaling.Configuration,
+) ([]autoscaling.Result, error) {
+	connect, options, err := s.cloudConnect.NewGCPClients(ctx, customerID)
+	if err != nil {
+		return nil, wrapOperationError("NewGCPClients", customerID, err)
+	}
+
+	bq := connect.BQ.BigqueryService
+	defer bq.Close()
+
+	location, projectID, err := s.serviceBQ.GetDatasetLocationAndProjectID(ctx, bq, bqLensDomain.DoitCmpDatasetID)
+	if err != nil {
+		return nil, wrapOperationError("GetDatasetLocationAndProjectID", customerID, err)
+	}
+
+	slotsConsumption, err := s.serviceBQ.GetSlotsConsumption(ctx, bq, projectID, location, editionsSimulationLookbackDays)
+	if err != nil {
+		return nil, wrapOperationError("GetSlotsConsumption", customerID, err)
+	}
+
+	editionsPricebooks, err := s.pricebook.GetPricebooks(ctx)
+	if err != nil {
+		return nil, wrapOperationError("GetPricebooks", customerID, err)
+	}
+
+	reservationClient, err := s.reservations.NewClient(ctx, options)
+	if err != nil {
+		return nil, wrapOperationError("NewClient", customerID, err)
+	}
+
+	capacityCommitments := s.reservations.GetCapacityCommitments(ctx, customerID, reservationClient, billingProjectsWithReservations)
+
+	// the query covers the whole days of the lookback window up to now
+	now := s.timeNowFunc().UTC()
+	windowEnd := now.Truncate(editionsSimulationStep)
+	windowStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -editionsSimulationLookbackDays)
+
+	samples := toAutoscalingSamples(slotsConsumption, windowStart, windowEnd)
+
+	if len(configurations) == 0 {
+		configurations = autoscaling.Candidates(samples)
+	}
+
+	results, err := autoscaling.Simulate(
+		samples,
+		editionsSimulationStep,
+		toAutoscalingCommitments(capacityCommitments, billingProjectsWithReservations, location),
+		autoscaling.PricesForRegion(editionsPricebooks, strings.ToLower(location)),
+		configurations,
+	)
+	if err != nil {
+		return nil, wrapOperationError("Simulate", customerID, err)
+	}
+
+	return results, nil
+}
+
+// toAutoscalingSamples returns a sample for every step of the window, as the query only returns the
+// minutes in which jobs ran and the minutes without jobs consume no slots. The window is extended to
+// the consumption outside of it.
+func toAutoscalingSamples(slotsConsumption []bqmodels.SlotsConsumption, start, end time.Time) []autoscaling.Sample {
+	slotsByMinute := make(map[time.Time]float64, len(slotsConsumption))
+
+	for _, consumption := range slotsConsumption {
+		minute := consumption.Minute.UTC()
+		slotsByMinute[minute] += consumption.Slots
+
+		if minute.Before(start) {
+			start = minute
+		}
+
+		if !minute.Before(end) {
+			end = minute.Add(editionsSimulationStep)
+		}
+	}
+
+	var samples []autoscaling.Sample
+
+	for minute := start; minute.Before(end); minute = minute.Add(editionsSimulationStep) {
+		samples = append(samples, autoscaling.Sample{
+			Time:  minute,
+			Slots: slotsByMinute[minute],
+		})
+	}
+
+	return samples
+}
+
+// toAutoscalingCommitments returns the commitments of the billing projects in the location, as
+// commitments are regional and only cover reservations of the same location. The current
+// commitments apply to the whole replayed window.
+func toAutoscalingCommitments(
+	capacityCommitments map[string][]domain.CapacityCommitment,
+	billingProjectsWithReservations []domain.BillingProjectWithReservation,
+	location string,
+) []autoscaling.Commitment {
+	var commitments []autoscaling.Commitment
+
+	for _, billingProject := range billingProjectsWithReservations {
+		if !strings.EqualFold(billingProject.Location, location) {
+			continue
+		}
+
+		for _, commitment := range capacityCommitments[billingProject.Project] {
+			edition, ok := editionsByReservationEdition[commitment.Edition]
+			if !ok {
+				continue
+			}
+
+			commitments = append(commitments, autoscaling.Commitment{
+				Edition:   edition,
+				UsageType: usageTypeOfPlan(commitment.Plan),
+				Slots:     commitment.SlotCount,
+			})
+		}
+	}
+
+	return commitments
+}
+
+var editionsByReservationEdition = map[reservationpb.Edition]pricebookDomain.Edition{
+	reservationpb.Edition_STANDARD:        pricebookDomain.Standard,
+	reservationpb.Edition_ENTERPRISE:      pricebookDomain.Enterprise,
+	reservationpb.Edition_ENTERPRISE_PLUS: pricebookDomain.EnterprisePlus,
+}
+
+func usageTypeOfPlan(plan reservationpb.CapacityCommitment_CommitmentPlan) pricebookDomain.UsageType {
+	switch plan {
+	case reservationpb.CapacityCommitment_MONTHLY:
+		return pricebookDomain.Commit1Mo
+	case reservationpb.CapacityCommitment_ANNUAL:
+		return pricebookDomain.Commit1Yr
+	case reservationpb.CapacityCommitment_THREE_YEAR:
+		return pricebookDomain.Commit3Yr
+	default:
+		// flex and trial commitments are billed at the pay as you go price
+		return pricebookDomain.OnDemand
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	reservation "cloud.google.com/go/bigquery/reservation/apiv1"
+	"cloud.google.com/go/bigquery/reservation/apiv1/reservationpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+
+	bq "github.com/doitintl/bigquery"
+	crmMocks "github.com/doitintl/cloudresourcemanager/mocks"
+	bqLensDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/domain"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain"
+	bqmodels "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/domain/bigquery"
+	"github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/autoscaling"
+	serviceMocks "github.com/doitintl/hello/scheduled-tasks/bq-lens/optimizer/service/mocks"
+	pricebookDomain "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/domain"
+	pricebookMocks "github.com/doitintl/hello/scheduled-tasks/bq-lens/pricebook/service/mocks"
+	cloudConnectMocks "github.com/doitintl/hello/scheduled-tasks/cloudconnect/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/cloudconnect/pkg"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+)
+
+func TestOptimizerService_SimulateEditionsAutoscaling(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		someErr    = errors.New("some error")
+		customerID = "mock-customer-id"
+		location   = "US"
+		projectID  = "mock-project-id"
+		start      = time.Date(2024, 6, 17, 10, 0, 0, 0, time.UTC)
+		rsvClient  = &reservation.Client{}
+
+		billingProjects = []domain.BillingProjectWithReservation{
+			{Project: "reservation-project", Location: "us"},
+		}
+
+		slotsConsumption = []bqmodels.SlotsConsumption{
+			{Minute: start, Slots: 50},
+			{Minute: start.Add(time.Minute), Slots: 250},
+		}
+
+		capacityCommitments = map[string][]domain.CapacityCommitment{
+			"reservation-project": {
+				{
+					SlotCount: 100,
+					Plan:      reservationpb.CapacityCommitment_ANNUAL,
+					Edition:   reservationpb.Edition_ENTERPRISE,
+				},
+			},
+		}
+
+		editionsPricebooks = pricebookDomain.PriceBooksByEdition{
+			pricebookDomain.Enterprise: &pricebookDomain.PricebookDocument{
+				string(pricebookDomain.OnDemand):  {"us": 0.06},
+				string(pricebookDomain.Commit1Yr): {"us": 0.048},
+			},
+		}
+
+		configurations = []autoscaling.Configuration{
+			{Edition: pricebookDomain.Enterprise, Baseline: 100, MaxSlots: 400},
+		}
+	)
+
+	testBQClient, err := bigquery.NewClient(ctx, common.TestProjectID)
+	assert.NoError(t, err)
+
+	connClients := &pkg.ConnectClients{
+		CRM: crmMocks.NewCloudResourceManager(t),
+		BQ: &bq.Service{
+			BigqueryService: testBQClient,
+		},
+	}
+
+	clientOptions := []option.ClientOption{}
+
+	// the lookback window starts 30 days before the current day
+	samples := toAutoscalingSamples(slotsConsumption, time.Date(2024, 5, 18, 0, 0, 0, 0, time.UTC), start.Add(2*time.Minute))
+
+	wantResults, err := autoscaling.Simulate(
+		samples,
+		time.Minute,
+		[]autoscaling.Commitment{{Edition: pricebookDomain.Enterprise, UsageType: pricebookDomain.Commit1Yr, Slots: 100}},
+		autoscaling.PricesForRegion(editionsPricebooks, "us"),
+		configurations,
+	)
+	assert.NoError(t, err)
+
+	wantCandidateResults, err := autoscaling.Simulate(
+		samples,
+		time.Minute,
+		[]autoscaling.Commitment{{Edition: pricebookDomain.Enterprise, UsageType: pricebookDomain.Commit1Yr, Slots: 100}},
+		autoscaling.PricesForRegion(editionsPricebooks, "us"),
+		autoscaling.Candidates(samples),
+	)
+	assert.NoError(t, err)
+
+	type fields struct {
+		serviceBQ    serviceMocks.Bigquery
+		cloudConnect cloudConnectMocks.CloudConnectService
+		reservations serviceMocks.Reservations
+		pricebook    pricebookMocks.Pricebook
+	}
+
+	onSuccess := func(f *fields) {
+		f.cloudConnect.On("NewGCPClients", ctx, customerID).Return(connClients, clientOptions, nil)
+		f.serviceBQ.On("GetDatasetLocationAndProjectID", ctx, connClients.BQ.BigqueryService, bqLensDomain.DoitCmpDatasetID).
+			Return(location, projectID, nil)
+		f.serviceBQ.On("GetSlotsConsumption", ctx, connClients.BQ.BigqueryService, projectID, location, editionsSimulationLookbackDays).
+			Return(slotsConsumption, nil)
+		f.pricebook.On("GetPricebooks", ctx).Return(editionsPricebooks, nil)
+		f.reservations.On("NewClient", ctx, clientOptions).Return(rsvClient, nil)
+		f.reservations.On("GetCapacityCommitments", ctx, customerID, rsvClient, billingProjects).
+			Return(capacityCommitments)
+	}
+
+	tests := []struct {
+		name           string
+		configurations []autoscaling.Configuration
+		on             func(*fields)
+		want           []autoscaling.Result
+		wantErr        error
+	}{
+		{
+			name:           "simulates the configurations",
+			configurations: configurations,
+			on:             onSuccess,
+			want:           wantResults,
+		},
+		{
+			name: "simulates the candidates when no configuration is given",
+			on:   onSuccess,
+			want: wantCandidateResults,
+		},
+		{
+			name:           "invalid configuration",
+			configurations: []autoscaling.Configuration{{Edition: pricebookDomain.Enterprise, Baseline: 400, MaxSlots: 100}},
+			on:             onSuccess,
+			wantErr:        wrapOperationError("Simulate", customerID, autoscaling.ErrInvalidMaxSlots),
+		},
+		{
+			name:           "failed to get the slots consumption",
+			configurations: configurations,
+			on: func(f *fields) {
+				f.cloudConnect.On("NewGCPClients", ctx, customerID).Return(connClients, clientOptions, nil)
+				f.serviceBQ.On("GetDatasetLocationAndProjectID", ctx, connClients.BQ.BigqueryService, bqLensDomain.DoitCmpDatasetID).
+					Return(location, projectID, nil)
+				f.serviceBQ.On("GetSlotsConsumption", ctx, connClients.BQ.BigqueryService, projectID, location, editionsSimulationLookbackDays).
+					Return(nil, someErr)
+			},
+			wantErr: wrapOperationError("GetSlotsConsumption", customerID, someErr),
+		},
+		{
+			name:           "failed to get connection clients",
+			configurations: configurations,
+			on: func(f *fields) {
+				f.cloudConnect.On("NewGCPClients", ctx, customerID).Return(nil, nil, someErr)
+			},
+			wantErr: wrapOperationError("NewGCPClients", customerID, someErr),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := fields{}
+			if tt.on != nil {
+				tt.on(&fields)
+			}
+
+			s := &OptimizerService{
+				serviceBQ:    &fields.serviceBQ,
+				cloudConnect: &fields.cloudConnect,
+				reservations: &fields.reservations,
+				pricebook:    &fields.pricebook,
+				timeNowFunc: func() time.Time {
+					return start.Add(2*time.Minute + 30*time.Second)
+				},
+			}
+
+			got, err := s.SimulateEditionsAutoscaling(ctx, customerID, billingProjects, tt.configurations)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_toAutoscalingSamples(t *testing.T) {
+	start := time.Date(2024, 6, 17, 10, 0, 0, 0, time.UTC)
+
+	slotsConsumption := []bqmodels.SlotsConsumption{
+		{Minute: start.Add(time.Minute), Slots: 50},
+		{Minute: start.Add(4 * time.Minute), Slots: 250},
+		{Minute: start.Add(6 * time.Minute), Slots: 10},
+	}
+
+	assert.Equal(t, []autoscaling.Sample{
+		{Time: start},
+		{Time: start.Add(time.Minute), Slots: 50},
+		{Time: start.Add(2 * time.Minute)},
+		{Time: start.Add(3 * time.Minute)},
+		{Time: start.Add(4 * time.Minute), Slots: 250},
+		{Time: start.Add(5 * time.Minute)},
+		{Time: start.Add(6 * time.Minute), Slots: 10},
+	}, toAutoscalingSamples(slotsConsumption, start, start.Add(5*time.Minute)))
+
+	assert.Len(t, toAutoscalingSamples(nil, start, start.Add(time.Hour)), 60)
+}
+
+func Test_toAutoscalingCommitments(t *testing.T) {
+	billingProjects := []domain.BillingProjectWithReservation{
+		{Project: "project-us", Location: "us"},
+		{Project: "project-eu", Location: "EU"},
+	}
+
+	capacityCommitments := map[string][]domain.CapacityCommitment{
+		"project-us": {
+			{SlotCount: 100, Plan: reservationpb.CapacityCommitment_ANNUAL, Edition: reservationpb.Edition_ENTERPRISE},
+			{SlotCount: 200, Plan: reservationpb.CapacityCommitment_THREE_YEAR, Edition: reservationpb.Edition_ENTERPRISE_PLUS},
+			{SlotCount: 50, Plan: reservationpb.CapacityCommitment_MONTHLY, Edition: reservationpb.Edition_EDITION_UNSPECIFIED},
+		},
+		"project-eu": {
+			{SlotCount: 300, Plan: reservationpb.CapacityCommitment_ANNUAL, Edition: reservationpb.Edition_ENTERPRISE},
+		},
+	}
+
+	assert.Equal(t, []autoscaling.Commitment{
+		{Edition: pricebookDomain.Enterprise, UsageType: pricebookDomain.Commit1Yr, Slots: 100},
+		{Edition: pricebookDomain.EnterprisePlus, UsageType: pricebookDomain.Commit3Yr, Slots: 200},
+	}, toAutoscalingCommitments(capacityCommitments, billingProjects, "US"))
+}
@@ -359,6 +359,31 @@ func (d *AlertsFirestore) GetByCustomerAndAttribution(
 	return alerts, nil
 }
 
+// GetByMetricRef returns the alerts that are configured with the given calculated metric.
+func (d *AlertsFirestore) GetByMetricRef(ctx context.Context, metricRef *firestore.DocumentRef) ([]*domain.Alert, error) {
+	alertDocSnaps, err := d.firestoreClientFun(ctx).
+		Collection(AlertsCollection).
+		Where("config.calculatedMetric", "==", metricRef).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*domain.Alert, 0, len(alertDocSnaps))
+
+	for _, doc := range alertDocSnaps {
+		var alert domain.Alert
+		if err := doc.DataTo(&alert); err != nil {
+			return nil, err
+		}
+
+		alert.ID = doc.Ref.ID
+
+		alerts = append(alerts, &alert)
+	}
+
+	return alerts, nil
+}
+
 func isAttributionInAlertScope(alert *domain.Alert, attrID string) bool {
 	for _, filter := range alert.Config.Filters {
 		if filter.Values != nil && len(*filter.Values) > 0 {
@@ -35,4 +35,5 @@ type Alerts interface {
 		customerRef *firestore.DocumentRef,
 		attrRef *firestore.DocumentRef,
 	) ([]*domain.Alert, error)
+	GetByMetricRef(ctx context.Context, metricRef *firestore.DocumentRef) ([]*domain.Alert, error)
 }
@@ -3,6 +3,7 @@ package iface
 
 import (
 	"context"
+	"time"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
 )
@@ -12,5 +13,6 @@ type Notifications interface {
 	GetAlertDetectedNotifications(ctx context.Context, customerID string) (domain.NotificationsByAlertID, error)
 	GetCustomers(ctx context.Context) ([]string, error)
 	GetDetectedBreakdowns(ctx context.Context, etag, alertID, period string) ([]string, int, error)
+	GetRecentDetectedNotifications(ctx context.Context, etag, alertID string, since time.Time) ([]*domain.Notification, error)
 	UpdateNotificationTimeSent(ctx context.Context, notification *domain.Notification) error
 }
@@ -191,6 +191,32 @@ func (_m *Alerts) GetByCustomerAndAttribution(ctx context.Context, customerRef *
 	return r0, r1
 }
 
+// GetByMetricRef provides a mock function with given fields: ctx, metricRef
+func (_m *Alerts) GetByMetricRef(ctx context.Context, metricRef *firestore.DocumentRef) ([]*domain.Alert, error) {
+	ret := _m.Called(ctx, metricRef)
+
+	var r0 []*domain.Alert
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *firestore.DocumentRef) ([]*domain.Alert, error)); ok {
+		return rf(ctx, metricRef)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *firestore.DocumentRef) []*domain.Alert); ok {
+		r0 = rf(ctx, metricRef)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Alert)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *firestore.DocumentRef) error); ok {
+		r1 = rf(ctx, metricRef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCustomerOrgRef provides a mock function with given fields: ctx, customerID, orgID
 func (_m *Alerts) GetCustomerOrgRef(ctx context.Context, customerID string, orgID string) *firestore.DocumentRef {
 	ret := _m.Called(ctx, customerID, orgID)
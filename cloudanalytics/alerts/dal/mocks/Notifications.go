@@ -8,6 +8,8 @@ import (
 	domain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Notifications is an autogenerated mock type for the Notifications type
@@ -126,6 +128,32 @@ func (_m *Notifications) GetDetectedBreakdowns(ctx context.Context, etag string,
 	return r0, r1, r2
 }
 
+// GetRecentDetectedNotifications provides a mock function with given fields: ctx, etag, alertID, since
+func (_m *Notifications) GetRecentDetectedNotifications(ctx context.Context, etag string, alertID string, since time.Time) ([]*domain.Notification, error) {
+	ret := _m.Called(ctx, etag, alertID, since)
+
+	var r0 []*domain.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) ([]*domain.Notification, error)); ok {
+		return rf(ctx, etag, alertID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) []*domain.Notification); ok {
+		r0 = rf(ctx, etag, alertID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, etag, alertID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateNotificationTimeSent provides a mock function with given fields: ctx, notification
 func (_m *Notifications) UpdateNotificationTimeSent(ctx context.Context, notification *domain.Notification) error {
 	ret := _m.Called(ctx, notification)
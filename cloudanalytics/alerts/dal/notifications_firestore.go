@@ -225,3 +225,29 @@ func (d *NotificationsFirestore) GetDetectedBreakdowns(ctx context.Context, etag
 
 	return excludedNotifications, unsentDetectedNotifications, nil
 }
+
+// GetRecentDetectedNotifications returns the notifications of an alert that were detected since the given time.
+func (d *NotificationsFirestore) GetRecentDetectedNotifications(ctx context.Context, etag, alertID string, since time.Time) ([]*domain.Notification, error) {
+	iter := d.GetAlertRef(ctx, alertID).Collection(alertsDetectedCollection).
+		Where("etag", "==", etag).
+		Where("timeDetected", ">=", since).
+		Documents(ctx)
+
+	snapshots, err := d.documentsHandler.GetAll(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*domain.Notification, 0, len(snapshots))
+
+	for _, snap := range snapshots {
+		var notification domain.Notification
+		if err := snap.DataTo(&notification); err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, &notification)
+	}
+
+	return notifications, nil
+}
@@ -1,12 +1,14 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"cloud.google.com/go/firestore"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/collab"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
 )
 
@@ -24,9 +26,35 @@ type Alert struct {
 	IsValid         bool                     `json:"-" firestore:"isValid"`
 	Labels          []*firestore.DocumentRef `json:"-" firestore:"labels"`
 
+	SnoozedUntil       *time.Time          `json:"-" firestore:"snoozedUntil"`
+	MaintenanceWindows []MaintenanceWindow `json:"-" firestore:"maintenanceWindows"`
+	MutedBreakdowns    []string            `json:"-" firestore:"mutedBreakdowns"`
+
+	RecipientsMSTeamsChannels []common.MSTeamsChannel `json:"-" firestore:"recipientsMSTeamsChannels"`
+
 	ID string `json:"id" firestore:"-"`
 }
 
+// IsSilenced returns true if the alert is snoozed or in one of its maintenance windows at the given time
+func (a *Alert) IsSilenced(t time.Time) bool {
+	if a.SnoozedUntil != nil && t.Before(*a.SnoozedUntil) {
+		return true
+	}
+
+	for _, window := range a.MaintenanceWindows {
+		if window.IsActive(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsBreakdownMuted returns true if notifications of the given breakdown value are muted
+func (a *Alert) IsBreakdownMuted(breakdown string) bool {
+	return slices.Contains(a.MutedBreakdowns, breakdown)
+}
+
 func (a *Alert) SetCollaborators(collaborators []collab.Collaborator) {
 	a.Collaborators = collaborators
 }
@@ -55,6 +83,31 @@ type Config struct {
 	TimeInterval      report.TimeInterval      `firestore:"timeInterval"` // ["day", "week", "month", "quarter", "year"]
 	Values            []float64                `firestore:"values"`
 	IgnoreValuesRange *IgnoreValuesRange       `firestore:"ignoreValuesRange"`
+	Anomaly           *AnomalyConfig           `firestore:"anomaly"`
+	Dedup             *DedupPolicy             `firestore:"dedup"`
+}
+
+// GetAnomalyConfig returns the anomaly settings of the alert, falling back to the defaults
+// for any setting that is not set
+func (c *Config) GetAnomalyConfig() AnomalyConfig {
+	anomalyConfig := AnomalyConfig{
+		Method:   AnomalyMethodZScore,
+		Lookback: DefaultAnomalyLookback,
+	}
+
+	if c.Anomaly == nil {
+		return anomalyConfig
+	}
+
+	if c.Anomaly.Method != "" {
+		anomalyConfig.Method = c.Anomaly.Method
+	}
+
+	if c.Anomaly.Lookback > 0 {
+		anomalyConfig.Lookback = c.Anomaly.Lookback
+	}
+
+	return anomalyConfig
 }
 
 type Condition string
@@ -63,6 +116,87 @@ const (
 	ConditionForecast   Condition = "forecast"
 	ConditionPercentage Condition = "percentage"
 	ConditionValue      Condition = "value"
+	ConditionAnomaly    Condition = "anomaly"
+)
+
+type AnomalyMethod string
+
+const (
+	AnomalyMethodZScore AnomalyMethod = "zscore"
+	AnomalyMethodMAD    AnomalyMethod = "mad"
+)
+
+const (
+	DefaultAnomalyLookback = 14
+	// MinAnomalyBaseline is the minimum number of past intervals needed to evaluate an anomaly
+	MinAnomalyBaseline = 3
+)
+
+// AnomalyConfig configures the anomaly condition. The sensitivity is the first value of
+// the alert config, which is the number of standard deviations (or scaled MADs) a value
+// has to deviate from its baseline to trigger the alert.
+type AnomalyConfig struct {
+	Method   AnomalyMethod `firestore:"method"`   // zscore or mad
+	Lookback int           `firestore:"lookback"` // number of past intervals in the baseline
+}
+
+type Recurrence string
+
+const (
+	RecurrenceNone    Recurrence = ""
+	RecurrenceDaily   Recurrence = "daily"
+	RecurrenceWeekly  Recurrence = "weekly"
+	RecurrenceMonthly Recurrence = "monthly"
+)
+
+// MaintenanceWindow silences the alert between start and end. A recurring window repeats
+// every day, week or month from its first occurrence.
+type MaintenanceWindow struct {
+	Start      time.Time  `firestore:"start"`
+	End        time.Time  `firestore:"end"`
+	Recurrence Recurrence `firestore:"recurrence"`
+}
+
+// IsActive returns true if the given time is within an occurrence of the window
+func (w MaintenanceWindow) IsActive(t time.Time) bool {
+	if t.Before(w.Start) {
+		return false
+	}
+
+	start := w.Start
+
+	switch w.Recurrence {
+	case RecurrenceDaily, RecurrenceWeekly:
+		period := 24 * time.Hour
+		if w.Recurrence == RecurrenceWeekly {
+			period *= 7
+		}
+
+		start = w.Start.Add(t.Sub(w.Start).Truncate(period))
+	case RecurrenceMonthly:
+		months := (t.Year()-w.Start.Year())*12 + int(t.Month()-w.Start.Month())
+
+		start = w.Start.AddDate(0, months, 0)
+		if start.After(t) {
+			start = w.Start.AddDate(0, months-1, 0)
+		}
+	}
+
+	return t.Before(start.Add(w.End.Sub(w.Start)))
+}
+
+// DedupPolicy suppresses notifications of a breakdown that was already detected
+// in one of the previous intervals
+type DedupPolicy struct {
+	Intervals int `firestore:"intervals"` // number of previous intervals to look back for a repeat
+	// a repeat is still notified if its value changed by more than this percentage, 0 suppresses all repeats
+	MinChangePct float64 `firestore:"minChangePct"`
+}
+
+const (
+	MaxDedupIntervals     = 30
+	MaxMaintenanceWindows = 10
+	MaxMutedBreakdowns    = 100
 )
 
 const BreakdownLimitValue = 10
@@ -81,6 +215,8 @@ type Notification struct {
 	Period          string                 `firestore:"period"`
 	ConditionString string                 `firestore:"condition"`
 	AlertName       string                 `firestore:"alertName"`
+	ExpectedValue   *float64               `firestore:"expectedValue"`
+	Deviation       *float64               `firestore:"deviation"`
 }
 
 type NotificationsByAlertID map[string][]*Notification
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceWindow_IsActive(t *testing.T) {
+	start := time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		time       time.Time
+		want       bool
+	}{
+		{
+			name: "before the first occurrence",
+			time: start.Add(-time.Minute),
+		},
+		{
+			name: "in a one time window",
+			time: start.Add(time.Hour),
+			want: true,
+		},
+		{
+			name: "after a one time window",
+			time: start.AddDate(0, 0, 1),
+		},
+		{
+			name:       "in a later occurrence of a daily window",
+			recurrence: RecurrenceDaily,
+			time:       start.AddDate(0, 0, 5).Add(time.Hour),
+			want:       true,
+		},
+		{
+			name:       "between occurrences of a daily window",
+			recurrence: RecurrenceDaily,
+			time:       start.AddDate(0, 0, 5).Add(3 * time.Hour),
+		},
+		{
+			name:       "in a later occurrence of a weekly window",
+			recurrence: RecurrenceWeekly,
+			time:       start.AddDate(0, 0, 14).Add(time.Hour),
+			want:       true,
+		},
+		{
+			name:       "on a different weekday of a weekly window",
+			recurrence: RecurrenceWeekly,
+			time:       start.AddDate(0, 0, 15).Add(time.Hour),
+		},
+		{
+			name:       "in a later occurrence of a monthly window",
+			recurrence: RecurrenceMonthly,
+			time:       start.AddDate(0, 3, 0).Add(time.Hour),
+			want:       true,
+		},
+		{
+			name:       "between occurrences of a monthly window",
+			recurrence: RecurrenceMonthly,
+			time:       start.AddDate(0, 3, 5),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window := MaintenanceWindow{
+				Start:      start,
+				End:        start.Add(2 * time.Hour),
+				Recurrence: tt.recurrence,
+			}
+
+			assert.Equal(t, tt.want, window.IsActive(tt.time))
+		})
+	}
+}
+
+func TestAlert_IsSilenced(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		alert Alert
+		want  bool
+	}{
+		{
+			name: "not snoozed",
+		},
+		{
+			name:  "snoozed",
+			alert: Alert{SnoozedUntil: &later},
+			want:  true,
+		},
+		{
+			name:  "snooze expired",
+			alert: Alert{SnoozedUntil: &earlier},
+		},
+		{
+			name: "in a maintenance window",
+			alert: Alert{MaintenanceWindows: []MaintenanceWindow{
+				{Start: earlier, End: later},
+			}},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.alert.IsSilenced(now))
+		})
+	}
+}
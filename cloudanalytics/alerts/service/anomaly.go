@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+)
+
+// madScaleFactor makes the MAD a consistent estimator of the standard deviation for normally distributed data
+const madScaleFactor = 1.4826
+
+type anomalyPoint struct {
+	interval string
+	value    float64
+	row      []bigquery.Value
+}
+
+// checkRowsForAlertAnomaly groups the rows by breakdown and checks the value of the latest interval
+// of each breakdown against the baseline of its previous intervals, dropping the rows of muted breakdowns
+// and the anomalies that repeat a recent notification
+func (s *AnalyticsAlertsService) checkRowsForAlertAnomaly(ctx context.Context, alert *domain.Alert, rows [][]bigquery.Value, metricIndex int, alertID string, notificationsToAdd *[]*domain.Notification, qr *cloudanalytics.QueryRequest, suppressor *notificationSuppressor) error {
+	if alert.Config.Condition != domain.ConditionAnomaly {
+		return nil
+	}
+
+	series, latestInterval, err := getAnomalySeries(rows, metricIndex, len(qr.Rows), len(qr.Cols))
+	if err != nil {
+		return err
+	}
+
+	anomalyConfig := alert.Config.GetAnomalyConfig()
+
+	for _, points := range series {
+		last := points[len(points)-1]
+		if last.interval != latestInterval || suppressor.isMuted(last.row) {
+			continue
+		}
+
+		baseline := make([]float64, 0, len(points)-1)
+		for _, p := range points[:len(points)-1] {
+			baseline = append(baseline, p.value)
+		}
+
+		if len(baseline) > anomalyConfig.Lookback {
+			baseline = baseline[len(baseline)-anomalyConfig.Lookback:]
+		}
+
+		if len(baseline) < domain.MinAnomalyBaseline || isIgnoredValue(alert.Config.IgnoreValuesRange, last.value) {
+			continue
+		}
+
+		expected, deviation, ok := s.checkAnomalyCondition(alert.Config, anomalyConfig.Method, baseline, last.value)
+		if !ok {
+			continue
+		}
+
+		rowTimeDetected, err := s.getRowTimeDetected(alert.Config.TimeInterval, qr, last.row)
+		if err != nil {
+			return err
+		}
+
+		if suppressor.isDuplicate(last.row, last.value, rowTimeDetected) {
+			continue
+		}
+
+		notification, err := s.buildNotification(ctx, alert, &last.row, last.value, alertID, rowTimeDetected)
+		if err != nil {
+			return err
+		}
+
+		notification.ExpectedValue = &expected
+		notification.Deviation = &deviation
+
+		*notificationsToAdd = append(*notificationsToAdd, notification)
+		suppressor.track(notification)
+	}
+
+	return nil
+}
+
+// getAnomalySeries returns the values of each breakdown sorted by interval, and the latest interval of the rows
+func getAnomalySeries(rows [][]bigquery.Value, metricIndex, numRows, numCols int) (map[string][]anomalyPoint, string, error) {
+	series := make(map[string][]anomalyPoint)
+
+	var latestInterval string
+
+	for _, row := range rows {
+		value, ok := row[metricIndex].(float64)
+		if !ok {
+			return nil, "", ErrInvalidTableCell
+		}
+
+		breakdown, err := query.GetRowKey(row, numRows)
+		if err != nil {
+			return nil, "", err
+		}
+
+		intervalParts := make([]string, 0, numCols)
+
+		for _, col := range row[numRows : numRows+numCols] {
+			part, err := query.BigqueryValueToString(col)
+			if err != nil {
+				return nil, "", err
+			}
+
+			intervalParts = append(intervalParts, part)
+		}
+
+		interval := strings.Join(intervalParts, "-")
+		if interval > latestInterval {
+			latestInterval = interval
+		}
+
+		series[breakdown] = append(series[breakdown], anomalyPoint{
+			interval: interval,
+			value:    value,
+			row:      row,
+		})
+	}
+
+	for _, points := range series {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].interval < points[j].interval
+		})
+	}
+
+	return series, latestInterval, nil
+}
+
+// checkAnomalyCondition checks if the value deviates from the baseline by more than the sensitivity of the alert,
+// in the direction of the alert operator. It returns the expected value and the deviation from it.
+func (s *AnalyticsAlertsService) checkAnomalyCondition(config *domain.Config, method domain.AnomalyMethod, baseline []float64, value float64) (float64, float64, bool) {
+	var expected, scale float64
+
+	switch method {
+	case domain.AnomalyMethodMAD:
+		expected = median(baseline)
+
+		absDeviations := make([]float64, 0, len(baseline))
+		for _, v := range baseline {
+			absDeviations = append(absDeviations, math.Abs(v-expected))
+		}
+
+		scale = madScaleFactor * median(absDeviations)
+	default:
+		expected = mean(baseline)
+
+		var variance float64
+		for _, v := range baseline {
+			variance += (v - expected) * (v - expected)
+		}
+
+		scale = math.Sqrt(variance / float64(len(baseline)))
+	}
+
+	deviation := value - expected
+	if deviation == 0 {
+		return expected, deviation, false
+	}
+
+	// a flat baseline has no spread, so any change from it is an anomaly
+	score := math.Inf(1)
+	if scale > 0 {
+		score = math.Abs(deviation) / scale
+	}
+
+	sensitivity := config.Values[0]
+
+	switch config.Operator {
+	case report.MetricFilterGreaterThan:
+		return expected, deviation, deviation > 0 && score > sensitivity
+	case report.MetricFilterLessThan:
+		return expected, deviation, deviation < 0 && score > sensitivity
+	}
+
+	return expected, deviation, false
+}
+
+func isIgnoredValue(ignoreValuesRange *domain.IgnoreValuesRange, value float64) bool {
+	return ignoreValuesRange != nil &&
+		value >= ignoreValuesRange.LowerBound &&
+		value <= ignoreValuesRange.UpperBound
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	alertsMock "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+)
+
+func TestAnalyticsAlertsService_checkRowsForAlertAnomaly(t *testing.T) {
+	anomalyQR := &cloudanalytics.QueryRequest{
+		Metric: report.MetricCost,
+		Rows:   []*domainQuery.QueryRequestX{{}},
+		Cols:   []*domainQuery.QueryRequestX{{Key: "year"}, {Key: "month"}},
+	}
+
+	rows := [][]bigquery.Value{
+		{"A", "2024", "01", float64(100)},
+		{"A", "2024", "02", float64(102)},
+		{"A", "2024", "03", float64(98)},
+		{"A", "2024", "04", float64(100)},
+		{"A", "2024", "05", float64(130)},
+		{"B", "2024", "01", float64(100)},
+		{"B", "2024", "02", float64(102)},
+		{"B", "2024", "03", float64(98)},
+		{"B", "2024", "04", float64(100)},
+		{"B", "2024", "05", float64(101)},
+		// not enough intervals for a baseline
+		{"C", "2024", "04", float64(10)},
+		{"C", "2024", "05", float64(1000)},
+		// no value in the latest interval
+		{"D", "2024", "01", float64(10)},
+		{"D", "2024", "02", float64(10)},
+		{"D", "2024", "03", float64(10)},
+		{"D", "2024", "04", float64(1000)},
+	}
+
+	newConfig := func() *domain.Config {
+		return &domain.Config{
+			Values:       []float64{3},
+			Operator:     report.MetricFilterGreaterThan,
+			Condition:    domain.ConditionAnomaly,
+			Rows:         []string{"breakdown:123"},
+			TimeInterval: report.TimeIntervalMonth,
+		}
+	}
+
+	tests := []struct {
+		name              string
+		config            func() *domain.Config
+		rows              [][]bigquery.Value
+		wantBreakdowns    []string
+		wantExpectedValue float64
+		wantDeviation     float64
+		wantErr           error
+	}{
+		{
+			name:              "z-score spike",
+			config:            newConfig,
+			rows:              rows,
+			wantBreakdowns:    []string{"A"},
+			wantExpectedValue: 100,
+			wantDeviation:     30,
+		},
+		{
+			name: "MAD spike",
+			config: func() *domain.Config {
+				config := newConfig()
+				config.Anomaly = &domain.AnomalyConfig{Method: domain.AnomalyMethodMAD}
+
+				return config
+			},
+			rows:              rows,
+			wantBreakdowns:    []string{"A"},
+			wantExpectedValue: 100,
+			wantDeviation:     30,
+		},
+		{
+			name: "spike does not match the less than operator",
+			config: func() *domain.Config {
+				config := newConfig()
+				config.Operator = report.MetricFilterLessThan
+
+				return config
+			},
+			rows: rows,
+		},
+		{
+			name: "value is in the ignored values range",
+			config: func() *domain.Config {
+				config := newConfig()
+				config.IgnoreValuesRange = &domain.IgnoreValuesRange{LowerBound: 0, UpperBound: 200}
+
+				return config
+			},
+			rows: rows,
+		},
+		{
+			name:    "invalid table cell",
+			config:  newConfig,
+			rows:    [][]bigquery.Value{{"A", "2024", "01", "invalid"}},
+			wantErr: ErrInvalidTableCell,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertsDal := &alertsMock.Alerts{}
+			alertsDal.On("GetRef", mock.Anything, mock.Anything).Return(nil)
+
+			s := &AnalyticsAlertsService{
+				alertsDal: alertsDal,
+			}
+
+			alert := &domain.Alert{Config: tt.config()}
+			notifications := []*domain.Notification{}
+
+			err := s.checkRowsForAlertAnomaly(context.Background(), alert, tt.rows, 3, testAlertID, &notifications, anomalyQR, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			breakdowns := make([]string, 0, len(notifications))
+			for _, notification := range notifications {
+				breakdowns = append(breakdowns, *notification.Breakdown)
+
+				assert.Equal(t, tt.wantExpectedValue, *notification.ExpectedValue)
+				assert.Equal(t, tt.wantDeviation, *notification.Deviation)
+			}
+
+			assert.ElementsMatch(t, tt.wantBreakdowns, breakdowns)
+		})
+	}
+}
+
+func TestAnalyticsAlertsService_checkAnomalyCondition(t *testing.T) {
+	config := &domain.Config{
+		Values:   []float64{2},
+		Operator: report.MetricFilterLessThan,
+	}
+
+	tests := []struct {
+		name          string
+		baseline      []float64
+		value         float64
+		wantExpected  float64
+		wantDeviation float64
+		wantAnomaly   bool
+	}{
+		{
+			name:          "drop",
+			baseline:      []float64{10, 12, 8, 10},
+			value:         5,
+			wantExpected:  10,
+			wantDeviation: -5,
+			wantAnomaly:   true,
+		},
+		{
+			name:          "drop within the sensitivity",
+			baseline:      []float64{10, 12, 8, 10},
+			value:         8,
+			wantExpected:  10,
+			wantDeviation: -2,
+		},
+		{
+			name:          "drop from a flat baseline",
+			baseline:      []float64{10, 10, 10},
+			value:         9,
+			wantExpected:  10,
+			wantDeviation: -1,
+			wantAnomaly:   true,
+		},
+		{
+			name:         "no change from a flat baseline",
+			baseline:     []float64{10, 10, 10},
+			value:        10,
+			wantExpected: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AnalyticsAlertsService{}
+
+			expected, deviation, anomaly := s.checkAnomalyCondition(config, domain.AnomalyMethodZScore, tt.baseline, tt.value)
+			assert.Equal(t, tt.wantExpected, expected)
+			assert.Equal(t, tt.wantDeviation, deviation)
+			assert.Equal(t, tt.wantAnomaly, anomaly)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
+)
+
+const (
+	textOpenAlert = "Open Alert"
+	textAlertURL  = "https://console.doit.com/customers/%s/analytics/alerts/%s"
+)
+
+// sendMSTeamsAlert posts the alert notifications to the teams channels of the alert,
+// using the same content as the alert email
+func (s *AnalyticsAlertsService) sendMSTeamsAlert(ctx context.Context, customerID, alertID string, alert *domain.Alert, body *EmailBody) {
+	l := s.loggerProvider(ctx)
+
+	if len(alert.RecipientsMSTeamsChannels) == 0 {
+		return
+	}
+
+	card := buildMSTeamsCard(customerID, alertID, body)
+
+	for _, channel := range alert.RecipientsMSTeamsChannels {
+		if !common.Production && customerID != common.DoitCustomerID { // on development - only send alerts for doit
+			l.Infof("teams alert to %s didn't send while in development", channel.Name)
+			continue
+		}
+
+		if err := s.msTeams.Post(ctx, channel.WebhookURL, card); err != nil {
+			l.Errorf("failed to post teams alert for alert %s to channel %s; %s", alertID, channel.Name, err)
+		}
+	}
+}
+
+func buildMSTeamsCard(customerID, alertID string, body *EmailBody) *msteams.AdaptiveCard {
+	card := msteams.NewAdaptiveCard().
+		AddTitle("Alert: " + body.Name).
+		AddText("**Condition**: " + body.Condition)
+
+	if body.BreakdownLabel != nil {
+		card.AddText("**Breakdown**: " + *body.BreakdownLabel)
+	}
+
+	if body.Value != nil {
+		card.AddFacts(msteams.Fact{Title: "Value", Value: *body.Value})
+	}
+
+	for _, data := range body.NotificationsData {
+		facts := make([]msteams.Fact, 0, len(data.Items)+1)
+
+		if data.Value != nil {
+			facts = append(facts, msteams.Fact{Title: "Value", Value: *data.Value})
+		}
+
+		for _, item := range data.Items {
+			facts = append(facts, msteams.Fact{Title: item.Label, Value: item.Value})
+		}
+
+		if data.Timestamp != "" {
+			card.AddText("**" + data.Timestamp + "**")
+		}
+
+		card.AddFacts(facts...)
+	}
+
+	if body.TopHits != nil {
+		card.AddText(*body.TopHits)
+	}
+
+	return card.AddOpenURLAction(textOpenAlert, fmt.Sprintf(textAlertURL, customerID, alertID))
+}
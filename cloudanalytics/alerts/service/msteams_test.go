@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
+	msteamsMocks "github.com/doitintl/hello/scheduled-tasks/msteams/mocks"
+)
+
+func TestAnalyticsAlertsService_sendMSTeamsAlert(t *testing.T) {
+	breakdownLabel := "Service"
+	topHits := "Top hits: Compute Engine"
+	value := "$1,200.00"
+
+	body := &EmailBody{
+		Name:           "Daily cost",
+		Condition:      "Cost greater than $1,000.00",
+		BreakdownLabel: &breakdownLabel,
+		NotificationsData: []TimestampData{
+			{
+				Timestamp: "May 20th",
+				Items:     []EmailBodyItem{{Label: "Compute Engine", Value: value}},
+			},
+		},
+		TopHits: &topHits,
+	}
+
+	channels := []common.MSTeamsChannel{
+		{Name: "finops", WebhookURL: "https://doit.webhook.office.com/webhookb2/a"},
+		{Name: "eng", WebhookURL: "https://doit.webhook.office.com/webhookb2/b"},
+	}
+
+	tests := []struct {
+		name     string
+		channels []common.MSTeamsChannel
+		on       func(n *msteamsMocks.Notifier)
+	}{
+		{
+			name:     "post to each channel",
+			channels: channels,
+			on: func(n *msteamsMocks.Notifier) {
+				for _, channel := range channels {
+					n.On("Post", mock.Anything, channel.WebhookURL, mock.AnythingOfType("*msteams.AdaptiveCard")).Return(nil).Once()
+				}
+			},
+		},
+		{
+			name:     "failing channel does not stop the others",
+			channels: channels,
+			on: func(n *msteamsMocks.Notifier) {
+				n.On("Post", mock.Anything, channels[0].WebhookURL, mock.Anything).Return(errors.New("error")).Once()
+				n.On("Post", mock.Anything, channels[1].WebhookURL, mock.Anything).Return(nil).Once()
+			},
+		},
+		{
+			name: "no channels",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := msteamsMocks.NewNotifier(t)
+			if tt.on != nil {
+				tt.on(n)
+			}
+
+			s := &AnalyticsAlertsService{
+				loggerProvider: logger.FromContext,
+				msTeams:        n,
+			}
+
+			alert := &domain.Alert{RecipientsMSTeamsChannels: tt.channels}
+
+			s.sendMSTeamsAlert(context.Background(), common.DoitCustomerID, testAlertID, alert, body)
+		})
+	}
+}
+
+func TestBuildMSTeamsCard(t *testing.T) {
+	value := "$1,200.00"
+
+	card := buildMSTeamsCard("customer-id", testAlertID, &EmailBody{
+		Name:      "Monthly cost",
+		Condition: "Cost greater than $1,000.00",
+		Value:     &value,
+	})
+
+	assert.Equal(t, []msteams.Element{
+		{Type: msteams.ElementTypeTextBlock, Text: "Alert: Monthly cost", Wrap: true, Weight: "Bolder", Size: "Medium"},
+		{Type: msteams.ElementTypeTextBlock, Text: "**Condition**: Cost greater than $1,000.00", Wrap: true},
+		{Type: msteams.ElementTypeFactSet, Facts: []msteams.Fact{{Title: "Value", Value: value}}},
+	}, card.Body)
+	assert.Equal(t, "https://console.doit.com/customers/customer-id/analytics/alerts/"+testAlertID, card.Actions[0].URL)
+}
@@ -125,6 +125,11 @@ func (s *AnalyticsAlertsService) RefreshAlert(ctx context.Context, alertID strin
 		}
 	}
 
+	if alert.IsSilenced(time.Now().UTC()) {
+		l.Infof("alert %s is snoozed or in a maintenance window", alertID)
+		return nil
+	}
+
 	qr, err := s.getReportRequest(ctx, alert, alertID)
 	if err != nil {
 		return err
@@ -376,6 +381,14 @@ func (s *AnalyticsAlertsService) getTimeSettings(today time.Time, config *domain
 		comparative = &percent
 	}
 
+	// the baseline and the evaluated value are complete intervals, so the current interval is not included
+	if config.Condition == domain.ConditionAnomaly {
+		ts.Mode = report.TimeSettingsModeLast
+		ts.Unit = report.TimeSettingsUnit(config.TimeInterval)
+		ts.Amount = config.GetAnomalyConfig().Lookback + 1
+		ts.IncludeCurrent = false
+	}
+
 	if config.Condition == domain.ConditionForecast {
 		switch config.TimeInterval {
 		case report.TimeIntervalWeek, report.TimeIntervalMonth, report.TimeIntervalQuarter:
@@ -398,11 +411,20 @@ func (s *AnalyticsAlertsService) refreshAlert(ctx context.Context, alert *domain
 	notificationsToAdd := []*domain.Notification{}
 	metricIndex := len(qr.Rows) + len(qr.Cols) + qr.GetMetricIndex()
 
-	if err := s.checkRowsForAlertValue(ctx, alert, result.Rows, metricIndex, alertID, &notificationsToAdd, qr); err != nil {
+	suppressor, err := s.getNotificationSuppressor(ctx, alert, alertID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkRowsForAlertValue(ctx, alert, result.Rows, metricIndex, alertID, &notificationsToAdd, qr, suppressor); err != nil {
 		return err
 	}
 
-	if err := s.checkRowsForAlertPercentatge(ctx, alert, result.Rows, metricIndex, alertID, &notificationsToAdd, qr); err != nil {
+	if err := s.checkRowsForAlertPercentatge(ctx, alert, result.Rows, metricIndex, alertID, &notificationsToAdd, qr, suppressor); err != nil {
+		return err
+	}
+
+	if err := s.checkRowsForAlertAnomaly(ctx, alert, result.Rows, metricIndex, alertID, &notificationsToAdd, qr, suppressor); err != nil {
 		return err
 	}
 
@@ -542,21 +564,26 @@ func (s *AnalyticsAlertsService) getRowTimeDetected(timeInterval report.TimeInte
 	return nil, nil
 }
 
-func (s *AnalyticsAlertsService) checkRowsForAlertValue(ctx context.Context, alert *domain.Alert, rows [][]bigquery.Value, metricIndex int, alertID string, notificationsToAdd *[]*domain.Notification, qr *cloudanalytics.QueryRequest) error {
+func (s *AnalyticsAlertsService) checkRowsForAlertValue(ctx context.Context, alert *domain.Alert, rows [][]bigquery.Value, metricIndex int, alertID string, notificationsToAdd *[]*domain.Notification, qr *cloudanalytics.QueryRequest, suppressor *notificationSuppressor) error {
 	if alert.Config.Condition == domain.ConditionValue {
 		for _, row := range rows {
+			if suppressor.isMuted(row) {
+				continue
+			}
+
 			rowTimeDetected, err := s.getRowTimeDetected(alert.Config.TimeInterval, qr, row)
 			if err != nil {
 				return err
 			}
 
-			notification, err := s.checkRowForAlertValue(ctx, row, alert, metricIndex, alertID, rowTimeDetected)
+			notification, err := s.checkRowForAlertValue(ctx, row, alert, metricIndex, alertID, rowTimeDetected, suppressor)
 			if err != nil {
 				return err
 			}
 
 			if notification != nil {
 				*notificationsToAdd = append(*notificationsToAdd, notification)
+				suppressor.track(notification)
 			}
 		}
 	}
@@ -578,11 +605,15 @@ func (s *AnalyticsAlertsService) isFirstPercentageDay(rowTimeDetected *time.Time
 	return false
 }
 
-func (s *AnalyticsAlertsService) checkRowsForAlertPercentatge(ctx context.Context, alert *domain.Alert, rows [][]bigquery.Value, metricIndex int, alertID string, notificationsToAdd *[]*domain.Notification, qr *cloudanalytics.QueryRequest) error {
+func (s *AnalyticsAlertsService) checkRowsForAlertPercentatge(ctx context.Context, alert *domain.Alert, rows [][]bigquery.Value, metricIndex int, alertID string, notificationsToAdd *[]*domain.Notification, qr *cloudanalytics.QueryRequest, suppressor *notificationSuppressor) error {
 	if alert.Config.Condition == domain.ConditionPercentage {
 		metricIndex += qr.GetMetricCount()
 
 		for _, row := range rows {
+			if suppressor.isMuted(row) {
+				continue
+			}
+
 			rowTimeDetected, err := s.getRowTimeDetected(alert.Config.TimeInterval, qr, row)
 			if err != nil {
 				return err
@@ -592,13 +623,14 @@ func (s *AnalyticsAlertsService) checkRowsForAlertPercentatge(ctx context.Contex
 				continue
 			}
 
-			notification, err := s.checkRowForAlertPercentage(ctx, row, alert, metricIndex, alertID, rowTimeDetected)
+			notification, err := s.checkRowForAlertPercentage(ctx, row, alert, metricIndex, alertID, rowTimeDetected, suppressor)
 			if err != nil {
 				return err
 			}
 
 			if notification != nil {
 				*notificationsToAdd = append(*notificationsToAdd, notification)
+				suppressor.track(notification)
 			}
 		}
 	}
@@ -670,7 +702,7 @@ func (s *AnalyticsAlertsService) validateAlert(alert *domain.Alert) error {
 	return nil
 }
 
-func (s *AnalyticsAlertsService) checkRowForAlertValue(ctx context.Context, row []bigquery.Value, alert *domain.Alert, metricIndex int, alertID string, rowTimeDetected *time.Time) (*domain.Notification, error) {
+func (s *AnalyticsAlertsService) checkRowForAlertValue(ctx context.Context, row []bigquery.Value, alert *domain.Alert, metricIndex int, alertID string, rowTimeDetected *time.Time, suppressor *notificationSuppressor) (*domain.Notification, error) {
 	var value float64
 	switch row[metricIndex].(type) {
 	case float64:
@@ -679,6 +711,10 @@ func (s *AnalyticsAlertsService) checkRowForAlertValue(ctx context.Context, row
 		return nil, ErrInvalidTableCell
 	}
 
+	if suppressor.isDuplicate(row, value, rowTimeDetected) {
+		return nil, nil
+	}
+
 	return s.newNotification(ctx, alert, &row, value, alertID, rowTimeDetected)
 }
 
@@ -707,7 +743,7 @@ func (s *AnalyticsAlertsService) getComparativePctChangeForMetric(row []bigquery
 	return pct, nil
 }
 
-func (s *AnalyticsAlertsService) checkRowForAlertPercentage(ctx context.Context, row []bigquery.Value, alert *domain.Alert, metricIndex int, alertID string, rowTimeDetected *time.Time) (*domain.Notification, error) {
+func (s *AnalyticsAlertsService) checkRowForAlertPercentage(ctx context.Context, row []bigquery.Value, alert *domain.Alert, metricIndex int, alertID string, rowTimeDetected *time.Time, suppressor *notificationSuppressor) (*domain.Notification, error) {
 	l := s.loggerProvider(ctx)
 
 	pct, err := s.getComparativePctChangeForMetric(row, metricIndex)
@@ -729,6 +765,10 @@ func (s *AnalyticsAlertsService) checkRowForAlertPercentage(ctx context.Context,
 		}
 	}
 
+	if suppressor.isDuplicate(row, pct, rowTimeDetected) {
+		return nil, nil
+	}
+
 	return s.newNotification(ctx, alert, &row, pct, alertID, rowTimeDetected)
 }
 
@@ -773,6 +813,11 @@ func (s *AnalyticsAlertsService) newNotification(ctx context.Context, alert *dom
 		return nil, nil
 	}
 
+	return s.buildNotification(ctx, alert, row, value, alertID, rowTimeDetected)
+}
+
+// buildNotification builds a notification(detected alert) of the alert for a row that met the alert condition
+func (s *AnalyticsAlertsService) buildNotification(ctx context.Context, alert *domain.Alert, row *[]bigquery.Value, value float64, alertID string, rowTimeDetected *time.Time) (*domain.Notification, error) {
 	now := time.Now().UTC()
 
 	expireBy, err := s.getExpireByTime(alert.Config.TimeInterval, now)
@@ -864,6 +909,9 @@ func (s *AnalyticsAlertsService) buildBreakdownFilter(ctx context.Context, alert
 		queryLimit = domain.BreakdownLimitValue
 	}
 
+	// muted breakdowns are excluded from the query so that they don't take the place of other breakdowns
+	excludedValues = append(excludedValues, alert.MutedBreakdowns...)
+
 	if queryLimit <= 0 {
 		return nil, nil
 	}
@@ -914,5 +962,7 @@ func toWebhookEvent(n *domain.Notification, a *domain.Alert) any {
 		TimeSent:       n.TimeSent,
 		Period:         n.Period,
 		Value:          n.Value,
+		ExpectedValue:  n.ExpectedValue,
+		Deviation:      n.Deviation,
 	}
 }
@@ -449,7 +449,7 @@ func TestAnalyticsAlertsService_checkRowForAlertPercentage(t *testing.T) {
 				},
 			}
 
-			_, err := s.checkRowForAlertPercentage(ctx, tt.args.row, tt.args.alert, tt.args.metricIndex, alertID, &now)
+			_, err := s.checkRowForAlertPercentage(ctx, tt.args.row, tt.args.alert, tt.args.metricIndex, alertID, &now, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AnalyticsAlertsService.checkRowForAlertPercentage() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -516,7 +516,7 @@ func TestAnalyticsAlertsService_checkRowForAlert(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &AnalyticsAlertsService{}
 
-			_, err := s.checkRowForAlertValue(ctx, tt.args.row, tt.args.alert, tt.args.metricIndex, alertID, &now)
+			_, err := s.checkRowForAlertValue(ctx, tt.args.row, tt.args.alert, tt.args.metricIndex, alertID, &now, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AnalyticsAlertsService.checkRowForAlertValue() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1500,6 +1500,22 @@ func TestAnalyticsAlertsService_getTimeSettings(t *testing.T) {
 				IncludeCurrent: true,
 			},
 		},
+		{
+			name: "Condition is anomaly and interval is month",
+			args: args{
+				today: testToday,
+				config: &domain.Config{
+					Condition:    domain.ConditionAnomaly,
+					TimeInterval: report.TimeIntervalMonth,
+					Anomaly:      &domain.AnomalyConfig{Lookback: 6},
+				},
+			},
+			wantTimeSettings: &report.TimeSettings{
+				Mode:   report.TimeSettingsModeLast,
+				Unit:   report.TimeSettingsUnitMonth,
+				Amount: 7,
+			},
+		},
 		{
 			name: "Condition is forecast and interval is year",
 			args: args{
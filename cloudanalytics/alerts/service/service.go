@@ -45,6 +45,7 @@ import (
 	labelsIface "github.com/doitintl/hello/scheduled-tasks/labels/dal/iface"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	"github.com/doitintl/hello/scheduled-tasks/mailer"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
 	userDal "github.com/doitintl/hello/scheduled-tasks/user/dal"
 	"github.com/doitintl/hello/scheduled-tasks/user/dal/iface"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
@@ -69,6 +70,11 @@ var timeIntervalLabelMap = map[report.TimeInterval]string{
 	report.TimeIntervalYear:    "Yearly",
 }
 
+var anomalyMethodLabelMap = map[domain.AnomalyMethod]string{
+	domain.AnomalyMethodZScore: "standard deviations",
+	domain.AnomalyMethodMAD:    "median absolute deviations",
+}
+
 type AnalyticsAlertsService struct {
 	loggerProvider   logger.Provider
 	conn             *connection.Connection
@@ -88,6 +94,7 @@ type AnalyticsAlertsService struct {
 	labelsDal        labelsIface.Labels
 	alertTierService alertTierIface.AlertTierService
 	eventDispatcher  dispatch.Dispatcher
+	msTeams          msteams.Notifier
 }
 
 func NewAnalyticsAlertsService(
@@ -124,7 +131,8 @@ func NewAnalyticsAlertsService(
 		service.NewCAOwnerChecker(conn),
 		labelsDal.NewLabelsFirestoreWithClient(conn.Firestore),
 		alertTierService,
-		dispatch.NewEventDispatcher(loggerProvider, conn.Firestore),
+		dispatch.NewEventDispatcher(loggerProvider, conn),
+		msteams.NewWebhookNotifier(),
 	}, nil
 }
 
@@ -205,11 +213,16 @@ func (s *AnalyticsAlertsService) SendEmailsToCustomer(ctx context.Context, custo
 			continue
 		}
 
-		if err := s.buildAlertEmailBody(ctx, recipientsBodyMap, notifications, alert); err != nil {
+		body, err := s.buildAlertEmailBody(ctx, recipientsBodyMap, notifications, alert)
+		if err != nil {
 			log.Errorf("error building alert email body, alert ID: %s, error: %s", notifications[0].Alert.ID, err)
 			continue
 		}
 
+		if body != nil {
+			s.sendMSTeamsAlert(ctx, customerID, notifications[0].Alert.ID, alert, body)
+		}
+
 		if err = s.alertsDal.UpdateAlertNotified(ctx, notifications[0].Alert.ID); err != nil {
 			log.Errorf("error updating alert notified, alert ID: %s, error: %s", notifications[0].Alert.ID, err)
 			continue
@@ -257,7 +270,7 @@ func (s *AnalyticsAlertsService) DeleteMany(
 	return s.labelsDal.DeleteManyObjectsWithLabels(ctx, alertRefs)
 }
 
-func (s *AnalyticsAlertsService) buildAlertEmailBody(ctx context.Context, recipientsBodyMap RecipientsBodyMap, notifications []*domain.Notification, alert *domain.Alert) error {
+func (s *AnalyticsAlertsService) buildAlertEmailBody(ctx context.Context, recipientsBodyMap RecipientsBodyMap, notifications []*domain.Notification, alert *domain.Alert) (*EmailBody, error) {
 	var validNotification *domain.Notification
 
 	for _, notification := range notifications {
@@ -268,12 +281,12 @@ func (s *AnalyticsAlertsService) buildAlertEmailBody(ctx context.Context, recipi
 	}
 
 	if validNotification == nil {
-		return nil
+		return nil, nil
 	}
 
 	condition, err := s.buildAlertCondition(ctx, alert)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	body := EmailBody{
@@ -283,12 +296,12 @@ func (s *AnalyticsAlertsService) buildAlertEmailBody(ctx context.Context, recipi
 
 	if len(alert.Config.Rows) > 0 {
 		if body.BreakdownLabel, err = s.getBreakdownLabel(alert.Config.Rows); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if err := s.buildBodyNotifications(ctx, notifications, alert, &body); err != nil {
-		return err
+		return nil, err
 	}
 
 	s.createTopHitsText(alert.Config.Operator, &body)
@@ -305,7 +318,7 @@ func (s *AnalyticsAlertsService) buildAlertEmailBody(ctx context.Context, recipi
 		recipientsBodyMap[recipient] = append(recipientsBodyMap[recipient], body)
 	}
 
-	return nil
+	return &body, nil
 }
 
 func (s *AnalyticsAlertsService) addTimestampDataToMap(notificationsData map[string]*TimestampData, timeDetected time.Time, timeInterval report.TimeInterval) *TimestampData {
@@ -424,6 +437,12 @@ func (s *AnalyticsAlertsService) buildAlertCondition(ctx context.Context, alert
 		}
 	}
 
+	if alert.Config.Condition == domain.ConditionAnomaly {
+		anomalyConfig := alert.Config.GetAnomalyConfig()
+		valueString = fmt.Sprintf("%.2f %s over the last %d intervals",
+			alert.Config.Values[0], anomalyMethodLabelMap[anomalyConfig.Method], anomalyConfig.Lookback)
+	}
+
 	stringArr := []string{
 		timeIntervalLabelMap[alert.Config.TimeInterval],
 		metricLabel,
@@ -1491,6 +1491,64 @@ func TestAnalyticsAlertsExternalService_UpdateAlert(t *testing.T) {
 			},
 			want: updatedAlertAPI,
 		},
+		{
+			name: "Invalid maintenance window on update Alert",
+			args: args{
+				ctx:        ctx,
+				customerID: customerID,
+				userID:     userID,
+				email:      customerEmail,
+				alertRequest: &AlertRequest{
+					MaintenanceWindows: []MaintenanceWindowAPI{
+						{Start: alertTime.UnixMilli(), End: alertTime.Add(48 * time.Hour).UnixMilli(), Recurrence: domain.RecurrenceDaily},
+					},
+				},
+			},
+			on: func(f *fields) {
+				f.alertsDal.
+					On("GetAlert", ctx, alertID).
+					Return(currentAlert, nil).
+					Once()
+			},
+			expectedErr: domain.ErrValidationErrors,
+			validationErr: []error{
+				errormsg.ErrorMsg{Field: "maintenanceWindows", Message: ErrInvalidMaintenanceWindow},
+			},
+		},
+		{
+			name: "Successful Alert snooze and mute update",
+			args: args{
+				ctx:        ctx,
+				customerID: customerID,
+				userID:     userID,
+				email:      customerEmail,
+				alertRequest: &AlertRequest{
+					SnoozedUntil:       common.Int64(0),
+					MaintenanceWindows: []MaintenanceWindowAPI{},
+					MutedBreakdowns:    []string{"Compute Engine"},
+				},
+			},
+			on: func(f *fields) {
+				f.alertsDal.
+					On("GetAlert", ctx, alertID).
+					Return(currentAlert, nil).
+					Once()
+
+				f.alertsDal.
+					On("GetAlert", ctx, alertID).
+					Return(updatedAlert, nil).Once()
+
+				f.alertsDal.
+					On("UpdateAlert", ctx, alertID, []firestore.Update{
+						{Path: "snoozedUntil", Value: (*time.Time)(nil)},
+						{Path: "maintenanceWindows", Value: []domain.MaintenanceWindow(nil)},
+						{Path: "mutedBreakdowns", Value: []string{"Compute Engine"}},
+					}).
+					Return(nil).
+					Once()
+			},
+			want: updatedAlertAPI,
+		},
 		{
 			name: "Successful Alert Update",
 			args: args{
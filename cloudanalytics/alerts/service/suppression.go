@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/times"
+)
+
+// notificationSuppressor drops the rows of muted breakdowns and the notifications that repeat
+// a notification detected in one of the previous intervals, according to the dedup policy of the alert
+type notificationSuppressor struct {
+	alert    *domain.Alert
+	detected map[string][]*domain.Notification
+}
+
+// getNotificationSuppressor returns the suppressor of the alert, loading the recently detected
+// notifications of the alert if it has a dedup policy
+func (s *AnalyticsAlertsService) getNotificationSuppressor(ctx context.Context, alert *domain.Alert, alertID string) (*notificationSuppressor, error) {
+	suppressor := &notificationSuppressor{
+		alert:    alert,
+		detected: make(map[string][]*domain.Notification),
+	}
+
+	policy := alert.Config.Dedup
+	if policy == nil || policy.Intervals <= 0 {
+		return suppressor, nil
+	}
+
+	// daily alerts evaluate a few days back, so look back from before the earliest evaluated row
+	since := shiftInterval(times.CurrentDayUTC(), alert.Config.TimeInterval, -(policy.Intervals + PercentageDailyRange))
+
+	notifications, err := s.notificationsDal.GetRecentDetectedNotifications(ctx, alert.Etag, alertID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, notification := range notifications {
+		suppressor.track(notification)
+	}
+
+	return suppressor, nil
+}
+
+// isMuted returns true if the breakdown value of the row is muted
+func (ns *notificationSuppressor) isMuted(row []bigquery.Value) bool {
+	if ns == nil || len(ns.alert.MutedBreakdowns) == 0 || len(ns.alert.Config.Rows) == 0 || len(row) == 0 {
+		return false
+	}
+
+	breakdown, err := query.BigqueryValueToString(row[0])
+	if err != nil {
+		return false
+	}
+
+	return ns.alert.IsBreakdownMuted(breakdown)
+}
+
+// isDuplicate returns true if the breakdown was already detected in one of the previous intervals
+// of the dedup policy, and its value did not change by more than the allowed percentage
+func (ns *notificationSuppressor) isDuplicate(row []bigquery.Value, value float64, rowTimeDetected *time.Time) bool {
+	if ns == nil || ns.alert.Config.Dedup == nil {
+		return false
+	}
+
+	policy := ns.alert.Config.Dedup
+	timeInterval := ns.alert.Config.TimeInterval
+
+	timeDetected := time.Now().UTC()
+	if timeInterval == report.TimeIntervalDay && rowTimeDetected != nil {
+		timeDetected = *rowTimeDetected
+	}
+
+	previousPeriods := make(map[string]bool, policy.Intervals)
+	for i := 1; i <= policy.Intervals; i++ {
+		previousPeriods[getFormattedDate(timeInterval, shiftInterval(timeDetected, timeInterval, -i))] = true
+	}
+
+	for _, previous := range ns.detected[ns.getBreakdownKey(row)] {
+		if !previousPeriods[previous.Period] {
+			continue
+		}
+
+		if policy.MinChangePct == 0 || !isValueChanged(previous.Value, value, policy.MinChangePct) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// track records a detected notification so that repeats of it in the next intervals are suppressed
+func (ns *notificationSuppressor) track(notification *domain.Notification) {
+	if ns == nil || notification == nil {
+		return
+	}
+
+	var key string
+	if notification.Breakdown != nil {
+		key = *notification.Breakdown
+	}
+
+	ns.detected[key] = append(ns.detected[key], notification)
+}
+
+func (ns *notificationSuppressor) getBreakdownKey(row []bigquery.Value) string {
+	if len(ns.alert.Config.Rows) == 0 || ns.alert.Config.Condition == domain.ConditionForecast || len(row) == 0 {
+		return ""
+	}
+
+	breakdown, err := query.BigqueryValueToString(row[0])
+	if err != nil {
+		return ""
+	}
+
+	return breakdown
+}
+
+// isValueChanged returns true if the value changed by more than pct percent from the previous value
+func isValueChanged(previous, value, pct float64) bool {
+	if previous == 0 {
+		return value != 0
+	}
+
+	return math.Abs(value-previous)/math.Abs(previous)*100 > pct
+}
+
+// shiftInterval moves the date by the given number of time intervals
+func shiftInterval(date time.Time, timeInterval report.TimeInterval, n int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+
+	switch timeInterval {
+	case report.TimeIntervalDay:
+		return date.AddDate(0, 0, n)
+	case report.TimeIntervalWeek:
+		return date.AddDate(0, 0, 7*n)
+	case report.TimeIntervalMonth:
+		return firstOfMonth.AddDate(0, n, 0)
+	case report.TimeIntervalQuarter:
+		return firstOfMonth.AddDate(0, 3*n, 0)
+	case report.TimeIntervalYear:
+		return firstOfMonth.AddDate(n, 0, 0)
+	}
+
+	return date
+}
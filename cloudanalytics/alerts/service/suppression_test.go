@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	alertsMock "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+)
+
+func TestAnalyticsAlertsService_checkRowsForAlertValueSuppressed(t *testing.T) {
+	ctx := context.Background()
+
+	qr := &cloudanalytics.QueryRequest{
+		Rows: []*domainQuery.QueryRequestX{{}},
+		Cols: []*domainQuery.QueryRequestX{{Key: "year"}, {Key: "month"}, {Key: "day"}},
+	}
+
+	rows := [][]bigquery.Value{
+		{"A", "2024", "05", "01", float64(150)},
+		// repeats A of the previous day with a small change
+		{"A", "2024", "05", "02", float64(160)},
+		// changed by more than the allowed percentage since the previous day
+		{"B", "2024", "05", "02", float64(150)},
+		// repeats C of the previous day, detected in a previous refresh
+		{"C", "2024", "05", "02", float64(155)},
+		{"muted", "2024", "05", "02", float64(500)},
+		{"D", "2024", "05", "02", float64(50)},
+	}
+
+	b := "B"
+	c := "C"
+
+	newAlert := func() *domain.Alert {
+		return &domain.Alert{
+			Etag:            "etag",
+			MutedBreakdowns: []string{"muted"},
+			Config: &domain.Config{
+				Values:       []float64{100},
+				Operator:     report.MetricFilterGreaterThan,
+				Condition:    domain.ConditionValue,
+				Rows:         []string{"fixed:service_description"},
+				TimeInterval: report.TimeIntervalDay,
+				Dedup:        &domain.DedupPolicy{Intervals: 1, MinChangePct: 20},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		alert          func() *domain.Alert
+		dalErr         error
+		wantBreakdowns []string
+		wantErr        bool
+	}{
+		{
+			name:           "muted and repeated notifications are suppressed",
+			alert:          newAlert,
+			wantBreakdowns: []string{"A", "B"},
+		},
+		{
+			name: "without a dedup policy only muted breakdowns are suppressed",
+			alert: func() *domain.Alert {
+				alert := newAlert()
+				alert.Config.Dedup = nil
+
+				return alert
+			},
+			wantBreakdowns: []string{"A", "A", "B", "C"},
+		},
+		{
+			name:    "error getting the recent notifications",
+			alert:   newAlert,
+			dalErr:  errors.New("error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertsDal := &alertsMock.Alerts{}
+			alertsDal.On("GetRef", mock.Anything, mock.Anything).Return(nil)
+
+			notificationsDal := &alertsMock.Notifications{}
+			notificationsDal.On("GetRecentDetectedNotifications", ctx, "etag", testAlertID, mock.AnythingOfType("time.Time")).
+				Return([]*domain.Notification{
+					{Breakdown: &b, Period: "2024-05-01", Value: 100},
+					{Breakdown: &c, Period: "2024-05-01", Value: 150},
+				}, tt.dalErr)
+
+			s := &AnalyticsAlertsService{
+				alertsDal:        alertsDal,
+				notificationsDal: notificationsDal,
+			}
+
+			alert := tt.alert()
+
+			suppressor, err := s.getNotificationSuppressor(ctx, alert, testAlertID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			notifications := []*domain.Notification{}
+
+			err = s.checkRowsForAlertValue(ctx, alert, rows, 4, testAlertID, &notifications, qr, suppressor)
+			assert.NoError(t, err)
+
+			breakdowns := make([]string, 0, len(notifications))
+			for _, notification := range notifications {
+				breakdowns = append(breakdowns, *notification.Breakdown)
+			}
+
+			assert.ElementsMatch(t, tt.wantBreakdowns, breakdowns)
+		})
+	}
+}
+
+func TestAnalyticsAlertsService_checkRowsForAlertAnomalySuppressed(t *testing.T) {
+	ctx := context.Background()
+
+	qr := &cloudanalytics.QueryRequest{
+		Rows: []*domainQuery.QueryRequestX{{}},
+		Cols: []*domainQuery.QueryRequestX{{Key: "year"}, {Key: "month"}},
+	}
+
+	var rows [][]bigquery.Value
+
+	// every breakdown spikes in the latest month
+	for _, breakdown := range []string{"A", "F", "muted"} {
+		rows = append(rows,
+			[]bigquery.Value{breakdown, "2024", "01", float64(100)},
+			[]bigquery.Value{breakdown, "2024", "02", float64(102)},
+			[]bigquery.Value{breakdown, "2024", "03", float64(98)},
+			[]bigquery.Value{breakdown, "2024", "04", float64(100)},
+			[]bigquery.Value{breakdown, "2024", "05", float64(130)},
+		)
+	}
+
+	a := "A"
+	f := "F"
+	previousMonth := getFormattedDate(report.TimeIntervalMonth, shiftInterval(time.Now().UTC(), report.TimeIntervalMonth, -1))
+
+	newAlert := func() *domain.Alert {
+		return &domain.Alert{
+			Etag:            "etag",
+			MutedBreakdowns: []string{"muted"},
+			Config: &domain.Config{
+				Values:       []float64{3},
+				Operator:     report.MetricFilterGreaterThan,
+				Condition:    domain.ConditionAnomaly,
+				Rows:         []string{"fixed:service_description"},
+				TimeInterval: report.TimeIntervalMonth,
+				Dedup:        &domain.DedupPolicy{Intervals: 1, MinChangePct: 20},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		alert          func() *domain.Alert
+		wantBreakdowns []string
+	}{
+		{
+			name:           "muted and repeated anomalies are suppressed",
+			alert:          newAlert,
+			wantBreakdowns: []string{"F"},
+		},
+		{
+			name: "without a dedup policy only muted breakdowns are suppressed",
+			alert: func() *domain.Alert {
+				alert := newAlert()
+				alert.Config.Dedup = nil
+
+				return alert
+			},
+			wantBreakdowns: []string{"A", "F"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertsDal := &alertsMock.Alerts{}
+			alertsDal.On("GetRef", mock.Anything, mock.Anything).Return(nil)
+
+			notificationsDal := &alertsMock.Notifications{}
+			notificationsDal.On("GetRecentDetectedNotifications", ctx, "etag", testAlertID, mock.AnythingOfType("time.Time")).
+				Return([]*domain.Notification{
+					// A was detected last month with about the same value
+					{Breakdown: &a, Period: previousMonth, Value: 128},
+					// F was detected last month with a much lower value
+					{Breakdown: &f, Period: previousMonth, Value: 50},
+				}, nil)
+
+			s := &AnalyticsAlertsService{
+				alertsDal:        alertsDal,
+				notificationsDal: notificationsDal,
+			}
+
+			alert := tt.alert()
+
+			suppressor, err := s.getNotificationSuppressor(ctx, alert, testAlertID)
+			assert.NoError(t, err)
+
+			notifications := []*domain.Notification{}
+
+			err = s.checkRowsForAlertAnomaly(ctx, alert, rows, 3, testAlertID, &notifications, qr, suppressor)
+			assert.NoError(t, err)
+
+			breakdowns := make([]string, 0, len(notifications))
+			for _, notification := range notifications {
+				breakdowns = append(breakdowns, *notification.Breakdown)
+			}
+
+			assert.ElementsMatch(t, tt.wantBreakdowns, breakdowns)
+		})
+	}
+}
+
+func TestShiftInterval(t *testing.T) {
+	date := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timeInterval report.TimeInterval
+		want         time.Time
+	}{
+		{report.TimeIntervalDay, time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)},
+		{report.TimeIntervalWeek, time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC)},
+		{report.TimeIntervalMonth, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{report.TimeIntervalQuarter, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{report.TimeIntervalYear, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.timeInterval), func(t *testing.T) {
+			assert.Equal(t, tt.want, shiftInterval(date, tt.timeInterval, -1))
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
 	domainExternalReport "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/externalreport"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
 )
 
@@ -50,6 +51,7 @@ type AlertConfigAPI struct {
 	// example: "forecast"
 	// example: "percentage-change"
 	// example: "value"
+	// example: "anomaly"
 	// default: "value"
 	Condition Condition `json:"condition"`
 
@@ -81,7 +83,50 @@ type AlertConfigAPI struct {
 	// default: "billing"
 	DataSource domainExternalReport.ExternalDataSource `json:"dataSource"`
 
+	// The threshold of the condition. For the anomaly condition, this is the sensitivity: the number of
+	// standard deviations (or scaled median absolute deviations) a value must deviate from its baseline.
 	Value float64 `json:"value" binding:"required"`
+
+	// Optional: the anomaly detection settings, used only by the anomaly condition
+	Anomaly *AnomalyConfigAPI `json:"anomaly,omitempty"`
+
+	// Optional: suppress notifications that repeat a notification of the previous intervals
+	Dedup *DedupPolicyAPI `json:"dedup,omitempty"`
+}
+
+type DedupPolicyAPI struct {
+	// The number of previous intervals in which a notification of the same breakdown is considered a repeat
+	// example: 3
+	Intervals int `json:"intervals"`
+
+	// Optional: a repeat is still sent if its value changed by more than this percentage. 0 suppresses all repeats
+	// default: 0
+	MinChangePct float64 `json:"minChangePct"`
+}
+
+type MaintenanceWindowAPI struct {
+	// Start of the first occurrence of the window (in unix milliseconds)
+	Start int64 `json:"start"`
+
+	// End of the first occurrence of the window (in unix milliseconds)
+	End int64 `json:"end"`
+
+	// Optional: repeat the window every day, week or month
+	// example: "weekly"
+	// default: ""
+	Recurrence domain.Recurrence `json:"recurrence"`
+}
+
+type AnomalyConfigAPI struct {
+	// The method used to compute the baseline of each value
+	// example: "zscore"
+	// example: "mad"
+	// default: "zscore"
+	Method domain.AnomalyMethod `json:"method"`
+
+	// The number of past intervals used as the baseline
+	// default: 14
+	Lookback int `json:"lookback"`
 }
 
 type AlertAPI struct {
@@ -106,6 +151,18 @@ type AlertAPI struct {
 	// List of emails that will be notified when the Alert is triggered
 	Recipients []string `json:"recipients"`
 
+	// The Alert is not evaluated until this time (in unix milliseconds)
+	SnoozedUntil *int64 `json:"snoozedUntil"`
+
+	// Periods in which the Alert is not evaluated
+	MaintenanceWindows []MaintenanceWindowAPI `json:"maintenanceWindows"`
+
+	// Values of the evaluateForEach dimension that will not trigger the Alert
+	MutedBreakdowns []string `json:"mutedBreakdowns"`
+
+	// Microsoft Teams channels that will be notified when the Alert is triggered
+	RecipientsMSTeamsChannels []common.MSTeamsChannel `json:"recipientsMSTeamsChannels"`
+
 	Config *AlertConfigAPI `json:"config"`
 }
 
@@ -149,6 +206,14 @@ type AlertRequest struct {
 	Name string `json:"name" binding:"required,lte=64"`
 	// List of people that will be notified when the Alert is triggered
 	Recipients []string `json:"recipients"`
+	// Optional: do not evaluate the Alert until this time (in unix milliseconds). 0 removes the snooze
+	SnoozedUntil *int64 `json:"snoozedUntil"`
+	// Optional: periods in which the Alert is not evaluated. An empty list removes all windows
+	MaintenanceWindows []MaintenanceWindowAPI `json:"maintenanceWindows"`
+	// Optional: values of the evaluateForEach dimension that will not trigger the Alert. An empty list unmutes all values
+	MutedBreakdowns []string `json:"mutedBreakdowns"`
+	// Optional: Microsoft Teams incoming webhooks that will be notified when the Alert is triggered. An empty list removes all channels
+	RecipientsMSTeamsChannels []common.MSTeamsChannel `json:"recipientsMSTeamsChannels"`
 }
 
 type AlertUpdateRequest struct {
@@ -158,6 +223,14 @@ type AlertUpdateRequest struct {
 	Name string `json:"name" binding:"required,lte=64"`
 	// List of people that will be notified when the Alert is triggered
 	Recipients []string `json:"recipients"`
+	// Optional: do not evaluate the Alert until this time (in unix milliseconds). 0 removes the snooze
+	SnoozedUntil *int64 `json:"snoozedUntil"`
+	// Optional: periods in which the Alert is not evaluated. An empty list removes all windows
+	MaintenanceWindows []MaintenanceWindowAPI `json:"maintenanceWindows"`
+	// Optional: values of the evaluateForEach dimension that will not trigger the Alert. An empty list unmutes all values
+	MutedBreakdowns []string `json:"mutedBreakdowns"`
+	// Optional: Microsoft Teams incoming webhooks that will be notified when the Alert is triggered. An empty list removes all channels
+	RecipientsMSTeamsChannels []common.MSTeamsChannel `json:"recipientsMSTeamsChannels"`
 }
 
 type MetricConfig struct {
@@ -202,6 +275,7 @@ const (
 	ConditionForecast   Condition = "forecast"
 	ConditionPercentage Condition = "percentage-change"
 	ConditionValue      Condition = "value"
+	ConditionAnomaly    Condition = "anomaly"
 )
 
 func fromAPICondition(c Condition) domain.Condition {
@@ -212,6 +286,8 @@ func fromAPICondition(c Condition) domain.Condition {
 		return domain.ConditionPercentage
 	case ConditionValue:
 		return domain.ConditionValue
+	case ConditionAnomaly:
+		return domain.ConditionAnomaly
 	default:
 		return ""
 	}
@@ -225,6 +301,8 @@ func toAPICondition(c domain.Condition) Condition {
 		return ConditionPercentage
 	case domain.ConditionValue:
 		return ConditionValue
+	case domain.ConditionAnomaly:
+		return ConditionAnomaly
 	default:
 		return ""
 	}
@@ -286,6 +364,12 @@ const (
 	ErrUnknown                      = "Unknown error"
 	ErrForecastMetadataIncompatible = "Config.condition Forecast does not currently support the evaluateForEach option"
 	ErrInvalidScopeMetadataType     = "Invalid metadata type"
+	ErrInvalidAnomalyLookback       = "Config.anomaly.lookback must be at least 3 intervals"
+	ErrInvalidDedupIntervals        = "Config.dedup.intervals must be between 1 and 30"
+	ErrInvalidMaintenanceWindow     = "Maintenance window must end after it starts, and a recurring window must be shorter than its recurrence"
+	ErrTooManyMaintenanceWindows    = "At most 10 maintenance windows are allowed"
+	ErrTooManyMutedBreakdowns       = "At most 100 muted breakdowns are allowed"
+	ErrInvalidMSTeamsWebhookURL     = "Microsoft Teams channel webhook url must be an https incoming webhook url"
 )
 
 const rootOrgID = "root"
@@ -299,4 +383,6 @@ type WebhookAlertNotification struct {
 	TimeSent       *time.Time `json:"timeSent"`
 	Period         string     `json:"period"`
 	Value          float64    `json:"value"`
+	ExpectedValue  *float64   `json:"expectedValue,omitempty"`
+	Deviation      *float64   `json:"deviation,omitempty"`
 }
@@ -57,13 +57,34 @@ func toAlertAPI(alert *domain.Alert) (*AlertAPI, error) {
 		lastAlerted = &time
 	}
 
+	var snoozedUntil *int64
+
+	if alert.SnoozedUntil != nil {
+		time := alert.SnoozedUntil.UnixMilli()
+		snoozedUntil = &time
+	}
+
+	var maintenanceWindows []MaintenanceWindowAPI
+
+	for _, window := range alert.MaintenanceWindows {
+		maintenanceWindows = append(maintenanceWindows, MaintenanceWindowAPI{
+			Start:      window.Start.UnixMilli(),
+			End:        window.End.UnixMilli(),
+			Recurrence: window.Recurrence,
+		})
+	}
+
 	alertAPI := &AlertAPI{
-		ID:          alert.ID,
-		Name:        alert.Name,
-		CreateTime:  alert.TimeCreated.UnixMilli(),
-		UpdateTime:  alert.TimeModified.UnixMilli(),
-		LastAlerted: lastAlerted,
-		Recipients:  alert.Recipients,
+		ID:                        alert.ID,
+		Name:                      alert.Name,
+		CreateTime:                alert.TimeCreated.UnixMilli(),
+		UpdateTime:                alert.TimeModified.UnixMilli(),
+		LastAlerted:               lastAlerted,
+		Recipients:                alert.Recipients,
+		SnoozedUntil:              snoozedUntil,
+		MaintenanceWindows:        maintenanceWindows,
+		MutedBreakdowns:           alert.MutedBreakdowns,
+		RecipientsMSTeamsChannels: alert.RecipientsMSTeamsChannels,
 	}
 
 	if alert.Config == nil {
@@ -124,6 +145,22 @@ func toAlertAPI(alert *domain.Alert) (*AlertAPI, error) {
 		return nil, dataSourceValidationErrors[0]
 	}
 
+	var anomaly *AnomalyConfigAPI
+	if alert.Config.Anomaly != nil {
+		anomaly = &AnomalyConfigAPI{
+			Method:   alert.Config.Anomaly.Method,
+			Lookback: alert.Config.Anomaly.Lookback,
+		}
+	}
+
+	var dedup *DedupPolicyAPI
+	if alert.Config.Dedup != nil {
+		dedup = &DedupPolicyAPI{
+			Intervals:    alert.Config.Dedup.Intervals,
+			MinChangePct: alert.Config.Dedup.MinChangePct,
+		}
+	}
+
 	alertAPI.Config = &AlertConfigAPI{
 		Attributions:    attributions,
 		Metric:          metricConfig,
@@ -135,6 +172,8 @@ func toAlertAPI(alert *domain.Alert) (*AlertAPI, error) {
 		EvaluateForEach: evaluateForEach,
 		Scopes:          scopes,
 		DataSource:      *dataSource,
+		Anomaly:         anomaly,
+		Dedup:           dedup,
 	}
 
 	return alertAPI, nil
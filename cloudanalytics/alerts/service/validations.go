@@ -23,6 +23,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
 )
 
 const scopeMaxItems = 26
@@ -68,6 +69,47 @@ func (s *AnalyticsAlertsService) validateCreateAlertRequest(ctx context.Context,
 		errs = append(errs, errormsg.ErrorMsg{Field: "config.condition", Message: ErrInvalidValue})
 	}
 
+	if alertRequest.Config.Anomaly != nil {
+		anomalyConfig, err := validateAnomaly(alertRequest.Config.Anomaly)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		validatedAlert.Config.Anomaly = anomalyConfig
+	}
+
+	if alertRequest.Config.Dedup != nil {
+		dedup, err := validateDedup(alertRequest.Config.Dedup)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		validatedAlert.Config.Dedup = dedup
+	}
+
+	if alertRequest.SnoozedUntil != nil {
+		validatedAlert.SnoozedUntil = toSnoozedUntil(*alertRequest.SnoozedUntil)
+	}
+
+	maintenanceWindows, err := validateMaintenanceWindows(alertRequest.MaintenanceWindows)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	validatedAlert.MaintenanceWindows = maintenanceWindows
+
+	if err := validateMutedBreakdowns(alertRequest.MutedBreakdowns); err != nil {
+		errs = append(errs, err)
+	}
+
+	validatedAlert.MutedBreakdowns = alertRequest.MutedBreakdowns
+
+	if err := validateMSTeamsChannels(alertRequest.RecipientsMSTeamsChannels); err != nil {
+		errs = append(errs, err)
+	}
+
+	validatedAlert.RecipientsMSTeamsChannels = alertRequest.RecipientsMSTeamsChannels
+
 	if validatedAlert.Config.Currency == "" {
 		customer, _ := s.customersDAL.GetCustomer(ctx, args.CustomerID)
 		validatedAlert.Config.Currency = fixer.Currency(common.GetCustomerCurrency(customer))
@@ -157,6 +199,35 @@ func (s *AnalyticsAlertsService) validateUpdateAlertRequest(ctx context.Context,
 		addUpdate("recipients", alertRequest.Recipients, &updates, errs)
 	}
 
+	if alertRequest.SnoozedUntil != nil {
+		addUpdate("snoozedUntil", toSnoozedUntil(*alertRequest.SnoozedUntil), &updates, errs)
+	}
+
+	if alertRequest.MaintenanceWindows != nil {
+		maintenanceWindows, err := validateMaintenanceWindows(alertRequest.MaintenanceWindows)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		addUpdate("maintenanceWindows", maintenanceWindows, &updates, errs)
+	}
+
+	if alertRequest.MutedBreakdowns != nil {
+		if err := validateMutedBreakdowns(alertRequest.MutedBreakdowns); err != nil {
+			errs = append(errs, err)
+		}
+
+		addUpdate("mutedBreakdowns", alertRequest.MutedBreakdowns, &updates, errs)
+	}
+
+	if alertRequest.RecipientsMSTeamsChannels != nil {
+		if err := validateMSTeamsChannels(alertRequest.RecipientsMSTeamsChannels); err != nil {
+			errs = append(errs, err)
+		}
+
+		addUpdate("recipientsMSTeamsChannels", alertRequest.RecipientsMSTeamsChannels, &updates, errs)
+	}
+
 	if alertRequest.Config == nil || reflect.DeepEqual(*alertRequest.Config, AlertConfigAPI{}) {
 		return updates, errs
 	}
@@ -225,6 +296,24 @@ func (s *AnalyticsAlertsService) validateUpdateAlertRequest(ctx context.Context,
 		addUpdate("config.values", []float64{alertRequest.Config.Value}, &updates, errs)
 	}
 
+	if alertRequest.Config.Anomaly != nil {
+		anomalyConfig, err := validateAnomaly(alertRequest.Config.Anomaly)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		addUpdate("config.anomaly", anomalyConfig, &updates, errs)
+	}
+
+	if alertRequest.Config.Dedup != nil {
+		dedup, err := validateDedup(alertRequest.Config.Dedup)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		addUpdate("config.dedup", dedup, &updates, errs)
+	}
+
 	if alertRequest.Config.Attributions != nil && len(alertRequest.Config.Attributions) > 0 {
 		attributions, err := s.validateAttributions(ctx, args.CustomerID, alertRequest)
 		if err != nil {
@@ -262,6 +351,112 @@ func (s *AnalyticsAlertsService) validateUpdateAlertRequest(ctx context.Context,
 	return updates, errs
 }
 
+func validateAnomaly(anomaly *AnomalyConfigAPI) (*domain.AnomalyConfig, error) {
+	switch anomaly.Method {
+	case "", domain.AnomalyMethodZScore, domain.AnomalyMethodMAD:
+	default:
+		return nil, errormsg.ErrorMsg{Field: "config.anomaly.method", Message: ErrInvalidValue}
+	}
+
+	if anomaly.Lookback != 0 && anomaly.Lookback < domain.MinAnomalyBaseline {
+		return nil, errormsg.ErrorMsg{Field: "config.anomaly.lookback", Message: ErrInvalidAnomalyLookback}
+	}
+
+	return &domain.AnomalyConfig{
+		Method:   anomaly.Method,
+		Lookback: anomaly.Lookback,
+	}, nil
+}
+
+func validateDedup(dedup *DedupPolicyAPI) (*domain.DedupPolicy, error) {
+	if dedup.Intervals < 1 || dedup.Intervals > domain.MaxDedupIntervals {
+		return nil, errormsg.ErrorMsg{Field: "config.dedup.intervals", Message: ErrInvalidDedupIntervals}
+	}
+
+	if dedup.MinChangePct < 0 {
+		return nil, errormsg.ErrorMsg{Field: "config.dedup.minChangePct", Message: ErrInvalidValue}
+	}
+
+	return &domain.DedupPolicy{
+		Intervals:    dedup.Intervals,
+		MinChangePct: dedup.MinChangePct,
+	}, nil
+}
+
+// toSnoozedUntil converts the snooze time of the request, where 0 removes the snooze
+func toSnoozedUntil(snoozedUntil int64) *time.Time {
+	if snoozedUntil <= 0 {
+		return nil
+	}
+
+	t := time.UnixMilli(snoozedUntil).UTC()
+
+	return &t
+}
+
+func validateMaintenanceWindows(windows []MaintenanceWindowAPI) ([]domain.MaintenanceWindow, error) {
+	fieldName := "maintenanceWindows"
+
+	if len(windows) > domain.MaxMaintenanceWindows {
+		return nil, errormsg.ErrorMsg{Field: fieldName, Message: ErrTooManyMaintenanceWindows}
+	}
+
+	var maintenanceWindows []domain.MaintenanceWindow
+
+	for _, window := range windows {
+		var maxDuration time.Duration
+
+		switch window.Recurrence {
+		case domain.RecurrenceNone:
+		case domain.RecurrenceDaily:
+			maxDuration = 24 * time.Hour
+		case domain.RecurrenceWeekly:
+			maxDuration = 7 * 24 * time.Hour
+		case domain.RecurrenceMonthly:
+			maxDuration = 28 * 24 * time.Hour
+		default:
+			return nil, errormsg.ErrorMsg{Field: fieldName + ".recurrence", Message: ErrInvalidValue}
+		}
+
+		duration := time.Duration(window.End-window.Start) * time.Millisecond
+		if window.Start <= 0 || duration <= 0 || (maxDuration > 0 && duration >= maxDuration) {
+			return nil, errormsg.ErrorMsg{Field: fieldName, Message: ErrInvalidMaintenanceWindow}
+		}
+
+		maintenanceWindows = append(maintenanceWindows, domain.MaintenanceWindow{
+			Start:      time.UnixMilli(window.Start).UTC(),
+			End:        time.UnixMilli(window.End).UTC(),
+			Recurrence: window.Recurrence,
+		})
+	}
+
+	return maintenanceWindows, nil
+}
+
+func validateMutedBreakdowns(breakdowns []string) error {
+	if len(breakdowns) > domain.MaxMutedBreakdowns {
+		return errormsg.ErrorMsg{Field: "mutedBreakdowns", Message: ErrTooManyMutedBreakdowns}
+	}
+
+	for _, breakdown := range breakdowns {
+		if breakdown == "" {
+			return errormsg.ErrorMsg{Field: "mutedBreakdowns", Message: ErrInvalidValue}
+		}
+	}
+
+	return nil
+}
+
+func validateMSTeamsChannels(channels []common.MSTeamsChannel) error {
+	for _, channel := range channels {
+		if err := msteams.ValidateWebhookURL(channel.WebhookURL); err != nil {
+			return errormsg.ErrorMsg{Field: "recipientsMSTeamsChannels", Message: ErrInvalidMSTeamsWebhookURL}
+		}
+	}
+
+	return nil
+}
+
 func addUpdate(path string, value interface{}, updates *[]firestore.Update, errs []error) {
 	if len(errs) == 0 {
 		*updates = append(*updates, firestore.Update{
@@ -169,12 +169,12 @@ func (h *AnalyticsAttributionGroups) createAttributionGroup(
 
 		_, err := h.attributionService.UpdateAttributions(ctx, customerID, attributionsToUpdate, ctx.GetString(common.CtxKeys.UserID))
 		if err != nil {
-			switch err {
-			case attributionsService.ErrForbidden:
+			switch {
+			case errors.Is(err, attributionsService.ErrForbidden):
 				return web.NewRequestError(err, http.StatusForbidden)
-			case attributionsService.ErrNotFound:
+			case errors.Is(err, attributionsService.ErrNotFound):
 				return web.NewRequestError(err, http.StatusNotFound)
-			case query.ErrInvalidChars:
+			case errors.Is(err, query.ErrInvalidFormula):
 				return web.NewRequestError(err, http.StatusBadRequest)
 			default:
 				return web.NewRequestError(err, http.StatusInternalServerError)
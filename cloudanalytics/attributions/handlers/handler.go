@@ -197,12 +197,12 @@ func (h *Attributions) UpdateAttribution(ctx *gin.Context, customerID string) er
 		UserID:      userID,
 	})
 	if err != nil {
-		switch err {
-		case service.ErrForbidden:
+		switch {
+		case errors.Is(err, service.ErrForbidden):
 			return web.NewRequestError(err, http.StatusForbidden)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			return web.NewRequestError(err, http.StatusNotFound)
-		case query.ErrInvalidChars:
+		case errors.Is(err, query.ErrInvalidFormula):
 			return web.NewRequestError(err, http.StatusBadRequest)
 		default:
 			return web.NewRequestError(err, http.StatusInternalServerError)
@@ -265,12 +265,12 @@ func (h *Attributions) UpdateAttributionInternal(ctx *gin.Context, customerID st
 		UserID:      userID,
 	})
 	if err != nil {
-		switch err {
-		case service.ErrForbidden:
+		switch {
+		case errors.Is(err, service.ErrForbidden):
 			return web.NewRequestError(err, http.StatusForbidden)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			return web.NewRequestError(err, http.StatusNotFound)
-		case query.ErrInvalidChars:
+		case errors.Is(err, query.ErrInvalidFormula):
 			return web.NewRequestError(err, http.StatusBadRequest)
 		default:
 			return web.NewRequestError(err, http.StatusInternalServerError)
@@ -312,11 +312,13 @@ func (h *Attributions) CreateAttribution(ctx *gin.Context, customerID string) er
 	})
 
 	if err != nil {
-		switch err {
-		case service.ErrForbidden:
+		switch {
+		case errors.Is(err, service.ErrForbidden):
 			return web.NewRequestError(err, http.StatusForbidden)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			return web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, query.ErrInvalidFormula):
+			return web.NewRequestError(err, http.StatusBadRequest)
 		default:
 			return web.NewRequestError(err, http.StatusInternalServerError)
 		}
@@ -367,11 +369,13 @@ func (h *Attributions) CreateAttributionInternal(ctx *gin.Context, customerID st
 	})
 
 	if err != nil {
-		switch err {
-		case service.ErrForbidden:
+		switch {
+		case errors.Is(err, service.ErrForbidden):
 			return web.NewRequestError(err, http.StatusForbidden)
-		case service.ErrNotFound:
+		case errors.Is(err, service.ErrNotFound):
 			return web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, query.ErrInvalidFormula):
+			return web.NewRequestError(err, http.StatusBadRequest)
 		default:
 			return web.NewRequestError(err, http.StatusInternalServerError)
 		}
@@ -32,7 +32,6 @@ import (
 	metricsDal "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/dal"
 	metricsDalIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/dal/iface"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query"
-	domainOrigin "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain/origin"
 	attributionsQueryIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/iface"
 	reportsDal "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal"
 	reportsIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/iface"
@@ -184,13 +183,6 @@ func (s *AttributionsService) ListAttributions(ctx context.Context, req *custome
 }
 
 func (s *AttributionsService) CreateAttribution(ctx context.Context, req *CreateAttributionRequest) (*attribution.AttributionAPI, error) {
-	l := s.loggerProvider(ctx)
-
-	bq, ok := domainOrigin.Bigquery(ctx, s.conn)
-	if !ok {
-		l.Warningf("no bq client found for origin, using default")
-	}
-
 	// validate user if not doitEmployee
 	isDoitEmployee, ok := ctx.Value(common.DoitEmployee).(bool)
 	if ok && !isDoitEmployee {
@@ -209,11 +201,14 @@ func (s *AttributionsService) CreateAttribution(ctx context.Context, req *Create
 		return nil, err
 	}
 
-	// validate formula
-	if err := s.attributionQuery.ValidateFormula(ctx, bq, len(req.Attribution.Filters), req.Attribution.Formula); err != nil {
+	// validate and normalize formula
+	formula, err := s.attributionQuery.NormalizeFormula(ctx, len(req.Attribution.Filters), req.Attribution.Formula)
+	if err != nil {
 		return nil, err
 	}
 
+	req.Attribution.Formula = formula
+
 	// Add customer ref
 	customer, err := s.customerDal.GetCustomer(ctx, req.CustomerID)
 	if err != nil {
@@ -668,22 +663,26 @@ func (s *AttributionsService) getAttributionUpdates(ctx context.Context, att *at
 	return updates, nil
 }
 
+// validateFormula validates the formula against the filters the attribution will have after the update,
+// and normalizes the updated formula
 func (s *AttributionsService) validateFormula(ctx context.Context, att *attribution.Attribution, currentAttribution *attribution.Attribution) error {
-	bq, ok := domainOrigin.Bigquery(ctx, s.conn)
-	if !ok {
-		s.loggerProvider(ctx).Warningf("no bq client found for origin. Using default")
+	if att.Formula == "" {
+		return s.attributionQuery.ValidateFormula(ctx, len(att.Filters), currentAttribution.Formula)
 	}
 
-	var err error
-	if att.Formula == "" {
-		err = s.attributionQuery.ValidateFormula(ctx, bq, len(att.Filters), currentAttribution.Formula)
-	} else if att.Filters == nil {
-		err = s.attributionQuery.ValidateFormula(ctx, bq, len(currentAttribution.Filters), att.Formula)
-	} else {
-		err = s.attributionQuery.ValidateFormula(ctx, bq, len(att.Filters), att.Formula)
+	variablesLength := len(att.Filters)
+	if att.Filters == nil {
+		variablesLength = len(currentAttribution.Filters)
 	}
 
-	return err
+	formula, err := s.attributionQuery.NormalizeFormula(ctx, variablesLength, att.Formula)
+	if err != nil {
+		return err
+	}
+
+	att.Formula = formula
+
+	return nil
 }
 
 func (s *AttributionsService) HandleErrors(err error) error {
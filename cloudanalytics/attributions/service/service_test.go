@@ -284,10 +284,12 @@ func TestAttributionService_CreateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, 1, "").Return("A", nil)
 				f.customersDal.On("GetCustomer", ctx, customerID).Return(customer, nil)
 				f.customersDal.On("GetCustomerOrPresentationModeCustomer", ctx, customerID).Return(customer, nil)
-				f.dal.On("CreateAttribution", ctx, mock.AnythingOfType("*attribution.Attribution")).Return(attributionSuccessResponse, nil)
+				f.dal.On("CreateAttribution", ctx, mock.MatchedBy(func(a *attribution.Attribution) bool {
+					return a.Formula == "A"
+				})).Return(attributionSuccessResponse, nil)
 			},
 			expectedErr: nil,
 		},
@@ -398,7 +400,7 @@ func TestAttributionService_CreateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(errors.New("invalid formula"))
+				f.attributionsQuery.On("NormalizeFormula", ctx, mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return("", errors.New("invalid formula"))
 			},
 			expectedErr: errors.New("invalid formula"),
 		},
@@ -421,7 +423,7 @@ func TestAttributionService_CreateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return("A", nil)
 				f.customersDal.On("GetCustomer", ctx, customerID).Return(nil, errors.New("get customer error"))
 			},
 			expectedErr: errors.New("get customer error"),
@@ -445,7 +447,7 @@ func TestAttributionService_CreateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return("A", nil)
 				f.customersDal.On("GetCustomer", ctx, customerID).Return(customer, nil)
 				f.dal.On("CreateAttribution", ctx, mock.AnythingOfType("*attribution.Attribution")).Return(nil, errors.New("attributionsDal error"))
 			},
@@ -774,7 +776,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 1, completeAttributionRequest.request.Attribution.Formula).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, 1, completeAttributionRequest.request.Attribution.Formula).Return(completeAttributionRequest.request.Attribution.Formula, nil)
 				f.dal.On("UpdateAttribution", ctx, attributionID, completeAttributionRequest.firestoreUpdates).Return(nil)
 			},
 			expectedErr: nil,
@@ -792,7 +794,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					Customer: &firestore.DocumentRef{},
 				}, nil)
 				f.metadataService.On("ExternalAPIList", mock.Anything).Return(nil, errors.New("ExternalAPIList shouldn't be called"))
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.Anything, mock.Anything).Return(errors.New("Validate formula shouldn't be called"))
+				f.attributionsQuery.On("ValidateFormula", ctx, mock.Anything, mock.Anything).Return(errors.New("Validate formula shouldn't be called"))
 				f.dal.On("UpdateAttribution", ctx, attributionID, onlyNameRequest.firestoreUpdates).Return(nil)
 			},
 			expectedErr: nil,
@@ -810,7 +812,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					Customer: &firestore.DocumentRef{},
 				}, nil)
 				f.metadataService.On("ExternalAPIList", mock.Anything).Return(nil, errors.New("ExternalAPIList shouldn't be called"))
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.Anything, mock.Anything).Return(errors.New("Validate formula shouldn't be called"))
+				f.attributionsQuery.On("ValidateFormula", ctx, mock.Anything, mock.Anything).Return(errors.New("Validate formula shouldn't be called"))
 				f.dal.On("UpdateAttribution", ctx, attributionID, onlyDescriptionRequest.firestoreUpdates).Return(nil)
 			},
 			expectedErr: nil,
@@ -830,7 +832,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					Customer: &firestore.DocumentRef{},
 				}, nil)
 				f.metadataService.On("ExternalAPIList", mock.Anything).Return(nil, errors.New("ExternalAPIList shouldn't be called"))
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 2, onlyFormulaRequest.request.Attribution.Formula).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, 2, onlyFormulaRequest.request.Attribution.Formula).Return("A AND B", nil)
 				f.dal.On("UpdateAttribution", ctx, attributionID, onlyFormulaRequest.firestoreUpdates).Return(nil)
 			},
 			expectedErr: nil,
@@ -858,7 +860,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 1, "other_formula").Return(nil)
+				f.attributionsQuery.On("ValidateFormula", ctx, 1, "other_formula").Return(nil)
 				f.dal.On("UpdateAttribution", ctx, attributionID, onlyFiltersRequest.firestoreUpdates).Return(nil)
 			},
 			expectedErr: nil,
@@ -901,7 +903,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 				f.dal.On("GetAttribution", ctx, attributionID).Return(&attribution.Attribution{
 					Customer: &firestore.DocumentRef{},
 				}, nil)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 1, completeAttributionRequest.request.Attribution.Formula).Return(errors.New("invalid formula"))
+				f.attributionsQuery.On("NormalizeFormula", ctx, 1, completeAttributionRequest.request.Attribution.Formula).Return("", errors.New("invalid formula"))
 			},
 			expectedErr: errors.New("invalid formula"),
 		},
@@ -926,7 +928,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 1, completeAttributionRequest.request.Attribution.Formula).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, 1, completeAttributionRequest.request.Attribution.Formula).Return(completeAttributionRequest.request.Attribution.Formula, nil)
 			},
 			expectedErr: errors.New("filter 1 is not valid"),
 		},
@@ -951,7 +953,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 1, completeAttributionRequest.request.Attribution.Formula).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, 1, completeAttributionRequest.request.Attribution.Formula).Return(completeAttributionRequest.request.Attribution.Formula, nil)
 			},
 			expectedErr: errors.New("filter 1 is not valid"),
 		},
@@ -976,7 +978,7 @@ func TestAttributionService_UpdateAttribution(t *testing.T) {
 					},
 						nil,
 					)
-				f.attributionsQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), 1, completeAttributionRequest.request.Attribution.Formula).Return(nil)
+				f.attributionsQuery.On("NormalizeFormula", ctx, 1, completeAttributionRequest.request.Attribution.Formula).Return(completeAttributionRequest.request.Attribution.Formula, nil)
 				f.dal.On("UpdateAttribution", ctx, attributionID, completeAttributionRequest.firestoreUpdates).Return(errors.New("update attribution dal error"))
 			},
 			expectedErr: errors.New("update attribution dal error"),
@@ -1411,11 +1413,11 @@ func setupUpdateAttributionData() (UdateAttributionTestData, UdateAttributionTes
 				UserID:     userID,
 				Attribution: attribution.Attribution{
 					ID:      attributionID,
-					Formula: "test_formula",
+					Formula: "(A) AND (B)",
 				},
 			},
 			[]firestore.Update{
-				{Path: "formula", Value: "test_formula"},
+				{Path: "formula", Value: "A AND B"},
 			},
 		}
 
@@ -21,20 +21,21 @@ const (
 
 type Budget struct {
 	collab.Access
-	Recipients              []string                 `json:"recipients" firestore:"recipients"`
-	RecipientsSlackChannels []common.SlackChannel    `json:"recipientsSlackChannels" firestore:"recipientsSlackChannels"`
-	Customer                *firestore.DocumentRef   `json:"customer" firestore:"customer"`
-	Description             string                   `json:"description" firestore:"description"`
-	Name                    string                   `json:"name" firestore:"name"`
-	TimeCreated             time.Time                `json:"timeCreated" firestore:"timeCreated"`
-	TimeModified            time.Time                `json:"timeModified" firestore:"timeModified"`
-	TimeRefreshed           time.Time                `json:"timeRefreshed" firestore:"timeRefreshed"`
-	Config                  *BudgetConfig            `json:"config" firestore:"config"`
-	ID                      string                   `json:"id" firestore:"-"`
-	Utilization             BudgetUtilization        `json:"utilization" firestore:"utilization"`
-	IsValid                 bool                     `json:"isValid" firestore:"isValid"`
-	Draft                   bool                     `json:"-" firestore:"draft"`
-	Labels                  []*firestore.DocumentRef `json:"labels" firestore:"labels"`
+	Recipients                []string                 `json:"recipients" firestore:"recipients"`
+	RecipientsSlackChannels   []common.SlackChannel    `json:"recipientsSlackChannels" firestore:"recipientsSlackChannels"`
+	RecipientsMSTeamsChannels []common.MSTeamsChannel  `json:"recipientsMSTeamsChannels" firestore:"recipientsMSTeamsChannels"`
+	Customer                  *firestore.DocumentRef   `json:"customer" firestore:"customer"`
+	Description               string                   `json:"description" firestore:"description"`
+	Name                      string                   `json:"name" firestore:"name"`
+	TimeCreated               time.Time                `json:"timeCreated" firestore:"timeCreated"`
+	TimeModified              time.Time                `json:"timeModified" firestore:"timeModified"`
+	TimeRefreshed             time.Time                `json:"timeRefreshed" firestore:"timeRefreshed"`
+	Config                    *BudgetConfig            `json:"config" firestore:"config"`
+	ID                        string                   `json:"id" firestore:"-"`
+	Utilization               BudgetUtilization        `json:"utilization" firestore:"utilization"`
+	IsValid                   bool                     `json:"isValid" firestore:"isValid"`
+	Draft                     bool                     `json:"-" firestore:"draft"`
+	Labels                    []*firestore.DocumentRef `json:"labels" firestore:"labels"`
 }
 
 type BudgetAlert struct {
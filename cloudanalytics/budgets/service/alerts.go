@@ -17,6 +17,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	fb "github.com/doitintl/hello/scheduled-tasks/firebase"
 	"github.com/doitintl/hello/scheduled-tasks/mailer"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
 	events "github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
@@ -26,6 +27,14 @@ type BudgetSlackAlert struct {
 	SlackBlocks []slackgo.Block
 }
 
+// BudgetMSTeamsAlert is a budget alert card, the forecast chart image is added by the caller
+type BudgetMSTeamsAlert struct {
+	BudgetID   string
+	CustomerID string
+	Card       *msteams.AdaptiveCard
+	Channels   []common.MSTeamsChannel
+}
+
 const (
 	datePattern string = "Mon, 02 Jan 2006"
 )
@@ -46,6 +55,7 @@ const (
 	TextCurrentSpend          string = "Current Spend"
 	TextForecastedSpend       string = "Forecasted Spend"
 	TextBudgetButton          string = ":mag: Open Budget"
+	TextOpenBudget            string = "Open Budget"
 	TextCurrent               string = "Current"
 	TextForecasted            string = "Forecasted"
 	TextForecastedTotalAmount string = "Based on your current spending patterns, we estimate that you’ll reach 100%% of this budget on *%s*"
@@ -57,6 +67,11 @@ const (
 	EventBudgetInvestigate    string = "slack.budget.investigate"
 )
 
+// Microsoft Teams card texts
+const (
+	TextMSTeamsForecastedTotalAmount string = "Based on your current spending patterns, we estimate that you’ll reach 100%% of this budget on **%s**"
+)
+
 func getAlertData(b *budget.Budget) *budget.BudgetAlert {
 	selectedAlert := &b.Config.Alerts[0]
 	for i, alert := range b.Config.Alerts {
@@ -79,10 +94,10 @@ func isPendingAlertBudget(b *budget.Budget) bool {
 	return false
 }
 
-func (s *BudgetsService) TriggerBudgetsAlerts(ctx context.Context) (map[*BudgetSlackAlert][]common.SlackChannel, error) {
+func (s *BudgetsService) TriggerBudgetsAlerts(ctx context.Context) (map[*BudgetSlackAlert][]common.SlackChannel, []*BudgetMSTeamsAlert, error) {
 	budgets, err := s.getAllBudgets(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	return s.processThresholdAlerts(ctx, budgets)
@@ -116,32 +131,32 @@ func (s *BudgetsService) processForecastedDateAlerts(ctx context.Context, budget
 	return nil
 }
 
-func (s *BudgetsService) processThresholdAlerts(ctx context.Context, budgets []*budget.Budget) (map[*BudgetSlackAlert][]common.SlackChannel, error) {
+func (s *BudgetsService) processThresholdAlerts(ctx context.Context, budgets []*budget.Budget) (map[*BudgetSlackAlert][]common.SlackChannel, []*BudgetMSTeamsAlert, error) {
 	l := s.loggerProvider(ctx)
 
 	budgetsWithAlerts, err := s.getBudgetsWithPendingAlerts(ctx, budgets)
 	if err != nil {
 		l.Errorf("Error getting budgets with pending alerts. error [%s]", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := s.resetTriggeredAlerts(ctx, budgets); err != nil {
 		l.Errorf("Error resetting triggered alerts. error [%s]", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(budgetsWithAlerts) > 0 {
-		slackPersonalizations, err := s.sendThresholdAlerts(ctx, budgetsWithAlerts) //	send email alerts & return slack alerts
+		slackPersonalizations, msTeamsAlerts, err := s.sendThresholdAlerts(ctx, budgetsWithAlerts) //	send email alerts & return slack and teams alerts
 		if err != nil {
 			l.Errorf("Error sending threshold alerts. error [%s]", err)
-			return nil, err
+			return nil, nil, err
 		}
 
-		// return slack alerts to be sent by slack service (analytics handler)
-		return slackPersonalizations, nil
+		// return slack and teams alerts to be sent with the forecast chart image (analytics handler)
+		return slackPersonalizations, msTeamsAlerts, nil
 	}
 
-	return nil, nil
+	return nil, nil, nil
 }
 
 func (s *BudgetsService) getAllBudgets(ctx context.Context) ([]*budget.Budget, error) {
@@ -287,6 +302,14 @@ func (s *BudgetsService) sendForecastedDateAlerts(ctx context.Context, budgets [
 			l.Warningf("unable to save notification for budget %s: %s", b.ID, err)
 		}
 
+		if err := s.dispatchBudgetForecastedDate(ctx, b); err != nil {
+			l.Warningf("unable to dispatch %s for event %s: %s",
+				b.ID,
+				events.BudgetForecastedDateChanged,
+				err,
+			)
+		}
+
 		personalizations, err := s.getForecastedDateAlertPersonalizations(ctx, b)
 		if err != nil {
 			l.Errorf("failed to get personalizations for budget %s with error: %s", b.ID, err)
@@ -307,10 +330,11 @@ func (s *BudgetsService) sendForecastedDateAlerts(ctx context.Context, budgets [
 	return nil
 }
 
-// sendThresholdAlerts sends alert emails, dispatches alert events, saves notifications, and returns slack messages
-func (s *BudgetsService) sendThresholdAlerts(ctx context.Context, budgets []*budget.Budget) (map[*BudgetSlackAlert][]common.SlackChannel, error) {
+// sendThresholdAlerts sends alert emails, dispatches alert events, saves notifications, and returns slack and teams messages
+func (s *BudgetsService) sendThresholdAlerts(ctx context.Context, budgets []*budget.Budget) (map[*BudgetSlackAlert][]common.SlackChannel, []*BudgetMSTeamsAlert, error) {
 	l := s.loggerProvider(ctx)
 	slackPersonalizations := make(map[*BudgetSlackAlert][]common.SlackChannel, 0)
+	msTeamsAlerts := make([]*BudgetMSTeamsAlert, 0)
 
 	for _, b := range budgets {
 		err := s.handleEmailAlert(ctx, b)
@@ -340,9 +364,24 @@ func (s *BudgetsService) sendThresholdAlerts(ctx context.Context, budgets []*bud
 			slackAlert := &BudgetSlackAlert{BudgetID: b.ID, SlackBlocks: blocks}
 			slackPersonalizations[slackAlert] = channels
 		}
+
+		card, msTeamsChannels, err := s.getMSTeamsPersonalizations(ctx, b)
+		if err != nil {
+			l.Errorf("failed to get Microsoft Teams personalizations for budget %s with error: %s", b.ID, err)
+			continue
+		}
+
+		if len(msTeamsChannels) > 0 {
+			msTeamsAlerts = append(msTeamsAlerts, &BudgetMSTeamsAlert{
+				BudgetID:   b.ID,
+				CustomerID: b.Customer.ID,
+				Card:       card,
+				Channels:   msTeamsChannels,
+			})
+		}
 	}
 
-	return slackPersonalizations, nil
+	return slackPersonalizations, msTeamsAlerts, nil
 }
 
 func (s *BudgetsService) handleEmailAlert(ctx context.Context, budget *budget.Budget) error {
@@ -631,6 +670,88 @@ func (s *BudgetsService) GetSlackFinalBlocks(ctx context.Context, imageURLForeca
 	return slackgo.MsgOptionBlocks(blocks...)
 }
 
+// getMSTeamsPersonalizations generates the teams alert card with the content of the slack alert, along with the channels to post it on
+func (s *BudgetsService) getMSTeamsPersonalizations(ctx context.Context, b *budget.Budget) (*msteams.AdaptiveCard, []common.MSTeamsChannel, error) {
+	l := s.loggerProvider(ctx)
+
+	channels := make([]common.MSTeamsChannel, 0)
+
+	if len(b.RecipientsMSTeamsChannels) == 0 {
+		return nil, channels, nil
+	}
+
+	currentSpendAmount := common.FormatNumber(b.Utilization.Current, 2)
+
+	var currentSpendPercentage float64
+	if b.Config.Amount != 0 {
+		currentSpendPercentage = math.Round(b.Utilization.Current/b.Config.Amount*10000) / 100
+	}
+
+	selectedAlert := getAlertData(b)
+	alertAmount := common.FormatNumber(selectedAlert.Percentage*b.Config.Amount/100, 2)
+
+	budgetType, err := GetBudgetTypeString(b.Config.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currency := b.Config.Currency.Symbol()
+
+	budgetPeriod := string(b.Config.TimeInterval)
+	if budgetPeriod == "day" {
+		budgetPeriod = "dai"
+	}
+
+	facts := []msteams.Fact{
+		{Title: "Type", Value: strings.Title(budgetType)},
+		{Title: "Period", Value: strings.Title(budgetPeriod) + "ly"},
+		{Title: "Alert spend", Value: currency + alertAmount},
+		{Title: "Alert %", Value: fmt.Sprintf("%.f", selectedAlert.Percentage)},
+		{Title: "Current spend", Value: currency + currentSpendAmount},
+		{Title: "Budget %", Value: fmt.Sprintf("%.f", currentSpendPercentage)},
+	}
+
+	if b.Config.Type == budget.Fixed {
+		facts = append(facts[:1], facts[2:]...)
+	}
+
+	card := msteams.NewAdaptiveCard().
+		AddTitle(s.getSubject(currentSpendPercentage, b.Name, false)).
+		AddFacts(facts...)
+
+	if b.Description != "" {
+		card.AddText("**Description**: " + b.Description)
+	}
+
+	var forecastedTotalAmountDate string
+	if b.Utilization.ForecastedTotalAmountDate != nil {
+		forecastedTotalAmountDate = b.Utilization.ForecastedTotalAmountDate.Format(DateFormat)
+	}
+
+	card.AddText(fmt.Sprintf(TextMSTeamsForecastedTotalAmount, forecastedTotalAmountDate)).
+		AddOpenURLAction(TextOpenBudget, fmt.Sprintf(TextBudgetURL, b.Customer.ID, b.ID))
+
+	for _, channel := range b.RecipientsMSTeamsChannels {
+		if !common.Production && b.Customer.ID != common.DoitCustomerID { // on development - only send alerts for doit
+			l.Info(fmt.Sprintf("teams alert to %s didn't send while in development", channel.Name))
+			continue
+		}
+
+		channels = append(channels, channel)
+	}
+
+	return card, channels, nil
+}
+
+// AddMSTeamsForecastImage adds the forecast chart image to a budget alert card
+func (s *BudgetsService) AddMSTeamsForecastImage(card *msteams.AdaptiveCard, imageURLForecasted string) *msteams.AdaptiveCard {
+	if imageURLForecasted == "" {
+		return card
+	}
+
+	return card.AddText(TextForecastedSpend).AddImage(imageURLForecasted, TextForecasted)
+}
+
 func (b *BudgetsService) dispatchBudget(ctx context.Context, budget *budget.Budget) error {
 	dispatchBudget, err := mapInternalBudgetToResponseBudget(budget)
 	if err != nil {
@@ -645,6 +766,32 @@ func (b *BudgetsService) dispatchBudget(ctx context.Context, budget *budget.Budg
 	)
 }
 
+func (b *BudgetsService) dispatchBudgetForecastedDate(ctx context.Context, budget *budget.Budget) error {
+	budgetAPI, err := mapInternalBudgetToResponseBudget(budget)
+	if err != nil {
+		return fmt.Errorf("unable to dispatch: error map to external budget: %w", err)
+	}
+
+	event := WebhookBudgetForecastedDate{Budget: *budgetAPI}
+
+	if budget.Utilization.ForecastedTotalAmountDate != nil {
+		forecastedDate := budget.Utilization.ForecastedTotalAmountDate.UnixMilli()
+		event.ForecastedDate = &forecastedDate
+	}
+
+	if budget.Utilization.PreviousForecastedDate != nil {
+		previousForecastedDate := budget.Utilization.PreviousForecastedDate.UnixMilli()
+		event.PreviousForecastedDate = &previousForecastedDate
+	}
+
+	return b.eventDispatcher.Dispatch(ctx,
+		event,
+		budget.Customer,
+		budget.ID,
+		events.BudgetForecastedDateChanged,
+	)
+}
+
 func mapBudgetToNotification(b *budget.Budget, nt budget.BudgetNotificationType, alertDate time.Time) *budget.BudgetNotification {
 	var currentSpendPercentage float64
 	if b.Config.Amount != 0 {
@@ -1,11 +1,17 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/domain/budget"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/fixer"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -81,3 +87,76 @@ func Test_mapBudgetToNotification(t *testing.T) {
 		})
 	}
 }
+
+func TestBudgetsService_getMSTeamsPersonalizations(t *testing.T) {
+	forecastedDate := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	channel := common.MSTeamsChannel{Name: "finops", WebhookURL: "https://doit.webhook.office.com/webhookb2/abc"}
+
+	newBudget := func() *budget.Budget {
+		return &budget.Budget{
+			ID:       "budget-id",
+			Name:     "Prod",
+			Customer: &firestore.DocumentRef{ID: common.DoitCustomerID},
+			Config: &budget.BudgetConfig{
+				Type:         budget.Recurring,
+				Amount:       100,
+				Currency:     fixer.USD,
+				TimeInterval: report.TimeIntervalMonth,
+				Alerts: [3]budget.BudgetAlert{
+					{Percentage: 50, Triggered: true},
+					{Percentage: 90, Triggered: true},
+				},
+			},
+			Utilization: budget.BudgetUtilization{
+				Current:                   95,
+				ForecastedTotalAmountDate: &forecastedDate,
+			},
+			RecipientsMSTeamsChannels: []common.MSTeamsChannel{channel},
+		}
+	}
+
+	s := &BudgetsService{loggerProvider: logger.FromContext}
+
+	t.Run("recurring budget", func(t *testing.T) {
+		card, channels, err := s.getMSTeamsPersonalizations(context.Background(), newBudget())
+		assert.NoError(t, err)
+		assert.Equal(t, []common.MSTeamsChannel{channel}, channels)
+
+		assert.Equal(t, "Budget Alert: You’ve exceeded 95.00% of your Prod budget", card.Body[0].Text)
+		assert.Equal(t, []msteams.Fact{
+			{Title: "Type", Value: "Recurring"},
+			{Title: "Period", Value: "Monthly"},
+			{Title: "Alert spend", Value: "$90"},
+			{Title: "Alert %", Value: "90"},
+			{Title: "Current spend", Value: "$95"},
+			{Title: "Budget %", Value: "95"},
+		}, card.Body[1].Facts)
+		assert.Contains(t, card.Body[2].Text, "May 20, 2024")
+		assert.Equal(t, "https://console.doit.com/customers/"+common.DoitCustomerID+"/analytics/budgets/budget-id", card.Actions[0].URL)
+
+		card = s.AddMSTeamsForecastImage(card, "https://example.com/forecast.png")
+		assert.Equal(t, msteams.ElementTypeImage, card.Body[len(card.Body)-1].Type)
+	})
+
+	t.Run("fixed budget with a description", func(t *testing.T) {
+		b := newBudget()
+		b.Config.Type = budget.Fixed
+		b.Description = "Production workloads"
+
+		card, _, err := s.getMSTeamsPersonalizations(context.Background(), b)
+		assert.NoError(t, err)
+		assert.Len(t, card.Body[1].Facts, 5)
+		assert.Equal(t, "Alert spend", card.Body[1].Facts[1].Title)
+		assert.Equal(t, "**Description**: Production workloads", card.Body[2].Text)
+	})
+
+	t.Run("no channels", func(t *testing.T) {
+		b := newBudget()
+		b.RecipientsMSTeamsChannels = nil
+
+		card, channels, err := s.getMSTeamsPersonalizations(context.Background(), b)
+		assert.NoError(t, err)
+		assert.Nil(t, card)
+		assert.Empty(t, channels)
+	})
+}
@@ -60,7 +60,7 @@ func NewBudgetsService(loggerProvider logger.Provider, conn *connection.Connecti
 		doitemployees.NewService(conn),
 		service.NewCAOwnerChecker(conn),
 		labelsDal.NewLabelsFirestoreWithClient(conn.Firestore),
-		dispatch.NewEventDispatcher(loggerProvider, conn.Firestore),
+		dispatch.NewEventDispatcher(loggerProvider, conn),
 	}, nil
 }
 
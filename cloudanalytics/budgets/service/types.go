@@ -144,3 +144,10 @@ type DeleteManyBudgetRequest struct {
 	Email      string   `json:"-"`
 	CustomerID string   `json:"-"`
 }
+
+// WebhookBudgetForecastedDate is the payload of the budget forecasted date changed webhook event
+type WebhookBudgetForecastedDate struct {
+	Budget                 BudgetAPI `json:"budget"`
+	ForecastedDate         *int64    `json:"forecastedDate"`
+	PreviousForecastedDate *int64    `json:"previousForecastedDate"`
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"cloud.google.com/go/firestore"
+
+	"github.com/doitintl/hello/scheduled-tasks/dashboard"
+)
+
+const (
+	DashboardTypeCustom = "custom"
+	DashboardTypePreset = "preset"
+
+	// ExportVersion is the version of the dashboard export format. Imports of other versions are rejected.
+	ExportVersion = 1
+
+	reportWidgetPrefix = "cloudReports::"
+)
+
+type DashboardRequestData struct {
+	// A field by which the results will be sorted.
+	// Required: false
+	// Enum: id,name,type
+	SortBy string `json:"sortBy"`
+	// Sort order of Dashboards can be either ascending or descending.
+	// Required: false
+	// Enum: asc,desc
+	SortOrder string `json:"sortOrder"`
+	// The maximum number of results to return in a single page. Leverage the page tokens to iterate through the entire collection.
+	// Required: false
+	// Default: 500
+	// Type: integer
+	MaxResults string `json:"maxResults"`
+	// Page token, returned by a previous call, to request the next page of results
+	PageToken string `json:"pageToken,omitempty"`
+	// An expression for filtering the results of the request. The syntax is "key:[<value>]". e.g: "name:test". Multiple filters can be connected using a pipe |. Note that using different keys in the same filter results in “AND,” while using the same key multiple times in the same filter results in “OR”.
+	// Available filters: owner, name, type
+	Filter     string `json:"filter"`
+	Email      string `json:"-"`
+	CustomerID string `json:"-"`
+}
+
+func (d *DashboardRequestData) GetFilter() string          { return d.Filter }
+func (d *DashboardRequestData) GetMaxResults() string      { return d.MaxResults }
+func (d *DashboardRequestData) GetNextPageToken() string   { return d.PageToken }
+func (d *DashboardRequestData) GetSortBy() string          { return d.SortBy }
+func (d *DashboardRequestData) GetSortOrder() string       { return d.SortOrder }
+func (d *DashboardRequestData) GetCustomerID() string      { return d.CustomerID }
+func (d *DashboardRequestData) GetEmail() string           { return d.Email }
+func (d *DashboardRequestData) GetMinCreationTime() string { return "" }
+func (d *DashboardRequestData) GetMaxCreationTime() string { return "" }
+
+func (d *DashboardRequestData) GetAllowedFilters() map[string]string {
+	return map[string]string{"owner": "Owner", "name": "Name", "type": "Type"}
+}
+
+func (d *DashboardRequestData) GetAllowedSortBy() map[string]string {
+	return map[string]string{"id": firestore.DocumentID, "name": "name", "type": "type"}
+}
+
+// ReportWidgetName returns the name of the widget that shows the report on the customer dashboards.
+func ReportWidgetName(customerID, reportID string) string {
+	return reportWidgetPrefix + customerID + "_" + reportID
+}
+
+// WidgetReportID returns the report that is shown by the widget. ok is false for widgets that do not show a report.
+func WidgetReportID(widget dashboard.DashboardWidget) (reportID string, ok bool) {
+	if _, _, reportID, err := widget.ExtractInfoFromName(); err == nil {
+		return reportID, true
+	}
+
+	return "", false
+}
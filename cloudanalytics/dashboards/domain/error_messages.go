@@ -0,0 +1,7 @@
+package domain
+
+const (
+	ErrUnsupportedVersionMsg = "unsupported export version"
+	ErrUnknownReportKeyMsg   = "widget references a report that is not in the export"
+	ErrDuplicateReportKeyMsg = "report key is used more than once"
+)
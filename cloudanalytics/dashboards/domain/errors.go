@@ -0,0 +1,18 @@
+package domain
+
+import "errors"
+
+var (
+	ErrValidation         = errors.New("validation error")
+	ErrMissingDashboardID = errors.New("missing dashboard id")
+	ErrMissingReportID    = errors.New("missing report id")
+	ErrNotFound           = errors.New("dashboard not found")
+	ErrWidgetNotFound     = errors.New("widget not found on dashboard")
+	ErrWidgetDataNotFound = errors.New("widget data is not available yet")
+	ErrGetDashboard       = errors.New("failed to get dashboard")
+	ErrListDashboards     = errors.New("failed to list dashboards")
+	ErrGetWidgetData      = errors.New("failed to get widget data")
+	ErrExportDashboard    = errors.New("failed to export dashboard")
+	ErrImportDashboard    = errors.New("failed to import dashboard")
+	ErrMissingUserID      = errors.New("missing user id")
+)
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attribution-groups/dal"
+	attributionGroupsService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attribution-groups/service"
+	attributionsDal "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/dal"
+	attributionsService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/service"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/service"
+	serviceIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/service/iface"
+	externalAPIService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/service"
+	datahubMetricDal "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/dal/datahubmetric"
+	metricsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/dal"
+	metricsService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/service"
+	postProcessingAggregationService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/aggregation/service"
+	splittingService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/service"
+	reportsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal"
+	reportsService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service"
+	externalReportService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/externalreport"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/reporttier"
+	reportValidatorService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/reportvalidator"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/widget"
+	customersDAL "github.com/doitintl/hello/scheduled-tasks/customer/dal"
+	"github.com/doitintl/hello/scheduled-tasks/doitemployees"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	tier "github.com/doitintl/tiers/service"
+)
+
+type Dashboards struct {
+	loggerProvider logger.Provider
+	service        serviceIface.IDashboardsService
+}
+
+func NewDashboards(ctx context.Context, loggerProvider logger.Provider, conn *connection.Connection) *Dashboards {
+	customerDAL := customersDAL.NewCustomersFirestoreWithClient(conn.Firestore)
+	reportDAL := reportsDAL.NewReportsFirestoreWithClient(conn.Firestore)
+	metricDAL := metricsDAL.NewMetricsFirestoreWithClient(conn.Firestore)
+
+	externalReportService, err := externalReportService.NewExternalReportService(
+		loggerProvider,
+		datahubMetricDal.NewDataHubMetricFirestoreWithClient(conn.Firestore),
+		attributionsService.NewAttributionsService(ctx, loggerProvider, conn),
+		attributionGroupsService.NewAttributionGroupsService(ctx, loggerProvider, conn),
+		metricsService.NewMetricsService(loggerProvider, conn),
+		splittingService.NewSplittingService(),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	widgetService, err := widget.NewWidgetService(loggerProvider, conn)
+	if err != nil {
+		panic(err)
+	}
+
+	cloudAnalyticsService, err := cloudanalytics.NewCloudAnalyticsService(loggerProvider, conn, reportDAL, customerDAL)
+	if err != nil {
+		panic(err)
+	}
+
+	reportService, err := reportsService.NewReportService(
+		loggerProvider,
+		conn,
+		cloudAnalyticsService,
+		externalReportService,
+		externalAPIService.NewExternalAPIService(),
+		reportValidatorService.NewWithAllRules(metricDAL),
+		widgetService,
+		postProcessingAggregationService.NewAggregationService(),
+		reportDAL,
+		customerDAL,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	reportTierService := reporttier.NewReportTierService(
+		loggerProvider,
+		reportDAL,
+		customerDAL,
+		attributionsDal.NewAttributionsFirestoreWithClient(conn.Firestore),
+		dal.NewAttributionGroupsFirestoreWithClient(conn.Firestore),
+		tier.NewTiersService(conn.Firestore),
+		doitemployees.NewService(conn),
+	)
+
+	return &Dashboards{
+		loggerProvider,
+		service.NewDashboardsService(loggerProvider, conn, reportService, reportTierService, widgetService),
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/doitintl/auth"
+	"github.com/doitintl/customerapi"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/service"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	domainTier "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/tier/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+func (h *Dashboards) ExternalAPIListDashboards(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	email := ctx.GetString(common.CtxKeys.Email)
+
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+	})
+
+	r := domain.DashboardRequestData{
+		SortBy:     ctx.Request.URL.Query().Get("sortBy"),
+		SortOrder:  ctx.Request.URL.Query().Get("sortOrder"),
+		MaxResults: ctx.Request.URL.Query().Get("maxResults"),
+		PageToken:  ctx.Request.URL.Query().Get("pageToken"),
+		Filter:     ctx.Request.URL.Query().Get("filter"),
+		Email:      email,
+		CustomerID: customerID,
+	}
+
+	reqData, err := customerapi.NewAPIRequest(&r)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	dashboardsList, err := h.service.ListDashboards(ctx, service.ExternalAPIListArgsReq{
+		CustomerID:    customerID,
+		Email:         email,
+		SortBy:        reqData.SortBy,
+		SortOrder:     reqData.SortOrder,
+		Filters:       reqData.Filters,
+		MaxResults:    reqData.MaxResults,
+		NextPageToken: reqData.NextPageToken,
+	})
+	if err != nil {
+		l.Errorf("Failed to get list of dashboards\n Error: %v", err)
+		return web.NewRequestError(domain.ErrListDashboards, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, dashboardsList, http.StatusOK)
+}
+
+func (h *Dashboards) ExternalAPIGetDashboard(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	email := ctx.GetString(common.CtxKeys.Email)
+	dashboardID := ctx.Param("id")
+
+	if dashboardID == "" {
+		return web.NewRequestError(domain.ErrMissingDashboardID, http.StatusBadRequest)
+	}
+
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+		"dashboardId":          dashboardID,
+	})
+
+	d, err := h.service.GetDashboard(ctx, customerID, email, dashboardID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return web.NewRequestError(err, http.StatusNotFound)
+		}
+
+		l.Errorf("Failed to get dashboard %s \n Error: %v", dashboardID, err)
+
+		return web.NewRequestError(domain.ErrGetDashboard, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, d, http.StatusOK)
+}
+
+func (h *Dashboards) ExternalAPIGetWidgetData(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	email := ctx.GetString(common.CtxKeys.Email)
+	dashboardID := ctx.Param("id")
+	reportID := ctx.Param("reportId")
+
+	if dashboardID == "" {
+		return web.NewRequestError(domain.ErrMissingDashboardID, http.StatusBadRequest)
+	}
+
+	if reportID == "" {
+		return web.NewRequestError(domain.ErrMissingReportID, http.StatusBadRequest)
+	}
+
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+		"dashboardId":          dashboardID,
+		"reportId":             reportID,
+	})
+
+	data, err := h.service.GetWidgetData(ctx, customerID, email, dashboardID, reportID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound),
+			errors.Is(err, domain.ErrWidgetNotFound),
+			errors.Is(err, domain.ErrWidgetDataNotFound):
+			return web.NewRequestError(err, http.StatusNotFound)
+		}
+
+		l.Errorf("Failed to get data of widget %s of dashboard %s \n Error: %v", reportID, dashboardID, err)
+
+		return web.NewRequestError(domain.ErrGetWidgetData, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, data, http.StatusOK)
+}
+
+func (h *Dashboards) ExternalAPIExportDashboard(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	email := ctx.GetString(common.CtxKeys.Email)
+	dashboardID := ctx.Param("id")
+
+	if dashboardID == "" {
+		return web.NewRequestError(domain.ErrMissingDashboardID, http.StatusBadRequest)
+	}
+
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+		"dashboardId":          dashboardID,
+	})
+
+	export, err := h.service.ExportDashboard(ctx, customerID, email, dashboardID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return web.NewRequestError(err, http.StatusNotFound)
+		}
+
+		l.Errorf("Failed to export dashboard %s \n Error: %v", dashboardID, err)
+
+		return web.NewRequestError(domain.ErrExportDashboard, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, export, http.StatusOK)
+}
+
+func (h *Dashboards) ExternalAPIImportDashboard(ctx *gin.Context) error {
+	email := ctx.GetString(common.CtxKeys.Email)
+	userID := ctx.GetString(common.CtxKeys.UserID)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+
+	l := h.loggerProvider(ctx)
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+	})
+
+	var importRequest service.DashboardImportRequest
+
+	if err := ctx.ShouldBindJSON(&importRequest); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": errormsg.MapTagValidationErrors(err, false)})
+		return nil
+	}
+
+	resp := h.service.ImportDashboard(ctx, service.ExternalAPIImportArgsReq{
+		ImportRequest: &importRequest,
+		CustomerID:    customerID,
+		Email:         email,
+		UserID:        userID,
+	})
+
+	if resp.Error != nil {
+		var accessDeniedErr *domainTier.AccessDeniedError
+
+		switch {
+		case errors.Is(resp.Error, domain.ErrValidation):
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": resp.ValidationErrors})
+			return nil
+		case errors.As(resp.Error, &accessDeniedErr):
+			return web.Respond(ctx, accessDeniedErr.PublicError(), http.StatusForbidden)
+		case errors.Is(resp.Error, domain.ErrMissingUserID):
+			return web.NewRequestError(resp.Error, http.StatusForbidden)
+		}
+
+		l.Errorf("Failed to import dashboard\n Error: %v", resp.Error)
+
+		return web.NewRequestError(domain.ErrImportDashboard, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, resp.Dashboard, http.StatusCreated)
+}
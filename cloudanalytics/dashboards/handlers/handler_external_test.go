@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/auth"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/service"
+	serviceMock "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/service/mocks"
+	domainTier "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/tier/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+const (
+	customerID  = "customer1"
+	email       = "user@example.com"
+	userID      = "user1"
+	dashboardID = "dashboard1"
+	reportID    = "report1"
+)
+
+func getExternalContext(method string, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	request := httptest.NewRequest(method, "http://example.com/analytics/v1/dashboards", bytes.NewBufferString(body))
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+	ctx.Params = []gin.Param{{Key: "id", Value: dashboardID}, {Key: "reportId", Value: reportID}}
+	ctx.Set(auth.CtxKeyVerifiedCustomerID, customerID)
+	ctx.Set(common.CtxKeys.Email, email)
+	ctx.Set(common.CtxKeys.UserID, userID)
+
+	return ctx, recorder
+}
+
+func TestDashboards_ExternalAPIGetDashboard(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceErr  error
+		expectedErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:        "dashboard not found",
+			serviceErr:  domain.ErrNotFound,
+			expectedErr: web.NewRequestError(domain.ErrNotFound, http.StatusNotFound),
+		},
+		{
+			name:        "internal error",
+			serviceErr:  errors.New("error"),
+			expectedErr: web.NewRequestError(domain.ErrGetDashboard, http.StatusInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := serviceMock.NewIDashboardsService(t)
+
+			var d *service.DashboardAPI
+			if tt.serviceErr == nil {
+				d = &service.DashboardAPI{ID: dashboardID}
+			}
+
+			s.On("GetDashboard", mock.Anything, customerID, email, dashboardID).Return(d, tt.serviceErr)
+
+			h := &Dashboards{
+				loggerProvider: logger.FromContext,
+				service:        s,
+			}
+
+			ctx, _ := getExternalContext(http.MethodGet, "")
+
+			err := h.ExternalAPIGetDashboard(ctx)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestDashboards_ExternalAPIGetWidgetData(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceErr  error
+		expectedErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:        "report is not on the dashboard",
+			serviceErr:  domain.ErrWidgetNotFound,
+			expectedErr: web.NewRequestError(domain.ErrWidgetNotFound, http.StatusNotFound),
+		},
+		{
+			name:        "widget was not refreshed yet",
+			serviceErr:  domain.ErrWidgetDataNotFound,
+			expectedErr: web.NewRequestError(domain.ErrWidgetDataNotFound, http.StatusNotFound),
+		},
+		{
+			name:        "internal error",
+			serviceErr:  errors.New("error"),
+			expectedErr: web.NewRequestError(domain.ErrGetWidgetData, http.StatusInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := serviceMock.NewIDashboardsService(t)
+
+			var data *service.WidgetDataAPI
+			if tt.serviceErr == nil {
+				data = &service.WidgetDataAPI{ReportID: reportID}
+			}
+
+			s.On("GetWidgetData", mock.Anything, customerID, email, dashboardID, reportID).Return(data, tt.serviceErr)
+
+			h := &Dashboards{
+				loggerProvider: logger.FromContext,
+				service:        s,
+			}
+
+			ctx, _ := getExternalContext(http.MethodGet, "")
+
+			err := h.ExternalAPIGetWidgetData(ctx)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestDashboards_ExternalAPIImportDashboard(t *testing.T) {
+	const validBody = `{"export":{"version":1,"dashboard":{"name":"Imported","widgets":[{"reportKey":"r1"}]},"reports":[{"key":"r1","report":{"name":"Monthly cost"}}]}}`
+
+	tests := []struct {
+		name         string
+		body         string
+		resp         service.ExternalAPIImportResp
+		callService  bool
+		expectedCode int
+	}{
+		{
+			name:         "success",
+			body:         validBody,
+			resp:         service.ExternalAPIImportResp{Dashboard: &service.DashboardAPI{ID: dashboardID}},
+			callService:  true,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "missing export",
+			body:         `{"attributionMapping":{"a":"b"}}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "validation errors",
+			body:         validBody,
+			resp:         service.ExternalAPIImportResp{Error: domain.ErrValidation},
+			callService:  true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "access denied",
+			body:         validBody,
+			resp:         service.ExternalAPIImportResp{Error: &domainTier.AccessDeniedError{}},
+			callService:  true,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := serviceMock.NewIDashboardsService(t)
+			if tt.callService {
+				s.On("ImportDashboard", mock.Anything, mock.MatchedBy(func(args service.ExternalAPIImportArgsReq) bool {
+					return args.CustomerID == customerID && args.Email == email && args.UserID == userID
+				})).Return(tt.resp)
+			}
+
+			h := &Dashboards{
+				loggerProvider: logger.FromContext,
+				service:        s,
+			}
+
+			ctx, recorder := getExternalContext(http.MethodPost, tt.body)
+
+			err := h.ExternalAPIImportDashboard(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
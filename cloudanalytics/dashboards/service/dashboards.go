@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	reportsIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/iface"
+	reportTierIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/reporttier/iface"
+	domainWidget "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/widget/domain"
+	dashboardDal "github.com/doitintl/hello/scheduled-tasks/dashboard/dal"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+type widgetService interface {
+	GetWidgetReport(ctx context.Context, customerID, orgID, reportID string) (*domainWidget.WidgetReport, error)
+}
+
+type DashboardsService struct {
+	loggerProvider      logger.Provider
+	dashboardsDAL       dashboardDal.Dashboards
+	publicDashboardsDAL dashboardDal.PublicDashboards
+	reportService       reportsIface.IReportService
+	reportTierService   reportTierIface.ReportTierService
+	widgetService       widgetService
+}
+
+func NewDashboardsService(
+	loggerProvider logger.Provider,
+	conn *connection.Connection,
+	reportService reportsIface.IReportService,
+	reportTierService reportTierIface.ReportTierService,
+	widgetService widgetService,
+) *DashboardsService {
+	return &DashboardsService{
+		loggerProvider,
+		dashboardDal.NewDashboardsFirestoreWithClient(conn.Firestore),
+		dashboardDal.NewPublicDashboardsFirestoreWithClient(conn.Firestore),
+		reportService,
+		reportTierService,
+		widgetService,
+	}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/externalreport"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard"
+)
+
+const (
+	versionField = "export.version"
+	widgetsField = "export.dashboard.widgets"
+	reportsField = "export.reports"
+)
+
+// ExportDashboard exports the layout of the dashboard report widgets and the config of their reports.
+// Widgets that do not show a report are not exported.
+func (s *DashboardsService) ExportDashboard(ctx context.Context, customerID string, email string, dashboardID string) (*DashboardExport, error) {
+	d, err := s.getDashboard(ctx, customerID, email, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &DashboardExport{
+		Version: domain.ExportVersion,
+		Dashboard: &ExportedDashboard{
+			Name:         d.Name,
+			WidgetHeight: d.WidgetHeight,
+			Widgets:      []*ExportedWidget{},
+		},
+		Reports: []*ExportedReport{},
+	}
+
+	exportedReports := make(map[string]bool)
+
+	for _, w := range d.Widgets {
+		reportID, ok := domain.WidgetReportID(w)
+		if !ok {
+			continue
+		}
+
+		export.Dashboard.Widgets = append(export.Dashboard.Widgets, &ExportedWidget{
+			ReportKey: reportID,
+			CardWidth: w.CardWidth,
+			Visible:   w.Visible,
+		})
+
+		if exportedReports[reportID] {
+			continue
+		}
+
+		report, err := s.reportService.GetReportConfig(ctx, reportID, customerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export report %s: %w", reportID, err)
+		}
+
+		report.ID = ""
+		report.Type = nil
+
+		export.Reports = append(export.Reports, &ExportedReport{
+			Key:    reportID,
+			Report: report,
+		})
+		exportedReports[reportID] = true
+	}
+
+	return export, nil
+}
+
+// ImportDashboard creates the reports of the export for the customer, remapping the attributions they
+// use, and a dashboard of the user that shows them. Reports that were created are deleted when the import fails.
+func (s *DashboardsService) ImportDashboard(ctx context.Context, args ExternalAPIImportArgsReq) ExternalAPIImportResp {
+	if args.UserID == "" {
+		return ExternalAPIImportResp{Error: domain.ErrMissingUserID}
+	}
+
+	export := args.ImportRequest.Export
+
+	if validationErrors := validateExport(export); len(validationErrors) > 0 {
+		return ExternalAPIImportResp{ValidationErrors: validationErrors, Error: domain.ErrValidation}
+	}
+
+	for _, r := range export.Reports {
+		r.Report.ID = ""
+		r.Report.Type = nil
+		remapAttributions(r.Report.Config, args.ImportRequest.AttributionMapping)
+
+		accessDeniedErr, err := s.reportTierService.CheckAccessToExternalReport(ctx, args.CustomerID, r.Report, true)
+		if err != nil {
+			return ExternalAPIImportResp{Error: err}
+		}
+
+		if accessDeniedErr != nil {
+			return ExternalAPIImportResp{Error: accessDeniedErr}
+		}
+	}
+
+	reportIDs := make(map[string]string, len(export.Reports))
+	createdReportIDs := make([]string, 0, len(export.Reports))
+
+	for i, r := range export.Reports {
+		report, validationErrors, err := s.reportService.CreateReportWithExternal(ctx, r.Report, args.CustomerID, args.Email)
+		if err != nil {
+			s.deleteImportedReports(ctx, args.CustomerID, args.Email, createdReportIDs)
+
+			if validationErrors != nil {
+				return ExternalAPIImportResp{
+					ValidationErrors: reportValidationErrors(i, validationErrors),
+					Error:            domain.ErrValidation,
+				}
+			}
+
+			return ExternalAPIImportResp{Error: err}
+		}
+
+		reportIDs[r.Key] = report.ID
+		createdReportIDs = append(createdReportIDs, report.ID)
+	}
+
+	widgets := make([]dashboard.DashboardWidget, len(export.Dashboard.Widgets))
+
+	for i, w := range export.Dashboard.Widgets {
+		widgets[i] = dashboard.DashboardWidget{
+			Name:      domain.ReportWidgetName(args.CustomerID, reportIDs[w.ReportKey]),
+			CardWidth: w.CardWidth,
+			Visible:   w.Visible,
+		}
+	}
+
+	d, err := s.dashboardsDAL.CreateUserDashboard(ctx, args.UserID, args.CustomerID, &dashboard.Dashboard{
+		AllowToEdit:         true,
+		CustomerID:          args.CustomerID,
+		Email:               args.Email,
+		Name:                export.Dashboard.Name,
+		WidgetHeight:        export.Dashboard.WidgetHeight,
+		Widgets:             widgets,
+		OwnerID:             args.UserID,
+		RequiredPermissions: []string{},
+		HasCloudReports:     len(widgets) > 0,
+	})
+	if err != nil {
+		s.deleteImportedReports(ctx, args.CustomerID, args.Email, createdReportIDs)
+		return ExternalAPIImportResp{Error: err}
+	}
+
+	return ExternalAPIImportResp{Dashboard: toDashboardAPI(&customerDashboard{d, domain.DashboardTypeCustom})}
+}
+
+func (s *DashboardsService) deleteImportedReports(ctx context.Context, customerID string, email string, reportIDs []string) {
+	if len(reportIDs) == 0 {
+		return
+	}
+
+	if err := s.reportService.DeleteMany(ctx, customerID, email, reportIDs); err != nil {
+		s.loggerProvider(ctx).Errorf("failed to delete reports %v of failed dashboard import: %s", reportIDs, err)
+	}
+}
+
+// validateExport checks the export version and that every widget references a report of the export.
+func validateExport(export *DashboardExport) []errormsg.ErrorMsg {
+	var validationErrors []errormsg.ErrorMsg
+
+	if export.Version != domain.ExportVersion {
+		validationErrors = append(validationErrors, errormsg.ErrorMsg{
+			Field:   versionField,
+			Message: fmt.Sprintf("%s: %d", domain.ErrUnsupportedVersionMsg, export.Version),
+		})
+	}
+
+	reportKeys := make(map[string]bool, len(export.Reports))
+
+	for i, r := range export.Reports {
+		if reportKeys[r.Key] {
+			validationErrors = append(validationErrors, errormsg.ErrorMsg{
+				Field:   fmt.Sprintf("%s[%d].key", reportsField, i),
+				Message: fmt.Sprintf("%s: %s", domain.ErrDuplicateReportKeyMsg, r.Key),
+			})
+		}
+
+		reportKeys[r.Key] = true
+	}
+
+	for i, w := range export.Dashboard.Widgets {
+		if !reportKeys[w.ReportKey] {
+			validationErrors = append(validationErrors, errormsg.ErrorMsg{
+				Field:   fmt.Sprintf("%s[%d].reportKey", widgetsField, i),
+				Message: fmt.Sprintf("%s: %s", domain.ErrUnknownReportKeyMsg, w.ReportKey),
+			})
+		}
+	}
+
+	return validationErrors
+}
+
+// reportValidationErrors prefixes the validation errors of a report with its position in the export.
+func reportValidationErrors(i int, validationErrors []errormsg.ErrorMsg) []errormsg.ErrorMsg {
+	prefixed := make([]errormsg.ErrorMsg, len(validationErrors))
+
+	for j, e := range validationErrors {
+		prefixed[j] = errormsg.ErrorMsg{
+			Field:   fmt.Sprintf("%s[%d].report.%s", reportsField, i, e.Field),
+			Message: e.Message,
+		}
+	}
+
+	return prefixed
+}
+
+// remapAttributions replaces the attribution IDs used by the report filters, groups and splits
+// according to the mapping. Attributions that are not in the mapping are kept.
+func remapAttributions(config *externalreport.ExternalConfig, mapping map[string]string) {
+	if config == nil || len(mapping) == 0 {
+		return
+	}
+
+	remap := func(id string) string {
+		if mapped, ok := mapping[id]; ok {
+			return mapped
+		}
+
+		return id
+	}
+
+	for _, filter := range config.Filters {
+		if filter == nil || filter.Type != metadata.MetadataFieldTypeAttribution || filter.Values == nil {
+			continue
+		}
+
+		values := make([]string, len(*filter.Values))
+		for i, v := range *filter.Values {
+			values[i] = remap(v)
+		}
+
+		filter.Values = &values
+	}
+
+	for _, group := range config.Groups {
+		if group != nil && group.Type == metadata.MetadataFieldTypeAttribution {
+			group.ID = remap(group.ID)
+		}
+	}
+
+	for _, split := range config.Splits {
+		if split == nil {
+			continue
+		}
+
+		if split.Origin.Type == metadata.MetadataFieldTypeAttribution {
+			split.Origin.ID = remap(split.Origin.ID)
+		}
+
+		for i := range split.Targets {
+			if split.Targets[i].Type == metadata.MetadataFieldTypeAttribution {
+				split.Targets[i].ID = remap(split.Targets[i].ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/externalreport"
+	reportMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/mocks"
+	reportTierMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/service/reporttier/mocks"
+	domainTier "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/tier/domain"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard"
+	dashboardMocks "github.com/doitintl/hello/scheduled-tasks/dashboard/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+func TestDashboardsService_ExportDashboard(t *testing.T) {
+	d := testDashboard(dashboardID, email, false)
+	d.Widgets = append(d.Widgets, dashboard.DashboardWidget{Name: domain.ReportWidgetName(customerID, reportID), CardWidth: 12})
+
+	dashboardsDAL := dashboardMocks.NewDashboards(t)
+	publicDashboardsDAL := dashboardMocks.NewPublicDashboards(t)
+	reportService := reportMocks.NewIReportService(t)
+
+	dashboardsDAL.On("ListCustomerDashboards", mock.Anything, customerID).Return([]*dashboard.Dashboard{d}, nil)
+	publicDashboardsDAL.On("ListCustomerDashboards", mock.Anything, customerID).Return([]*dashboard.Dashboard{}, nil)
+
+	reportType := "custom"
+	reportService.On("GetReportConfig", mock.Anything, reportID, customerID).
+		Return(&externalreport.ExternalReport{ID: reportID, Name: "Monthly cost", Type: &reportType}, nil).
+		Once()
+
+	s := &DashboardsService{
+		loggerProvider:      logger.FromContext,
+		dashboardsDAL:       dashboardsDAL,
+		publicDashboardsDAL: publicDashboardsDAL,
+		reportService:       reportService,
+	}
+
+	export, err := s.ExportDashboard(context.Background(), customerID, email, dashboardID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ExportVersion, export.Version)
+	assert.Equal(t, d.Name, export.Dashboard.Name)
+	assert.Equal(t, []*ExportedWidget{
+		{ReportKey: reportID, CardWidth: 4, Visible: true},
+		{ReportKey: reportID, CardWidth: 12},
+	}, export.Dashboard.Widgets)
+	assert.Equal(t, []*ExportedReport{
+		{Key: reportID, Report: &externalreport.ExternalReport{Name: "Monthly cost"}},
+	}, export.Reports)
+}
+
+func TestDashboardsService_ImportDashboard(t *testing.T) {
+	newExport := func() *DashboardExport {
+		values := []string{"attr1", "preset1"}
+
+		return &DashboardExport{
+			Version: domain.ExportVersion,
+			Dashboard: &ExportedDashboard{
+				Name:    "Imported",
+				Widgets: []*ExportedWidget{{ReportKey: "r1", CardWidth: 4, Visible: true}},
+			},
+			Reports: []*ExportedReport{
+				{
+					Key: "r1",
+					Report: &externalreport.ExternalReport{
+						Name: "Monthly cost",
+						Config: &externalreport.ExternalConfig{
+							Filters: []*externalreport.ExternalConfigFilter{
+								{ID: "attribution", Type: metadata.MetadataFieldTypeAttribution, Values: &values},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	accessDeniedErr := &domainTier.AccessDeniedError{}
+
+	tests := []struct {
+		name           string
+		userID         string
+		export         func() *DashboardExport
+		on             func(r *reportMocks.IReportService, rt *reportTierMocks.ReportTierService, d *dashboardMocks.Dashboards)
+		expectedErr    error
+		expectedFields []string
+	}{
+		{
+			name:   "success",
+			userID: userID,
+			export: newExport,
+			on: func(r *reportMocks.IReportService, rt *reportTierMocks.ReportTierService, d *dashboardMocks.Dashboards) {
+				rt.On("CheckAccessToExternalReport", mock.Anything, customerID, mock.Anything, true).Return(nil, nil)
+				r.On("CreateReportWithExternal", mock.Anything, mock.MatchedBy(func(report *externalreport.ExternalReport) bool {
+					return assert.ObjectsAreEqual([]string{"attr2", "preset1"}, *report.Config.Filters[0].Values)
+				}), customerID, email).Return(&externalreport.ExternalReport{ID: "newReport"}, nil, nil)
+				d.On("CreateUserDashboard", mock.Anything, userID, customerID, mock.MatchedBy(func(dash *dashboard.Dashboard) bool {
+					return dash.Name == "Imported" && dash.HasCloudReports && dash.OwnerID == userID &&
+						dash.Widgets[0].Name == domain.ReportWidgetName(customerID, "newReport")
+				})).Return(&dashboard.Dashboard{
+					ID:      "newDashboard",
+					Name:    "Imported",
+					Email:   email,
+					Widgets: []dashboard.DashboardWidget{{Name: domain.ReportWidgetName(customerID, "newReport")}},
+				}, nil)
+			},
+		},
+		{
+			name:        "missing user",
+			export:      newExport,
+			expectedErr: domain.ErrMissingUserID,
+		},
+		{
+			name:   "invalid export",
+			userID: userID,
+			export: func() *DashboardExport {
+				e := newExport()
+				e.Version = 2
+				e.Dashboard.Widgets[0].ReportKey = "r2"
+
+				return e
+			},
+			expectedErr:    domain.ErrValidation,
+			expectedFields: []string{"export.version", "export.dashboard.widgets[0].reportKey"},
+		},
+		{
+			name:   "report is not allowed by the customer tier",
+			userID: userID,
+			export: newExport,
+			on: func(r *reportMocks.IReportService, rt *reportTierMocks.ReportTierService, d *dashboardMocks.Dashboards) {
+				rt.On("CheckAccessToExternalReport", mock.Anything, customerID, mock.Anything, true).Return(accessDeniedErr, nil)
+			},
+			expectedErr: accessDeniedErr,
+		},
+		{
+			name:   "invalid report",
+			userID: userID,
+			export: newExport,
+			on: func(r *reportMocks.IReportService, rt *reportTierMocks.ReportTierService, d *dashboardMocks.Dashboards) {
+				rt.On("CheckAccessToExternalReport", mock.Anything, customerID, mock.Anything, true).Return(nil, nil)
+				r.On("CreateReportWithExternal", mock.Anything, mock.Anything, customerID, email).
+					Return(nil, []errormsg.ErrorMsg{{Field: "config.filters", Message: "invalid"}}, errors.New("validation error"))
+			},
+			expectedErr:    domain.ErrValidation,
+			expectedFields: []string{"export.reports[0].report.config.filters"},
+		},
+		{
+			name:   "reports are deleted when the dashboard is not created",
+			userID: userID,
+			export: newExport,
+			on: func(r *reportMocks.IReportService, rt *reportTierMocks.ReportTierService, d *dashboardMocks.Dashboards) {
+				rt.On("CheckAccessToExternalReport", mock.Anything, customerID, mock.Anything, true).Return(nil, nil)
+				r.On("CreateReportWithExternal", mock.Anything, mock.Anything, customerID, email).
+					Return(&externalreport.ExternalReport{ID: "newReport"}, nil, nil)
+				d.On("CreateUserDashboard", mock.Anything, userID, customerID, mock.Anything).Return(nil, errors.New("error"))
+				r.On("DeleteMany", mock.Anything, customerID, email, []string{"newReport"}).Return(nil)
+			},
+			expectedErr: errors.New("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reportService := reportMocks.NewIReportService(t)
+			reportTierService := reportTierMocks.NewReportTierService(t)
+			dashboardsDAL := dashboardMocks.NewDashboards(t)
+
+			if tt.on != nil {
+				tt.on(reportService, reportTierService, dashboardsDAL)
+			}
+
+			s := &DashboardsService{
+				loggerProvider:    logger.FromContext,
+				dashboardsDAL:     dashboardsDAL,
+				reportService:     reportService,
+				reportTierService: reportTierService,
+			}
+
+			resp := s.ImportDashboard(context.Background(), ExternalAPIImportArgsReq{
+				ImportRequest: &DashboardImportRequest{
+					Export:             tt.export(),
+					AttributionMapping: map[string]string{"attr1": "attr2"},
+				},
+				CustomerID: customerID,
+				Email:      email,
+				UserID:     tt.userID,
+			})
+
+			assert.Equal(t, tt.expectedErr, resp.Error)
+
+			fields := make([]string, 0, len(resp.ValidationErrors))
+			for _, e := range resp.ValidationErrors {
+				fields = append(fields, e.Field)
+			}
+
+			assert.ElementsMatch(t, tt.expectedFields, fields)
+
+			if tt.expectedErr == nil {
+				assert.Equal(t, "newDashboard", resp.Dashboard.ID)
+				assert.Equal(t, domain.DashboardTypeCustom, resp.Dashboard.Type)
+				assert.Equal(t, "newReport", resp.Dashboard.Widgets[0].ReportID)
+			}
+		})
+	}
+}
+
+func Test_remapAttributions(t *testing.T) {
+	filterValues := []string{"attr1", "attr3"}
+	config := &externalreport.ExternalConfig{
+		Filters: []*externalreport.ExternalConfigFilter{
+			{ID: "attribution", Type: metadata.MetadataFieldTypeAttribution, Values: &filterValues},
+			{ID: "service_description", Type: metadata.MetadataFieldTypeFixed, Values: &[]string{"attr1"}},
+		},
+		Groups: []*externalreport.Group{
+			{ID: "attr1", Type: metadata.MetadataFieldTypeAttribution},
+		},
+		Splits: []*externalreport.ExternalSplit{
+			{
+				ID:     "group1",
+				Type:   metadata.MetadataFieldTypeAttributionGroup,
+				Origin: externalreport.ExternalOrigin{ID: "attr1", Type: metadata.MetadataFieldTypeAttribution},
+				Targets: []externalreport.ExternalSplitTarget{
+					{ID: "attr3", Type: metadata.MetadataFieldTypeAttribution},
+					{ID: "attr1", Type: metadata.MetadataFieldTypeAttribution},
+				},
+			},
+		},
+	}
+
+	remapAttributions(config, map[string]string{"attr1": "attr2"})
+
+	assert.Equal(t, []string{"attr2", "attr3"}, *config.Filters[0].Values)
+	assert.Equal(t, []string{"attr1"}, *config.Filters[1].Values)
+	assert.Equal(t, "attr2", config.Groups[0].ID)
+	assert.Equal(t, "group1", config.Splits[0].ID)
+	assert.Equal(t, "attr2", config.Splits[0].Origin.ID)
+	assert.Equal(t, "attr3", config.Splits[0].Targets[0].ID)
+	assert.Equal(t, "attr2", config.Splits[0].Targets[1].ID)
+
+	// the original filter values are not changed
+	assert.Equal(t, []string{"attr1", "attr3"}, filterValues)
+}
@@ -0,0 +1,16 @@
+//go:generate mockery --output=../mocks --all
+package iface
+
+import (
+	"context"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/service"
+)
+
+type IDashboardsService interface {
+	ListDashboards(ctx context.Context, args service.ExternalAPIListArgsReq) (*service.ExternalDashboardList, error)
+	GetDashboard(ctx context.Context, customerID string, email string, dashboardID string) (*service.DashboardAPI, error)
+	GetWidgetData(ctx context.Context, customerID string, email string, dashboardID string, reportID string) (*service.WidgetDataAPI, error)
+	ExportDashboard(ctx context.Context, customerID string, email string, dashboardID string) (*service.DashboardExport, error)
+	ImportDashboard(ctx context.Context, args service.ExternalAPIImportArgsReq) service.ExternalAPIImportResp
+}
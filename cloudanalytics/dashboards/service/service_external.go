@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/doitintl/customerapi"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/domain"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard"
+)
+
+// customerDashboard is a dashboard the caller can access, with its external type.
+type customerDashboard struct {
+	*dashboard.Dashboard
+	dashboardType string
+}
+
+func (s *DashboardsService) ListDashboards(ctx context.Context, args ExternalAPIListArgsReq) (*ExternalDashboardList, error) {
+	dashboards, err := s.listDashboards(ctx, args.CustomerID, args.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	apiDashboards := toListDashboardAPI(dashboards)
+
+	filteredDashboards := customerapi.FilterAPIList(apiDashboards, args.Filters)
+
+	sortedDashboards, err := customerapi.SortAPIList(filteredDashboards, args.SortBy, args.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	page, token, err := customerapi.GetEncodedAPIPage(args.MaxResults, args.NextPageToken, sortedDashboards)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExternalDashboardList{
+		PageToken:  token,
+		RowCount:   len(page),
+		Dashboards: page,
+	}, nil
+}
+
+func (s *DashboardsService) GetDashboard(ctx context.Context, customerID string, email string, dashboardID string) (*DashboardAPI, error) {
+	d, err := s.getDashboard(ctx, customerID, email, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDashboardAPI(d), nil
+}
+
+// GetWidgetData returns the cached data of a report widget of the dashboard, as it was saved by the
+// last widget refresh. The data of the customer widget is returned, organization widgets are not exposed.
+func (s *DashboardsService) GetWidgetData(
+	ctx context.Context,
+	customerID string,
+	email string,
+	dashboardID string,
+	reportID string,
+) (*WidgetDataAPI, error) {
+	d, err := s.getDashboard(ctx, customerID, email, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasReportWidget(d.Dashboard, reportID) {
+		return nil, domain.ErrWidgetNotFound
+	}
+
+	wr, err := s.widgetService.GetWidgetReport(ctx, customerID, "", reportID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, domain.ErrWidgetDataNotFound
+		}
+
+		return nil, err
+	}
+
+	return &WidgetDataAPI{
+		ReportID:      reportID,
+		Name:          wr.Name,
+		Description:   wr.Description,
+		Type:          wr.Type,
+		TimeRefreshed: wr.TimeRefreshed.UnixMilli(),
+		ExpireBy:      wr.ExpireBy.UnixMilli(),
+		Rows:          wr.Data.Rows,
+		ForecastRows:  wr.Data.ForecastRows,
+	}, nil
+}
+
+// listDashboards returns the dashboards of the customer the caller can access: the dashboards the caller
+// owns, the dashboards that are shared with the customer users and the preset dashboards attached to the customer.
+func (s *DashboardsService) listDashboards(ctx context.Context, customerID string, email string) ([]*customerDashboard, error) {
+	userDashboards, err := s.dashboardsDAL.ListCustomerDashboards(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	presetDashboards, err := s.publicDashboardsDAL.ListCustomerDashboards(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboards := make([]*customerDashboard, 0, len(userDashboards)+len(presetDashboards))
+
+	for _, d := range userDashboards {
+		if d.Email != email && !d.IsPublic {
+			continue
+		}
+
+		dashboards = append(dashboards, &customerDashboard{d, domain.DashboardTypeCustom})
+	}
+
+	for _, d := range presetDashboards {
+		dashboards = append(dashboards, &customerDashboard{d, domain.DashboardTypePreset})
+	}
+
+	return dashboards, nil
+}
+
+func (s *DashboardsService) getDashboard(ctx context.Context, customerID string, email string, dashboardID string) (*customerDashboard, error) {
+	dashboards, err := s.listDashboards(ctx, customerID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range dashboards {
+		if d.ID == dashboardID {
+			return d, nil
+		}
+	}
+
+	return nil, domain.ErrNotFound
+}
+
+func hasReportWidget(d *dashboard.Dashboard, reportID string) bool {
+	for _, w := range d.Widgets {
+		if id, ok := domain.WidgetReportID(w); ok && id == reportID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func reportIDs(d *dashboard.Dashboard) []string {
+	ids := make([]string, 0, len(d.Widgets))
+
+	for _, w := range d.Widgets {
+		if id, ok := domain.WidgetReportID(w); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func toDashboardAPI(d *customerDashboard) *DashboardAPI {
+	widgets := make([]*DashboardWidgetAPI, len(d.Widgets))
+
+	for i, w := range d.Widgets {
+		reportID, _ := domain.WidgetReportID(w)
+
+		widgets[i] = &DashboardWidgetAPI{
+			Name:      w.Name,
+			ReportID:  reportID,
+			CardWidth: w.CardWidth,
+			Visible:   w.Visible,
+			State:     string(w.State),
+		}
+	}
+
+	return &DashboardAPI{
+		ID:           d.ID,
+		Name:         d.Name,
+		Type:         d.dashboardType,
+		Owner:        d.Email,
+		IsPublic:     d.IsPublic,
+		WidgetHeight: d.WidgetHeight,
+		Widgets:      widgets,
+	}
+}
+
+func toListDashboardAPI(dashboards []*customerDashboard) []customerapi.SortableItem {
+	apiDashboards := make([]customerapi.SortableItem, len(dashboards))
+
+	for i, d := range dashboards {
+		apiDashboards[i] = ListDashboardAPI{
+			ID:        d.ID,
+			Name:      d.Name,
+			Type:      d.dashboardType,
+			Owner:     d.Email,
+			IsPublic:  d.IsPublic,
+			ReportIDs: reportIDs(d.Dashboard),
+		}
+	}
+
+	return apiDashboards
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/domain"
+	domainWidget "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/widget/domain"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard"
+	dashboardMocks "github.com/doitintl/hello/scheduled-tasks/dashboard/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+const (
+	customerID  = "customer1"
+	email       = "user@example.com"
+	userID      = "user1"
+	dashboardID = "dashboard1"
+	reportID    = "report1"
+)
+
+type widgetServiceMock struct {
+	getWidgetReportFunc func(ctx context.Context, customerID, orgID, reportID string) (*domainWidget.WidgetReport, error)
+}
+
+func (w widgetServiceMock) GetWidgetReport(ctx context.Context, customerID, orgID, reportID string) (*domainWidget.WidgetReport, error) {
+	return w.getWidgetReportFunc(ctx, customerID, orgID, reportID)
+}
+
+func testDashboard(id string, owner string, isPublic bool) *dashboard.Dashboard {
+	return &dashboard.Dashboard{
+		ID:         id,
+		Name:       "Dashboard " + id,
+		CustomerID: customerID,
+		Email:      owner,
+		IsPublic:   isPublic,
+		Widgets: []dashboard.DashboardWidget{
+			{Name: domain.ReportWidgetName(customerID, reportID), Visible: true, CardWidth: 4},
+			{Name: "supportGraph", Visible: true},
+		},
+	}
+}
+
+func TestDashboardsService_GetDashboard(t *testing.T) {
+	userDashboards := []*dashboard.Dashboard{
+		testDashboard("own", email, false),
+		testDashboard("shared", "other@example.com", true),
+		testDashboard("private", "other@example.com", false),
+	}
+	presetDashboards := []*dashboard.Dashboard{testDashboard("preset", "", false)}
+
+	tests := []struct {
+		name         string
+		dashboardID  string
+		expectedType string
+		expectedErr  error
+	}{
+		{
+			name:         "own dashboard",
+			dashboardID:  "own",
+			expectedType: domain.DashboardTypeCustom,
+		},
+		{
+			name:         "dashboard shared with the customer",
+			dashboardID:  "shared",
+			expectedType: domain.DashboardTypeCustom,
+		},
+		{
+			name:         "preset dashboard",
+			dashboardID:  "preset",
+			expectedType: domain.DashboardTypePreset,
+		},
+		{
+			name:        "private dashboard of another user",
+			dashboardID: "private",
+			expectedErr: domain.ErrNotFound,
+		},
+		{
+			name:        "dashboard does not exist",
+			dashboardID: "missing",
+			expectedErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dashboardsDAL := dashboardMocks.NewDashboards(t)
+			publicDashboardsDAL := dashboardMocks.NewPublicDashboards(t)
+
+			dashboardsDAL.On("ListCustomerDashboards", mock.Anything, customerID).Return(userDashboards, nil)
+			publicDashboardsDAL.On("ListCustomerDashboards", mock.Anything, customerID).Return(presetDashboards, nil)
+
+			s := &DashboardsService{
+				loggerProvider:      logger.FromContext,
+				dashboardsDAL:       dashboardsDAL,
+				publicDashboardsDAL: publicDashboardsDAL,
+			}
+
+			d, err := s.GetDashboard(context.Background(), customerID, email, tt.dashboardID)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.dashboardID, d.ID)
+			assert.Equal(t, tt.expectedType, d.Type)
+			assert.Len(t, d.Widgets, 2)
+			assert.Equal(t, reportID, d.Widgets[0].ReportID)
+			assert.Empty(t, d.Widgets[1].ReportID)
+		})
+	}
+}
+
+func TestDashboardsService_GetWidgetData(t *testing.T) {
+	timeRefreshed := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		reportID     string
+		widgetReport *domainWidget.WidgetReport
+		widgetErr    error
+		expectedErr  error
+	}{
+		{
+			name:     "success",
+			reportID: reportID,
+			widgetReport: &domainWidget.WidgetReport{
+				Name:          "Monthly cost",
+				TimeRefreshed: timeRefreshed,
+			},
+		},
+		{
+			name:        "report is not on the dashboard",
+			reportID:    "report2",
+			expectedErr: domain.ErrWidgetNotFound,
+		},
+		{
+			name:        "widget was not refreshed yet",
+			reportID:    reportID,
+			widgetErr:   status.Error(codes.NotFound, "not found"),
+			expectedErr: domain.ErrWidgetDataNotFound,
+		},
+		{
+			name:        "error getting widget",
+			reportID:    reportID,
+			widgetErr:   errors.New("error"),
+			expectedErr: errors.New("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dashboardsDAL := dashboardMocks.NewDashboards(t)
+			publicDashboardsDAL := dashboardMocks.NewPublicDashboards(t)
+
+			dashboardsDAL.On("ListCustomerDashboards", mock.Anything, customerID).
+				Return([]*dashboard.Dashboard{testDashboard(dashboardID, email, false)}, nil)
+			publicDashboardsDAL.On("ListCustomerDashboards", mock.Anything, customerID).
+				Return([]*dashboard.Dashboard{}, nil)
+
+			s := &DashboardsService{
+				loggerProvider:      logger.FromContext,
+				dashboardsDAL:       dashboardsDAL,
+				publicDashboardsDAL: publicDashboardsDAL,
+				widgetService: widgetServiceMock{
+					getWidgetReportFunc: func(ctx context.Context, c, orgID, r string) (*domainWidget.WidgetReport, error) {
+						assert.Equal(t, customerID, c)
+						assert.Equal(t, reportID, r)
+
+						return tt.widgetReport, tt.widgetErr
+					},
+				},
+			}
+
+			data, err := s.GetWidgetData(context.Background(), customerID, email, dashboardID, tt.reportID)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, reportID, data.ReportID)
+			assert.Equal(t, "Monthly cost", data.Name)
+			assert.Equal(t, timeRefreshed.UnixMilli(), data.TimeRefreshed)
+		})
+	}
+}
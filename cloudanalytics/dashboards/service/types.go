@@ -0,0 +1,153 @@
+package service
+
+import (
+	"cloud.google.com/go/firestore"
+
+	"github.com/doitintl/customerapi"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/externalreport"
+)
+
+type DashboardWidgetAPI struct {
+	// Widget name
+	Name string `json:"name"`
+	// ID of the report shown by the widget. Empty for widgets that do not show a report
+	ReportID string `json:"reportId,omitempty"`
+	// Width of the widget card
+	CardWidth interface{} `json:"cardWidth"`
+	// Whether the widget is visible
+	Visible bool `json:"visible"`
+	// Refresh state of the widget data, one of: ["processing", "success", "failed"]
+	State string `json:"state,omitempty"`
+}
+
+type DashboardAPI struct {
+	// Dashboard ID
+	ID string `json:"id"`
+	// Dashboard name
+	Name string `json:"name"`
+	// Type of the dashboard can be either "preset" or "custom"
+	Type string `json:"type"`
+	// Dashboard owner
+	Owner string `json:"owner"`
+	// Whether the dashboard is shared with all the users of the customer
+	IsPublic bool `json:"isPublic"`
+	// Height of the widgets
+	WidgetHeight *float64 `json:"widgetHeight,omitempty"`
+	// Widgets of the dashboard in layout order
+	Widgets []*DashboardWidgetAPI `json:"widgets"`
+}
+
+type ListDashboardAPI struct {
+	// Dashboard ID
+	ID string `sortKey:"id" json:"id"`
+	// Dashboard name
+	Name string `sortKey:"name" json:"name"`
+	// Type of the dashboard can be either "preset" or "custom"
+	Type string `sortKey:"type" json:"type"`
+	// Dashboard owner
+	Owner string `json:"owner"`
+	// Whether the dashboard is shared with all the users of the customer
+	IsPublic bool `json:"isPublic"`
+	// IDs of the reports shown on the dashboard
+	ReportIDs []string `json:"reportIds"`
+}
+
+func (d ListDashboardAPI) GetID() string {
+	return d.ID
+}
+
+type ExternalDashboardList struct {
+	// Page token, returned by a previous call, to request the next page of results
+	PageToken string `json:"pageToken,omitempty"`
+	// Dashboards rows count
+	RowCount int `json:"rowCount"`
+	// Array of Dashboards
+	Dashboards []customerapi.SortableItem `json:"dashboards"`
+}
+
+type WidgetDataAPI struct {
+	// ID of the report shown by the widget
+	ReportID string `json:"reportId"`
+	// Report name
+	Name string `json:"name"`
+	// Report description
+	Description string `json:"description"`
+	// Report type
+	Type string `json:"type"`
+	// The time the widget data was last refreshed, in milliseconds since the epoch
+	TimeRefreshed int64 `json:"timeRefreshed"`
+	// The time the widget data expires, in milliseconds since the epoch
+	ExpireBy int64 `json:"expireBy"`
+	// Cached report rows
+	Rows map[string]interface{} `json:"rows"`
+	// Cached forecast rows
+	ForecastRows map[string]interface{} `json:"forecastRows,omitempty"`
+}
+
+// DashboardExport is a dashboard and the config of its reports, that can be imported
+// by another customer or organization.
+type DashboardExport struct {
+	// Version of the export format
+	Version int `json:"version" binding:"required"`
+	// Dashboard layout
+	Dashboard *ExportedDashboard `json:"dashboard" binding:"required"`
+	// Config of the reports shown on the dashboard
+	Reports []*ExportedReport `json:"reports" binding:"dive"`
+}
+
+type ExportedDashboard struct {
+	// Dashboard name
+	Name string `json:"name" binding:"required"`
+	// Height of the widgets
+	WidgetHeight *float64 `json:"widgetHeight,omitempty"`
+	// Report widgets of the dashboard in layout order
+	Widgets []*ExportedWidget `json:"widgets" binding:"dive"`
+}
+
+type ExportedWidget struct {
+	// Key of the report in the export reports
+	ReportKey string `json:"reportKey" binding:"required"`
+	// Width of the widget card
+	CardWidth interface{} `json:"cardWidth"`
+	// Whether the widget is visible
+	Visible bool `json:"visible"`
+}
+
+type ExportedReport struct {
+	// Key of the report, referenced by the widgets
+	Key string `json:"key" binding:"required"`
+	// Report config
+	Report *externalreport.ExternalReport `json:"report" binding:"required"`
+}
+
+type DashboardImportRequest struct {
+	// Exported dashboard
+	Export *DashboardExport `json:"export" binding:"required"`
+	// Maps attribution IDs used by the exported reports to attribution IDs of the importing customer.
+	// Attributions that are not mapped are used as is, e.g. preset attributions.
+	AttributionMapping map[string]string `json:"attributionMapping"`
+}
+
+type ExternalAPIListArgsReq struct {
+	CustomerID    string
+	Email         string
+	SortBy        string
+	SortOrder     firestore.Direction
+	Filters       []customerapi.Filter
+	MaxResults    int
+	NextPageToken string
+}
+
+type ExternalAPIImportArgsReq struct {
+	ImportRequest *DashboardImportRequest
+	CustomerID    string
+	Email         string
+	UserID        string
+}
+
+type ExternalAPIImportResp struct {
+	Dashboard        *DashboardAPI
+	ValidationErrors []errormsg.ErrorMsg
+	Error            error
+}
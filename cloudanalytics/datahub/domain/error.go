@@ -20,6 +20,8 @@ var (
 	ErrDatasetNameCanNotBeEmpty        = errors.New("dataset name can not be empty")
 
 	ErrInternalDatahub = errors.New("internal datahub error")
+
+	ErrInvalidEventsStream = errors.New("events stream could not be read")
 )
 
 const ParsingRequestErrorTpl = "parsing request error: %v"
@@ -27,6 +27,11 @@ const (
 
 	InvalidColumnsLengthMsg ErrMsg = "number of columns does not match the number of header fields"
 	InvalidFieldTypeMsg     ErrMsg = "invalid field type"
+
+	InvalidCSVRowMsg      ErrMsg = "row is not valid CSV"
+	InvalidJSONRowMsg     ErrMsg = "row is not a valid JSON event"
+	InvalidEventCloudMsg  ErrMsg = "event cloud does not match the dataset"
+	MandatoryUsageDateMsg ErrMsg = "event time must be provided"
 )
 
 type ErrTpl = string
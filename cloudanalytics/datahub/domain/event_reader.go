@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+)
+
+// EventReader reads the events of a stream one row at a time, so the stream does not have to fit in memory
+type EventReader interface {
+	// Read returns the next event of the stream, or the validation errors of the row when it is not valid.
+	// io.EOF is returned at the end of the stream
+	Read() (*Event, []errormsg.ErrorMsg, error)
+	// Row returns the position of the last row read
+	Row() int
+	// Close closes the source of the stream when it can be closed
+	Close() error
+}
+
+// CSVEventReader reads events from CSV rows, the first row is the schema of the events
+type CSVEventReader struct {
+	dataset string
+	schema  Schema
+	source  io.Reader
+	r       *csv.Reader
+	row     int
+}
+
+// NewCSVEventReader reads the schema from the first row of the stream and returns a reader of the following rows.
+// Schema validation errors are returned when the first row is not a valid schema
+func NewCSVEventReader(dataset string, r io.Reader) (*CSVEventReader, []errormsg.ErrorMsg, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+
+	rawSchema, err := csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, []errormsg.ErrorMsg{{Field: RawSchemaField, Message: InvalidSchemaFieldMsg}}, nil
+		}
+
+		return nil, nil, err
+	}
+
+	schema, validationErrs := NewSchema(rawSchema)
+	if validationErrs != nil {
+		return nil, validationErrs, nil
+	}
+
+	return &CSVEventReader{
+		dataset: dataset,
+		schema:  *schema,
+		source:  r,
+		r:       csvReader,
+	}, nil, nil
+}
+
+func (cr *CSVEventReader) Read() (*Event, []errormsg.ErrorMsg, error) {
+	rawEvent, err := cr.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil, nil, err
+		}
+
+		cr.row++
+
+		return nil, []errormsg.ErrorMsg{{
+			Field:   rowField(cr.row),
+			Message: InvalidCSVRowMsg,
+		}}, nil
+	}
+
+	cr.row++
+
+	event, validationErrs := newEventFromRawEvent(cr.dataset, cr.schema, cr.row, rawEvent)
+	if validationErrs != nil {
+		return nil, validationErrs, nil
+	}
+
+	return event, nil, nil
+}
+
+func (cr *CSVEventReader) Row() int {
+	return cr.row
+}
+
+func (cr *CSVEventReader) Close() error {
+	return closeSource(cr.source)
+}
+
+// NDJSONEventReader reads events from newline delimited JSON, one event per line
+type NDJSONEventReader struct {
+	dataset string
+	source  io.Reader
+	r       *bufio.Reader
+	row     int
+}
+
+func NewNDJSONEventReader(dataset string, r io.Reader) *NDJSONEventReader {
+	return &NDJSONEventReader{
+		dataset: dataset,
+		source:  r,
+		r:       bufio.NewReader(r),
+	}
+}
+
+func (nr *NDJSONEventReader) Read() (*Event, []errormsg.ErrorMsg, error) {
+	for {
+		line, err := nr.r.ReadBytes('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+			return nil, nil, err
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		nr.row++
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, []errormsg.ErrorMsg{{
+				Field:   rowField(nr.row),
+				Message: InvalidJSONRowMsg,
+			}}, nil
+		}
+
+		validEvent, validationErrs := validateEvent(nr.dataset, nr.row, &event)
+		if validationErrs != nil {
+			return nil, validationErrs, nil
+		}
+
+		return validEvent, nil, nil
+	}
+}
+
+func (nr *NDJSONEventReader) Row() int {
+	return nr.row
+}
+
+func (nr *NDJSONEventReader) Close() error {
+	return closeSource(nr.source)
+}
+
+func closeSource(source io.Reader) error {
+	if closer, ok := source.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// validateEvent turns an event that was not read through a schema back into the schema and the row it would
+// have been read from, so it goes through the same rules as the CSV rows
+func validateEvent(dataset string, rowPos int, event *Event) (*Event, []errormsg.ErrorMsg) {
+	var errs []errormsg.ErrorMsg
+
+	if event.Cloud != nil && *event.Cloud != dataset {
+		errs = append(errs, errormsg.ErrorMsg{
+			Field:   rowField(rowPos),
+			Message: InvalidEventCloudMsg,
+		})
+	}
+
+	rawSchema, rawEvent := toRawEvent(event)
+
+	schema, schemaErrs := NewSchema(rawSchema)
+	for _, schemaErr := range schemaErrs {
+		errs = append(errs, errormsg.ErrorMsg{
+			Field:   rowField(rowPos),
+			Message: schemaErr.Message,
+		})
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return newEventFromRawEvent(dataset, *schema, rowPos, rawEvent)
+}
+
+// toRawEvent returns the raw schema and the raw values of the fields of the event
+func toRawEvent(event *Event) ([]string, []string) {
+	var rawSchema, rawEvent []string
+
+	if !event.Time.IsZero() {
+		rawSchema = append(rawSchema, SchemaFieldTypeUsageDate)
+		rawEvent = append(rawEvent, event.Time.UTC().Format(time.RFC3339Nano))
+	}
+
+	if event.ID != nil {
+		rawSchema = append(rawSchema, SchemaFieldTypeEventID)
+		rawEvent = append(rawEvent, *event.ID)
+	}
+
+	for _, dimension := range event.Dimensions {
+		if dimension == nil {
+			continue
+		}
+
+		rawSchema = append(rawSchema, rawDimensionField(dimension))
+
+		if dimension.Value == nil {
+			rawEvent = append(rawEvent, "")
+		} else {
+			rawEvent = append(rawEvent, fmt.Sprint(dimension.Value))
+		}
+	}
+
+	for _, metric := range event.Metrics {
+		if metric == nil {
+			continue
+		}
+
+		rawSchema = append(rawSchema, SchemaFieldTypeMetric+"."+metric.Type)
+		rawEvent = append(rawEvent, strconv.FormatFloat(metric.Value, 'f', -1, 64))
+	}
+
+	return rawSchema, rawEvent
+}
+
+func rawDimensionField(dimension *Dimension) string {
+	switch dimension.Type {
+	case SchemaFieldTypeFixed:
+		if isFixedDimension(dimension.Key) {
+			return dimension.Key
+		}
+	case SchemaFieldTypeLabel, SchemaFieldTypeProjectLabel:
+		return dimension.Type + "." + dimension.Key
+	}
+
+	// not a field of the schema, so NewSchema rejects it
+	return SchemaFieldTypeFixed + "." + dimension.Key
+}
+
+func rowField(rowPos int) string {
+	return "row: " + strconv.Itoa(rowPos)
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+)
+
+type readResult struct {
+	event *Event
+	errs  []errormsg.ErrorMsg
+}
+
+func readAll(t *testing.T, r EventReader) []readResult {
+	var results []readResult
+
+	for {
+		event, errs, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return results
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results = append(results, readResult{event: event, errs: errs})
+	}
+}
+
+func TestCSVEventReader(t *testing.T) {
+	dataset := "datadog"
+
+	t.Run("reads rows with the schema of the first row", func(t *testing.T) {
+		stream := "usage_date,project_id,label.house,metric.cost\n" +
+			"2024-03-01T00:00:00Z,pr1,adoption,12\n" +
+			"2024-03-02T00:00:00Z,pr2,another_house\n" +
+			"2024-03-03T00:00:00Z,pr3,adoption,abc\n"
+
+		r, validationErrs, err := NewCSVEventReader(dataset, strings.NewReader(stream))
+		assert.NoError(t, err)
+		assert.Nil(t, validationErrs)
+
+		results := readAll(t, r)
+		assert.Len(t, results, 3)
+		assert.Equal(t, 3, r.Row())
+
+		assert.Nil(t, results[0].errs)
+		assert.Equal(t, &Event{
+			Cloud: &dataset,
+			Time:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Dimensions: []*Dimension{
+				{Key: "project_id", Type: SchemaFieldTypeFixed, Value: "pr1"},
+				{Key: "house", Type: SchemaFieldTypeLabel, Value: "adoption"},
+			},
+			Metrics: []*Metric{
+				{Type: "cost", Value: 12},
+			},
+		}, results[0].event)
+
+		assert.Equal(t, []errormsg.ErrorMsg{{Field: "row: 2", Message: InvalidColumnsLengthMsg}}, results[1].errs)
+		assert.Len(t, results[2].errs, 1)
+		assert.Equal(t, "row: 3", results[2].errs[0].Field)
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		stream := "project_id,metric.cost\npr1,12\n"
+
+		r, validationErrs, err := NewCSVEventReader(dataset, strings.NewReader(stream))
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+		assert.Equal(t, []errormsg.ErrorMsg{{Field: UsageDateSchemaField, Message: MandatoryFieldNotExistMsg}}, validationErrs)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		r, validationErrs, err := NewCSVEventReader(dataset, strings.NewReader(""))
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+		assert.Equal(t, []errormsg.ErrorMsg{{Field: RawSchemaField, Message: InvalidSchemaFieldMsg}}, validationErrs)
+	})
+}
+
+func TestNDJSONEventReader(t *testing.T) {
+	dataset := "datadog"
+	otherDataset := "other"
+	id := "73f8b9da-ebb2-4046-8226-1015ee94b499"
+
+	stream := `{"id":"` + id + `","time":"2024-03-01T00:00:00Z","dimensions":[{"key":"project_id","type":"fixed","value":"pr1"}],"metrics":[{"type":"cost","value":12}]}
+
+{"time":"2024-03-01T00:00:00Z","dimensions":[{"key":"house","type":"label","value":"adoption"}]}
+not json
+{"cloud":"` + otherDataset + `","time":"2024-03-01T00:00:00Z","dimensions":[{"key":"unknown","type":"fixed","value":"x"}],"metrics":[{"type":"cost","value":1}]}
+{"time":"2024-03-02T00:00:00Z","dimensions":[{"key":"app","type":"project_label","value":"web"}],"metrics":[{"type":"cost","value":3}]}
+{"dimensions":[{"key":"","type":"label","value":"x"}],"metrics":[{"type":"cost","value":1}]}`
+
+	r := NewNDJSONEventReader(dataset, strings.NewReader(stream))
+
+	results := readAll(t, r)
+	assert.Len(t, results, 6)
+	assert.Equal(t, 6, r.Row())
+
+	assert.Nil(t, results[0].errs)
+	assert.Equal(t, &Event{
+		Cloud: &dataset,
+		ID:    &id,
+		Time:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Dimensions: []*Dimension{
+			{Key: "project_id", Type: SchemaFieldTypeFixed, Value: "pr1"},
+		},
+		Metrics: []*Metric{
+			{Type: "cost", Value: 12},
+		},
+	}, results[0].event)
+
+	assert.Equal(t, []errormsg.ErrorMsg{{Field: "row: 2", Message: MetricNotExistMsg}}, results[1].errs)
+	assert.Equal(t, []errormsg.ErrorMsg{{Field: "row: 3", Message: InvalidJSONRowMsg}}, results[2].errs)
+	assert.Equal(t, []errormsg.ErrorMsg{
+		{Field: "row: 4", Message: InvalidEventCloudMsg},
+		{Field: "row: 4", Message: InvalidSchemaFieldTypeMsg},
+		{Field: "row: 4", Message: DimensionNotExistMsg},
+	}, results[3].errs)
+
+	assert.Nil(t, results[4].errs)
+	assert.Equal(t, &dataset, results[4].event.Cloud)
+
+	assert.Equal(t, []errormsg.ErrorMsg{
+		{Field: "row: 6", Message: InvalidSchemaLabelKeyMsg},
+		{Field: "row: 6", Message: MandatoryFieldNotExistMsg},
+	}, results[5].errs)
+}
+
+type testSource struct {
+	io.Reader
+	closed bool
+}
+
+func (s *testSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestEventReader_Close(t *testing.T) {
+	csvSource := &testSource{Reader: strings.NewReader("usage_date,project_id,metric.cost\n")}
+
+	csvReader, _, err := NewCSVEventReader("datadog", csvSource)
+	assert.NoError(t, err)
+	assert.NoError(t, csvReader.Close())
+	assert.True(t, csvSource.closed)
+
+	ndjsonSource := &testSource{Reader: strings.NewReader("")}
+
+	assert.NoError(t, NewNDJSONEventReader("datadog", ndjsonSource).Close())
+	assert.True(t, ndjsonSource.closed)
+
+	assert.NoError(t, NewNDJSONEventReader("datadog", strings.NewReader("")).Close())
+}
+
+func TestStreamBatchRes_AddError(t *testing.T) {
+	var batch StreamBatchRes
+
+	for i := 0; i < maxErrsLimit+10; i++ {
+		batch.AddError(errormsg.ErrorMsg{Field: rowField(i + 1), Message: InvalidJSONRowMsg})
+	}
+
+	assert.Len(t, batch.Errors, maxErrsLimit)
+}
@@ -104,3 +104,45 @@ func TestRawEventsReq(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamEventsReq(t *testing.T) {
+	tests := []struct {
+		name string
+		req  StreamEventsReq
+		want []errormsg.ErrorMsg
+	}{
+		{
+			name: "valid ndjson request",
+			req: StreamEventsReq{
+				Dataset:  "datadog",
+				Source:   StreamSourceNDJSON,
+				Filename: "usage.ndjson",
+			},
+		},
+		{
+			name: "valid gzip csv request",
+			req: StreamEventsReq{
+				Dataset:  "datadog",
+				Source:   StreamSourceCSVGzip,
+				Filename: "usage.csv.gz",
+			},
+		},
+		{
+			name: "unsupported source and missing fields",
+			req: StreamEventsReq{
+				Source: "csv",
+			},
+			want: []errormsg.ErrorMsg{
+				{Field: SourceField, Message: InvalidSourceTypeMsg},
+				{Field: DatasetField, Message: InvalidDatasetFieldMsg},
+				{Field: FilenameField, Message: InvalidFilenameFieldMsg},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.req.Validate())
+		})
+	}
+}
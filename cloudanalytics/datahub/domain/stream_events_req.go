@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+)
+
+type StreamSource = string
+
+const (
+	StreamSourceNDJSON  StreamSource = "ndjson"
+	StreamSourceCSVGzip StreamSource = "csv.gz"
+)
+
+// StreamEventsReq describes an events stream, the events themselves are read from the request body
+type StreamEventsReq struct {
+	Dataset  string `form:"dataset"`
+	Source   string `form:"source"`
+	Filename string `form:"filename"`
+	Execute  bool   `form:"execute"`
+}
+
+func (req *StreamEventsReq) Validate() []errormsg.ErrorMsg {
+	var errs []errormsg.ErrorMsg
+
+	switch req.Source {
+	case "":
+		errs = append(errs, errormsg.ErrorMsg{
+			Field:   SourceField,
+			Message: EmptySourceTypeMsg,
+		})
+	case StreamSourceNDJSON, StreamSourceCSVGzip:
+	default:
+		errs = append(errs, errormsg.ErrorMsg{
+			Field:   SourceField,
+			Message: InvalidSourceTypeMsg,
+		})
+	}
+
+	if req.Dataset == "" {
+		errs = append(errs, errormsg.ErrorMsg{
+			Field:   DatasetField,
+			Message: InvalidDatasetFieldMsg,
+		})
+	}
+
+	if req.Filename == "" {
+		errs = append(errs, errormsg.ErrorMsg{
+			Field:   FilenameField,
+			Message: InvalidFilenameFieldMsg,
+		})
+	}
+
+	return errs
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+)
+
+// StreamBatchRes is the report of a batch of rows sent to the ingest API
+type StreamBatchRes struct {
+	Batch    int                 `json:"batch"`
+	FirstRow int                 `json:"firstRow"`
+	LastRow  int                 `json:"lastRow"`
+	Accepted int                 `json:"accepted"`
+	Rejected int                 `json:"rejected"`
+	Errors   []errormsg.ErrorMsg `json:"errors,omitempty"`
+}
+
+// AddError records a row validation error, up to maxErrsLimit errors are kept per batch
+func (b *StreamBatchRes) AddError(errs ...errormsg.ErrorMsg) {
+	for _, err := range errs {
+		if len(b.Errors) >= maxErrsLimit {
+			return
+		}
+
+		b.Errors = append(b.Errors, err)
+	}
+}
+
+type StreamEventsRes struct {
+	Accepted int              `json:"accepted"`
+	Rejected int              `json:"rejected"`
+	Execute  bool             `json:"execute"`
+	Batches  []StreamBatchRes `json:"batches"`
+}
@@ -188,6 +188,60 @@ func (h *DataHub) AddRawEvents(ctx *gin.Context) error {
 	return web.Respond(ctx, resp, http.StatusOK)
 }
 
+// StreamEvents ingests the events streamed in the request body as NDJSON or gzip compressed CSV,
+// and responds with the report of the accepted and rejected rows of every batch.
+func (h *DataHub) StreamEvents(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+
+	customerID := ctx.Param("customerID")
+	if customerID == "" {
+		return web.NewRequestError(ErrMissingCustomerID, http.StatusBadRequest)
+	}
+
+	email := ctx.GetString("email")
+	if email == "" {
+		return web.NewRequestError(nil, http.StatusBadRequest)
+	}
+
+	l.SetLabels(map[string]string{
+		logger.LabelCustomerID: customerID,
+	})
+
+	var streamEventsReq domain.StreamEventsReq
+
+	if err := ctx.ShouldBindQuery(&streamEventsReq); err != nil {
+		l.Errorf(domain.ParsingRequestErrorTpl, err)
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	if validationErrs := streamEventsReq.Validate(); validationErrs != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": validationErrs})
+		return nil
+	}
+
+	res, validationErrs, err := h.datahubService.StreamEvents(
+		ctx,
+		customerID,
+		email,
+		streamEventsReq,
+		ctx.Request.Body,
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidEventsStream) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	if validationErrs != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": validationErrs})
+		return nil
+	}
+
+	return web.Respond(ctx, res, http.StatusOK)
+}
+
 // DeleteCustomerData deletes all the customer's DataHub API data.
 func (h *DataHub) DeleteCustomerData(ctx *gin.Context) error {
 	ctx.Set(domainOrigin.QueryOriginCtxKey, domainOrigin.QueryOriginDataHub)
@@ -1152,3 +1152,116 @@ func TestDataHubAPIHandler_AddRawEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestDataHubAPIHandler_StreamEvents(t *testing.T) {
+	type fields struct {
+		loggerProvider logger.Provider
+		service        *mocks.DataHubService
+	}
+
+	const (
+		customerID = "some-customer-id"
+		email      = "test@doit.com"
+		stream     = `{"time":"2024-03-01T00:00:00Z","dimensions":[{"key":"project_id","type":"fixed","value":"pr1"}],"metrics":[{"type":"cost","value":12}]}`
+	)
+
+	streamEventsReq := domain.StreamEventsReq{
+		Dataset:  "some dataset name",
+		Source:   domain.StreamSourceNDJSON,
+		Filename: "usage.ndjson",
+		Execute:  true,
+	}
+
+	tests := []struct {
+		name         string
+		fields       fields
+		query        string
+		wantErr      bool
+		wantedStatus int
+		on           func(*fields)
+	}{
+		{
+			name:  "successful stream ingestion",
+			query: "?dataset=some%20dataset%20name&source=ndjson&filename=usage.ndjson&execute=true",
+			on: func(f *fields) {
+				f.service.On(
+					"StreamEvents",
+					ginContextMock,
+					customerID,
+					email,
+					streamEventsReq,
+					mock.Anything,
+				).
+					Return(&domain.StreamEventsRes{Accepted: 1}, nil, nil).
+					Once()
+			},
+			wantedStatus: http.StatusOK,
+		},
+		{
+			name:         "unsupported source",
+			query:        "?dataset=some%20dataset%20name&source=csv&filename=usage.csv",
+			wantedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "stream could not be read",
+			query: "?dataset=some%20dataset%20name&source=ndjson&filename=usage.ndjson&execute=true",
+			on: func(f *fields) {
+				f.service.On(
+					"StreamEvents",
+					ginContextMock,
+					customerID,
+					email,
+					streamEventsReq,
+					mock.Anything,
+				).
+					Return(nil, nil, domain.ErrInvalidEventsStream).
+					Once()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+
+			tt.fields = fields{
+				loggerProvider: logger.FromContext,
+				service:        mocks.NewDataHubService(t),
+			}
+
+			h := &DataHub{
+				loggerProvider: tt.fields.loggerProvider,
+				datahubService: tt.fields.service,
+			}
+
+			if tt.on != nil {
+				tt.on(&tt.fields)
+			}
+
+			request := httptest.NewRequest(http.MethodPost, "/someRequest"+tt.query, strings.NewReader(stream))
+
+			ctx.Params = []gin.Param{
+				{
+					Key:   "customerID",
+					Value: customerID,
+				},
+			}
+
+			ctx.Set("email", email)
+			ctx.Request = request
+
+			respond := h.StreamEvents(ctx)
+			status := ctx.Writer.Status()
+
+			if tt.wantedStatus != 0 && tt.wantedStatus != status {
+				t.Errorf("got %v, want %v", ctx.Writer.Status(), tt.wantedStatus)
+			}
+
+			if (respond != nil) != tt.wantErr {
+				t.Errorf("DataHub.StreamEvents() error = %v, wantErr %v", respond, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -31,6 +33,9 @@ const (
 	softDeleteIntervalDays = 7
 
 	deleteCustomerDatahubDataTaskPathTemplate = "/tasks/datahub/events/customers/%s/hard"
+
+	// streamBatchSize is the number of valid events sent to the ingest API in a single request
+	streamBatchSize = 10000
 )
 
 type Service struct {
@@ -45,6 +50,7 @@ type Service struct {
 	customerDAL             customerDal.Customers
 	cloudTaskClient         cloudtasks.CloudTaskClient
 	timeNow                 func() time.Time
+	streamBatchSize         int
 }
 
 func NewService(
@@ -71,6 +77,7 @@ func NewService(
 		customerDAL:             customerDAL,
 		cloudTaskClient:         cloudTaskClient,
 		timeNow:                 func() time.Time { return time.Now().UTC() },
+		streamBatchSize:         streamBatchSize,
 	}
 }
 
@@ -385,8 +392,6 @@ func (s *Service) AddRawEvents(
 	email string,
 	rawEventsReq domain.RawEventsReq,
 ) ([]*domain.Event, []errormsg.ErrorMsg, error) {
-	l := s.loggerProvider(ctx)
-
 	rawSchema := rawEventsReq.Schema
 	source := rawEventsReq.Source
 	filename := rawEventsReq.Filename
@@ -417,6 +422,125 @@ func (s *Service) AddRawEvents(
 		Events:     events,
 	}
 
+	validationErrs, err := s.ingestEvents(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if validationErrs != nil {
+		return nil, validationErrs, nil
+	}
+
+	return events, nil, nil
+}
+
+// StreamEvents reads the events from the stream one row at a time and sends the valid events to the ingest API in
+// batches. Rows that are not valid are rejected without failing the stream, and a report is kept for every batch
+func (s *Service) StreamEvents(
+	ctx context.Context,
+	customerID string,
+	email string,
+	streamEventsReq domain.StreamEventsReq,
+	stream io.Reader,
+) (*domain.StreamEventsRes, []errormsg.ErrorMsg, error) {
+	l := s.loggerProvider(ctx)
+
+	reader, validationErrs, err := newEventReader(streamEventsReq, stream)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrInvalidEventsStream, err)
+	}
+
+	if validationErrs != nil {
+		return nil, validationErrs, nil
+	}
+
+	defer reader.Close()
+
+	res := &domain.StreamEventsRes{
+		Execute: streamEventsReq.Execute,
+		Batches: []domain.StreamBatchRes{},
+	}
+
+	batch := domain.StreamBatchRes{Batch: 1, FirstRow: 1}
+	events := make([]*domain.Event, 0, s.streamBatchSize)
+
+	flush := func() error {
+		batch.LastRow = reader.Row()
+
+		if len(events) > 0 {
+			validationErrs, err := s.ingestEvents(ctx, domain.IngestEventsInternalReq{
+				CustomerID: customerID,
+				Email:      email,
+				Source:     streamEventsReq.Source,
+				Execute:    streamEventsReq.Execute,
+				FileName:   streamEventsReq.Filename,
+				Events:     events,
+			})
+			if err != nil {
+				return err
+			}
+
+			if validationErrs != nil {
+				batch.Rejected += len(events)
+				batch.AddError(validationErrs...)
+			} else {
+				batch.Accepted += len(events)
+			}
+		}
+
+		l.Infof("datahub stream %s batch %d: %d accepted, %d rejected", streamEventsReq.Filename, batch.Batch, batch.Accepted, batch.Rejected)
+
+		res.Accepted += batch.Accepted
+		res.Rejected += batch.Rejected
+		res.Batches = append(res.Batches, batch)
+
+		batch = domain.StreamBatchRes{Batch: batch.Batch + 1, FirstRow: batch.LastRow + 1}
+		events = make([]*domain.Event, 0, s.streamBatchSize)
+
+		return nil
+	}
+
+	for {
+		event, rowErrs, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, nil, fmt.Errorf("%w: %s", domain.ErrInvalidEventsStream, err)
+		}
+
+		if rowErrs != nil {
+			batch.Rejected++
+			batch.AddError(rowErrs...)
+		} else {
+			events = append(events, event)
+		}
+
+		if len(events) == s.streamBatchSize {
+			if err := flush(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if reader.Row() >= batch.FirstRow {
+		if err := flush(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return res, nil, nil
+}
+
+// ingestEvents sends the events to the internal ingest API. Validation errors of the ingest API are returned
+// so they can be exposed to the client, other errors are not exposed
+func (s *Service) ingestEvents(
+	ctx context.Context,
+	req domain.IngestEventsInternalReq,
+) ([]errormsg.ErrorMsg, error) {
+	l := s.loggerProvider(ctx)
+
 	_, err := s.datahubInternalAPIDAL.IngestEvents(ctx, req)
 	if err != nil {
 		var webErr httpDoit.WebError
@@ -428,18 +552,42 @@ func (s *Service) AddRawEvents(
 			err := json.Unmarshal([]byte(webErr.Err.Error()), &internalErrRes)
 			if err != nil {
 				l.Warningf("Error unmarshalling response JSON: %v", err)
-				return nil, nil, errors.New("internal datahub api error")
+				return nil, errors.New("internal datahub api error")
 			}
 
-			return nil, internalErrRes.Errors, err
+			return internalErrRes.Errors, nil
 		}
 
 		l.Errorf("error occurred when calling datahubInternalApiDAL %s", webErr)
 
-		return nil, nil, domain.ErrInternalDatahub
+		return nil, domain.ErrInternalDatahub
 	}
 
-	return events, nil, nil
+	return nil, nil
+}
+
+// newEventReader returns the reader of the stream source, gzip compressed streams are decompressed while read.
+// Closing the reader closes the gzip reader of compressed streams
+func newEventReader(req domain.StreamEventsReq, stream io.Reader) (domain.EventReader, []errormsg.ErrorMsg, error) {
+	switch req.Source {
+	case domain.StreamSourceNDJSON:
+		return domain.NewNDJSONEventReader(req.Dataset, stream), nil, nil
+	case domain.StreamSourceCSVGzip:
+		gzipReader, err := gzip.NewReader(stream)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reader, validationErrs, err := domain.NewCSVEventReader(req.Dataset, gzipReader)
+		if err != nil || validationErrs != nil {
+			gzipReader.Close()
+			return nil, validationErrs, err
+		}
+
+		return reader, nil, nil
+	default:
+		return nil, []errormsg.ErrorMsg{{Field: domain.SourceField, Message: domain.InvalidSourceTypeMsg}}, nil
+	}
 }
 
 func (s *Service) CreateDataset(
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -1984,6 +1988,209 @@ func TestDataHubService_AddRawEvents(t *testing.T) {
 	}
 }
 
+func TestDataHubService_StreamEvents(t *testing.T) {
+	ctx := context.Background()
+
+	customerID := "123"
+	email := "test@doit.com"
+	dataset := "datadog"
+
+	ndjsonRow := func(project string) string {
+		return `{"time":"2024-03-01T00:00:00Z","dimensions":[{"key":"project_id","type":"fixed","value":"` + project + `"}],"metrics":[{"type":"cost","value":12}]}` + "\n"
+	}
+
+	newEvent := func(project string) *domain.Event {
+		return &domain.Event{
+			Cloud: &dataset,
+			Time:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Dimensions: []*domain.Dimension{
+				{Key: "project_id", Type: domain.SchemaFieldTypeFixed, Value: project},
+			},
+			Metrics: []*domain.Metric{
+				{Type: "cost", Value: 12},
+			},
+		}
+	}
+
+	gzipped := func(data string) io.Reader {
+		var buf bytes.Buffer
+
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		return &buf
+	}
+
+	ndjsonReq := domain.StreamEventsReq{
+		Dataset:  dataset,
+		Source:   domain.StreamSourceNDJSON,
+		Filename: "usage.ndjson",
+		Execute:  true,
+	}
+
+	csvReq := domain.StreamEventsReq{
+		Dataset:  dataset,
+		Source:   domain.StreamSourceCSVGzip,
+		Filename: "usage.csv.gz",
+	}
+
+	internalErrRes := domain.InternalErrRes{
+		Errors: []errormsg.ErrorMsg{
+			{
+				Field:   "field1",
+				Message: "field1 value is not valid",
+			},
+		},
+	}
+
+	resJSON, _ := json.Marshal(internalErrRes)
+
+	webValidationErr := httpDoit.WebError{
+		Code: http.StatusBadRequest,
+		Err:  errors.New(string(resJSON)),
+	}
+
+	tests := []struct {
+		name                   string
+		req                    domain.StreamEventsReq
+		stream                 io.Reader
+		expectedRes            *domain.StreamEventsRes
+		expectedValidationErrs []errormsg.ErrorMsg
+		expectedErr            error
+		on                     func(*datahubDalMocks.DatahubInternalAPIDAL)
+	}{
+		{
+			name:   "ndjson rows are ingested in batches and invalid rows are rejected",
+			req:    ndjsonReq,
+			stream: strings.NewReader(ndjsonRow("pr1") + "not json\n" + ndjsonRow("pr2") + ndjsonRow("pr3")),
+			on: func(m *datahubDalMocks.DatahubInternalAPIDAL) {
+				m.On("IngestEvents", testutils.ContextBackgroundMock, domain.IngestEventsInternalReq{
+					CustomerID: customerID,
+					Email:      email,
+					Source:     domain.StreamSourceNDJSON,
+					Execute:    true,
+					FileName:   "usage.ndjson",
+					Events:     []*domain.Event{newEvent("pr1"), newEvent("pr2")},
+				}).Return(nil, nil).Once()
+				m.On("IngestEvents", testutils.ContextBackgroundMock, domain.IngestEventsInternalReq{
+					CustomerID: customerID,
+					Email:      email,
+					Source:     domain.StreamSourceNDJSON,
+					Execute:    true,
+					FileName:   "usage.ndjson",
+					Events:     []*domain.Event{newEvent("pr3")},
+				}).Return(nil, nil).Once()
+			},
+			expectedRes: &domain.StreamEventsRes{
+				Accepted: 3,
+				Rejected: 1,
+				Execute:  true,
+				Batches: []domain.StreamBatchRes{
+					{
+						Batch:    1,
+						FirstRow: 1,
+						LastRow:  3,
+						Accepted: 2,
+						Rejected: 1,
+						Errors: []errormsg.ErrorMsg{
+							{Field: "row: 2", Message: domain.InvalidJSONRowMsg},
+						},
+					},
+					{
+						Batch:    2,
+						FirstRow: 4,
+						LastRow:  4,
+						Accepted: 1,
+					},
+				},
+			},
+		},
+		{
+			name:   "gzip csv batch rejected by the ingest api",
+			req:    csvReq,
+			stream: gzipped("usage_date,project_id,metric.cost\n2024-03-01T00:00:00Z,pr1,12\n"),
+			on: func(m *datahubDalMocks.DatahubInternalAPIDAL) {
+				m.On("IngestEvents", testutils.ContextBackgroundMock, domain.IngestEventsInternalReq{
+					CustomerID: customerID,
+					Email:      email,
+					Source:     domain.StreamSourceCSVGzip,
+					FileName:   "usage.csv.gz",
+					Events:     []*domain.Event{newEvent("pr1")},
+				}).Return(nil, webValidationErr).Once()
+			},
+			expectedRes: &domain.StreamEventsRes{
+				Rejected: 1,
+				Batches: []domain.StreamBatchRes{
+					{
+						Batch:    1,
+						FirstRow: 1,
+						LastRow:  1,
+						Rejected: 1,
+						Errors:   internalErrRes.Errors,
+					},
+				},
+			},
+		},
+		{
+			name:   "gzip csv with invalid schema",
+			req:    csvReq,
+			stream: gzipped("project_id,metric.cost\npr1,12\n"),
+			expectedValidationErrs: []errormsg.ErrorMsg{
+				{Field: "usage_date", Message: "field must be provided in schema"},
+			},
+		},
+		{
+			name:        "stream is not gzip compressed",
+			req:         csvReq,
+			stream:      strings.NewReader("usage_date,project_id,metric.cost\n"),
+			expectedErr: domain.ErrInvalidEventsStream,
+		},
+		{
+			name:   "internal error during ingesting events",
+			req:    ndjsonReq,
+			stream: strings.NewReader(ndjsonRow("pr1")),
+			on: func(m *datahubDalMocks.DatahubInternalAPIDAL) {
+				m.On("IngestEvents", testutils.ContextBackgroundMock, mock.AnythingOfType("domain.IngestEventsInternalReq")).
+					Return(nil, errors.New("error ingesting events")).Once()
+			},
+			expectedErr: domain.ErrInternalDatahub,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			datahubInternalAPIDAL := datahubDalMocks.NewDatahubInternalAPIDAL(t)
+
+			s := &Service{
+				loggerProvider:        logger.FromContext,
+				datahubInternalAPIDAL: datahubInternalAPIDAL,
+				streamBatchSize:       2,
+			}
+
+			if tt.on != nil {
+				tt.on(datahubInternalAPIDAL)
+			}
+
+			res, validationErrs, err := s.StreamEvents(ctx, customerID, email, tt.req, tt.stream)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedValidationErrs, validationErrs)
+			assert.Equal(t, tt.expectedRes, res)
+		})
+	}
+}
+
 func TestDataHubService_isDatasetProcessing(t *testing.T) {
 	now := time.Now()
 
@@ -3,6 +3,7 @@ package iface
 
 import (
 	"context"
+	"io"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/datahub/domain"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
@@ -49,6 +50,13 @@ type DataHubService interface {
 		email string,
 		rawEventsReq domain.RawEventsReq,
 	) ([]*domain.Event, []errormsg.ErrorMsg, error)
+	StreamEvents(
+		ctx context.Context,
+		customerID string,
+		email string,
+		streamEventsReq domain.StreamEventsReq,
+		stream io.Reader,
+	) (*domain.StreamEventsRes, []errormsg.ErrorMsg, error)
 	CreateDataset(
 		ctx context.Context,
 		customerID string,
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	io "io"
+
 	domain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/datahub/domain"
 	errormsg "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
 
@@ -201,6 +203,41 @@ func (_m *DataHubService) GetCustomerDatasets(ctx context.Context, customerID st
 	return r0, r1
 }
 
+// StreamEvents provides a mock function with given fields: ctx, customerID, email, streamEventsReq, stream
+func (_m *DataHubService) StreamEvents(ctx context.Context, customerID string, email string, streamEventsReq domain.StreamEventsReq, stream io.Reader) (*domain.StreamEventsRes, []errormsg.ErrorMsg, error) {
+	ret := _m.Called(ctx, customerID, email, streamEventsReq, stream)
+
+	var r0 *domain.StreamEventsRes
+	var r1 []errormsg.ErrorMsg
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.StreamEventsReq, io.Reader) (*domain.StreamEventsRes, []errormsg.ErrorMsg, error)); ok {
+		return rf(ctx, customerID, email, streamEventsReq, stream)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.StreamEventsReq, io.Reader) *domain.StreamEventsRes); ok {
+		r0 = rf(ctx, customerID, email, streamEventsReq, stream)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.StreamEventsRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.StreamEventsReq, io.Reader) []errormsg.ErrorMsg); ok {
+		r1 = rf(ctx, customerID, email, streamEventsReq, stream)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]errormsg.ErrorMsg)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, domain.StreamEventsReq, io.Reader) error); ok {
+		r2 = rf(ctx, customerID, email, streamEventsReq, stream)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewDataHubService creates a new instance of DataHubService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDataHubService(t interface {
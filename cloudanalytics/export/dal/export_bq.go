@@ -2,111 +2,98 @@ package dal
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"cloud.google.com/go/bigquery"
 	"google.golang.org/api/googleapi"
-)
 
-const (
-	DatasetAWS = "aws_billing"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/domain"
 )
 
+const schemaVersionLabel = "schema_version"
+
 type BigQueryDAL struct {
-	viewsClient       *bigquery.Client
-	billingDataClient *bigquery.Client
+	viewsClient *bigquery.Client
 }
 
-func NewBigQueryDAL(ctx context.Context, viewsProject, billingDataProject string) *BigQueryDAL {
+func NewBigQueryDAL(ctx context.Context, viewsProject string) *BigQueryDAL {
 	viewsClient, err := bigquery.NewClient(ctx, viewsProject)
 	if err != nil {
 		log.Fatalf("Failed to create viewsClient client: %v", err)
 	}
 
-	billingDataClient, err := bigquery.NewClient(ctx, billingDataProject)
+	return &BigQueryDAL{viewsClient: viewsClient}
+}
+
+// GetView returns the metadata of the view, or nil if the view does not exist.
+func (d *BigQueryDAL) GetView(ctx context.Context, view *domain.View) (*domain.ViewMetadata, error) {
+	md, err := d.viewsClient.Dataset(view.Dataset).Table(view.Table).Metadata(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create billingDataClient client: %v", err)
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
 	}
 
-	return &BigQueryDAL{viewsClient: viewsClient, billingDataClient: billingDataClient}
+	return &domain.ViewMetadata{
+		Query:         md.ViewQuery,
+		SchemaVersion: getSchemaVersion(md.Labels),
+	}, nil
 }
 
-func (d *BigQueryDAL) CheckViewExists(ctx context.Context, customerID string) (bool, error) {
-	datasetRef := d.viewsClient.Dataset(getAWSBillingDatasetID(customerID))
-	_, err := datasetRef.Metadata(ctx)
-	if err != nil {
-		// create the dataset
-		metaData := &bigquery.DatasetMetadata{}
-		err = datasetRef.Create(ctx, metaData)
-		if err != nil {
-			return false, err
+// CreateView creates the view and its dataset if it does not exist yet.
+func (d *BigQueryDAL) CreateView(ctx context.Context, view *domain.View) error {
+	datasetRef := d.viewsClient.Dataset(view.Dataset)
+
+	if _, err := datasetRef.Metadata(ctx); err != nil {
+		if !isNotFound(err) {
+			return err
 		}
-		return false, nil
-	}
 
-	tableRef := datasetRef.Table(getAWSBillingTableID(customerID))
-	_, err = tableRef.Metadata(ctx)
-	if err != nil {
-		if e, ok := err.(*googleapi.Error); ok {
-			if e.Code == http.StatusNotFound {
-				return false, nil
-			}
+		if err := datasetRef.Create(ctx, &bigquery.DatasetMetadata{}); err != nil {
+			return err
 		}
-		return false, err
 	}
 
-	return true, nil
+	metaData := &bigquery.TableMetadata{
+		ViewQuery: view.Query,
+		Labels: map[string]string{
+			schemaVersionLabel: strconv.Itoa(view.SchemaVersion),
+		},
+	}
+
+	return datasetRef.Table(view.Table).Create(ctx, metaData)
 }
 
-func (d *BigQueryDAL) CreateViewAWS(ctx context.Context, customerID string) error {
-	datasetRef := d.viewsClient.Dataset(getAWSBillingDatasetID(customerID))
-	viewRef := datasetRef.Table(getAWSBillingTableID(customerID))
-
-	sourceTable := fmt.Sprintf("%s.%s.%s", d.billingDataClient.Project(), getAWSBillingDatasetID(customerID), getAWSBillingTableID(customerID))
-
-	query := `
-	SELECT
-		billing_account_id,
-		project_id,
-		service_description,
-		service_id,
-		sku_description,
-		sku_id,
-		usage_date_time,
-		usage_start_time,
-		usage_end_time,
-		labels,
-		system_labels,
-		location,
-		export_time,
-		cost,
-		currency,
-		currency_conversion_rate,
-		usage,
-		invoice,
-		cost_type,
-		STRUCT(report[OFFSET(0)].cost AS cost, report[OFFSET(0)].usage AS usage, report[OFFSET(0)].savings AS savings, report[OFFSET(0)].savings_description AS savings_description, report[OFFSET(0)].credit AS credit) AS report,
-		resource_id,
-		operation,
-		aws_metric,
-		row_id,
-		description
-	FROM
-		` + sourceTable
+// UpdateView replaces the query and the schema version of an existing view.
+func (d *BigQueryDAL) UpdateView(ctx context.Context, view *domain.View) error {
+	viewRef := d.viewsClient.Dataset(view.Dataset).Table(view.Table)
 
-	metaData := &bigquery.TableMetadata{
-		ViewQuery: query,
+	md, err := viewRef.Metadata(ctx)
+	if err != nil {
+		return err
 	}
 
-	return viewRef.Create(ctx, metaData)
+	update := bigquery.TableMetadataToUpdate{
+		ViewQuery: view.Query,
+	}
+	update.SetLabel(schemaVersionLabel, strconv.Itoa(view.SchemaVersion))
+
+	_, err = viewRef.Update(ctx, update, md.ETag)
+
+	return err
 }
 
-func (d *BigQueryDAL) AuthorizeView(ctx context.Context, customerID, customerEmail string) error {
-	srcDataset := d.billingDataClient.Dataset(getAWSBillingDatasetID(customerID))
-	viewDataset := d.viewsClient.Dataset(getAWSBillingDatasetID(customerID))
-	view := viewDataset.Table(getAWSBillingTableID(customerID))
+// AuthorizeView shares the view dataset with the customer and authorizes the view against
+// its source datasets. Access entries that already exist are not added again.
+func (d *BigQueryDAL) AuthorizeView(ctx context.Context, view *domain.View, customerEmail string) error {
+	viewDataset := d.viewsClient.Dataset(view.Dataset)
+	viewRef := viewDataset.Table(view.Table)
 
 	// Add the customer email to the ACL for the dataset containing the view
 	vMeta, err := viewDataset.Metadata(ctx)
@@ -114,39 +101,123 @@ func (d *BigQueryDAL) AuthorizeView(ctx context.Context, customerID, customerEma
 		return err
 	}
 
-	vUpdateMeta := bigquery.DatasetMetadataToUpdate{
-		Access: append(vMeta.Access, &bigquery.AccessEntry{
-			Role:       bigquery.ReaderRole,
-			EntityType: bigquery.UserEmailEntity,
-			Entity:     customerEmail,
-		}),
+	if !hasUserAccess(vMeta.Access, customerEmail) {
+		vUpdateMeta := bigquery.DatasetMetadataToUpdate{
+			Access: append(vMeta.Access, &bigquery.AccessEntry{
+				Role:       bigquery.ReaderRole,
+				EntityType: bigquery.UserEmailEntity,
+				Entity:     customerEmail,
+			}),
+		}
+		if _, err := viewDataset.Update(ctx, vUpdateMeta, vMeta.ETag); err != nil {
+			return err
+		}
 	}
-	if _, err := viewDataset.Update(ctx, vUpdateMeta, vMeta.ETag); err != nil {
-		return err
+
+	// Authorize the view against the source datasets
+	for _, source := range view.Sources {
+		srcDataset := d.viewsClient.DatasetInProject(source.Project, source.Dataset)
+
+		srcMeta, err := srcDataset.Metadata(ctx)
+		if err != nil {
+			return err
+		}
+
+		if hasViewAccess(srcMeta.Access, viewRef) {
+			continue
+		}
+
+		srcUpdateMeta := bigquery.DatasetMetadataToUpdate{
+			Access: append(srcMeta.Access, &bigquery.AccessEntry{
+				EntityType: bigquery.ViewEntity,
+				View:       viewRef,
+			}),
+		}
+		if _, err := srcDataset.Update(ctx, srcUpdateMeta, srcMeta.ETag); err != nil {
+			return err
+		}
 	}
 
-	// Authorize the view against a source dataset
-	srcMeta, err := srcDataset.Metadata(ctx)
+	return nil
+}
+
+// RevokeView removes the customer email from the ACL of the dataset containing the view.
+// The view itself and its authorization on the source datasets are kept, so it can be shared again.
+func (d *BigQueryDAL) RevokeView(ctx context.Context, view *domain.View, customerEmail string) error {
+	viewDataset := d.viewsClient.Dataset(view.Dataset)
+
+	vMeta, err := viewDataset.Metadata(ctx)
 	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
 		return err
 	}
-	srcUpdateMeta := bigquery.DatasetMetadataToUpdate{
 
-		Access: append(srcMeta.Access, &bigquery.AccessEntry{
-			EntityType: bigquery.ViewEntity,
-			View:       view,
-		}),
+	if !hasUserAccess(vMeta.Access, customerEmail) {
+		return nil
 	}
-	if _, err := srcDataset.Update(ctx, srcUpdateMeta, srcMeta.ETag); err != nil {
-		return err
+
+	access := make([]*bigquery.AccessEntry, 0, len(vMeta.Access))
+
+	for _, entry := range vMeta.Access {
+		if !isUserEntry(entry, customerEmail) {
+			access = append(access, entry)
+		}
 	}
-	return nil
+
+	_, err = viewDataset.Update(ctx, bigquery.DatasetMetadataToUpdate{Access: access}, vMeta.ETag)
+
+	return err
+}
+
+func hasUserAccess(access []*bigquery.AccessEntry, email string) bool {
+	for _, entry := range access {
+		if isUserEntry(entry, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isUserEntry(entry *bigquery.AccessEntry, email string) bool {
+	return entry.EntityType == bigquery.UserEmailEntity && strings.EqualFold(entry.Entity, email)
+}
+
+func hasViewAccess(access []*bigquery.AccessEntry, view *bigquery.Table) bool {
+	for _, entry := range access {
+		if entry.EntityType != bigquery.ViewEntity || entry.View == nil {
+			continue
+		}
+
+		if entry.View.ProjectID == view.ProjectID &&
+			entry.View.DatasetID == view.DatasetID &&
+			entry.View.TableID == view.TableID {
+			return true
+		}
+	}
+
+	return false
 }
 
-func getAWSBillingDatasetID(customerID string) string {
-	return fmt.Sprintf("%s_%s", DatasetAWS, customerID)
+// getSchemaVersion returns the schema version of the view from its labels. Views that
+// were created before the schema versioning have no label and are version 1.
+func getSchemaVersion(labels map[string]string) int {
+	version, err := strconv.Atoi(labels[schemaVersionLabel])
+	if err != nil {
+		return 1
+	}
+
+	return version
 }
 
-func getAWSBillingTableID(customerID string) string {
-	return fmt.Sprintf("doitintl_billing_export_v1_%s", customerID)
+func isNotFound(err error) bool {
+	var e *googleapi.Error
+	if errors.As(err, &e) {
+		return e.Code == http.StatusNotFound
+	}
+
+	return false
 }
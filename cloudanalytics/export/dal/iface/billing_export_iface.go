@@ -2,12 +2,15 @@ package iface
 
 import (
 	"context"
-	//"github.com/doitintl/hello/scheduled-tasks/billing-explainer/domain"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/domain"
 )
 
 //go:generate mockery --name BigQueryDAL --output ../mocks
 type BigQueryDAL interface {
-	CreateViewAWS(ctx context.Context, customerID string) error
-	AuthorizeView(ctx context.Context, customerID, customerEmail string) error
-	CheckViewExists(ctx context.Context, customerID string) (bool, error)
+	GetView(ctx context.Context, view *domain.View) (*domain.ViewMetadata, error)
+	CreateView(ctx context.Context, view *domain.View) error
+	UpdateView(ctx context.Context, view *domain.View) error
+	AuthorizeView(ctx context.Context, view *domain.View, customerEmail string) error
+	RevokeView(ctx context.Context, view *domain.View, customerEmail string) error
 }
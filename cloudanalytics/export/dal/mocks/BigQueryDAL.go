@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	domain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/domain"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,17 +15,35 @@ type BigQueryDAL struct {
 	mock.Mock
 }
 
-// AuthorizeView provides a mock function with given fields: ctx, customerID, customerEmail
-func (_m *BigQueryDAL) AuthorizeView(ctx context.Context, customerID string, customerEmail string) error {
-	ret := _m.Called(ctx, customerID, customerEmail)
+// AuthorizeView provides a mock function with given fields: ctx, view, customerEmail
+func (_m *BigQueryDAL) AuthorizeView(ctx context.Context, view *domain.View, customerEmail string) error {
+	ret := _m.Called(ctx, view, customerEmail)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AuthorizeView")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, customerID, customerEmail)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View, string) error); ok {
+		r0 = rf(ctx, view, customerEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateView provides a mock function with given fields: ctx, view
+func (_m *BigQueryDAL) CreateView(ctx context.Context, view *domain.View) error {
+	ret := _m.Called(ctx, view)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateView")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View) error); ok {
+		r0 = rf(ctx, view)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,27 +51,29 @@ func (_m *BigQueryDAL) AuthorizeView(ctx context.Context, customerID string, cus
 	return r0
 }
 
-// CheckViewExists provides a mock function with given fields: ctx, customerID
-func (_m *BigQueryDAL) CheckViewExists(ctx context.Context, customerID string) (bool, error) {
-	ret := _m.Called(ctx, customerID)
+// GetView provides a mock function with given fields: ctx, view
+func (_m *BigQueryDAL) GetView(ctx context.Context, view *domain.View) (*domain.ViewMetadata, error) {
+	ret := _m.Called(ctx, view)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckViewExists")
+		panic("no return value specified for GetView")
 	}
 
-	var r0 bool
+	var r0 *domain.ViewMetadata
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View) (*domain.ViewMetadata, error)); ok {
+		return rf(ctx, view)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View) *domain.ViewMetadata); ok {
+		r0 = rf(ctx, view)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ViewMetadata)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, customerID)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.View) error); ok {
+		r1 = rf(ctx, view)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,17 +81,35 @@ func (_m *BigQueryDAL) CheckViewExists(ctx context.Context, customerID string) (
 	return r0, r1
 }
 
-// CreateViewAWS provides a mock function with given fields: ctx, customerID
-func (_m *BigQueryDAL) CreateViewAWS(ctx context.Context, customerID string) error {
-	ret := _m.Called(ctx, customerID)
+// RevokeView provides a mock function with given fields: ctx, view, customerEmail
+func (_m *BigQueryDAL) RevokeView(ctx context.Context, view *domain.View, customerEmail string) error {
+	ret := _m.Called(ctx, view, customerEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeView")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View, string) error); ok {
+		r0 = rf(ctx, view, customerEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateView provides a mock function with given fields: ctx, view
+func (_m *BigQueryDAL) UpdateView(ctx context.Context, view *domain.View) error {
+	ret := _m.Called(ctx, view)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateViewAWS")
+		panic("no return value specified for UpdateView")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.View) error); ok {
+		r0 = rf(ctx, view)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1,6 +1,61 @@
 package domain
 
+import (
+	"fmt"
+)
+
+// CloudCombined exports the billing data of all the customer clouds in a single view
+// using the unified report schema.
+const CloudCombined = "combined"
+
+// Schema versions of the billing export views. Bump the version when the view schema
+// changes, existing views are updated on their next export.
+const (
+	SchemaVersionAWS      = 1
+	SchemaVersionGCP      = 1
+	SchemaVersionAzure    = 1
+	SchemaVersionCombined = 1
+)
+
 type BillingExportInputStruct struct {
 	Cloud         string `json:"cloud" validate:"required"`
 	CustomerEmail string `json:"customer_email" validate:"required"`
 }
+
+// SourceDataset is a billing data dataset the view reads from, the view must be
+// authorized on it.
+type SourceDataset struct {
+	Project string
+	Dataset string
+}
+
+// View is a customer billing export view.
+type View struct {
+	Dataset       string
+	Table         string
+	Query         string
+	SchemaVersion int
+	Sources       []SourceDataset
+}
+
+// ViewMetadata is the current state of an existing view.
+type ViewMetadata struct {
+	Query         string
+	SchemaVersion int
+}
+
+// UpToDate returns true if the existing view has the expected schema version and query.
+func (m *ViewMetadata) UpToDate(view *View) bool {
+	return m.SchemaVersion == view.SchemaVersion && m.Query == view.Query
+}
+
+// CustomerBillingAccounts are the customer billing data sources for each cloud.
+type CustomerBillingAccounts struct {
+	GoogleCloud       []string
+	AmazonWebServices bool
+	MicrosoftAzure    bool
+}
+
+func (v *View) String() string {
+	return fmt.Sprintf("%s.%s", v.Dataset, v.Table)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	domainExport "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/domain"
@@ -45,10 +46,47 @@ func (h *BillingExport) HandleCustomerBillingExport(ctx *gin.Context) error {
 
 	customerID := ctx.Param("customerID")
 
-	err := h.service.ExportBillingData(ctx, customerID, &taskBody)
-	if err != nil {
-		return web.NewRequestError(err, http.StatusInternalServerError)
+	if err := h.service.ExportBillingData(ctx, customerID, &taskBody); err != nil {
+		return handleServiceError(err)
 	}
 
 	return web.Respond(ctx, nil, http.StatusOK)
 }
+
+// HandleRevokeCustomerBillingExport stops sharing the billing data view with the customer email.
+// Posting the same body to the billing export endpoint shares the view again.
+func (h *BillingExport) HandleRevokeCustomerBillingExport(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+
+	var taskBody domainExport.BillingExportInputStruct
+
+	if err := ctx.ShouldBindJSON(&taskBody); err != nil {
+		l.Errorf("Billing data export revoke failed while parsing request body.\n Error: %v", err)
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	validate := validator.New()
+
+	if err := validate.Struct(taskBody); err != nil {
+		return web.Respond(ctx, "Missing required fields", http.StatusBadRequest)
+	}
+
+	customerID := ctx.Param("customerID")
+
+	if err := h.service.RevokeBillingExport(ctx, customerID, &taskBody); err != nil {
+		return handleServiceError(err)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func handleServiceError(err error) error {
+	switch {
+	case errors.Is(err, exportService.ErrUnsupportedCloud):
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case errors.Is(err, exportService.ErrNoBillingData):
+		return web.NewRequestError(err, http.StatusNotFound)
+	default:
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+}
@@ -9,4 +9,5 @@ import (
 //go:generate mockery --output=../mocks --all
 type IExportService interface {
 	ExportBillingData(ctx context.Context, customeID string, taskBody *domainExport.BillingExportInputStruct) error
+	RevokeBillingExport(ctx context.Context, customeID string, taskBody *domainExport.BillingExportInputStruct) error
 }
@@ -15,17 +15,35 @@ type IExportService struct {
 	mock.Mock
 }
 
-// ExportBillingData provides a mock function with given fields: ctx, taskBody
-func (_m *IExportService) ExportBillingData(ctx context.Context, taskBody *domain.BillingExportInputStruct) error {
-	ret := _m.Called(ctx, taskBody)
+// ExportBillingData provides a mock function with given fields: ctx, customeID, taskBody
+func (_m *IExportService) ExportBillingData(ctx context.Context, customeID string, taskBody *domain.BillingExportInputStruct) error {
+	ret := _m.Called(ctx, customeID, taskBody)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ExportBillingData")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.BillingExportInputStruct) error); ok {
-		r0 = rf(ctx, taskBody)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.BillingExportInputStruct) error); ok {
+		r0 = rf(ctx, customeID, taskBody)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeBillingExport provides a mock function with given fields: ctx, customeID, taskBody
+func (_m *IExportService) RevokeBillingExport(ctx context.Context, customeID string, taskBody *domain.BillingExportInputStruct) error {
+	ret := _m.Called(ctx, customeID, taskBody)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeBillingExport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.BillingExportInputStruct) error); ok {
+		r0 = rf(ctx, customeID, taskBody)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 
+	assetsDal "github.com/doitintl/hello/scheduled-tasks/assets/dal"
+	"github.com/doitintl/hello/scheduled-tasks/assets/pkg"
 	"github.com/doitintl/hello/scheduled-tasks/common"
+	customerDal "github.com/doitintl/hello/scheduled-tasks/customer/dal"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 
@@ -13,16 +17,21 @@ import (
 )
 
 const (
-	ProdViewsProject   = "doit-data-views"
-	DevViewsProject    = "doit-data-views-dev"
-	ProdAWSDataProject = "doitintl-cmp-aws-data"
-	DevAWSDataProject  = "cmp-aws-etl-dev"
+	ProdViewsProject = "doit-data-views"
+	DevViewsProject  = "doit-data-views-dev"
+)
+
+var (
+	ErrUnsupportedCloud = errors.New("unsupported cloud for billing export")
+	ErrNoBillingData    = errors.New("no billing data found for customer")
 )
 
 type BillingExportService struct {
 	loggerProvider logger.Provider
 	conn           *connection.Connection
 	bigQueryDAL    dalIface.BigQueryDAL
+	assetsDAL      assetsDal.Assets
+	customersDAL   customerDal.Customers
 }
 
 func NewBillingExportService(
@@ -30,50 +39,139 @@ func NewBillingExportService(
 	conn *connection.Connection) *BillingExportService {
 
 	viewsProject := DevViewsProject
-	billingDataProject := DevAWSDataProject
 
 	if common.Production {
 		viewsProject = ProdViewsProject
-		billingDataProject = ProdAWSDataProject
 	}
 
 	return &BillingExportService{
 		loggerProvider: loggerProvider,
 		conn:           conn,
-		bigQueryDAL:    dal.NewBigQueryDAL(context.Background(), viewsProject, billingDataProject),
+		bigQueryDAL:    dal.NewBigQueryDAL(context.Background(), viewsProject),
+		assetsDAL:      assetsDal.NewAssetsFirestoreWithClient(conn.Firestore),
+		customersDAL:   customerDal.NewCustomersFirestoreWithClient(conn.Firestore),
 	}
 }
 
+// ExportBillingData creates the customer billing view of the cloud and shares it with the customer email.
+// Views with an outdated schema are updated, and exporting an existing view shares it again.
 func (s *BillingExportService) ExportBillingData(ctx context.Context, customerID string, inputParams *domainExport.BillingExportInputStruct) error {
 	log := s.loggerProvider(ctx)
 
-	if inputParams.Cloud != common.Assets.AmazonWebServices {
-		return nil
+	view, err := s.getView(ctx, customerID, inputParams.Cloud)
+	if err != nil {
+		return err
 	}
 
-	log.Info("Checking if view exists for customerId %s", customerID)
+	log.Infof("Checking if view %s exists for customerId %s", view, customerID)
 
-	viewExists, err := s.bigQueryDAL.CheckViewExists(ctx, customerID)
+	existing, err := s.bigQueryDAL.GetView(ctx, view)
 	if err != nil {
 		return err
 	}
 
-	if viewExists {
+	switch {
+	case existing == nil:
+		log.Info("Creating view")
+
+		if err := s.bigQueryDAL.CreateView(ctx, view); err != nil {
+			return err
+		}
+	case !existing.UpToDate(view):
+		log.Infof("Updating view from schema version %d to %d", existing.SchemaVersion, view.SchemaVersion)
+
+		if err := s.bigQueryDAL.UpdateView(ctx, view); err != nil {
+			return err
+		}
+	default:
 		log.Info("View already exist")
-		return nil
 	}
 
-	log.Info("Creating view")
+	log.Info("Sharing view with customer")
+
+	return s.bigQueryDAL.AuthorizeView(ctx, view, inputParams.CustomerEmail)
+}
+
+// RevokeBillingExport stops sharing the customer billing view of the cloud with the customer email.
+func (s *BillingExportService) RevokeBillingExport(ctx context.Context, customerID string, inputParams *domainExport.BillingExportInputStruct) error {
+	log := s.loggerProvider(ctx)
 
-	if err := s.bigQueryDAL.CreateViewAWS(ctx, customerID); err != nil {
+	view, err := s.getView(ctx, customerID, inputParams.Cloud)
+	if err != nil {
 		return err
 	}
 
-	log.Info("Sharing view with customer")
+	log.Infof("Revoking access to view %s", view)
 
-	if err := s.bigQueryDAL.AuthorizeView(ctx, customerID, inputParams.CustomerEmail); err != nil {
-		return err
+	return s.bigQueryDAL.RevokeView(ctx, view, inputParams.CustomerEmail)
+}
+
+func (s *BillingExportService) getView(ctx context.Context, customerID, cloud string) (*domainExport.View, error) {
+	switch cloud {
+	case common.Assets.AmazonWebServices:
+		return newAWSView(customerID), nil
+	case common.Assets.MicrosoftAzure:
+		return newAzureView(customerID)
+	case common.Assets.GoogleCloud:
+		accounts, err := s.getCustomerBillingAccounts(ctx, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return newGoogleCloudView(customerID, accounts.GoogleCloud)
+	case domainExport.CloudCombined:
+		accounts, err := s.getCustomerBillingAccounts(ctx, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return newCombinedView(customerID, accounts)
+	default:
+		return nil, ErrUnsupportedCloud
+	}
+}
+
+// getCustomerBillingAccounts returns the billing data sources of the customer assets.
+func (s *BillingExportService) getCustomerBillingAccounts(ctx context.Context, customerID string) (*domainExport.CustomerBillingAccounts, error) {
+	customerRef := s.customersDAL.GetRef(ctx, customerID)
+
+	assets, err := s.assetsDAL.ListBaseAssetsForCustomer(ctx, customerRef, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts domainExport.CustomerBillingAccounts
+
+	for _, asset := range assets {
+		switch asset.AssetType {
+		case common.Assets.AmazonWebServices, common.Assets.AmazonWebServicesStandalone:
+			accounts.AmazonWebServices = true
+		case common.Assets.MicrosoftAzure, common.Assets.MicrosoftAzureStandalone:
+			accounts.MicrosoftAzure = true
+		}
+	}
+
+	gcpAssets, err := s.assetsDAL.GetCustomerGCPAssetsWithTypes(ctx, customerRef, []string{pkg.AssetGoogleCloud, pkg.AssetStandaloneGoogleCloud})
+	if err != nil {
+		return nil, err
+	}
+
+	usedAccounts := make(map[string]bool)
+
+	for _, asset := range gcpAssets {
+		if asset.Properties == nil || asset.Properties.BillingAccountID == "" {
+			continue
+		}
+
+		if asset.AssetType == pkg.AssetStandaloneGoogleCloud && (asset.StandaloneProperties == nil || !asset.StandaloneProperties.BillingReady) {
+			continue
+		}
+
+		if billingAccountID := asset.Properties.BillingAccountID; !usedAccounts[billingAccountID] {
+			usedAccounts[billingAccountID] = true
+			accounts.GoogleCloud = append(accounts.GoogleCloud, billingAccountID)
+		}
 	}
 
-	return nil
+	return &accounts, nil
 }
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	assetsMocks "github.com/doitintl/hello/scheduled-tasks/assets/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/assets/pkg"
+	dalMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/dal/mocks"
+	domainExport "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	customerMocks "github.com/doitintl/hello/scheduled-tasks/customer/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+const (
+	testCustomerID = "customer-id"
+	testEmail      = "user@example.com"
+)
+
+func TestBillingExportService_ExportBillingData(t *testing.T) {
+	ctx := context.Background()
+	customerRef := &firestore.DocumentRef{ID: testCustomerID}
+
+	type fields struct {
+		bigQueryDAL  *dalMocks.BigQueryDAL
+		assetsDAL    *assetsMocks.Assets
+		customersDAL *customerMocks.Customers
+	}
+
+	viewMatcher := func(dataset string) interface{} {
+		return mock.MatchedBy(func(view *domainExport.View) bool {
+			return view.Dataset == dataset
+		})
+	}
+
+	tests := []struct {
+		name    string
+		cloud   string
+		on      func(f *fields)
+		wantErr error
+	}{
+		{
+			name:  "create and share aws view",
+			cloud: common.Assets.AmazonWebServices,
+			on: func(f *fields) {
+				f.bigQueryDAL.On("GetView", ctx, viewMatcher("aws_billing_customer-id")).Return(nil, nil)
+				f.bigQueryDAL.On("CreateView", ctx, viewMatcher("aws_billing_customer-id")).Return(nil)
+				f.bigQueryDAL.On("AuthorizeView", ctx, viewMatcher("aws_billing_customer-id"), testEmail).Return(nil)
+			},
+		},
+		{
+			name:  "share existing up to date view again",
+			cloud: common.Assets.AmazonWebServices,
+			on: func(f *fields) {
+				view := newAWSView(testCustomerID)
+				f.bigQueryDAL.On("GetView", ctx, viewMatcher(view.Dataset)).Return(&domainExport.ViewMetadata{
+					Query:         view.Query,
+					SchemaVersion: view.SchemaVersion,
+				}, nil)
+				f.bigQueryDAL.On("AuthorizeView", ctx, viewMatcher(view.Dataset), testEmail).Return(nil)
+			},
+		},
+		{
+			name:  "update view with outdated schema",
+			cloud: common.Assets.MicrosoftAzure,
+			on: func(f *fields) {
+				f.bigQueryDAL.On("GetView", ctx, viewMatcher("azure_billing_customer-id")).Return(&domainExport.ViewMetadata{
+					Query:         "SELECT 1",
+					SchemaVersion: 0,
+				}, nil)
+				f.bigQueryDAL.On("UpdateView", ctx, viewMatcher("azure_billing_customer-id")).Return(nil)
+				f.bigQueryDAL.On("AuthorizeView", ctx, viewMatcher("azure_billing_customer-id"), testEmail).Return(nil)
+			},
+		},
+		{
+			name:  "google cloud view over all billing accounts",
+			cloud: common.Assets.GoogleCloud,
+			on: func(f *fields) {
+				f.customersDAL.On("GetRef", ctx, testCustomerID).Return(customerRef)
+				f.assetsDAL.On("ListBaseAssetsForCustomer", ctx, customerRef, 0).Return([]*pkg.BaseAsset{}, nil)
+				f.assetsDAL.On("GetCustomerGCPAssetsWithTypes", ctx, customerRef, []string{pkg.AssetGoogleCloud, pkg.AssetStandaloneGoogleCloud}).Return([]*pkg.GCPAsset{
+					{BaseAsset: pkg.BaseAsset{AssetType: pkg.AssetGoogleCloud}, Properties: &pkg.GCPProperties{BillingAccountID: "AAAAAA-BBBBBB-CCCCCC"}},
+					{BaseAsset: pkg.BaseAsset{AssetType: pkg.AssetGoogleCloud}, Properties: &pkg.GCPProperties{BillingAccountID: "AAAAAA-BBBBBB-CCCCCC"}},
+					{BaseAsset: pkg.BaseAsset{AssetType: pkg.AssetStandaloneGoogleCloud}, Properties: &pkg.GCPProperties{BillingAccountID: "DDDDDD-EEEEEE-FFFFFF"}},
+				}, nil)
+
+				isGCPView := mock.MatchedBy(func(view *domainExport.View) bool {
+					return view.Dataset == "gcp_billing_customer-id" &&
+						len(view.Sources) == 1 &&
+						view.Sources[0].Dataset == "gcp_billing_AAAAAA_BBBBBB_CCCCCC" &&
+						!strings.Contains(view.Query, "UNION ALL")
+				})
+				f.bigQueryDAL.On("GetView", ctx, isGCPView).Return(nil, nil)
+				f.bigQueryDAL.On("CreateView", ctx, isGCPView).Return(nil)
+				f.bigQueryDAL.On("AuthorizeView", ctx, isGCPView, testEmail).Return(nil)
+			},
+		},
+		{
+			name:  "combined view over all clouds",
+			cloud: domainExport.CloudCombined,
+			on: func(f *fields) {
+				f.customersDAL.On("GetRef", ctx, testCustomerID).Return(customerRef)
+				f.assetsDAL.On("ListBaseAssetsForCustomer", ctx, customerRef, 0).Return([]*pkg.BaseAsset{
+					{AssetType: common.Assets.AmazonWebServices},
+					{AssetType: common.Assets.MicrosoftAzure},
+				}, nil)
+				f.assetsDAL.On("GetCustomerGCPAssetsWithTypes", ctx, customerRef, mock.Anything).Return([]*pkg.GCPAsset{
+					{BaseAsset: pkg.BaseAsset{AssetType: pkg.AssetGoogleCloud}, Properties: &pkg.GCPProperties{BillingAccountID: "AAAAAA-BBBBBB-CCCCCC"}},
+				}, nil)
+
+				isCombinedView := mock.MatchedBy(func(view *domainExport.View) bool {
+					return view.Dataset == "billing_customer-id" &&
+						len(view.Sources) == 3 &&
+						strings.Count(view.Query, "UNION ALL") == 2
+				})
+				f.bigQueryDAL.On("GetView", ctx, isCombinedView).Return(nil, nil)
+				f.bigQueryDAL.On("CreateView", ctx, isCombinedView).Return(nil)
+				f.bigQueryDAL.On("AuthorizeView", ctx, isCombinedView, testEmail).Return(nil)
+			},
+		},
+		{
+			name:  "no billing data",
+			cloud: common.Assets.GoogleCloud,
+			on: func(f *fields) {
+				f.customersDAL.On("GetRef", ctx, testCustomerID).Return(customerRef)
+				f.assetsDAL.On("ListBaseAssetsForCustomer", ctx, customerRef, 0).Return([]*pkg.BaseAsset{}, nil)
+				f.assetsDAL.On("GetCustomerGCPAssetsWithTypes", ctx, customerRef, mock.Anything).Return([]*pkg.GCPAsset{}, nil)
+			},
+			wantErr: ErrNoBillingData,
+		},
+		{
+			name:    "unsupported cloud",
+			cloud:   "office-365",
+			wantErr: ErrUnsupportedCloud,
+		},
+		{
+			name:  "create view fails",
+			cloud: common.Assets.AmazonWebServices,
+			on: func(f *fields) {
+				f.bigQueryDAL.On("GetView", ctx, mock.Anything).Return(nil, nil)
+				f.bigQueryDAL.On("CreateView", ctx, mock.Anything).Return(errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fields{
+				bigQueryDAL:  dalMocks.NewBigQueryDAL(t),
+				assetsDAL:    assetsMocks.NewAssets(t),
+				customersDAL: customerMocks.NewCustomers(t),
+			}
+
+			if tt.on != nil {
+				tt.on(&f)
+			}
+
+			s := &BillingExportService{
+				loggerProvider: logger.FromContext,
+				bigQueryDAL:    f.bigQueryDAL,
+				assetsDAL:      f.assetsDAL,
+				customersDAL:   f.customersDAL,
+			}
+
+			err := s.ExportBillingData(ctx, testCustomerID, &domainExport.BillingExportInputStruct{
+				Cloud:         tt.cloud,
+				CustomerEmail: testEmail,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBillingExportService_RevokeBillingExport(t *testing.T) {
+	ctx := context.Background()
+	bigQueryDAL := dalMocks.NewBigQueryDAL(t)
+
+	bigQueryDAL.On("RevokeView", ctx, mock.MatchedBy(func(view *domainExport.View) bool {
+		return view.Dataset == "azure_billing_customer-id"
+	}), testEmail).Return(nil)
+
+	s := &BillingExportService{
+		loggerProvider: logger.FromContext,
+		bigQueryDAL:    bigQueryDAL,
+	}
+
+	err := s.RevokeBillingExport(ctx, testCustomerID, &domainExport.BillingExportInputStruct{
+		Cloud:         common.Assets.MicrosoftAzure,
+		CustomerEmail: testEmail,
+	})
+	assert.NoError(t, err)
+}
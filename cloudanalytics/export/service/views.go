@@ -0,0 +1,175 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	analyticsAWS "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/amazonwebservices/utils"
+	domainExport "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/domain"
+	gcpTableMgmtDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/googlecloud/billingtablemgmt/domain"
+	analyticsAzure "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/microsoftazure"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+)
+
+const (
+	viewTableTemplate      = "doitintl_billing_export_v1_%s"
+	gcpViewDatasetPrefix   = "gcp_billing"
+	awsViewDatasetPrefix   = "aws_billing"
+	azureViewDatasetPrefix = "azure_billing"
+	combinedDatasetPrefix  = "billing"
+
+	selectStatement = "SELECT \"%s\" AS cloud_provider, %s FROM `%s.%s.%s`"
+)
+
+type sourceTable struct {
+	cloudProvider string
+	project       string
+	dataset       string
+	table         string
+}
+
+func viewDataset(prefix, customerID string) string {
+	return fmt.Sprintf("%s_%s", prefix, customerID)
+}
+
+func viewTable(customerID string) string {
+	return fmt.Sprintf(viewTableTemplate, customerID)
+}
+
+// newAWSView returns the AWS view, which keeps the original AWS billing table columns.
+func newAWSView(customerID string) *domainExport.View {
+	source := awsSourceTable(customerID)
+	sourceTableID := fmt.Sprintf("%s.%s.%s", source.project, source.dataset, source.table)
+
+	query := `
+	SELECT
+		billing_account_id,
+		project_id,
+		service_description,
+		service_id,
+		sku_description,
+		sku_id,
+		usage_date_time,
+		usage_start_time,
+		usage_end_time,
+		labels,
+		system_labels,
+		location,
+		export_time,
+		cost,
+		currency,
+		currency_conversion_rate,
+		usage,
+		invoice,
+		cost_type,
+		STRUCT(report[OFFSET(0)].cost AS cost, report[OFFSET(0)].usage AS usage, report[OFFSET(0)].savings AS savings, report[OFFSET(0)].savings_description AS savings_description, report[OFFSET(0)].credit AS credit) AS report,
+		resource_id,
+		operation,
+		aws_metric,
+		row_id,
+		description
+	FROM
+		` + sourceTableID
+
+	return &domainExport.View{
+		Dataset:       viewDataset(awsViewDatasetPrefix, customerID),
+		Table:         viewTable(customerID),
+		Query:         query,
+		SchemaVersion: domainExport.SchemaVersionAWS,
+		Sources:       []domainExport.SourceDataset{{Project: source.project, Dataset: source.dataset}},
+	}
+}
+
+// newGoogleCloudView returns a view over the billing tables of all the customer billing accounts.
+func newGoogleCloudView(customerID string, billingAccountIDs []string) (*domainExport.View, error) {
+	sources := make([]sourceTable, 0, len(billingAccountIDs))
+	for _, billingAccountID := range billingAccountIDs {
+		sources = append(sources, gcpSourceTable(billingAccountID))
+	}
+
+	return newUnifiedView(viewDataset(gcpViewDatasetPrefix, customerID), customerID, domainExport.SchemaVersionGCP, sources)
+}
+
+func newAzureView(customerID string) (*domainExport.View, error) {
+	return newUnifiedView(viewDataset(azureViewDatasetPrefix, customerID), customerID, domainExport.SchemaVersionAzure, []sourceTable{azureSourceTable(customerID)})
+}
+
+// newCombinedView returns a view over the billing tables of all the customer clouds.
+func newCombinedView(customerID string, accounts *domainExport.CustomerBillingAccounts) (*domainExport.View, error) {
+	sources := make([]sourceTable, 0, len(accounts.GoogleCloud)+2)
+	for _, billingAccountID := range accounts.GoogleCloud {
+		sources = append(sources, gcpSourceTable(billingAccountID))
+	}
+
+	if accounts.AmazonWebServices {
+		sources = append(sources, awsSourceTable(customerID))
+	}
+
+	if accounts.MicrosoftAzure {
+		sources = append(sources, azureSourceTable(customerID))
+	}
+
+	return newUnifiedView(viewDataset(combinedDatasetPrefix, customerID), customerID, domainExport.SchemaVersionCombined, sources)
+}
+
+// newUnifiedView returns a view that selects the unified report fields from all the source tables.
+func newUnifiedView(dataset, customerID string, schemaVersion int, sources []sourceTable) (*domainExport.View, error) {
+	if len(sources) == 0 {
+		return nil, ErrNoBillingData
+	}
+
+	selects := make([]string, 0, len(sources))
+	datasets := make([]domainExport.SourceDataset, 0, len(sources))
+	usedDatasets := make(map[domainExport.SourceDataset]bool)
+
+	for _, source := range sources {
+		fields, err := cloudanalytics.GetReportFields(source.cloudProvider, false)
+		if err != nil {
+			return nil, err
+		}
+
+		selects = append(selects, fmt.Sprintf(selectStatement, source.cloudProvider, fields, source.project, source.dataset, source.table))
+
+		sourceDataset := domainExport.SourceDataset{Project: source.project, Dataset: source.dataset}
+		if !usedDatasets[sourceDataset] {
+			usedDatasets[sourceDataset] = true
+			datasets = append(datasets, sourceDataset)
+		}
+	}
+
+	return &domainExport.View{
+		Dataset:       dataset,
+		Table:         viewTable(customerID),
+		Query:         strings.Join(selects, "\nUNION ALL\n"),
+		SchemaVersion: schemaVersion,
+		Sources:       datasets,
+	}, nil
+}
+
+func gcpSourceTable(billingAccountID string) sourceTable {
+	return sourceTable{
+		cloudProvider: common.Assets.GoogleCloud,
+		project:       gcpTableMgmtDomain.GetBillingProject(),
+		dataset:       gcpTableMgmtDomain.GetCustomerBillingDataset(billingAccountID),
+		table:         gcpTableMgmtDomain.GetCustomerBillingTable(billingAccountID, ""),
+	}
+}
+
+func awsSourceTable(customerID string) sourceTable {
+	return sourceTable{
+		cloudProvider: common.Assets.AmazonWebServices,
+		project:       analyticsAWS.GetBillingProject(),
+		dataset:       analyticsAWS.GetCustomerBillingDataset(customerID),
+		table:         analyticsAWS.GetCustomerBillingTable(customerID, ""),
+	}
+}
+
+func azureSourceTable(customerID string) sourceTable {
+	return sourceTable{
+		cloudProvider: common.Assets.MicrosoftAzure,
+		project:       analyticsAzure.GetBillingProject(),
+		dataset:       analyticsAzure.GetCustomerBillingDataset(customerID),
+		table:         analyticsAzure.GetCustomerBillingTable(customerID, ""),
+	}
+}
@@ -1,12 +1,37 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 )
 
+// Engine selects the backend that computes a forecast.
+type Engine string
+
+const (
+	// EngineAuto uses the remote forecast service and falls back to the local engine when it fails.
+	EngineAuto Engine = ""
+	// EngineRemote uses only the remote forecast service.
+	EngineRemote Engine = "remote"
+	// EngineLocal uses only the in-process forecast engine.
+	EngineLocal Engine = "local"
+)
+
+var ErrInvalidEngine = errors.New("invalid forecast engine")
+
+// Validate checks that the engine is one of the supported forecast engines
+func (e Engine) Validate() error {
+	switch e {
+	case EngineAuto, EngineRemote, EngineLocal:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidEngine, e)
+	}
+}
+
 type ForecastRequest struct {
 	Series        []*OriginSeries `json:"series"`
 	Steps         int             `json:"steps"`
@@ -30,6 +55,8 @@ func (p *OriginSeries) IsBeforePeriod(interval string, t time.Time) bool {
 type ModelSeries struct {
 	DS     string  `json:"ds"`
 	Value  float64 `json:"yhat"`
+	Lower  float64 `json:"yhat_lower"`
+	Upper  float64 `json:"yhat_upper"`
 	Ignore bool    `json:"-"`
 }
 
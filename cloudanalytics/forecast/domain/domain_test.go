@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine_Validate(t *testing.T) {
+	assert.NoError(t, EngineAuto.Validate())
+	assert.NoError(t, EngineRemote.Validate())
+	assert.NoError(t, EngineLocal.Validate())
+	assert.ErrorIs(t, Engine("prophet").Validate(), ErrInvalidEngine)
+}
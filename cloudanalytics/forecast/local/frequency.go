@@ -0,0 +1,153 @@
+package local
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doitintl/hello/scheduled-tasks/times"
+)
+
+// Step frequencies, matching the values sent to the remote forecast service.
+const (
+	FrequencyHour        = "H"
+	FrequencyDay         = "D"
+	FrequencyWeek        = "W"
+	FrequencyMonth       = "MS"
+	FrequencyQuarter     = "Q"
+	FrequencyYear        = "Y"
+	FrequencyMonthCumSum = "MONTH_CUM_SUM"
+)
+
+const (
+	responseTimeDSLayout   = "2006-01-02 15:04"
+	requestHourDSLayout    = "2006-01-02-15:04"
+	requestDayDSLayout     = times.YearMonthDayLayout
+	requestMonthDSLayout   = times.YearMonthLayout
+	requestYearDSLayout    = "2006"
+	requestWeekDSPrefix    = "W"
+	requestQuarterDSPrefix = "Q"
+)
+
+// frequency describes how the points of a series are spaced in time, how their
+// "ds" values are read from the request and written to the response.
+type frequency struct {
+	// periodicity is the seasonal cycle length in steps, 0 when there is no seasonality.
+	periodicity int
+	// cumulative is set when the series values are a running sum that resets every month.
+	cumulative bool
+	parse      func(ds string) (time.Time, error)
+	format     func(t time.Time) string
+	next       func(t time.Time) time.Time
+}
+
+var frequencies = map[string]*frequency{
+	FrequencyHour: {
+		periodicity: 24,
+		parse:       layoutParser(requestHourDSLayout),
+		format:      layoutFormatter(responseTimeDSLayout),
+		next:        func(t time.Time) time.Time { return t.Add(time.Hour) },
+	},
+	FrequencyDay: {
+		periodicity: 7,
+		parse:       layoutParser(requestDayDSLayout),
+		format:      layoutFormatter(responseTimeDSLayout),
+		next:        func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+	},
+	FrequencyMonthCumSum: {
+		periodicity: 7,
+		cumulative:  true,
+		parse:       layoutParser(requestDayDSLayout),
+		format:      layoutFormatter(responseTimeDSLayout),
+		next:        func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+	},
+	FrequencyWeek: {
+		periodicity: 52,
+		parse:       parseWeek,
+		format: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-%s%02d", year, requestWeekDSPrefix, week)
+		},
+		next: func(t time.Time) time.Time { return t.AddDate(0, 0, 7) },
+	},
+	FrequencyMonth: {
+		periodicity: 12,
+		parse:       layoutParser(requestMonthDSLayout),
+		format:      layoutFormatter(responseTimeDSLayout),
+		next:        func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+	},
+	FrequencyQuarter: {
+		periodicity: 4,
+		parse:       parseQuarter,
+		format: func(t time.Time) string {
+			return fmt.Sprintf("%d-%s%d", t.Year(), requestQuarterDSPrefix, (int(t.Month())-1)/3+1)
+		},
+		next: func(t time.Time) time.Time { return t.AddDate(0, 3, 0) },
+	},
+	FrequencyYear: {
+		parse:  layoutParser(requestYearDSLayout),
+		format: layoutFormatter(responseTimeDSLayout),
+		next:   func(t time.Time) time.Time { return t.AddDate(1, 0, 0) },
+	},
+}
+
+func layoutParser(layout string) func(string) (time.Time, error) {
+	return func(ds string) (time.Time, error) {
+		return time.Parse(layout, ds)
+	}
+}
+
+func layoutFormatter(layout string) func(time.Time) string {
+	return func(t time.Time) string {
+		return t.Format(layout)
+	}
+}
+
+// parseWeek parses an ISO week in the format YYYY-W## and returns its monday.
+func parseWeek(ds string) (time.Time, error) {
+	year, week, err := splitYearAndPeriod(ds, requestWeekDSPrefix)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	monday, err := times.WeekStart(year, week)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return *monday, nil
+}
+
+// parseQuarter parses a quarter in the format YYYY-Q# and returns its first day.
+func parseQuarter(ds string) (time.Time, error) {
+	year, quarter, err := splitYearAndPeriod(ds, requestQuarterDSPrefix)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if quarter < 1 || quarter > 4 {
+		return time.Time{}, fmt.Errorf("invalid quarter %v", quarter)
+	}
+
+	return time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+func splitYearAndPeriod(ds string, prefix string) (int, int, error) {
+	parts := strings.Split(ds, "-")
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], prefix) {
+		return 0, 0, fmt.Errorf("invalid ds %s, expected format YYYY-%s#", ds, prefix)
+	}
+
+	year, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	period, err := strconv.Atoi(strings.TrimPrefix(parts[1], prefix))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return year, period, nil
+}
@@ -0,0 +1,177 @@
+// Package local implements an in-process forecasting engine that is used when the
+// remote forecast service is not available. It decomposes the series into seasonal
+// and seasonally adjusted components, and forecasts the seasonally adjusted series
+// with a damped Holt linear trend.
+package local
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// confidenceZ is the z-score of the 95% confidence interval.
+const confidenceZ = 1.959963984540054
+
+var (
+	ErrEmptySeries          = errors.New("forecast series is empty")
+	ErrUnsupportedFrequency = errors.New("unsupported forecast step frequency")
+)
+
+// Observation is a single point of the input series.
+type Observation struct {
+	DS    string
+	Value float64
+}
+
+// Prediction is a single point of the output series. It contains the fitted values
+// for the input series followed by the forecasted steps.
+type Prediction struct {
+	DS    string
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// Predict forecasts the given number of steps after the last observation.
+// The ds format of the observations and predictions matches the remote forecast service.
+func Predict(series []Observation, steps int, stepFrequency string) ([]*Prediction, error) {
+	freq, ok := frequencies[stepFrequency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFrequency, stepFrequency)
+	}
+
+	if len(series) == 0 {
+		return nil, ErrEmptySeries
+	}
+
+	dates, values, err := regularize(series, freq)
+	if err != nil {
+		return nil, err
+	}
+
+	if steps < 0 {
+		steps = 0
+	}
+
+	model := fitModel(values, freq.periodicity, steps)
+
+	predictions := make([]*Prediction, 0, len(values)+steps)
+
+	if freq.cumulative {
+		return appendCumulative(predictions, dates, values, model, freq, steps), nil
+	}
+
+	for i, date := range dates {
+		predictions = append(predictions, newPrediction(freq.format(date), model.fitted[i], model.sigma))
+	}
+
+	date := dates[len(dates)-1]
+
+	for h := 1; h <= steps; h++ {
+		date = freq.next(date)
+		predictions = append(predictions, newPrediction(freq.format(date), model.forecast[h-1], model.sigma*math.Sqrt(float64(h))))
+	}
+
+	return predictions, nil
+}
+
+// appendCumulative converts the fitted and forecasted daily values back to month to
+// date cumulative sums. The forecast continues from the last actual cumulative value.
+func appendCumulative(predictions []*Prediction, dates []time.Time, values []float64, model *fit, freq *frequency, steps int) []*Prediction {
+	var fittedSum, actualSum float64
+
+	for i, date := range dates {
+		if i > 0 && date.Month() != dates[i-1].Month() {
+			fittedSum, actualSum = 0, 0
+		}
+
+		fittedSum += model.fitted[i]
+		actualSum += values[i]
+
+		predictions = append(predictions, newPrediction(freq.format(date), fittedSum, model.sigma))
+	}
+
+	date := dates[len(dates)-1]
+	sum := actualSum
+
+	var variance float64
+
+	for h := 1; h <= steps; h++ {
+		next := freq.next(date)
+		if next.Month() != date.Month() {
+			sum, variance = 0, 0
+		}
+
+		date = next
+		sum += model.forecast[h-1]
+		variance += math.Pow(model.sigma, 2) * float64(h)
+
+		predictions = append(predictions, newPrediction(freq.format(date), sum, math.Sqrt(variance)))
+	}
+
+	return predictions
+}
+
+func newPrediction(ds string, value, sigma float64) *Prediction {
+	return &Prediction{
+		DS:    ds,
+		Value: value,
+		Lower: value - confidenceZ*sigma,
+		Upper: value + confidenceZ*sigma,
+	}
+}
+
+// regularize parses the observations and returns a series with one value per step,
+// filling missing steps with zero. Cumulative series are converted to daily values.
+func regularize(series []Observation, freq *frequency) ([]time.Time, []float64, error) {
+	type point struct {
+		date  time.Time
+		value float64
+	}
+
+	points := make([]point, len(series))
+
+	for i, obs := range series {
+		date, err := freq.parse(obs.DS)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		points[i] = point{date, obs.Value}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].date.Before(points[j].date)
+	})
+
+	if freq.cumulative {
+		var prev point
+
+		for i, p := range points {
+			if i > 0 && p.date.Month() == prev.date.Month() && p.date.Year() == prev.date.Year() {
+				points[i].value = p.value - prev.value
+			}
+
+			prev = p
+		}
+	}
+
+	valuesByDate := make(map[time.Time]float64, len(points))
+	for _, p := range points {
+		valuesByDate[p.date] += p.value
+	}
+
+	last := points[len(points)-1].date
+	dates := make([]time.Time, 0, len(points))
+	values := make([]float64, 0, len(points))
+
+	for date := points[0].date; !date.After(last); date = freq.next(date) {
+		dates = append(dates, date)
+		values = append(values, valuesByDate[date])
+	}
+
+	return dates, values, nil
+}
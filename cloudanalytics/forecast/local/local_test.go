@@ -0,0 +1,160 @@
+package local
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dailySeries(from time.Time, days int, value func(i int) float64) []Observation {
+	series := make([]Observation, days)
+	for i := 0; i < days; i++ {
+		series[i] = Observation{
+			DS:    from.AddDate(0, 0, i).Format("2006-01-02"),
+			Value: value(i),
+		}
+	}
+
+	return series
+}
+
+func TestPredict(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("linear daily trend", func(t *testing.T) {
+		series := dailySeries(from, 60, func(i int) float64 { return 100 + 2*float64(i) })
+
+		predictions, err := Predict(series, 5, FrequencyDay)
+		assert.NoError(t, err)
+		assert.Len(t, predictions, 65)
+
+		first := predictions[60]
+		assert.Equal(t, "2024-03-01 00:00", first.DS)
+		assert.InDelta(t, 220, first.Value, 2)
+		assert.LessOrEqual(t, first.Lower, first.Value)
+		assert.GreaterOrEqual(t, first.Upper, first.Value)
+	})
+
+	t.Run("weekly seasonality", func(t *testing.T) {
+		series := dailySeries(from, 8*7, func(i int) float64 {
+			if i%7 >= 5 {
+				return 10
+			}
+
+			return 100
+		})
+
+		predictions, err := Predict(series, 7, FrequencyDay)
+		assert.NoError(t, err)
+
+		forecast := predictions[len(series):]
+		for i, p := range forecast {
+			if (len(series)+i)%7 >= 5 {
+				assert.InDelta(t, 10, p.Value, 15, p.DS)
+			} else {
+				assert.InDelta(t, 100, p.Value, 15, p.DS)
+			}
+		}
+	})
+
+	t.Run("month cumulative sum resets every month", func(t *testing.T) {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		series := dailySeries(start, 45, func(i int) float64 {
+			d := start.AddDate(0, 0, i)
+			return 10 * float64(d.Day())
+		})
+
+		predictions, err := Predict(series, 20, FrequencyMonthCumSum)
+		assert.NoError(t, err)
+		assert.Len(t, predictions, 65)
+
+		lastActual := series[len(series)-1].Value
+		first := predictions[45]
+		assert.Equal(t, "2024-02-15 00:00", first.DS)
+		assert.InDelta(t, lastActual+10, first.Value, 2)
+
+		march := predictions[45+15]
+		assert.Equal(t, "2024-03-01 00:00", march.DS)
+		assert.InDelta(t, 10, march.Value, 2)
+	})
+
+	t.Run("missing days are filled", func(t *testing.T) {
+		series := []Observation{
+			{DS: "2024-01-01", Value: 1},
+			{DS: "2024-01-04", Value: 1},
+		}
+
+		predictions, err := Predict(series, 1, FrequencyDay)
+		assert.NoError(t, err)
+		assert.Len(t, predictions, 5)
+		assert.Equal(t, "2024-01-05 00:00", predictions[4].DS)
+	})
+
+	t.Run("unsupported frequency", func(t *testing.T) {
+		_, err := Predict(dailySeries(from, 10, func(i int) float64 { return 1 }), 1, "X")
+		assert.ErrorIs(t, err, ErrUnsupportedFrequency)
+	})
+
+	t.Run("empty series", func(t *testing.T) {
+		_, err := Predict(nil, 1, FrequencyDay)
+		assert.ErrorIs(t, err, ErrEmptySeries)
+	})
+}
+
+func TestPredictFormats(t *testing.T) {
+	tests := []struct {
+		name          string
+		stepFrequency string
+		series        []string
+		wantNextDS    string
+	}{
+		{
+			name:          "hour",
+			stepFrequency: FrequencyHour,
+			series:        []string{"2024-01-01-22:00", "2024-01-01-23:00"},
+			wantNextDS:    "2024-01-02 00:00",
+		},
+		{
+			name:          "week",
+			stepFrequency: FrequencyWeek,
+			series:        []string{"2023-W51", "2023-W52"},
+			wantNextDS:    "2024-W01",
+		},
+		{
+			name:          "month",
+			stepFrequency: FrequencyMonth,
+			series:        []string{"2023-11", "2023-12"},
+			wantNextDS:    "2024-01-01 00:00",
+		},
+		{
+			name:          "quarter",
+			stepFrequency: FrequencyQuarter,
+			series:        []string{"2023-Q3", "2023-Q4"},
+			wantNextDS:    "2024-Q1",
+		},
+		{
+			name:          "year",
+			stepFrequency: FrequencyYear,
+			series:        []string{"2022", "2023"},
+			wantNextDS:    "2024-01-01 00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := make([]Observation, len(tt.series))
+			for i, ds := range tt.series {
+				series[i] = Observation{DS: ds, Value: float64(i + 1)}
+			}
+
+			predictions, err := Predict(series, 1, tt.stepFrequency)
+			assert.NoError(t, err)
+			assert.Len(t, predictions, len(series)+1)
+			assert.Equal(t, tt.wantNextDS, predictions[len(series)].DS)
+			assert.False(t, math.IsNaN(predictions[len(series)].Value), fmt.Sprintf("%+v", predictions[len(series)]))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package local
+
+import "math"
+
+const (
+	// dampingFactor keeps long horizons from extrapolating the trend indefinitely.
+	dampingFactor = 0.98
+	// smoothingGridStep is the resolution of the alpha/beta grid search.
+	smoothingGridStep = 0.05
+)
+
+// fit is the result of fitting the model on a series.
+type fit struct {
+	fitted   []float64
+	forecast []float64
+	// sigma is the standard deviation of the one step ahead errors.
+	sigma float64
+}
+
+// fitModel decomposes the series into a seasonal component and a seasonally adjusted
+// component when there are at least two full cycles, fits a damped Holt linear trend on
+// the seasonally adjusted series and forecasts the next steps.
+func fitModel(y []float64, periodicity, steps int) *fit {
+	seasonal := seasonalIndexes(y, periodicity)
+
+	adjusted := make([]float64, len(y))
+	for i, v := range y {
+		adjusted[i] = v - seasonalAt(seasonal, i)
+	}
+
+	alpha, beta := bestSmoothingParams(adjusted)
+	fitted, level, trend := holt(adjusted, alpha, beta)
+
+	res := &fit{
+		fitted:   make([]float64, len(y)),
+		forecast: make([]float64, steps),
+	}
+
+	var sse float64
+
+	for i := range y {
+		res.fitted[i] = fitted[i] + seasonalAt(seasonal, i)
+
+		if i > 0 {
+			sse += math.Pow(y[i]-res.fitted[i], 2)
+		}
+	}
+
+	if len(y) > 1 {
+		res.sigma = math.Sqrt(sse / float64(len(y)-1))
+	}
+
+	damped := 0.0
+	phi := 1.0
+
+	for h := 1; h <= steps; h++ {
+		phi *= dampingFactor
+		damped += phi
+		res.forecast[h-1] = level + damped*trend + seasonalAt(seasonal, len(y)+h-1)
+	}
+
+	return res
+}
+
+// seasonalIndexes returns the average seasonal component for each phase of the cycle
+// using a classical additive decomposition, or nil when the series is too short or has
+// no seasonality. The trend is estimated with a centered moving average over one cycle.
+// chewxy/stl, used by the trend detection, does not recover short cycles reliably
+// enough for point forecasts.
+func seasonalIndexes(y []float64, periodicity int) []float64 {
+	if periodicity < 2 || len(y) < 2*periodicity {
+		return nil
+	}
+
+	trend := centeredMovingAverage(y, periodicity)
+	indexes := make([]float64, periodicity)
+	counts := make([]int, periodicity)
+
+	for i, v := range y {
+		if math.IsNaN(trend[i]) {
+			continue
+		}
+
+		indexes[i%periodicity] += v - trend[i]
+		counts[i%periodicity]++
+	}
+
+	var mean float64
+
+	for i := range indexes {
+		indexes[i] /= float64(counts[i])
+		mean += indexes[i]
+	}
+
+	mean /= float64(periodicity)
+
+	for i := range indexes {
+		indexes[i] -= mean
+	}
+
+	return indexes
+}
+
+// centeredMovingAverage returns the centered moving average of the series over the given
+// window. An even window uses a 2xwindow average so it stays centered on a point.
+// Points without a full window around them are NaN.
+func centeredMovingAverage(y []float64, window int) []float64 {
+	res := make([]float64, len(y))
+	half := window / 2
+
+	for i := range y {
+		if i-half < 0 || i+half >= len(y) {
+			res[i] = math.NaN()
+			continue
+		}
+
+		var sum float64
+
+		if window%2 == 1 {
+			for j := i - half; j <= i+half; j++ {
+				sum += y[j]
+			}
+
+			res[i] = sum / float64(window)
+
+			continue
+		}
+
+		for j := i - half + 1; j < i+half; j++ {
+			sum += y[j]
+		}
+
+		sum += (y[i-half] + y[i+half]) / 2
+		res[i] = sum / float64(window)
+	}
+
+	return res
+}
+
+func seasonalAt(indexes []float64, i int) float64 {
+	if len(indexes) == 0 {
+		return 0
+	}
+
+	return indexes[i%len(indexes)]
+}
+
+// bestSmoothingParams grid searches the level and trend smoothing parameters that
+// minimize the sum of squared one step ahead errors.
+func bestSmoothingParams(y []float64) (float64, float64) {
+	bestAlpha, bestBeta := 0.5, 0.1
+	bestSSE := math.Inf(1)
+
+	for alpha := smoothingGridStep; alpha < 1; alpha += smoothingGridStep {
+		for beta := smoothingGridStep; beta < 1; beta += smoothingGridStep {
+			fitted, _, _ := holt(y, alpha, beta)
+
+			var sse float64
+			for i := 1; i < len(y); i++ {
+				sse += math.Pow(y[i]-fitted[i], 2)
+			}
+
+			if sse < bestSSE {
+				bestSSE = sse
+				bestAlpha, bestBeta = alpha, beta
+			}
+		}
+	}
+
+	return bestAlpha, bestBeta
+}
+
+// holt runs damped Holt linear exponential smoothing and returns the one step ahead
+// fitted values along with the final level and trend.
+func holt(y []float64, alpha, beta float64) ([]float64, float64, float64) {
+	fitted := make([]float64, len(y))
+	if len(y) == 0 {
+		return fitted, 0, 0
+	}
+
+	level := y[0]
+	trend := 0.0
+
+	if len(y) > 1 {
+		trend = y[1] - y[0]
+	}
+
+	fitted[0] = y[0]
+
+	for i := 1; i < len(y); i++ {
+		fitted[i] = level + dampingFactor*trend
+		prevLevel := level
+		level = alpha*y[i] + (1-alpha)*(level+dampingFactor*trend)
+		trend = beta*(level-prevLevel) + (1-beta)*dampingFactor*trend
+	}
+
+	return fitted, level, trend
+}
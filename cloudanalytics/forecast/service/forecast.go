@@ -18,7 +18,6 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 	"github.com/doitintl/hello/scheduled-tasks/slice"
 	"github.com/doitintl/hello/scheduled-tasks/times"
-	"github.com/doitintl/http"
 )
 
 var intervalToStepFrequency = map[string]string{
@@ -43,28 +42,89 @@ func (s *Service) GetForecastOriginAndResultRows(
 	return s.getForecastOriginAndResultRows(ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
 }
 
+// GetForecastPredictions returns the forecasted points of the query result, starting from the forecast cutoff.
+// Unlike the result rows, the predictions also contain the lower and upper bounds of the forecast.
+func (s *Service) GetForecastPredictions(
+	ctx context.Context,
+	queryResultRows [][]bigquery.Value,
+	queryRequestRows int,
+	queryRequestCols []*domainQuery.QueryRequestX,
+	interval string,
+	metric int,
+	maxRefreshTime, from, to time.Time,
+) ([]*domain.ModelSeries, error) {
+	res, err := s.getForecastResponse(ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
+	if err != nil || res == nil {
+		return nil, err
+	}
+
+	predictions := make([]*domain.ModelSeries, 0, len(res.Prediction))
+
+	for _, prediction := range res.Prediction {
+		if !prediction.Ignore {
+			predictions = append(predictions, prediction)
+		}
+	}
+
+	return predictions, nil
+}
+
+// makeForecastRequest computes the forecast with the engine selected on the context.
+// By default the remote forecast service is used, and the local engine is used when it fails.
 func (s *Service) makeForecastRequest(ctx context.Context, rawReq *domain.ForecastRequest) (*domain.ForecastResponse, error) {
 	l := s.loggerProvider(ctx)
 
-	var forecast domain.ForecastResponse
+	switch engine := engineFromContext(ctx); engine {
+	case domain.EngineLocal:
+		return s.localForecaster.Predict(ctx, rawReq)
+	case domain.EngineRemote:
+		return s.remoteForecaster.Predict(ctx, rawReq)
+	case domain.EngineAuto:
+		forecast, err := s.remoteForecaster.Predict(ctx, rawReq)
+		if err == nil {
+			return forecast, nil
+		}
 
-	resp, err := s.httpClient.Post(ctx, &http.Request{
-		URL:          "/predict",
-		Payload:      rawReq,
-		ResponseType: &forecast,
-	})
+		l.Warningf("falling back to local forecast engine: %s", err)
+
+		return s.localForecaster.Predict(ctx, rawReq)
+	default:
+		return nil, fmt.Errorf("unsupported forecast engine %s", engine)
+	}
+}
+
+func (s *Service) getForecastResponse(ctx context.Context, queryResultRows [][]bigquery.Value, queryRequestRows int, queryRequestCols []*domainQuery.QueryRequestX, interval string, metric int, maxRefreshTime, from, to time.Time) (*domain.ForecastResponse, error) {
+	forecastData, forecastCutOff, err := getForecastDataAndCutOff(queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
 	if err != nil {
-		l.Errorf("%+v", resp)
-		return nil, fmt.Errorf("Forecast request failed with error: %s", err)
+		return nil, err
 	}
 
-	return &forecast, nil
+	return s.getPredictions(ctx, forecastData, interval, from, to, forecastCutOff)
 }
 
 func (s *Service) getForecastOriginAndResultRows(ctx context.Context, queryResultRows [][]bigquery.Value, queryRequestRows int, queryRequestCols []*domainQuery.QueryRequestX, interval string, metric int, maxRefreshTime, from, to time.Time) ([]*domain.OriginSeries, [][]bigquery.Value, error) {
+	forecastData, forecastCutOff, err := getForecastDataAndCutOff(queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	forecastRows, err := s.getForecasts(ctx, forecastData, interval, from, to, forecastCutOff)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkMatchingDateFields(forecastRows, queryResultRows, len(queryRequestCols)); err != nil {
+		return nil, nil, err
+	}
+
+	return forecastData, forecastRows, nil
+}
+
+// getForecastDataAndCutOff returns the data points that are used as the forecast input, and the forecast cutoff
+func getForecastDataAndCutOff(queryResultRows [][]bigquery.Value, queryRequestRows int, queryRequestCols []*domainQuery.QueryRequestX, interval string, metric int, maxRefreshTime, from, to time.Time) ([]*domain.OriginSeries, time.Time, error) {
 	// week_day is currently not supported for forecasts
 	if weekDayUsed(queryRequestCols) {
-		return nil, nil, errors.New("unsupported col week_day used in forecasts")
+		return nil, time.Time{}, errors.New("unsupported col week_day used in forecasts")
 	}
 
 	forecastData := make([]*domain.OriginSeries, 0)
@@ -72,7 +132,7 @@ func (s *Service) getForecastOriginAndResultRows(ctx context.Context, queryResul
 	for _, row := range queryResultRows {
 		forecastDataPoint, err := formatForecastRow(row, queryRequestCols, queryRequestRows, metric)
 		if err != nil {
-			return nil, nil, err
+			return nil, time.Time{}, err
 		}
 
 		if forecastDataPoint.IsBeforePeriod(interval, maxRefreshTime) {
@@ -80,25 +140,12 @@ func (s *Service) getForecastOriginAndResultRows(ctx context.Context, queryResul
 		}
 	}
 
-	var lastDateWithData *time.Time
-
 	lastDateWithData, err := utils.GetLatestDateWithData(&from, &to, queryRequestRows, report.TimeInterval(interval), &queryResultRows)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	forecastCutOff := getForecastCutOff(*lastDateWithData, to, report.TimeInterval(interval))
-
-	forecastRows, err := s.getForecasts(ctx, forecastData, interval, from, to, forecastCutOff)
-	if err != nil {
-		return nil, nil, err
+		return nil, time.Time{}, err
 	}
 
-	if err := checkMatchingDateFields(forecastRows, queryResultRows, len(queryRequestCols)); err != nil {
-		return nil, nil, err
-	}
-
-	return forecastData, forecastRows, nil
+	return forecastData, getForecastCutOff(*lastDateWithData, to, report.TimeInterval(interval)), nil
 }
 
 // getForecastCutOff computes the forecast cutoff that corresponds to the time interval.
@@ -197,6 +244,21 @@ func checkMatchingDateFields(forecastRows [][]bigquery.Value, queryResultRows []
 }
 
 func (s *Service) getForecasts(ctx context.Context, rawData []*domain.OriginSeries, interval string, from, to time.Time, forecastCutOff time.Time) ([][]bigquery.Value, error) {
+	cleanForecasts, err := s.getPredictions(ctx, rawData, interval, from, to, forecastCutOff)
+	if err != nil || cleanForecasts == nil {
+		return nil, err
+	}
+
+	rows, err := makePredictionRows(cleanForecasts, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// getPredictions returns the cleaned forecast response, or nil if the series is too short to forecast
+func (s *Service) getPredictions(ctx context.Context, rawData []*domain.OriginSeries, interval string, from, to time.Time, forecastCutOff time.Time) (*domain.ForecastResponse, error) {
 	series := aggregateForecastRows(rawData)
 	if len(series) < 8 {
 		return nil, nil
@@ -226,14 +288,7 @@ func (s *Service) getForecasts(ctx context.Context, rawData []*domain.OriginSeri
 		return nil, err
 	}
 
-	cleanForecasts := cleanForecastResponse(res, interval, from, forecastCutOff)
-
-	rows, err := makePredictionRows(cleanForecasts, interval)
-	if err != nil {
-		return nil, err
-	}
-
-	return rows, nil
+	return cleanForecastResponse(res, interval, from, forecastCutOff), nil
 }
 
 // aggregateForecastRows creates unique array by dates (sums up all services for given date)
@@ -1,6 +1,8 @@
 package forecasts
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -12,6 +14,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/domain"
 	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
 )
 
 func Test_formatForecastRow(t *testing.T) {
@@ -431,3 +434,143 @@ func TestGetForecastCutOff(t *testing.T) {
 		})
 	}
 }
+
+type fakeForecaster struct {
+	res   *domain.ForecastResponse
+	err   error
+	calls int
+}
+
+func (f *fakeForecaster) Predict(_ context.Context, _ *domain.ForecastRequest) (*domain.ForecastResponse, error) {
+	f.calls++
+	return f.res, f.err
+}
+
+func TestMakeForecastRequest(t *testing.T) {
+	remoteRes := &domain.ForecastResponse{Prediction: []*domain.ModelSeries{{DS: "remote"}}}
+	localRes := &domain.ForecastResponse{Prediction: []*domain.ModelSeries{{DS: "local"}}}
+	remoteErr := errors.New("remote failed")
+
+	tests := []struct {
+		name        string
+		engine      *domain.Engine
+		remoteErr   error
+		want        *domain.ForecastResponse
+		wantErr     bool
+		remoteCalls int
+		localCalls  int
+	}{
+		{
+			name:        "auto uses remote",
+			want:        remoteRes,
+			remoteCalls: 1,
+		},
+		{
+			name:        "auto falls back to local when remote fails",
+			remoteErr:   remoteErr,
+			want:        localRes,
+			remoteCalls: 1,
+			localCalls:  1,
+		},
+		{
+			name:        "remote engine does not fall back",
+			engine:      func() *domain.Engine { e := domain.EngineRemote; return &e }(),
+			remoteErr:   remoteErr,
+			wantErr:     true,
+			remoteCalls: 1,
+		},
+		{
+			name:       "local engine skips remote",
+			engine:     func() *domain.Engine { e := domain.EngineLocal; return &e }(),
+			want:       localRes,
+			localCalls: 1,
+		},
+		{
+			name:    "unsupported engine",
+			engine:  func() *domain.Engine { e := domain.Engine("other"); return &e }(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := &fakeForecaster{res: remoteRes, err: tt.remoteErr}
+			local := &fakeForecaster{res: localRes}
+
+			s := &Service{
+				loggerProvider:   logger.FromContext,
+				remoteForecaster: remote,
+				localForecaster:  local,
+			}
+
+			ctx := context.Background()
+			if tt.engine != nil {
+				ctx = WithEngine(ctx, *tt.engine)
+			}
+
+			got, err := s.makeForecastRequest(ctx, &domain.ForecastRequest{})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.Equal(t, tt.remoteCalls, remote.calls)
+			assert.Equal(t, tt.localCalls, local.calls)
+		})
+	}
+}
+
+func TestGetForecastPredictions(t *testing.T) {
+	yearCol, err := domainQuery.NewCol("year")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	monthCol, err := domainQuery.NewCol("month")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cols := []*domainQuery.QueryRequestX{yearCol, monthCol}
+
+	newRows := func(months int) [][]bigquery.Value {
+		rows := make([][]bigquery.Value, 0, months)
+		for i := 1; i <= months; i++ {
+			rows = append(rows, []bigquery.Value{"2024", fmt.Sprintf("%02d", i), float64(100 + i)})
+		}
+
+		return rows
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	maxRefreshTime := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	s := &Service{
+		loggerProvider:  logger.FromContext,
+		localForecaster: &localForecaster{},
+	}
+
+	ctx := WithEngine(context.Background(), domain.EngineLocal)
+
+	t.Run("predictions start after the last month with data", func(t *testing.T) {
+		predictions, err := s.GetForecastPredictions(ctx, newRows(9), 0, cols, string(report.TimeIntervalMonth), 0, maxRefreshTime, from, to)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, predictions)
+		assert.Equal(t, "2024-10", predictions[0].DS)
+
+		for _, prediction := range predictions {
+			assert.False(t, prediction.Ignore)
+			assert.LessOrEqual(t, prediction.Lower, prediction.Value)
+			assert.GreaterOrEqual(t, prediction.Upper, prediction.Value)
+		}
+	})
+
+	t.Run("series is too short", func(t *testing.T) {
+		predictions, err := s.GetForecastPredictions(ctx, newRows(5), 0, cols, string(report.TimeIntervalMonth), 0, maxRefreshTime, from, to)
+		assert.NoError(t, err)
+		assert.Nil(t, predictions)
+	})
+}
@@ -0,0 +1,88 @@
+package forecasts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/local"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/http"
+)
+
+// Forecaster computes the prediction for a forecast request.
+type Forecaster interface {
+	Predict(ctx context.Context, req *domain.ForecastRequest) (*domain.ForecastResponse, error)
+}
+
+type engineContextKey struct{}
+
+// WithEngine returns a context that selects the forecast engine used by the service.
+func WithEngine(ctx context.Context, engine domain.Engine) context.Context {
+	return context.WithValue(ctx, engineContextKey{}, engine)
+}
+
+func engineFromContext(ctx context.Context) domain.Engine {
+	if engine, ok := ctx.Value(engineContextKey{}).(domain.Engine); ok {
+		return engine
+	}
+
+	return domain.EngineAuto
+}
+
+// remoteForecaster calls the forecast service /predict endpoint.
+type remoteForecaster struct {
+	loggerProvider logger.Provider
+	httpClient     *http.Client
+}
+
+func (f *remoteForecaster) Predict(ctx context.Context, req *domain.ForecastRequest) (*domain.ForecastResponse, error) {
+	l := f.loggerProvider(ctx)
+
+	var forecast domain.ForecastResponse
+
+	resp, err := f.httpClient.Post(ctx, &http.Request{
+		URL:          "/predict",
+		Payload:      req,
+		ResponseType: &forecast,
+	})
+	if err != nil {
+		l.Errorf("%+v", resp)
+		return nil, fmt.Errorf("Forecast request failed with error: %s", err)
+	}
+
+	return &forecast, nil
+}
+
+// localForecaster computes the forecast in-process.
+type localForecaster struct{}
+
+func (f *localForecaster) Predict(_ context.Context, req *domain.ForecastRequest) (*domain.ForecastResponse, error) {
+	series := make([]local.Observation, len(req.Series))
+	for i, point := range req.Series {
+		series[i] = local.Observation{
+			DS:    point.DS,
+			Value: point.Value,
+		}
+	}
+
+	predictions, err := local.Predict(series, req.Steps, req.StepFrequency)
+	if err != nil {
+		return nil, fmt.Errorf("local forecast failed with error: %w", err)
+	}
+
+	forecast := &domain.ForecastResponse{
+		Prediction: make([]*domain.ModelSeries, len(predictions)),
+	}
+
+	for i, p := range predictions {
+		forecast.Prediction[i] = &domain.ModelSeries{
+			DS:    p.DS,
+			Value: p.Value,
+			Lower: p.Lower,
+			Upper: p.Upper,
+		}
+	}
+
+	return forecast, nil
+}
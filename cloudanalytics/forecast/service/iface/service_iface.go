@@ -21,4 +21,13 @@ type Service interface {
 		metric int,
 		maxRefreshTime, from, to time.Time,
 	) ([]*domain.OriginSeries, [][]bigquery.Value, error)
+	GetForecastPredictions(
+		ctx context.Context,
+		queryResultRows [][]bigquery.Value,
+		queryRequestRows int,
+		queryRequestCols []*domainQuery.QueryRequestX,
+		interval string,
+		metric int,
+		maxRefreshTime, from, to time.Time,
+	) ([]*domain.ModelSeries, error)
 }
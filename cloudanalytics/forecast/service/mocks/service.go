@@ -60,6 +60,36 @@ func (_m *Service) GetForecastOriginAndResultRows(ctx context.Context, queryResu
 	return r0, r1, r2
 }
 
+// GetForecastPredictions provides a mock function with given fields: ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to
+func (_m *Service) GetForecastPredictions(ctx context.Context, queryResultRows [][]bigquery.Value, queryRequestRows int, queryRequestCols []*domain.QueryRequestX, interval string, metric int, maxRefreshTime time.Time, from time.Time, to time.Time) ([]*forecastdomain.ModelSeries, error) {
+	ret := _m.Called(ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForecastPredictions")
+	}
+
+	var r0 []*forecastdomain.ModelSeries
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, [][]bigquery.Value, int, []*domain.QueryRequestX, string, int, time.Time, time.Time, time.Time) ([]*forecastdomain.ModelSeries, error)); ok {
+		return rf(ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, [][]bigquery.Value, int, []*domain.QueryRequestX, string, int, time.Time, time.Time, time.Time) []*forecastdomain.ModelSeries); ok {
+		r0 = rf(ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*forecastdomain.ModelSeries)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, [][]bigquery.Value, int, []*domain.QueryRequestX, string, int, time.Time, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, queryResultRows, queryRequestRows, queryRequestCols, interval, metric, maxRefreshTime, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewService(t interface {
@@ -17,8 +17,9 @@ const (
 )
 
 type Service struct {
-	loggerProvider logger.Provider
-	httpClient     *http.Client
+	loggerProvider   logger.Provider
+	remoteForecaster Forecaster
+	localForecaster  Forecaster
 }
 
 func getServiceHost() string {
@@ -53,7 +54,11 @@ func NewService(loggerProvider logger.Provider) (iface.Service, error) {
 	}
 
 	return &Service{
-		loggerProvider,
-		httpClient,
+		loggerProvider: loggerProvider,
+		remoteForecaster: &remoteForecaster{
+			loggerProvider: loggerProvider,
+			httpClient:     httpClient,
+		},
+		localForecaster: &localForecaster{},
 	}, nil
 }
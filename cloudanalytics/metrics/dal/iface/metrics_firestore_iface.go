@@ -15,4 +15,7 @@ type Metrics interface {
 	GetCustomMetric(ctx context.Context, calculatedMetricID string) (*metrics.CalculatedMetric, error)
 	GetMetricsUsingAttr(ctx context.Context, attrRef *firestore.DocumentRef) ([]*metrics.CalculatedMetric, error)
 	DeleteMany(ctx context.Context, IDs []string) error
+	ListCustomerMetrics(ctx context.Context, customerRef *firestore.DocumentRef) ([]*metrics.CalculatedMetric, error)
+	Create(ctx context.Context, metric *metrics.CalculatedMetric) (*metrics.CalculatedMetric, error)
+	Update(ctx context.Context, calculatedMetricID string, updates []firestore.Update) error
 }
@@ -122,9 +122,62 @@ func (d *MetricsFirestore) GetCustomMetric(ctx context.Context, calculatedMetric
 		return nil, err
 	}
 
+	metric.ID = metricSnap.ID()
+
 	return &metric, nil
 }
 
+// ListCustomerMetrics returns the custom metrics of a customer together with the preset metrics.
+func (d *MetricsFirestore) ListCustomerMetrics(ctx context.Context, customerRef *firestore.DocumentRef) ([]*metrics.CalculatedMetric, error) {
+	collection := d.firestoreClientFun(ctx).Collection(MetricsCollection)
+
+	customDocs, err := collection.Where("customer", "==", customerRef).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	presetDocs, err := collection.Where("type", "==", metrics.MetricTypePreset).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	calculatedMetrics := make([]*metrics.CalculatedMetric, 0, len(customDocs)+len(presetDocs))
+
+	for _, doc := range append(customDocs, presetDocs...) {
+		var calculatedMetric metrics.CalculatedMetric
+		if err := doc.DataTo(&calculatedMetric); err != nil {
+			return nil, err
+		}
+
+		calculatedMetric.ID = doc.Ref.ID
+
+		calculatedMetrics = append(calculatedMetrics, &calculatedMetric)
+	}
+
+	return calculatedMetrics, nil
+}
+
+// Create adds a new custom metric and returns it with its ID set.
+func (d *MetricsFirestore) Create(ctx context.Context, metric *metrics.CalculatedMetric) (*metrics.CalculatedMetric, error) {
+	docRef, _, err := d.firestoreClientFun(ctx).Collection(MetricsCollection).Add(ctx, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	metric.ID = docRef.ID
+
+	return metric, nil
+}
+
+// Update applies the given updates to a custom metric.
+func (d *MetricsFirestore) Update(ctx context.Context, calculatedMetricID string, updates []firestore.Update) error {
+	docRef := d.GetRef(ctx, calculatedMetricID)
+
+	_, err := d.documentsHandler.Update(ctx, docRef, updates)
+
+	return err
+}
+
 func (d *MetricsFirestore) DeleteMany(ctx context.Context, IDs []string) error {
 	metricsRefs := make([]*firestore.DocumentRef, len(IDs))
 
@@ -17,6 +17,32 @@ type Metrics struct {
 	mock.Mock
 }
 
+// Create provides a mock function with given fields: ctx, metric
+func (_m *Metrics) Create(ctx context.Context, metric *metrics.CalculatedMetric) (*metrics.CalculatedMetric, error) {
+	ret := _m.Called(ctx, metric)
+
+	var r0 *metrics.CalculatedMetric
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *metrics.CalculatedMetric) (*metrics.CalculatedMetric, error)); ok {
+		return rf(ctx, metric)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *metrics.CalculatedMetric) *metrics.CalculatedMetric); ok {
+		r0 = rf(ctx, metric)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metrics.CalculatedMetric)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *metrics.CalculatedMetric) error); ok {
+		r1 = rf(ctx, metric)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteMany provides a mock function with given fields: ctx, IDs
 func (_m *Metrics) DeleteMany(ctx context.Context, IDs []string) error {
 	ret := _m.Called(ctx, IDs)
@@ -123,6 +149,46 @@ func (_m *Metrics) GetRef(ctx context.Context, calculatedMetricID string) *fires
 	return r0
 }
 
+// ListCustomerMetrics provides a mock function with given fields: ctx, customerRef
+func (_m *Metrics) ListCustomerMetrics(ctx context.Context, customerRef *firestore.DocumentRef) ([]*metrics.CalculatedMetric, error) {
+	ret := _m.Called(ctx, customerRef)
+
+	var r0 []*metrics.CalculatedMetric
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *firestore.DocumentRef) ([]*metrics.CalculatedMetric, error)); ok {
+		return rf(ctx, customerRef)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *firestore.DocumentRef) []*metrics.CalculatedMetric); ok {
+		r0 = rf(ctx, customerRef)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*metrics.CalculatedMetric)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *firestore.DocumentRef) error); ok {
+		r1 = rf(ctx, customerRef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, calculatedMetricID, updates
+func (_m *Metrics) Update(ctx context.Context, calculatedMetricID string, updates []firestore.Update) error {
+	ret := _m.Called(ctx, calculatedMetricID, updates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []firestore.Update) error); ok {
+		r0 = rf(ctx, calculatedMetricID, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMetrics creates a new instance of Metrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMetrics(t interface {
@@ -12,6 +12,12 @@ const (
 	MetricPercentageFormat
 )
 
+// Metric Type
+const (
+	MetricTypeCustom = "custom"
+	MetricTypePreset = "preset"
+)
+
 type CalculatedMetric struct {
 	Name        string                      `json:"name" firestore:"name"`
 	Description string                      `json:"description" firestore:"description"`
@@ -30,3 +36,44 @@ type CalculatedMetricVariable struct {
 	Metric      report.Metric          `json:"metric" firestore:"metric"`
 	Attribution *firestore.DocumentRef `json:"attribution" firestore:"attribution"`
 }
+
+type MetricRequestData struct {
+	// A field by which the results will be sorted.
+	// Required: false
+	// Enum: id,name,type
+	SortBy string `json:"sortBy"`
+	// Sort order of Metrics can be either ascending or descending.
+	// Required: false
+	// Enum: asc,desc
+	SortOrder string `json:"sortOrder"`
+	// The maximum number of results to return in a single page. Leverage the page tokens to iterate through the entire collection.
+	// Required: false
+	// Default: 500
+	// Type: integer
+	MaxResults string `json:"maxResults"`
+	// Page token, returned by a previous call, to request the next page of results
+	PageToken string `json:"pageToken,omitempty"`
+	// An expression for filtering the results of the request. The syntax is "key:[<value>]". e.g: "name:test". Multiple filters can be connected using a pipe |. Note that using different keys in the same filter results in “AND,” while using the same key multiple times in the same filter results in “OR”.
+	// Available filters: owner, name, type
+	Filter     string `json:"filter"`
+	Email      string `json:"-"`
+	CustomerID string `json:"-"`
+}
+
+func (m *MetricRequestData) GetFilter() string          { return m.Filter }
+func (m *MetricRequestData) GetMaxResults() string      { return m.MaxResults }
+func (m *MetricRequestData) GetNextPageToken() string   { return m.PageToken }
+func (m *MetricRequestData) GetSortBy() string          { return m.SortBy }
+func (m *MetricRequestData) GetSortOrder() string       { return m.SortOrder }
+func (m *MetricRequestData) GetCustomerID() string      { return m.CustomerID }
+func (m *MetricRequestData) GetEmail() string           { return m.Email }
+func (m *MetricRequestData) GetMinCreationTime() string { return "" }
+func (m *MetricRequestData) GetMaxCreationTime() string { return "" }
+
+func (m *MetricRequestData) GetAllowedFilters() map[string]string {
+	return map[string]string{"owner": "Owner", "name": "Name", "type": "Type"}
+}
+
+func (m *MetricRequestData) GetAllowedSortBy() map[string]string {
+	return map[string]string{"id": firestore.DocumentID, "name": "name", "type": "type"}
+}
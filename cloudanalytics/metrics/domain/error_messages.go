@@ -9,4 +9,8 @@ const (
 	ErrCustomMetricNotFoundMsg        = "custom metric not found"
 	ErrExtendedMetricValueMsg         = "extended metric value can not be empty"
 	ErrExtendedMetricValueNotFoundMsg = "extended metric value not found"
+	ErrInvalidFormulaMsg              = "invalid formula"
+	ErrVariableMetricTypeMsg          = "variable metric must be a basic metric"
+	ErrAttributionNotFoundMsg         = "attribution not found"
+	ErrInvalidFormatMsg               = "invalid metric format"
 )
@@ -2,4 +2,13 @@ package metrics
 
 import "errors"
 
-var ErrValidation = errors.New("validation error")
+var (
+	ErrValidation         = errors.New("validation error")
+	ErrMissingMetricID    = errors.New("missing metric id")
+	ErrNotFound           = errors.New("metric not found")
+	ErrForbidden          = errors.New("metric does not belong to your organization")
+	ErrPresetMetricUpdate = errors.New("preset metrics cannot be updated")
+	ErrEmptyBody          = errors.New("request body is empty")
+	ErrGetMetric          = errors.New("failed to get metric")
+	ErrListMetrics        = errors.New("failed to list metrics")
+)
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/doitintl/auth"
+	"github.com/doitintl/customerapi"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/service"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+func (h *Metric) ExternalAPIGetMetric(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	metricID := ctx.Param("id")
+
+	if metricID == "" {
+		return web.NewRequestError(metrics.ErrMissingMetricID, http.StatusBadRequest)
+	}
+
+	l.SetLabels(map[string]string{
+		logger.LabelCustomerID: customerID,
+		"metricId":             metricID,
+	})
+
+	m, err := h.service.GetMetric(ctx, customerID, metricID)
+	if err != nil {
+		switch {
+		case errors.Is(err, metrics.ErrNotFound):
+			return web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, metrics.ErrForbidden):
+			return web.NewRequestError(err, http.StatusForbidden)
+		}
+
+		l.Errorf("Failed to get metric %s \n Error: %v", metricID, err)
+
+		return web.NewRequestError(metrics.ErrGetMetric, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, m, http.StatusOK)
+}
+
+func (h *Metric) ExternalAPIListMetrics(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	email := ctx.GetString(common.CtxKeys.Email)
+
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+	})
+
+	r := metrics.MetricRequestData{
+		SortBy:     ctx.Request.URL.Query().Get("sortBy"),
+		SortOrder:  ctx.Request.URL.Query().Get("sortOrder"),
+		MaxResults: ctx.Request.URL.Query().Get("maxResults"),
+		PageToken:  ctx.Request.URL.Query().Get("pageToken"),
+		Filter:     ctx.Request.URL.Query().Get("filter"),
+		Email:      email,
+		CustomerID: customerID,
+	}
+
+	reqData, err := customerapi.NewAPIRequest(&r)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	metricsList, err := h.service.ListMetrics(ctx, service.ExternalAPIListArgsReq{
+		CustomerID:    customerID,
+		Email:         email,
+		SortBy:        reqData.SortBy,
+		SortOrder:     reqData.SortOrder,
+		Filters:       reqData.Filters,
+		MaxResults:    reqData.MaxResults,
+		NextPageToken: reqData.NextPageToken,
+	})
+	if err != nil {
+		l.Errorf("Failed to get list of metrics\n Error: %v", err)
+		return web.NewRequestError(metrics.ErrListMetrics, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, metricsList, http.StatusOK)
+}
+
+func (h *Metric) ExternalAPICreateMetric(ctx *gin.Context) error {
+	email := ctx.GetString(common.CtxKeys.Email)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+
+	l := h.loggerProvider(ctx)
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+	})
+
+	var metricRequest service.MetricRequest
+
+	if err := ctx.ShouldBindJSON(&metricRequest); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": errormsg.MapTagValidationErrors(err, false)})
+		return nil
+	}
+
+	resp := h.service.CreateMetric(ctx, service.ExternalAPICreateUpdateArgsReq{
+		MetricRequest: &metricRequest,
+		CustomerID:    customerID,
+		Email:         email,
+	})
+
+	if resp.Error != nil {
+		if errors.Is(resp.Error, metrics.ErrValidation) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": resp.ValidationErrors})
+			return nil
+		}
+
+		return web.NewRequestError(resp.Error, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, resp.Metric, http.StatusCreated)
+}
+
+func (h *Metric) ExternalAPIUpdateMetric(ctx *gin.Context) error {
+	metricID := ctx.Param("id")
+
+	if metricID == "" {
+		return web.NewRequestError(metrics.ErrMissingMetricID, http.StatusBadRequest)
+	}
+
+	email := ctx.GetString(common.CtxKeys.Email)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+
+	l := h.loggerProvider(ctx)
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+		"metricId":             metricID,
+	})
+
+	var metricRequest service.MetricRequest
+
+	if err := ctx.ShouldBindJSON(&metricRequest); err != nil {
+		errors := errormsg.MapTagValidationErrors(err, true)
+		if len(errors) > 0 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": errors})
+			return nil
+		}
+	}
+
+	if (reflect.DeepEqual(metricRequest, service.MetricRequest{})) {
+		return web.NewRequestError(metrics.ErrEmptyBody, http.StatusBadRequest)
+	}
+
+	resp := h.service.UpdateMetric(ctx, metricID, service.ExternalAPICreateUpdateArgsReq{
+		MetricRequest: &metricRequest,
+		CustomerID:    customerID,
+		Email:         email,
+	})
+
+	if resp.Error != nil {
+		switch {
+		case errors.Is(resp.Error, metrics.ErrValidation):
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": resp.ValidationErrors})
+			return nil
+		case errors.Is(resp.Error, metrics.ErrNotFound):
+			return web.NewRequestError(resp.Error, http.StatusNotFound)
+		case errors.Is(resp.Error, metrics.ErrForbidden),
+			errors.Is(resp.Error, metrics.ErrPresetMetricUpdate):
+			return web.NewRequestError(resp.Error, http.StatusForbidden)
+		default:
+			return web.NewRequestError(resp.Error, http.StatusInternalServerError)
+		}
+	}
+
+	return web.Respond(ctx, resp.Metric, http.StatusOK)
+}
+
+func (h *Metric) ExternalAPIDeleteMetric(ctx *gin.Context) error {
+	l := h.loggerProvider(ctx)
+	customerID := ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	email := ctx.GetString(common.CtxKeys.Email)
+	metricID := ctx.Param("id")
+	emptyJSON := struct{}{}
+
+	if metricID == "" {
+		return web.NewRequestError(metrics.ErrMissingMetricID, http.StatusBadRequest)
+	}
+
+	l.SetLabels(map[string]string{
+		logger.LabelEmail:      email,
+		logger.LabelCustomerID: customerID,
+		"metricId":             metricID,
+	})
+
+	if err := h.service.DeleteMetric(ctx, customerID, metricID); err != nil {
+		switch {
+		case errors.Is(err, metrics.ErrNotFound):
+			return web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, metrics.ErrForbidden),
+			errors.As(err, &service.PresetMetricsCannotBeDeletedError{}),
+			errors.As(err, &service.MetricIsInUseError{}),
+			errors.As(err, &service.MetricIsInUseByAlertError{}):
+			return web.NewRequestError(err, http.StatusForbidden)
+		}
+
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, emptyJSON, http.StatusOK)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/auth"
+	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/service"
+	serviceMock "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/service/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+const (
+	customerID = "customer1"
+	metricID   = "metric1"
+)
+
+func getExternalContext(method string, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	request := httptest.NewRequest(method, "http://example.com/analytics/v1/metrics", bytes.NewBufferString(body))
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+	ctx.Params = []gin.Param{{Key: "id", Value: metricID}}
+	ctx.Set(auth.CtxKeyVerifiedCustomerID, customerID)
+
+	return ctx, recorder
+}
+
+func TestMetric_ExternalAPIGetMetric(t *testing.T) {
+	tests := []struct {
+		name        string
+		on          func(s *serviceMock.IMetricsService)
+		expectedErr error
+	}{
+		{
+			name: "success",
+			on: func(s *serviceMock.IMetricsService) {
+				s.On("GetMetric", mock.Anything, customerID, metricID).Return(&service.MetricAPI{ID: metricID}, nil)
+			},
+		},
+		{
+			name: "metric not found",
+			on: func(s *serviceMock.IMetricsService) {
+				s.On("GetMetric", mock.Anything, customerID, metricID).Return(nil, metrics.ErrNotFound)
+			},
+			expectedErr: web.NewRequestError(metrics.ErrNotFound, http.StatusNotFound),
+		},
+		{
+			name: "metric of another customer",
+			on: func(s *serviceMock.IMetricsService) {
+				s.On("GetMetric", mock.Anything, customerID, metricID).Return(nil, metrics.ErrForbidden)
+			},
+			expectedErr: web.NewRequestError(metrics.ErrForbidden, http.StatusForbidden),
+		},
+		{
+			name: "internal error",
+			on: func(s *serviceMock.IMetricsService) {
+				s.On("GetMetric", mock.Anything, customerID, metricID).Return(nil, errors.New("error"))
+			},
+			expectedErr: web.NewRequestError(metrics.ErrGetMetric, http.StatusInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := serviceMock.NewIMetricsService(t)
+			tt.on(s)
+
+			h := &Metric{
+				loggerProvider: logger.FromContext,
+				service:        s,
+			}
+
+			ctx, _ := getExternalContext(http.MethodGet, "")
+
+			err := h.ExternalAPIGetMetric(ctx)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestMetric_ExternalAPICreateMetric(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		on           func(s *serviceMock.IMetricsService)
+		expectedCode int
+	}{
+		{
+			name: "success",
+			body: `{"name":"cost","formula":"A","variables":[{"metric":{"type":"basic","value":"cost"},"attribution":"attr1"}]}`,
+			on: func(s *serviceMock.IMetricsService) {
+				s.On("CreateMetric", mock.Anything, mock.AnythingOfType("service.ExternalAPICreateUpdateArgsReq")).
+					Return(service.ExternalAPICreateUpdateResp{Metric: &service.MetricAPI{ID: metricID}})
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "missing formula",
+			body:         `{"name":"cost","variables":[{"metric":{"type":"basic","value":"cost"},"attribution":"attr1"}]}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation errors",
+			body: `{"name":"cost","formula":"A + B","variables":[{"metric":{"type":"basic","value":"cost"},"attribution":"attr1"}]}`,
+			on: func(s *serviceMock.IMetricsService) {
+				s.On("CreateMetric", mock.Anything, mock.AnythingOfType("service.ExternalAPICreateUpdateArgsReq")).
+					Return(service.ExternalAPICreateUpdateResp{Error: metrics.ErrValidation})
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := serviceMock.NewIMetricsService(t)
+			if tt.on != nil {
+				tt.on(s)
+			}
+
+			h := &Metric{
+				loggerProvider: logger.FromContext,
+				service:        s,
+			}
+
+			ctx, recorder := getExternalContext(http.MethodPost, tt.body)
+
+			err := h.ExternalAPICreateMetric(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestMetric_ExternalAPIDeleteMetric(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceErr  error
+		expectedErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:        "metric used by a report",
+			serviceErr:  service.MetricIsInUseError{ID: metricID},
+			expectedErr: web.NewRequestError(service.MetricIsInUseError{ID: metricID}, http.StatusForbidden),
+		},
+		{
+			name:        "metric used by an alert",
+			serviceErr:  service.MetricIsInUseByAlertError{ID: metricID},
+			expectedErr: web.NewRequestError(service.MetricIsInUseByAlertError{ID: metricID}, http.StatusForbidden),
+		},
+		{
+			name:        "preset metric",
+			serviceErr:  service.PresetMetricsCannotBeDeletedError{ID: metricID},
+			expectedErr: web.NewRequestError(service.PresetMetricsCannotBeDeletedError{ID: metricID}, http.StatusForbidden),
+		},
+		{
+			name:        "metric not found",
+			serviceErr:  metrics.ErrNotFound,
+			expectedErr: web.NewRequestError(metrics.ErrNotFound, http.StatusNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := serviceMock.NewIMetricsService(t)
+			s.On("DeleteMetric", mock.Anything, customerID, metricID).Return(tt.serviceErr)
+
+			h := &Metric{
+				loggerProvider: logger.FromContext,
+				service:        s,
+			}
+
+			ctx, _ := getExternalContext(http.MethodDelete, "")
+
+			err := h.ExternalAPIDeleteMetric(ctx)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
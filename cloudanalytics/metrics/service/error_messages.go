@@ -34,3 +34,11 @@ type MetricIsInUseError struct {
 func (e MetricIsInUseError) Error() string {
 	return fmt.Sprintf("metric %s is used by a report", e.ID)
 }
+
+type MetricIsInUseByAlertError struct {
+	ID string
+}
+
+func (e MetricIsInUseByAlertError) Error() string {
+	return fmt.Sprintf("metric %s is used by an alert", e.ID)
+}
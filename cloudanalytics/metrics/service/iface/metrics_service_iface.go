@@ -17,4 +17,9 @@ type IMetricsService interface {
 	) (*metrics.InternalMetricParameters, []errormsg.ErrorMsg, error)
 	ToExternal(params *metrics.InternalMetricParameters) (*metrics.ExternalMetric, []errormsg.ErrorMsg, error)
 	DeleteMany(ctx context.Context, req service.DeleteMetricsRequest) error
+	GetMetric(ctx context.Context, customerID string, metricID string) (*service.MetricAPI, error)
+	ListMetrics(ctx context.Context, args service.ExternalAPIListArgsReq) (*service.ExternalMetricList, error)
+	CreateMetric(ctx context.Context, args service.ExternalAPICreateUpdateArgsReq) service.ExternalAPICreateUpdateResp
+	UpdateMetric(ctx context.Context, metricID string, args service.ExternalAPICreateUpdateArgsReq) service.ExternalAPICreateUpdateResp
+	DeleteMetric(ctx context.Context, customerID string, metricID string) error
 }
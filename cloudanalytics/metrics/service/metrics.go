@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 
+	alertsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal"
+	alertsDALIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal/iface"
+	attributionsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/dal"
+	attributionsDALIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/dal/iface"
 	extendedMetricDal "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/config/dal"
 	datahubMetricMetricDal "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/dal/datahubmetric"
 	datahubMetricDalIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/dal/datahubmetric/iface"
@@ -10,6 +14,7 @@ import (
 	metricsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/dal/iface"
 	reportsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal"
 	reportsDALIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/iface"
+	customerDAL "github.com/doitintl/hello/scheduled-tasks/customer/dal"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 )
@@ -20,6 +25,9 @@ type MetricsService struct {
 	reportsDAL        reportsDALIface.Reports
 	datahubMetricDAL  datahubMetricDalIface.DataHubMetricFirestore
 	extendedMetricDAL extendedMetricDal.Configs
+	alertsDAL         alertsDALIface.Alerts
+	attributionsDAL   attributionsDALIface.Attributions
+	customersDAL      customerDAL.Customers
 }
 
 func NewMetricsService(
@@ -31,6 +39,9 @@ func NewMetricsService(
 		reportsDAL.NewReportsFirestoreWithClient(conn.Firestore),
 		datahubMetricMetricDal.NewDataHubMetricFirestoreWithClient(conn.Firestore),
 		extendedMetricDal.NewConfigsFirestoreWithClient(conn.Firestore),
+		alertsDAL.NewAlertsFirestoreWithClient(conn.Firestore),
+		attributionsDAL.NewAttributionsFirestoreWithClient(conn.Firestore),
+		customerDAL.NewCustomersFirestoreWithClient(conn.Firestore),
 	}
 }
 
@@ -19,6 +19,20 @@ type IMetricsService struct {
 	mock.Mock
 }
 
+// CreateMetric provides a mock function with given fields: ctx, args
+func (_m *IMetricsService) CreateMetric(ctx context.Context, args service.ExternalAPICreateUpdateArgsReq) service.ExternalAPICreateUpdateResp {
+	ret := _m.Called(ctx, args)
+
+	var r0 service.ExternalAPICreateUpdateResp
+	if rf, ok := ret.Get(0).(func(context.Context, service.ExternalAPICreateUpdateArgsReq) service.ExternalAPICreateUpdateResp); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(service.ExternalAPICreateUpdateResp)
+	}
+
+	return r0
+}
+
 // DeleteMany provides a mock function with given fields: ctx, req
 func (_m *IMetricsService) DeleteMany(ctx context.Context, req service.DeleteMetricsRequest) error {
 	ret := _m.Called(ctx, req)
@@ -33,6 +47,72 @@ func (_m *IMetricsService) DeleteMany(ctx context.Context, req service.DeleteMet
 	return r0
 }
 
+// DeleteMetric provides a mock function with given fields: ctx, customerID, metricID
+func (_m *IMetricsService) DeleteMetric(ctx context.Context, customerID string, metricID string) error {
+	ret := _m.Called(ctx, customerID, metricID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, customerID, metricID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMetric provides a mock function with given fields: ctx, customerID, metricID
+func (_m *IMetricsService) GetMetric(ctx context.Context, customerID string, metricID string) (*service.MetricAPI, error) {
+	ret := _m.Called(ctx, customerID, metricID)
+
+	var r0 *service.MetricAPI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*service.MetricAPI, error)); ok {
+		return rf(ctx, customerID, metricID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *service.MetricAPI); ok {
+		r0 = rf(ctx, customerID, metricID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.MetricAPI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, customerID, metricID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMetrics provides a mock function with given fields: ctx, args
+func (_m *IMetricsService) ListMetrics(ctx context.Context, args service.ExternalAPIListArgsReq) (*service.ExternalMetricList, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 *service.ExternalMetricList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, service.ExternalAPIListArgsReq) (*service.ExternalMetricList, error)); ok {
+		return rf(ctx, args)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, service.ExternalAPIListArgsReq) *service.ExternalMetricList); ok {
+		r0 = rf(ctx, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ExternalMetricList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, service.ExternalAPIListArgsReq) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ToExternal provides a mock function with given fields: params
 func (_m *IMetricsService) ToExternal(params *metrics.InternalMetricParameters) (*metrics.ExternalMetric, []errormsg.ErrorMsg, error) {
 	ret := _m.Called(params)
@@ -103,6 +183,20 @@ func (_m *IMetricsService) ToInternal(ctx context.Context, customerID string, ex
 	return r0, r1, r2
 }
 
+// UpdateMetric provides a mock function with given fields: ctx, metricID, args
+func (_m *IMetricsService) UpdateMetric(ctx context.Context, metricID string, args service.ExternalAPICreateUpdateArgsReq) service.ExternalAPICreateUpdateResp {
+	ret := _m.Called(ctx, metricID, args)
+
+	var r0 service.ExternalAPICreateUpdateResp
+	if rf, ok := ret.Get(0).(func(context.Context, string, service.ExternalAPICreateUpdateArgsReq) service.ExternalAPICreateUpdateResp); ok {
+		r0 = rf(ctx, metricID, args)
+	} else {
+		r0 = ret.Get(0).(service.ExternalAPICreateUpdateResp)
+	}
+
+	return r0
+}
+
 // NewIMetricsService creates a new instance of IMetricsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIMetricsService(t interface {
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/doitintl/customerapi"
+	attributionDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/domain/attribution"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/formula"
+)
+
+const (
+	formulaField   = "formula"
+	variablesField = "variables"
+	formatField    = "format"
+)
+
+func (s *MetricsService) GetMetric(ctx context.Context, customerID string, metricID string) (*MetricAPI, error) {
+	metric, err := s.getCustomerMetric(ctx, customerID, metricID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toMetricAPI(metric)
+}
+
+func (s *MetricsService) ListMetrics(ctx context.Context, args ExternalAPIListArgsReq) (*ExternalMetricList, error) {
+	customerRef := s.customersDAL.GetRef(ctx, args.CustomerID)
+
+	customerMetrics, err := s.metricsDAL.ListCustomerMetrics(ctx, customerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	apiMetrics := toListMetricAPI(customerMetrics)
+
+	filteredMetrics := customerapi.FilterAPIList(apiMetrics, args.Filters)
+
+	sortedMetrics, err := customerapi.SortAPIList(filteredMetrics, args.SortBy, args.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	page, token, err := customerapi.GetEncodedAPIPage(args.MaxResults, args.NextPageToken, sortedMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExternalMetricList{
+		PageToken: token,
+		RowCount:  len(page),
+		Metrics:   page,
+	}, nil
+}
+
+func (s *MetricsService) CreateMetric(ctx context.Context, args ExternalAPICreateUpdateArgsReq) ExternalAPICreateUpdateResp {
+	req := args.MetricRequest
+
+	metricFormula, variables, validationErrors, err := s.validateMetric(ctx, args.CustomerID, req.Formula, req.Variables)
+	if err != nil {
+		return ExternalAPICreateUpdateResp{ValidationErrors: validationErrors, Error: err}
+	}
+
+	format := metrics.MetricNumericFormat
+
+	if req.Format != "" {
+		f, ok := metricFormatToInternal[req.Format]
+		if !ok {
+			return ExternalAPICreateUpdateResp{
+				ValidationErrors: []errormsg.ErrorMsg{{Field: formatField, Message: fmt.Sprintf(msgFormat, metrics.ErrInvalidFormatMsg, req.Format)}},
+				Error:            metrics.ErrValidation,
+			}
+		}
+
+		format = f
+	}
+
+	metric, err := s.metricsDAL.Create(ctx, &metrics.CalculatedMetric{
+		Name:        req.Name,
+		Description: req.Description,
+		Customer:    s.customersDAL.GetRef(ctx, args.CustomerID),
+		Type:        metrics.MetricTypeCustom,
+		Owner:       args.Email,
+		Formula:     metricFormula,
+		Variables:   variables,
+		Format:      format,
+		Labels:      []*firestore.DocumentRef{},
+	})
+	if err != nil {
+		return ExternalAPICreateUpdateResp{Error: err}
+	}
+
+	metricAPI, err := s.toMetricAPI(metric)
+	if err != nil {
+		return ExternalAPICreateUpdateResp{Error: err}
+	}
+
+	return ExternalAPICreateUpdateResp{Metric: metricAPI}
+}
+
+// UpdateMetric updates the fields that are set on the request. The formula is validated against
+// the resulting variables, so the formula and the variables can be updated on their own.
+func (s *MetricsService) UpdateMetric(ctx context.Context, metricID string, args ExternalAPICreateUpdateArgsReq) ExternalAPICreateUpdateResp {
+	currentMetric, err := s.getCustomerMetric(ctx, args.CustomerID, metricID)
+	if err != nil {
+		return ExternalAPICreateUpdateResp{Error: err}
+	}
+
+	if currentMetric.Type == metrics.MetricTypePreset {
+		return ExternalAPICreateUpdateResp{Error: metrics.ErrPresetMetricUpdate}
+	}
+
+	req := args.MetricRequest
+
+	var updates []firestore.Update
+
+	if req.Name != "" {
+		updates = append(updates, firestore.Update{Path: "name", Value: req.Name})
+	}
+
+	if req.Description != "" {
+		updates = append(updates, firestore.Update{Path: "description", Value: req.Description})
+	}
+
+	if req.Format != "" {
+		f, ok := metricFormatToInternal[req.Format]
+		if !ok {
+			return ExternalAPICreateUpdateResp{
+				ValidationErrors: []errormsg.ErrorMsg{{Field: formatField, Message: fmt.Sprintf(msgFormat, metrics.ErrInvalidFormatMsg, req.Format)}},
+				Error:            metrics.ErrValidation,
+			}
+		}
+
+		updates = append(updates, firestore.Update{Path: "format", Value: f})
+	}
+
+	if req.Formula != "" || req.Variables != nil {
+		metricFormula := currentMetric.Formula
+		if req.Formula != "" {
+			metricFormula = req.Formula
+		}
+
+		requestVariables := req.Variables
+		if requestVariables == nil {
+			requestVariables, err = s.toMetricVariables(currentMetric.Variables)
+			if err != nil {
+				return ExternalAPICreateUpdateResp{Error: err}
+			}
+		}
+
+		metricFormula, variables, validationErrors, err := s.validateMetric(ctx, args.CustomerID, metricFormula, requestVariables)
+		if err != nil {
+			return ExternalAPICreateUpdateResp{ValidationErrors: validationErrors, Error: err}
+		}
+
+		updates = append(updates,
+			firestore.Update{Path: "formula", Value: metricFormula},
+			firestore.Update{Path: "variables", Value: variables},
+		)
+	}
+
+	if err := s.metricsDAL.Update(ctx, metricID, updates); err != nil {
+		return ExternalAPICreateUpdateResp{Error: err}
+	}
+
+	metric, err := s.metricsDAL.GetCustomMetric(ctx, metricID)
+	if err != nil {
+		return ExternalAPICreateUpdateResp{Error: err}
+	}
+
+	metricAPI, err := s.toMetricAPI(metric)
+	if err != nil {
+		return ExternalAPICreateUpdateResp{Error: err}
+	}
+
+	return ExternalAPICreateUpdateResp{Metric: metricAPI}
+}
+
+// DeleteMetric deletes a custom metric of the customer. Preset metrics and metrics that are
+// used by reports or alerts cannot be deleted.
+func (s *MetricsService) DeleteMetric(ctx context.Context, customerID string, metricID string) error {
+	metric, err := s.getCustomerMetric(ctx, customerID, metricID)
+	if err != nil {
+		return err
+	}
+
+	if metric.Type == metrics.MetricTypePreset {
+		return PresetMetricsCannotBeDeletedError{metricID}
+	}
+
+	if err := s.checkMetricsNotInUse(ctx, []string{metricID}); err != nil {
+		return err
+	}
+
+	if err := s.checkMetricsNotUsedByAlerts(ctx, []string{metricID}); err != nil {
+		return err
+	}
+
+	return s.metricsDAL.DeleteMany(ctx, []string{metricID})
+}
+
+// getCustomerMetric returns the metric if it is a preset metric or a custom metric of the customer.
+func (s *MetricsService) getCustomerMetric(ctx context.Context, customerID string, metricID string) (*metrics.CalculatedMetric, error) {
+	metric, err := s.metricsDAL.GetCustomMetric(ctx, metricID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, metrics.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if metric.Type != metrics.MetricTypePreset && (metric.Customer == nil || metric.Customer.ID != customerID) {
+		return nil, metrics.ErrForbidden
+	}
+
+	return metric, nil
+}
+
+// validateMetric validates the formula against the variables, returns the formula normalized by the parser
+// and converts the variables to their internal representation. Variables must use a basic metric and an
+// attribution the customer can access.
+func (s *MetricsService) validateMetric(
+	ctx context.Context,
+	customerID string,
+	metricFormula string,
+	variables []*MetricVariable,
+) (string, []*metrics.CalculatedMetricVariable, []errormsg.ErrorMsg, error) {
+	var validationErrors []errormsg.ErrorMsg
+
+	if len(variables) == 0 {
+		validationErrors = append(validationErrors, errormsg.ErrorMsg{Field: variablesField, Message: "This field is required"})
+	} else if f, err := formula.ParseArithmetic(metricFormula, len(variables)); err != nil {
+		validationErrors = append(validationErrors, errormsg.ErrorMsg{
+			Field:   formulaField,
+			Message: fmt.Sprintf(msgFormat, metrics.ErrInvalidFormulaMsg, err),
+		})
+	} else {
+		metricFormula = f.String()
+	}
+
+	internalVariables := make([]*metrics.CalculatedMetricVariable, 0, len(variables))
+
+	for i, v := range variables {
+		field := fmt.Sprintf("%s[%d]", variablesField, i)
+
+		if v == nil || v.Metric == nil || v.Metric.Type != metrics.ExternalMetricTypeBasic {
+			validationErrors = append(validationErrors, errormsg.ErrorMsg{Field: field, Message: metrics.ErrVariableMetricTypeMsg})
+			continue
+		}
+
+		params, errs, err := s.ToInternal(ctx, customerID, v.Metric)
+		if err != nil {
+			if !errors.Is(err, metrics.ErrValidation) {
+				return "", nil, nil, err
+			}
+
+			validationErrors = append(validationErrors, errs...)
+
+			continue
+		}
+
+		attr, err := s.attributionsDAL.GetAttribution(ctx, v.Attribution)
+		if err != nil {
+			if !errors.Is(err, attributionDomain.ErrNotFound) && !errors.Is(err, attributionDomain.ErrInvalidAttributionID) {
+				return "", nil, nil, err
+			}
+
+			attr = nil
+		}
+
+		if attr == nil || (attr.Type != string(attributionDomain.ObjectTypePreset) && (attr.Customer == nil || attr.Customer.ID != customerID)) {
+			validationErrors = append(validationErrors, errormsg.ErrorMsg{
+				Field:   field,
+				Message: fmt.Sprintf(msgFormat, metrics.ErrAttributionNotFoundMsg, v.Attribution),
+			})
+
+			continue
+		}
+
+		internalVariables = append(internalVariables, &metrics.CalculatedMetricVariable{
+			Metric:      *params.Metric,
+			Attribution: attr.Ref,
+		})
+	}
+
+	if len(validationErrors) > 0 {
+		return "", nil, validationErrors, metrics.ErrValidation
+	}
+
+	return metricFormula, internalVariables, nil, nil
+}
+
+func (s *MetricsService) toMetricVariables(variables []*metrics.CalculatedMetricVariable) ([]*MetricVariable, error) {
+	apiVariables := make([]*MetricVariable, len(variables))
+
+	for i, v := range variables {
+		metric := v.Metric
+
+		externalMetric, _, err := s.ToExternal(&metrics.InternalMetricParameters{Metric: &metric})
+		if err != nil {
+			return nil, err
+		}
+
+		var attributionID string
+		if v.Attribution != nil {
+			attributionID = v.Attribution.ID
+		}
+
+		apiVariables[i] = &MetricVariable{
+			Metric:      externalMetric,
+			Attribution: attributionID,
+		}
+	}
+
+	return apiVariables, nil
+}
+
+func (s *MetricsService) toMetricAPI(metric *metrics.CalculatedMetric) (*MetricAPI, error) {
+	variables, err := s.toMetricVariables(metric.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetricAPI{
+		ID:          metric.ID,
+		Name:        metric.Name,
+		Description: metric.Description,
+		Type:        metric.Type,
+		Owner:       metric.Owner,
+		Formula:     metric.Formula,
+		Variables:   variables,
+		Format:      metricFormatToExternal[metric.Format],
+	}, nil
+}
+
+func toListMetricAPI(calculatedMetrics []*metrics.CalculatedMetric) []customerapi.SortableItem {
+	apiMetrics := make([]customerapi.SortableItem, len(calculatedMetrics))
+
+	for i, metric := range calculatedMetrics {
+		apiMetrics[i] = ListMetricAPI{
+			ID:          metric.ID,
+			Name:        metric.Name,
+			Description: metric.Description,
+			Type:        metric.Type,
+			Owner:       metric.Owner,
+		}
+	}
+
+	return apiMetrics
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	alertsDALMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal/mocks"
+	alertsDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
+	attributionsDALMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/dal/mocks"
+	attributionDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/domain/attribution"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	metricsDALMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/dal/mocks"
+	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+	reportsDALMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	customerDALMocks "github.com/doitintl/hello/scheduled-tasks/customer/dal/mocks"
+)
+
+const (
+	testCustomerID = "customer1"
+	testMetricID   = "metric1"
+	testEmail      = "user@example.com"
+)
+
+type externalFields struct {
+	metricsDAL      *metricsDALMocks.Metrics
+	reportsDAL      *reportsDALMocks.Reports
+	alertsDAL       *alertsDALMocks.Alerts
+	attributionsDAL *attributionsDALMocks.Attributions
+	customersDAL    *customerDALMocks.Customers
+}
+
+func newExternalService(f *externalFields) *MetricsService {
+	return &MetricsService{
+		metricsDAL:      f.metricsDAL,
+		reportsDAL:      f.reportsDAL,
+		alertsDAL:       f.alertsDAL,
+		attributionsDAL: f.attributionsDAL,
+		customersDAL:    f.customersDAL,
+	}
+}
+
+func TestMetricsService_ListMetrics(t *testing.T) {
+	ctx := context.Background()
+	customerRef := &firestore.DocumentRef{ID: testCustomerID}
+
+	calculatedMetrics := []*metrics.CalculatedMetric{
+		{ID: "m1", Name: "b metric", Type: metrics.MetricTypeCustom, Owner: testEmail},
+		{ID: "m2", Name: "a metric", Type: metrics.MetricTypePreset},
+		{ID: "m3", Name: "c metric", Type: metrics.MetricTypeCustom, Owner: "other@example.com"},
+	}
+
+	tests := []struct {
+		name    string
+		args    ExternalAPIListArgsReq
+		on      func(f *externalFields)
+		wantIDs []string
+		wantErr error
+	}{
+		{
+			name: "sorted by name",
+			args: ExternalAPIListArgsReq{CustomerID: testCustomerID, SortBy: "name", SortOrder: firestore.Asc},
+			on: func(f *externalFields) {
+				f.customersDAL.On("GetRef", ctx, testCustomerID).Return(customerRef)
+				f.metricsDAL.On("ListCustomerMetrics", ctx, customerRef).Return(calculatedMetrics, nil)
+			},
+			wantIDs: []string{"m2", "m1", "m3"},
+		},
+		{
+			name: "error listing metrics",
+			args: ExternalAPIListArgsReq{CustomerID: testCustomerID},
+			on: func(f *externalFields) {
+				f.customersDAL.On("GetRef", ctx, testCustomerID).Return(customerRef)
+				f.metricsDAL.On("ListCustomerMetrics", ctx, customerRef).Return(nil, errors.New("error"))
+			},
+			wantErr: errors.New("error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &externalFields{
+				metricsDAL:   metricsDALMocks.NewMetrics(t),
+				customersDAL: customerDALMocks.NewCustomers(t),
+			}
+
+			tt.on(f)
+
+			got, err := newExternalService(f).ListMetrics(ctx, tt.args)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.wantIDs), got.RowCount)
+
+			for i, item := range got.Metrics {
+				assert.Equal(t, tt.wantIDs[i], item.GetID())
+			}
+		})
+	}
+}
+
+func TestMetricsService_GetMetric(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		on      func(f *externalFields)
+		want    *MetricAPI
+		wantErr error
+	}{
+		{
+			name: "custom metric of the customer",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(&metrics.CalculatedMetric{
+					ID:       testMetricID,
+					Name:     "cost per user",
+					Customer: &firestore.DocumentRef{ID: testCustomerID},
+					Type:     metrics.MetricTypeCustom,
+					Owner:    testEmail,
+					Formula:  "A / B",
+					Format:   metrics.MetricNumericFormat,
+					Variables: []*metrics.CalculatedMetricVariable{
+						{Metric: report.MetricCost, Attribution: &firestore.DocumentRef{ID: "attr1"}},
+						{Metric: report.MetricUsage, Attribution: &firestore.DocumentRef{ID: "attr2"}},
+					},
+				}, nil)
+			},
+			want: &MetricAPI{
+				ID:      testMetricID,
+				Name:    "cost per user",
+				Type:    metrics.MetricTypeCustom,
+				Owner:   testEmail,
+				Formula: "A / B",
+				Format:  MetricFormatNumeric,
+				Variables: []*MetricVariable{
+					{Metric: &metrics.ExternalMetric{Type: metrics.ExternalMetricTypeBasic, Value: "cost"}, Attribution: "attr1"},
+					{Metric: &metrics.ExternalMetric{Type: metrics.ExternalMetricTypeBasic, Value: "usage"}, Attribution: "attr2"},
+				},
+			},
+		},
+		{
+			name: "preset metric",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(&metrics.CalculatedMetric{
+					ID:     testMetricID,
+					Type:   metrics.MetricTypePreset,
+					Format: metrics.MetricPercentageFormat,
+				}, nil)
+			},
+			want: &MetricAPI{
+				ID:        testMetricID,
+				Type:      metrics.MetricTypePreset,
+				Format:    MetricFormatPercentage,
+				Variables: []*MetricVariable{},
+			},
+		},
+		{
+			name: "custom metric of another customer",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(&metrics.CalculatedMetric{
+					ID:       testMetricID,
+					Customer: &firestore.DocumentRef{ID: "customer2"},
+					Type:     metrics.MetricTypeCustom,
+				}, nil)
+			},
+			wantErr: metrics.ErrForbidden,
+		},
+		{
+			name: "metric not found",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(nil, status.Error(codes.NotFound, "not found"))
+			},
+			wantErr: metrics.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &externalFields{
+				metricsDAL: metricsDALMocks.NewMetrics(t),
+			}
+
+			tt.on(f)
+
+			got, err := newExternalService(f).GetMetric(ctx, testCustomerID, testMetricID)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMetricsService_CreateMetric(t *testing.T) {
+	ctx := context.Background()
+	customerRef := &firestore.DocumentRef{ID: testCustomerID}
+	attrRef := &firestore.DocumentRef{ID: "attr1"}
+
+	costVariable := &MetricVariable{
+		Metric:      &metrics.ExternalMetric{Type: metrics.ExternalMetricTypeBasic, Value: "cost"},
+		Attribution: "attr1",
+	}
+
+	customerAttribution := &attributionDomain.Attribution{
+		ID:       "attr1",
+		Type:     string(attributionDomain.ObjectTypeCustom),
+		Customer: customerRef,
+		Ref:      attrRef,
+	}
+
+	tests := []struct {
+		name                 string
+		req                  *MetricRequest
+		on                   func(f *externalFields)
+		wantErr              error
+		wantValidationErrors []errormsg.ErrorMsg
+		want                 *MetricAPI
+	}{
+		{
+			name: "success",
+			req: &MetricRequest{
+				Name:      "cost percentage",
+				Formula:   "(A)*100",
+				Variables: []*MetricVariable{costVariable},
+				Format:    MetricFormatPercentage,
+			},
+			on: func(f *externalFields) {
+				f.attributionsDAL.On("GetAttribution", ctx, "attr1").Return(customerAttribution, nil)
+				f.customersDAL.On("GetRef", ctx, testCustomerID).Return(customerRef)
+				f.metricsDAL.On("Create", ctx, mock.MatchedBy(func(m *metrics.CalculatedMetric) bool {
+					return m.Type == metrics.MetricTypeCustom &&
+						m.Formula == "A * 100" &&
+						m.Owner == testEmail &&
+						m.Customer == customerRef &&
+						m.Format == metrics.MetricPercentageFormat &&
+						len(m.Variables) == 1 &&
+						m.Variables[0].Metric == report.MetricCost &&
+						m.Variables[0].Attribution == attrRef
+				})).Return(func(_ context.Context, m *metrics.CalculatedMetric) (*metrics.CalculatedMetric, error) {
+					m.ID = testMetricID
+					return m, nil
+				})
+			},
+			want: &MetricAPI{
+				ID:        testMetricID,
+				Name:      "cost percentage",
+				Type:      metrics.MetricTypeCustom,
+				Owner:     testEmail,
+				Formula:   "A * 100",
+				Variables: []*MetricVariable{costVariable},
+				Format:    MetricFormatPercentage,
+			},
+		},
+		{
+			name: "invalid formula",
+			req: &MetricRequest{
+				Name:      "invalid",
+				Formula:   "A + B",
+				Variables: []*MetricVariable{costVariable},
+			},
+			on: func(f *externalFields) {
+				f.attributionsDAL.On("GetAttribution", ctx, "attr1").Return(customerAttribution, nil)
+			},
+			wantErr: metrics.ErrValidation,
+		},
+		{
+			name: "variable with a custom metric",
+			req: &MetricRequest{
+				Name:    "nested",
+				Formula: "A",
+				Variables: []*MetricVariable{{
+					Metric:      &metrics.ExternalMetric{Type: metrics.ExternalMetricTypeCustom, Value: "other"},
+					Attribution: "attr1",
+				}},
+			},
+			on:      func(f *externalFields) {},
+			wantErr: metrics.ErrValidation,
+			wantValidationErrors: []errormsg.ErrorMsg{
+				{Field: "variables[0]", Message: metrics.ErrVariableMetricTypeMsg},
+			},
+		},
+		{
+			name: "attribution of another customer",
+			req: &MetricRequest{
+				Name:      "foreign",
+				Formula:   "A",
+				Variables: []*MetricVariable{costVariable},
+			},
+			on: func(f *externalFields) {
+				f.attributionsDAL.On("GetAttribution", ctx, "attr1").Return(&attributionDomain.Attribution{
+					ID:       "attr1",
+					Type:     string(attributionDomain.ObjectTypeCustom),
+					Customer: &firestore.DocumentRef{ID: "customer2"},
+				}, nil)
+			},
+			wantErr: metrics.ErrValidation,
+			wantValidationErrors: []errormsg.ErrorMsg{
+				{Field: "variables[0]", Message: "attribution not found: attr1"},
+			},
+		},
+		{
+			name: "invalid format",
+			req: &MetricRequest{
+				Name:      "format",
+				Formula:   "A",
+				Variables: []*MetricVariable{costVariable},
+				Format:    "currency",
+			},
+			on: func(f *externalFields) {
+				f.attributionsDAL.On("GetAttribution", ctx, "attr1").Return(customerAttribution, nil)
+			},
+			wantErr: metrics.ErrValidation,
+			wantValidationErrors: []errormsg.ErrorMsg{
+				{Field: "format", Message: "invalid metric format: currency"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &externalFields{
+				metricsDAL:      metricsDALMocks.NewMetrics(t),
+				attributionsDAL: attributionsDALMocks.NewAttributions(t),
+				customersDAL:    customerDALMocks.NewCustomers(t),
+			}
+
+			tt.on(f)
+
+			resp := newExternalService(f).CreateMetric(ctx, ExternalAPICreateUpdateArgsReq{
+				MetricRequest: tt.req,
+				CustomerID:    testCustomerID,
+				Email:         testEmail,
+			})
+
+			assert.ErrorIs(t, resp.Error, tt.wantErr)
+
+			if tt.wantValidationErrors != nil {
+				assert.Equal(t, tt.wantValidationErrors, resp.ValidationErrors)
+			}
+
+			if tt.wantErr == metrics.ErrValidation {
+				assert.NotEmpty(t, resp.ValidationErrors)
+			}
+
+			assert.Equal(t, tt.want, resp.Metric)
+		})
+	}
+}
+
+func TestMetricsService_UpdateMetric(t *testing.T) {
+	ctx := context.Background()
+	customerRef := &firestore.DocumentRef{ID: testCustomerID}
+	attrRef := &firestore.DocumentRef{ID: "attr1"}
+
+	currentMetric := &metrics.CalculatedMetric{
+		ID:       testMetricID,
+		Name:     "cost",
+		Customer: customerRef,
+		Type:     metrics.MetricTypeCustom,
+		Formula:  "A",
+		Variables: []*metrics.CalculatedMetricVariable{
+			{Metric: report.MetricCost, Attribution: attrRef},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		req     *MetricRequest
+		on      func(f *externalFields)
+		wantErr error
+	}{
+		{
+			name: "update name only",
+			req:  &MetricRequest{Name: "new name"},
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(currentMetric, nil)
+				f.metricsDAL.On("Update", ctx, testMetricID, []firestore.Update{{Path: "name", Value: "new name"}}).Return(nil)
+			},
+		},
+		{
+			name: "formula is saved normalized",
+			req:  &MetricRequest{Formula: "(A)*100"},
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(currentMetric, nil)
+				f.attributionsDAL.On("GetAttribution", ctx, "attr1").Return(&attributionDomain.Attribution{
+					ID:       "attr1",
+					Type:     string(attributionDomain.ObjectTypeCustom),
+					Customer: customerRef,
+					Ref:      attrRef,
+				}, nil)
+				f.metricsDAL.On("Update", ctx, testMetricID, mock.MatchedBy(func(updates []firestore.Update) bool {
+					return len(updates) == 2 && updates[0].Path == "formula" && updates[0].Value == "A * 100"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "formula is validated against the current variables",
+			req:  &MetricRequest{Formula: "A / B"},
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(currentMetric, nil)
+				f.attributionsDAL.On("GetAttribution", ctx, "attr1").Return(&attributionDomain.Attribution{
+					ID:       "attr1",
+					Type:     string(attributionDomain.ObjectTypeCustom),
+					Customer: customerRef,
+					Ref:      attrRef,
+				}, nil)
+			},
+			wantErr: metrics.ErrValidation,
+		},
+		{
+			name: "preset metric",
+			req:  &MetricRequest{Name: "new name"},
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(&metrics.CalculatedMetric{
+					ID:   testMetricID,
+					Type: metrics.MetricTypePreset,
+				}, nil)
+			},
+			wantErr: metrics.ErrPresetMetricUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &externalFields{
+				metricsDAL:      metricsDALMocks.NewMetrics(t),
+				attributionsDAL: attributionsDALMocks.NewAttributions(t),
+			}
+
+			tt.on(f)
+
+			resp := newExternalService(f).UpdateMetric(ctx, testMetricID, ExternalAPICreateUpdateArgsReq{
+				MetricRequest: tt.req,
+				CustomerID:    testCustomerID,
+				Email:         testEmail,
+			})
+
+			assert.ErrorIs(t, resp.Error, tt.wantErr)
+
+			if tt.wantErr == nil {
+				assert.NotNil(t, resp.Metric)
+			}
+		})
+	}
+}
+
+func TestMetricsService_DeleteMetric(t *testing.T) {
+	ctx := context.Background()
+	metricRef := &firestore.DocumentRef{ID: testMetricID}
+
+	customMetric := &metrics.CalculatedMetric{
+		ID:       testMetricID,
+		Customer: &firestore.DocumentRef{ID: testCustomerID},
+		Type:     metrics.MetricTypeCustom,
+	}
+
+	tests := []struct {
+		name    string
+		on      func(f *externalFields)
+		wantErr error
+	}{
+		{
+			name: "success",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(customMetric, nil)
+				f.metricsDAL.On("GetRef", ctx, testMetricID).Return(metricRef)
+				f.reportsDAL.On("GetByMetricRef", ctx, metricRef).Return([]*report.Report{}, nil)
+				f.alertsDAL.On("GetByMetricRef", ctx, metricRef).Return([]*alertsDomain.Alert{}, nil)
+				f.metricsDAL.On("DeleteMany", ctx, []string{testMetricID}).Return(nil)
+			},
+		},
+		{
+			name: "metric used by a report",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(customMetric, nil)
+				f.metricsDAL.On("GetRef", ctx, testMetricID).Return(metricRef)
+				f.reportsDAL.On("GetByMetricRef", ctx, metricRef).Return([]*report.Report{{}}, nil)
+			},
+			wantErr: MetricIsInUseError{testMetricID},
+		},
+		{
+			name: "metric used by an alert",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(customMetric, nil)
+				f.metricsDAL.On("GetRef", ctx, testMetricID).Return(metricRef)
+				f.reportsDAL.On("GetByMetricRef", ctx, metricRef).Return([]*report.Report{}, nil)
+				f.alertsDAL.On("GetByMetricRef", ctx, metricRef).Return([]*alertsDomain.Alert{{}}, nil)
+			},
+			wantErr: MetricIsInUseByAlertError{testMetricID},
+		},
+		{
+			name: "preset metric",
+			on: func(f *externalFields) {
+				f.metricsDAL.On("GetCustomMetric", ctx, testMetricID).Return(&metrics.CalculatedMetric{
+					ID:   testMetricID,
+					Type: metrics.MetricTypePreset,
+				}, nil)
+			},
+			wantErr: PresetMetricsCannotBeDeletedError{testMetricID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &externalFields{
+				metricsDAL: metricsDALMocks.NewMetrics(t),
+				reportsDAL: reportsDALMocks.NewReports(t),
+				alertsDAL:  alertsDALMocks.NewAlerts(t),
+			}
+
+			tt.on(f)
+
+			err := newExternalService(f).DeleteMetric(ctx, testCustomerID, testMetricID)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -1,5 +1,119 @@
 package service
 
+import (
+	"cloud.google.com/go/firestore"
+
+	"github.com/doitintl/customerapi"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
+	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+)
+
 type DeleteMetricsRequest struct {
 	IDs []string `json:"ids" validate:"gt=0,required"`
 }
+
+// swagger:enum MetricFormat
+type MetricFormat string
+
+const (
+	MetricFormatNumeric    MetricFormat = "numeric"
+	MetricFormatPercentage MetricFormat = "percentage"
+)
+
+var metricFormatToInternal = map[MetricFormat]int{
+	MetricFormatNumeric:    metrics.MetricNumericFormat,
+	MetricFormatPercentage: metrics.MetricPercentageFormat,
+}
+
+var metricFormatToExternal = map[int]MetricFormat{
+	metrics.MetricNumericFormat:    MetricFormatNumeric,
+	metrics.MetricPercentageFormat: MetricFormatPercentage,
+}
+
+// A variable of the metric formula. The first variable is A, the second is B and so on.
+type MetricVariable struct {
+	// The basic metric of the variable, one of: ["cost", "usage", "savings"]
+	Metric *metrics.ExternalMetric `json:"metric" binding:"required"`
+	// The id of the attribution the metric is calculated for
+	Attribution string `json:"attribution" binding:"required"`
+}
+
+type MetricRequest struct {
+	// Metric name
+	Name string `json:"name" binding:"required"`
+	// Metric description
+	Description string `json:"description"`
+	// Arithmetic formula of the metric variables, e.g. "A / B * 100"
+	Formula string `json:"formula" binding:"required"`
+	// Metric variables
+	Variables []*MetricVariable `json:"variables" binding:"required,dive"`
+	// Format of the metric values, "numeric" or "percentage". Defaults to "numeric"
+	Format MetricFormat `json:"format"`
+}
+
+type MetricAPI struct {
+	// Metric ID
+	ID string `json:"id"`
+	// Metric name
+	Name string `json:"name"`
+	// Metric description
+	Description string `json:"description"`
+	// Type of the metric can be either "preset" or "custom"
+	Type string `json:"type"`
+	// Metric owner
+	Owner string `json:"owner"`
+	// Arithmetic formula of the metric variables
+	Formula string `json:"formula"`
+	// Metric variables
+	Variables []*MetricVariable `json:"variables"`
+	// Format of the metric values
+	Format MetricFormat `json:"format"`
+}
+
+type ListMetricAPI struct {
+	// Metric ID
+	ID string `sortKey:"id" json:"id"`
+	// Metric name
+	Name string `sortKey:"name" json:"name"`
+	// Metric description
+	Description string `json:"description"`
+	// Type of the metric can be either "preset" or "custom"
+	Type string `sortKey:"type" json:"type"`
+	// Metric owner
+	Owner string `json:"owner"`
+}
+
+func (m ListMetricAPI) GetID() string {
+	return m.ID
+}
+
+type ExternalMetricList struct {
+	// Page token, returned by a previous call, to request the next page of results
+	PageToken string `json:"pageToken,omitempty"`
+	// Metrics rows count
+	RowCount int `json:"rowCount"`
+	// Array of Metrics
+	Metrics []customerapi.SortableItem `json:"metrics"`
+}
+
+type ExternalAPIListArgsReq struct {
+	CustomerID    string
+	Email         string
+	SortBy        string
+	SortOrder     firestore.Direction
+	Filters       []customerapi.Filter
+	MaxResults    int
+	NextPageToken string
+}
+
+type ExternalAPICreateUpdateArgsReq struct {
+	MetricRequest *MetricRequest
+	CustomerID    string
+	Email         string
+}
+
+type ExternalAPICreateUpdateResp struct {
+	Metric           *MetricAPI
+	ValidationErrors []errormsg.ErrorMsg
+	Error            error
+}
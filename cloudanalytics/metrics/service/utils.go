@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 
+	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -18,7 +20,7 @@ func (s *MetricsService) checkMetricsNotPreset(ctx context.Context, IDs []string
 			return err
 		}
 
-		if metric.Type == "preset" {
+		if metric.Type == metrics.MetricTypePreset {
 			return PresetMetricsCannotBeDeletedError{id}
 		}
 	}
@@ -42,3 +44,20 @@ func (s *MetricsService) checkMetricsNotInUse(ctx context.Context, IDs []string)
 
 	return nil
 }
+
+func (s *MetricsService) checkMetricsNotUsedByAlerts(ctx context.Context, IDs []string) error {
+	for _, id := range IDs {
+		metricRef := s.metricsDAL.GetRef(ctx, id)
+		metricAlerts, err := s.alertsDAL.GetByMetricRef(ctx, metricRef)
+
+		if err != nil {
+			return err
+		}
+
+		if len(metricAlerts) > 0 {
+			return MetricIsInUseByAlertError{id}
+		}
+	}
+
+	return nil
+}
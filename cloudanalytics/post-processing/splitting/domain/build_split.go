@@ -5,6 +5,7 @@ import (
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain/split"
 	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 )
 
 type BuildSplit struct {
@@ -15,4 +16,10 @@ type BuildSplit struct {
 	ResRows       *[][]bigquery.Value
 	SplitsReq     *[]split.Split
 	Attributions  []*domainQuery.QueryRequestX
+	// TimeInterval is set when the columns of the result rows are a time series,
+	// it is used to find the time bucket of a row for splits with effective-date periods.
+	TimeInterval report.TimeInterval
+	// UsageMetricIndexes are the positions in a row of the DataHub extended metrics
+	// the usage-driven splits divide the origin by.
+	UsageMetricIndexes map[string]int
 }
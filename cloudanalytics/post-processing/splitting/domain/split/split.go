@@ -2,8 +2,10 @@ package split
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
+	"github.com/doitintl/hello/scheduled-tasks/slice"
 )
 
 // swagger:enum Mode
@@ -14,16 +16,19 @@ const (
 	ModeEven         Mode = "even"
 	ModeCustom       Mode = "custom"
 	ModeProportional Mode = "proportional"
+	ModeUsageDriven  Mode = "usage-driven"
 )
 
 type Split struct {
-	ID            string                     `json:"id"            firestore:"id"`
-	Key           string                     `json:"key"           firestore:"key"`
-	Type          metadata.MetadataFieldType `json:"type"          firestore:"type"`
-	Origin        string                     `json:"origin"        firestore:"origin"`
-	Mode          Mode                       `json:"mode"          firestore:"mode"`
-	Targets       []SplitTarget              `json:"targets"       firestore:"targets"`
-	IncludeOrigin bool                       `json:"includeOrigin" firestore:"includeOrigin"`
+	ID            string                     `json:"id"                    firestore:"id"`
+	Key           string                     `json:"key"                   firestore:"key"`
+	Type          metadata.MetadataFieldType `json:"type"                  firestore:"type"`
+	Origin        string                     `json:"origin"                firestore:"origin"`
+	Mode          Mode                       `json:"mode"                  firestore:"mode"`
+	Targets       []SplitTarget              `json:"targets"               firestore:"targets"`
+	IncludeOrigin bool                       `json:"includeOrigin"         firestore:"includeOrigin"`
+	Periods       []SplitPeriod              `json:"periods,omitempty"     firestore:"periods,omitempty"`
+	UsageMetric   string                     `json:"usageMetric,omitempty" firestore:"usageMetric,omitempty"`
 }
 
 type SplitTarget struct {
@@ -31,16 +36,76 @@ type SplitTarget struct {
 	Value float64 `json:"value" firestore:"value"`
 }
 
+// SplitPeriod overrides the targets of a split for the result rows whose time bucket starts
+// within [From, To). A nil From or To leaves the period open on that side.
+type SplitPeriod struct {
+	From    *time.Time    `json:"from"    firestore:"from"`
+	To      *time.Time    `json:"to"      firestore:"to"`
+	Targets []SplitTarget `json:"targets" firestore:"targets"`
+}
+
 type SplitTargetPerMetric map[string][]float64
 
+// UsageMetrics returns the extended metrics the usage-driven splits divide their origin by
+func UsageMetrics(splits []Split) []string {
+	var usageMetrics []string
+
+	for _, split := range splits {
+		if split.Mode == ModeUsageDriven && split.UsageMetric != "" && !slice.Contains(usageMetrics, split.UsageMetric) {
+			usageMetrics = append(usageMetrics, split.UsageMetric)
+		}
+	}
+
+	return usageMetrics
+}
+
 func (mode Mode) Validate() error {
 	switch mode {
 	case
 		ModeEven,
 		ModeCustom,
-		ModeProportional:
+		ModeProportional,
+		ModeUsageDriven:
 		return nil
 	default:
 		return fmt.Errorf("%s: %s", ErrInvalidSplitMode, mode)
 	}
 }
+
+// TargetsAt returns the targets in effect at the given date, which are the targets of the
+// first period containing it, or the targets of the split if there is no such period.
+func (s Split) TargetsAt(date time.Time) []SplitTarget {
+	for _, period := range s.Periods {
+		if period.Contains(date) {
+			return period.Targets
+		}
+	}
+
+	return s.Targets
+}
+
+// AllTargets returns the targets of the split followed by the targets of its periods
+func (s Split) AllTargets() []SplitTarget {
+	targets := append([]SplitTarget{}, s.Targets...)
+
+	for _, period := range s.Periods {
+		targets = append(targets, period.Targets...)
+	}
+
+	return targets
+}
+
+func (p SplitPeriod) Contains(date time.Time) bool {
+	return (p.From == nil || !date.Before(*p.From)) && (p.To == nil || date.Before(*p.To))
+}
+
+func (p SplitPeriod) IsValid() bool {
+	return p.From == nil || p.To == nil || p.From.Before(*p.To)
+}
+
+func (p SplitPeriod) Overlaps(other SplitPeriod) bool {
+	startsBeforeOtherEnds := p.From == nil || other.To == nil || p.From.Before(*other.To)
+	otherStartsBeforeEnd := other.From == nil || p.To == nil || other.From.Before(*p.To)
+
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
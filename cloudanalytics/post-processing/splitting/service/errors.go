@@ -11,6 +11,8 @@ var (
 	ErrInvalidMode                 = errors.New("invalid splitting mode")
 	ErrInvalidSplitType            = errors.New("invalid split type")
 	ErrInvalidIndex                = errors.New("invalid index")
+	ErrUsageMetricNotInQuery       = errors.New("usage metric of the split is not in the query")
+	ErrPeriodsWithoutTimeInterval  = errors.New("split periods require a time series report")
 )
 
 type ValidationErrorType string
@@ -19,6 +21,10 @@ const (
 	ValidationErrorTypeCircularDependency                   ValidationErrorType = "circular_dependency_error"
 	ValidationErrorTypeIDCannotBeOriginAndTargetInSameSplit ValidationErrorType = "origin_is_target_error"
 	ValidationErrorTypeOriginDuplicated                     ValidationErrorType = "origin_duplicated"
+	ValidationErrorTypeMissingUsageMetric                   ValidationErrorType = "missing_usage_metric"
+	ValidationErrorTypePeriodsNotSupported                  ValidationErrorType = "periods_not_supported"
+	ValidationErrorTypeInvalidPeriod                        ValidationErrorType = "invalid_period"
+	ValidationErrorTypeOverlappingPeriods                   ValidationErrorType = "overlapping_periods"
 )
 
 type ValidationError struct {
@@ -12,10 +12,15 @@ import (
 	domainSplit "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain/split"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query"
 	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/utils"
 )
 
 const unAllocatedKey = "Unallocated"
 
+// targetValuesFunc returns the split values of a target for an origin row,
+// either a single value for all metrics or a value per metric.
+type targetValuesFunc func(originRow []bigquery.Value, target domainSplit.SplitTarget) []float64
+
 type SplittingService struct{}
 
 func NewSplittingService() *SplittingService {
@@ -30,14 +35,17 @@ func (s *SplittingService) Split(splitParams domain.BuildSplit) error {
 		return ErrorNoSplittingDefined
 	}
 
-	var splitTargetsPerMetric domainSplit.SplitTargetPerMetric
-
 	for _, split := range *splitParams.SplitsReq {
 		isAttributionGroupSplit := split.Type == metadata.MetadataFieldTypeAttributionGroup
 		if isAttributionGroupSplit {
 			replaceAttrIDsWithName(splitParams.Attributions, &split)
 		}
 
+		// the targets of the periods apply to the time buckets of the rows
+		if len(split.Periods) > 0 && splitParams.TimeInterval == "" {
+			return ErrPeriodsWithoutTimeInterval
+		}
+
 		metricOffset := len(splitParams.RowsCols)
 
 		splitIndex := domainQuery.FindIndexInQueryRequestX(splitParams.RowsCols, split.ID)
@@ -47,19 +55,46 @@ func (s *SplittingService) Split(splitParams domain.BuildSplit) error {
 
 		splitMap := keyValMapForSplitting(metricOffset, splitIndex, &split, *splitParams.ResRows, isAttributionGroupSplit)
 
+		var targetValues targetValuesFunc
+
 		// set the split values between the targets e.g equally split or custom split etc.
 		switch split.Mode {
 		case domainSplit.ModeCustom, domainSplit.ModeEven:
 			calculatePercentageSplitValues(&split)
+
+			targetValues = func(_ []bigquery.Value, target domainSplit.SplitTarget) []float64 {
+				return []float64{target.Value}
+			}
 		case domainSplit.ModeProportional:
-			splitTargetsPerMetric = calculateProportionalPercentageSplitValues(
+			splitTargetsPerMetric := calculateProportionalPercentageSplitValues(
 				split.Targets, splitParams, splitIndex)
+
+			targetValues = func(_ []bigquery.Value, target domainSplit.SplitTarget) []float64 {
+				return splitTargetsPerMetric[target.ID]
+			}
+		case domainSplit.ModeUsageDriven:
+			usageIndex, ok := splitParams.UsageMetricIndexes[split.UsageMetric]
+			if !ok {
+				return ErrUsageMetricNotInQuery
+			}
+
+			usageShares := calculateUsageDrivenSplitValues(split.Targets, splitParams, splitIndex, usageIndex)
+
+			targetValues = func(originRow []bigquery.Value, target domainSplit.SplitTarget) []float64 {
+				colKey, _ := generateKeys(originRow, "", splitParams.NumRows, splitParams.NumCols)
+				return []float64{usageShares[colKey][target.ID]}
+			}
 		default:
 			return ErrInvalidMode
 		}
 
+		originTargets, err := targetsByOriginRow(split, splitMap[split.Origin], splitParams)
+		if err != nil {
+			return err
+		}
+
 		splitValues(
-			split, splitMap, splitParams.MetricsLength, metricOffset, splitIndex, splitParams.ResRows, splitTargetsPerMetric)
+			split, splitMap, originTargets, targetValues, splitParams.MetricsLength, metricOffset, splitIndex, splitParams.ResRows)
 	}
 
 	return nil
@@ -78,19 +113,63 @@ func replaceAttrIDsWithName(attributions []*domainQuery.QueryRequestX, split *do
 				split.Targets[i].ID = attribution.Key
 			}
 		}
+
+		for _, period := range split.Periods {
+			for i, target := range period.Targets {
+				if attribution.ID == target.ID {
+					period.Targets[i].ID = attribution.Key
+				}
+			}
+		}
 	}
 }
 
+// targetsByOriginRow returns the targets in effect for each origin row, by the row index.
+// When the split has effective-date periods, the targets are those of the period
+// that contains the time bucket of the row.
+func targetsByOriginRow(
+	split domainSplit.Split,
+	originRows map[string]int,
+	splitParams domain.BuildSplit,
+) (map[int][]domainSplit.SplitTarget, error) {
+	targets := make(map[int][]domainSplit.SplitTarget, len(originRows))
+
+	for _, rowIndex := range originRows {
+		if len(split.Periods) == 0 {
+			targets[rowIndex] = split.Targets
+			continue
+		}
+
+		rowDate, err := utils.GetRowDate((*splitParams.ResRows)[rowIndex], splitParams.NumRows, splitParams.TimeInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		targets[rowIndex] = split.TargetsAt(*rowDate)
+	}
+
+	return targets, nil
+}
+
 // calculatePercentageSplitValues calculates the percentage split values for each target.
 // depending on the split type the values will be calculated differently.
 // if the split type is "custom" then the values will be used as is.
 // if the split type is "evenly" then the values will be calculated based on the number of targets.
+// the targets of each effective-date period are calculated the same way.
 func calculatePercentageSplitValues(split *domainSplit.Split) {
+	calculateTargetsPercentageSplitValues(split.Targets)
+
+	for _, period := range split.Periods {
+		calculateTargetsPercentageSplitValues(period.Targets)
+	}
+}
+
+func calculateTargetsPercentageSplitValues(targets []domainSplit.SplitTarget) {
 	var total float64
 
 	allZero := true
 
-	for _, target := range split.Targets {
+	for _, target := range targets {
 		if target.Value > 0 {
 			allZero = false
 			break
@@ -98,20 +177,20 @@ func calculatePercentageSplitValues(split *domainSplit.Split) {
 	}
 
 	if allZero {
-		for i := range split.Targets {
-			value := 1 / float64(len(split.Targets))
-			split.Targets[i].Value = value
+		for i := range targets {
+			value := 1 / float64(len(targets))
+			targets[i].Value = value
 			total += value
 		}
 	} else {
-		for _, target := range split.Targets {
+		for _, target := range targets {
 			total += target.Value
 		}
 	}
 	// if values are greater than 1 then split evenly.
 	if total > 1 {
-		for i := range split.Targets {
-			split.Targets[i].Value = 1 / float64(len(split.Targets))
+		for i := range targets {
+			targets[i].Value = 1 / float64(len(targets))
 		}
 	}
 }
@@ -227,6 +306,62 @@ func calculateProportionalPercentageSplitValues(
 	return splitTargetsPerMetric
 }
 
+// calculateUsageDrivenSplitValues calculates the share of each target of the usage metric
+// (a DataHub extended metric, e.g. requests per team) per column, which for time series
+// reports is the time bucket of the row. The share of a target is then applied to all
+// the metrics of the origin row in the same column.
+//
+// team       month   requests
+// gigabright 2024-01 300
+// turing     2024-01 100
+// gigabright 2024-02 100
+// turing     2024-02 100
+//
+// gigabright gets .75 of the origin in 2024-01 and .5 in 2024-02.
+func calculateUsageDrivenSplitValues(
+	targets []domainSplit.SplitTarget,
+	splitParams domain.BuildSplit,
+	splitIndex int,
+	usageIndex int,
+) map[string]map[string]float64 {
+	usageSums := make(map[string]float64)
+	usageValues := make(map[string]map[string]float64)
+
+	for _, row := range *splitParams.ResRows {
+		key, ok := row[splitIndex].(string)
+		if !ok {
+			key = unAllocatedKey
+		}
+
+		if !isTarget(key, targets) {
+			continue
+		}
+
+		colKey, _ := generateKeys(row, key, splitParams.NumRows, splitParams.NumCols)
+
+		if _, ok := usageValues[colKey]; !ok {
+			usageValues[colKey] = make(map[string]float64)
+		}
+
+		value := toFloat64(row[usageIndex])
+		usageSums[colKey] += value
+		usageValues[colKey][key] += value
+	}
+
+	for colKey, values := range usageValues {
+		for targetID, value := range values {
+			if usageSums[colKey] == 0 {
+				values[targetID] = 0
+				continue
+			}
+
+			values[targetID] = value / usageSums[colKey]
+		}
+	}
+
+	return usageValues
+}
+
 func isTarget(key string, targets []domainSplit.SplitTarget) bool {
 	for _, target := range targets {
 		if target.ID == key {
@@ -279,11 +414,10 @@ func keyValMapForSplitting(metricsOffset, splitKeyIndex int, split *domainSplit.
 		if len(splitKeyMap[key]) == 0 {
 			delete(splitKeyMap, key)
 			// remove from targets
-			for i, target := range split.Targets {
-				if target.ID == key {
-					split.Targets = append(split.Targets[:i], split.Targets[i+1:]...)
-					break
-				}
+			split.Targets = removeTarget(split.Targets, key)
+
+			for i := range split.Periods {
+				split.Periods[i].Targets = removeTarget(split.Periods[i].Targets, key)
 			}
 		}
 	}
@@ -291,15 +425,26 @@ func keyValMapForSplitting(metricsOffset, splitKeyIndex int, split *domainSplit.
 	return splitKeyMap
 }
 
+func removeTarget(targets []domainSplit.SplitTarget, targetID string) []domainSplit.SplitTarget {
+	for i, target := range targets {
+		if target.ID == targetID {
+			return append(targets[:i], targets[i+1:]...)
+		}
+	}
+
+	return targets
+}
+
 // splitValues splits the values from the "origin" rows into the "target" rows.
 // depending on the configuration of the split the values will be split differently.
 // in some cases into the target rows and in some cases new "origin" rows will be created.
 func splitValues(split domainSplit.Split,
 	splitMap map[string]map[string]int,
+	originTargets map[int][]domainSplit.SplitTarget,
+	targetValuesFor targetValuesFunc,
 	metricLength, metricOffset,
 	splitIndex int,
 	resRows *[][]bigquery.Value,
-	splitTargetsPerMetric domainSplit.SplitTargetPerMetric,
 ) {
 	rows := *resRows
 
@@ -315,12 +460,8 @@ func splitValues(split domainSplit.Split,
 			initialMetricValues = append(initialMetricValues, rows[originRowIndex][i].(float64))
 		}
 
-		for _, target := range split.Targets {
-			targetValues := []float64{target.Value}
-
-			if split.Mode == domainSplit.ModeProportional {
-				targetValues = splitTargetsPerMetric[target.ID]
-			}
+		for _, target := range originTargets[originRowIndex] {
+			targetValues := targetValuesFor(originRow, target)
 
 			if _, ok := splitMap[target.ID][originRowsKey]; ok {
 				targetRowIndex := splitMap[target.ID][originRowsKey]
@@ -372,6 +513,12 @@ func initializeSplitMap(split domainSplit.Split) map[string]map[string]int {
 		resultMap[target.ID] = make(map[string]int)
 	}
 
+	for _, period := range split.Periods {
+		for _, target := range period.Targets {
+			resultMap[target.ID] = make(map[string]int)
+		}
+	}
+
 	return resultMap
 }
 
@@ -466,13 +613,21 @@ func (s *SplittingService) ValidateSplitsReq(splits *[]domainSplit.Split) []erro
 			))
 		}
 
+		validationErrs = append(validationErrs, validateSplitWeights(split)...)
+
 		idMap[split.Origin] = make(map[string]bool)
 
 		if _, ok := attrToAttrGroup[split.Origin]; !ok {
 			attrToAttrGroup[split.Origin] = split.ID
 		}
 
-		for _, target := range split.Targets {
+		for _, target := range split.AllTargets() {
+			if _, ok := idMap[split.Origin][target.ID]; ok {
+				continue
+			}
+
+			idMap[split.Origin][target.ID] = true
+
 			if target.ID == split.Origin {
 				validationErrs = append(validationErrs, NewValidationError(
 					ValidationErrorTypeIDCannotBeOriginAndTargetInSameSplit,
@@ -480,10 +635,6 @@ func (s *SplittingService) ValidateSplitsReq(splits *[]domainSplit.Split) []erro
 					split.Origin,
 				))
 			}
-
-			if _, ok := idMap[split.Origin][target.ID]; !ok {
-				idMap[split.Origin][target.ID] = true
-			}
 		}
 	}
 
@@ -504,6 +655,56 @@ func (s *SplittingService) ValidateSplitsReq(splits *[]domainSplit.Split) []erro
 	return validationErrs
 }
 
+// validateSplitWeights validates the usage metric of usage-driven splits and the
+// effective-date periods of the split, which are supported only by even and custom splits.
+func validateSplitWeights(split domainSplit.Split) []error {
+	var validationErrs []error
+
+	if split.Mode == domainSplit.ModeUsageDriven && split.UsageMetric == "" {
+		validationErrs = append(validationErrs, NewValidationError(
+			ValidationErrorTypeMissingUsageMetric,
+			split.ID,
+			split.Origin,
+		))
+	}
+
+	if len(split.Periods) == 0 {
+		return validationErrs
+	}
+
+	if split.Mode != domainSplit.ModeEven && split.Mode != domainSplit.ModeCustom {
+		return append(validationErrs, NewValidationError(
+			ValidationErrorTypePeriodsNotSupported,
+			split.ID,
+			split.Origin,
+		))
+	}
+
+	for i, period := range split.Periods {
+		if !period.IsValid() {
+			validationErrs = append(validationErrs, NewValidationError(
+				ValidationErrorTypeInvalidPeriod,
+				split.ID,
+				split.Origin,
+			))
+
+			continue
+		}
+
+		for _, other := range split.Periods[i+1:] {
+			if other.IsValid() && period.Overlaps(other) {
+				validationErrs = append(validationErrs, NewValidationError(
+					ValidationErrorTypeOverlappingPeriods,
+					split.ID,
+					split.Origin,
+				))
+			}
+		}
+	}
+
+	return validationErrs
+}
+
 func toFloat64(value bigquery.Value) float64 {
 	switch value := value.(type) {
 	case float64:
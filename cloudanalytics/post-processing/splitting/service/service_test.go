@@ -2,14 +2,17 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/consts"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain"
 	domainSplit "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain/split"
 	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 	testTools "github.com/doitintl/hello/scheduled-tasks/common/test_tools"
 )
 
@@ -64,7 +67,146 @@ func TestService_Split(t *testing.T) {
 	}
 }
 
+func TestService_SplitTimeBased(t *testing.T) {
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	rowsCols := []*domainQuery.QueryRequestX{
+		{ID: "attribution_group:teams", Key: "teams", Type: metadata.MetadataFieldTypeAttributionGroup},
+		{ID: "datetime:year", Key: "year", Type: metadata.MetadataFieldTypeDatetime},
+		{ID: "datetime:month", Key: "month", Type: metadata.MetadataFieldTypeDatetime},
+	}
+
+	tests := []struct {
+		name     string
+		split    domainSplit.Split
+		rows     [][]bigquery.Value
+		interval report.TimeInterval
+		wantRows [][]bigquery.Value
+		wantErr  error
+	}{
+		{
+			name: "custom split with effective-date periods",
+			split: domainSplit.Split{
+				ID:      "attribution_group:teams",
+				Type:    metadata.MetadataFieldTypeAttributionGroup,
+				Origin:  "shared",
+				Mode:    domainSplit.ModeCustom,
+				Targets: []domainSplit.SplitTarget{{ID: "a", Value: 0.5}, {ID: "b", Value: 0.5}},
+				Periods: []domainSplit.SplitPeriod{
+					{From: &february, Targets: []domainSplit.SplitTarget{{ID: "a", Value: 0.25}, {ID: "b", Value: 0.75}}},
+				},
+			},
+			rows: [][]bigquery.Value{
+				{"shared", "2024", "01", 100.0, 0.0},
+				{"shared", "2024", "02", 100.0, 0.0},
+				{"a", "2024", "01", 10.0, 0.0},
+				{"a", "2024", "02", 10.0, 0.0},
+				{"b", "2024", "01", 20.0, 0.0},
+				{"b", "2024", "02", 20.0, 0.0},
+			},
+			interval: report.TimeIntervalMonth,
+			wantRows: [][]bigquery.Value{
+				{"a", "2024", "01", 60.0, 0.0},
+				{"a", "2024", "02", 35.0, 0.0},
+				{"b", "2024", "01", 70.0, 0.0},
+				{"b", "2024", "02", 95.0, 0.0},
+			},
+		},
+		{
+			name: "periods are rejected when the result is not a time series",
+			split: domainSplit.Split{
+				ID:      "attribution_group:teams",
+				Type:    metadata.MetadataFieldTypeAttributionGroup,
+				Origin:  "shared",
+				Mode:    domainSplit.ModeCustom,
+				Targets: []domainSplit.SplitTarget{{ID: "a", Value: 0.5}, {ID: "b", Value: 0.5}},
+				Periods: []domainSplit.SplitPeriod{
+					{From: &february, Targets: []domainSplit.SplitTarget{{ID: "a", Value: 0.25}, {ID: "b", Value: 0.75}}},
+				},
+			},
+			rows: [][]bigquery.Value{
+				{"shared", "2024", "02", 100.0, 0.0},
+				{"a", "2024", "02", 10.0, 0.0},
+				{"b", "2024", "02", 20.0, 0.0},
+			},
+			wantErr: ErrPeriodsWithoutTimeInterval,
+		},
+		{
+			name: "usage-driven split",
+			split: domainSplit.Split{
+				ID:          "attribution_group:teams",
+				Type:        metadata.MetadataFieldTypeAttributionGroup,
+				Origin:      "shared",
+				Mode:        domainSplit.ModeUsageDriven,
+				UsageMetric: "requests",
+				Targets:     []domainSplit.SplitTarget{{ID: "a"}, {ID: "b"}},
+			},
+			rows: [][]bigquery.Value{
+				{"shared", "2024", "01", 100.0, 0.0},
+				{"shared", "2024", "02", 100.0, 0.0},
+				{"a", "2024", "01", 10.0, 300.0},
+				{"a", "2024", "02", 10.0, 100.0},
+				{"b", "2024", "01", 20.0, 100.0},
+				{"b", "2024", "02", 20.0, 100.0},
+			},
+			interval: report.TimeIntervalMonth,
+			wantRows: [][]bigquery.Value{
+				{"a", "2024", "01", 85.0, 300.0},
+				{"a", "2024", "02", 60.0, 100.0},
+				{"b", "2024", "01", 45.0, 100.0},
+				{"b", "2024", "02", 70.0, 100.0},
+			},
+		},
+		{
+			name: "usage-driven split with a usage metric that is not in the query",
+			split: domainSplit.Split{
+				ID:          "attribution_group:teams",
+				Type:        metadata.MetadataFieldTypeAttributionGroup,
+				Origin:      "shared",
+				Mode:        domainSplit.ModeUsageDriven,
+				UsageMetric: "tokens",
+				Targets:     []domainSplit.SplitTarget{{ID: "a"}, {ID: "b"}},
+			},
+			rows: [][]bigquery.Value{
+				{"shared", "2024", "01", 100.0, 0.0},
+				{"a", "2024", "01", 10.0, 300.0},
+			},
+			wantErr: ErrUsageMetricNotInQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSplittingService()
+
+			rows := tt.rows
+			splits := []domainSplit.Split{tt.split}
+
+			err := s.Split(domain.BuildSplit{
+				MetricsLength:      2,
+				RowsCols:           rowsCols,
+				NumRows:            1,
+				NumCols:            2,
+				ResRows:            &rows,
+				SplitsReq:          &splits,
+				TimeInterval:       tt.interval,
+				UsageMetricIndexes: map[string]int{"requests": 4},
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantRows, rows)
+		})
+	}
+}
+
 func TestService_ValidateSplitsReq(t *testing.T) {
+	periodFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodTo := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name     string
 		req      *[]domainSplit.Split
@@ -197,6 +339,138 @@ func TestService_ValidateSplitsReq(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "error on usage-driven split without a usage metric",
+			req: &[]domainSplit.Split{
+				{
+					ID:     "attribution_group:split1",
+					Origin: "attribution:def",
+					Type:   "attribution_group",
+					Mode:   domainSplit.ModeUsageDriven,
+					Targets: []domainSplit.SplitTarget{
+						{ID: "attribution:ghi"},
+					},
+				},
+			},
+			wantErrs: []error{
+				NewValidationError(
+					ValidationErrorTypeMissingUsageMetric,
+					"attribution_group:split1",
+					"attribution:def",
+				),
+			},
+		},
+		{
+			name: "ID used as origin and period target in same split",
+			req: &[]domainSplit.Split{
+				{
+					ID:      "attribution_group:split1",
+					Origin:  "attribution:def",
+					Type:    "attribution_group",
+					Mode:    domainSplit.ModeEven,
+					Targets: []domainSplit.SplitTarget{{ID: "attribution:ghi"}},
+					Periods: []domainSplit.SplitPeriod{
+						{From: &periodFrom, Targets: []domainSplit.SplitTarget{{ID: "attribution:def"}}},
+					},
+				},
+			},
+			wantErrs: []error{
+				NewValidationError(
+					ValidationErrorTypeIDCannotBeOriginAndTargetInSameSplit,
+					"attribution_group:split1",
+					"attribution:def",
+				),
+			},
+		},
+		{
+			name: "circular dependency through a period target",
+			req: &[]domainSplit.Split{
+				{
+					ID:      "attribution_group:split1",
+					Origin:  "attribution:abc",
+					Type:    "attribution_group",
+					Mode:    domainSplit.ModeEven,
+					Targets: []domainSplit.SplitTarget{{ID: "attribution:ghi"}},
+					Periods: []domainSplit.SplitPeriod{
+						{From: &periodFrom, Targets: []domainSplit.SplitTarget{{ID: "attribution:def"}}},
+					},
+				},
+				{
+					ID:      "attribution_group:split2",
+					Origin:  "attribution:def",
+					Type:    "attribution_group",
+					Mode:    domainSplit.ModeEven,
+					Targets: []domainSplit.SplitTarget{{ID: "attribution:abc"}},
+				},
+			},
+			wantErrs: []error{
+				NewValidationError(
+					ValidationErrorTypeCircularDependency,
+					"attribution_group:split1",
+					"attribution:def",
+				),
+				NewValidationError(
+					ValidationErrorTypeCircularDependency,
+					"attribution_group:split2",
+					"attribution:abc",
+				),
+			},
+		},
+		{
+			name: "error on overlapping and invalid periods",
+			req: &[]domainSplit.Split{
+				{
+					ID:     "attribution_group:split1",
+					Origin: "attribution:def",
+					Type:   "attribution_group",
+					Mode:   domainSplit.ModeCustom,
+					Targets: []domainSplit.SplitTarget{
+						{ID: "attribution:ghi", Value: 1},
+					},
+					Periods: []domainSplit.SplitPeriod{
+						{From: &periodFrom},
+						{From: &periodFrom, To: &periodTo},
+						{From: &periodTo, To: &periodFrom},
+					},
+				},
+			},
+			wantErrs: []error{
+				NewValidationError(
+					ValidationErrorTypeOverlappingPeriods,
+					"attribution_group:split1",
+					"attribution:def",
+				),
+				NewValidationError(
+					ValidationErrorTypeInvalidPeriod,
+					"attribution_group:split1",
+					"attribution:def",
+				),
+			},
+		},
+		{
+			name: "error on periods of a proportional split",
+			req: &[]domainSplit.Split{
+				{
+					ID:     "attribution_group:split1",
+					Origin: "attribution:def",
+					Type:   "attribution_group",
+					Mode:   domainSplit.ModeProportional,
+					Targets: []domainSplit.SplitTarget{
+						{ID: "attribution:ghi"},
+					},
+					Periods: []domainSplit.SplitPeriod{
+						{From: &periodFrom},
+					},
+				},
+			},
+			wantErrs: []error{
+				NewValidationError(
+					ValidationErrorTypePeriodsNotSupported,
+					"attribution_group:split1",
+					"attribution:def",
+				),
+			},
+		},
 	}
 
 	for _, tt := range tests {
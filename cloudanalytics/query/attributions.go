@@ -125,7 +125,7 @@ func (q *Query) buildCompositeFilter(ctx context.Context, attr *queryDomain.Quer
 		return "", ErrEmptyFormula
 	}
 
-	if err := q.attributionQuery.ValidateFormula(ctx, q.bq, len(predicates), attr.Formula); err != nil {
+	if err := q.attributionQuery.ValidateFormula(ctx, len(predicates), attr.Formula); err != nil {
 		return "", err
 	}
 
@@ -3,12 +3,9 @@ package query
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
-	"cloud.google.com/go/bigquery"
-
-	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/consts"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/formula"
 )
 
 type LogicalOperator = string
@@ -45,95 +42,35 @@ func (q *AttributionQuery) LogicalOperatorsSymbolToAlpha(formula string) string
 	return formula
 }
 
-// validateFormulaQuery passes the raw formula with mock variables to validate the formula is valid
-func (q *AttributionQuery) validateFormulaQuery(ctx context.Context, bq *bigquery.Client, variablesLength int, formula string) error {
-	subQueryElements := make([]string, 0)
-
-	for i := 0; i < variablesLength; i++ {
-		variableString := getVariableStringFromIndex(i)
-		expression := fmt.Sprintf("%d AS %s", i+1, variableString)
-		formula = q.LogicalOperatorsAlphaToSymbol(formula)
-		formula = strings.ReplaceAll(formula, variableString, fmt.Sprintf("%s = %d ", variableString, i+1))
-		formula = q.LogicalOperatorsSymbolToAlpha(formula)
-
-		subQueryElements = append(subQueryElements, expression)
-	}
-
-	queryString := fmt.Sprintf("SELECT * FROM (SELECT %s) WHERE %s", strings.Join(subQueryElements, ","), formula)
-	queryJob := bq.Query(queryString)
-	queryJob.DryRun = true
-	_, err := queryJob.Run(ctx)
-
-	return err
-}
-
-// validateChars validates the formula contains only valid characters A-Z, AND, OR, NOT, (, )
-func (q *AttributionQuery) validateChars(formula string) bool {
-	matched, err := regexp.MatchString(`^[A-Z\(\)(?:AND|OR|NOT) ]+$`, formula)
-	if err != nil {
-		return false
-	}
-
-	return matched
-}
-
-func (q *AttributionQuery) validateVariablesLength(formula string, variablesLength int) bool {
-	for i := 0; i < variablesLength; i++ {
-		char := string(rune(consts.ASCIIAInt + i))
-		if !strings.Contains(formula, char) {
-			return false
-		}
-	}
-
-	return true
-}
-
 func (q *AttributionQuery) isFormulaLengthValid(formula string) bool {
 	return len(formula) <= FormulaMaxLength
 }
 
-func (q *AttributionQuery) validateParenthesis(formula string) bool {
-	var i int
-
-	for _, c := range formula {
-		switch c {
-		case '(':
-			i++
-		case ')':
-			i--
-		default:
-		}
-
-		if i < 0 {
-			return false
-		}
-	}
-
-	return i == 0
+// ValidateFormula parses the formula and checks that it uses exactly the variables
+// A up to the variablesLength-th letter. Errors wrap ErrInvalidFormula and report the
+// position of the offending token.
+func (q *AttributionQuery) ValidateFormula(ctx context.Context, variablesLength int, rawFormula string) error {
+	_, err := q.NormalizeFormula(ctx, variablesLength, rawFormula)
+	return err
 }
 
-func (q *AttributionQuery) ValidateFormula(ctx context.Context, bq *bigquery.Client, variablesLength int, formula string) error {
-	if !q.validateParenthesis(formula) {
-		return ErrInvalidParenthesis
-	}
-
-	if !q.validateChars(formula) {
-		return ErrInvalidChars
+// NormalizeFormula validates the formula as ValidateFormula does and returns it normalized,
+// with single spaces between the tokens and only the parenthesis needed to keep its meaning.
+func (q *AttributionQuery) NormalizeFormula(ctx context.Context, variablesLength int, rawFormula string) (string, error) {
+	if !q.isFormulaLengthValid(rawFormula) {
+		return "", ErrTooLongFormula
 	}
 
-	if !q.validateVariablesLength(formula, variablesLength) {
-		return ErrInvalidVariableLength
-	}
-
-	if !q.isFormulaLengthValid(formula) {
-		return ErrTooLongFormula
+	f, err := formula.ParseLogical(rawFormula, variablesLength)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidFormula, err)
 	}
 
-	if err := q.validateFormulaQuery(ctx, bq, variablesLength, formula); err != nil {
-		return ErrInvalidFormula
+	if err := f.ValidateVariablesUsed(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidFormula, err)
 	}
 
-	return nil
+	return f.String(), nil
 }
 
 func getVariableStringFromIndex(index int) string {
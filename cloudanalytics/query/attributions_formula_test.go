@@ -1,34 +1,125 @@
 package query
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_AttributionQuery_validateChars(t *testing.T) {
+func Test_AttributionQuery_ValidateFormula(t *testing.T) {
 	q := NewAttributionQuery()
+	ctx := context.Background()
 
-	validFormula := "A AND B OR (C AND D) OR NOT E"
-	isValid := q.validateChars(validFormula)
-	assert.Equal(t, isValid, true)
+	tests := []struct {
+		name            string
+		formula         string
+		variablesLength int
+		wantErr         error
+		wantErrMsg      string
+	}{
+		{
+			name:            "valid formula",
+			formula:         "A AND B OR (C AND D) OR NOT E",
+			variablesLength: 5,
+		},
+		{
+			name:            "symbol operators",
+			formula:         "A && B OR (C AND D) OR NOT E AND F",
+			variablesLength: 6,
+			wantErr:         ErrInvalidFormula,
+			wantErrMsg:      "formula is invalid: invalid character '&' at column 3",
+		},
+		{
+			name:            "unbalanced parenthesis",
+			formula:         "A AND B OR (C AND D OR (NOT E",
+			variablesLength: 5,
+			wantErr:         ErrInvalidFormula,
+			wantErrMsg:      "formula is invalid: unexpected end of formula at column 30",
+		},
+		{
+			name:            "undefined variable",
+			formula:         "A AND B OR C",
+			variablesLength: 2,
+			wantErr:         ErrInvalidFormula,
+			wantErrMsg:      "formula is invalid: variable C not defined",
+		},
+		{
+			name:            "unused variable",
+			formula:         "A AND C",
+			variablesLength: 3,
+			wantErr:         ErrInvalidFormula,
+			wantErrMsg:      "formula is invalid: variable B not used",
+		},
+		{
+			name:            "too long",
+			formula:         strings.Repeat("A AND ", FormulaMaxLength) + "A",
+			variablesLength: 1,
+			wantErr:         ErrTooLongFormula,
+		},
+	}
 
-	invalidFormula := "A && B OR (C AND D) OR NOT E AND F"
-	isValid = q.validateChars(invalidFormula)
-	assert.Equal(t, isValid, false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := q.ValidateFormula(ctx, tt.variablesLength, tt.formula)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, err, tt.wantErrMsg)
+			}
+		})
+	}
 }
 
-func Test_AttributionQuery_validateParenthesis(t *testing.T) {
+func Test_AttributionQuery_NormalizeFormula(t *testing.T) {
 	q := NewAttributionQuery()
+	ctx := context.Background()
 
-	validFormula := "A AND B OR (C AND D) OR NOT E"
-	isValid := q.validateParenthesis(validFormula)
-	assert.Equal(t, isValid, true)
+	tests := []struct {
+		name            string
+		formula         string
+		variablesLength int
+		want            string
+		wantErr         error
+	}{
+		{
+			name:            "redundant parenthesis and spaces",
+			formula:         "((A)  AND B) OR (C)",
+			variablesLength: 3,
+			want:            "A AND B OR C",
+		},
+		{
+			name:            "parenthesis needed to keep the precedence",
+			formula:         "A AND (B OR NOT (C))",
+			variablesLength: 3,
+			want:            "A AND (B OR NOT C)",
+		},
+		{
+			name:            "invalid formula",
+			formula:         "A AND",
+			variablesLength: 1,
+			wantErr:         ErrInvalidFormula,
+		},
+	}
 
-	invalidFormula := "A AND B OR (C AND D OR (NOT E"
-	isValid = q.validateParenthesis(invalidFormula)
-	assert.Equal(t, isValid, false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := q.NormalizeFormula(ctx, tt.variablesLength, tt.formula)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
 }
 
 func Test_AttributionQuery_logicalOperatorsAlphaToSymbol(t *testing.T) {
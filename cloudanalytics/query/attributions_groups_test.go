@@ -23,7 +23,7 @@ func TestQuery_HandleAttributionGroups(t *testing.T) {
 		attributionQuery: attributionQuery,
 	}
 
-	attributionQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	attributionQuery.On("ValidateFormula", ctx, mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
 	attributionQuery.On("LogicalOperatorsAlphaToSymbol", "A AND B OR (C AND D) OR NOT E").Return("A && B || (C && D) || ! E")
 	attributionQuery.On("LogicalOperatorsSymbolToAlpha", "A && B || (C && D) || ! E").Return("A AND B OR (C AND D) OR NOT E")
 	attributionQuery.On("LogicalOperatorsAlphaToSymbol", "A AND B").Return("A && B").Times(6)
@@ -76,7 +76,7 @@ func TestQuery_HandleAttributionGroups_AbsoluteKey(t *testing.T) {
 		attributionQuery: attributionQuery,
 	}
 
-	attributionQuery.On("ValidateFormula", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	attributionQuery.On("ValidateFormula", ctx, mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
 	attributionQuery.On("LogicalOperatorsAlphaToSymbol", "A AND B").Return("A && B").Times(1)
 	attributionQuery.On("LogicalOperatorsSymbolToAlpha", "T.project.ancestry_names IN UNNEST(@YXR0cmlidXRpb246dWlLZkdjNzhsWmdZOG1KbklBZks6cHJvamVjdF9hbmNlc3RyeV9uYW1lczowMA) && T.cloud_provider IN UNNEST(@YXR0cmlidXRpb246dWlLZkdjNzhsWmdZOG1KbklBZks6Y2xvdWRfcHJvdmlkZXI6MTA)").Return("T.project.ancestry_names IN UNNEST(@YXR0cmlidXRpb246dWlLZkdjNzhsWmdZOG1KbklBZks6cHJvamVjdF9hbmNlc3RyeV9uYW1lczowMA) AND T.cloud_provider IN UNNEST(@YXR0cmlidXRpb246dWlLZkdjNzhsWmdZOG1KbklBZks6Y2xvdWRfcHJvdmlkZXI6MTA)")
 
@@ -65,7 +65,7 @@ func TestQuery_Query_buildCompositeFilter(t *testing.T) {
 				predicates: predicates,
 			},
 			on: func(f *fields) {
-				f.attributionQuery.On("ValidateFormula", ctx, bq, len(predicates), validFormula).Return(nil)
+				f.attributionQuery.On("ValidateFormula", ctx, len(predicates), validFormula).Return(nil)
 				f.attributionQuery.On("LogicalOperatorsAlphaToSymbol", validFormula).Return(validFormulaWithSymbols)
 				f.attributionQuery.On("LogicalOperatorsSymbolToAlpha", validFormulaWithPredicatesSymbols).Return(validFormulaWithPredicatesAlpha)
 			},
@@ -82,7 +82,7 @@ func TestQuery_Query_buildCompositeFilter(t *testing.T) {
 				predicates: predicates,
 			},
 			on: func(f *fields) {
-				f.attributionQuery.On("ValidateFormula", ctx, bq, len(predicates), invalidFormula).Return(fmt.Errorf("invalid formula"))
+				f.attributionQuery.On("ValidateFormula", ctx, len(predicates), invalidFormula).Return(fmt.Errorf("invalid formula"))
 			},
 		},
 	}
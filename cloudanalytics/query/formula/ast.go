@@ -0,0 +1,96 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// precedence of the operators, higher binds tighter.
+const (
+	precedenceOr = iota + 1
+	precedenceAnd
+	precedenceNot
+	precedenceAdditive
+	precedenceMultiplicative
+	precedenceUnary
+	precedencePrimary
+)
+
+type node interface {
+	precedence() int
+	String() string
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) precedence() int {
+	return precedencePrimary
+}
+
+func (n *variableNode) String() string {
+	return n.name
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) precedence() int {
+	return precedencePrimary
+}
+
+func (n *numberNode) String() string {
+	return strconv.FormatFloat(n.value, 'f', -1, 64)
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+	prec     int
+}
+
+func (n *unaryNode) precedence() int {
+	return n.prec
+}
+
+func (n *unaryNode) String() string {
+	if n.operator == keywordNot {
+		return fmt.Sprintf("%s %s", n.operator, wrap(n.operand, n.operand.precedence() < n.prec))
+	}
+
+	// Nested negations keep their parenthesis, "--" starts a comment in BigQuery.
+	return n.operator + wrap(n.operand, n.operand.precedence() <= n.prec)
+}
+
+type binaryNode struct {
+	operator string
+	left     node
+	right    node
+	prec     int
+}
+
+func (n *binaryNode) precedence() int {
+	return n.prec
+}
+
+// String renders the expression with the minimal parenthesis needed to keep its meaning.
+// AND, OR, + and * are associative, so only the right side of - and / keeps parenthesis
+// around an operand of the same precedence.
+func (n *binaryNode) String() string {
+	nonAssociative := n.operator == "-" || n.operator == "/"
+
+	left := wrap(n.left, n.left.precedence() < n.prec)
+	right := wrap(n.right, n.right.precedence() < n.prec || (nonAssociative && n.right.precedence() == n.prec))
+
+	return fmt.Sprintf("%s %s %s", left, n.operator, right)
+}
+
+func wrap(n node, parenthesis bool) string {
+	if parenthesis {
+		return "(" + n.String() + ")"
+	}
+
+	return n.String()
+}
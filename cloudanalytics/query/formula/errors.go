@@ -0,0 +1,51 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEmptyFormula      = errors.New("formula cannot be empty")
+	ErrInvalidCharacter  = errors.New("invalid character")
+	ErrUnexpectedToken   = errors.New("unexpected token")
+	ErrUnexpectedEnd     = errors.New("unexpected end of formula")
+	ErrUndefinedVariable = errors.New("variable not defined")
+	ErrUnusedVariable    = errors.New("variable not used")
+)
+
+// Error is a formula validation error. Column is the 1-based position of the offending token.
+type Error struct {
+	Err    error
+	Token  string
+	Column int
+}
+
+func (e *Error) Error() string {
+	switch e.Err {
+	case ErrInvalidCharacter:
+		return fmt.Sprintf("invalid character '%s' at column %d", e.Token, e.Column)
+	case ErrUnexpectedToken:
+		return fmt.Sprintf("unexpected '%s' at column %d", e.Token, e.Column)
+	case ErrUnexpectedEnd:
+		return fmt.Sprintf("unexpected end of formula at column %d", e.Column)
+	case ErrUndefinedVariable:
+		return fmt.Sprintf("variable %s not defined", e.Token)
+	case ErrUnusedVariable:
+		return fmt.Sprintf("variable %s not used", e.Token)
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(err error, tok token) *Error {
+	return &Error{
+		Err:    err,
+		Token:  tok.text,
+		Column: tok.column,
+	}
+}
@@ -0,0 +1,104 @@
+package formula
+
+import (
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenVariable
+	tokenNumber
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenPlus
+	tokenMinus
+	tokenMultiply
+	tokenDivide
+	tokenOpenParenthesis
+	tokenCloseParenthesis
+	tokenWord
+)
+
+const (
+	keywordAnd = "AND"
+	keywordOr  = "OR"
+	keywordNot = "NOT"
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+var symbols = map[rune]tokenKind{
+	'+': tokenPlus,
+	'-': tokenMinus,
+	'*': tokenMultiply,
+	'/': tokenDivide,
+	'(': tokenOpenParenthesis,
+	')': tokenCloseParenthesis,
+}
+
+// tokenize splits the formula into tokens. Words that are not keywords or single letter
+// variables are returned as tokenWord so the parser can report them in context.
+func tokenize(formula string) ([]token, error) {
+	runes := []rune(formula)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, wordToken(string(runes[start:i]), column))
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), column: column})
+		default:
+			kind, ok := symbols[r]
+			if !ok {
+				return nil, newError(ErrInvalidCharacter, token{text: string(r), column: column})
+			}
+
+			tokens = append(tokens, token{kind: kind, text: string(r), column: column})
+			i++
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, column: len(runes) + 1}), nil
+}
+
+func wordToken(word string, column int) token {
+	tok := token{kind: tokenWord, text: word, column: column}
+
+	switch word {
+	case keywordAnd:
+		tok.kind = tokenAnd
+	case keywordOr:
+		tok.kind = tokenOr
+	case keywordNot:
+		tok.kind = tokenNot
+	default:
+		if len(word) == 1 && word[0] >= 'A' && word[0] <= 'Z' {
+			tok.kind = tokenVariable
+		}
+	}
+
+	return tok
+}
@@ -0,0 +1,246 @@
+// Package formula parses the attribution and calculated metric formulas locally,
+// reporting syntax errors with their position instead of dry-running them on BigQuery.
+//
+// Attribution formulas combine the variables A..Z with AND, OR, NOT and parenthesis.
+// Calculated metric formulas combine the variables and numbers with +, -, *, / and parenthesis.
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formula is a parsed formula.
+type Formula struct {
+	root            node
+	variablesLength int
+	used            map[string]bool
+}
+
+// String returns the normalized formula.
+func (f *Formula) String() string {
+	return f.root.String()
+}
+
+// UnusedVariables returns the defined variables that do not appear in the formula.
+func (f *Formula) UnusedVariables() []string {
+	unused := make([]string, 0)
+
+	for i := 0; i < f.variablesLength; i++ {
+		if name := variableName(i); !f.used[name] {
+			unused = append(unused, name)
+		}
+	}
+
+	return unused
+}
+
+// ValidateVariablesUsed returns an error for the first defined variable that does not
+// appear in the formula.
+func (f *Formula) ValidateVariablesUsed() error {
+	if unused := f.UnusedVariables(); len(unused) > 0 {
+		return &Error{Err: ErrUnusedVariable, Token: unused[0]}
+	}
+
+	return nil
+}
+
+// ParseLogical parses an attribution formula over variablesLength variables.
+func ParseLogical(formula string, variablesLength int) (*Formula, error) {
+	return parse(formula, variablesLength, func(p *parser) (node, error) {
+		return p.parseOr()
+	})
+}
+
+// ParseArithmetic parses a calculated metric formula over variablesLength variables.
+func ParseArithmetic(formula string, variablesLength int) (*Formula, error) {
+	return parse(formula, variablesLength, func(p *parser) (node, error) {
+		return p.parseAdditive()
+	})
+}
+
+func parse(formula string, variablesLength int, parseRoot func(p *parser) (node, error)) (*Formula, error) {
+	if strings.TrimSpace(formula) == "" {
+		return nil, ErrEmptyFormula
+	}
+
+	tokens, err := tokenize(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		tokens:          tokens,
+		variablesLength: variablesLength,
+		used:            make(map[string]bool),
+	}
+
+	root, err := parseRoot(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, newError(ErrUnexpectedToken, tok)
+	}
+
+	return &Formula{
+		root:            root,
+		variablesLength: variablesLength,
+		used:            p.used,
+	}, nil
+}
+
+type parser struct {
+	tokens          []token
+	pos             int
+	variablesLength int
+	used            map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) unexpected(tok token) error {
+	if tok.kind == tokenEOF {
+		return newError(ErrUnexpectedEnd, tok)
+	}
+
+	return newError(ErrUnexpectedToken, tok)
+}
+
+// parseBinary parses a left associative chain of operands joined by the given operators.
+func (p *parser) parseBinary(prec int, operand func() (node, error), operators ...tokenKind) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if !hasKind(tok, operators) {
+			return left, nil
+		}
+
+		p.next()
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryNode{operator: tok.text, left: left, right: right, prec: prec}
+	}
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary(precedenceOr, p.parseAnd, tokenOr)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(precedenceAnd, p.parseNot, tokenAnd)
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind != tokenNot {
+		return p.parsePrimary(p.parseOr, false)
+	}
+
+	tok := p.next()
+
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &unaryNode{operator: tok.text, operand: operand, prec: precedenceNot}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(precedenceAdditive, p.parseMultiplicative, tokenPlus, tokenMinus)
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary(precedenceMultiplicative, p.parseUnary, tokenMultiply, tokenDivide)
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind != tokenMinus {
+		return p.parsePrimary(p.parseAdditive, true)
+	}
+
+	tok := p.next()
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &unaryNode{operator: tok.text, operand: operand, prec: precedenceUnary}, nil
+}
+
+// parsePrimary parses a variable, a number when allowed, or a parenthesized expression.
+func (p *parser) parsePrimary(parseExpression func() (node, error), numbers bool) (node, error) {
+	tok := p.next()
+
+	switch {
+	case tok.kind == tokenVariable:
+		return p.variable(tok)
+	case tok.kind == tokenNumber && numbers:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, newError(ErrUnexpectedToken, tok)
+		}
+
+		return &numberNode{value: value}, nil
+	case tok.kind == tokenOpenParenthesis:
+		expr, err := parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenCloseParenthesis {
+			return nil, p.unexpected(closing)
+		}
+
+		return expr, nil
+	default:
+		return nil, p.unexpected(tok)
+	}
+}
+
+func (p *parser) variable(tok token) (node, error) {
+	index := int(tok.text[0] - 'A')
+	if index >= p.variablesLength {
+		return nil, newError(ErrUndefinedVariable, tok)
+	}
+
+	p.used[tok.text] = true
+
+	return &variableNode{name: tok.text}, nil
+}
+
+func hasKind(tok token, kinds []tokenKind) bool {
+	for _, kind := range kinds {
+		if tok.kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func variableName(index int) string {
+	return fmt.Sprintf("%c", 'A'+index)
+}
@@ -0,0 +1,210 @@
+package formula
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLogical(t *testing.T) {
+	tests := []struct {
+		name            string
+		formula         string
+		variablesLength int
+		want            string
+		wantErr         error
+		wantErrMsg      string
+	}{
+		{
+			name:            "valid formula",
+			formula:         "A AND B OR (C AND D) OR NOT E",
+			variablesLength: 5,
+			want:            "A AND B OR C AND D OR NOT E",
+		},
+		{
+			name:            "keeps required parenthesis",
+			formula:         "(A OR B)  AND NOT (C OR D)",
+			variablesLength: 4,
+			want:            "(A OR B) AND NOT (C OR D)",
+		},
+		{
+			name:            "removes redundant parenthesis",
+			formula:         "((A)) AND ((B AND C))",
+			variablesLength: 3,
+			want:            "A AND B AND C",
+		},
+		{
+			name:            "unexpected close parenthesis",
+			formula:         "A AND (B OR C))",
+			variablesLength: 3,
+			wantErr:         ErrUnexpectedToken,
+			wantErrMsg:      "unexpected ')' at column 15",
+		},
+		{
+			name:            "missing close parenthesis",
+			formula:         "A AND (B OR C",
+			variablesLength: 3,
+			wantErr:         ErrUnexpectedEnd,
+			wantErrMsg:      "unexpected end of formula at column 14",
+		},
+		{
+			name:            "undefined variable",
+			formula:         "A AND C",
+			variablesLength: 2,
+			wantErr:         ErrUndefinedVariable,
+			wantErrMsg:      "variable C not defined",
+		},
+		{
+			name:            "symbol operators are not allowed",
+			formula:         "A && B",
+			variablesLength: 2,
+			wantErr:         ErrInvalidCharacter,
+			wantErrMsg:      "invalid character '&' at column 3",
+		},
+		{
+			name:            "lower case operator",
+			formula:         "A and B",
+			variablesLength: 2,
+			wantErr:         ErrUnexpectedToken,
+			wantErrMsg:      "unexpected 'and' at column 3",
+		},
+		{
+			name:            "missing operator",
+			formula:         "A B",
+			variablesLength: 2,
+			wantErr:         ErrUnexpectedToken,
+			wantErrMsg:      "unexpected 'B' at column 3",
+		},
+		{
+			name:            "numbers are not allowed",
+			formula:         "A OR 1",
+			variablesLength: 1,
+			wantErr:         ErrUnexpectedToken,
+		},
+		{
+			name:            "empty formula",
+			formula:         "  ",
+			variablesLength: 1,
+			wantErr:         ErrEmptyFormula,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLogical(tt.formula, tt.variablesLength)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				if tt.wantErrMsg != "" {
+					assert.EqualError(t, err, tt.wantErrMsg)
+				}
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestParseArithmetic(t *testing.T) {
+	tests := []struct {
+		name            string
+		formula         string
+		variablesLength int
+		want            string
+		wantErr         error
+		wantErrMsg      string
+	}{
+		{
+			name:            "precedence",
+			formula:         "A+B*2",
+			variablesLength: 2,
+			want:            "A + B * 2",
+		},
+		{
+			name:            "keeps required parenthesis",
+			formula:         "(A + B) / (C - (D - 1.50))",
+			variablesLength: 4,
+			want:            "(A + B) / (C - (D - 1.5))",
+		},
+		{
+			name:            "removes redundant parenthesis",
+			formula:         "(A * B) + (C)",
+			variablesLength: 3,
+			want:            "A * B + C",
+		},
+		{
+			name:            "negation",
+			formula:         "-A - -(B)",
+			variablesLength: 2,
+			want:            "-A - -B",
+		},
+		{
+			name:            "nested negation",
+			formula:         "- -A",
+			variablesLength: 1,
+			want:            "-(-A)",
+		},
+		{
+			name:            "undefined variable",
+			formula:         "A / K",
+			variablesLength: 3,
+			wantErr:         ErrUndefinedVariable,
+			wantErrMsg:      "variable K not defined",
+		},
+		{
+			name:            "missing operand",
+			formula:         "A * (B + )",
+			variablesLength: 2,
+			wantErr:         ErrUnexpectedToken,
+			wantErrMsg:      "unexpected ')' at column 10",
+		},
+		{
+			name:            "invalid number",
+			formula:         "A * 1.2.3",
+			variablesLength: 1,
+			wantErr:         ErrUnexpectedToken,
+			wantErrMsg:      "unexpected '1.2.3' at column 5",
+		},
+		{
+			name:            "logical operators are not allowed",
+			formula:         "A AND B",
+			variablesLength: 2,
+			wantErr:         ErrUnexpectedToken,
+			wantErrMsg:      "unexpected 'AND' at column 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArithmetic(tt.formula, tt.variablesLength)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				if tt.wantErrMsg != "" {
+					assert.EqualError(t, err, tt.wantErrMsg)
+				}
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestFormula_UnusedVariables(t *testing.T) {
+	f, err := ParseLogical("A AND NOT C", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B", "D"}, f.UnusedVariables())
+	assert.EqualError(t, f.ValidateVariablesUsed(), "variable B not used")
+	assert.ErrorIs(t, f.ValidateVariablesUsed(), ErrUnusedVariable)
+
+	f, err = ParseArithmetic("A / B", 2)
+	assert.NoError(t, err)
+	assert.Empty(t, f.UnusedVariables())
+	assert.NoError(t, f.ValidateVariablesUsed())
+}
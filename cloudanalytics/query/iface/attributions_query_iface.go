@@ -3,12 +3,11 @@ package iface
 
 import (
 	"context"
-
-	"cloud.google.com/go/bigquery"
 )
 
 type IAttributionQuery interface {
-	ValidateFormula(ctx context.Context, bq *bigquery.Client, variablesLength int, formula string) error
+	ValidateFormula(ctx context.Context, variablesLength int, formula string) error
+	NormalizeFormula(ctx context.Context, variablesLength int, formula string) (string, error)
 	LogicalOperatorsAlphaToSymbol(formulaString string) string
 	LogicalOperatorsSymbolToAlpha(formulaString string) string
 }
@@ -5,8 +5,6 @@ package mocks
 import (
 	context "context"
 
-	bigquery "cloud.google.com/go/bigquery"
-
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -43,13 +41,37 @@ func (_m *IAttributionQuery) LogicalOperatorsSymbolToAlpha(formulaString string)
 	return r0
 }
 
-// ValidateFormula provides a mock function with given fields: ctx, bq, variablesLength, formula
-func (_m *IAttributionQuery) ValidateFormula(ctx context.Context, bq *bigquery.Client, variablesLength int, formula string) error {
-	ret := _m.Called(ctx, bq, variablesLength, formula)
+// NormalizeFormula provides a mock function with given fields: ctx, variablesLength, formula
+func (_m *IAttributionQuery) NormalizeFormula(ctx context.Context, variablesLength int, formula string) (string, error) {
+	ret := _m.Called(ctx, variablesLength, formula)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (string, error)); ok {
+		return rf(ctx, variablesLength, formula)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) string); ok {
+		r0 = rf(ctx, variablesLength, formula)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, variablesLength, formula)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateFormula provides a mock function with given fields: ctx, variablesLength, formula
+func (_m *IAttributionQuery) ValidateFormula(ctx context.Context, variablesLength int, formula string) error {
+	ret := _m.Called(ctx, variablesLength, formula)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *bigquery.Client, int, string) error); ok {
-		r0 = rf(ctx, bq, variablesLength, formula)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, variablesLength, formula)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -5,22 +5,22 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 	"unicode"
 
-	"cloud.google.com/go/bigquery"
 	"cloud.google.com/go/firestore"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attribution-groups/domain/attributiongroups"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/domain/attribution"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/consts"
+	forecastDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/domain"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
 	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
 	splitDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain/split"
 	queryDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
 	originDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain/origin"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/formula"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
@@ -48,6 +48,7 @@ type QueryRequest struct {
 	Metric             report.Metric                               `json:"metric"`
 	ExtendedMetric     string                                      `json:"extendedMetric"`
 	Forecast           bool                                        `json:"forecast"`
+	ForecastEngine     forecastDomain.Engine                       `json:"forecastEngine"`
 	Trends             []report.Feature                            `json:"trends"`
 	Mode               string                                      `json:"mode"`
 	IsCSP              bool                                        `json:"isCSP"`
@@ -891,30 +892,13 @@ func formulaSplitter(formula string, metricVariables map[string]string) ([]strin
 	return arrFormula, nil
 }
 
-// validateMetricsFormula passes the raw netrics formula with mock variables to validate the formula is valid
-func (c *QueryRequestCalculatedMetric) validateMetricsFormula(ctx context.Context, bq *bigquery.Client) error {
-	matched, err := regexp.MatchString(`^[\dA-J\(\)\+\-\*\/\.\s]+$`, c.Formula)
-	if err != nil {
-		return err
-	}
-
-	if !matched {
-		return fmt.Errorf("formula contains invalid illegal characters")
-	}
-
-	subQueryElements := make([]string, 0)
-
-	for i := range c.Variables {
-		expression := fmt.Sprintf(`%s AS %c`, fmt.Sprint(i+1), consts.ASCIIAInt+i)
-		subQueryElements = append(subQueryElements, expression)
+// validateMetricsFormula parses the raw metrics formula to validate the formula is valid
+func (c *QueryRequestCalculatedMetric) validateMetricsFormula() error {
+	if _, err := formula.ParseArithmetic(c.Formula, len(c.Variables)); err != nil {
+		return fmt.Errorf("invalid metric formula: %w", err)
 	}
 
-	queryString := fmt.Sprintf("SELECT %s FROM (SELECT %s)", c.Formula, strings.Join(subQueryElements, ","))
-	queryJob := bq.Query(queryString)
-	queryJob.DryRun = true
-	_, err = queryJob.Run(ctx)
-
-	return err
+	return nil
 }
 
 // parseMetricsFormulaExpression convers the raw metrics formula into a valid bigquery expression
@@ -1045,6 +1029,15 @@ func (qr *QueryRequest) GetMetricIndex() int {
 	return metricIdx
 }
 
+// GetSplitUsageMetrics returns the extended metrics the usage-driven splits of the query divide the origin by
+func (qr *QueryRequest) GetSplitUsageMetrics() []string {
+	if qr.SplitsReq == nil {
+		return nil
+	}
+
+	return splitDomain.UsageMetrics(*qr.SplitsReq)
+}
+
 func (qr *QueryRequest) GetMetricCount() int {
 	count := int(report.MetricEnumLength)
 
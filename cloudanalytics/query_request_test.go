@@ -399,6 +399,20 @@ func TestProcessSplit(t *testing.T) {
 	}
 }
 
+func TestGetSplitUsageMetrics(t *testing.T) {
+	splits := []splitDomain.Split{
+		{ID: "1", Mode: splitDomain.ModeUsageDriven, UsageMetric: "requests"},
+		{ID: "2", Mode: splitDomain.ModeEven},
+		{ID: "3", Mode: splitDomain.ModeUsageDriven, UsageMetric: "tokens"},
+		{ID: "4", Mode: splitDomain.ModeUsageDriven, UsageMetric: "requests"},
+	}
+
+	qr := QueryRequest{Metric: report.MetricCost, SplitsReq: &splits}
+	assert.Equal(t, []string{"requests", "tokens"}, qr.GetSplitUsageMetrics())
+
+	assert.Empty(t, (&QueryRequest{}).GetSplitUsageMetrics())
+}
+
 func TestGetMetricString(t *testing.T) {
 	extendedMetricFlexsave := "flexsave"
 
@@ -82,6 +82,7 @@ func FindFieldIndex(vs []*domainQuery.QueryRequestX, t string) int {
 var (
 	ErrCalculatedMetricNotProvided = errors.New("calculated metric is not provided")
 	ErrExtendedMetricNotProvided   = errors.New("extended metric is not provided")
+	ErrComparativeUsageDrivenSplit = errors.New("usage-driven splits are not supported in comparative reports")
 	ErrDataSourceIsNotSupported    = errors.New("data source is not supported")
 )
 
@@ -243,6 +244,20 @@ func getAzureReportFields(isCSP bool) string {
 	return strings.Join(fields, consts.Comma)
 }
 
+// GetReportFields returns the unified report select fields of the cloud provider billing tables.
+func GetReportFields(cloudProvider string, isCSP bool) (string, error) {
+	switch cloudProvider {
+	case common.Assets.GoogleCloud:
+		return getGcpReportFields(isCSP), nil
+	case common.Assets.AmazonWebServices:
+		return getAwsReportFields(isCSP), nil
+	case common.Assets.MicrosoftAzure:
+		return getAzureReportFields(isCSP), nil
+	default:
+		return "", fmt.Errorf("unsupported cloud provider %s", cloudProvider)
+	}
+}
+
 func getCustomerFeaturesReportFields(billingTableSuffix string, isCSP bool) string {
 	var additionalMapping map[string]string
 
@@ -459,6 +474,11 @@ func (s *CloudAnalyticsService) GetQueryResult(ctx context.Context, qr *QueryReq
 		}
 	}
 
+	usageMetrics := qr.GetSplitUsageMetrics()
+	if len(usageMetrics) > 0 && qr.Comparative != nil {
+		return result, ErrComparativeUsageDrivenSplit
+	}
+
 	processLimitInQuery := qr.Comparative != nil
 
 	if isForecastMode {
@@ -772,7 +792,7 @@ func (s *CloudAnalyticsService) GetQueryResult(ctx context.Context, qr *QueryReq
 
 	// Calculated metrics
 	if qr.CalculatedMetric != nil {
-		if err := qr.CalculatedMetric.validateMetricsFormula(ctx, bq); err != nil {
+		if err := qr.CalculatedMetric.validateMetricsFormula(); err != nil {
 			return result, err
 		}
 
@@ -808,6 +828,21 @@ func (s *CloudAnalyticsService) GetQueryResult(ctx context.Context, qr *QueryReq
 		countsAliases = append(countsAliases, countAggCountResult)
 	}
 
+	// Usage metrics of the usage-driven splits, follow the metrics and are removed once the splits are applied
+	for i, usageMetric := range usageMetrics {
+		usageMetricAlias := fmt.Sprintf("split_usage_metric_%d", i)
+		queryReportUsageMetric := fmt.Sprintf(`SUM(IF(report_value.ext_metric.key = @%s, report_value.ext_metric.value * IF(report_value.ext_metric.type = "cost", currency_conversion_rate, 1), 0)) AS %s`, usageMetricAlias, usageMetricAlias)
+		rowsCols.fieldsAliases = append(rowsCols.fieldsAliases, queryReportUsageMetric)
+		f.queryParams = append(f.queryParams, bigquery.QueryParameter{
+			Name:  usageMetricAlias,
+			Value: usageMetric,
+		})
+
+		if qr.Count != nil {
+			countsAliases = append(countsAliases, fmt.Sprintf("SUM(%s) AS %s", usageMetricAlias, usageMetricAlias))
+		}
+	}
+
 	var attrGroupsQueryFilters string
 	if len(attributionGroupFilters) > 0 {
 		attrGroupsQueryFilters = strings.NewReplacer(
@@ -966,7 +1001,7 @@ func (s *CloudAnalyticsService) GetQueryResult(ctx context.Context, qr *QueryReq
 
 		rowsAndCols := append(qr.Rows, qr.Cols...)
 
-		if err := split.Split(domain.BuildSplit{
+		buildSplit := domain.BuildSplit{
 			MetricsLength: qr.GetMetricCount(),
 			RowsCols:      rowsAndCols,
 			NumRows:       len(qr.Rows),
@@ -974,10 +1009,31 @@ func (s *CloudAnalyticsService) GetQueryResult(ctx context.Context, qr *QueryReq
 			ResRows:       &queryResponse.rows,
 			SplitsReq:     qr.SplitsReq,
 			Attributions:  attributions,
-		}); err != nil {
+		}
+
+		if isTimeSeriesReport {
+			buildSplit.TimeInterval = qr.TimeSettings.Interval
+		}
+
+		metricsEnd := len(rowsAndCols) + qr.GetMetricCount()
+
+		if len(usageMetrics) > 0 {
+			buildSplit.UsageMetricIndexes = make(map[string]int, len(usageMetrics))
+
+			for i, usageMetric := range usageMetrics {
+				buildSplit.UsageMetricIndexes[usageMetric] = metricsEnd + i
+			}
+		}
+
+		if err := split.Split(buildSplit); err != nil {
 			l.Errorf("error on splitting: %v", qr.SplitsReq)
 			return result, err
 		}
+
+		if len(usageMetrics) > 0 {
+			queryResponse.rows = trimRows(queryResponse.rows, metricsEnd)
+			queryResponse.allRows = trimRows(queryResponse.allRows, metricsEnd)
+		}
 	}
 
 	if !processLimitInQuery && hasTopBottomLimit {
@@ -1028,7 +1084,9 @@ func (s *CloudAnalyticsService) GetQueryResult(ctx context.Context, qr *QueryReq
 		if len(queryResponse.allRows) > 0 {
 			maxFreshTime := time.Now().UTC().Add(time.Hour * -36)
 
-			_, forecastRows, err := s.forecastService.GetForecastOriginAndResultRows(ctx, result.Rows, len(qr.Rows), qr.Cols, interval, metric, maxFreshTime, from, to)
+			forecastCtx := forecastService.WithEngine(ctx, qr.ForecastEngine)
+
+			_, forecastRows, err := s.forecastService.GetForecastOriginAndResultRows(forecastCtx, result.Rows, len(qr.Rows), qr.Cols, interval, metric, maxFreshTime, from, to)
 			if err != nil {
 				return result, err
 			}
@@ -1322,6 +1322,17 @@ func dedupeQueryParamNames(params []bigquery.QueryParameter) []bigquery.QueryPar
 	return result
 }
 
+// trimRows removes the values after rowLength from every row
+func trimRows(rows [][]bigquery.Value, rowLength int) [][]bigquery.Value {
+	for i, row := range rows {
+		if len(row) > rowLength {
+			rows[i] = row[:rowLength]
+		}
+	}
+
+	return rows
+}
+
 func aggregateRows(rows [][]bigquery.Value, rowsColsLen, metricsLen int) ([][]bigquery.Value, error) {
 	var rowsMap = make(map[string][]int)
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/externalapi/domain/errormsg"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
@@ -30,6 +31,23 @@ type ExternalSplit struct {
 	IncludeOrigin bool `json:"includeOrigin" binding:"required"`
 	// Targets for the split
 	Targets []ExternalSplitTarget `json:"targets" binding:"required"`
+	// Key of the DataHub extended metric used to weight the targets. Must be set only if Split Mode is usage-driven.
+	// example: "requests"
+	UsageMetric string `json:"usageMetric,omitempty"`
+	// Effective-date periods overriding the targets for the time buckets starting within them.
+	// Supported only by even and custom splits of time series reports.
+	Periods []ExternalSplitPeriod `json:"periods,omitempty"`
+}
+
+type ExternalSplitPeriod struct {
+	// Start of the period, inclusive. The period has no start if not set.
+	// example: "2024-01-01T00:00:00Z"
+	From *time.Time `json:"from,omitempty"`
+	// End of the period, exclusive. The period has no end if not set.
+	// example: "2024-04-01T00:00:00Z"
+	To *time.Time `json:"to,omitempty"`
+	// Targets of the split during the period
+	Targets []ExternalSplitTarget `json:"targets" binding:"required"`
 }
 
 type ExternalOrigin struct {
@@ -59,6 +77,7 @@ func (externalSplit ExternalSplit) ToInternal() (*domainSplit.Split, []errormsg.
 		Mode:          externalSplit.Mode,
 		Origin:        externalSplit.Origin.getInternalID(),
 		IncludeOrigin: externalSplit.IncludeOrigin,
+		UsageMetric:   externalSplit.UsageMetric,
 	}
 
 	if err := externalSplit.Type.Validate(); err != nil {
@@ -79,9 +98,38 @@ func (externalSplit ExternalSplit) ToInternal() (*domainSplit.Split, []errormsg.
 		}
 	}
 
-	var totalSplitValue float64
+	targets, targetsValidationErrors := externalSplit.targetsToInternal(externalSplit.Targets)
+	if targetsValidationErrors != nil {
+		return nil, targetsValidationErrors
+	}
+
+	split.Targets = targets
+
+	for _, externalPeriod := range externalSplit.Periods {
+		periodTargets, periodValidationErrors := externalSplit.targetsToInternal(externalPeriod.Targets)
+		if periodValidationErrors != nil {
+			return nil, periodValidationErrors
+		}
 
-	for _, externalTarget := range externalSplit.Targets {
+		split.Periods = append(split.Periods, domainSplit.SplitPeriod{
+			From:    externalPeriod.From,
+			To:      externalPeriod.To,
+			Targets: periodTargets,
+		})
+	}
+
+	return &split, nil
+}
+
+// targetsToInternal converts the targets of the split or of one of its periods, whose values
+// must add up to 1 in custom splits
+func (externalSplit ExternalSplit) targetsToInternal(externalTargets []ExternalSplitTarget) ([]domainSplit.SplitTarget, []errormsg.ErrorMsg) {
+	var (
+		targets         []domainSplit.SplitTarget
+		totalSplitValue float64
+	)
+
+	for _, externalTarget := range externalTargets {
 		if (externalSplit.Mode != domainSplit.ModeCustom && externalTarget.Value != nil) ||
 			(externalSplit.Mode == domainSplit.ModeCustom && externalTarget.Value == nil) {
 			return nil, []errormsg.ErrorMsg{
@@ -98,12 +146,10 @@ func (externalSplit ExternalSplit) ToInternal() (*domainSplit.Split, []errormsg.
 			totalSplitValue = totalSplitValue + value
 		}
 
-		target := domainSplit.SplitTarget{
+		targets = append(targets, domainSplit.SplitTarget{
 			ID:    externalTarget.getInternalID(),
 			Value: value,
-		}
-
-		split.Targets = append(split.Targets, target)
+		})
 	}
 
 	if externalSplit.Mode == domainSplit.ModeCustom && math.Abs(1-totalSplitValue) >= Float64SplitEqualityThreshold {
@@ -115,7 +161,7 @@ func (externalSplit ExternalSplit) ToInternal() (*domainSplit.Split, []errormsg.
 		}
 	}
 
-	return &split, nil
+	return targets, nil
 }
 
 func NewExternalSplitFromInternal(split *domainSplit.Split) (*ExternalSplit, []errormsg.ErrorMsg) {
@@ -144,13 +190,40 @@ func NewExternalSplitFromInternal(split *domainSplit.Split) (*ExternalSplit, []e
 		Type:          split.Type,
 		Mode:          split.Mode,
 		IncludeOrigin: split.IncludeOrigin,
+		UsageMetric:   split.UsageMetric,
 		Origin: ExternalOrigin{
 			ID:   externalOriginID,
 			Type: externalOriginType,
 		},
 	}
 
-	for _, target := range split.Targets {
+	externalTargets, targetsErrors := newExternalSplitTargetsFromInternal(split.Mode, split.Targets)
+	if targetsErrors != nil {
+		return nil, targetsErrors
+	}
+
+	externalSplit.Targets = externalTargets
+
+	for _, period := range split.Periods {
+		externalPeriodTargets, periodErrors := newExternalSplitTargetsFromInternal(split.Mode, period.Targets)
+		if periodErrors != nil {
+			return nil, periodErrors
+		}
+
+		externalSplit.Periods = append(externalSplit.Periods, ExternalSplitPeriod{
+			From:    period.From,
+			To:      period.To,
+			Targets: externalPeriodTargets,
+		})
+	}
+
+	return &externalSplit, nil
+}
+
+func newExternalSplitTargetsFromInternal(mode domainSplit.Mode, targets []domainSplit.SplitTarget) ([]ExternalSplitTarget, []errormsg.ErrorMsg) {
+	var externalTargets []ExternalSplitTarget
+
+	for _, target := range targets {
 		externalTargetType, externalTargetID, err := getTypeAndID(target.ID)
 		if err != nil {
 			return nil, []errormsg.ErrorMsg{
@@ -163,21 +236,19 @@ func NewExternalSplitFromInternal(split *domainSplit.Split) (*ExternalSplit, []e
 
 		var externalValue *float64
 
-		if split.Mode == domainSplit.ModeCustom {
+		if mode == domainSplit.ModeCustom {
 			value := target.Value
 			externalValue = &value
 		}
 
-		externalTarget := ExternalSplitTarget{
+		externalTargets = append(externalTargets, ExternalSplitTarget{
 			ID:    externalTargetID,
 			Type:  externalTargetType,
 			Value: externalValue,
-		}
-
-		externalSplit.Targets = append(externalSplit.Targets, externalTarget)
+		})
 	}
 
-	return &externalSplit, nil
+	return externalTargets, nil
 }
 
 func (externalSplit ExternalSplit) getInternalID() string {
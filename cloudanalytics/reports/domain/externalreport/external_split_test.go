@@ -2,6 +2,7 @@ package externalreport
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -16,6 +17,8 @@ func TestExternalSplit_ToInternal(t *testing.T) {
 
 	targetValTooSmall := 0.1
 
+	periodFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name                 string
 		externalSplit        ExternalSplit
@@ -65,6 +68,65 @@ func TestExternalSplit_ToInternal(t *testing.T) {
 				IncludeOrigin: true,
 			},
 		},
+		{
+			name: "conversion to internal with periods",
+			externalSplit: ExternalSplit{
+				ID:   "attrgroup111",
+				Type: metadata.MetadataFieldTypeAttributionGroup,
+				Mode: split.ModeEven,
+				Origin: ExternalOrigin{
+					ID:   "attr1",
+					Type: metadata.MetadataFieldTypeAttribution,
+				},
+				Targets: []ExternalSplitTarget{
+					{ID: "attr2", Type: metadata.MetadataFieldTypeAttribution},
+				},
+				Periods: []ExternalSplitPeriod{
+					{
+						From:    &periodFrom,
+						Targets: []ExternalSplitTarget{{ID: "attr3", Type: metadata.MetadataFieldTypeAttribution}},
+					},
+				},
+			},
+			want: &split.Split{
+				ID:      "attribution_group:attrgroup111",
+				Type:    "attribution_group",
+				Origin:  "attribution:attr1",
+				Mode:    "even",
+				Targets: []split.SplitTarget{{ID: "attribution:attr2"}},
+				Periods: []split.SplitPeriod{
+					{From: &periodFrom, Targets: []split.SplitTarget{{ID: "attribution:attr3"}}},
+				},
+			},
+		},
+		{
+			name: "error when period target total does not total to 1 when mode is custom",
+			externalSplit: ExternalSplit{
+				ID:   "attrgroup111",
+				Type: metadata.MetadataFieldTypeAttributionGroup,
+				Mode: split.ModeCustom,
+				Origin: ExternalOrigin{
+					ID:   "attr1",
+					Type: metadata.MetadataFieldTypeAttribution,
+				},
+				Targets: []ExternalSplitTarget{
+					{ID: "attr2", Type: metadata.MetadataFieldTypeAttribution, Value: &targetVal},
+					{ID: "attr3", Type: metadata.MetadataFieldTypeAttribution, Value: &targetVal2},
+				},
+				Periods: []ExternalSplitPeriod{
+					{
+						From:    &periodFrom,
+						Targets: []ExternalSplitTarget{{ID: "attr2", Type: metadata.MetadataFieldTypeAttribution, Value: &targetValTooSmall}},
+					},
+				},
+			},
+			wantValidationErrors: []errormsg.ErrorMsg{
+				{
+					Field:   "split",
+					Message: "invalid target total for custom mode: 0.10",
+				},
+			},
+		},
 		{
 			name: "error when target total does not total to 1 when mode is custom",
 			externalSplit: ExternalSplit{
@@ -228,6 +290,7 @@ func TestExternalSplit_ToInternal(t *testing.T) {
 
 func TestNewExternalSplitFromInternal(t *testing.T) {
 	targetVal := 14.2
+	periodFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name                 string
@@ -269,6 +332,37 @@ func TestNewExternalSplitFromInternal(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "conversion to external with periods",
+			split: &split.Split{
+				ID:      "attribution_group:attrgroup111",
+				Type:    "attribution_group",
+				Origin:  "attribution:attr1",
+				Mode:    "even",
+				Targets: []split.SplitTarget{{ID: "attribution:attr2"}},
+				Periods: []split.SplitPeriod{
+					{From: &periodFrom, Targets: []split.SplitTarget{{ID: "attribution:attr3"}}},
+				},
+			},
+			want: &ExternalSplit{
+				ID:   "attrgroup111",
+				Type: metadata.MetadataFieldTypeAttributionGroup,
+				Mode: split.ModeEven,
+				Origin: ExternalOrigin{
+					ID:   "attr1",
+					Type: metadata.MetadataFieldTypeAttribution,
+				},
+				Targets: []ExternalSplitTarget{
+					{ID: "attr2", Type: metadata.MetadataFieldTypeAttribution},
+				},
+				Periods: []ExternalSplitPeriod{
+					{
+						From:    &periodFrom,
+						Targets: []ExternalSplitTarget{{ID: "attr3", Type: metadata.MetadataFieldTypeAttribution}},
+					},
+				},
+			},
+		},
 		{
 			name: "fail conversion to external",
 			split: &split.Split{
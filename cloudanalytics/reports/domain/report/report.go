@@ -301,8 +301,19 @@ type Schedule struct {
 	Timezone  string   `json:"timezone" firestore:"timezone"`
 	Subject   string   `json:"subject" firestore:"subject"`
 	Body      string   `json:"body" firestore:"body"`
+
+	// Attachments are the formats of the report data files attached to the email
+	Attachments []ScheduleAttachment `json:"attachments" firestore:"attachments"`
 }
 
+// swagger:enum ScheduleAttachment
+type ScheduleAttachment string
+
+const (
+	ScheduleAttachmentCSV  ScheduleAttachment = "csv"
+	ScheduleAttachmentXLSX ScheduleAttachment = "xlsx"
+)
+
 type ConfigCustomTimeRange struct {
 	From time.Time `json:"from" firestore:"from"`
 	To   time.Time `json:"to" firestore:"to"`
@@ -56,7 +56,7 @@ func (s *Service) NewExternalSplitToInternal(
 				attributionsIDs[attributionID] = true
 			}
 
-			for _, target := range split.Targets {
+			for _, target := range split.AllTargets() {
 				attributionID, err := getAttributionID(target.ID)
 				if err != nil {
 					validationErrors = append(validationErrors, errormsg.ErrorMsg{
@@ -17,6 +17,7 @@ import (
 	attributionConsts "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/domain/attribution/consts"
 	domainMetadata "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
 	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain/split"
 	queryDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/iface"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/externalreport"
@@ -334,6 +335,21 @@ func (s *ReportTierService) CheckAccessToExtendedMetric(
 	return nil, nil
 }
 
+// checkAccessToSplitUsageMetrics checks the access to the extended metrics the usage-driven splits divide their origin by
+func (s *ReportTierService) checkAccessToSplitUsageMetrics(
+	ctx context.Context,
+	customerID string,
+	usageMetrics []string,
+) (*domainTier.AccessDeniedError, error) {
+	for _, usageMetric := range usageMetrics {
+		if accessDeniedErr, err := s.CheckAccessToExtendedMetric(ctx, customerID, usageMetric); accessDeniedErr != nil || err != nil {
+			return accessDeniedErr, err
+		}
+	}
+
+	return nil, nil
+}
+
 func (s *ReportTierService) CheckAccessToQueryRequest(
 	ctx context.Context,
 	customerID string,
@@ -384,6 +400,10 @@ func (s *ReportTierService) CheckAccessToQueryRequest(
 		return accessDenied, err
 	}
 
+	if accessDenied, err := s.checkAccessToSplitUsageMetrics(ctx, customerID, qr.GetSplitUsageMetrics()); accessDenied != nil || err != nil {
+		return accessDenied, err
+	}
+
 	attributionIDs := make(map[string]string)
 	attributionGroupIDs := make(map[string]string)
 
@@ -499,6 +519,14 @@ func (s *ReportTierService) CheckAccessToReport(
 			return accessDeniedErr, err
 		}
 
+		if accessDeniedErr, err := s.checkAccessToSplitUsageMetrics(
+			ctx,
+			customerID,
+			split.UsageMetrics(report.Config.Splits),
+		); accessDeniedErr != nil || err != nil {
+			return accessDeniedErr, err
+		}
+
 		attributionIDs := make(map[string]string)
 		attributionGroupIDs := make(map[string]string)
 
@@ -625,6 +653,18 @@ func (s *ReportTierService) CheckAccessToExternalReport(
 			}
 		}
 
+		var usageMetrics []string
+
+		for _, externalSplit := range externalReport.Config.Splits {
+			if externalSplit != nil && externalSplit.Mode == split.ModeUsageDriven && externalSplit.UsageMetric != "" {
+				usageMetrics = append(usageMetrics, externalSplit.UsageMetric)
+			}
+		}
+
+		if accessDeniedErr, err := s.checkAccessToSplitUsageMetrics(ctx, customerID, usageMetrics); accessDeniedErr != nil || err != nil {
+			return accessDeniedErr, err
+		}
+
 		attributionIDs := make(map[string]string)
 		attrGroupIDs := make(map[string]string)
 
@@ -16,6 +16,7 @@ import (
 	attributionDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/domain/attribution"
 	domainMetadata "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metadata/domain/metadata"
 	metrics "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/metrics/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/post-processing/splitting/domain/split"
 	domainQuery "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
 	reportsDALMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/mocks"
 	externalReportDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/externalreport"
@@ -1977,6 +1978,17 @@ func TestReportTierService_CheckAccessToQuery(t *testing.T) {
 		Forecast: true,
 	}
 
+	queryUsageDrivenSplit := cloudanalytics.QueryRequest{
+		Type: "report",
+		ID:   reportID,
+		SplitsReq: &[]split.Split{
+			{
+				Mode:        split.ModeUsageDriven,
+				UsageMetric: domainReport.ExtendedMetricAmortizedCost,
+			},
+		},
+	}
+
 	queryAttrAndAttrGroups := cloudanalytics.QueryRequest{
 		Type: "report",
 		ID:   reportID,
@@ -2066,6 +2078,42 @@ func TestReportTierService_CheckAccessToQuery(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			name: "no access to the extended metric of a usage-driven split",
+			args: args{
+				ctx:        ctx,
+				customerID: customerID,
+				qr:         &queryUsageDrivenSplit,
+			},
+			expectedAccessErr: &AccessDeniedAmortizedCostSavingsExtendedMetrics,
+			on: func(f *fields) {
+				f.doitEmployeeService.On(
+					"IsDoitEmployee",
+					ctx,
+				).Return(false).
+					Once()
+				f.reportDAL.On(
+					"Get",
+					testutils.ContextBackgroundMock,
+					reportID,
+				).Return(&report, nil).
+					Once()
+				f.tierService.On(
+					"CustomerCanAccessFeature",
+					testutils.ContextBackgroundMock,
+					customerID,
+					pkg.TiersFeatureKeyAnalyticsReports,
+				).Return(true, nil).
+					Once()
+				f.tierService.On(
+					"CustomerCanAccessFeature",
+					testutils.ContextBackgroundMock,
+					customerID,
+					pkg.TiersFeatureKeyAnalyticsAmortizedCostSavingsExtendedMetrics,
+				).Return(false, nil).
+					Once()
+			},
+		},
 		{
 			name: "has access to attr and attr groups query",
 			args: args{
@@ -0,0 +1,120 @@
+package attachment
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTable() *Table {
+	return &Table{
+		Columns: []Column{
+			{Header: "Service", Type: ColumnTypeText},
+			{Header: "Month", Type: ColumnTypeText},
+			{Header: "Cost", Type: ColumnTypeCurrency},
+			{Header: "Usage", Type: ColumnTypeNumber},
+		},
+		Rows: []*Row{
+			{Values: []interface{}{"Compute Engine", "2024-01", 1234.5, 10.25}},
+			{Values: []interface{}{"Cloud Storage, \"Nearline\"", "2024-01", -3.456, 0.0}},
+			{Values: []interface{}{nil, "2024-02", 1300.0, nil}, Forecast: true},
+		},
+		CurrencySymbol: "$",
+	}
+}
+
+func TestTable_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := testTable().WriteCSV(&buf)
+	assert.NoError(t, err)
+
+	want := "Service,Month,Cost,Usage,Type\n" +
+		"Compute Engine,2024-01,\"$1,234.50\",10.25,Actual\n" +
+		"\"Cloud Storage, \"\"Nearline\"\"\",2024-01,-$3.46,0,Actual\n" +
+		",2024-02,\"$1,300.00\",,Forecast\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestTable_WriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := testTable().WriteXLSX(&buf)
+	assert.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+
+		files[f.Name] = string(content)
+	}
+
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+	} {
+		assert.Contains(t, files, name)
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t>Service</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="E1" s="1" t="inlineStr"><is><t>Type</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2" s="2"><v>1234.5</v></c>`)
+	assert.Contains(t, sheet, `<c r="D2" s="0"><v>10.25</v></c>`)
+	assert.Contains(t, sheet, `<t>Cloud Storage, &#34;Nearline&#34;</t>`)
+	assert.Contains(t, sheet, `<c r="C4" s="5"><v>1300</v></c>`)
+	assert.Contains(t, sheet, `<c r="E4" s="3" t="inlineStr"><is><t>Forecast</t></is></c>`)
+	assert.NotContains(t, sheet, `r="A4"`)
+
+	assert.Contains(t, files["xl/styles.xml"], `formatCode="&#34;$&#34;#,##0.00;-&#34;$&#34;#,##0.00"`)
+}
+
+func TestTable_WriteXLSX_RowLength(t *testing.T) {
+	table := testTable()
+	table.Rows = append(table.Rows, &Row{Values: []interface{}{"a"}})
+
+	err := table.WriteXLSX(io.Discard)
+	assert.EqualError(t, err, "row 3 has 1 values, expected 4")
+}
+
+func TestTable_Encode(t *testing.T) {
+	table := testTable()
+
+	csvFile, err := table.Encode("report", FormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, "report.csv", csvFile.Name)
+	assert.Equal(t, "text/csv", csvFile.ContentType)
+	assert.NotEmpty(t, csvFile.Content)
+
+	xlsxFile, err := table.Encode("report", FormatXLSX)
+	assert.NoError(t, err)
+	assert.Equal(t, "report.xlsx", xlsxFile.Name)
+	assert.Equal(t, contentTypeXLSX, xlsxFile.ContentType)
+
+	_, err = table.Encode("report", Format("pdf"))
+	assert.EqualError(t, err, "unsupported attachment format pdf")
+}
+
+func TestCellRef(t *testing.T) {
+	assert.Equal(t, "A1", cellRef(0, 1))
+	assert.Equal(t, "Z2", cellRef(25, 2))
+	assert.Equal(t, "AA3", cellRef(26, 3))
+	assert.Equal(t, "AZ4", cellRef(51, 4))
+	assert.Equal(t, "BA5", cellRef(52, 5))
+}
@@ -0,0 +1,61 @@
+package attachment
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// WriteCSV writes the table as CSV. Currency values are formatted with the currency
+// symbol and two decimal places.
+func (t *Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	p := message.NewPrinter(language.English)
+
+	if err := writer.Write(t.headers()); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, 0, len(t.Columns)+1)
+
+		for i, col := range t.Columns {
+			record = append(record, t.formatCSVValue(p, col, row.Values[i]))
+		}
+
+		record = append(record, rowType(row))
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func (t *Table) formatCSVValue(p *message.Printer, col Column, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		if col.Type == ColumnTypeCurrency {
+			if v < 0 {
+				return p.Sprintf("-%s%.2f", t.CurrencySymbol, -v)
+			}
+
+			return p.Sprintf("%s%.2f", t.CurrencySymbol, v)
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,109 @@
+// Package attachment renders report query results as CSV and XLSX files
+// that are attached to the scheduled report emails.
+package attachment
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+const (
+	contentTypeCSV  = "text/csv"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+type ColumnType int
+
+const (
+	ColumnTypeText ColumnType = iota
+	ColumnTypeNumber
+	ColumnTypeCurrency
+)
+
+type Column struct {
+	Header string
+	Type   ColumnType
+}
+
+// Row is a single table row. Values are either strings for text columns, float64 for
+// numeric columns, or nil for empty cells.
+type Row struct {
+	Values   []interface{}
+	Forecast bool
+}
+
+// Table is the tabular representation of a report result.
+type Table struct {
+	Columns        []Column
+	Rows           []*Row
+	CurrencySymbol string
+}
+
+// File is an encoded table ready to be attached to an email.
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Encode renders the table in the given format.
+func (t *Table) Encode(name string, format Format) (*File, error) {
+	var buf bytes.Buffer
+
+	file := &File{
+		Name: fmt.Sprintf("%s.%s", name, format),
+	}
+
+	switch format {
+	case FormatCSV:
+		if err := t.WriteCSV(&buf); err != nil {
+			return nil, err
+		}
+
+		file.ContentType = contentTypeCSV
+	case FormatXLSX:
+		if err := t.WriteXLSX(&buf); err != nil {
+			return nil, err
+		}
+
+		file.ContentType = contentTypeXLSX
+	default:
+		return nil, fmt.Errorf("unsupported attachment format %s", format)
+	}
+
+	file.Content = buf.Bytes()
+
+	return file, nil
+}
+
+// headers returns the column headers followed by the header of the row type column
+// that marks forecast rows.
+func (t *Table) headers() []string {
+	headers := make([]string, 0, len(t.Columns)+1)
+	for _, col := range t.Columns {
+		headers = append(headers, col.Header)
+	}
+
+	return append(headers, typeHeader)
+}
+
+const (
+	typeHeader   = "Type"
+	typeActual   = "Actual"
+	typeForecast = "Forecast"
+)
+
+func rowType(row *Row) string {
+	if row.Forecast {
+		return typeForecast
+	}
+
+	return typeActual
+}
@@ -0,0 +1,211 @@
+package attachment
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Cell style indexes in the cellXfs of xlsxStyles.
+const (
+	styleDefault = iota
+	styleHeader
+	styleCurrency
+	styleForecastText
+	styleForecastNumber
+	styleForecastCurrency
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	// xlsxStyles defines a bold font for the header, an italic font for forecast rows and
+	// a custom currency number format with id 164. The currency symbol is set with %s.
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="%s"/></numFmts>` +
+		`<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font><font><i/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="6">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1" applyNumberFormat="1"/>` +
+		`</cellXfs>` +
+		`</styleSheet>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// WriteXLSX writes the table as a single sheet XLSX workbook. Numeric values are kept as
+// numbers, currency values use a currency number format and forecast rows are italic.
+func (t *Table) WriteXLSX(w io.Writer) error {
+	sheet, err := t.xlsxSheet()
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", fmt.Sprintf(xlsxStyles, xmlEscape(currencyFormatCode(t.CurrencySymbol)))},
+		{"xl/worksheets/sheet1.xml", sheet},
+	}
+
+	zw := zip.NewWriter(w)
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(fw, file.content); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (t *Table) xlsxSheet() (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(xlsxSheetStart)
+
+	headers := t.headers()
+	headerValues := make([]interface{}, len(headers))
+
+	for i, h := range headers {
+		headerValues[i] = h
+	}
+
+	writeXLSXRow(&sb, 1, headerValues, func(int) int { return styleHeader })
+
+	for i, row := range t.Rows {
+		if len(row.Values) != len(t.Columns) {
+			return "", fmt.Errorf("row %d has %d values, expected %d", i, len(row.Values), len(t.Columns))
+		}
+
+		values := append(append([]interface{}{}, row.Values...), rowType(row))
+
+		writeXLSXRow(&sb, i+2, values, func(col int) int {
+			colType := ColumnTypeText
+			if col < len(t.Columns) {
+				colType = t.Columns[col].Type
+			}
+
+			return cellStyle(colType, row.Forecast)
+		})
+	}
+
+	sb.WriteString(xlsxSheetEnd)
+
+	return sb.String(), nil
+}
+
+func writeXLSXRow(sb *strings.Builder, rowNum int, values []interface{}, style func(col int) int) {
+	fmt.Fprintf(sb, `<row r="%d">`, rowNum)
+
+	for col, value := range values {
+		ref := cellRef(col, rowNum)
+		s := style(col)
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case float64:
+			fmt.Fprintf(sb, `<c r="%s" s="%d"><v>%s</v></c>`, ref, s, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			fmt.Fprintf(sb, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, s, xmlEscape(fmt.Sprint(v)))
+		}
+	}
+
+	sb.WriteString(`</row>`)
+}
+
+func cellStyle(colType ColumnType, forecast bool) int {
+	switch {
+	case colType == ColumnTypeCurrency && forecast:
+		return styleForecastCurrency
+	case colType == ColumnTypeCurrency:
+		return styleCurrency
+	case colType == ColumnTypeNumber && forecast:
+		return styleForecastNumber
+	case forecast:
+		return styleForecastText
+	default:
+		return styleDefault
+	}
+}
+
+// cellRef returns the A1 style reference of a zero based column and a one based row.
+func cellRef(col, row int) string {
+	name := ""
+
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+
+	return name + strconv.Itoa(row)
+}
+
+func currencyFormatCode(symbol string) string {
+	if symbol == "" {
+		return "#,##0.00"
+	}
+
+	quoted := `"` + strings.ReplaceAll(symbol, `"`, `""`) + `"`
+
+	return fmt.Sprintf("%s#,##0.00;-%s#,##0.00", quoted, quoted)
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+
+	_ = xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
@@ -232,6 +232,14 @@ func (s *ScheduledReportsService) validateSchedule(schedule *report.Schedule, em
 		return ErrInvalidScheduleBody
 	}
 
+	for _, format := range schedule.Attachments {
+		switch format {
+		case report.ScheduleAttachmentCSV, report.ScheduleAttachmentXLSX:
+		default:
+			return ErrInvalidAttachment
+		}
+	}
+
 	return nil
 }
 
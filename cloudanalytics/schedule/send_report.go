@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -16,8 +17,10 @@ import (
 	domainOrigin "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain/origin"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
 	domainReport "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/schedule/attachment"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/mailer"
+	events "github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
 type SendReportRequest struct {
@@ -25,6 +28,17 @@ type SendReportRequest struct {
 	CustomerID string `json:"customerId"`
 }
 
+// WebhookReportDelivery is the payload of the report schedule delivered webhook event
+type WebhookReportDelivery struct {
+	ReportID        string            `json:"reportId"`
+	ReportName      string            `json:"reportName"`
+	Frequency       string            `json:"frequency"`
+	Recipients      []string          `json:"recipients"`
+	ImageURL        string            `json:"imageUrl"`
+	AttachmentLinks []*attachmentLink `json:"attachmentLinks"`
+	TimeDelivered   int64             `json:"timeDelivered"`
+}
+
 type scheduledReportExecution struct {
 	State     executionState `firestore:"state"`
 	Timestamp time.Time      `firestore:"timestamp,serverTimestamp"`
@@ -166,16 +180,48 @@ func (s *ScheduledReportsService) SendReport(ctx context.Context, reportReq *Sen
 		return ErrImageEmpty
 	}
 
-	if err := s.sendReport(ctx, reportReq.CustomerID, reportReq.ReportID, report, imageURL); err != nil {
+	// The report is still sent with the chart image if the attachments fail
+	attachments, attachmentLinks, err := s.getReportAttachments(ctx, reportReq.CustomerID, reportID, report)
+	if err != nil {
+		l.Errorf("failed to create attachments for report %s with error: %s", reportID, err)
+	}
+
+	recipients, err := s.sendReport(ctx, reportReq.CustomerID, reportReq.ReportID, report, imageURL, attachments, attachmentLinks)
+	if err != nil {
 		return err
 	}
 
 	newState = executionStateSuccess
 
+	if len(recipients) > 0 {
+		if err := s.dispatchReportDelivery(ctx, reportID, report, recipients, imageURL, attachmentLinks); err != nil {
+			l.Warningf("unable to dispatch %s for event %s: %s", reportID, events.ReportScheduleDelivered, err)
+		}
+	}
+
 	return nil
 }
 
-func (s *ScheduledReportsService) sendReport(ctx context.Context, customerID, reportID string, r *domainReport.Report, imageURL string) error {
+// dispatchReportDelivery sends the report schedule delivered event to the webhook subscriptions of the report
+func (s *ScheduledReportsService) dispatchReportDelivery(ctx context.Context, reportID string, r *domainReport.Report, recipients []string, imageURL string, attachmentLinks []*attachmentLink) error {
+	return s.eventDispatcher.Dispatch(ctx,
+		WebhookReportDelivery{
+			ReportID:        reportID,
+			ReportName:      r.Name,
+			Frequency:       r.Schedule.Frequency,
+			Recipients:      recipients,
+			ImageURL:        imageURL,
+			AttachmentLinks: attachmentLinks,
+			TimeDelivered:   time.Now().UnixMilli(),
+		},
+		r.Customer,
+		reportID,
+		events.ReportScheduleDelivered,
+	)
+}
+
+// sendReport emails the report to the schedule recipients and returns the recipients it was sent to
+func (s *ScheduledReportsService) sendReport(ctx context.Context, customerID, reportID string, r *domainReport.Report, imageURL string, attachments []*attachment.File, attachmentLinks []*attachmentLink) ([]string, error) {
 	l := s.loggerProvider(ctx)
 
 	m := mail.NewV3Mail()
@@ -186,13 +232,24 @@ func (s *ScheduledReportsService) sendReport(ctx context.Context, customerID, re
 	m.SetTemplateID(mailer.Config.DynamicTemplates.ScheduledCloudReport)
 	m.AddCategories([]string{mailer.CatagoryReports, mailer.CatagoryScheduledReports}...)
 
+	for _, file := range attachments {
+		a := mail.NewAttachment()
+		a.SetContent(base64.StdEncoding.EncodeToString(file.Content))
+		a.SetType(file.ContentType)
+		a.SetFilename(file.Name)
+		a.SetDisposition("attachment")
+		m.AddAttachment(a)
+	}
+
 	personalizations := make([]*mail.Personalization, 0)
 
 	filteredEmails, err := s.validateRecipientsOrganization(ctx, r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	recipients := make([]string, 0, len(filteredEmails))
+
 	for _, recipient := range filteredEmails {
 		if !common.Production && !common.IsDoitDomain(recipient) {
 			l.Info("mail to <" + recipient + "> didn't send while in development")
@@ -207,14 +264,16 @@ func (s *ScheduledReportsService) sendReport(ctx context.Context, customerID, re
 		p.SetDynamicTemplateData("customer_id", customerID)
 		p.SetDynamicTemplateData("report_id", reportID)
 		p.SetDynamicTemplateData("image_url", imageURL)
+		p.SetDynamicTemplateData("attachment_links", attachmentLinks)
 		p.SetDynamicTemplateData("domain", common.Domain)
 		p.SetDynamicTemplateData("timestamp", time.Now().Format(time.RFC3339))
 		personalizations = append(personalizations, p)
+		recipients = append(recipients, recipient)
 	}
 
 	if len(personalizations) == 0 {
 		l.Info("personalizations slice is empty; will not send report.")
-		return nil
+		return nil, nil
 	}
 
 	m.AddPersonalizations(personalizations...)
@@ -224,12 +283,12 @@ func (s *ScheduledReportsService) sendReport(ctx context.Context, customerID, re
 	request.Body = mail.GetRequestBody(m)
 
 	if _, err := sendgrid.MakeRequestRetry(request); err != nil {
-		return err
+		return nil, err
 	}
 
 	l.Info("Mail sent successfully!")
 
-	return nil
+	return recipients, nil
 }
 
 // Validate that recipients are from report organization (in case of change after jobs scheduled)
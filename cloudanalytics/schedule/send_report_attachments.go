@@ -0,0 +1,246 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/storage"
+
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	queryDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	domainReport "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/schedule/attachment"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+)
+
+const (
+	// maxAttachmentsSize is the total size of the files attached to a single email,
+	// larger files are sent as download links. SendGrid allows up to 30MB per email.
+	maxAttachmentsSize = 10 * 1024 * 1024
+
+	attachmentLinkExpiration = 7 * 24 * time.Hour
+	defaultAttachmentName    = "report"
+)
+
+var attachmentNameInvalidChars = regexp.MustCompile(`[^\w\- ]+`)
+
+type attachmentLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// getReportAttachments renders the report data in the formats selected on the schedule.
+// Files that do not fit in the email are uploaded to GCS and returned as signed links.
+func (s *ScheduledReportsService) getReportAttachments(ctx context.Context, customerID, reportID string, r *domainReport.Report) ([]*attachment.File, []*attachmentLink, error) {
+	if len(r.Schedule.Attachments) == 0 {
+		return nil, nil, nil
+	}
+
+	email, _ := ctx.Value("email").(string)
+
+	qr, _, err := s.cloudAnalytics.GetQueryRequest(ctx, customerID, reportID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := s.cloudAnalytics.GetQueryResult(ctx, qr, customerID, email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if result.Error != nil {
+		return nil, nil, fmt.Errorf("report query failed with code %s: %s", result.Error.Code, result.Error.Message)
+	}
+
+	table := newAttachmentTable(qr, &result)
+	name := attachmentName(r.Name)
+
+	var (
+		files []*attachment.File
+		links []*attachmentLink
+		size  int
+	)
+
+	for _, format := range r.Schedule.Attachments {
+		file, err := table.Encode(name, attachment.Format(format))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if size+len(file.Content) <= maxAttachmentsSize {
+			size += len(file.Content)
+			files = append(files, file)
+
+			continue
+		}
+
+		url, err := s.uploadAttachment(ctx, customerID, reportID, file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		links = append(links, &attachmentLink{Name: file.Name, URL: url})
+	}
+
+	return files, links, nil
+}
+
+// uploadAttachment saves the file to the ephemeral bucket and returns a signed URL to download it.
+func (s *ScheduledReportsService) uploadAttachment(ctx context.Context, customerID, reportID string, file *attachment.File) (string, error) {
+	gcs := s.conn.CloudStorage(ctx)
+	bkt := gcs.Bucket(common.GetStaticEphemeralBucket())
+
+	gcsPath := fmt.Sprintf("cloud-analytics/scheduled-reports/%s/%s/%d/%s", customerID, reportID, time.Now().Unix(), file.Name)
+
+	wc := bkt.Object(gcsPath).NewWriter(ctx)
+	wc.ContentType = file.ContentType
+	wc.ContentDisposition = fmt.Sprintf("attachment; filename=%q", file.Name)
+
+	if _, err := wc.Write(file.Content); err != nil {
+		return "", err
+	}
+
+	if err := wc.Close(); err != nil {
+		return "", err
+	}
+
+	return bkt.SignedURL(gcsPath, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  "GET",
+		Expires: time.Now().Add(attachmentLinkExpiration),
+	})
+}
+
+// newAttachmentTable builds the attachment table from the query result. Each row has the
+// report rows and cols values followed by the metrics, forecast rows are appended at the end.
+func newAttachmentTable(qr *cloudanalytics.QueryRequest, result *cloudanalytics.QueryResult) *attachment.Table {
+	dimensions := make([]attachment.Column, 0, len(qr.Rows)+len(qr.Cols))
+
+	for _, x := range append(append([]*queryDomain.QueryRequestX{}, qr.Rows...), qr.Cols...) {
+		header := x.Label
+		if header == "" {
+			header = x.Key
+		}
+
+		dimensions = append(dimensions, attachment.Column{Header: header, Type: attachment.ColumnTypeText})
+	}
+
+	metrics := metricColumns(qr)
+	columns := append(dimensions, metrics...)
+
+	table := &attachment.Table{
+		Columns:        columns,
+		Rows:           make([]*attachment.Row, 0, len(result.Rows)+len(result.ForecastRows)),
+		CurrencySymbol: qr.Currency.Symbol(),
+	}
+
+	for _, resultRow := range result.Rows {
+		if len(resultRow) < len(columns) {
+			continue
+		}
+
+		values := make([]interface{}, len(columns))
+
+		for i := range dimensions {
+			values[i] = textValue(resultRow[i])
+		}
+
+		for i := range metrics {
+			values[len(dimensions)+i] = numericValue(resultRow[len(dimensions)+i])
+		}
+
+		table.Rows = append(table.Rows, &attachment.Row{Values: values})
+	}
+
+	// Forecast rows are aggregated over the report rows, they have a "Forecast" label
+	// followed by the cols values and the forecasted value of the report metric.
+	metricIndex := qr.GetMetricIndex()
+
+	for _, forecastRow := range result.ForecastRows {
+		if len(forecastRow) != len(qr.Cols)+2 || metricIndex >= len(metrics) {
+			continue
+		}
+
+		values := make([]interface{}, len(columns))
+
+		for i := range qr.Cols {
+			values[len(qr.Rows)+i] = textValue(forecastRow[i+1])
+		}
+
+		values[len(dimensions)+metricIndex] = numericValue(forecastRow[len(forecastRow)-1])
+
+		table.Rows = append(table.Rows, &attachment.Row{Values: values, Forecast: true})
+	}
+
+	return table
+}
+
+// metricColumns returns the metric columns in the order they appear in the query result rows.
+func metricColumns(qr *cloudanalytics.QueryRequest) []attachment.Column {
+	columns := []attachment.Column{
+		{Header: "Cost", Type: attachment.ColumnTypeCurrency},
+		{Header: "Usage", Type: attachment.ColumnTypeNumber},
+		{Header: "Savings", Type: attachment.ColumnTypeCurrency},
+	}
+
+	if qr.IsCSP {
+		columns = append(columns, attachment.Column{Header: "Margin", Type: attachment.ColumnTypeCurrency})
+	}
+
+	switch qr.Metric {
+	case domainReport.MetricCustom:
+		header := "Custom Metric"
+		if qr.CalculatedMetric != nil && qr.CalculatedMetric.Name != "" {
+			header = qr.CalculatedMetric.Name
+		}
+
+		columns = append(columns, attachment.Column{Header: header, Type: attachment.ColumnTypeNumber})
+	case domainReport.MetricExtended:
+		header := "Extended Metric"
+		if qr.ExtendedMetric != "" {
+			header = qr.ExtendedMetric
+		}
+
+		columns = append(columns, attachment.Column{Header: header, Type: attachment.ColumnTypeNumber})
+	}
+
+	if qr.Count != nil {
+		columns = append(columns, attachment.Column{Header: "Count", Type: attachment.ColumnTypeNumber})
+	}
+
+	return columns
+}
+
+func textValue(value bigquery.Value) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return fmt.Sprint(value)
+}
+
+func numericValue(value bigquery.Value) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	default:
+		return nil
+	}
+}
+
+func attachmentName(reportName string) string {
+	name := strings.TrimSpace(attachmentNameInvalidChars.ReplaceAllString(reportName, ""))
+	if name == "" {
+		return defaultAttachmentName
+	}
+
+	return name
+}
@@ -14,15 +14,17 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
 )
 
 type ScheduledReportsService struct {
-	loggerProvider logger.Provider
-	conn           *connection.Connection
-	cloudAnalytics cloudanalytics.CloudAnalytics
-	cloudScheduler *scheduler.CloudSchedulerClient
-	highCharts     highchartsIface.IHighcharts
-	reportDAL      reportIface.Reports
+	loggerProvider  logger.Provider
+	conn            *connection.Connection
+	cloudAnalytics  cloudanalytics.CloudAnalytics
+	cloudScheduler  *scheduler.CloudSchedulerClient
+	highCharts      highchartsIface.IHighcharts
+	reportDAL       reportIface.Reports
+	eventDispatcher dispatch.Dispatcher
 }
 
 var (
@@ -35,6 +37,7 @@ var (
 	ErrEmptyRecipientsList = errors.New("recipients list cannot be nil")
 	ErrInvalidFrequency    = errors.New("invalid frequency")
 	ErrInvalidScheduleBody = errors.New("invalid schedule body")
+	ErrInvalidAttachment   = errors.New("invalid schedule attachment format")
 )
 
 func NewScheduledReportsService(
@@ -64,5 +67,6 @@ func NewScheduledReportsService(
 		cloudScheduler,
 		highcharts,
 		reportDAL,
+		dispatch.NewEventDispatcher(loggerProvider, conn),
 	}, nil
 }
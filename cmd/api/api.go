@@ -27,6 +27,7 @@ import (
 	attributionGroups "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attribution-groups/handlers"
 	attributions "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/attributions/handlers"
 	budgetsHandlers "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/handlers"
+	dashboardsHandlers "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboards/handlers"
 	dashboardSubscription "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/dashboardsubscription/handlers"
 	datahubHandlers "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/datahub/handlers"
 	billingDataExportHandlers "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/export/handlers"
@@ -37,6 +38,7 @@ import (
 	reportsHandlers "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/handlers"
 	reportTemplatesHandlers "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/templatelibrary/handlers"
 	"github.com/doitintl/hello/scheduled-tasks/cmd/api/handlers"
+	collectionsHandlers "github.com/doitintl/hello/scheduled-tasks/collections/handlers"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	contractHandlers "github.com/doitintl/hello/scheduled-tasks/contract/handlers"
 	courierHandlers "github.com/doitintl/hello/scheduled-tasks/courier/handler"
@@ -63,6 +65,7 @@ import (
 	microsoftLicensesHandlers "github.com/doitintl/hello/scheduled-tasks/microsoft/license/handlers"
 	perksHandlers "github.com/doitintl/hello/scheduled-tasks/perks/handlers"
 	presentationHandlers "github.com/doitintl/hello/scheduled-tasks/presentations/handlers"
+	receivablesHandlers "github.com/doitintl/hello/scheduled-tasks/receivables/handlers"
 	stripe "github.com/doitintl/hello/scheduled-tasks/stripe/handlers"
 	supportHandlers "github.com/doitintl/hello/scheduled-tasks/support/handlers"
 	webhookSubscriptions "github.com/doitintl/hello/scheduled-tasks/zapier/handlers"
@@ -70,6 +73,12 @@ import (
 	tiersService "github.com/doitintl/tiers/service"
 )
 
+// Mixpanel features of the external API that are not defined by the mixpanel package
+const (
+	mixpanelFeatureMetrics    mixpanel.Feature = "metrics"
+	mixpanelFeatureDashboards mixpanel.Feature = "dashboards"
+)
+
 // API constructs an api with the needed functionality.
 type API struct {
 	shutdown chan os.Signal
@@ -173,6 +182,7 @@ func (a *API) Build() http.Handler {
 	analyticsMicrosoftAzure := analyticsAzure.NewAnalyticsMicrosoftAzure(loggerProvider, a.conn)
 	awsOpsPage := handlers.NewHandler(loggerProvider, a.conn)
 	metricsHandler := metricsHandlers.NewMetric(loggerProvider, a.conn)
+	dashboardsHandler := dashboardsHandlers.NewDashboards(backgroundContext, loggerProvider, a.conn)
 	billingExplainerHandler := billingExplainerHandlers.NewBillingExplainerHandler(loggerProvider, a.conn)
 	billingDataExportHandler := billingDataExportHandlers.NewBillingExportHandler(loggerProvider, a.conn)
 	bqLensDiscoveryHandler := bqLensDiscoveryHandlers.NewTableDiscovery(loggerProvider, a.conn)
@@ -188,6 +198,8 @@ func (a *API) Build() http.Handler {
 	tiers := handlers.NewTiersHandler(loggerProvider, a.conn)
 	tierService := tiersService.NewTiersService(a.conn.Firestore)
 	contractHandler := contractHandlers.NewContractHandler(loggerProvider, a.conn)
+	receivablesHandler := receivablesHandlers.NewReceivables(loggerProvider, a.conn)
+	collectionsHandler := collectionsHandlers.NewCollections(loggerProvider, a.conn)
 	publicdashboardsHandler := publicDashboardHandlers.NewDashboard(loggerProvider, a.conn)
 	ples := plesHandler.NewPLES(loggerProvider, a.conn)
 
@@ -233,6 +245,13 @@ func (a *API) Build() http.Handler {
 		tasksGroup.Get("/segment/all-customers", customerHandler.UpdateAllCustomersSegment)
 		tasksGroup.Post("/segment/:customerID", customerHandler.UpdateCustomerSegment)
 
+		zapierTasksGroup := tasksGroup.NewSubgroup("/zapier")
+		{
+			zapierTasksGroup.Post("/deliveries/:deliveryID/retry", webhookSubscriptionHandlers.RetryDelivery)
+			zapierTasksGroup.Get("/anomalies", apiV1.EnqueueAnomaliesDispatch)
+			zapierTasksGroup.Post("/anomalies/:customerID/:anomalyID", apiV1.DispatchAnomaly)
+		}
+
 		salesforceGroup := tasksGroup.NewSubgroup("/salesforce")
 		{
 			salesforceGroup.Get("/sync", salesforce.SyncHandler)
@@ -330,6 +349,8 @@ func (a *API) Build() http.Handler {
 				{
 					billing.Delete("", gcpStandaloneBillingHandler.RemoveAll)
 					billing.Post("/onboarding", gcpStandaloneBillingHandler.Onboaring)
+					billing.Get("/onboarding/:billingAccountId", gcpStandaloneBillingHandler.GetOnboardingStatus)
+					billing.Post("/onboarding/:billingAccountId/resume", gcpStandaloneBillingHandler.ResumeOnboarding)
 					billing.Post("/offboarding", gcpStandaloneBillingHandler.RemoveBilling)
 					billing.Post("/alternative", gcpStandaloneBillingHandler.RunAlternativeManager)
 					billing.Post("/sanity", gcpStandaloneBillingHandler.RunSanity)
@@ -579,11 +600,13 @@ func (a *API) Build() http.Handler {
 
 		invoicesGroup := tasksGroup.NewSubgroup("/invoices")
 		{
+			invoiceNotifications := handlers.NewInvoiceNotifications(loggerProvider, a.conn)
+
 			invoicesGroup.Get("", handlers.InvoicesMainHandler)
 			invoicesGroup.Post("", handlers.InvoicesCustomerWorker)
 			invoicesGroup.Get("/notifications", handlers.NotificationsHandler)
-			invoicesGroup.Post("/notifications", handlers.NotificationsWorker)
-			invoicesGroup.Get("/notice-to-remedy", handlers.NoticeToRemedy)
+			invoicesGroup.Post("/notifications", invoiceNotifications.NotificationsWorker)
+			invoicesGroup.Get("/notice-to-remedy", collectionsHandler.RunHandler)
 		}
 
 		receiptsGroup := tasksGroup.NewSubgroup("/receipts")
@@ -592,6 +615,7 @@ func (a *API) Build() http.Handler {
 			receiptsGroup.Post("", priorityHandler.SyncCustomerReceipts)
 			receiptsGroup.Get("/aggregate", handlers.Aggregate)
 			receiptsGroup.Get("/account-receivables", handlers.AccountReceiveables)
+			receiptsGroup.Get("/receivables-analytics", receivablesHandler.SnapshotHandler)
 		}
 
 		firestoreGroup := tasksGroup.NewSubgroup("/firestore")
@@ -667,6 +691,7 @@ func (a *API) Build() http.Handler {
 			dashboardGroup.Get("/ramp-plan", rampPlan.UpdateAllRampPlans)
 			dashboardGroup.Post("/update-ramp-plan", rampPlan.UpdateRampPlanByID)
 			dashboardGroup.Post("/create-ramp-plans", rampPlan.CreateRampPlans)
+			dashboardGroup.Get("/ramp-plan-shortfall", rampPlan.NotifyRampPlanShortfalls)
 
 			renewalsGroup := dashboardGroup.NewSubgroup("/renewals")
 			{
@@ -770,6 +795,7 @@ func (a *API) Build() http.Handler {
 
 			bqlensTasksGroup.Get("/optimizer-scheduler", bqLensOptimizerHandler.AllCustomersOptimizer)
 			bqlensTasksGroup.Post("/optimizer/:customerID", bqLensOptimizerHandler.SingleCustomerOptimizer)
+			bqlensTasksGroup.Post("/optimizer/:customerID/editions-simulation", bqLensOptimizerHandler.SimulateEditionsAutoscaling)
 
 			bqlensTasksGroup.Post("/onboarding", bqLensOnboardHandler.Onboard)
 
@@ -993,6 +1019,7 @@ func (a *API) Build() http.Handler {
 			invoicingGroup.Post("/issue-customer-invoices", invoicing.IssueSingleCustomerInvoiceHandler)
 			invoicingGroup.Post("/recalculate-customer", invoicing.RecalculateSingleCustomerHandler)
 			invoicingGroup.Post("/cancel-issued-invoices", invoicing.CancelIssuedInvoices)
+			invoicingGroup.Post("/invoice-diff", invoicing.InvoiceDiffHandler)
 		}
 
 		mixpanelGroup := apiGroup.NewSubgroup("/mixpanel", mid.AuthDoitEmployee())
@@ -1020,11 +1047,13 @@ func (a *API) Build() http.Handler {
 		flexsaveAdminRoleGroup := apiGroup.NewSubgroup("/flexsave", mid.AuthMPAOrFlexsaveAdmin(a.conn))
 		{
 			flexsaveAdminRoleGroup.Put("/payers/:payerId/ops-update", awsOpsPage.ProcessOpsUpdates)
+			flexsaveAdminRoleGroup.Get("/payers/:payerId/transitions", awsOpsPage.GetPayerTransitions)
 		}
 
 		flexsaveDoitEmployeeGroup := apiGroup.NewSubgroup("/flexsave", mid.AuthDoitEmployee())
 		{
 			flexsaveDoitEmployeeGroup.Post("/payers/mpaActivated/:accountNumber", AWSResoldCache.MPAActivatedHandler)
+			flexsaveDoitEmployeeGroup.Post("/savings-plans/simulate/:customerID", AWSResoldCache.SimulateSavingsPlans)
 		}
 
 		flexsaveGcpGroup := apiGroup.NewSubgroup("/flexsave-gcp", mid.AuthDoitEmployee())
@@ -1077,6 +1106,8 @@ func (a *API) Build() http.Handler {
 			saasConsoleGCPGroup.Get("/init-onboarding/:customerID", gcpSaaSConsoleHandler.InitOnboarding)
 			saasConsoleGCPGroup.Post("/contract-agreed", gcpSaaSConsoleHandler.AddContract)
 			saasConsoleGCPGroup.Post("/activate", gcpSaaSConsoleHandler.Activate)
+			saasConsoleGCPGroup.Get("/activate/:customerID/:billingAccountID", gcpSaaSConsoleHandler.GetActivationStatus)
+			saasConsoleGCPGroup.Post("/activate/:customerID/:billingAccountID/resume", gcpSaaSConsoleHandler.ResumeActivation)
 		}
 
 		saasConsoleAWSGroup := apiGroup.NewSubgroup("/saas-console-aws")
@@ -1133,6 +1164,12 @@ func (a *API) Build() http.Handler {
 
 			customerGroup.Get("/dashboards", handlers.GetCustomerDashboards)
 			customerGroup.Get("/invoices", handlers.CustomerHandler, mid.AuthDoitEmployee())
+			customerGroup.Get("/invoices/:invoiceID/einvoice", handlers.EInvoiceHandler, mid.AssertUserHasPermissions([]string{string(common.PermissionInvoices)}, a.conn))
+			customerGroup.Get("/collections", collectionsHandler.GetCaseHandler, mid.AuthDoitEmployee())
+			customerGroup.Post("/collections/dispute", collectionsHandler.OpenDisputeHandler, mid.AuthDoitEmployee())
+			customerGroup.Delete("/collections/dispute", collectionsHandler.ResolveDisputeHandler, mid.AuthDoitEmployee())
+			customerGroup.Post("/collections/promise-to-pay", collectionsHandler.SetPromiseToPayHandler, mid.AuthDoitEmployee())
+			customerGroup.Delete("/collections/promise-to-pay", collectionsHandler.RemovePromiseToPayHandler, mid.AuthDoitEmployee())
 			customerGroup.Get("/refresh/g-suite", handlers.SubscriptionsListHandler, mid.AuthDoitEmployee())
 
 			usersGroup := customerGroup.NewSubgroup("/users")
@@ -1211,6 +1248,7 @@ func (a *API) Build() http.Handler {
 			{
 				datahubGroup.Post("/dataset", datahubHandler.CreateDataset)
 				datahubGroup.Post("/events/raw", datahubHandler.AddRawEvents)
+				datahubGroup.Post("/events/stream", datahubHandler.StreamEvents)
 				datahubGroup.Get("/events/datasets", datahubHandler.GetCustomerDatasets)
 				datahubGroup.Get("/events/datasets/:datasetName/batches", datahubHandler.GetCustomerDatasetBatches)
 				datahubGroup.Delete("/events/datasets", datahubHandler.DeleteCustomerDatasets)
@@ -1347,6 +1385,9 @@ func (a *API) Build() http.Handler {
 				entityGroup.Get("/paymentMethods", stripe.GetPaymentMethodsHandler)
 				entityGroup.Patch("/paymentMethods", stripe.PatchPaymentMethodHandler)
 				entityGroup.Delete("/paymentMethods", stripe.DetachPaymentMethodHandler)
+				entityGroup.Get("/dunning", stripe.GetDunningHandler)
+				entityGroup.Put("/dunning", stripe.UpdateDunningPolicyHandler, mid.AuthDoitEmployee())
+				entityGroup.Delete("/dunning", stripe.ResetDunningPolicyHandler, mid.AuthDoitEmployee())
 
 				// Invoices
 				entityGroup.Post("/invoices/:invoiceID", stripe.PayInvoiceHandler)
@@ -1430,6 +1471,7 @@ func (a *API) Build() http.Handler {
 			customerGroup.Patch("/customerTier", supportHandlers.ChangeCustomerTier)
 
 			customerGroup.Post("/billing-export", billingDataExportHandler.HandleCustomerBillingExport)
+			customerGroup.Post("/billing-export/revoke", billingDataExportHandler.HandleRevokeCustomerBillingExport)
 		}
 
 		marketplaceGroup := apiGroup.NewSubgroup("/marketplace")
@@ -1470,6 +1512,18 @@ func (a *API) Build() http.Handler {
 			algoliaGroup.Get("/config", algolia.GetAlgoliaConfig)
 		}
 
+		receivablesGroup := apiGroup.NewSubgroup("/receivables", mid.AuthDoitEmployee())
+		{
+			receivablesGroup.Get("/aging", receivablesHandler.GetAgingHandler)
+			receivablesGroup.Get("/kpis", receivablesHandler.GetKPITrendHandler)
+		}
+
+		collectionsGroup := apiGroup.NewSubgroup("/collections", mid.AuthDoitEmployee())
+		{
+			collectionsGroup.Get("/workflow", collectionsHandler.GetWorkflowHandler)
+			collectionsGroup.Put("/workflow", collectionsHandler.UpdateWorkflowHandler)
+		}
+
 		tiersGroup := apiGroup.NewSubgroup("/tiers", mid.AuthDoitEmployee())
 		{
 			tiersGroup.Patch("/:id", tiers.UpdateTier, mid.AuthDoitEmployeeRole(a.conn, permissionsDomain.DoitRoleOwners))
@@ -1576,6 +1630,24 @@ func (a *API) Build() http.Handler {
 			alertsV1Group.Post("", analyticsAlerts.ExternalAPICreateAlert, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodCreate, mixpanel.FeatureAlerts))
 			alertsV1Group.Patch("/:id", analyticsAlerts.ExternalAPIUpdateAlert, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodUpdate, mixpanel.FeatureAlerts))
 		}
+
+		metricsV1Group := analyticsV1Group.NewSubgroup("/metrics")
+		{
+			metricsV1Group.Get("", metricsHandler.ExternalAPIListMetrics, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodList, mixpanelFeatureMetrics))
+			metricsV1Group.Get("/:id", metricsHandler.ExternalAPIGetMetric, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodGetConfig, mixpanelFeatureMetrics))
+			metricsV1Group.Delete("/:id", metricsHandler.ExternalAPIDeleteMetric, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodDelete, mixpanelFeatureMetrics))
+			metricsV1Group.Post("", metricsHandler.ExternalAPICreateMetric, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodCreate, mixpanelFeatureMetrics))
+			metricsV1Group.Patch("/:id", metricsHandler.ExternalAPIUpdateMetric, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodUpdate, mixpanelFeatureMetrics))
+		}
+
+		dashboardsV1Group := analyticsV1Group.NewSubgroup("/dashboards")
+		{
+			dashboardsV1Group.Get("", dashboardsHandler.ExternalAPIListDashboards, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodList, mixpanelFeatureDashboards))
+			dashboardsV1Group.Get("/:id", dashboardsHandler.ExternalAPIGetDashboard, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodGetConfig, mixpanelFeatureDashboards))
+			dashboardsV1Group.Get("/:id/widgets/:reportId", dashboardsHandler.ExternalAPIGetWidgetData, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodGetResults, mixpanelFeatureDashboards))
+			dashboardsV1Group.Get("/:id/export", dashboardsHandler.ExternalAPIExportDashboard, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodGetConfig, mixpanelFeatureDashboards))
+			dashboardsV1Group.Post("/import", dashboardsHandler.ExternalAPIImportDashboard, mid.ExternalAPIMixpanel(mixpanelHandler, mixpanel.MethodCreate, mixpanelFeatureDashboards))
+		}
 	}
 
 	supportV1Group := web.NewGroup(app, "/support/v1/metadata", mid.ExternalAPIAuthMiddleware(), hasEntitlement(pkg.TiersFeatureKeyDoiTAPIAccess), mid.AssertUserHasPermissions([]string{string(common.PermissionSupportRequester)}, a.conn))
@@ -1594,11 +1666,17 @@ func (a *API) Build() http.Handler {
 	{
 		zapierWebhookGroup.Post("/subscribe", webhookSubscriptionHandlers.Create)
 		zapierWebhookGroup.Delete("/unsubscribe", webhookSubscriptionHandlers.Delete)
+		zapierWebhookGroup.Get("/deliveries", webhookSubscriptionHandlers.ListDeliveries)
+		zapierWebhookGroup.Post("/deliveries/:deliveryID/redeliver", webhookSubscriptionHandlers.Redeliver)
 
 		zapierMocks := zapierWebhookGroup.NewSubgroup("/restHookMocks")
 		{
 			zapierMocks.Get("/alertNotification", webhookSubscriptionHandlers.GetAlertsMock)
 			zapierMocks.Get("/budgetThreshold", webhookSubscriptionHandlers.GetBudgetsMock)
+			zapierMocks.Get("/budgetForecastedDate", webhookSubscriptionHandlers.GetBudgetForecastsMock)
+			zapierMocks.Get("/costAnomaly", webhookSubscriptionHandlers.GetAnomaliesMock)
+			zapierMocks.Get("/invoiceIssued", webhookSubscriptionHandlers.GetInvoicesMock)
+			zapierMocks.Get("/scheduledReport", webhookSubscriptionHandlers.GetReportDeliveriesMock)
 		}
 	}
 
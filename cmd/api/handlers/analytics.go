@@ -42,6 +42,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/googlecloud"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/msteams"
 	"github.com/doitintl/hello/scheduled-tasks/slack/service/slack"
 	slackIface "github.com/doitintl/hello/scheduled-tasks/slack/service/slack/iface"
 	"github.com/doitintl/hello/scheduled-tasks/times"
@@ -74,6 +75,7 @@ type CloudAnalytics struct {
 	reportStatsService           reportStatsIface.ReportStatsService
 	reportTierService            iface.ReportTierService
 	attributionTierService       attributionTierServiceIface.AttributionTierService
+	msTeams                      msteams.Notifier
 }
 
 // NewCloudAnalytics init new CloudAnalytics handlers
@@ -179,6 +181,7 @@ func NewCloudAnalytics(loggerProvider logger.Provider, conn *connection.Connecti
 		reportStatsService,
 		reportTierService,
 		attributionTierService,
+		msteams.NewWebhookNotifier(),
 	}
 }
 
@@ -234,28 +237,27 @@ func (h *CloudAnalytics) RefreshAllBudgetsHandler(ctx *gin.Context) error {
 }
 
 func (h *CloudAnalytics) TriggerBudgetsAlertsHandler(ctx *gin.Context) error {
-	slackAlerts, err := h.budgets.TriggerBudgetsAlerts(ctx) //	send emails & return slack payloads
+	slackAlerts, msTeamsAlerts, err := h.budgets.TriggerBudgetsAlerts(ctx) //	send emails & return slack and teams payloads
 	if err != nil {
 		return web.NewRequestError(err, http.StatusInternalServerError)
 	}
 
-	parsedPayload := h.attachImagesToSlackAlerts(ctx, slackAlerts)
+	forecastImages := make(map[string]string)
+
+	parsedPayload := h.attachImagesToSlackAlerts(ctx, slackAlerts, forecastImages)
 	h.slack.PostMessages(ctx, parsedPayload)
 
+	h.postMSTeamsAlerts(ctx, msTeamsAlerts, forecastImages)
+
 	return web.Respond(ctx, nil, http.StatusOK)
 }
 
 // TODO remove after highcharts directory is refactored (CMP-2399)
-func (h *CloudAnalytics) attachImagesToSlackAlerts(ctx *gin.Context, alerts map[*budgetsSvc.BudgetSlackAlert][]common.SlackChannel) map[*slackgo.MsgOption][]common.SlackChannel {
-	l := h.loggerProvider(ctx)
-
+func (h *CloudAnalytics) attachImagesToSlackAlerts(ctx *gin.Context, alerts map[*budgetsSvc.BudgetSlackAlert][]common.SlackChannel, forecastImages map[string]string) map[*slackgo.MsgOption][]common.SlackChannel {
 	slackAlerts := make(map[*slackgo.MsgOption][]common.SlackChannel)
 
 	for budgetSlackAlert, channels := range alerts {
-		_, imageURLForecasted, err := h.highcharts.GetBudgetImages(ctx, budgetSlackAlert.BudgetID, channels[0].CustomerID, &domainHighCharts.SlackUnfurlFontSettings)
-		if err != nil {
-			l.Errorf("parseAlertsToSlack() error generating highcharts image for budget %s - reason: %s", budgetSlackAlert.BudgetID, err.Error())
-		}
+		imageURLForecasted := h.getBudgetForecastImage(ctx, budgetSlackAlert.BudgetID, channels[0].CustomerID, forecastImages)
 
 		msgOption := h.budgets.GetSlackFinalBlocks(ctx, imageURLForecasted, budgetSlackAlert.SlackBlocks)
 
@@ -265,6 +267,38 @@ func (h *CloudAnalytics) attachImagesToSlackAlerts(ctx *gin.Context, alerts map[
 	return slackAlerts
 }
 
+func (h *CloudAnalytics) postMSTeamsAlerts(ctx *gin.Context, alerts []*budgetsSvc.BudgetMSTeamsAlert, forecastImages map[string]string) {
+	l := h.loggerProvider(ctx)
+
+	for _, alert := range alerts {
+		imageURLForecasted := h.getBudgetForecastImage(ctx, alert.BudgetID, alert.CustomerID, forecastImages)
+		card := h.budgets.AddMSTeamsForecastImage(alert.Card, imageURLForecasted)
+
+		for _, channel := range alert.Channels {
+			if err := h.msTeams.Post(ctx, channel.WebhookURL, card); err != nil {
+				l.Errorf("failed to post teams alert for budget %s to channel %s; %s", alert.BudgetID, channel.Name, err)
+			}
+		}
+	}
+}
+
+// getBudgetForecastImage returns the forecast chart image of a budget, the image is generated once per budget
+// and shared between the slack and teams alerts
+func (h *CloudAnalytics) getBudgetForecastImage(ctx *gin.Context, budgetID, customerID string, forecastImages map[string]string) string {
+	if imageURLForecasted, ok := forecastImages[budgetID]; ok {
+		return imageURLForecasted
+	}
+
+	_, imageURLForecasted, err := h.highcharts.GetBudgetImages(ctx, budgetID, customerID, &domainHighCharts.SlackUnfurlFontSettings)
+	if err != nil {
+		h.loggerProvider(ctx).Errorf("getBudgetForecastImage() error generating highcharts image for budget %s - reason: %s", budgetID, err.Error())
+	}
+
+	forecastImages[budgetID] = imageURLForecasted
+
+	return imageURLForecasted
+}
+
 func (h *CloudAnalytics) TriggerForecastedDateAlertsHandler(ctx *gin.Context) error {
 	if err := h.budgets.TriggerForecastedDateAlerts(ctx); err != nil {
 		return web.NewRequestError(err, http.StatusInternalServerError)
@@ -844,6 +878,10 @@ func (h *CloudAnalytics) Query(ctx *gin.Context) error {
 		return web.NewRequestError(err, http.StatusBadRequest)
 	}
 
+	if err := qr.ForecastEngine.Validate(); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
 	if qr.Type == cloudanalytics.QueryRequestTypeAttribution {
 		accessDeniedErr, err := h.attributionTierService.CheckAccessToQueryRequest(
 			ctx,
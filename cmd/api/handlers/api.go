@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
 	tier "github.com/doitintl/tiers/service"
 )
 
@@ -34,6 +36,7 @@ type APIv1 struct {
 	*connection.Connection
 	service          *api.APIV1Service
 	reportAPIService iface.ReportAPIService
+	eventDispatcher  dispatch.Dispatcher
 }
 
 func NewAPIv1(ctx context.Context, loggerProvider logger.Provider, conn *connection.Connection) *APIv1 {
@@ -84,6 +87,7 @@ func NewAPIv1(ctx context.Context, loggerProvider logger.Provider, conn *connect
 		conn,
 		service,
 		reportAPIService,
+		dispatch.NewEventDispatcher(loggerProvider, conn),
 	}
 }
 
@@ -129,6 +133,28 @@ func (h *APIv1) GetAnomaly(ctx *gin.Context) error {
 	return nil
 }
 
+// DispatchAnomaly is a cloud task handler that sends a detected anomaly to the webhook subscriptions of the customer
+func (h *APIv1) DispatchAnomaly(ctx *gin.Context) error {
+	if err := api.DispatchAnomaly(ctx, h.Connection, h.eventDispatcher); err != nil {
+		if errors.Is(err, api.ErrAnomalyNotFound) {
+			return web.NewRequestError(err, http.StatusNotFound)
+		}
+
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+// EnqueueAnomaliesDispatch is a scheduled handler that creates the dispatch tasks of the anomalies recorded in the previous hour
+func (h *APIv1) EnqueueAnomaliesDispatch(ctx *gin.Context) error {
+	if err := api.EnqueueAnomaliesDispatch(ctx, h.Connection, time.Now()); err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
 func (h *APIv1) ListReports(ctx *gin.Context) error {
 	h.reportAPIService.ListReports(ctx, h.Connection)
 
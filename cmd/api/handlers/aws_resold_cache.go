@@ -16,6 +16,7 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/cache/manage"
 
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/cache/savingsplans"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/simulator"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
@@ -32,6 +33,7 @@ type ResoldAWSCache struct {
 	manageFlexsave      manage.Service
 	savingsPlansService savingsplans.Service
 	doitemployees       doitemployees.ServiceInterface
+	simulatorService    *simulator.Service
 }
 
 func NewAWSResoldCache(log logger.Provider, conn *connection.Connection) *ResoldAWSCache {
@@ -48,6 +50,7 @@ func NewAWSResoldCache(log logger.Provider, conn *connection.Connection) *Resold
 		manageFlexsaveService,
 		*savingsPlansService,
 		doitemployees.NewService(conn),
+		simulator.NewService(log, conn),
 	}
 }
 
@@ -139,6 +142,29 @@ func (h *ResoldAWSCache) MPAActivatedHandler(ctx *gin.Context) error {
 	return web.Respond(ctx, nil, http.StatusOK)
 }
 
+func (h *ResoldAWSCache) SimulateSavingsPlans(ctx *gin.Context) error {
+	customerID := ctx.Param("customerID")
+
+	var req simulator.SimulationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	result, err := h.simulatorService.Simulate(ctx, customerID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, simulator.ErrInvalidRequest):
+			return web.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, simulator.ErrNoUsageHistory):
+			return web.NewRequestError(err, http.StatusNotFound)
+		default:
+			return handleServiceError(err)
+		}
+	}
+
+	return web.Respond(ctx, result, http.StatusOK)
+}
+
 func (h *ResoldAWSCache) CustomerSavingsPlansCache(ctx *gin.Context) error {
 	customerID := ctx.Param("customerID")
 
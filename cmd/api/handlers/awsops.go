@@ -8,9 +8,11 @@ import (
 	"github.com/doitintl/errors"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager"
 	computeManager "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/compute"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	rdsManager "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/rds"
 	sagemakerManager "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/sagemaker"
 	payermanagerutils "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
@@ -19,6 +21,7 @@ import (
 
 type Handler interface {
 	ProcessOpsUpdates(ctx *gin.Context) error
+	GetPayerTransitions(ctx *gin.Context) error
 }
 
 type handler struct {
@@ -97,6 +100,15 @@ func (h *handler) ProcessOpsUpdates(ctx *gin.Context) error {
 		ctx.Set(utils.StatusChangeReasonContextKey, *body.StatusChangeReason)
 	}
 
+	if body.DryRun {
+		transitions, err := h.dryRunTransitions(ctx, payer, compute, rds, sagemaker)
+		if err != nil {
+			return web.NewRequestError(err, http.StatusInternalServerError)
+		}
+
+		return web.Respond(ctx, transitions, http.StatusOK)
+	}
+
 	err = h.payerManager.UpdateNonStatusPayerConfigFields(ctx, payer, body)
 	if err != nil {
 		return web.NewRequestError(err, http.StatusInternalServerError)
@@ -119,3 +131,34 @@ func (h *handler) ProcessOpsUpdates(ctx *gin.Context) error {
 
 	return web.Respond(ctx, nil, http.StatusOK)
 }
+
+// dryRunTransitions returns the changes the status transitions of the payer would apply, without applying them
+func (h *handler) dryRunTransitions(ctx *gin.Context, payer types.PayerConfig, compute, rds, sagemaker string) ([]*history.Transition, error) {
+	computeTransition, err := h.computeStateService.DryRunPayerStatusTransition(ctx, payer.AccountID, payer.CustomerID, payer.Status, compute)
+	if err != nil {
+		return nil, err
+	}
+
+	rdsTransition, err := h.rdsStateService.DryRunPayerStatusTransition(ctx, payer.AccountID, payer.CustomerID, payer.RDSStatus, rds)
+	if err != nil {
+		return nil, err
+	}
+
+	sagemakerTransition, err := h.sagemakerStateService.DryRunPayerStatusTransition(ctx, payer.AccountID, payer.CustomerID, payer.SageMakerStatus, sagemaker)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*history.Transition{computeTransition, rdsTransition, sagemakerTransition}, nil
+}
+
+func (h *handler) GetPayerTransitions(ctx *gin.Context) error {
+	payerID := ctx.Param("payerId")
+
+	transitions, err := h.payerManager.ListPayerTransitions(ctx, payerID)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, transitions, http.StatusOK)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager"
 	state "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/compute/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	mockpayermanager "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/mocks"
 	mockrdsstate "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/rds/mocks"
 	mocksagemakerstate "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/sagemaker/mocks"
@@ -35,6 +36,7 @@ func Test_handler_ProcessComputeFormEntry(t *testing.T) {
 
 		body                       = `{"managed":"auto","status":"pending","rdsStatus":"pending","sagemakerStatus":"pending"}`
 		bodyMissingField           = `{"managed":"auto","status":"","rdsStatus":"pending","sagemakerStatus":"pending"}`
+		bodyDryRun                 = `{"managed":"auto","status":"pending","rdsStatus":"pending","sagemakerStatus":"pending","dryRun":true}`
 		bodyWithStatusChangeReason = `{"managed":"auto","status":"pending","statusChangeReason":"just because","rdsStatus":"pending","sagemakerStatus":"pending"}`
 
 		someErr = errors.New("something went wrong")
@@ -95,6 +97,31 @@ func Test_handler_ProcessComputeFormEntry(t *testing.T) {
 				assert.Equal(t, nil, ctx.Value(utils.StatusChangeReasonContextKey))
 			},
 		},
+		{
+			name: "dry run",
+			on: func(f *fields, ctx *gin.Context) {
+				config := types.PayerConfig{
+					CustomerID:      customerID,
+					AccountID:       payerID,
+					Status:          statusPending,
+					Managed:         managedType,
+					RDSStatus:       statusDisabled,
+					SageMakerStatus: statusDisabled,
+				}
+
+				f.payerManager.On("GetPayer", ctx, payerID).Return(config, nil)
+
+				f.payerStateController.On("DryRunPayerStatusTransition", ctx, payerID, customerID, statusPending, statusPending).Return(&history.Transition{}, nil)
+
+				f.rdsState.On("DryRunPayerStatusTransition", ctx, payerID, customerID, statusDisabled, statusPending).Return(&history.Transition{}, nil)
+
+				f.sagemakerState.On("DryRunPayerStatusTransition", ctx, payerID, customerID, statusDisabled, statusPending).Return(&history.Transition{}, nil)
+			},
+			args: args{
+				body: strings.NewReader(bodyDryRun),
+			},
+			wantStatusCode: http.StatusOK,
+		},
 		{
 			name: "with status change reason",
 			on: func(f *fields, ctx *gin.Context) {
@@ -331,6 +358,54 @@ func Test_handler_ProcessComputeFormEntry(t *testing.T) {
 	}
 }
 
+func Test_handler_GetPayerTransitions(t *testing.T) {
+	payerID := "1233455"
+
+	tests := []struct {
+		name           string
+		err            error
+		wantStatusCode int
+	}{
+		{
+			name:           "happy path",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "failed to list transitions",
+			err:            errors.New("something went wrong"),
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/payers/%s/transitions", payerID), nil)
+			ctx.Params = []gin.Param{{Key: "payerId", Value: payerID}}
+
+			payerManager := mockpayermanager.Service{}
+			payerManager.On("ListPayerTransitions", ctx, payerID).Return([]*history.Transition{{AccountID: payerID}}, tt.err)
+
+			h := &handler{
+				payerManager: &payerManager,
+			}
+
+			err := h.GetPayerTransitions(ctx)
+			if err == nil {
+				assert.Equal(t, tt.wantStatusCode, w.Code)
+			} else {
+				var reqErr *web.Error
+				if errors.As(err, &reqErr) {
+					assert.Equal(t, tt.wantStatusCode, reqErr.Status)
+				} else {
+					t.Fatalf("Unexpected error type: %v", err)
+				}
+			}
+		})
+	}
+}
+
 func Test_validateTransitions(t *testing.T) {
 	type args struct {
 		compute   string
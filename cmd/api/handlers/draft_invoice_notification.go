@@ -16,6 +16,8 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/invoicing"
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/invoicediff"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	ncDomain "github.com/doitintl/notificationcenter/domain"
 	nc "github.com/doitintl/notificationcenter/pkg"
@@ -120,6 +122,13 @@ func SendDraftInvoicesCreatedNotification(ctx context.Context, req DraftInvoices
 		"entity": entity.Name,
 	}
 
+	// the changes since the previous month are informative, the notification is sent without them on failure
+	if changes, err := previousMonthInvoiceChanges(ctx, fs, req); err != nil {
+		l.Warningf("failed to diff draft invoices with the previous month for request: %v, error: %v", req, err)
+	} else {
+		notification.Data["changes"] = changes
+	}
+
 	c, err := nc.NewClient(ctx, common.ProjectID)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrCanRetry, err)
@@ -152,3 +161,36 @@ func customerURL(customerID string) string {
 
 	return fmt.Sprintf("https://%s.doit.com/customers/%s", sub, customerID)
 }
+
+// previousMonthInvoiceChanges summarizes the line level changes of the draft invoices of the entity
+// compared to the invoices issued to it in the previous month
+func previousMonthInvoiceChanges(ctx context.Context, fs *firestore.Client, req DraftInvoicesNotificationRequest) (map[string]interface{}, error) {
+	invoiceMonth, err := time.Parse(invoicing.InvoiceMonthPattern, req.YearMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	previousMonthRows, err := invoicing.EntityInvoiceRows(ctx, fs, req.EntityID, invoicing.InvoiceDiffSource{
+		Month:  invoiceMonth.AddDate(0, -1, 0).Format(invoicing.InvoiceMonthPattern),
+		Issued: true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	draftRows, err := invoicing.EntityInvoiceRows(ctx, fs, req.EntityID, invoicing.InvoiceDiffSource{
+		Month: req.YearMonth,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := invoicediff.Diff(previousMonthRows, draftRows)
+
+	return map[string]interface{}{
+		"added":       len(diff.Added),
+		"removed":     len(diff.Removed),
+		"changed":     len(diff.Changed),
+		"totalDeltas": diff.TotalDeltas,
+	}, nil
+}
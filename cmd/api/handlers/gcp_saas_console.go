@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	doitFirestore "github.com/doitintl/firestore"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
@@ -54,6 +55,41 @@ func (h *GCPSaaSConsoleHandler) Activate(ctx *gin.Context) error {
 	return web.Respond(ctx, res, http.StatusOK)
 }
 
+func (h *GCPSaaSConsoleHandler) ResumeActivation(ctx *gin.Context) error {
+	customerID := ctx.Param("customerID")
+	billingAccountID := ctx.Param("billingAccountID")
+
+	res, err := h.onboardingService.ResumeActivation(ctx, customerID, billingAccountID)
+	if err != nil {
+		return activationRequestError(err)
+	}
+
+	return web.Respond(ctx, res, http.StatusOK)
+}
+
+func (h *GCPSaaSConsoleHandler) GetActivationStatus(ctx *gin.Context) error {
+	customerID := ctx.Param("customerID")
+	billingAccountID := ctx.Param("billingAccountID")
+
+	checkpoint, err := h.onboardingService.GetActivationStatus(ctx, customerID, billingAccountID)
+	if err != nil {
+		return activationRequestError(err)
+	}
+
+	return web.Respond(ctx, checkpoint, http.StatusOK)
+}
+
+func activationRequestError(err error) error {
+	switch err {
+	case doitFirestore.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case gcpsaasconsole.ErrActivationCompleted:
+		return web.NewRequestError(err, http.StatusConflict)
+	default:
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+}
+
 func (h *GCPSaaSConsoleHandler) RunCreateServiceAccountsTask(ctx *gin.Context) error {
 	if err := h.onboardingService.CreateServiceAccounts(ctx); err != nil {
 		return web.NewRequestError(err, http.StatusInternalServerError)
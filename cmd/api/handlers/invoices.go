@@ -4,7 +4,11 @@ import (
 	"net/http"
 
 	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/einvoice"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
 	"github.com/gin-gonic/gin"
 )
 
@@ -26,14 +30,30 @@ func NotificationsHandler(ctx *gin.Context) error {
 	return nil
 }
 
-func NotificationsWorker(ctx *gin.Context) error {
-	invoices.NotificationsWorker(ctx)
+type InvoiceNotifications struct {
+	eventDispatcher dispatch.Dispatcher
+}
+
+func NewInvoiceNotifications(loggerProvider logger.Provider, conn *connection.Connection) *InvoiceNotifications {
+	return &InvoiceNotifications{
+		eventDispatcher: dispatch.NewEventDispatcher(loggerProvider, conn),
+	}
+}
+
+func (h *InvoiceNotifications) NotificationsWorker(ctx *gin.Context) error {
+	invoices.NotificationsWorker(ctx, h.eventDispatcher)
 
 	return nil
 }
 
-func NoticeToRemedy(ctx *gin.Context) error {
-	invoices.NoticeToRemedy(ctx)
+// EInvoiceHandler downloads an issued invoice as a UBL (default) or CII e-invoice
+func EInvoiceHandler(ctx *gin.Context) error {
+	customerID := ctx.Param("customerID")
+	invoiceID := ctx.Param("invoiceID")
+
+	format := einvoice.Format(ctx.DefaultQuery("format", string(einvoice.FormatUBL)))
+
+	invoices.EInvoiceHandler(ctx, customerID, invoiceID, format)
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -222,6 +223,29 @@ func (h *Invoicing) CancelIssuedInvoices(ctx *gin.Context) error {
 	return web.Respond(ctx, nil, http.StatusOK)
 }
 
+// InvoiceDiffHandler compares the invoice rows of two billing runs or months of a customer
+func (h *Invoicing) InvoiceDiffHandler(ctx *gin.Context) error {
+	var body invoicing.InvoiceDiffRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	h.Logger(ctx).SetLabels(map[string]string{
+		logger.LabelCustomerID: body.CustomerID,
+	})
+
+	result, err := h.service.DiffCustomerInvoices(ctx, &body)
+	if err != nil {
+		if errors.Is(err, invoicing.ErrInvalidInvoiceMonth) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return h.InvoicingResponseHandler(ctx, err)
+	}
+
+	return web.Respond(ctx, result, http.StatusOK)
+}
+
 func (h *Invoicing) DevExportHandler(ctx *gin.Context) error {
 	var body invoicing.ExportInvoicesRequest
 	if err := ctx.ShouldBindJSON(&body); err != nil {
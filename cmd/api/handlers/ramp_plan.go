@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -21,6 +23,8 @@ type Request struct {
 	PlanID string `json:"planId"`
 }
 
+var errInvalidShortfallThreshold = errors.New("invalid shortfall threshold")
+
 type CreateRampPlanRequest struct {
 	ContractID string `json:"contract-id"`
 	Name       string `json:"name"`
@@ -110,3 +114,23 @@ func (h *RampPlan) CreateRampPlans(ctx *gin.Context) error {
 
 	return web.Respond(ctx, nil, http.StatusOK)
 }
+
+// NotifyRampPlanShortfalls notifies the ramp plans whose projected attainment is below the threshold query param
+func (h *RampPlan) NotifyRampPlanShortfalls(ctx *gin.Context) error {
+	threshold := rampplan.DefaultShortfallThreshold
+
+	if thresholdParam := ctx.Query("threshold"); thresholdParam != "" {
+		value, err := strconv.ParseFloat(thresholdParam, 64)
+		if err != nil || value <= 0 {
+			return web.NewRequestError(errInvalidShortfallThreshold, http.StatusBadRequest)
+		}
+
+		threshold = value
+	}
+
+	if err := h.service.NotifyShortfalls(ctx, threshold); err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
@@ -33,6 +33,8 @@ func (h *CloudAnalytics) handleServiceError(ctx *gin.Context, err error) error {
 		return web.Respond(ctx, "Invalid scheduler frequency", http.StatusBadRequest)
 	case schedule.ErrEmptyRecipientsList:
 		return web.Respond(ctx, "Invalid recipients", http.StatusBadRequest)
+	case schedule.ErrInvalidAttachment:
+		return web.Respond(ctx, "Invalid attachment format", http.StatusBadRequest)
 	default:
 		return web.NewRequestError(err, http.StatusInternalServerError)
 	}
@@ -0,0 +1,251 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/collections/domain"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+)
+
+const (
+	appCollection      = "app"
+	workflowDoc        = "collections-workflow"
+	noticeToRemedyDoc  = "notice-to-remedy"
+	casesCollection    = "collectionCases"
+	timelineCollection = "timeline"
+	invoicesCollection = "invoices"
+)
+
+// overdueInvoice are the fields of an issued invoice the collections workflow reads
+type overdueInvoice struct {
+	Customer           *firestore.DocumentRef `firestore:"customer"`
+	Entity             *firestore.DocumentRef `firestore:"entity"`
+	PayDate            time.Time              `firestore:"PAYDATE"`
+	Total              float64                `firestore:"QPRICE"`
+	Debit              float64                `firestore:"DEBIT"`
+	USDExchangeRate    float64                `firestore:"USDEXCH"`
+	NoticeToRemedySent bool                   `firestore:"isNoticeToRemedySent"`
+}
+
+// CollectionsFirestore stores the collections workflow, the customers cases and their timelines
+type CollectionsFirestore struct {
+	firestoreClientFun connection.FirestoreFromContextFun
+}
+
+func NewCollectionsFirestoreWithClient(fun connection.FirestoreFromContextFun) *CollectionsFirestore {
+	return &CollectionsFirestore{
+		firestoreClientFun: fun,
+	}
+}
+
+// GetWorkflow returns the configured collections workflow, or the default workflow when none was
+// configured yet. The default notice to remedy stage keeps the daily limit of the notice to remedy task.
+func (d *CollectionsFirestore) GetWorkflow(ctx context.Context) (*domain.Workflow, error) {
+	fs := d.firestoreClientFun(ctx)
+
+	docSnap, err := fs.Collection(appCollection).Doc(workflowDoc).Get(ctx)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+
+		return d.getDefaultWorkflow(ctx)
+	}
+
+	var workflow domain.Workflow
+	if err := docSnap.DataTo(&workflow); err != nil {
+		return nil, err
+	}
+
+	return &workflow, nil
+}
+
+func (d *CollectionsFirestore) getDefaultWorkflow(ctx context.Context) (*domain.Workflow, error) {
+	var dailyLimit int
+
+	docSnap, err := d.firestoreClientFun(ctx).Collection(appCollection).Doc(noticeToRemedyDoc).Get(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	if err == nil {
+		if totalPerDay, err := docSnap.DataAt("totalPerDay"); err == nil {
+			if v, ok := totalPerDay.(int64); ok {
+				dailyLimit = int(v)
+			}
+		}
+	}
+
+	return domain.DefaultWorkflow(dailyLimit), nil
+}
+
+// SetWorkflow replaces the collections workflow
+func (d *CollectionsFirestore) SetWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	_, err := d.firestoreClientFun(ctx).Collection(appCollection).Doc(workflowDoc).Set(ctx, workflow)
+
+	return err
+}
+
+// ListOverdueInvoices returns the unpaid invoices with debit whose pay date is on or before payDate,
+// grouped by customer ID
+func (d *CollectionsFirestore) ListOverdueInvoices(ctx context.Context, payDate time.Time) (map[string][]*domain.OverdueInvoice, error) {
+	iter := d.firestoreClientFun(ctx).Collection(invoicesCollection).
+		Where("PAID", "==", false).
+		Where("CANCELED", "==", false).
+		Where("PAYDATE", "<=", payDate).
+		Select("customer", "entity", "PAYDATE", "QPRICE", "DEBIT", "USDEXCH", "isNoticeToRemedySent").
+		Documents(ctx)
+	defer iter.Stop()
+
+	invoices := make(map[string][]*domain.OverdueInvoice)
+
+	for {
+		docSnap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var invoice overdueInvoice
+		if err := docSnap.DataTo(&invoice); err != nil {
+			return nil, err
+		}
+
+		if invoice.Customer == nil || invoice.Total <= 0 || invoice.Debit <= 0 {
+			continue
+		}
+
+		var entityID string
+		if invoice.Entity != nil {
+			entityID = invoice.Entity.ID
+		}
+
+		invoices[invoice.Customer.ID] = append(invoices[invoice.Customer.ID], &domain.OverdueInvoice{
+			ID:                 docSnap.Ref.ID,
+			EntityID:           entityID,
+			PayDate:            invoice.PayDate,
+			Debit:              invoice.Debit,
+			USDExchangeRate:    invoice.USDExchangeRate,
+			NoticeToRemedySent: invoice.NoticeToRemedySent,
+		})
+	}
+
+	return invoices, nil
+}
+
+// GetActiveCases returns the cases of the customers with overdue debt, by customer ID
+func (d *CollectionsFirestore) GetActiveCases(ctx context.Context) (map[string]*domain.Case, error) {
+	docSnaps, err := d.firestoreClientFun(ctx).Collection(casesCollection).
+		Where("active", "==", true).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make(map[string]*domain.Case, len(docSnaps))
+
+	for _, docSnap := range docSnaps {
+		var c domain.Case
+		if err := docSnap.DataTo(&c); err != nil {
+			return nil, err
+		}
+
+		cases[docSnap.Ref.ID] = &c
+	}
+
+	return cases, nil
+}
+
+// GetCase returns the case of a customer, or doitFirestore.ErrNotFound when the customer never had
+// overdue debt
+func (d *CollectionsFirestore) GetCase(ctx context.Context, customerID string) (*domain.Case, error) {
+	docSnap, err := d.firestoreClientFun(ctx).Collection(casesCollection).Doc(customerID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, doitFirestore.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	var c domain.Case
+	if err := docSnap.DataTo(&c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// SetCase replaces the case of a customer
+func (d *CollectionsFirestore) SetCase(ctx context.Context, customerID string, c *domain.Case) error {
+	_, err := d.firestoreClientFun(ctx).Collection(casesCollection).Doc(customerID).Set(ctx, c)
+
+	return err
+}
+
+// AddTimelineEvent appends an event to the timeline of a customer
+func (d *CollectionsFirestore) AddTimelineEvent(ctx context.Context, customerID string, event *domain.TimelineEvent) error {
+	_, _, err := d.firestoreClientFun(ctx).Collection(casesCollection).Doc(customerID).
+		Collection(timelineCollection).Add(ctx, event)
+
+	return err
+}
+
+// GetTimeline returns the events of a customer, most recent first
+func (d *CollectionsFirestore) GetTimeline(ctx context.Context, customerID string) ([]*domain.TimelineEvent, error) {
+	docSnaps, err := d.firestoreClientFun(ctx).Collection(casesCollection).Doc(customerID).
+		Collection(timelineCollection).
+		OrderBy("timestamp", firestore.Desc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.TimelineEvent, 0, len(docSnaps))
+
+	for _, docSnap := range docSnaps {
+		var event domain.TimelineEvent
+		if err := docSnap.DataTo(&event); err != nil {
+			return nil, err
+		}
+
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// CountStageExecutions returns the number of times each stage was executed on the given day, by stage ID
+func (d *CollectionsFirestore) CountStageExecutions(ctx context.Context, day time.Time) (map[string]int, error) {
+	docSnaps, err := d.firestoreClientFun(ctx).CollectionGroup(timelineCollection).
+		Where("type", "==", domain.EventTypeStageExecuted).
+		Where("timestamp", ">=", day).
+		Where("timestamp", "<", day.AddDate(0, 0, 1)).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	executed := make(map[string]int)
+
+	for _, docSnap := range docSnaps {
+		var event domain.TimelineEvent
+		if err := docSnap.DataTo(&event); err != nil {
+			return nil, err
+		}
+
+		executed[event.StageID]++
+	}
+
+	return executed, nil
+}
@@ -0,0 +1,21 @@
+package iface
+
+import (
+	"context"
+	"time"
+
+	"github.com/doitintl/hello/scheduled-tasks/collections/domain"
+)
+
+//go:generate mockery --name CollectionsFirestore --output ../mocks
+type CollectionsFirestore interface {
+	GetWorkflow(ctx context.Context) (*domain.Workflow, error)
+	SetWorkflow(ctx context.Context, workflow *domain.Workflow) error
+	ListOverdueInvoices(ctx context.Context, payDate time.Time) (map[string][]*domain.OverdueInvoice, error)
+	GetActiveCases(ctx context.Context) (map[string]*domain.Case, error)
+	GetCase(ctx context.Context, customerID string) (*domain.Case, error)
+	SetCase(ctx context.Context, customerID string, c *domain.Case) error
+	AddTimelineEvent(ctx context.Context, customerID string, event *domain.TimelineEvent) error
+	GetTimeline(ctx context.Context, customerID string) ([]*domain.TimelineEvent, error)
+	CountStageExecutions(ctx context.Context, day time.Time) (map[string]int, error)
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+const hoursPerDay = 24
+
+type EventType string
+
+const (
+	EventTypeStageExecuted       EventType = "stageExecuted"
+	EventTypeStageFailed         EventType = "stageFailed"
+	EventTypeStageSkipped        EventType = "stageSkipped"
+	EventTypeDisputeOpened       EventType = "disputeOpened"
+	EventTypeDisputeResolved     EventType = "disputeResolved"
+	EventTypePromiseToPay        EventType = "promiseToPay"
+	EventTypePromiseToPayRemoved EventType = "promiseToPayRemoved"
+	EventTypePromiseToPayBroken  EventType = "promiseToPayBroken"
+	EventTypeResolved            EventType = "resolved"
+)
+
+// OverdueInvoice is an unpaid invoice of a customer past its pay date
+type OverdueInvoice struct {
+	ID              string
+	EntityID        string
+	PayDate         time.Time
+	Debit           float64
+	USDExchangeRate float64
+	// NoticeToRemedySent is set once the customer was sent a notice to remedy for the invoice
+	NoticeToRemedySent bool
+}
+
+// Overdue is the overdue debt of a customer
+type Overdue struct {
+	CustomerID string
+	// DaysOverdue are the days since the pay date of the oldest overdue invoice
+	DaysOverdue int
+	// UnnoticedDaysOverdue are the days since the pay date of the oldest overdue invoice the customer
+	// was not sent a notice to remedy for
+	UnnoticedDaysOverdue int
+	AmountUSD            float64
+	InvoiceIDs           []string
+	EntityIDs            []string
+}
+
+// NewOverdue sums the overdue invoices of a customer on the given day. Invoices that are not yet due
+// or have no debit are ignored.
+func NewOverdue(customerID string, invoices []*OverdueInvoice, today time.Time) *Overdue {
+	overdue := &Overdue{
+		CustomerID: customerID,
+		InvoiceIDs: make([]string, 0),
+		EntityIDs:  make([]string, 0),
+	}
+
+	entityIDs := make(map[string]bool)
+
+	for _, invoice := range invoices {
+		days := int(today.Sub(invoice.PayDate).Hours() / hoursPerDay)
+		if days < 1 || invoice.Debit <= 0 {
+			continue
+		}
+
+		if days > overdue.DaysOverdue {
+			overdue.DaysOverdue = days
+		}
+
+		if !invoice.NoticeToRemedySent && days > overdue.UnnoticedDaysOverdue {
+			overdue.UnnoticedDaysOverdue = days
+		}
+
+		if invoice.USDExchangeRate > 0 {
+			overdue.AmountUSD += invoice.Debit / invoice.USDExchangeRate
+		}
+
+		overdue.InvoiceIDs = append(overdue.InvoiceIDs, invoice.ID)
+
+		if invoice.EntityID != "" && !entityIDs[invoice.EntityID] {
+			entityIDs[invoice.EntityID] = true
+			overdue.EntityIDs = append(overdue.EntityIDs, invoice.EntityID)
+		}
+	}
+
+	sort.Strings(overdue.InvoiceIDs)
+	sort.Strings(overdue.EntityIDs)
+
+	return overdue
+}
+
+// Dispute pauses the collections workflow of a customer until it is resolved
+type Dispute struct {
+	Reason     string    `firestore:"reason" json:"reason"`
+	InvoiceIDs []string  `firestore:"invoiceIds" json:"invoiceIds"`
+	OpenedBy   string    `firestore:"openedBy" json:"openedBy"`
+	OpenedAt   time.Time `firestore:"openedAt" json:"openedAt"`
+}
+
+// PromiseToPay pauses the collections workflow of a customer until the promised date
+type PromiseToPay struct {
+	Date      time.Time `firestore:"date" json:"date"`
+	AmountUSD float64   `firestore:"amountUsd" json:"amountUsd"`
+	Note      string    `firestore:"note" json:"note"`
+	CreatedBy string    `firestore:"createdBy" json:"createdBy"`
+	CreatedAt time.Time `firestore:"createdAt" json:"createdAt"`
+}
+
+// Case is the collections state of a customer
+type Case struct {
+	Active bool `firestore:"active" json:"active"`
+	// StageID is the most escalated stage executed for the customer, empty when no stage was executed yet
+	StageID         string        `firestore:"stageId" json:"stageId"`
+	StageExecutedAt *time.Time    `firestore:"stageExecutedAt" json:"stageExecutedAt"`
+	DaysOverdue     int           `firestore:"daysOverdue" json:"daysOverdue"`
+	AmountUSD       float64       `firestore:"amountUsd" json:"amountUsd"`
+	InvoiceIDs      []string      `firestore:"invoiceIds" json:"invoiceIds"`
+	Dispute         *Dispute      `firestore:"dispute" json:"dispute"`
+	PromiseToPay    *PromiseToPay `firestore:"promiseToPay" json:"promiseToPay"`
+	UpdatedAt       time.Time     `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// Paused reports whether the workflow of the customer is paused on the given day, by an open
+// dispute or by a promise to pay that is not yet due
+func (c *Case) Paused(today time.Time) bool {
+	return c.Dispute != nil || (c.PromiseToPay != nil && !today.After(c.PromiseToPay.Date))
+}
+
+// PromiseToPayBroken reports whether the customer did not pay by the promised date
+func (c *Case) PromiseToPayBroken(today time.Time) bool {
+	return c.PromiseToPay != nil && today.After(c.PromiseToPay.Date)
+}
+
+// TimelineEvent is an action taken in the collections of a customer, by the workflow or by a user
+type TimelineEvent struct {
+	Type        EventType   `firestore:"type" json:"type"`
+	StageID     string      `firestore:"stageId" json:"stageId"`
+	Action      StageAction `firestore:"action" json:"action"`
+	Recipients  []string    `firestore:"recipients" json:"recipients"`
+	InvoiceIDs  []string    `firestore:"invoiceIds" json:"invoiceIds"`
+	DaysOverdue int         `firestore:"daysOverdue" json:"daysOverdue"`
+	AmountUSD   float64     `firestore:"amountUsd" json:"amountUsd"`
+	Note        string      `firestore:"note" json:"note"`
+	// By is the email of the user that took the action, empty for actions of the workflow
+	By        string    `firestore:"by" json:"by"`
+	Timestamp time.Time `firestore:"timestamp" json:"timestamp"`
+}
+
+// CaseTimeline is the case of a customer with its events, most recent first
+type CaseTimeline struct {
+	*Case
+	Timeline []*TimelineEvent `json:"timeline"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOverdue(t *testing.T) {
+	today := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return today.AddDate(0, 0, -days)
+	}
+
+	overdue := NewOverdue("c1", []*OverdueInvoice{
+		{ID: "i3", EntityID: "b", PayDate: daysAgo(10), Debit: 110, USDExchangeRate: 1.1},
+		{ID: "i1", EntityID: "a", PayDate: daysAgo(40), Debit: 50, USDExchangeRate: 1, NoticeToRemedySent: true},
+		{ID: "i2", EntityID: "a", PayDate: daysAgo(0), Debit: 500, USDExchangeRate: 1},
+		{ID: "i4", EntityID: "a", PayDate: daysAgo(60), Debit: 0, USDExchangeRate: 1},
+	}, today)
+
+	assert.Equal(t, "c1", overdue.CustomerID)
+	assert.Equal(t, 40, overdue.DaysOverdue)
+	assert.Equal(t, 10, overdue.UnnoticedDaysOverdue)
+	assert.InDelta(t, 150, overdue.AmountUSD, 1e-9)
+	assert.Equal(t, []string{"i1", "i3"}, overdue.InvoiceIDs)
+	assert.Equal(t, []string{"a", "b"}, overdue.EntityIDs)
+
+	assert.Empty(t, NewOverdue("c2", nil, today).InvoiceIDs)
+}
+
+func TestCasePaused(t *testing.T) {
+	today := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		c          *Case
+		wantPaused bool
+		wantBroken bool
+	}{
+		{
+			name: "active",
+			c:    &Case{},
+		},
+		{
+			name:       "open dispute",
+			c:          &Case{Dispute: &Dispute{Reason: "wrong discount"}},
+			wantPaused: true,
+		},
+		{
+			name:       "payment promised later",
+			c:          &Case{PromiseToPay: &PromiseToPay{Date: today.AddDate(0, 0, 5)}},
+			wantPaused: true,
+		},
+		{
+			name:       "payment promised today",
+			c:          &Case{PromiseToPay: &PromiseToPay{Date: today}},
+			wantPaused: true,
+		},
+		{
+			name:       "promise to pay broken",
+			c:          &Case{PromiseToPay: &PromiseToPay{Date: today.AddDate(0, 0, -1)}},
+			wantBroken: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPaused, tt.c.Paused(today))
+			assert.Equal(t, tt.wantBroken, tt.c.PromiseToPayBroken(today))
+		})
+	}
+}
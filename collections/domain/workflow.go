@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+type StageAction string
+
+const (
+	// StageActionEmail emails the stage recipients with the stage template
+	StageActionEmail StageAction = "email"
+	// StageActionNoticeToRemedy creates the legal notice to remedy document and emails it to the
+	// customer with the overdue invoices attached. The notice is always sent to the billing contacts
+	// with the account manager and finance in copy, so the stage recipients do not apply to it.
+	StageActionNoticeToRemedy StageAction = "noticeToRemedy"
+)
+
+type Recipient string
+
+const (
+	// RecipientBillingContact are the billing contacts of the overdue entities and the customer users
+	// with billing permissions
+	RecipientBillingContact Recipient = "billingContact"
+	// RecipientAccountManager is the DoiT account manager of the customer
+	RecipientAccountManager Recipient = "accountManager"
+	// RecipientFinanceLead are the finance leads of the workflow
+	RecipientFinanceLead Recipient = "financeLead"
+)
+
+var ErrInvalidWorkflow = errors.New("invalid collections workflow")
+
+// Stage is a step of the collections workflow, executed once a customer has debt overdue by
+// at least MinDaysOverdue days and amounting to at least MinAmountUSD
+type Stage struct {
+	ID             string      `firestore:"id" json:"id"`
+	Name           string      `firestore:"name" json:"name"`
+	MinDaysOverdue int         `firestore:"minDaysOverdue" json:"minDaysOverdue"`
+	MinAmountUSD   float64     `firestore:"minAmountUsd" json:"minAmountUsd"`
+	Action         StageAction `firestore:"action" json:"action"`
+	// TemplateID is the SendGrid dynamic template of an email stage, the simple notification
+	// template is used when empty
+	TemplateID string `firestore:"templateId" json:"templateId"`
+	Subject    string `firestore:"subject" json:"subject"`
+	// Recipients are the recipients of an email stage
+	Recipients []Recipient `firestore:"recipients" json:"recipients"`
+	// DailyLimit is the maximum number of customers the stage is executed for per day, 0 is unlimited
+	DailyLimit int `firestore:"dailyLimit" json:"dailyLimit"`
+}
+
+// Workflow are the collections stages ordered by escalation
+type Workflow struct {
+	Stages       []*Stage `firestore:"stages" json:"stages"`
+	FinanceLeads []string `firestore:"financeLeads" json:"financeLeads"`
+}
+
+// DefaultWorkflow sends the notice to remedy after 90 days as before the workflow existed. Reminder and
+// escalation stages are added to the workflow by finance.
+func DefaultWorkflow(noticeToRemedyDailyLimit int) *Workflow {
+	return &Workflow{
+		Stages: []*Stage{
+			{
+				ID:             "notice-to-remedy",
+				Name:           "Notice to remedy",
+				MinDaysOverdue: 90,
+				Action:         StageActionNoticeToRemedy,
+				DailyLimit:     noticeToRemedyDailyLimit,
+			},
+		},
+	}
+}
+
+// Validate checks that the stages have unique IDs, known actions and recipients, that email stages have
+// recipients, and that their days overdue thresholds do not decrease as the stages escalate
+func (w *Workflow) Validate() error {
+	if len(w.Stages) == 0 {
+		return fmt.Errorf("%w: no stages", ErrInvalidWorkflow)
+	}
+
+	ids := make(map[string]bool)
+
+	for i, stage := range w.Stages {
+		if stage == nil || stage.ID == "" {
+			return fmt.Errorf("%w: stage %d has no id", ErrInvalidWorkflow, i)
+		}
+
+		if ids[stage.ID] {
+			return fmt.Errorf("%w: duplicate stage %s", ErrInvalidWorkflow, stage.ID)
+		}
+
+		ids[stage.ID] = true
+
+		if stage.MinDaysOverdue < 1 || stage.MinAmountUSD < 0 || stage.DailyLimit < 0 {
+			return fmt.Errorf("%w: stage %s has invalid thresholds", ErrInvalidWorkflow, stage.ID)
+		}
+
+		if i > 0 {
+			prev := w.Stages[i-1]
+			if stage.MinDaysOverdue < prev.MinDaysOverdue {
+				return fmt.Errorf("%w: stage %s is due before stage %s", ErrInvalidWorkflow, stage.ID, prev.ID)
+			}
+		}
+
+		switch stage.Action {
+		case StageActionEmail:
+			if stage.Subject == "" && stage.TemplateID == "" {
+				return fmt.Errorf("%w: email stage %s has no subject", ErrInvalidWorkflow, stage.ID)
+			}
+
+			if len(stage.Recipients) == 0 {
+				return fmt.Errorf("%w: email stage %s has no recipients", ErrInvalidWorkflow, stage.ID)
+			}
+		case StageActionNoticeToRemedy:
+		default:
+			return fmt.Errorf("%w: stage %s has unknown action %q", ErrInvalidWorkflow, stage.ID, stage.Action)
+		}
+
+		for _, recipient := range stage.Recipients {
+			switch recipient {
+			case RecipientBillingContact, RecipientAccountManager, RecipientFinanceLead:
+			default:
+				return fmt.Errorf("%w: stage %s has unknown recipient %q", ErrInvalidWorkflow, stage.ID, recipient)
+			}
+		}
+	}
+
+	return nil
+}
+
+// StageIndex returns the position of a stage in the workflow, or -1 when there is no such stage
+func (w *Workflow) StageIndex(stageID string) int {
+	for i, stage := range w.Stages {
+		if stage.ID == stageID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NextStage returns the most escalated stage the overdue debt qualifies for that is beyond the last
+// stage executed for the customer, or nil when there is none. Stages the customer skipped over, e.g.
+// because its debt was below their amount threshold, are not executed retroactively.
+// Notice to remedy stages are qualified by the invoices the customer was not noticed about yet rather
+// than by the last stage, so invoices that become overdue after a notice get a notice of their own.
+func (w *Workflow) NextStage(lastStageID string, overdue *Overdue) *Stage {
+	var next *Stage
+
+	lastIndex := w.StageIndex(lastStageID)
+
+	for i, stage := range w.Stages {
+		daysOverdue := overdue.DaysOverdue
+
+		switch {
+		case stage.Action == StageActionNoticeToRemedy:
+			daysOverdue = overdue.UnnoticedDaysOverdue
+		case i <= lastIndex:
+			continue
+		}
+
+		if daysOverdue >= stage.MinDaysOverdue && overdue.AmountUSD >= stage.MinAmountUSD {
+			next = stage
+		}
+	}
+
+	return next
+}
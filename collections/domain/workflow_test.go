@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// escalationWorkflow reminds the billing contacts after a week and escalates to the account manager and
+// then to the finance leads before the notice to remedy
+func escalationWorkflow() *Workflow {
+	return &Workflow{
+		Stages: []*Stage{
+			{
+				ID:             "reminder",
+				MinDaysOverdue: 7,
+				Action:         StageActionEmail,
+				Subject:        "Payment reminder",
+				Recipients:     []Recipient{RecipientBillingContact},
+			},
+			{
+				ID:             "account-manager",
+				MinDaysOverdue: 30,
+				MinAmountUSD:   1000,
+				Action:         StageActionEmail,
+				Subject:        "Second reminder",
+				Recipients:     []Recipient{RecipientBillingContact, RecipientAccountManager},
+			},
+			{
+				ID:             "finance",
+				MinDaysOverdue: 60,
+				MinAmountUSD:   5000,
+				Action:         StageActionEmail,
+				Subject:        "Final reminder",
+				Recipients:     []Recipient{RecipientBillingContact, RecipientAccountManager, RecipientFinanceLead},
+			},
+			DefaultWorkflow(10).Stages[0],
+		},
+	}
+}
+
+func TestDefaultWorkflow(t *testing.T) {
+	workflow := DefaultWorkflow(10)
+
+	assert.NoError(t, workflow.Validate())
+
+	if assert.Len(t, workflow.Stages, 1) {
+		assert.Equal(t, StageActionNoticeToRemedy, workflow.Stages[0].Action)
+		assert.Equal(t, 90, workflow.Stages[0].MinDaysOverdue)
+		assert.Equal(t, 10, workflow.Stages[0].DailyLimit)
+	}
+}
+
+func TestEscalationWorkflowIsValid(t *testing.T) {
+	assert.NoError(t, escalationWorkflow().Validate())
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	stage := func(id string, days int) *Stage {
+		return &Stage{
+			ID:             id,
+			MinDaysOverdue: days,
+			Action:         StageActionEmail,
+			Subject:        "overdue",
+			Recipients:     []Recipient{RecipientBillingContact},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		workflow *Workflow
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			workflow: &Workflow{Stages: []*Stage{stage("a", 7), stage("b", 7), stage("c", 30)}},
+		},
+		{
+			name:     "no stages",
+			workflow: &Workflow{},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate stage",
+			workflow: &Workflow{Stages: []*Stage{stage("a", 7), stage("a", 30)}},
+			wantErr:  true,
+		},
+		{
+			name:     "stage due before the previous stage",
+			workflow: &Workflow{Stages: []*Stage{stage("a", 30), stage("b", 7)}},
+			wantErr:  true,
+		},
+		{
+			name:     "stage due on the pay date",
+			workflow: &Workflow{Stages: []*Stage{stage("a", 0)}},
+			wantErr:  true,
+		},
+		{
+			name: "email stage without subject",
+			workflow: &Workflow{Stages: []*Stage{
+				{ID: "a", MinDaysOverdue: 7, Action: StageActionEmail, Recipients: []Recipient{RecipientBillingContact}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unknown action",
+			workflow: &Workflow{Stages: []*Stage{
+				{ID: "a", MinDaysOverdue: 7, Action: "call", Recipients: []Recipient{RecipientBillingContact}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "notice stage without recipients",
+			workflow: &Workflow{Stages: []*Stage{
+				stage("a", 7),
+				{ID: "b", MinDaysOverdue: 90, Action: StageActionNoticeToRemedy},
+			}},
+		},
+		{
+			name: "email stage without recipients",
+			workflow: &Workflow{Stages: []*Stage{
+				{ID: "a", MinDaysOverdue: 7, Action: StageActionEmail, Subject: "overdue"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unknown recipient",
+			workflow: &Workflow{Stages: []*Stage{
+				{ID: "a", MinDaysOverdue: 7, Action: StageActionNoticeToRemedy, Recipients: []Recipient{"ceo"}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.workflow.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWorkflow)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWorkflowNextStage(t *testing.T) {
+	workflow := escalationWorkflow()
+
+	stageID := func(stage *Stage) string {
+		if stage == nil {
+			return ""
+		}
+
+		return stage.ID
+	}
+
+	tests := []struct {
+		name                 string
+		lastStageID          string
+		daysOverdue          int
+		unnoticedDaysOverdue int
+		amountUSD            float64
+		want                 string
+	}{
+		{
+			name:        "not overdue enough",
+			daysOverdue: 3,
+			amountUSD:   100000,
+		},
+		{
+			name:        "first reminder",
+			daysOverdue: 7,
+			amountUSD:   10,
+			want:        "reminder",
+		},
+		{
+			name:        "reminder already sent",
+			lastStageID: "reminder",
+			daysOverdue: 20,
+			amountUSD:   10,
+		},
+		{
+			name:        "small debt is not escalated to the account manager",
+			lastStageID: "reminder",
+			daysOverdue: 45,
+			amountUSD:   500,
+		},
+		{
+			name:        "escalated to the account manager",
+			lastStageID: "reminder",
+			daysOverdue: 45,
+			amountUSD:   1500,
+			want:        "account-manager",
+		},
+		{
+			name:                 "skips the stages the debt outgrew",
+			daysOverdue:          95,
+			unnoticedDaysOverdue: 95,
+			amountUSD:            10000,
+			want:                 "notice-to-remedy",
+		},
+		{
+			name:                 "small debt still gets the notice to remedy",
+			lastStageID:          "reminder",
+			daysOverdue:          95,
+			unnoticedDaysOverdue: 95,
+			amountUSD:            10,
+			want:                 "notice-to-remedy",
+		},
+		{
+			name:        "invoices were noticed before the workflow",
+			lastStageID: "finance",
+			daysOverdue: 95,
+			amountUSD:   10000,
+		},
+		{
+			name:                 "last stage executed and every invoice noticed",
+			lastStageID:          "notice-to-remedy",
+			daysOverdue:          200,
+			unnoticedDaysOverdue: 60,
+			amountUSD:            10000,
+		},
+		{
+			name:                 "invoice overdue past the notice threshold after the last notice",
+			lastStageID:          "notice-to-remedy",
+			daysOverdue:          200,
+			unnoticedDaysOverdue: 90,
+			amountUSD:            10000,
+			want:                 "notice-to-remedy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overdue := &Overdue{
+				DaysOverdue:          tt.daysOverdue,
+				UnnoticedDaysOverdue: tt.unnoticedDaysOverdue,
+				AmountUSD:            tt.amountUSD,
+			}
+			assert.Equal(t, tt.want, stageID(workflow.NextStage(tt.lastStageID, overdue)))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/gin-gonic/gin"
+
+	"github.com/doitintl/hello/scheduled-tasks/collections/domain"
+	"github.com/doitintl/hello/scheduled-tasks/collections/service"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+type Collections struct {
+	loggerProvider logger.Provider
+	service        *service.CollectionsService
+}
+
+type disputeRequest struct {
+	Reason     string   `json:"reason"`
+	InvoiceIDs []string `json:"invoiceIds"`
+}
+
+type resolveDisputeRequest struct {
+	Note string `json:"note"`
+}
+
+type promiseToPayRequest struct {
+	Date      string  `json:"date"`
+	AmountUSD float64 `json:"amountUsd"`
+	Note      string  `json:"note"`
+}
+
+func NewCollections(log logger.Provider, conn *connection.Connection) *Collections {
+	return &Collections{
+		log,
+		service.NewCollectionsService(log, conn),
+	}
+}
+
+// RunHandler advances the collections workflow of all the customers with overdue invoices
+func (h *Collections) RunHandler(ctx *gin.Context) error {
+	if err := h.service.Run(ctx, time.Now().UTC()); err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func (h *Collections) GetWorkflowHandler(ctx *gin.Context) error {
+	workflow, err := h.service.GetWorkflow(ctx)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, workflow, http.StatusOK)
+}
+
+func (h *Collections) UpdateWorkflowHandler(ctx *gin.Context) error {
+	var workflow domain.Workflow
+	if err := ctx.ShouldBindJSON(&workflow); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	if err := h.service.UpdateWorkflow(ctx, &workflow); err != nil {
+		return respondError(err)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+// GetCaseHandler returns the collections case of a customer and its timeline
+func (h *Collections) GetCaseHandler(ctx *gin.Context) error {
+	result, err := h.service.GetCase(ctx, ctx.Param("customerID"))
+	if err != nil {
+		return respondError(err)
+	}
+
+	return web.Respond(ctx, result, http.StatusOK)
+}
+
+func (h *Collections) OpenDisputeHandler(ctx *gin.Context) error {
+	var req disputeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	dispute := &domain.Dispute{
+		Reason:     req.Reason,
+		InvoiceIDs: req.InvoiceIDs,
+	}
+
+	if err := h.service.OpenDispute(ctx, ctx.Param("customerID"), ctx.GetString(common.CtxKeys.Email), dispute); err != nil {
+		return respondError(err)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func (h *Collections) ResolveDisputeHandler(ctx *gin.Context) error {
+	var req resolveDisputeRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+	}
+
+	if err := h.service.ResolveDispute(ctx, ctx.Param("customerID"), ctx.GetString(common.CtxKeys.Email), req.Note); err != nil {
+		return respondError(err)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func (h *Collections) SetPromiseToPayHandler(ctx *gin.Context) error {
+	var req promiseToPayRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	date, err := civil.ParseDate(req.Date)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	promise := &domain.PromiseToPay{
+		Date:      date.In(time.UTC),
+		AmountUSD: req.AmountUSD,
+		Note:      req.Note,
+	}
+
+	if err := h.service.SetPromiseToPay(ctx, ctx.Param("customerID"), ctx.GetString(common.CtxKeys.Email), promise); err != nil {
+		return respondError(err)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func (h *Collections) RemovePromiseToPayHandler(ctx *gin.Context) error {
+	if err := h.service.RemovePromiseToPay(ctx, ctx.Param("customerID"), ctx.GetString(common.CtxKeys.Email)); err != nil {
+		return respondError(err)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func respondError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrCaseNotFound):
+		return web.NewRequestError(err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrInvalidWorkflow),
+		errors.Is(err, service.ErrInvalidDispute),
+		errors.Is(err, service.ErrInvalidPromiseToPay):
+		return web.NewRequestError(err, http.StatusBadRequest)
+	default:
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+}
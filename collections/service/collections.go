@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/collections/dal"
+	"github.com/doitintl/hello/scheduled-tasks/collections/dal/iface"
+	"github.com/doitintl/hello/scheduled-tasks/collections/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	customerDal "github.com/doitintl/hello/scheduled-tasks/customer/dal"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
+	entityDal "github.com/doitintl/hello/scheduled-tasks/entity/dal"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/mailer"
+)
+
+var (
+	ErrCaseNotFound        = errors.New("collections case not found")
+	ErrCustomerExempt      = errors.New("customer is exempt from collections")
+	ErrInvalidDispute      = errors.New("invalid dispute")
+	ErrInvalidPromiseToPay = errors.New("invalid promise to pay")
+	ErrNoRecipients        = errors.New("no recipients")
+)
+
+type Mailer interface {
+	SendNotification(sn *mailer.SimpleNotification, to string, params map[string]interface{}) error
+}
+
+type noticeToRemedyFun func(ctx context.Context, customerID string, minDaysOverdue int) ([]string, error)
+
+type billingUsersFun func(ctx context.Context, customerRef *firestore.DocumentRef) ([]string, error)
+
+type CollectionsService struct {
+	loggerProvider     logger.Provider
+	collectionsDAL     iface.CollectionsFirestore
+	customersDAL       customerDal.Customers
+	entitiesDAL        entityDal.Entites
+	mailer             Mailer
+	sendNoticeToRemedy noticeToRemedyFun
+	getBillingUsers    billingUsersFun
+}
+
+func NewCollectionsService(loggerProvider logger.Provider, conn *connection.Connection) *CollectionsService {
+	var collectionsMailer Mailer = mailer.CowardMailer{}
+	if common.Production {
+		collectionsMailer = mailer.NewMailer()
+	}
+
+	return &CollectionsService{
+		loggerProvider,
+		dal.NewCollectionsFirestoreWithClient(conn.Firestore),
+		customerDal.NewCustomersFirestoreWithClient(conn.Firestore),
+		entityDal.NewEntitiesFirestoreWithClient(conn.Firestore),
+		collectionsMailer,
+		invoices.SendNoticeToRemedy,
+		func(ctx context.Context, customerRef *firestore.DocumentRef) ([]string, error) {
+			users, err := common.GetCustomerUsersWithPermissions(ctx, conn.Firestore(ctx), customerRef, []string{
+				string(common.PermissionBillingProfiles),
+				string(common.PermissionInvoices),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			emails := make([]string, 0, len(users))
+			for _, user := range users {
+				emails = append(emails, user.Email)
+			}
+
+			return emails, nil
+		},
+	}
+}
+
+// Run advances the collections workflow of every customer with overdue invoices on the given day:
+// cases of customers that paid are resolved, paused cases are left as they are, and the next stage the
+// overdue debt qualifies for is executed within the stage daily limit
+func (s *CollectionsService) Run(ctx context.Context, day time.Time) error {
+	l := s.loggerProvider(ctx)
+
+	today := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	workflow, err := s.collectionsDAL.GetWorkflow(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := workflow.Validate(); err != nil {
+		return err
+	}
+
+	overdueInvoices, err := s.collectionsDAL.ListOverdueInvoices(ctx, today.AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+
+	cases, err := s.collectionsDAL.GetActiveCases(ctx)
+	if err != nil {
+		return err
+	}
+
+	customerIDs := make([]string, 0, len(overdueInvoices)+len(cases))
+
+	for customerID := range overdueInvoices {
+		customerIDs = append(customerIDs, customerID)
+	}
+
+	for customerID := range cases {
+		if _, ok := overdueInvoices[customerID]; !ok {
+			customerIDs = append(customerIDs, customerID)
+		}
+	}
+
+	sort.Strings(customerIDs)
+
+	// the executions of earlier runs of the day count towards the stages daily limits
+	executed, err := s.collectionsDAL.CountStageExecutions(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+
+	for _, customerID := range customerIDs {
+		overdue := domain.NewOverdue(customerID, overdueInvoices[customerID], today)
+
+		if err := s.runCase(ctx, workflow, cases[customerID], overdue, today, executed); err != nil {
+			l.Errorf("collections workflow of customer %s failed: %s", customerID, err)
+
+			failed++
+		}
+	}
+
+	l.Infof("collections workflow %s: %d customers, %v stages executed, %d failed", today.Format(time.DateOnly), len(customerIDs), executed, failed)
+
+	return nil
+}
+
+func (s *CollectionsService) runCase(
+	ctx context.Context,
+	workflow *domain.Workflow,
+	c *domain.Case,
+	overdue *domain.Overdue,
+	today time.Time,
+	executed map[string]int,
+) error {
+	now := time.Now().UTC()
+
+	if len(overdue.InvoiceIDs) == 0 {
+		if c == nil {
+			return nil
+		}
+
+		return s.resolveCase(ctx, overdue.CustomerID, c, now)
+	}
+
+	if c == nil {
+		existing, err := s.collectionsDAL.GetCase(ctx, overdue.CustomerID)
+		if err != nil && !errors.Is(err, doitFirestore.ErrNotFound) {
+			return err
+		}
+
+		c = existing
+		if c == nil {
+			c = &domain.Case{}
+		}
+	}
+
+	c.Active = true
+	c.DaysOverdue = overdue.DaysOverdue
+	c.AmountUSD = overdue.AmountUSD
+	c.InvoiceIDs = overdue.InvoiceIDs
+	c.UpdatedAt = now
+
+	if c.PromiseToPayBroken(today) {
+		if err := s.collectionsDAL.AddTimelineEvent(ctx, overdue.CustomerID, &domain.TimelineEvent{
+			Type:        domain.EventTypePromiseToPayBroken,
+			InvoiceIDs:  overdue.InvoiceIDs,
+			DaysOverdue: overdue.DaysOverdue,
+			AmountUSD:   overdue.AmountUSD,
+			Note:        fmt.Sprintf("payment promised by %s was not received", c.PromiseToPay.Date.Format(time.DateOnly)),
+			Timestamp:   now,
+		}); err != nil {
+			return err
+		}
+
+		c.PromiseToPay = nil
+	}
+
+	stage := workflow.NextStage(c.StageID, overdue)
+
+	if c.Paused(today) || stage == nil || (stage.DailyLimit > 0 && executed[stage.ID] >= stage.DailyLimit) {
+		return s.collectionsDAL.SetCase(ctx, overdue.CustomerID, c)
+	}
+
+	recipients, stageErr := s.executeStage(ctx, workflow, stage, overdue)
+
+	event := &domain.TimelineEvent{
+		StageID:     stage.ID,
+		Action:      stage.Action,
+		Recipients:  recipients,
+		InvoiceIDs:  overdue.InvoiceIDs,
+		DaysOverdue: overdue.DaysOverdue,
+		AmountUSD:   overdue.AmountUSD,
+		Timestamp:   now,
+	}
+
+	stageNotRequired := errors.Is(stageErr, ErrCustomerExempt) || errors.Is(stageErr, invoices.ErrNoticeToRemedyNotRequired)
+
+	switch {
+	case stageNotRequired && stage.ID == c.StageID:
+		// the notice stage is re-evaluated every run while the customer has invoices it was not
+		// noticed about, e.g. when it is exempt from notices, which is recorded only the first time
+		return s.collectionsDAL.SetCase(ctx, overdue.CustomerID, c)
+	case stageNotRequired:
+		event.Type = domain.EventTypeStageSkipped
+		event.Note = stageErr.Error()
+	case stageErr != nil:
+		event.Type = domain.EventTypeStageFailed
+		event.Note = stageErr.Error()
+	default:
+		event.Type = domain.EventTypeStageExecuted
+		executed[stage.ID]++
+	}
+
+	// a failed stage is retried on the next run, a skipped stage is not. A notice stage that is
+	// executed again does not take the case back to an earlier stage.
+	if event.Type != domain.EventTypeStageFailed {
+		if workflow.StageIndex(stage.ID) > workflow.StageIndex(c.StageID) {
+			c.StageID = stage.ID
+		}
+
+		c.StageExecutedAt = &now
+	}
+
+	if err := s.collectionsDAL.AddTimelineEvent(ctx, overdue.CustomerID, event); err != nil {
+		return err
+	}
+
+	if err := s.collectionsDAL.SetCase(ctx, overdue.CustomerID, c); err != nil {
+		return err
+	}
+
+	if event.Type == domain.EventTypeStageFailed {
+		return fmt.Errorf("stage %s: %w", stage.ID, stageErr)
+	}
+
+	return nil
+}
+
+// resolveCase closes the case of a customer that has no overdue debt left, so the workflow starts over
+// from its first stage the next time the customer is overdue
+func (s *CollectionsService) resolveCase(ctx context.Context, customerID string, c *domain.Case, now time.Time) error {
+	if err := s.collectionsDAL.AddTimelineEvent(ctx, customerID, &domain.TimelineEvent{
+		Type:      domain.EventTypeResolved,
+		StageID:   c.StageID,
+		Timestamp: now,
+	}); err != nil {
+		return err
+	}
+
+	return s.collectionsDAL.SetCase(ctx, customerID, &domain.Case{UpdatedAt: now})
+}
+
+// executeStage emails the stage recipients or sends the notice to remedy, and returns the recipients.
+// Customers exempt from the notice to remedy are exempt from every stage of the workflow.
+func (s *CollectionsService) executeStage(ctx context.Context, workflow *domain.Workflow, stage *domain.Stage, overdue *domain.Overdue) ([]string, error) {
+	customer, err := s.customersDAL.GetCustomer(ctx, overdue.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.SkipRemedyBreach {
+		return nil, ErrCustomerExempt
+	}
+
+	if stage.Action == domain.StageActionNoticeToRemedy {
+		return s.sendNoticeToRemedy(ctx, overdue.CustomerID, stage.MinDaysOverdue)
+	}
+
+	recipients, err := s.getRecipients(ctx, workflow, stage, overdue)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	sn := &mailer.SimpleNotification{
+		TemplateID: mailer.Config.DynamicTemplates.SimpleNotification,
+		Categories: []string{mailer.CatagoryInvoicesReminder},
+	}
+
+	if stage.TemplateID != "" {
+		sn.TemplateID = stage.TemplateID
+	}
+
+	params := map[string]interface{}{
+		"subject":     stage.Subject,
+		"preheader":   fmt.Sprintf("%d invoices are overdue by up to %d days", len(overdue.InvoiceIDs), overdue.DaysOverdue),
+		"body":        getStageEmailBody(overdue),
+		"customerId":  overdue.CustomerID,
+		"daysOverdue": overdue.DaysOverdue,
+		"amountUsd":   fmt.Sprintf("%.2f", overdue.AmountUSD),
+	}
+
+	sent := make([]string, 0, len(recipients))
+
+	var sendErr error
+
+	for _, recipient := range recipients {
+		if err := s.mailer.SendNotification(sn, recipient, params); err != nil {
+			sendErr = err
+			continue
+		}
+
+		sent = append(sent, recipient)
+	}
+
+	if len(sent) == 0 {
+		return nil, sendErr
+	}
+
+	return sent, nil
+}
+
+// getRecipients resolves the recipients of a stage to unique emails
+func (s *CollectionsService) getRecipients(ctx context.Context, workflow *domain.Workflow, stage *domain.Stage, overdue *domain.Overdue) ([]string, error) {
+	emails := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(email string) {
+		if email != "" && !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+
+	for _, recipient := range stage.Recipients {
+		switch recipient {
+		case domain.RecipientBillingContact:
+			for _, entityID := range overdue.EntityIDs {
+				entity, err := s.entitiesDAL.GetEntity(ctx, entityID)
+				if err != nil {
+					return nil, err
+				}
+
+				if entity.Contact != nil && entity.Contact.Email != nil {
+					add(*entity.Contact.Email)
+				}
+			}
+
+			users, err := s.getBillingUsers(ctx, s.customersDAL.GetRef(ctx, overdue.CustomerID))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, email := range users {
+				add(email)
+			}
+		case domain.RecipientAccountManager:
+			accountTeam, err := s.customersDAL.GetCustomerAccountTeam(ctx, overdue.CustomerID)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, accountManager := range accountTeam {
+				if accountManager.Role == common.AccountManagerRoleFSR {
+					add(accountManager.Email)
+				}
+			}
+		case domain.RecipientFinanceLead:
+			for _, email := range workflow.FinanceLeads {
+				add(email)
+			}
+		}
+	}
+
+	return emails, nil
+}
+
+func getStageEmailBody(overdue *domain.Overdue) string {
+	return fmt.Sprintf(
+		`<p>Our records show that invoices of your account amounting to USD %.2f are overdue, the oldest by %d days.</p>`+
+			`<p>Please review your <a href="https://console.doit.com/customers/%s/invoices">invoices</a> and arrange the payment. `+
+			`If you already paid or dispute any of these invoices, please reply to this email.</p>`,
+		overdue.AmountUSD, overdue.DaysOverdue, overdue.CustomerID)
+}
+
+// GetWorkflow returns the collections workflow
+func (s *CollectionsService) GetWorkflow(ctx context.Context) (*domain.Workflow, error) {
+	return s.collectionsDAL.GetWorkflow(ctx)
+}
+
+// UpdateWorkflow validates and replaces the collections workflow
+func (s *CollectionsService) UpdateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	if err := workflow.Validate(); err != nil {
+		return err
+	}
+
+	return s.collectionsDAL.SetWorkflow(ctx, workflow)
+}
+
+// GetCase returns the case of a customer with its timeline
+func (s *CollectionsService) GetCase(ctx context.Context, customerID string) (*domain.CaseTimeline, error) {
+	c, err := s.getCase(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, err := s.collectionsDAL.GetTimeline(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CaseTimeline{
+		Case:     c,
+		Timeline: timeline,
+	}, nil
+}
+
+// OpenDispute pauses the workflow of a customer until the dispute is resolved
+func (s *CollectionsService) OpenDispute(ctx context.Context, customerID, email string, dispute *domain.Dispute) error {
+	if dispute.Reason == "" {
+		return fmt.Errorf("%w: reason is required", ErrInvalidDispute)
+	}
+
+	c, err := s.getCase(ctx, customerID)
+	if err != nil && !errors.Is(err, ErrCaseNotFound) {
+		return err
+	}
+
+	if c == nil {
+		c = &domain.Case{}
+	}
+
+	now := time.Now().UTC()
+
+	dispute.OpenedBy = email
+	dispute.OpenedAt = now
+	c.Dispute = dispute
+	c.UpdatedAt = now
+
+	return s.updateCase(ctx, customerID, c, &domain.TimelineEvent{
+		Type:       domain.EventTypeDisputeOpened,
+		InvoiceIDs: dispute.InvoiceIDs,
+		Note:       dispute.Reason,
+		By:         email,
+		Timestamp:  now,
+	})
+}
+
+// ResolveDispute resumes the workflow of a customer from its last executed stage
+func (s *CollectionsService) ResolveDispute(ctx context.Context, customerID, email, note string) error {
+	c, err := s.getCase(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if c.Dispute == nil {
+		return fmt.Errorf("%w: customer has no open dispute", ErrInvalidDispute)
+	}
+
+	now := time.Now().UTC()
+
+	invoiceIDs := c.Dispute.InvoiceIDs
+	c.Dispute = nil
+	c.UpdatedAt = now
+
+	return s.updateCase(ctx, customerID, c, &domain.TimelineEvent{
+		Type:       domain.EventTypeDisputeResolved,
+		InvoiceIDs: invoiceIDs,
+		Note:       note,
+		By:         email,
+		Timestamp:  now,
+	})
+}
+
+// SetPromiseToPay pauses the workflow of a customer until the promised payment date
+func (s *CollectionsService) SetPromiseToPay(ctx context.Context, customerID, email string, promise *domain.PromiseToPay) error {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if promise.Date.Before(today) {
+		return fmt.Errorf("%w: date %s is in the past", ErrInvalidPromiseToPay, promise.Date.Format(time.DateOnly))
+	}
+
+	if promise.AmountUSD < 0 {
+		return fmt.Errorf("%w: negative amount", ErrInvalidPromiseToPay)
+	}
+
+	c, err := s.getCase(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	promise.CreatedBy = email
+	promise.CreatedAt = now
+	c.PromiseToPay = promise
+	c.UpdatedAt = now
+
+	return s.updateCase(ctx, customerID, c, &domain.TimelineEvent{
+		Type:      domain.EventTypePromiseToPay,
+		AmountUSD: promise.AmountUSD,
+		Note:      fmt.Sprintf("payment promised by %s. %s", promise.Date.Format(time.DateOnly), promise.Note),
+		By:        email,
+		Timestamp: now,
+	})
+}
+
+// RemovePromiseToPay resumes the workflow of a customer before the promised payment date
+func (s *CollectionsService) RemovePromiseToPay(ctx context.Context, customerID, email string) error {
+	c, err := s.getCase(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if c.PromiseToPay == nil {
+		return fmt.Errorf("%w: customer has no promise to pay", ErrInvalidPromiseToPay)
+	}
+
+	now := time.Now().UTC()
+
+	c.PromiseToPay = nil
+	c.UpdatedAt = now
+
+	return s.updateCase(ctx, customerID, c, &domain.TimelineEvent{
+		Type:      domain.EventTypePromiseToPayRemoved,
+		By:        email,
+		Timestamp: now,
+	})
+}
+
+func (s *CollectionsService) getCase(ctx context.Context, customerID string) (*domain.Case, error) {
+	c, err := s.collectionsDAL.GetCase(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, doitFirestore.ErrNotFound) {
+			return nil, ErrCaseNotFound
+		}
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s *CollectionsService) updateCase(ctx context.Context, customerID string, c *domain.Case, event *domain.TimelineEvent) error {
+	if err := s.collectionsDAL.SetCase(ctx, customerID, c); err != nil {
+		return err
+	}
+
+	return s.collectionsDAL.AddTimelineEvent(ctx, customerID, event)
+}
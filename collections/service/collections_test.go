@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/collections/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/collections/domain"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	customerMocks "github.com/doitintl/hello/scheduled-tasks/customer/dal/mocks"
+	customerDomain "github.com/doitintl/hello/scheduled-tasks/customer/domain"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
+	entitiesDALMocks "github.com/doitintl/hello/scheduled-tasks/entity/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/mailer"
+)
+
+type testMailer struct {
+	sent []string
+}
+
+func (m *testMailer) SendNotification(_ *mailer.SimpleNotification, to string, _ map[string]interface{}) error {
+	m.sent = append(m.sent, to)
+	return nil
+}
+
+// escalationWorkflow reminds the billing contacts and escalates to the account manager and then to the
+// finance leads before the notice to remedy
+func escalationWorkflow() *domain.Workflow {
+	return &domain.Workflow{
+		Stages: []*domain.Stage{
+			{
+				ID:             "reminder",
+				MinDaysOverdue: 7,
+				Action:         domain.StageActionEmail,
+				Subject:        "Payment reminder",
+				Recipients:     []domain.Recipient{domain.RecipientBillingContact},
+			},
+			{
+				ID:             "account-manager",
+				MinDaysOverdue: 30,
+				MinAmountUSD:   1000,
+				Action:         domain.StageActionEmail,
+				Subject:        "Second reminder",
+				Recipients:     []domain.Recipient{domain.RecipientBillingContact, domain.RecipientAccountManager},
+			},
+			{
+				ID:             "finance",
+				MinDaysOverdue: 60,
+				MinAmountUSD:   5000,
+				Action:         domain.StageActionEmail,
+				Subject:        "Final reminder",
+				Recipients:     []domain.Recipient{domain.RecipientBillingContact, domain.RecipientAccountManager, domain.RecipientFinanceLead},
+			},
+			domain.DefaultWorkflow(10).Stages[0],
+		},
+	}
+}
+
+func TestCollectionsService_Run(t *testing.T) {
+	ctx := context.Background()
+	today := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	billingEmail := "billing@customer.com"
+
+	overdueInvoices := func(days int) []*domain.OverdueInvoice {
+		return []*domain.OverdueInvoice{
+			{ID: "i1", EntityID: "e1", PayDate: today.AddDate(0, 0, -days), Debit: 2000, USDExchangeRate: 1},
+		}
+	}
+
+	type fields struct {
+		collectionsDAL *mocks.CollectionsFirestore
+		customersDAL   *customerMocks.Customers
+		entitiesDAL    *entitiesDALMocks.Entites
+	}
+
+	tests := []struct {
+		name        string
+		invoices    []*domain.OverdueInvoice
+		c           *domain.Case
+		exempt      bool
+		executed    map[string]int
+		noticeErr   error
+		on          func(f *fields)
+		wantStageID string
+		wantEvents  []domain.EventType
+		wantEmails  []string
+		wantNotice  bool
+	}{
+		{
+			name:     "first reminder to the billing contacts",
+			invoices: overdueInvoices(10),
+			on: func(f *fields) {
+				f.collectionsDAL.On("GetCase", ctx, "c1").Return(nil, doitFirestore.ErrNotFound).Once()
+				f.entitiesDAL.On("GetEntity", ctx, "e1").Return(&common.Entity{
+					Contact: &common.EntityContact{Email: &billingEmail},
+				}, nil).Once()
+				f.customersDAL.On("GetRef", ctx, "c1").Return(&firestore.DocumentRef{ID: "c1"}).Once()
+			},
+			wantStageID: "reminder",
+			wantEvents:  []domain.EventType{domain.EventTypeStageExecuted},
+			wantEmails:  []string{billingEmail, "user@customer.com"},
+		},
+		{
+			name:     "escalation to the account manager",
+			invoices: overdueInvoices(40),
+			c:        &domain.Case{Active: true, StageID: "reminder"},
+			on: func(f *fields) {
+				f.entitiesDAL.On("GetEntity", ctx, "e1").Return(&common.Entity{}, nil).Once()
+				f.customersDAL.On("GetRef", ctx, "c1").Return(&firestore.DocumentRef{ID: "c1"}).Once()
+				f.customersDAL.On("GetCustomerAccountTeam", ctx, "c1").Return([]customerDomain.AccountManagerListItem{
+					{Email: "fsr@doit.com", Role: common.AccountManagerRoleFSR},
+					{Email: "sam@doit.com", Role: "strategic_account_manager"},
+				}, nil).Once()
+			},
+			wantStageID: "account-manager",
+			wantEvents:  []domain.EventType{domain.EventTypeStageExecuted},
+			wantEmails:  []string{"user@customer.com", billingEmail, "fsr@doit.com"},
+		},
+		{
+			name:        "paused by a dispute",
+			invoices:    overdueInvoices(40),
+			c:           &domain.Case{Active: true, StageID: "reminder", Dispute: &domain.Dispute{Reason: "wrong discount"}},
+			wantStageID: "reminder",
+		},
+		{
+			name:     "broken promise to pay resumes the workflow",
+			invoices: overdueInvoices(95),
+			c: &domain.Case{
+				Active:       true,
+				StageID:      "finance",
+				PromiseToPay: &domain.PromiseToPay{Date: today.AddDate(0, 0, -2)},
+			},
+			wantStageID: "notice-to-remedy",
+			wantEvents:  []domain.EventType{domain.EventTypePromiseToPayBroken, domain.EventTypeStageExecuted},
+			wantNotice:  true,
+		},
+		{
+			name: "notice to remedy already sent",
+			invoices: []*domain.OverdueInvoice{
+				{ID: "i1", EntityID: "e1", PayDate: today.AddDate(0, 0, -95), Debit: 2000, USDExchangeRate: 1, NoticeToRemedySent: true},
+			},
+			c:           &domain.Case{Active: true, StageID: "finance"},
+			wantStageID: "finance",
+		},
+		{
+			name: "notice to remedy for an invoice overdue after the last notice",
+			invoices: []*domain.OverdueInvoice{
+				{ID: "i1", EntityID: "e1", PayDate: today.AddDate(0, 0, -150), Debit: 2000, USDExchangeRate: 1, NoticeToRemedySent: true},
+				{ID: "i2", EntityID: "e1", PayDate: today.AddDate(0, 0, -90), Debit: 1000, USDExchangeRate: 1},
+			},
+			c:           &domain.Case{Active: true, StageID: "notice-to-remedy"},
+			wantStageID: "notice-to-remedy",
+			wantEvents:  []domain.EventType{domain.EventTypeStageExecuted},
+			wantNotice:  true,
+		},
+		{
+			name:        "customer exempt from the notice to remedy",
+			invoices:    overdueInvoices(95),
+			c:           &domain.Case{Active: true, StageID: "finance"},
+			noticeErr:   invoices.ErrNoticeToRemedyNotRequired,
+			wantStageID: "notice-to-remedy",
+			wantEvents:  []domain.EventType{domain.EventTypeStageSkipped},
+			wantNotice:  true,
+		},
+		{
+			name:     "exempt customer skips the email stages",
+			invoices: overdueInvoices(10),
+			exempt:   true,
+			on: func(f *fields) {
+				f.collectionsDAL.On("GetCase", ctx, "c1").Return(nil, doitFirestore.ErrNotFound).Once()
+			},
+			wantStageID: "reminder",
+			wantEvents:  []domain.EventType{domain.EventTypeStageSkipped},
+		},
+		{
+			name:        "exempt customer skips the notice to remedy",
+			invoices:    overdueInvoices(95),
+			c:           &domain.Case{Active: true, StageID: "finance"},
+			exempt:      true,
+			wantStageID: "notice-to-remedy",
+			wantEvents:  []domain.EventType{domain.EventTypeStageSkipped},
+		},
+		{
+			name:        "daily limit reached by an earlier run of the day",
+			invoices:    overdueInvoices(95),
+			c:           &domain.Case{Active: true, StageID: "finance"},
+			executed:    map[string]int{"notice-to-remedy": 10},
+			wantStageID: "finance",
+		},
+		{
+			name:        "exemption from the notice to remedy is recorded once",
+			invoices:    overdueInvoices(95),
+			c:           &domain.Case{Active: true, StageID: "notice-to-remedy"},
+			noticeErr:   invoices.ErrNoticeToRemedyNotRequired,
+			wantStageID: "notice-to-remedy",
+			wantNotice:  true,
+		},
+		{
+			name:        "failed stage is retried on the next run",
+			invoices:    overdueInvoices(95),
+			c:           &domain.Case{Active: true, StageID: "finance"},
+			noticeErr:   errors.New("drive is unavailable"),
+			wantStageID: "finance",
+			wantEvents:  []domain.EventType{domain.EventTypeStageFailed},
+			wantNotice:  true,
+		},
+		{
+			name:       "paid customer case is resolved",
+			c:          &domain.Case{Active: true, StageID: "finance", Dispute: &domain.Dispute{Reason: "wrong discount"}},
+			wantEvents: []domain.EventType{domain.EventTypeResolved},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fields{
+				collectionsDAL: mocks.NewCollectionsFirestore(t),
+				customersDAL:   &customerMocks.Customers{},
+				entitiesDAL:    &entitiesDALMocks.Entites{},
+			}
+
+			overdueInvoices := make(map[string][]*domain.OverdueInvoice)
+			if tt.invoices != nil {
+				overdueInvoices["c1"] = tt.invoices
+			}
+
+			cases := make(map[string]*domain.Case)
+			if tt.c != nil {
+				cases["c1"] = tt.c
+			}
+
+			f.collectionsDAL.On("GetWorkflow", ctx).Return(escalationWorkflow(), nil).Once()
+			f.collectionsDAL.On("ListOverdueInvoices", ctx, today.AddDate(0, 0, -1)).Return(overdueInvoices, nil).Once()
+			f.collectionsDAL.On("GetActiveCases", ctx).Return(cases, nil).Once()
+
+			executed := tt.executed
+			if executed == nil {
+				executed = make(map[string]int)
+			}
+
+			f.collectionsDAL.On("CountStageExecutions", ctx, today).Return(executed, nil).Once()
+
+			var events []domain.EventType
+
+			for range tt.wantEvents {
+				f.collectionsDAL.On("AddTimelineEvent", ctx, "c1", mock.AnythingOfType("*domain.TimelineEvent")).
+					Run(func(args mock.Arguments) {
+						events = append(events, args.Get(2).(*domain.TimelineEvent).Type)
+					}).
+					Return(nil).Once()
+			}
+
+			var savedCase *domain.Case
+
+			f.collectionsDAL.On("SetCase", ctx, "c1", mock.AnythingOfType("*domain.Case")).
+				Run(func(args mock.Arguments) {
+					savedCase = args.Get(2).(*domain.Case)
+				}).
+				Return(nil).Once()
+
+			f.customersDAL.On("GetCustomer", ctx, "c1").Return(&common.Customer{SkipRemedyBreach: tt.exempt}, nil).Maybe()
+
+			if tt.on != nil {
+				tt.on(f)
+			}
+
+			m := &testMailer{}
+
+			var noticeSent bool
+
+			s := &CollectionsService{
+				loggerProvider: logger.FromContext,
+				collectionsDAL: f.collectionsDAL,
+				customersDAL:   f.customersDAL,
+				entitiesDAL:    f.entitiesDAL,
+				mailer:         m,
+				sendNoticeToRemedy: func(_ context.Context, customerID string, minDaysOverdue int) ([]string, error) {
+					noticeSent = true
+
+					assert.Equal(t, "c1", customerID)
+					assert.Equal(t, 90, minDaysOverdue)
+
+					return []string{billingEmail}, tt.noticeErr
+				},
+				getBillingUsers: func(_ context.Context, _ *firestore.DocumentRef) ([]string, error) {
+					return []string{"user@customer.com", billingEmail}, nil
+				},
+			}
+
+			assert.NoError(t, s.Run(ctx, today.Add(10*time.Hour)))
+
+			assert.Equal(t, tt.wantEvents, events)
+			assert.Equal(t, tt.wantEmails, m.sent)
+			assert.Equal(t, tt.wantNotice, noticeSent)
+			assert.Equal(t, tt.wantStageID, savedCase.StageID)
+			assert.Equal(t, tt.invoices != nil, savedCase.Active)
+
+			f.customersDAL.AssertExpectations(t)
+			f.entitiesDAL.AssertExpectations(t)
+		})
+	}
+}
@@ -107,6 +107,9 @@ const (
 
 	// Datahub
 	TaskQueueDatahubDeleteCustomerData TaskQueue = "datahub-delete-customer-data"
+
+	// Zapier
+	TaskQueueZapierWebhooks TaskQueue = "zapier-webhooks"
 )
 
 var (
@@ -99,6 +99,7 @@ type Customer struct {
 	InvoiceAttributionGroup *firestore.DocumentRef             `firestore:"invoiceAttributionGroup"`
 	CustomerSegment         *CustomerSegment                   `firestore:"customerSegment"`
 	InvoicesOnHold          map[string]*CloudOnHoldDetails     `firestore:"invoicesOnHold"`
+	SkipRemedyBreach        bool                               `firestore:"skipRemedyBreach"`
 	PresentationMode        *PresentationMode                  `firestore:"presentationMode"`
 	Tiers                   map[string]*pkg.CustomerTier       `firestore:"tiers"`
 
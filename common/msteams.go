@@ -0,0 +1,7 @@
+package common
+
+// MSTeamsChannel is a Microsoft Teams channel that receives notifications through an incoming webhook
+type MSTeamsChannel struct {
+	Name       string `json:"name" firestore:"name"`
+	WebhookURL string `json:"webhookUrl" firestore:"webhookUrl"`
+}
@@ -45,6 +45,7 @@ type Entity struct {
 	Invoicing       Invoicing                   `firestore:"invoicing"`
 	Contact         *EntityContact              `firestore:"contact"`
 	Payment         *EntityPayment              `firestore:"payment"`
+	VATNumber       *string                     `firestore:"vatNumber"`
 	Snapshot        *firestore.DocumentSnapshot `firestore:"-"`
 }
 
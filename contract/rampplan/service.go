@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"cloud.google.com/go/firestore"
 
 	"github.com/doitintl/firestore/pkg"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics"
+	forecast "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/service"
+	forecastIface "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/service/iface"
 	reportDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	attributionGroupDal "github.com/doitintl/hello/scheduled-tasks/contract/attributiongroup/dal"
@@ -19,8 +22,15 @@ import (
 	customerDAL "github.com/doitintl/hello/scheduled-tasks/customer/dal"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/mailer"
+	userDal "github.com/doitintl/hello/scheduled-tasks/user/dal"
+	userDalIface "github.com/doitintl/hello/scheduled-tasks/user/dal/iface"
 )
 
+type Mailer interface {
+	SendNotification(sn *mailer.SimpleNotification, to string, params map[string]interface{}) error
+}
+
 type Service struct {
 	*logger.Logging
 	conn                *connection.Connection
@@ -28,6 +38,10 @@ type Service struct {
 	RampPlansDal        rampPlansDal.RampPlans
 	contractsDal        contractDALIface.ContractFirestore
 	AttributionGroupDal attributionGroupDal.AttributionGroup
+	forecastService     forecastIface.Service
+	customersDal        customerDAL.Customers
+	usersDal            userDalIface.IUserFirestoreDAL
+	mailer              Mailer
 }
 
 func NewRampPlanService(log *logger.Logging, conn *connection.Connection) (*Service, error) {
@@ -43,6 +57,16 @@ func NewRampPlanService(log *logger.Logging, conn *connection.Connection) (*Serv
 		return nil, err
 	}
 
+	forecastService, err := forecast.NewService(logger.FromContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var shortfallMailer Mailer = mailer.CowardMailer{}
+	if common.Production {
+		shortfallMailer = mailer.NewMailer()
+	}
+
 	return &Service{
 		log,
 		conn,
@@ -50,6 +74,10 @@ func NewRampPlanService(log *logger.Logging, conn *connection.Connection) (*Serv
 		rampPlansDAL,
 		contractDAL,
 		attributionGroupDAL,
+		forecastService,
+		customerDal,
+		userDal.NewUserFirestoreDALWithClient(conn.Firestore),
+		shortfallMailer,
 	}, nil
 }
 
@@ -66,6 +94,12 @@ func (s *Service) UpdateUsage(ctx context.Context, planDoc *firestore.DocumentSn
 		return err
 	}
 
+	// the projection is computed before the actuals are added, since addActualSpendsToPeriods modifies the periods
+	projection, err := s.GetProjection(ctx, plan, periodsSpends, time.Now().UTC())
+	if err != nil {
+		s.Logger(ctx).Errorf("UpdateUsage: failed to project ramp plan %s: %s", plan.Ref.ID, err)
+	}
+
 	updatedCommitmentPeriods, err := addActualSpendsToPeriods(plan.CommitmentPeriods, periodsSpends)
 	if err != nil {
 		return err
@@ -81,10 +115,16 @@ func (s *Service) UpdateUsage(ctx context.Context, planDoc *firestore.DocumentSn
 
 	attainmentPercent := math.Min((totalActual/plan.TargetAmount)*100, 100)
 
-	if _, err := plan.Ref.Update(ctx, []firestore.Update{
+	updates := []firestore.Update{
 		{Path: "attainment", Value: attainmentPercent},
 		{Path: "commitmentPeriods", Value: updatedCommitmentPeriods},
-	}); err != nil {
+	}
+
+	if projection != nil {
+		updates = append(updates, firestore.Update{Path: "projection", Value: projection})
+	}
+
+	if _, err := plan.Ref.Update(ctx, updates); err != nil {
 		return err
 	}
 
@@ -51,6 +51,10 @@ func TestService_CreateRampPlan(t *testing.T) {
 			rampPlanMocks,
 			contractsMock,
 			attributionMocks,
+			nil,
+			nil,
+			nil,
+			nil,
 		}
 
 		err := service.CreateRampPlan(ctx, "ABCD", "EFGH", "")
@@ -0,0 +1,466 @@
+package rampplan
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/firestore"
+
+	"github.com/doitintl/firestore/pkg"
+	forecastDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/query/domain"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/mailer"
+)
+
+// DefaultShortfallThreshold is the projected attainment percent under which a ramp plan shortfall is notified
+const DefaultShortfallThreshold = 90.0
+
+// runRateMonths is the number of last full months used to project the spend when there is not enough history to forecast
+const runRateMonths = 3
+
+type ProjectionMethod string
+
+const (
+	ProjectionMethodForecast ProjectionMethod = "forecast"
+	ProjectionMethodRunRate  ProjectionMethod = "runRate"
+)
+
+// PeriodProjection is the projected spend of a commitment period at the end of the period
+type PeriodProjection struct {
+	StartDate       time.Time `json:"startDate" firestore:"startDate"`
+	EndDate         time.Time `json:"endDate" firestore:"endDate"`
+	Target          float64   `json:"target" firestore:"target"`
+	Actual          float64   `json:"actual" firestore:"actual"`
+	Projected       float64   `json:"projected" firestore:"projected"`
+	ProjectedLower  float64   `json:"projectedLower" firestore:"projectedLower"`
+	ProjectedUpper  float64   `json:"projectedUpper" firestore:"projectedUpper"`
+	Attainment      float64   `json:"attainment" firestore:"attainment"`
+	AttainmentLower float64   `json:"attainmentLower" firestore:"attainmentLower"`
+	AttainmentUpper float64   `json:"attainmentUpper" firestore:"attainmentUpper"`
+	Shortfall       float64   `json:"shortfall" firestore:"shortfall"`
+}
+
+// Projection is the projected spend of a ramp plan, for the whole plan and for each of its commitment periods.
+// Attainment is the projected spend as a percent of the target, and the lower and upper values are the confidence range.
+type Projection struct {
+	Method          ProjectionMethod   `json:"method" firestore:"method"`
+	Target          float64            `json:"target" firestore:"target"`
+	Actual          float64            `json:"actual" firestore:"actual"`
+	Projected       float64            `json:"projected" firestore:"projected"`
+	ProjectedLower  float64            `json:"projectedLower" firestore:"projectedLower"`
+	ProjectedUpper  float64            `json:"projectedUpper" firestore:"projectedUpper"`
+	Attainment      float64            `json:"attainment" firestore:"attainment"`
+	AttainmentLower float64            `json:"attainmentLower" firestore:"attainmentLower"`
+	AttainmentUpper float64            `json:"attainmentUpper" firestore:"attainmentUpper"`
+	Shortfall       float64            `json:"shortfall" firestore:"shortfall"`
+	Periods         []PeriodProjection `json:"periods" firestore:"periods"`
+	TimeUpdated     time.Time          `json:"timeUpdated" firestore:"timeUpdated"`
+}
+
+// rampPlanShortfall holds the ramp plan fields that are used by the shortfall notifications
+type rampPlanShortfall struct {
+	Projection        *Projection `firestore:"projection"`
+	ShortfallNotified bool        `firestore:"shortfallNotified"`
+}
+
+// monthlyForecast is the forecasted spend of a month
+type monthlyForecast struct {
+	value float64
+	lower float64
+	upper float64
+}
+
+// GetProjection projects the spend of the ramp plan at the end of each of its commitment periods.
+// The forecast is built from the monthly actual spend of the ramp plan attribution group.
+func (s *Service) GetProjection(ctx context.Context, plan pkg.RampPlan, periodsSpends []map[pkg.YearMonth]Spend, now time.Time) (*Projection, error) {
+	if len(plan.CommitmentPeriods) == 0 {
+		return nil, fmt.Errorf("ramp plan %s has no commitment periods", plan.Ref.ID)
+	}
+
+	actuals := monthlyTotals(periodsSpends)
+	currentMonth := startOfMonth(now)
+
+	forecast, err := s.getMonthlyForecast(ctx, plan, actuals, currentMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	method := ProjectionMethodForecast
+	if len(forecast) == 0 {
+		method = ProjectionMethodRunRate
+		forecast = runRateForecast(actuals, currentMonth)
+	}
+
+	projection := projectCommitmentPeriods(plan.CommitmentPeriods, actuals, forecast, currentMonth)
+	projection.Method = method
+	projection.TimeUpdated = now
+
+	return projection, nil
+}
+
+// getMonthlyForecast forecasts the spend of the months from the current month until the end of the ramp plan.
+// The current month is not used as input since it is not complete yet.
+func (s *Service) getMonthlyForecast(ctx context.Context, plan pkg.RampPlan, actuals map[pkg.YearMonth]float64, currentMonth time.Time) (map[pkg.YearMonth]monthlyForecast, error) {
+	yearCol, err := domain.NewCol("year")
+	if err != nil {
+		return nil, err
+	}
+
+	monthCol, err := domain.NewCol("month")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]bigquery.Value, 0, len(actuals))
+
+	for yearMonth, total := range actuals {
+		if !yearMonthTime(yearMonth).Before(currentMonth) {
+			continue
+		}
+
+		rows = append(rows, []bigquery.Value{strconv.Itoa(yearMonth.Year), fmt.Sprintf("%02d", yearMonth.Month), total})
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0].(string)+rows[i][1].(string) < rows[j][0].(string)+rows[j][1].(string)
+	})
+
+	periods := plan.CommitmentPeriods
+	from := startOfMonth(periods[0].StartDate)
+	to := periods[len(periods)-1].EndDate.AddDate(0, 0, -1) // end date is exclusive
+
+	predictions, err := s.forecastService.GetForecastPredictions(
+		ctx,
+		rows,
+		0,
+		[]*domain.QueryRequestX{yearCol, monthCol},
+		string(report.TimeIntervalMonth),
+		0,
+		currentMonth,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMonthlyForecast(predictions), nil
+}
+
+func toMonthlyForecast(predictions []*forecastDomain.ModelSeries) map[pkg.YearMonth]monthlyForecast {
+	forecast := make(map[pkg.YearMonth]monthlyForecast, len(predictions))
+
+	for _, prediction := range predictions {
+		parts := strings.Split(prediction.DS, "-")
+		if len(parts) != 2 {
+			continue
+		}
+
+		year, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+
+		month, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		forecast[pkg.YearMonth{Year: year, Month: month}] = monthlyForecast{
+			value: math.Max(prediction.Value, 0),
+			lower: math.Max(prediction.Lower, 0),
+			upper: math.Max(prediction.Upper, 0),
+		}
+	}
+
+	return forecast
+}
+
+// runRateForecast projects the average spend of the last full months, and uses the lowest and highest of them as the range
+func runRateForecast(actuals map[pkg.YearMonth]float64, currentMonth time.Time) map[pkg.YearMonth]monthlyForecast {
+	values := make([]float64, 0, runRateMonths)
+
+	for i := 1; i <= runRateMonths; i++ {
+		month := currentMonth.AddDate(0, -i, 0)
+		if value, ok := actuals[pkg.YearMonth{Year: month.Year(), Month: int(month.Month())}]; ok {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	runRate := monthlyForecast{lower: values[0], upper: values[0]}
+
+	for _, value := range values {
+		runRate.value += value
+		runRate.lower = math.Min(runRate.lower, value)
+		runRate.upper = math.Max(runRate.upper, value)
+	}
+
+	runRate.value /= float64(len(values))
+
+	return map[pkg.YearMonth]monthlyForecast{
+		{Year: currentMonth.Year(), Month: int(currentMonth.Month())}: runRate,
+	}
+}
+
+// projectCommitmentPeriods adds up the actual spend of the past months and the forecasted spend of the current and future months.
+// Months after the last forecasted month are projected with the spend of the last forecasted month.
+func projectCommitmentPeriods(periods []pkg.CommitmentPeriod, actuals map[pkg.YearMonth]float64, forecast map[pkg.YearMonth]monthlyForecast, currentMonth time.Time) *Projection {
+	projection := &Projection{
+		Periods: make([]PeriodProjection, 0, len(periods)),
+	}
+
+	var last monthlyForecast
+
+	for _, period := range periods {
+		periodProjection := PeriodProjection{
+			StartDate: period.StartDate,
+			EndDate:   period.EndDate,
+		}
+
+		for _, planned := range period.Planned {
+			periodProjection.Target += planned
+		}
+
+		for month := startOfMonth(period.StartDate); month.Before(period.EndDate); month = month.AddDate(0, 1, 0) {
+			yearMonth := pkg.YearMonth{Year: month.Year(), Month: int(month.Month())}
+			actual := actuals[yearMonth]
+			periodProjection.Actual += actual
+
+			if month.Before(currentMonth) {
+				periodProjection.Projected += actual
+				periodProjection.ProjectedLower += actual
+				periodProjection.ProjectedUpper += actual
+
+				continue
+			}
+
+			if monthForecast, ok := forecast[yearMonth]; ok {
+				last = monthForecast
+			}
+
+			// the current month already has some actual spend that the forecast can not go below
+			periodProjection.Projected += math.Max(last.value, actual)
+			periodProjection.ProjectedLower += math.Max(last.lower, actual)
+			periodProjection.ProjectedUpper += math.Max(last.upper, actual)
+		}
+
+		setAttainment(&periodProjection.Attainment, &periodProjection.AttainmentLower, &periodProjection.AttainmentUpper, &periodProjection.Shortfall,
+			periodProjection.Target, periodProjection.Projected, periodProjection.ProjectedLower, periodProjection.ProjectedUpper)
+
+		projection.Target += periodProjection.Target
+		projection.Actual += periodProjection.Actual
+		projection.Projected += periodProjection.Projected
+		projection.ProjectedLower += periodProjection.ProjectedLower
+		projection.ProjectedUpper += periodProjection.ProjectedUpper
+		projection.Periods = append(projection.Periods, periodProjection)
+	}
+
+	setAttainment(&projection.Attainment, &projection.AttainmentLower, &projection.AttainmentUpper, &projection.Shortfall,
+		projection.Target, projection.Projected, projection.ProjectedLower, projection.ProjectedUpper)
+
+	return projection
+}
+
+func setAttainment(attainment, attainmentLower, attainmentUpper, shortfall *float64, target, projected, projectedLower, projectedUpper float64) {
+	if target <= 0 {
+		return
+	}
+
+	*attainment = roundPercent(projected / target * 100)
+	*attainmentLower = roundPercent(projectedLower / target * 100)
+	*attainmentUpper = roundPercent(projectedUpper / target * 100)
+	*shortfall = math.Max(target-projected, 0)
+}
+
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*100) / 100
+}
+
+func monthlyTotals(periodsSpends []map[pkg.YearMonth]Spend) map[pkg.YearMonth]float64 {
+	totals := make(map[pkg.YearMonth]float64)
+
+	for _, periodSpends := range periodsSpends {
+		for yearMonth, spend := range periodSpends {
+			totals[yearMonth] += spend.Total
+		}
+	}
+
+	return totals
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func yearMonthTime(yearMonth pkg.YearMonth) time.Time {
+	return time.Date(yearMonth.Year, time.Month(yearMonth.Month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// isShortfall checks if the projected attainment of the ramp plan is below the threshold
+func isShortfall(projection *Projection, threshold float64) bool {
+	return projection != nil && projection.Target > 0 && projection.Attainment < threshold
+}
+
+// NotifyShortfalls notifies the account managers and the customer owners of the active ramp plans
+// whose projected attainment dropped below the threshold. A ramp plan is notified again only after
+// its projected attainment recovered above the threshold.
+func (s *Service) NotifyShortfalls(ctx context.Context, threshold float64) error {
+	log := s.Logger(ctx)
+
+	rampPlanDocs, err := s.RampPlansDal.GetAllActiveRampPlans(ctx)
+	if err != nil {
+		return err
+	}
+
+	var notified int
+
+	for _, planDoc := range rampPlanDocs {
+		var plan pkg.RampPlan
+		if err := planDoc.DataTo(&plan); err != nil {
+			log.Errorf("NotifyShortfalls: failed to read ramp plan %s: %s", planDoc.Ref.ID, err)
+			continue
+		}
+
+		var shortfall rampPlanShortfall
+		if err := planDoc.DataTo(&shortfall); err != nil {
+			log.Errorf("NotifyShortfalls: failed to read ramp plan %s projection: %s", planDoc.Ref.ID, err)
+			continue
+		}
+
+		below := isShortfall(shortfall.Projection, threshold)
+		if below == shortfall.ShortfallNotified {
+			continue
+		}
+
+		if below {
+			if err := s.sendShortfallNotification(ctx, planDoc.Ref.ID, &plan, shortfall.Projection, threshold); err != nil {
+				log.Errorf("NotifyShortfalls: failed to notify ramp plan %s shortfall: %s", planDoc.Ref.ID, err)
+				continue
+			}
+
+			notified++
+		}
+
+		if _, err := planDoc.Ref.Update(ctx, []firestore.Update{
+			{Path: "shortfallNotified", Value: below},
+		}); err != nil {
+			log.Errorf("NotifyShortfalls: failed to update ramp plan %s: %s", planDoc.Ref.ID, err)
+		}
+	}
+
+	log.Infof("NotifyShortfalls: notified %d of %d active ramp plans", notified, len(rampPlanDocs))
+
+	return nil
+}
+
+func (s *Service) sendShortfallNotification(ctx context.Context, planID string, plan *pkg.RampPlan, projection *Projection, threshold float64) error {
+	log := s.Logger(ctx)
+
+	if plan.Customer == nil {
+		return fmt.Errorf("ramp plan %s has no customer", planID)
+	}
+
+	customerID := plan.Customer.ID
+
+	recipients, err := s.getShortfallRecipients(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if len(recipients) == 0 {
+		log.Infof("sendShortfallNotification: no recipients for ramp plan %s of customer %s", planID, customerID)
+		return nil
+	}
+
+	sn := &mailer.SimpleNotification{
+		TemplateID: mailer.Config.DynamicTemplates.SimpleNotification,
+		Categories: []string{mailer.CatagoryContractsBreach},
+	}
+
+	params := map[string]interface{}{
+		"subject":   fmt.Sprintf("Ramp plan %s is projected to reach %.2f%% of its commitment", plan.Name, projection.Attainment),
+		"preheader": fmt.Sprintf("Projected attainment dropped below %.f%%", threshold),
+		"body":      getShortfallEmailBody(customerID, planID, plan.Name, projection),
+	}
+
+	for _, recipient := range recipients {
+		if err := s.mailer.SendNotification(sn, recipient, params); err != nil {
+			log.Errorf("sendShortfallNotification: failed to send ramp plan %s shortfall email to %s: %s", planID, recipient, err)
+		}
+	}
+
+	return nil
+}
+
+// getShortfallRecipients returns the emails of the customer account managers and admins
+func (s *Service) getShortfallRecipients(ctx context.Context, customerID string) ([]string, error) {
+	accountTeam, err := s.customersDal.GetCustomerAccountTeam(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := s.usersDal.GetCustomerUsersByRoles(ctx, customerID, []common.PresetRole{common.PresetRoleAdmin})
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make(map[string]bool)
+	recipients := make([]string, 0, len(accountTeam)+len(owners))
+
+	add := func(email string) {
+		if email != "" && !emails[email] {
+			emails[email] = true
+
+			recipients = append(recipients, email)
+		}
+	}
+
+	for _, accountManager := range accountTeam {
+		add(accountManager.Email)
+	}
+
+	for _, owner := range owners {
+		add(owner.Email)
+	}
+
+	return recipients, nil
+}
+
+func getShortfallEmailBody(customerID, planID, planName string, projection *Projection) string {
+	var body strings.Builder
+
+	body.WriteString(fmt.Sprintf("Based on the spend so far, the ramp plan <b>%s</b> is projected to reach <b>%.2f%%</b> of its commitment (%.2f%% - %.2f%%), a shortfall of <b>%s</b>.<br><br>",
+		html.EscapeString(planName), projection.Attainment, projection.AttainmentLower, projection.AttainmentUpper, common.FormatNumber(projection.Shortfall, 2)))
+
+	for i, period := range projection.Periods {
+		body.WriteString(fmt.Sprintf("Period %d (%s - %s): projected %s of %s (%.2f%%)<br>",
+			i+1,
+			period.StartDate.Format("Jan 2, 2006"),
+			period.EndDate.AddDate(0, 0, -1).Format("Jan 2, 2006"),
+			common.FormatNumber(period.Projected, 2),
+			common.FormatNumber(period.Target, 2),
+			period.Attainment,
+		))
+	}
+
+	body.WriteString(fmt.Sprintf("<br><a href=\"https://%s/customers/%s/contracts/ramps/%s\">Open ramp plan</a>", common.Domain, customerID, planID))
+
+	return body.String()
+}
@@ -0,0 +1,280 @@
+package rampplan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/firestore/pkg"
+	forecastDomain "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/domain"
+	forecastMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/forecast/service/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	customerMocks "github.com/doitintl/hello/scheduled-tasks/customer/dal/mocks"
+	customerDomain "github.com/doitintl/hello/scheduled-tasks/customer/domain"
+	userMocks "github.com/doitintl/hello/scheduled-tasks/user/dal/mocks"
+)
+
+var testCommitmentPeriods = []pkg.CommitmentPeriod{
+	{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		Planned:   []float64{100, 100, 100, 100, 100, 100},
+	},
+	{
+		StartDate: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Planned:   []float64{200, 200, 200, 200, 200, 200},
+	},
+}
+
+func TestProjectCommitmentPeriods(t *testing.T) {
+	currentMonth := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	forecast := map[pkg.YearMonth]monthlyForecast{
+		{Year: 2024, Month: 5}: {value: 90, lower: 80, upper: 100},
+		{Year: 2024, Month: 6}: {value: 90, lower: 80, upper: 100},
+		{Year: 2024, Month: 7}: {value: 95, lower: 85, upper: 105},
+	}
+
+	newActuals := func(currentMonthSpend float64) map[pkg.YearMonth]float64 {
+		return map[pkg.YearMonth]float64{
+			{Year: 2024, Month: 1}: 100,
+			{Year: 2024, Month: 2}: 100,
+			{Year: 2024, Month: 3}: 100,
+			{Year: 2024, Month: 4}: 100,
+			{Year: 2024, Month: 5}: currentMonthSpend,
+		}
+	}
+
+	t.Run("actuals of past months and forecast of the rest", func(t *testing.T) {
+		projection := projectCommitmentPeriods(testCommitmentPeriods, newActuals(40), forecast, currentMonth)
+
+		assert.Equal(t, []PeriodProjection{
+			{
+				StartDate:       testCommitmentPeriods[0].StartDate,
+				EndDate:         testCommitmentPeriods[0].EndDate,
+				Target:          600,
+				Actual:          440,
+				Projected:       580,
+				ProjectedLower:  560,
+				ProjectedUpper:  600,
+				Attainment:      96.67,
+				AttainmentLower: 93.33,
+				AttainmentUpper: 100,
+				Shortfall:       20,
+			},
+			{
+				StartDate:       testCommitmentPeriods[1].StartDate,
+				EndDate:         testCommitmentPeriods[1].EndDate,
+				Target:          1200,
+				Projected:       570,
+				ProjectedLower:  510,
+				ProjectedUpper:  630,
+				Attainment:      47.5,
+				AttainmentLower: 42.5,
+				AttainmentUpper: 52.5,
+				Shortfall:       630,
+			},
+		}, projection.Periods)
+
+		assert.Equal(t, 1800.0, projection.Target)
+		assert.Equal(t, 440.0, projection.Actual)
+		assert.Equal(t, 1150.0, projection.Projected)
+		assert.Equal(t, 63.89, projection.Attainment)
+		assert.Equal(t, 59.44, projection.AttainmentLower)
+		assert.Equal(t, 68.33, projection.AttainmentUpper)
+		assert.Equal(t, 650.0, projection.Shortfall)
+	})
+
+	t.Run("current month spend is above the forecast", func(t *testing.T) {
+		projection := projectCommitmentPeriods(testCommitmentPeriods, newActuals(120), forecast, currentMonth)
+
+		assert.Equal(t, 610.0, projection.Periods[0].Projected)
+		assert.Equal(t, 0.0, projection.Periods[0].Shortfall)
+		assert.Equal(t, 101.67, projection.Periods[0].Attainment)
+	})
+
+	t.Run("no forecast", func(t *testing.T) {
+		projection := projectCommitmentPeriods(testCommitmentPeriods, newActuals(40), nil, currentMonth)
+
+		assert.Equal(t, 440.0, projection.Projected)
+		assert.Equal(t, 1360.0, projection.Shortfall)
+	})
+}
+
+func TestRunRateForecast(t *testing.T) {
+	currentMonth := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	forecast := runRateForecast(map[pkg.YearMonth]float64{
+		{Year: 2024, Month: 1}: 1000,
+		{Year: 2024, Month: 2}: 100,
+		{Year: 2024, Month: 3}: 130,
+		{Year: 2024, Month: 4}: 70,
+		{Year: 2024, Month: 5}: 10,
+	}, currentMonth)
+
+	assert.Equal(t, map[pkg.YearMonth]monthlyForecast{
+		{Year: 2024, Month: 5}: {value: 100, lower: 70, upper: 130},
+	}, forecast)
+
+	assert.Nil(t, runRateForecast(map[pkg.YearMonth]float64{}, currentMonth))
+}
+
+func TestToMonthlyForecast(t *testing.T) {
+	forecast := toMonthlyForecast([]*forecastDomain.ModelSeries{
+		{DS: "2024-05", Value: 90, Lower: -10, Upper: 190},
+		{DS: "2024-06-01", Value: 90},
+		{DS: "invalid", Value: 90},
+	})
+
+	assert.Equal(t, map[pkg.YearMonth]monthlyForecast{
+		{Year: 2024, Month: 5}: {value: 90, lower: 0, upper: 190},
+	}, forecast)
+}
+
+func TestService_GetProjection(t *testing.T) {
+	now := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	plan := pkg.RampPlan{
+		Ref:               &firestore.DocumentRef{ID: "plan-id"},
+		CommitmentPeriods: testCommitmentPeriods,
+	}
+
+	periodsSpends := []map[pkg.YearMonth]Spend{
+		{
+			{Year: 2024, Month: 3}: {Total: 100},
+			{Year: 2024, Month: 4}: {Total: 100},
+			{Year: 2024, Month: 5}: {Total: 40},
+		},
+		{},
+	}
+
+	tests := []struct {
+		name          string
+		predictions   []*forecastDomain.ModelSeries
+		forecastErr   error
+		wantMethod    ProjectionMethod
+		wantProjected float64
+		wantErr       bool
+	}{
+		{
+			name: "forecast",
+			predictions: []*forecastDomain.ModelSeries{
+				{DS: "2024-05", Value: 150, Lower: 100, Upper: 200},
+			},
+			wantMethod:    ProjectionMethodForecast,
+			wantProjected: 200 + 150*8,
+		},
+		{
+			name:          "not enough history to forecast",
+			wantMethod:    ProjectionMethodRunRate,
+			wantProjected: 200 + 100*8,
+		},
+		{
+			name:        "forecast error",
+			forecastErr: errors.New("error"),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecastService := forecastMocks.NewService(t)
+			forecastService.On("GetForecastPredictions",
+				mock.Anything,
+				mock.MatchedBy(func(rows [][]bigquery.Value) bool { return len(rows) == 2 }),
+				0,
+				mock.Anything,
+				"month",
+				0,
+				time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			).Return(tt.predictions, tt.forecastErr)
+
+			s := &Service{forecastService: forecastService}
+
+			projection, err := s.GetProjection(context.Background(), plan, periodsSpends, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMethod, projection.Method)
+			assert.Equal(t, tt.wantProjected, projection.Projected)
+			assert.Equal(t, now, projection.TimeUpdated)
+		})
+	}
+}
+
+func TestService_getShortfallRecipients(t *testing.T) {
+	const customerID = "customer-id"
+
+	tests := []struct {
+		name    string
+		team    []customerDomain.AccountManagerListItem
+		owners  []*common.User
+		teamErr error
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "account managers and owners without duplicates",
+			team:   []customerDomain.AccountManagerListItem{{Email: "am@doit.com"}, {Email: ""}},
+			owners: []*common.User{{Email: "owner@customer.com"}, {Email: "am@doit.com"}},
+			want:   []string{"am@doit.com", "owner@customer.com"},
+		},
+		{
+			name:    "account team error",
+			teamErr: errors.New("error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customersDal := customerMocks.NewCustomers(t)
+			customersDal.On("GetCustomerAccountTeam", mock.Anything, customerID).Return(tt.team, tt.teamErr)
+
+			usersDal := userMocks.NewIUserFirestoreDAL(t)
+			if tt.teamErr == nil {
+				usersDal.On("GetCustomerUsersByRoles", mock.Anything, customerID, []common.PresetRole{common.PresetRoleAdmin}).Return(tt.owners, nil)
+			}
+
+			s := &Service{
+				customersDal: customersDal,
+				usersDal:     usersDal,
+			}
+
+			recipients, err := s.getShortfallRecipients(context.Background(), customerID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, recipients)
+		})
+	}
+}
+
+func TestIsShortfall(t *testing.T) {
+	assert.True(t, isShortfall(&Projection{Target: 100, Attainment: 80}, DefaultShortfallThreshold))
+	assert.False(t, isShortfall(&Projection{Target: 100, Attainment: 95}, DefaultShortfallThreshold))
+	assert.False(t, isShortfall(&Projection{}, DefaultShortfallThreshold))
+	assert.False(t, isShortfall(nil, DefaultShortfallThreshold))
+}
+
+func TestGetShortfallEmailBody(t *testing.T) {
+	body := getShortfallEmailBody("customer", "plan", "<script>alert(1)</script>", &Projection{Attainment: 80})
+
+	assert.Contains(t, body, "<b>&lt;script&gt;alert(1)&lt;/script&gt;</b>")
+	assert.NotContains(t, body, "<script>")
+}
@@ -17,6 +17,8 @@ const (
 	ticketStatistics     = "ticketStatistics"
 	ticketStatisticsDoc  = "ticket-statistics"
 	ducCollection        = "duc"
+	customizationDoc     = "customization"
+	usersCollection      = "users"
 )
 
 // DashboardsFirestore is used to interact with dashboards stored on Firestore.
@@ -129,6 +131,68 @@ func (d *DashboardsFirestore) GetCustomerDashboardsWithCloudReports(ctx context.
 	return dashboards, nil
 }
 
+// ListCustomerDashboards returns the dashboards that users of the customer created.
+func (d *DashboardsFirestore) ListCustomerDashboards(ctx context.Context, customerID string) ([]*domainDashboard.Dashboard, error) {
+	iter := d.dashboardsCollectionGroup(ctx).
+		Where("customerId", "==", customerID).
+		Documents(ctx)
+
+	snaps, err := d.documentsHandler.GetAll(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboards := make([]*domainDashboard.Dashboard, 0, len(snaps))
+
+	for _, snap := range snaps {
+		docRef := snap.Snapshot().Ref
+
+		parentDoc := docRef.Parent.Parent
+		if parentDoc == nil || parentDoc.Parent.ID != ducCollection {
+			continue
+		}
+
+		var dashboard domainDashboard.Dashboard
+
+		if err := snap.DataTo(&dashboard); err != nil {
+			return nil, err
+		}
+
+		dashboard.Ref = docRef
+		dashboard.ID = docRef.ID
+		dashboard.DocPath = getCleanDashboardDocumentPathFromRef(docRef)
+		dashboards = append(dashboards, &dashboard)
+	}
+
+	return dashboards, nil
+}
+
+// CreateUserDashboard creates a new dashboard of the user in the given customer.
+func (d *DashboardsFirestore) CreateUserDashboard(
+	ctx context.Context,
+	userID string,
+	customerID string,
+	dashboard *domainDashboard.Dashboard,
+) (*domainDashboard.Dashboard, error) {
+	docRef, _, err := d.rootDashboardsCollection(ctx).
+		Doc(customizationDoc).
+		Collection(usersCollection).
+		Doc(userID).
+		Collection(ducCollection).
+		Doc(customerID).
+		Collection(dashboardsCollection).
+		Add(ctx, dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard.Ref = docRef
+	dashboard.ID = docRef.ID
+	dashboard.DocPath = getCleanDashboardDocumentPathFromRef(docRef)
+
+	return dashboard, nil
+}
+
 func (d *DashboardsFirestore) RemoveDashboardWidget(ctx context.Context, dashboardRef *firestore.DocumentRef, widget domainDashboard.DashboardWidget) error {
 	_, err := dashboardRef.Update(ctx, []firestore.Update{
 		{FieldPath: []string{"widgets"}, Value: firestore.ArrayRemove(widget)},
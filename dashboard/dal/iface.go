@@ -17,12 +17,15 @@ type Dashboards interface {
 	RemoveDashboardWidget(ctx context.Context, dashboardRef *firestore.DocumentRef, widget dashboard.DashboardWidget) error
 	GetCustomerTicketStatistics(ctx context.Context, customerID string) ([]*dashboard.TicketSummary, error)
 	GetDashboardsWithPaths(ctx context.Context, paths []string) ([]*dashboard.Dashboard, error)
+	ListCustomerDashboards(ctx context.Context, customerID string) ([]*dashboard.Dashboard, error)
+	CreateUserDashboard(ctx context.Context, userID string, customerID string, d *dashboard.Dashboard) (*dashboard.Dashboard, error)
 }
 
 type PublicDashboards interface {
 	GetDashboardsWithCloudReportsCustomerIDs(ctx context.Context) ([]string, error)
 	GetCustomerDashboardsWithCloudReports(ctx context.Context, customerID string) ([]*dashboard.Dashboard, error)
 	UpdateReportWidgetDashboardsWidgetState(ctx context.Context, customerID string, reportID string, state dashboard.WidgetRefreshState) error
+	ListCustomerDashboards(ctx context.Context, customerID string) ([]*dashboard.Dashboard, error)
 }
 
 type DashboardAccessMetadata interface {
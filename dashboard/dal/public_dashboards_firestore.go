@@ -13,6 +13,7 @@ import (
 )
 
 const (
+	customersCollection        = "customers"
 	publicDashboardsCollection = "publicDashboards"
 
 	dashboardWidgetsField = "widgets"
@@ -117,6 +118,40 @@ func (d *PublicDashboardsFirestore) GetCustomerDashboardsWithCloudReports(ctx co
 	return dashboards, nil
 }
 
+// ListCustomerDashboards returns the public dashboards that are attached to the customer.
+func (d *PublicDashboardsFirestore) ListCustomerDashboards(ctx context.Context, customerID string) ([]*domainDashboard.Dashboard, error) {
+	iter := d.firestoreClientFun(ctx).
+		Collection(customersCollection).
+		Doc(customerID).
+		Collection(publicDashboardsCollection).
+		Documents(ctx)
+
+	snaps, err := d.documentsHandler.GetAll(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboards := make([]*domainDashboard.Dashboard, 0, len(snaps))
+
+	for _, snap := range snaps {
+		docRef := snap.Snapshot().Ref
+
+		var dashboard domainDashboard.Dashboard
+
+		if err := snap.DataTo(&dashboard); err != nil {
+			return nil, err
+		}
+
+		dashboard.Ref = docRef
+		dashboard.ID = docRef.ID
+		dashboard.DocPath = getCleanDashboardDocumentPathFromRef(docRef)
+
+		dashboards = append(dashboards, &dashboard)
+	}
+
+	return dashboards, nil
+}
+
 func (d *PublicDashboardsFirestore) UpdateReportWidgetDashboardsWidgetState(ctx context.Context, customerID string, reportID string, state domainDashboard.WidgetRefreshState) error {
 	path := fmt.Sprintf("customers/%s/publicDashboards", customerID)
 	widgetName := fmt.Sprintf("cloudReports::%s_%s", customerID, reportID)
@@ -0,0 +1,281 @@
+package invoices
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/einvoice"
+	"github.com/doitintl/hello/scheduled-tasks/priority"
+)
+
+const xmlMimeType = "application/xml"
+
+var (
+	ErrUnknownSellerCompany = errors.New("unknown seller company")
+	ErrUnknownBuyerCountry  = errors.New("unknown buyer country")
+	ErrMissingSellerTaxID   = errors.New("seller company has no VAT or registration identifier")
+)
+
+// EInvoiceHandler responds with the structured e-invoice of an issued invoice of the customer, which
+// is downloaded next to the PDF of the invoice.
+func EInvoiceHandler(ctx *gin.Context, customerID, invoiceID string, format einvoice.Format) {
+	fs := common.GetFirestoreClient(ctx)
+
+	docSnap, err := fs.Collection("invoices").Doc(invoiceID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			ctx.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	var invoice FullInvoice
+	if err := docSnap.DataTo(&invoice); err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if invoice.Customer == nil || invoice.Customer.ID != customerID || invoice.Entity == nil {
+		ctx.AbortWithError(http.StatusNotFound, fmt.Errorf("invoice %s not found", invoiceID))
+		return
+	}
+
+	if invoice.Canceled {
+		ctx.AbortWithError(http.StatusBadRequest, fmt.Errorf("invoice %s is canceled", invoice.ID))
+		return
+	}
+
+	entitySnap, err := invoice.Entity.Get(ctx)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	var entity common.Entity
+	if err := entitySnap.DataTo(&entity); err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	data, err := invoice.getEInvoice(&entity, format)
+	if err != nil {
+		switch {
+		case errors.Is(err, einvoice.ErrUnsupportedFormat):
+			ctx.AbortWithError(http.StatusBadRequest, err)
+		case errors.Is(err, einvoice.ErrInvalidDocument),
+			errors.Is(err, ErrUnknownSellerCompany),
+			errors.Is(err, ErrUnknownBuyerCountry),
+			errors.Is(err, ErrMissingSellerTaxID):
+			ctx.AbortWithError(http.StatusUnprocessableEntity, err)
+		default:
+			ctx.AbortWithError(http.StatusInternalServerError, err)
+		}
+
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.xml", invoice.ID, format))
+	ctx.Data(http.StatusOK, xmlMimeType, data)
+}
+
+// getEInvoice returns the invoice as a UBL or CII e-invoice, validated against the EN16931 rules
+func (i *FullInvoice) getEInvoice(entity *common.Entity, format einvoice.Format) ([]byte, error) {
+	document, err := i.toEInvoiceDocument(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return einvoice.Generate(document, format)
+}
+
+func (i *FullInvoice) toEInvoiceDocument(entity *common.Entity) (*einvoice.Document, error) {
+	company, ok := companyInfo(entity.PriorityCompany)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSellerCompany, entity.PriorityCompany)
+	}
+
+	if company.VATID == "" && company.LegalID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMissingSellerTaxID, company.CompanyID)
+	}
+
+	var countryName string
+
+	switch {
+	case entity.BillingAddress.CountryName != nil:
+		countryName = *entity.BillingAddress.CountryName
+	case entity.Country != nil:
+		countryName = *entity.Country
+	}
+
+	buyerCountry := einvoice.CountryCode(countryName)
+	if buyerCountry == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBuyerCountry, countryName)
+	}
+
+	sellerCountry := entity.PayeeCountry()
+
+	var buyerVATID string
+	if entity.VATNumber != nil {
+		buyerVATID = *entity.VATNumber
+	}
+
+	taxCategory, taxPercent := i.taxCategory(sellerCountry, buyerCountry)
+
+	sellerVATID, buyerPartyVATID := company.VATID, buyerVATID
+	if taxCategory == einvoice.TaxCategoryNotSubject {
+		// VAT identifiers are not allowed on invoices not subject to VAT, the electronic
+		// addresses below still use them
+		sellerVATID, buyerPartyVATID = "", ""
+	}
+
+	document := &einvoice.Document{
+		ID:             i.ID,
+		IssueDate:      i.Date,
+		DueDate:        i.PayDate,
+		Currency:       i.Currency,
+		BuyerReference: entity.PriorityID,
+		Note:           i.Details,
+		Seller: einvoice.Party{
+			Name:    company.CompanyName,
+			VATID:   sellerVATID,
+			LegalID: company.LegalID,
+			Address: einvoice.Address{CountryCode: sellerCountry},
+		},
+		Buyer: einvoice.Party{
+			Name:    entity.Name,
+			VATID:   buyerPartyVATID,
+			Address: toEInvoiceAddress(entity.BillingAddress, buyerCountry),
+		},
+		PayeeIBAN: wireTransferValue(company, "IBAN"),
+	}
+
+	document.Seller.EndpointID, document.Seller.EndpointSchemeID = einvoice.VATEndpoint(sellerCountry, company.VATID)
+	if document.Seller.EndpointID == "" {
+		document.Seller.EndpointID, document.Seller.EndpointSchemeID = einvoice.LegalEndpoint(sellerCountry, company.LegalID)
+	}
+
+	// the tax number of a buyer from a country without a VAT based scheme is its registration number
+	document.Buyer.EndpointID, document.Buyer.EndpointSchemeID = einvoice.VATEndpoint(buyerCountry, buyerVATID)
+	if document.Buyer.EndpointID == "" {
+		document.Buyer.EndpointID, document.Buyer.EndpointSchemeID = einvoice.LegalEndpoint(buyerCountry, buyerVATID)
+	}
+
+	for n, item := range i.InvoiceItems {
+		line := einvoice.Line{
+			ID:          strconv.Itoa(n + 1),
+			SKU:         item.SKU,
+			Name:        item.Description,
+			Description: item.Details,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.DiscountPrice,
+			NetAmount:   item.Total,
+			TaxCategory: taxCategory,
+			TaxPercent:  taxPercent,
+			PeriodStart: parseItemDate(item.FromDate),
+			PeriodEnd:   parseItemDate(item.ToDate),
+		}
+
+		if line.Name == "" {
+			line.Name = item.SKU
+		}
+
+		// credits are negative quantities, as the item price cannot be negative
+		if line.UnitPrice < 0 {
+			line.UnitPrice = -line.UnitPrice
+			line.Quantity = -line.Quantity
+		}
+
+		document.Lines = append(document.Lines, line)
+	}
+
+	return document, nil
+}
+
+// taxCategory returns the VAT category and rate of the invoice lines. Priority charges a single VAT
+// rate per invoice, so the rate is derived from the invoice totals.
+func (i *FullInvoice) taxCategory(sellerCountry, buyerCountry string) (einvoice.TaxCategory, float64) {
+	switch {
+	case i.Vat != 0 && i.Total != 0:
+		return einvoice.TaxCategoryStandard, math.Round(i.Vat/i.Total*10000) / 100
+	case sellerCountry == buyerCountry:
+		return einvoice.TaxCategoryZeroRated, 0
+	case einvoice.IsEU(sellerCountry) && einvoice.IsEU(buyerCountry):
+		return einvoice.TaxCategoryReverseCharge, 0
+	case einvoice.IsEU(sellerCountry):
+		return einvoice.TaxCategoryExport, 0
+	default:
+		return einvoice.TaxCategoryNotSubject, 0
+	}
+}
+
+// toEInvoiceAddress maps a Priority billing address, in which the second address line holds the city
+func toEInvoiceAddress(billingAddress common.BillingAddress, countryCode string) einvoice.Address {
+	address := einvoice.Address{CountryCode: countryCode}
+
+	for _, line := range []*string{billingAddress.Address, billingAddress.Address3} {
+		if line != nil && *line != "" {
+			address.Lines = append(address.Lines, *line)
+		}
+	}
+
+	if billingAddress.Address2 != nil {
+		address.City = *billingAddress.Address2
+	}
+
+	if billingAddress.Zip != nil {
+		address.PostalZone = *billingAddress.Zip
+	}
+
+	if billingAddress.StateName != nil {
+		address.CountrySubentity = *billingAddress.StateName
+	}
+
+	return address
+}
+
+func companyInfo(companyCode string) (priority.CompanyInfo, bool) {
+	for _, company := range priority.CompaniesDetails {
+		if company.CompanyID == companyCode {
+			return company, true
+		}
+	}
+
+	return priority.CompanyInfo{}, false
+}
+
+func wireTransferValue(company priority.CompanyInfo, key string) string {
+	for _, pair := range company.WireTransfer {
+		if pair.Key == key {
+			return pair.Value
+		}
+	}
+
+	return ""
+}
+
+func parseItemDate(date *string) *time.Time {
+	if date == nil || *date == "" {
+		return nil
+	}
+
+	for _, layout := range []string{dateTimeFormat, dateFormat} {
+		if t, err := time.Parse(layout, *date); err == nil {
+			return &t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package invoices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/einvoice"
+	"github.com/doitintl/hello/scheduled-tasks/priority"
+)
+
+// setCompanyTaxIDs sets the tax identifiers of a priority company for the duration of the test
+func setCompanyTaxIDs(t *testing.T, companyID, vatID, legalID string) {
+	for i := range priority.CompaniesDetails {
+		company := &priority.CompaniesDetails[i]
+		if company.CompanyID != companyID {
+			continue
+		}
+
+		prevVATID, prevLegalID := company.VATID, company.LegalID
+		company.VATID, company.LegalID = vatID, legalID
+
+		t.Cleanup(func() {
+			company.VATID, company.LegalID = prevVATID, prevLegalID
+		})
+
+		return
+	}
+
+	t.Fatalf("unknown company %s", companyID)
+}
+
+func TestFullInvoice_toEInvoiceDocument(t *testing.T) {
+	stringPtr := func(s string) *string {
+		return &s
+	}
+
+	invoice := &FullInvoice{
+		ID:       "IN240001",
+		Currency: "EUR",
+		Total:    900,
+		Details:  "January 2024",
+		Date:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		PayDate:  time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		InvoiceItems: []*InvoiceItem{
+			{
+				SKU:           "GCP-SKU",
+				Description:   "Google Cloud",
+				Details:       "Billing account 01",
+				Quantity:      1,
+				DiscountPrice: 1000,
+				Total:         1000,
+				FromDate:      stringPtr("2024-01-01T00:00:00+00:00"),
+				ToDate:        stringPtr("2024-01-31T00:00:00+00:00"),
+			},
+			{
+				SKU:           "CREDIT",
+				Quantity:      1,
+				DiscountPrice: -100,
+				Total:         -100,
+			},
+		},
+	}
+
+	frenchEntity := &common.Entity{
+		PriorityID:      "FR000123",
+		PriorityCompany: "doitde",
+		Name:            "Client SAS",
+		VATNumber:       stringPtr("FR12345678901"),
+		BillingAddress: common.BillingAddress{
+			CountryName: stringPtr("France"),
+			Address:     stringPtr("1 Rue de Rivoli"),
+			Address2:    stringPtr("Paris"),
+			Zip:         stringPtr("75001"),
+		},
+	}
+
+	t.Run("reverse charge within the EU", func(t *testing.T) {
+		setCompanyTaxIDs(t, "doitde", "DE123456789", "HRB 123456")
+
+		document, err := invoice.toEInvoiceDocument(frenchEntity)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, einvoice.Party{
+			Name:             "DoiT International, DACH GmbH",
+			VATID:            "DE123456789",
+			LegalID:          "HRB 123456",
+			EndpointID:       "DE123456789",
+			EndpointSchemeID: "9930",
+			Address:          einvoice.Address{CountryCode: "DE"},
+		}, document.Seller)
+		assert.Equal(t, einvoice.Party{
+			Name:             "Client SAS",
+			VATID:            "FR12345678901",
+			EndpointID:       "FR12345678901",
+			EndpointSchemeID: "9957",
+			Address: einvoice.Address{
+				Lines:       []string{"1 Rue de Rivoli"},
+				City:        "Paris",
+				PostalZone:  "75001",
+				CountryCode: "FR",
+			},
+		}, document.Buyer)
+		assert.Equal(t, "FR000123", document.BuyerReference)
+		assert.Equal(t, "DE89502109000218788068", document.PayeeIBAN)
+
+		if assert.Len(t, document.Lines, 2) {
+			assert.Equal(t, "Google Cloud", document.Lines[0].Name)
+			assert.Equal(t, einvoice.TaxCategoryReverseCharge, document.Lines[0].TaxCategory)
+			assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), document.Lines[0].PeriodEnd.UTC())
+
+			assert.Equal(t, "CREDIT", document.Lines[1].Name)
+			assert.Equal(t, float64(-1), document.Lines[1].Quantity)
+			assert.Equal(t, float64(100), document.Lines[1].UnitPrice)
+		}
+
+		for _, format := range []einvoice.Format{einvoice.FormatUBL, einvoice.FormatCII} {
+			_, err := einvoice.Generate(document, format)
+			assert.NoError(t, err, format)
+		}
+	})
+
+	t.Run("seller identified by its registration number", func(t *testing.T) {
+		setCompanyTaxIDs(t, "doitint", "", "12-3456789")
+
+		document, err := invoice.toEInvoiceDocument(&common.Entity{
+			PriorityID:      "IL000123",
+			PriorityCompany: "doitint",
+			Name:            "Client Ltd",
+			Country:         stringPtr("Israel"),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Empty(t, document.Seller.VATID)
+		assert.Equal(t, "12-3456789", document.Seller.LegalID)
+		assert.Equal(t, "12-3456789", document.Seller.EndpointID)
+		assert.Equal(t, "9959", document.Seller.EndpointSchemeID)
+		assert.Equal(t, einvoice.TaxCategoryNotSubject, document.Lines[0].TaxCategory)
+	})
+
+	t.Run("buyer outside of the EU not subject to VAT", func(t *testing.T) {
+		setCompanyTaxIDs(t, "doitint", "", "12-3456789")
+
+		for _, tt := range []struct {
+			country          string
+			vatNumber        string
+			endpointSchemeID string
+		}{
+			{country: "United Kingdom", vatNumber: "GB123456789", endpointSchemeID: "9932"},
+			{country: "Australia", vatNumber: "51824753556", endpointSchemeID: "0151"},
+		} {
+			document, err := invoice.toEInvoiceDocument(&common.Entity{
+				PriorityID:      "US000123",
+				PriorityCompany: "doitint",
+				Name:            "Client Ltd",
+				VATNumber:       stringPtr(tt.vatNumber),
+				Country:         stringPtr(tt.country),
+			})
+			if !assert.NoError(t, err, tt.country) {
+				continue
+			}
+
+			assert.Equal(t, einvoice.TaxCategoryNotSubject, document.Lines[0].TaxCategory, tt.country)
+			assert.Empty(t, document.Buyer.VATID, tt.country)
+			assert.Equal(t, tt.vatNumber, document.Buyer.EndpointID, tt.country)
+			assert.Equal(t, tt.endpointSchemeID, document.Buyer.EndpointSchemeID, tt.country)
+
+			for _, format := range []einvoice.Format{einvoice.FormatUBL, einvoice.FormatCII} {
+				_, err := einvoice.Generate(document, format)
+				assert.NoError(t, err, tt.country, format)
+			}
+		}
+	})
+
+	t.Run("seller without tax identifiers", func(t *testing.T) {
+		setCompanyTaxIDs(t, "doitde", "", "")
+
+		_, err := invoice.toEInvoiceDocument(frenchEntity)
+		assert.ErrorIs(t, err, ErrMissingSellerTaxID)
+	})
+
+	t.Run("unknown buyer country", func(t *testing.T) {
+		setCompanyTaxIDs(t, "doitde", "DE123456789", "")
+
+		_, err := invoice.toEInvoiceDocument(&common.Entity{PriorityCompany: "doitde", Country: stringPtr("Atlantis")})
+		assert.ErrorIs(t, err, ErrUnknownBuyerCountry)
+	})
+}
@@ -85,7 +85,7 @@ func MainHandler(ctx *gin.Context) {
 	openInvoices := make(map[string][]*priorityDomain.OpenInvoice)
 
 	for _, company := range priority.Companies {
-		companyOpenInvoices, err := getOpenInvoices(ctx, string(company), "")
+		companyOpenInvoices, err := GetOpenInvoices(ctx, string(company), "")
 		if err != nil {
 			ctx.AbortWithError(http.StatusInternalServerError, err)
 			return
@@ -625,7 +625,7 @@ func CustomerHandler(ctx *gin.Context, customerID string) {
 
 		openInvoices := make([]*priorityDomain.OpenInvoice, 0)
 
-		if entityOpenInvoices, err := getOpenInvoices(ctx, entity.PriorityCompany, entity.PriorityID); err == nil {
+		if entityOpenInvoices, err := GetOpenInvoices(ctx, entity.PriorityCompany, entity.PriorityID); err == nil {
 			for _, openInvoice := range entityOpenInvoices {
 				if openInvoice.ID == "" || strings.HasPrefix(openInvoice.ID, "RC") {
 					continue
@@ -740,7 +740,8 @@ func getEntityInvoices(ctx *gin.Context, entity *common.Entity) ([]*FullInvoice,
 	return invoices, nil
 }
 
-func getOpenInvoices(ctx *gin.Context, company, priorityID string) ([]*priorityDomain.OpenInvoice, error) {
+// GetOpenInvoices returns the open invoices and receipts of a Priority company, optionally filtered by customer
+func GetOpenInvoices(ctx context.Context, company, priorityID string) ([]*priorityDomain.OpenInvoice, error) {
 	params := make(map[string][]string)
 	if priorityID != "" {
 		params["$filter"] = []string{fmt.Sprintf("CUSTNAME eq '%s' and IVNUM gt ''", priorityID)}
@@ -2,10 +2,11 @@ package invoices
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,11 +18,9 @@ import (
 	"cloud.google.com/go/firestore"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/mailer"
-	"github.com/gin-gonic/gin"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/docs/v1"
 	drive "google.golang.org/api/drive/v3"
-	"google.golang.org/api/iterator"
 )
 
 type OverdueCustomer struct {
@@ -85,305 +84,243 @@ const (
 	senderEmail         string = "warren@doit-intl.com"
 )
 
-func NoticeToRemedy(ctx *gin.Context) {
-	fs := common.GetFirestoreClient(ctx)
+// ErrNoticeToRemedyNotRequired is returned when the customer is excluded from notices to remedy, or
+// none of its overdue invoices is left to notice
+var ErrNoticeToRemedyNotRequired = errors.New("notice to remedy not required")
+
+// SendNoticeToRemedy creates the notice to remedy document of a customer with invoices overdue by at
+// least minDaysOverdue days, emails it with the overdue invoices attached and marks the invoices as
+// noticed. It returns the email recipients.
+func SendNoticeToRemedy(ctx context.Context, customerID string, minDaysOverdue int) ([]string, error) {
+	customerOverdue, err := getOverdueCustomer(ctx, customerID, minDaysOverdue)
+	if err != nil {
+		return nil, err
+	}
 
 	data, err := secretmanager.AccessSecretLatestVersion(ctx, secretmanager.SecretGoogleDrive)
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	serviceConfig, err := google.JWTConfigFromJSON(data, drive.DriveFileScope, drive.DriveScope, drive.DriveAppdataScope, drive.DriveMetadataScope)
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
 	client := serviceConfig.Client(ctx)
 
 	driveService, err := drive.New(client)
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil, err
 	}
 
-	customers := getOverdueCustomers(ctx)
+	docsService, err := docs.New(client)
+	if err != nil {
+		return nil, err
+	}
 
-	totalPerDaySnap, err := fs.Collection("app").Doc("notice-to-remedy").Get(ctx)
-	totalPerDay, err := totalPerDaySnap.DataAt("totalPerDay")
-	// emails, err := totalPerDaySnap.DataAt("emails")
-	// senderEmailFs, err := totalPerDaySnap.DataAt("senderEmail")
+	foldersList, err := driveService.Files.List().Q(fmt.Sprintf("'%s' in parents", customerOverdue.SharedDriveFolderID)).Corpora("drive").SupportsAllDrives(true).IncludeItemsFromAllDrives(true).DriveId(teamDriveID).Do()
+	if err != nil {
+		return nil, err
+	}
 
-	counter := 0
+	needToCreateFolder := true
 
-	for _, customerOverdue := range customers {
-		if customerOverdue.skipRemedyBreach == false {
-			if counter < int(totalPerDay.(int64)) {
-				counter++
-			} else {
-				return
-			}
+	var folderID string
 
-			foldersList, err := driveService.Files.List().Q(fmt.Sprintf("'%s' in parents", customerOverdue.SharedDriveFolderID)).Corpora("drive").SupportsAllDrives(true).IncludeItemsFromAllDrives(true).DriveId(teamDriveID).Do()
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
+	for _, myFile := range foldersList.Files {
+		if myFile.Name == folderName {
+			needToCreateFolder = false
+			folderID = myFile.Id
+		}
+	}
 
-			needToCreateFolder := true
+	if needToCreateFolder {
+		folder, err := driveService.Files.Create(&drive.File{
+			MimeType:    folderMimeType,
+			Parents:     []string{customerOverdue.SharedDriveFolderID},
+			Name:        folderName,
+			TeamDriveId: teamDriveID,
+		}).SupportsAllDrives(true).Do()
+		if err != nil {
+			return nil, err
+		}
 
-			var folderID string
+		folderID = folder.Id
+	}
 
-			for _, myFile := range foldersList.Files {
-				if myFile.Name == folderName {
-					needToCreateFolder = false
-					folderID = myFile.Id
-				}
-			}
+	permission := &drive.Permission{
+		EmailAddress: serviceAccountEmail,
+		Role:         "writer",
+		Type:         "user",
+	}
 
-			if needToCreateFolder == true {
-				folder, err := driveService.Files.Create(&drive.File{
-					MimeType:    folderMimeType,
-					Parents:     []string{customerOverdue.SharedDriveFolderID},
-					Name:        folderName,
-					TeamDriveId: teamDriveID,
-				}).SupportsAllDrives(true).Do()
-				if err != nil {
-					fmt.Println(err)
-					return
-				}
+	f, err := driveService.Files.Copy(docTemplateID, &drive.File{
+		Parents:     []string{folderID},
+		TeamDriveId: teamDriveID,
+		Name:        fileName,
+	}).SupportsAllDrives(true).Do()
+	if err != nil {
+		return nil, err
+	}
 
-				folderID = folder.Id
-			}
+	if _, err := driveService.Permissions.Create(f.Id, permission).Do(); err != nil {
+		return nil, err
+	}
 
-			permission := &drive.Permission{
-				EmailAddress: serviceAccountEmail,
-				Role:         "writer",
-				Type:         "user",
-			}
+	totalString := ""
 
-			f, err := driveService.Files.Copy(docTemplateID, &drive.File{
-				Parents:     []string{folderID},
-				TeamDriveId: teamDriveID,
-				Name:        fileName,
-			}).SupportsAllDrives(true).Do()
-			if err != nil {
-				fmt.Println(err)
+	for k, v := range customerOverdue.TotalDebit {
+		debitNumber := numberToString(int(v), ',')
+		totalString += debitNumber + " " + k + " "
+	}
 
-				return
-			}
+	replacements := map[string]string{
+		"{{customer_legal_name}}":    customerOverdue.CustomerLegalName,
+		"{{customer_contact_name}}":  customerOverdue.CustomerContactName,
+		"{{customer_legal_address}}": customerOverdue.CustomerLegalAddress,
+		"{{date}}":                   customerOverdue.DateNow.Format("02/01/2006"),
+		"{{products_type}}":          strings.Join(customerOverdue.ProductType, ", "),
+		"{{unpaid_invoices}}":        strings.Join(customerOverdue.InvoicesID, ", "),
+		"{{payment_days}}":           fmt.Sprintf("%d", customerOverdue.PaymentDays),
+		"{{DEBIT}}":                  totalString,
+		"{{account_holder_name}}":    customerOverdue.HolderBankDetails.CompanyName,
+		"{{bankDetails}}":            getBankAccountInformation(customerOverdue.HolderBankDetails.WireTransferDetails),
+	}
 
-			driveService.Permissions.Create(f.Id, permission).Do()
-			fmt.Println(folderID)
+	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{}
 
-			docsService, err := docs.New(client)
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
+	for text, replaceText := range replacements {
+		batchUpdateRequest.Requests = append(batchUpdateRequest.Requests, &docs.Request{
+			ReplaceAllText: &docs.ReplaceAllTextRequest{
+				ContainsText: &docs.SubstringMatchCriteria{
+					MatchCase: false,
+					Text:      text,
+				},
+				ReplaceText: replaceText,
+			},
+		})
+	}
 
-			docID := f.Id
+	if _, err := docsService.Documents.BatchUpdate(f.Id, batchUpdateRequest).Do(); err != nil {
+		return nil, err
+	}
 
-			doc, err := docsService.Documents.Get(docID).Do()
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
+	newDoc, err := docsService.Documents.Get(f.Id).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	pdfFile, err := driveService.Files.Export(newDoc.DocumentId, pdfMimeType).Download()
+	if err != nil {
+		return nil, err
+	}
+	defer pdfFile.Body.Close()
 
-			//fmt.Println(doc.DocumentId)
-			fmt.Println(customerOverdue.CustomerID)
-			fmt.Println(customerOverdue.TotalDebit)
-			fmt.Println(customerOverdue.InvoicesID)
+	bodyPdfByte, err := io.ReadAll(pdfFile.Body)
+	if err != nil {
+		return nil, err
+	}
 
-			totalString := ""
+	encoded := base64.StdEncoding.EncodeToString(bodyPdfByte)
 
-			for k, v := range customerOverdue.TotalDebit {
-				debitNumber := numberToString(int(v), ',')
-				totalString += debitNumber + " " + k + " "
-			}
+	invoiceAttachments, err := getInvoiceAttachments(ctx, customerOverdue.PdfUrls)
+	if err != nil {
+		return nil, err
+	}
 
-			fmt.Println(totalString)
-			fmt.Println("=============================")
-			batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-				Requests: []*docs.Request{
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{customer_legal_name}}",
-							},
-							ReplaceText: customerOverdue.CustomerLegalName,
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{customer_contact_name}}",
-							},
-							ReplaceText: customerOverdue.CustomerContactName,
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{customer_legal_address}}",
-							},
-							ReplaceText: customerOverdue.CustomerLegalAddress,
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{date}}",
-							},
-							ReplaceText: customerOverdue.DateNow.Format("02/01/2006"),
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{products_type}}",
-							},
-							ReplaceText: strings.Join(customerOverdue.ProductType, ", "),
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{unpaid_invoices}}",
-							},
-							ReplaceText: strings.Join(customerOverdue.InvoicesID, ", "),
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{payment_days}}",
-							},
-							ReplaceText: fmt.Sprintf("%d", customerOverdue.PaymentDays),
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{DEBIT}}",
-							},
-							ReplaceText: totalString,
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{account_holder_name}}",
-							},
-							ReplaceText: customerOverdue.HolderBankDetails.CompanyName,
-						},
-					},
-					&docs.Request{
-						ReplaceAllText: &docs.ReplaceAllTextRequest{
-							ContainsText: &docs.SubstringMatchCriteria{
-								MatchCase: false,
-								Text:      "{{bankDetails}}",
-							},
-							ReplaceText: getBankAccountInformation(customerOverdue.HolderBankDetails.WireTransferDetails),
-						},
-					},
-				},
-			}
+	body := getNoticeToRemedyBody(newDoc, customerOverdue)
 
-			_, err = docsService.Documents.BatchUpdate(doc.DocumentId, batchUpdateRequest).Do()
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
+	tos, err := sendEmailToCustomer(ctx, body, encoded, customerOverdue, invoiceAttachments)
+	if err != nil {
+		return nil, err
+	}
 
-			newDoc, err := docsService.Documents.Get(doc.DocumentId).Do()
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
+	if common.Production {
+		if err := markInvoice(ctx, customerOverdue); err != nil {
+			return nil, err
+		}
+	}
 
-			pdfFile, err := driveService.Files.Export(newDoc.DocumentId, pdfMimeType).Download()
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
+	return tos, nil
+}
 
-			bodyPdfByte, err := io.ReadAll(pdfFile.Body)
-			encoded := base64.StdEncoding.EncodeToString([]byte(bodyPdfByte))
+func getInvoiceAttachments(ctx context.Context, pdfUrls []*ExternalFile) ([]mailer.InvoiceAttachments, error) {
+	var invoiceAttachments []mailer.InvoiceAttachments
 
-			var invoiceAttachments []mailer.InvoiceAttachments
+	client := &http.Client{}
 
-			for _, exFile := range customerOverdue.PdfUrls {
-				if exFile.URL != nil {
-					client := &http.Client{}
-					req, err := http.NewRequest("GET", *exFile.URL, nil)
-					req.Header.Set("User-Agent", "Mozilla/5.0")
+	for _, exFile := range pdfUrls {
+		if exFile.URL == nil || exFile.Key == nil {
+			continue
+		}
 
-					resp, err := client.Do(req)
-					if err != nil {
-						log.Fatalln(err)
-					}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, *exFile.URL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-					defer resp.Body.Close()
-					bodyInvoicePdf, err := io.ReadAll(resp.Body)
-					encodedFile := base64.StdEncoding.EncodeToString([]byte(bodyInvoicePdf))
-					invoiceAttachments = append(invoiceAttachments, mailer.InvoiceAttachments{
-						PdfFile: encodedFile,
-						Key:     *exFile.Key,
-					})
-				}
-			}
+		req.Header.Set("User-Agent", "Mozilla/5.0")
 
-			var body string
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
 
-			removeCustomerDetails := 4
+		bodyInvoicePdf, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 
-			for _, paragraph := range newDoc.Body.Content {
-				if paragraph != nil && paragraph.Paragraph != nil {
-					for i := 0; i < len(paragraph.Paragraph.Elements); i++ {
-						removeCustomerDetails--
-						if removeCustomerDetails <= 0 {
-							inputText := paragraph.Paragraph.Elements[i].TextRun.Content
-							if strings.Contains(paragraph.Paragraph.Elements[i].TextRun.Content, "following invoice numbers:") {
-								index := strings.Index(inputText, "numbers:") + 9
+		if err != nil {
+			return nil, err
+		}
 
-								inputTextFmt := inputText[:index]
-								for _, invoiceID := range customerOverdue.InvoicesID {
-									inputTextFmt += "<a href='https://console.doit.com/customers/" + customerOverdue.CustomerID + "/invoices/" + customerOverdue.EntityID + "/" + invoiceID + "' >" + invoiceID + "</a>, "
-								}
+		invoiceAttachments = append(invoiceAttachments, mailer.InvoiceAttachments{
+			PdfFile: base64.StdEncoding.EncodeToString(bodyInvoicePdf),
+			Key:     *exFile.Key,
+		})
+	}
 
-								inputText = inputTextFmt[:len(inputTextFmt)-2]
-							}
+	return invoiceAttachments, nil
+}
 
-							body += inputText
+// getNoticeToRemedyBody renders the notice document as the email body, without the customer details
+// header and with links to the unpaid invoices
+func getNoticeToRemedyBody(doc *docs.Document, customerOverdue *OverdueCustomer) string {
+	var body string
+
+	removeCustomerDetails := 4
+
+	for _, paragraph := range doc.Body.Content {
+		if paragraph != nil && paragraph.Paragraph != nil {
+			for i := 0; i < len(paragraph.Paragraph.Elements); i++ {
+				removeCustomerDetails--
+				if removeCustomerDetails <= 0 && paragraph.Paragraph.Elements[i].TextRun != nil {
+					inputText := paragraph.Paragraph.Elements[i].TextRun.Content
+					if strings.Contains(inputText, "following invoice numbers:") {
+						index := strings.Index(inputText, "numbers:") + 9
+
+						inputTextFmt := inputText[:index]
+						for _, invoiceID := range customerOverdue.InvoicesID {
+							inputTextFmt += "<a href='https://console.doit.com/customers/" + customerOverdue.CustomerID + "/invoices/" + customerOverdue.EntityID + "/" + invoiceID + "' >" + invoiceID + "</a>, "
 						}
-					}
 
-					if removeCustomerDetails <= 0 {
-						body += "<br/>"
+						inputText = inputTextFmt[:len(inputTextFmt)-2]
 					}
+
+					body += inputText
 				}
 			}
 
-			if common.Production {
-				sendEmailToCustomer(ctx, body, encoded, customerOverdue, invoiceAttachments)
-				markInvoice(ctx, customerOverdue)
+			if removeCustomerDetails <= 0 {
+				body += "<br/>"
 			}
 		}
 	}
+
+	return body
 }
 
-func sendEmailToCustomer(ctx *gin.Context, body string, pdfFileEncoded string, customerObj OverdueCustomer, pdfInvoices []mailer.InvoiceAttachments) {
+func sendEmailToCustomer(ctx context.Context, body string, pdfFileEncoded string, customerObj *OverdueCustomer, pdfInvoices []mailer.InvoiceAttachments) ([]string, error) {
 	fs := common.GetFirestoreClient(ctx)
 
 	ccs := []string{"vadim@doit.com", "warren@doit-intl.com", "noam@doit-intl.com", "dina@doit-intl.com", "avi.i@doit-intl.com"}
@@ -391,16 +328,16 @@ func sendEmailToCustomer(ctx *gin.Context, body string, pdfFileEncoded string, c
 	if customerObj.AccountManagerRef != nil {
 		aManger, err := fs.Collection("accountManagers").Doc(customerObj.AccountManagerRef.ID).Get(ctx)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return nil, err
 		}
 
-		email, _ := aManger.DataAt("email")
-		ccs = append(ccs, email.(string))
+		if email, err := aManger.DataAt("email"); err == nil {
+			if email, ok := email.(string); ok && email != "" {
+				ccs = append(ccs, email)
+			}
+		}
 	}
 
-	fmt.Println(customerObj.domain)
-
 	now := time.Now().UTC()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 	todayString := today.Format("2006-01-02")
@@ -409,224 +346,195 @@ func sendEmailToCustomer(ctx *gin.Context, body string, pdfFileEncoded string, c
 	sn := &mailer.SimpleNotification{
 		Subject:    subject,
 		Body:       body,
-		CCs:        ccs, //ccs
+		CCs:        ccs,
 		Attachment: pdfFileEncoded,
 		Categories: categories,
 	}
 
-	tos := []string{customerObj.CustomerContactEmail}
+	var tos []string
+	if customerObj.CustomerContactEmail != "" {
+		tos = append(tos, customerObj.CustomerContactEmail)
+	}
+
 	for _, userEmail := range customerObj.UsersEmail {
 		if !slice.Contains(tos, userEmail) {
 			tos = append(tos, userEmail)
 		}
 	}
 
+	if len(tos) == 0 {
+		return nil, fmt.Errorf("customer %s has no billing contacts", customerObj.CustomerID)
+	}
+
 	if common.Production {
 		mailer.SendSimpleEmailWithTemplate(sn, tos, senderName, senderEmail, pdfInvoices, mailer.Config.DynamicTemplates.NoticeToRemedy)
 	}
+
+	return tos, nil
 }
 
-func markInvoice(ctx *gin.Context, customerOverdue OverdueCustomer) {
+func markInvoice(ctx context.Context, customerOverdue *OverdueCustomer) error {
 	fs := common.GetFirestoreClient(ctx)
 
 	for _, docID := range customerOverdue.InvoicesDocID {
-		fmt.Println(docID)
-		fs.Collection("invoices").Doc(docID).Set(ctx, map[string]interface{}{
+		if _, err := fs.Collection("invoices").Doc(docID).Set(ctx, map[string]interface{}{
 			"isNoticeToRemedySent": true,
-		}, firestore.MergeAll)
+		}, firestore.MergeAll); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
-func getOverdueCustomers(ctx *gin.Context) map[string]OverdueCustomer {
+// getOverdueCustomer gathers the unpaid invoices of a customer for its notice to remedy. The notice is
+// required when at least one invoice overdue by minDaysOverdue days was not noticed yet.
+func getOverdueCustomer(ctx context.Context, customerID string, minDaysOverdue int) (*OverdueCustomer, error) {
 	fs := common.GetFirestoreClient(ctx)
 
-	var snapshotInvoicesID = make(map[string][]string)
-
-	var usersEmail = make(map[string][]string)
-
-	var customersOverdueInovices = make(map[string]OverdueCustomer)
-
-	var totalDebits = make(map[string]map[string]float64)
-
 	requiredPermissions := []string{string(common.PermissionBillingProfiles), string(common.PermissionInvoices)}
 
 	companiesSnap, err := fs.Collection("app").Doc("priority-v2").Get(ctx)
 	if err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, err)
-		return nil
+		return nil, err
 	}
 
 	var companies CompaniesBank
 
 	if err := companiesSnap.DataTo(&companies); err != nil {
-		ctx.AbortWithError(http.StatusInternalServerError, err)
-		return nil
+		return nil, err
 	}
 
-	now := time.Now()
-	overdueDate := now.AddDate(0, 0, -90)
-
-	iter := fs.Collection("invoices").Where("PAID", "==", false).Where("PAYDATE", "<=", overdueDate).Where("CANCELED", "==", false).Documents(ctx)
-	defer iter.Stop()
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-
-		if err != nil {
-			fmt.Println(err)
-			return nil
-		}
+	customer, err := fs.Collection("customers").Doc(customerID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		var mainInvoice FullInvoice
-		if err := doc.DataTo(&mainInvoice); err != nil {
-			fmt.Println(err)
-			continue
+	if skipRemedyBreach, err := customer.DataAt("skipRemedyBreach"); err == nil {
+		if skip, ok := skipRemedyBreach.(bool); ok && skip {
+			return nil, ErrNoticeToRemedyNotRequired
 		}
+	}
 
-		if mainInvoice.Total > 5 && mainInvoice.Debit > 0 && mainInvoice.IsNoticeToRemedySent != true && snapshotInvoicesID[mainInvoice.Customer.ID] == nil {
-			customerEntitie, err := fs.Collection("entities").Doc(mainInvoice.Entity.ID).Get(ctx)
-			customer, err := fs.Collection("customers").Doc(mainInvoice.Customer.ID).Get(ctx)
-
-			invoicesSnaps, err := fs.Collection("invoices").Where("customer", "==", customer.Ref).Where("PAID", "==", false).Where("PAYDATE", "<=", now.AddDate(0, 0, -1)).Where("CANCELED", "==", false).Documents(ctx).GetAll()
-			if err != nil {
-				fmt.Println(err)
-			}
-
-			for _, invoiceSnap := range invoicesSnaps {
-				var invoice FullInvoice
-				if err := invoiceSnap.DataTo(&invoice); err != nil {
-					fmt.Println(err)
-					continue
-				}
+	now := time.Now()
+	noticeDate := now.AddDate(0, 0, -minDaysOverdue)
 
-				if invoice.Total > 0 && invoice.Debit > 0 {
-					snapshotInvoicesID[invoice.Customer.ID] = append(snapshotInvoicesID[invoice.Customer.ID], invoiceSnap.Ref.ID)
+	invoicesSnaps, err := fs.Collection("invoices").Where("customer", "==", customer.Ref).Where("PAID", "==", false).Where("PAYDATE", "<=", now.AddDate(0, 0, -1)).Where("CANCELED", "==", false).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
 
-					if err != nil {
-						fmt.Println(err)
-						return nil
-					}
+	customerOverdue := &OverdueCustomer{
+		CustomerID: customer.Ref.ID,
+		DateNow:    now,
+		TotalDebit: make(map[string]float64),
+	}
 
-					users, err := common.GetCustomerUsersWithPermissions(ctx, fs, customer.Ref, requiredPermissions)
-					if err != nil {
-						fmt.Println(err)
-						return nil
-					}
+	noticeRequired := false
 
-					for _, user := range users {
-						usersEmail[customer.Ref.ID] = append(usersEmail[customer.Ref.ID], user.Email)
-					}
+	for _, invoiceSnap := range invoicesSnaps {
+		var invoice FullInvoice
+		if err := invoiceSnap.DataTo(&invoice); err != nil {
+			return nil, err
+		}
 
-					usersEmail[invoice.Customer.ID] = unique(usersEmail[invoice.Customer.ID])
+		if invoice.Total <= 0 || invoice.Debit <= 0 {
+			continue
+		}
 
-					customerLegalName, _ := customerEntitie.DataAt("name")
-					customerContactName, _ := customerEntitie.DataAt("contact.name")
-					customerContactEmail, _ := customerEntitie.DataAt("contact.email")
+		if invoice.Total > 5 && !invoice.IsNoticeToRemedySent && !invoice.PayDate.After(noticeDate) {
+			noticeRequired = true
+		}
 
-					address := ""
-					if country, err := customer.DataAt("enrichment.geo.country"); err == nil && country != nil {
-						address += country.(string) + " "
-					}
+		var bDetails BankDetails
 
-					if city, err := customer.DataAt("enrichment.geo.city"); err == nil && city != nil {
-						address += city.(string) + " "
-					}
-
-					if streetName, err := customer.DataAt("enrichment.geo.streetName"); err == nil && streetName != nil {
-						address += streetName.(string) + " "
-					}
+		for index := 0; index < len(companies.Compnies); index++ {
+			if companies.Compnies[index].CompanyID == invoice.Company {
+				bDetails = companies.Compnies[index]
+			}
+		}
 
-					if streetNumber, err := customer.DataAt("enrichment.geo.streetNumber"); err == nil && streetNumber != nil {
-						address += streetNumber.(string)
-					}
+		for _, p := range invoice.Products {
+			if p != "other" {
+				customerOverdue.ProductType = append(customerOverdue.ProductType, common.FormatAssetType(p))
+			}
+		}
 
-					sharedDriveFolderID, err := customer.DataAt("sharedDriveFolderId")
-					if sharedDriveFolderID == nil {
-						sharedDriveFolderID = ""
-					}
+		customerOverdue.InvoicesDocID = append(customerOverdue.InvoicesDocID, invoiceSnap.Ref.ID)
+		customerOverdue.InvoicesID = append(customerOverdue.InvoicesID, invoice.ID)
+		customerOverdue.PdfUrls = append(customerOverdue.PdfUrls, invoice.ExternalFilesSubForm...)
+		customerOverdue.PaymentDays = int(invoice.PayDate.Sub(invoice.Date).Hours() / 24)
+		customerOverdue.DEBIT += invoice.Debit
+		customerOverdue.TotalDebit[invoice.Symbol] += invoice.Debit
+		customerOverdue.SYMBOL = invoice.Symbol
+		customerOverdue.AccountHolderName = invoice.Company
+		customerOverdue.HolderBankDetails = bDetails
+
+		if invoice.Entity != nil {
+			customerOverdue.EntityID = invoice.Entity.ID
+		}
+	}
 
-					skipRemedyBreach, err := customer.DataAt("skipRemedyBreach")
-					if skipRemedyBreach == nil {
-						skipRemedyBreach = false
-					}
+	if !noticeRequired {
+		return nil, ErrNoticeToRemedyNotRequired
+	}
 
-					var bDetails BankDetails
+	customerOverdue.InvoicesID = unique(customerOverdue.InvoicesID)
+	customerOverdue.ProductType = unique(customerOverdue.ProductType)
 
-					for index := 0; index < len(companies.Compnies); index++ {
-						if companies.Compnies[index].CompanyID == invoice.Company {
-							bDetails = companies.Compnies[index]
-						}
-					}
+	if customerOverdue.EntityID == "" {
+		return nil, fmt.Errorf("customer %s overdue invoices have no billing profile", customerID)
+	}
 
-					estPaydate := int(invoice.PayDate.Sub(invoice.Date).Hours() / 24)
+	customerEntity, err := fs.Collection("entities").Doc(customerOverdue.EntityID).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-					var formatProductsName []string
+	customerOverdue.CustomerLegalName = stringDataAt(customerEntity, "name")
+	customerOverdue.CustomerContactName = stringDataAt(customerEntity, "contact.name")
+	customerOverdue.CustomerContactEmail = stringDataAt(customerEntity, "contact.email")
 
-					for _, p := range invoice.Products {
-						if p != "other" {
-							formatProductsName = append(formatProductsName, common.FormatAssetType(p))
-						}
-					}
+	address := ""
+	for _, field := range []string{"enrichment.geo.country", "enrichment.geo.city", "enrichment.geo.streetName", "enrichment.geo.streetNumber"} {
+		if value := stringDataAt(customer, field); value != "" {
+			address += value + " "
+		}
+	}
 
-					var accountManagerRef *firestore.DocumentRef
+	customerOverdue.CustomerLegalAddress = strings.TrimSpace(address)
+	customerOverdue.SharedDriveFolderID = stringDataAt(customer, "sharedDriveFolderId")
+	customerOverdue.domain = stringDataAt(customer, "primaryDomain")
 
-					amID, err := customer.DataAt("accountManagers.doit.account_manager.ref")
-					if err != nil {
-						fmt.Println(err)
+	if amRef, err := customer.DataAt("accountManagers.doit.account_manager.ref"); err == nil {
+		if ref, ok := amRef.(*firestore.DocumentRef); ok {
+			customerOverdue.AccountManagerRef = ref
+		}
+	}
 
-						accountManagerRef = nil
-					}
+	users, err := common.GetCustomerUsersWithPermissions(ctx, fs, customer.Ref, requiredPermissions)
+	if err != nil {
+		return nil, err
+	}
 
-					if amID != nil {
-						accountManagerRef = amID.(*firestore.DocumentRef)
-					}
+	for _, user := range users {
+		customerOverdue.UsersEmail = append(customerOverdue.UsersEmail, user.Email)
+	}
 
-					primaryDomain, err := customer.DataAt("primaryDomain")
+	customerOverdue.UsersEmail = unique(customerOverdue.UsersEmail)
 
-					if totalDebits[customer.Ref.ID] == nil {
-						totalDebits[customer.Ref.ID] = make(map[string]float64)
-					}
+	return customerOverdue, nil
+}
 
-					totalDebits[customer.Ref.ID][invoice.Symbol] += invoice.Debit
-					//fmt.Println(invoice.Debit)
-					allPdfUrls := append(customersOverdueInovices[customer.Ref.ID].PdfUrls, invoice.ExternalFilesSubForm...)
-					allInvoices := append(customersOverdueInovices[customer.Ref.ID].InvoicesID, invoice.ID)
-					productsType := append(customersOverdueInovices[customer.Ref.ID].ProductType, formatProductsName...)
-
-					customerData := OverdueCustomer{
-						CustomerID:           customer.Ref.ID,
-						CustomerLegalName:    customerLegalName.(string),
-						CustomerContactName:  customerContactName.(string),
-						CustomerContactEmail: customerContactEmail.(string),
-						UsersEmail:           usersEmail[customer.Ref.ID],
-						CustomerLegalAddress: address,
-						DateNow:              time.Now(),
-						InvoicesID:           unique(allInvoices),
-						InvoicesDocID:        snapshotInvoicesID[customer.Ref.ID],
-						ProductType:          unique(productsType),
-						PaymentDays:          estPaydate,
-						DEBIT:                customersOverdueInovices[customer.Ref.ID].DEBIT + invoice.Debit,
-						TotalDebit:           totalDebits[customer.Ref.ID],
-						SYMBOL:               invoice.Symbol,
-						AccountHolderName:    invoice.Company,
-						HolderBankDetails:    bDetails,
-						SharedDriveFolderID:  sharedDriveFolderID.(string),
-						AccountManagerRef:    accountManagerRef,
-						skipRemedyBreach:     skipRemedyBreach.(bool),
-						domain:               primaryDomain.(string),
-						PdfUrls:              allPdfUrls,
-						EntityID:             invoice.Entity.ID,
-					}
-					customersOverdueInovices[customer.Ref.ID] = customerData
-				}
-			}
-		}
+func stringDataAt(docSnap *firestore.DocumentSnapshot, path string) string {
+	value, err := docSnap.DataAt(path)
+	if err != nil {
+		return ""
 	}
 
-	return customersOverdueInovices
+	s, _ := value.(string)
+
+	return s
 }
 
 func unique(slice []string) []string {
@@ -20,6 +20,8 @@ import (
 	csm_service "github.com/doitintl/hello/scheduled-tasks/csmengagement/service"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
+	events "github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 	rsDomain "github.com/doitintl/notificationcenter/domain"
 	notificationcenter "github.com/doitintl/notificationcenter/pkg"
 	recipientsService "github.com/doitintl/notificationcenter/service"
@@ -30,6 +32,18 @@ type NotificationTask struct {
 	EntityID   string `json:"entity_id"`
 }
 
+// WebhookInvoice is the payload of the invoice issued webhook event
+type WebhookInvoice struct {
+	ID          string   `json:"id"`
+	InvoiceDate int64    `json:"invoiceDate"`
+	DueDate     int64    `json:"dueDate"`
+	Products    []string `json:"products"`
+	TotalAmount float64  `json:"totalAmount"`
+	Currency    string   `json:"currency"`
+	EntityID    string   `json:"entityId"`
+	URL         string   `json:"url"`
+}
+
 type InvoiceNotificationData struct {
 	ID       string `json:"id"`
 	Products string `json:"products"`
@@ -104,7 +118,7 @@ func NotificationsHandler(ctx *gin.Context) {
 }
 
 // func NotificationsWorker(ctx *gin.Context, t NotificationTask) {
-func NotificationsWorker(ctx *gin.Context) {
+func NotificationsWorker(ctx *gin.Context, eventDispatcher dispatch.Dispatcher) {
 	l := logger.FromContext(ctx)
 
 	var t NotificationTask
@@ -148,6 +162,7 @@ func NotificationsWorker(ctx *gin.Context) {
 
 	batch := fs.Batch()
 	invoices := make([]InvoiceNotificationData, 0)
+	webhookInvoices := make([]WebhookInvoice, 0)
 	attachments := make([]notificationcenter.EmailAttachment, 0)
 
 	for _, docSnap := range invoiceDocSnaps {
@@ -201,6 +216,17 @@ func NotificationsWorker(ctx *gin.Context) {
 			Amount:   formatAmount(printer, invoice.TotalTax, invoice.Symbol),
 		})
 
+		webhookInvoices = append(webhookInvoices, WebhookInvoice{
+			ID:          invoice.ID,
+			InvoiceDate: invoice.Date.UnixMilli(),
+			DueDate:     invoice.PayDate.UnixMilli(),
+			Products:    invoice.Products,
+			TotalAmount: invoice.TotalTax,
+			Currency:    invoice.Currency,
+			EntityID:    t.EntityID,
+			URL:         linkURL,
+		})
+
 		batch.Update(docSnap.Ref, []firestore.Update{
 			{FieldPath: []string{"notification", "sent"}, Value: true},
 		})
@@ -248,6 +274,12 @@ func NotificationsWorker(ctx *gin.Context) {
 		return
 	}
 
+	for _, invoice := range webhookInvoices {
+		if err := eventDispatcher.Dispatch(ctx, invoice, customerRef, t.EntityID, events.InvoiceIssued); err != nil {
+			l.Warningf("unable to dispatch %s for event %s: %s", invoice.ID, events.InvoiceIssued, err)
+		}
+	}
+
 	isFirstInvoice := len(prevInvoiceDocSnaps) == 0
 	if isFirstInvoice {
 		csmService := csm_service.NewService(ctx, fs, nil)
@@ -17,6 +17,36 @@ type Dashboards struct {
 	mock.Mock
 }
 
+// CreateUserDashboard provides a mock function with given fields: ctx, userID, customerID, d
+func (_m *Dashboards) CreateUserDashboard(ctx context.Context, userID string, customerID string, d *dashboard.Dashboard) (*dashboard.Dashboard, error) {
+	ret := _m.Called(ctx, userID, customerID, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUserDashboard")
+	}
+
+	var r0 *dashboard.Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *dashboard.Dashboard) (*dashboard.Dashboard, error)); ok {
+		return rf(ctx, userID, customerID, d)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *dashboard.Dashboard) *dashboard.Dashboard); ok {
+		r0 = rf(ctx, userID, customerID, d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dashboard.Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *dashboard.Dashboard) error); ok {
+		r1 = rf(ctx, userID, customerID, d)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCustomerDashboardsWithCloudReports provides a mock function with given fields: ctx, customerID
 func (_m *Dashboards) GetCustomerDashboardsWithCloudReports(ctx context.Context, customerID string) ([]*dashboard.Dashboard, error) {
 	ret := _m.Called(ctx, customerID)
@@ -137,6 +167,36 @@ func (_m *Dashboards) GetDashboardsWithPaths(ctx context.Context, paths []string
 	return r0, r1
 }
 
+// ListCustomerDashboards provides a mock function with given fields: ctx, customerID
+func (_m *Dashboards) ListCustomerDashboards(ctx context.Context, customerID string) ([]*dashboard.Dashboard, error) {
+	ret := _m.Called(ctx, customerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCustomerDashboards")
+	}
+
+	var r0 []*dashboard.Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*dashboard.Dashboard, error)); ok {
+		return rf(ctx, customerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*dashboard.Dashboard); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dashboard.Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveDashboardWidget provides a mock function with given fields: ctx, dashboardRef, widget
 func (_m *Dashboards) RemoveDashboardWidget(ctx context.Context, dashboardRef *firestore.DocumentRef, widget dashboard.DashboardWidget) error {
 	ret := _m.Called(ctx, dashboardRef, widget)
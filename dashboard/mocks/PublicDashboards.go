@@ -75,6 +75,36 @@ func (_m *PublicDashboards) GetDashboardsWithCloudReportsCustomerIDs(ctx context
 	return r0, r1
 }
 
+// ListCustomerDashboards provides a mock function with given fields: ctx, customerID
+func (_m *PublicDashboards) ListCustomerDashboards(ctx context.Context, customerID string) ([]*dashboard.Dashboard, error) {
+	ret := _m.Called(ctx, customerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCustomerDashboards")
+	}
+
+	var r0 []*dashboard.Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*dashboard.Dashboard, error)); ok {
+		return rf(ctx, customerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*dashboard.Dashboard); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dashboard.Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateReportWidgetDashboardsWidgetState provides a mock function with given fields: ctx, customerID, reportID, state
 func (_m *PublicDashboards) UpdateReportWidgetDashboardsWidgetState(ctx context.Context, customerID string, reportID string, state dashboard.WidgetRefreshState) error {
 	ret := _m.Called(ctx, customerID, reportID, state)
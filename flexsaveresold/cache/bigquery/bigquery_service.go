@@ -51,6 +51,7 @@ type BigQueryServiceInterface interface {
 	CheckActiveBillingTableExists(ctx context.Context, chCustomerID string) error
 	GetCustomerSavingsPlanData(ctx context.Context, customerID string) ([]types.SavingsPlanData, error)
 	GetSharedPayerOndemandMonthlyData(ctx context.Context, customerID string, startDate string, endDate string) ([]types.SharedPayerOndemandMonthlyData, error)
+	GetHourlyComputeUsage(ctx context.Context, customerID string, startDate string, endDate string) ([]types.HourlyComputeUsage, error)
 	GetPayerDailySpendSummary(DailyBQParams) (map[string]*fspkg.FlexsaveMonthSummary, error)
 	GetCustomerCredits(ctx context.Context, customerID string, now time.Time) CreditsResult
 	GetCustomerSavings(params BigQueryParams, query string, monthlySavings chan map[string]float64, errChan chan error)
@@ -458,6 +459,38 @@ func (s *BigQueryService) GetSharedPayerOndemandMonthlyData(ctx context.Context,
 	return sharedPayerMonthlyOndemandData, nil
 }
 
+// GetHourlyComputeUsage returns the flexsave eligible EC2 usage of every hour, split into the usage
+// paid on demand and the usage that was covered by savings plans
+func (s *BigQueryService) GetHourlyComputeUsage(ctx context.Context, customerID string, startDate string, endDate string) ([]types.HourlyComputeUsage, error) {
+	query := s.BigqueryClient.Query(BuildHourlyComputeUsageQuery(customerID, startDate, endDate))
+
+	s.applyLabels(query, customerID, "get-hourly-compute-usage")
+
+	iter, err := s.QueryHandler.Read(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var hourlyUsage []types.HourlyComputeUsage
+
+	for {
+		var usage types.HourlyComputeUsage
+
+		err = iter.Next(&usage)
+		if err == iterator.Done {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		hourlyUsage = append(hourlyUsage, usage)
+	}
+
+	return hourlyUsage, nil
+}
+
 func isSystemKeyLabelsPresentUDF() string {
 	return `CREATE TEMP FUNCTION
 	isKeyFromSystemLabelsPresent(labels ARRAY<STRUCT<key STRING,
@@ -496,6 +529,20 @@ func BuildSharedPayerOndemandMonthlyQuery(customerID string, startDate string, e
 	FROM res WHERE is_flexsave_eligibility_label_present is False GROUP BY month_year, year, month ORDER BY year, month`
 }
 
+func BuildHourlyComputeUsageQuery(customerID string, startDate string, endDate string) string {
+	return isSystemKeyLabelsPresentUDF() + ` WITH res AS ( SELECT cost, cost_type, TIMESTAMP_TRUNC(usage_date_time, HOUR) AS hour,
+	isKeyFromSystemLabelsPresent(system_labels, "cmp/flexsave_eligibility") AS is_flexsave_eligibility_label_present
+	FROM ` + getCustomerDataset(customerID) + `.` + getCustomerTable(customerID) +
+		` WHERE cost_type IN ("Usage", "SavingsPlanCoveredUsage") AND operation LIKE 'RunInstances%'
+	AND (sku_description LIKE '%Box%')
+	AND NOT REGEXP_CONTAINS(sku_description, ` + getIneligibleSKUsRegEx() + `)
+	AND service_id = "AmazonEC2" AND DATE(usage_date_time) BETWEEN "` + startDate + `" AND "` + endDate + `"
+	AND DATE(export_time) BETWEEN "` + startDate + `" AND "` + endDate + `")
+	SELECT hour, IFNULL(SUM(IF(cost_type = "Usage", cost, 0)), 0) AS ondemand_cost,
+	IFNULL(SUM(IF(cost_type = "SavingsPlanCoveredUsage", cost, 0)), 0) AS covered_cost
+	FROM res WHERE is_flexsave_eligibility_label_present is False GROUP BY hour ORDER BY hour`
+}
+
 func getIneligibleSKUsRegEx() string {
 	return `r"(\:m1\.|\:m2\.|\:m3\.|\:c1\.|\:c3\.|\:i2\.|\:cr1\.|\:r3\.|\:hs1\.|\:g2\.|\:t1\.)"`
 }
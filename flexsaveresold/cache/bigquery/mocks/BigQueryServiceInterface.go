@@ -144,6 +144,35 @@ func (_m *BigQueryServiceInterface) GetCustomerSavingsPlanData(ctx context.Conte
 	return r0, r1
 }
 
+// GetHourlyComputeUsage provides a mock function with given fields: ctx, customerID, startDate, endDate
+func (_m *BigQueryServiceInterface) GetHourlyComputeUsage(ctx context.Context, customerID string, startDate string, endDate string) ([]types.HourlyComputeUsage, error) {
+	ret := _m.Called(ctx, customerID, startDate, endDate)
+
+	var r0 []types.HourlyComputeUsage
+
+	var r1 error
+
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]types.HourlyComputeUsage, error)); ok {
+		return rf(ctx, customerID, startDate, endDate)
+	}
+
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []types.HourlyComputeUsage); ok {
+		r0 = rf(ctx, customerID, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.HourlyComputeUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, customerID, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPayerDailySpendSummary provides a mock function with given fields: _a0
 func (_m *BigQueryServiceInterface) GetPayerDailySpendSummary(_a0 bq.DailyBQParams) (map[string]*pkg.FlexsaveMonthSummary, error) {
 	ret := _m.Called(_a0)
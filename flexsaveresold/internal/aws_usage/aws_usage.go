@@ -43,10 +43,6 @@ func NewAWSUsageService(log logger.Provider, conn *connection.Connection, bigque
 func (s *awsUsageService) GetMonthlyOnDemand(ctx context.Context, customerID string, applicableMonths []string) (map[string]float64, error) {
 	applicableSpendByMonth := map[string]float64{}
 
-	log := s.loggerProvider(ctx)
-
-	var sharedPayerOndemandMonthlyData []types.SharedPayerOndemandMonthlyData
-
 	t := time.Now().UTC()
 
 	endDateTime := t.AddDate(0, 0, -consts.DaysToOffset)
@@ -56,20 +52,9 @@ func (s *awsUsageService) GetMonthlyOnDemand(ctx context.Context, customerID str
 	startDate := startDateTime.Format("2006-01-02")
 	endDate := endDateTime.Format("2006-01-02")
 
-	customerRef := s.customerDAL.GetRef(ctx, customerID)
-
-	var queryID string
-
-	if err := s.bigqueryInterface.CheckActiveBillingTableExists(ctx, customerID); err == bigQueryCache.ErrNoActiveTable {
-		chtCustomerID, err := s.cloudHealthDAL.GetCustomerCloudHealthID(ctx, customerRef)
-		if err != nil {
-			return applicableSpendByMonth, err
-		}
-
-		log.Infof("cht customerId %s", chtCustomerID)
-		queryID = chtCustomerID
-	} else {
-		queryID = customerID
+	queryID, err := s.getQueryID(ctx, customerID)
+	if err != nil {
+		return applicableSpendByMonth, err
 	}
 
 	sharedPayerOndemandMonthlyData, err := s.bigqueryInterface.GetSharedPayerOndemandMonthlyData(ctx, queryID, startDate, endDate)
@@ -85,3 +70,44 @@ func (s *awsUsageService) GetMonthlyOnDemand(ctx context.Context, customerID str
 
 	return monthlyData, err
 }
+
+// GetHourlyUsage returns the hourly EC2 usage of the customer between the given dates, both inclusive
+func (s *awsUsageService) GetHourlyUsage(ctx context.Context, customerID string, from time.Time, to time.Time) ([]types.HourlyComputeUsage, error) {
+	queryID, err := s.getQueryID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bigqueryInterface.GetHourlyComputeUsage(ctx, queryID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+// GetSavingsPlans returns the savings plans the customer had in the current month
+func (s *awsUsageService) GetSavingsPlans(ctx context.Context, customerID string) ([]types.SavingsPlanData, error) {
+	queryID, err := s.getQueryID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bigqueryInterface.GetCustomerSavingsPlanData(ctx, queryID)
+}
+
+// getQueryID returns the id of the billing table of the customer, falling back to
+// the cloudhealth customer id when the customer has no active billing table
+func (s *awsUsageService) getQueryID(ctx context.Context, customerID string) (string, error) {
+	log := s.loggerProvider(ctx)
+
+	customerRef := s.customerDAL.GetRef(ctx, customerID)
+
+	if err := s.bigqueryInterface.CheckActiveBillingTableExists(ctx, customerID); err == bigQueryCache.ErrNoActiveTable {
+		chtCustomerID, err := s.cloudHealthDAL.GetCustomerCloudHealthID(ctx, customerRef)
+		if err != nil {
+			return "", err
+		}
+
+		log.Infof("cht customerId %s", chtCustomerID)
+
+		return chtCustomerID, nil
+	}
+
+	return customerID, nil
+}
@@ -137,3 +137,81 @@ func Test_awsUsageService_GetMonthlyOnDemand(t *testing.T) {
 		})
 	}
 }
+
+func Test_awsUsageService_GetHourlyUsage(t *testing.T) {
+	var contextMock = mock.MatchedBy(func(_ context.Context) bool { return true })
+
+	customerRef := &firestore.DocumentRef{ID: "mr_customer"}
+	customerID := "mr_customer"
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC)
+
+	hourlyUsage := []types.HourlyComputeUsage{
+		{Hour: from, OndemandCost: 1.5, CoveredCost: 3},
+	}
+
+	tests := []struct {
+		name    string
+		on      func(*bqMocks.BigQueryServiceInterface, *chtMocks.CloudHealthDAL)
+		want    []types.HourlyComputeUsage
+		wantErr bool
+	}{
+		{
+			name: "billing table",
+			on: func(bigqueryInterface *bqMocks.BigQueryServiceInterface, _ *chtMocks.CloudHealthDAL) {
+				bigqueryInterface.On("CheckActiveBillingTableExists", contextMock, customerID).Return(nil)
+				bigqueryInterface.On("GetHourlyComputeUsage", contextMock, customerID, "2024-05-01", "2024-05-30").Return(hourlyUsage, nil)
+			},
+			want: hourlyUsage,
+		},
+		{
+			name: "cloudhealth table",
+			on: func(bigqueryInterface *bqMocks.BigQueryServiceInterface, cloudHealthDAL *chtMocks.CloudHealthDAL) {
+				bigqueryInterface.On("CheckActiveBillingTableExists", contextMock, customerID).Return(bq.ErrNoActiveTable)
+				cloudHealthDAL.On("GetCustomerCloudHealthID", contextMock, customerRef).Return("11532", nil)
+				bigqueryInterface.On("GetHourlyComputeUsage", contextMock, "11532", "2024-05-01", "2024-05-30").Return(hourlyUsage, nil)
+			},
+			want: hourlyUsage,
+		},
+		{
+			name: "query error",
+			on: func(bigqueryInterface *bqMocks.BigQueryServiceInterface, _ *chtMocks.CloudHealthDAL) {
+				bigqueryInterface.On("CheckActiveBillingTableExists", contextMock, customerID).Return(nil)
+				bigqueryInterface.On("GetHourlyComputeUsage", contextMock, customerID, "2024-05-01", "2024-05-30").Return(nil, errors.New("error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				loggerProvider    loggerMocks.ILogger
+				bigqueryInterface bqMocks.BigQueryServiceInterface
+				cloudHealthDAL    chtMocks.CloudHealthDAL
+				customerDAL       customerMocks.Customers
+			)
+
+			loggerProvider.On("Infof", mock.Anything, mock.Anything).Maybe()
+			customerDAL.On("GetRef", contextMock, customerID).Return(customerRef)
+			tt.on(&bigqueryInterface, &cloudHealthDAL)
+
+			s := &awsUsageService{
+				loggerProvider: func(ctx context.Context) logger.ILogger {
+					return &loggerProvider
+				},
+				bigqueryInterface: &bigqueryInterface,
+				cloudHealthDAL:    &cloudHealthDAL,
+				customerDAL:       &customerDAL,
+			}
+
+			got, err := s.GetHourlyUsage(context.Background(), customerID, from, to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/doitintl/firestore"
 	"github.com/doitintl/firestore/pkg"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/flexapi/payers"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
@@ -18,6 +19,8 @@ const (
 	pending  = "pending"
 	disabled = "disabled"
 
+	payerStatus      = "status"
+	subscribed       = "subscribed"
 	enabled          = "enabled"
 	reasonCantEnable = "reasonCantEnable"
 	timeEnabled      = "timeEnabled"
@@ -70,15 +73,16 @@ func (s *service) GetPayer(ctx context.Context, accountID string) (types.PayerCo
 func (s *service) OnPendingToDisabled(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
 		log := s.loggerProvider(ctx)
+		transition := history.FromArgs(args)
 
-		err := s.disablePayerConfig(ctx, accountID)
+		err := s.disablePayerConfig(ctx, transition, accountID)
 		if err != nil {
 			log.Errorf("OnPendingToDisabled : disablePayerConfig() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		if err = s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID); err != nil {
+		if err = s.disableCacheIfNoMoreActivePayers(ctx, transition, customerID, accountID); err != nil {
 			log.Errorf("OnPendingToDisabled : disableCacheIfNoMoreActivePayers() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
@@ -91,22 +95,23 @@ func (s *service) OnPendingToDisabled(ctx context.Context, accountID, customerID
 func (s *service) OnActiveToDisabled(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
 		log := s.loggerProvider(ctx)
+		transition := history.FromArgs(args)
 
-		err := s.disablePayerConfig(ctx, accountID)
+		err := s.disablePayerConfig(ctx, transition, accountID)
 		if err != nil {
 			log.Errorf("OnActiveToDisabled : disablePayerConfig() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		err = s.payers.UnsubscribeCustomerPayerAccount(ctx, accountID)
+		err = s.unsubscribePayer(ctx, transition, accountID)
 		if err != nil {
-			log.Errorf("OnActiveToDisabled : UnsubscribeCustomerPayerAccount() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
+			log.Errorf("OnActiveToDisabled : unsubscribePayer() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		if err = s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID); err != nil {
+		if err = s.disableCacheIfNoMoreActivePayers(ctx, transition, customerID, accountID); err != nil {
 			log.Errorf("OnActiveToDisabled : disableCacheFromActive() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
@@ -119,15 +124,16 @@ func (s *service) OnActiveToDisabled(ctx context.Context, accountID, customerID
 func (s *service) OnDisabledToPending(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
 		log := s.loggerProvider(ctx)
+		transition := history.FromArgs(args)
 
-		err := s.pendPayerConfig(ctx, accountID)
+		err := s.pendPayerConfig(ctx, transition, accountID)
 		if err != nil {
 			log.Errorf("OnDisabledToPending : pendPayerConfig() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		if err = s.pendingCacheFromDisabled(ctx, customerID); err != nil {
+		if err = s.pendingCacheFromDisabled(ctx, transition, customerID); err != nil {
 			log.Errorf("OnDisabledToPending : pendingCacheFromDisabled() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
@@ -140,20 +146,21 @@ func (s *service) OnDisabledToPending(ctx context.Context, accountID, customerID
 func (s *service) OnActiveToPending(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
 		log := s.loggerProvider(ctx)
+		transition := history.FromArgs(args)
 
-		if err := s.pendPayerConfig(ctx, accountID); err != nil {
+		if err := s.pendPayerConfig(ctx, transition, accountID); err != nil {
 			log.Errorf("OnActiveToPending : pendPayerConfig() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		if err := s.payers.UnsubscribeCustomerPayerAccount(ctx, accountID); err != nil {
-			log.Errorf("OnActiveToPending : UnsubscribeCustomerPayerAccount() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
+		if err := s.unsubscribePayer(ctx, transition, accountID); err != nil {
+			log.Errorf("OnActiveToPending : unsubscribePayer() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		if err := s.pendingCacheFromActive(ctx, accountID, customerID); err != nil {
+		if err := s.pendingCacheFromActive(ctx, transition, accountID, customerID); err != nil {
 			log.Errorf("OnActiveToPending : pendingCacheFromActive() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
@@ -166,15 +173,16 @@ func (s *service) OnActiveToPending(ctx context.Context, accountID, customerID s
 func (s *service) OnToActive(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
 		log := s.loggerProvider(ctx)
+		transition := history.FromArgs(args)
 
-		err := s.activatePayerConfig(ctx, accountID)
+		err := s.activatePayerConfig(ctx, transition, accountID)
 		if err != nil {
 			log.Errorf("OnToActive : activatePayerConfig() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
 		}
 
-		if err = s.activateCache(ctx, customerID); err != nil {
+		if err = s.activateCache(ctx, transition, customerID); err != nil {
 			log.Errorf("OnToActive : activateCache() failed for payer '%s' linked to customer '%s': %w", accountID, customerID, err)
 
 			return err
@@ -186,7 +194,7 @@ func (s *service) OnToActive(ctx context.Context, accountID, customerID string)
 
 type updateFunc func(*time.Time, types.PayerConfig) (*time.Time, *time.Time)
 
-func (s *service) updatePayerConfig(ctx context.Context, accountID string, status string, update updateFunc) error {
+func (s *service) updatePayerConfig(ctx context.Context, transition *history.Transition, accountID string, status string, update updateFunc) error {
 	now := time.Now()
 
 	payer, err := s.GetPayer(ctx, accountID)
@@ -194,7 +202,7 @@ func (s *service) updatePayerConfig(ctx context.Context, accountID string, statu
 		return err
 	}
 
-	timeEnabled, timeDisabled := update(&now, payer)
+	enabledAt, disabledAt := update(&now, payer)
 
 	config := types.PayerConfig{
 		CustomerID:      payer.CustomerID,
@@ -204,39 +212,83 @@ func (s *service) updatePayerConfig(ctx context.Context, accountID string, statu
 		Name:            payer.Name,
 		Type:            payer.Type,
 		Status:          status,
-		TimeEnabled:     timeEnabled,
-		TimeDisabled:    timeDisabled,
+		TimeEnabled:     enabledAt,
+		TimeDisabled:    disabledAt,
 		SageMakerStatus: payer.SageMakerStatus,
 		RDSStatus:       payer.RDSStatus,
 		LastUpdated:     &now,
 	}
 
-	_, err = s.payers.UpdatePayerConfigsForCustomer(ctx, []types.PayerConfig{config})
-	if err != nil {
-		return err
+	change := history.Change{
+		Type:   history.ChangeTypePayerConfig,
+		Target: accountID,
+		Before: map[string]interface{}{
+			payerStatus:  payer.Status,
+			timeEnabled:  payer.TimeEnabled,
+			timeDisabled: payer.TimeDisabled,
+		},
+		After: map[string]interface{}{
+			payerStatus:  status,
+			timeEnabled:  enabledAt,
+			timeDisabled: disabledAt,
+		},
 	}
 
-	return nil
+	return transition.Apply(change, func() error {
+		_, err := s.payers.UpdatePayerConfigsForCustomer(ctx, []types.PayerConfig{config})
+		return err
+	})
 }
 
-func (s *service) pendPayerConfig(ctx context.Context, accountID string) error {
-	return s.updatePayerConfig(ctx, accountID, pending, func(now *time.Time, payer types.PayerConfig) (*time.Time, *time.Time) {
+func (s *service) pendPayerConfig(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.updatePayerConfig(ctx, transition, accountID, pending, func(now *time.Time, payer types.PayerConfig) (*time.Time, *time.Time) {
 		return nil, nil
 	})
 }
 
-func (s *service) activatePayerConfig(ctx context.Context, accountID string) error {
-	return s.updatePayerConfig(ctx, accountID, active, func(now *time.Time, payer types.PayerConfig) (*time.Time, *time.Time) {
+func (s *service) activatePayerConfig(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.updatePayerConfig(ctx, transition, accountID, active, func(now *time.Time, payer types.PayerConfig) (*time.Time, *time.Time) {
 		return now, nil
 	})
 }
 
-func (s *service) disablePayerConfig(ctx context.Context, accountID string) error {
-	return s.updatePayerConfig(ctx, accountID, disabled, func(now *time.Time, payer types.PayerConfig) (*time.Time, *time.Time) {
+func (s *service) disablePayerConfig(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.updatePayerConfig(ctx, transition, accountID, disabled, func(now *time.Time, payer types.PayerConfig) (*time.Time, *time.Time) {
 		return payer.TimeEnabled, now
 	})
 }
 
+func (s *service) unsubscribePayer(ctx context.Context, transition *history.Transition, accountID string) error {
+	change := history.Change{
+		Type:   history.ChangeTypeSubscription,
+		Target: accountID,
+		After:  map[string]interface{}{subscribed: false},
+	}
+
+	return transition.Apply(change, func() error {
+		return s.payers.UnsubscribeCustomerPayerAccount(ctx, accountID)
+	})
+}
+
+func (s *service) updateCache(ctx context.Context, transition *history.Transition, customerID string, cacheDoc *pkg.FlexsaveConfiguration, data map[string]interface{}) error {
+	change := history.Change{
+		Type:   history.ChangeTypeCache,
+		Target: customerID,
+		After:  data,
+	}
+
+	if cacheDoc != nil {
+		change.Before = map[string]interface{}{
+			enabled:      cacheDoc.AWS.Enabled,
+			timeDisabled: cacheDoc.AWS.TimeDisabled,
+		}
+	}
+
+	return transition.Apply(change, func() error {
+		return s.integrations.UpdateComputeAWSCache(ctx, customerID, data)
+	})
+}
+
 func (s *service) getAllPayersExcluding(ctx context.Context, customerID, excludeAccountID string) ([]types.PayerConfig, error) {
 	allPayers, err := s.payers.GetPayerConfigsForCustomer(ctx, customerID)
 	if err != nil {
@@ -254,7 +306,7 @@ func (s *service) getAllPayersExcluding(ctx context.Context, customerID, exclude
 	return filteredPayers, nil
 }
 
-func (s *service) activateCache(ctx context.Context, customerID string) error {
+func (s *service) activateCache(ctx context.Context, transition *history.Transition, customerID string) error {
 	cacheDoc, err := s.integrations.GetFlexsaveConfigurationCustomer(ctx, customerID)
 	if err != nil {
 		return err
@@ -264,23 +316,23 @@ func (s *service) activateCache(ctx context.Context, customerID string) error {
 		return nil
 	}
 
-	return s.integrations.UpdateComputeAWSCache(ctx, customerID, getActivatedCacheMap())
+	return s.updateCache(ctx, transition, customerID, cacheDoc, getActivatedCacheMap())
 }
 
-func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, customerID, accountID string) error {
+func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, transition *history.Transition, customerID, accountID string) error {
 	allPayers, err := s.getAllPayersExcluding(ctx, customerID, accountID)
 	if err != nil {
 		return err
 	}
 
 	if areAllPayersDisabled(allPayers) {
-		return s.integrations.UpdateComputeAWSCache(ctx, customerID, getDisabledCacheMap())
+		return s.updateCache(ctx, transition, customerID, nil, getDisabledCacheMap())
 	}
 
 	return nil
 }
 
-func (s *service) pendingCacheFromDisabled(ctx context.Context, customerID string) error {
+func (s *service) pendingCacheFromDisabled(ctx context.Context, transition *history.Transition, customerID string) error {
 	cacheDoc, err := s.integrations.GetFlexsaveConfigurationCustomer(ctx, customerID)
 	if err != nil {
 		return err
@@ -290,10 +342,10 @@ func (s *service) pendingCacheFromDisabled(ctx context.Context, customerID strin
 		return nil
 	}
 
-	return s.integrations.UpdateComputeAWSCache(ctx, customerID, getPendingCacheMap())
+	return s.updateCache(ctx, transition, customerID, cacheDoc, getPendingCacheMap())
 }
 
-func (s *service) pendingCacheFromActive(ctx context.Context, accountID, customerID string) error {
+func (s *service) pendingCacheFromActive(ctx context.Context, transition *history.Transition, accountID, customerID string) error {
 	allPayers, err := s.getAllPayersExcluding(ctx, customerID, accountID)
 	if err != nil {
 		return err
@@ -303,7 +355,7 @@ func (s *service) pendingCacheFromActive(ctx context.Context, accountID, custome
 		return nil
 	}
 
-	return s.integrations.UpdateComputeAWSCache(ctx, customerID, getPendingCacheMap())
+	return s.updateCache(ctx, transition, customerID, nil, getPendingCacheMap())
 }
 
 func getActivatedCacheMap() map[string]interface{} {
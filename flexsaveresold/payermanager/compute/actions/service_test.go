@@ -12,6 +12,7 @@ import (
 	"github.com/doitintl/errors"
 	"github.com/doitintl/firestore/mocks"
 	payerMocks "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/flexapi/payers/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	loggerMock "github.com/doitintl/hello/scheduled-tasks/logger/mocks"
@@ -992,11 +993,24 @@ func Test_service_updatePayerConfig(t *testing.T) {
 		update    func(t *time.Time, config types.PayerConfig) (*time.Time, *time.Time)
 	}
 
+	payerConfigChange := func(before, after map[string]interface{}) *history.Change {
+		return &history.Change{
+			Type:   history.ChangeTypePayerConfig,
+			Target: accountID,
+			Before: before,
+			After:  after,
+		}
+	}
+
+	var noTime *time.Time
+
 	tests := []struct {
-		name    string
-		on      func(*fields)
-		args    args
-		wantErr error
+		name       string
+		on         func(*fields)
+		args       args
+		dryRun     bool
+		wantErr    error
+		wantChange *history.Change
 	}{
 		{
 			name: "activate",
@@ -1021,6 +1035,29 @@ func Test_service_updatePayerConfig(t *testing.T) {
 					})).
 					Return([]types.PayerConfig{mockPayerConfig("", "", active, nil, nil)}, nil)
 			},
+			wantChange: payerConfigChange(
+				map[string]interface{}{payerStatus: disabled, timeEnabled: &yesterday, timeDisabled: &yesterday},
+				map[string]interface{}{payerStatus: active, timeEnabled: &now, timeDisabled: noTime},
+			),
+		},
+		{
+			name: "activate dry run",
+			args: args{
+				accountID: accountID,
+				status:    active,
+				update: func(t *time.Time, config types.PayerConfig) (*time.Time, *time.Time) {
+					return &now, nil
+				},
+			},
+			dryRun: true,
+			on: func(f *fields) {
+				disabledConfig := mockPayerConfig(customerID, accountID, disabled, &yesterday, &yesterday)
+				f.payers.On("GetPayerConfig", ctx, accountID).Return(&disabledConfig, nil)
+			},
+			wantChange: payerConfigChange(
+				map[string]interface{}{payerStatus: disabled, timeEnabled: &yesterday, timeDisabled: &yesterday},
+				map[string]interface{}{payerStatus: active, timeEnabled: &now, timeDisabled: noTime},
+			),
 		},
 		{
 			name: "disable",
@@ -1045,6 +1082,10 @@ func Test_service_updatePayerConfig(t *testing.T) {
 					})).
 					Return([]types.PayerConfig{mockPayerConfig("", "", disabled, nil, nil)}, nil)
 			},
+			wantChange: payerConfigChange(
+				map[string]interface{}{payerStatus: active, timeEnabled: &yesterday, timeDisabled: noTime},
+				map[string]interface{}{payerStatus: disabled, timeEnabled: &yesterday, timeDisabled: &now},
+			),
 		},
 		{
 			name: "pend",
@@ -1069,6 +1110,10 @@ func Test_service_updatePayerConfig(t *testing.T) {
 					return nil, nil
 				},
 			},
+			wantChange: payerConfigChange(
+				map[string]interface{}{payerStatus: disabled, timeEnabled: &yesterday, timeDisabled: &yesterday},
+				map[string]interface{}{payerStatus: pending, timeEnabled: noTime, timeDisabled: noTime},
+			),
 		},
 		{
 			name: "failed to get payer config",
@@ -1109,6 +1154,10 @@ func Test_service_updatePayerConfig(t *testing.T) {
 				},
 			},
 			wantErr: someErr,
+			wantChange: payerConfigChange(
+				map[string]interface{}{payerStatus: disabled, timeEnabled: &yesterday, timeDisabled: &yesterday},
+				map[string]interface{}{payerStatus: pending, timeEnabled: noTime, timeDisabled: noTime},
+			),
 		},
 	}
 
@@ -1128,12 +1177,22 @@ func Test_service_updatePayerConfig(t *testing.T) {
 				integrations: &fields.integrations,
 			}
 
-			err := s.updatePayerConfig(ctx, accountID, tt.args.status, tt.args.update)
+			transition := history.NewTransition(ctx, accountID, customerID, "compute", disabled, tt.args.status, "updatePayerConfig", tt.dryRun)
+
+			err := s.updatePayerConfig(ctx, transition, accountID, tt.args.status, tt.args.update)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
 				assert.NoError(t, tt.wantErr)
 			}
+
+			if tt.wantChange == nil {
+				assert.Empty(t, transition.Changes)
+			} else {
+				assert.Equal(t, []history.Change{*tt.wantChange}, transition.Changes)
+			}
+
+			fields.payers.AssertExpectations(t)
 		})
 	}
 }
@@ -1234,7 +1293,7 @@ func Test_service_activateCache(t *testing.T) {
 				integrations: &fields.integrations,
 			}
 
-			err := s.activateCache(ctx, customerID)
+			err := s.activateCache(ctx, nil, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -1324,7 +1383,7 @@ func Test_service_disableCache(t *testing.T) {
 				integrations: &fields.integrations,
 			}
 
-			err := s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+			err := s.disableCacheIfNoMoreActivePayers(ctx, nil, customerID, accountID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -1429,7 +1488,7 @@ func Test_service_pendingCacheFromDisabled(t *testing.T) {
 				integrations: &fields.integrations,
 			}
 
-			err := s.pendingCacheFromDisabled(ctx, customerID)
+			err := s.pendingCacheFromDisabled(ctx, nil, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -1528,7 +1587,7 @@ func Test_service_pendingCacheFromActive(t *testing.T) {
 				integrations: &fields.integrations,
 			}
 
-			err := s.pendingCacheFromActive(ctx, accountID, customerID)
+			err := s.pendingCacheFromActive(ctx, nil, accountID, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -1553,3 +1612,41 @@ func mockCache(enabled bool, reason string, timeEnabled, timeDisabled *time.Time
 		},
 	}
 }
+
+func Test_service_OnToActive_dryRun(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		customerID = "dhjsnjf"
+		accountID  = "12345455"
+	)
+
+	payers := payerMocks.Service{}
+	integrations := mocks.Integrations{}
+
+	pendingConfig := mockPayerConfig(customerID, accountID, pending, nil, nil)
+	payers.On("GetPayerConfig", ctx, accountID).Return(&pendingConfig, nil)
+	integrations.On("GetFlexsaveConfigurationCustomer", ctx, customerID).Return(nil, nil)
+
+	s := &service{
+		loggerProvider: func(ctx context.Context) logger.ILogger {
+			return &loggerMock.ILogger{}
+		},
+		payers:       &payers,
+		integrations: &integrations,
+	}
+
+	transition := history.NewTransition(ctx, accountID, customerID, "compute", pending, active, "pendingToActive", true)
+
+	err := s.OnToActive(ctx, accountID, customerID)(ctx, transition)
+	assert.NoError(t, err)
+
+	payers.AssertNotCalled(t, "UpdatePayerConfigsForCustomer", mock.Anything, mock.Anything)
+	integrations.AssertNotCalled(t, "UpdateComputeAWSCache", mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Len(t, transition.Changes, 2)
+	assert.Equal(t, history.ChangeTypePayerConfig, transition.Changes[0].Type)
+	assert.Equal(t, pending, transition.Changes[0].Before[payerStatus])
+	assert.Equal(t, active, transition.Changes[0].After[payerStatus])
+	assert.Equal(t, history.ChangeTypeCache, transition.Changes[1].Type)
+	assert.Equal(t, true, transition.Changes[1].After[enabled])
+}
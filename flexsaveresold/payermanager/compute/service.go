@@ -4,7 +4,10 @@ import (
 	"context"
 
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/compute/actions"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
+	historyDal "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history/dal"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
+	flexsaveutils "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	"github.com/qmuntal/stateless"
@@ -15,21 +18,37 @@ import (
 //go:generate mockery --name Service --output ./mocks
 type Service interface {
 	ProcessPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) error
+	DryRunPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) (*history.Transition, error)
 }
 
 type service struct {
+	loggerProvider      logger.Provider
 	payerManagerService actions.Service
+	transitionsDAL      historyDal.Service
 }
 
 func NewService(log logger.Provider, conn *connection.Connection) Service {
 	payerManagement := actions.NewService(log, conn)
 
 	return &service{
+		loggerProvider:      log,
 		payerManagerService: payerManagement,
+		transitionsDAL:      historyDal.NewService(conn.Firestore(context.Background())),
 	}
 }
 
 func (s *service) ProcessPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) error {
+	_, err := s.processPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus, false)
+
+	return err
+}
+
+// DryRunPayerStatusTransition returns the changes the status transition would apply to the payer config and the cache, without applying them
+func (s *service) DryRunPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) (*history.Transition, error) {
+	return s.processPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus, true)
+}
+
+func (s *service) processPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string, dryRun bool) (*history.Transition, error) {
 	machine := stateless.NewStateMachine(initialStatus)
 
 	machine.Configure(utils.PendingState).
@@ -53,12 +72,18 @@ func (s *service) ProcessPayerStatusTransition(ctx context.Context, accountID, c
 		Permit(utils.DisabledToActive, utils.ActiveState).
 		PermitReentry(utils.StayWithinState)
 
-	err := machine.Fire(defineAction(initialStatus, targetStatus))
+	trigger := defineAction(initialStatus, targetStatus)
+	transition := history.NewTransition(ctx, accountID, customerID, flexsaveutils.ComputeFlexsaveType, initialStatus, targetStatus, trigger, dryRun)
+
+	err := machine.Fire(trigger, transition)
+	transition.SetError(err)
+	history.Record(ctx, s.transitionsDAL, s.loggerProvider(ctx), transition)
+
 	if err != nil {
-		return errors.Wrapf(err, "status transition (%s -> %s) failed for payer '%s'", initialStatus, targetStatus, accountID)
+		return transition, errors.Wrapf(err, "status transition (%s -> %s) failed for payer '%s'", initialStatus, targetStatus, accountID)
 	}
 
-	return nil
+	return transition, nil
 }
 
 func defineAction(from, to string) string {
@@ -6,8 +6,11 @@ import (
 	"testing"
 
 	computeactions "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/compute/actions/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
+	historyMocks "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history/dal/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_defineAction(t *testing.T) {
@@ -107,6 +110,7 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 
 	type fields struct {
 		payerManagement     computeactions.Service
+		transitionsDAL      historyMocks.Service
 		OnDisabledToPending bool
 		OnActiveToPending   bool
 		OnToActive          bool
@@ -134,6 +138,8 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 		for _, method := range methods {
 			f.payerManagement.On(method.name, ctx, accountID, customerID).Return(method.fn)
 		}
+
+		f.transitionsDAL.On("Add", ctx, mock.AnythingOfType("*history.Transition")).Return(nil)
 	}
 
 	tests := []struct {
@@ -304,6 +310,7 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 
 			s := &service{
 				payerManagerService: &fields.payerManagement,
+				transitionsDAL:      &fields.transitionsDAL,
 			}
 
 			err := s.ProcessPayerStatusTransition(ctx, accountID, customerID, tt.args.initialStatus, tt.args.targetStatus)
@@ -318,3 +325,53 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 		})
 	}
 }
+
+func Test_service_DryRunPayerStatusTransition(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		customerID = "xxxx"
+		accountID  = "123456"
+	)
+
+	written := false
+
+	payerManagement := computeactions.Service{}
+	payerManagement.On("OnToActive", ctx, accountID, customerID).Return(func(_ context.Context, args ...any) error {
+		return history.FromArgs(args).Apply(history.Change{Type: history.ChangeTypePayerConfig, Target: accountID}, func() error {
+			written = true
+			return nil
+		})
+	})
+
+	for _, name := range []string{"OnDisabledToPending", "OnActiveToPending", "OnPendingToDisabled", "OnActiveToDisabled"} {
+		payerManagement.On(name, ctx, accountID, customerID).Return(func(_ context.Context, args ...any) error { return nil })
+	}
+
+	transitionsDAL := historyMocks.Service{}
+
+	s := &service{
+		payerManagerService: &payerManagement,
+		transitionsDAL:      &transitionsDAL,
+	}
+
+	transition, err := s.DryRunPayerStatusTransition(ctx, accountID, customerID, utils.PendingState, utils.ActiveState)
+	assert.NoError(t, err)
+	assert.False(t, written)
+	assert.True(t, transition.DryRun)
+	assert.Equal(t, utils.PendingToActive, transition.Trigger)
+	assert.Equal(t, history.SystemActor, transition.Actor)
+	assert.Len(t, transition.Changes, 1)
+	transitionsDAL.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+
+	transitionsDAL.On("Add", ctx, mock.MatchedBy(func(transition *history.Transition) bool {
+		return !transition.DryRun && transition.Trigger == utils.PendingToActive && len(transition.Changes) == 1
+	})).Return(nil).Once()
+
+	err = s.ProcessPayerStatusTransition(ctx, accountID, customerID, utils.PendingState, utils.ActiveState)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	err = s.ProcessPayerStatusTransition(ctx, accountID, customerID, utils.ActiveState, utils.ActiveState)
+	assert.NoError(t, err)
+	transitionsDAL.AssertExpectations(t)
+}
@@ -15,4 +15,5 @@ type FormEntry struct {
 	StatusChangeReason          *string          `json:"statusChangeReason"`
 	KeepActiveEvenWhenOnCredits bool             `json:"keepActiveEvenWhenOnCredits"`
 	RDSTargetPercentage         *float64         `json:"rdsTargetPercentage"`
+	DryRun                      bool             `json:"dryRun"`
 }
@@ -0,0 +1,73 @@
+package dal
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/firestore/iface"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
+)
+
+//go:generate mockery --name Service --output ./mocks
+type Service interface {
+	Add(ctx context.Context, transition *history.Transition) error
+	List(ctx context.Context, accountID string, limit int) ([]*history.Transition, error)
+}
+
+type dal struct {
+	firestoreClient  *firestore.Client
+	documentsHandler iface.DocumentsHandler
+}
+
+func NewService(fs *firestore.Client) Service {
+	return &dal{
+		firestoreClient:  fs,
+		documentsHandler: doitFirestore.DocumentHandler{},
+	}
+}
+
+func (d *dal) collection() *firestore.CollectionRef {
+	return d.firestoreClient.Collection("integrations").Doc("flexsave").Collection("payer-transitions")
+}
+
+// Add stores the transition of a payer status
+func (d *dal) Add(ctx context.Context, transition *history.Transition) error {
+	doc := d.collection().NewDoc()
+
+	if _, err := d.documentsHandler.Create(ctx, doc, transition); err != nil {
+		return err
+	}
+
+	transition.ID = doc.ID
+
+	return nil
+}
+
+// List returns the latest status transitions of a payer, newest first
+func (d *dal) List(ctx context.Context, accountID string, limit int) ([]*history.Transition, error) {
+	query := d.collection().
+		Where("accountId", "==", accountID).
+		OrderBy("timestamp", firestore.Desc).
+		Limit(limit)
+
+	snaps, err := d.documentsHandler.GetAll(query.Documents(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]*history.Transition, 0, len(snaps))
+
+	for _, snap := range snaps {
+		var transition history.Transition
+		if err := snap.DataTo(&transition); err != nil {
+			return nil, err
+		}
+
+		transition.ID = snap.ID()
+		transitions = append(transitions, &transition)
+	}
+
+	return transitions, nil
+}
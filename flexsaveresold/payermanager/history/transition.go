@@ -0,0 +1,131 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	payermanagerutils "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+// SystemActor is the actor of transitions that were not triggered by a user, e.g. scheduled tasks
+const SystemActor = "system"
+
+type ChangeType string
+
+const (
+	ChangeTypePayerConfig  ChangeType = "payerConfig"
+	ChangeTypeCache        ChangeType = "cache"
+	ChangeTypeSubscription ChangeType = "subscription"
+)
+
+// Adder stores the transitions of the payers, it is implemented by the payer history DAL
+type Adder interface {
+	Add(ctx context.Context, transition *Transition) error
+}
+
+// Change is a side effect of a payer status transition. Before holds the previous values of the
+// changed fields when they are known, and After holds the values that were written.
+type Change struct {
+	Type   ChangeType             `json:"type" firestore:"type"`
+	Target string                 `json:"target" firestore:"target"`
+	Before map[string]interface{} `json:"before,omitempty" firestore:"before"`
+	After  map[string]interface{} `json:"after" firestore:"after"`
+}
+
+// Transition is the audit event of a payer status transition
+type Transition struct {
+	ID           string             `json:"id" firestore:"-"`
+	AccountID    string             `json:"accountId" firestore:"accountId"`
+	CustomerID   string             `json:"customerId" firestore:"customerId"`
+	FlexsaveType utils.FlexsaveType `json:"flexsaveType" firestore:"flexsaveType"`
+	From         string             `json:"from" firestore:"from"`
+	To           string             `json:"to" firestore:"to"`
+	Trigger      string             `json:"trigger" firestore:"trigger"`
+	Actor        string             `json:"actor" firestore:"actor"`
+	Reason       string             `json:"reason,omitempty" firestore:"reason"`
+	DryRun       bool               `json:"dryRun" firestore:"dryRun"`
+	Changes      []Change           `json:"changes" firestore:"changes"`
+	Error        string             `json:"error,omitempty" firestore:"error"`
+	Timestamp    time.Time          `json:"timestamp" firestore:"timestamp"`
+}
+
+// NewTransition returns the transition of a payer from one status to another.
+// The actor and the reason are taken from the context when they are set by the caller.
+func NewTransition(ctx context.Context, accountID, customerID string, flexsaveType utils.FlexsaveType, from, to, trigger string, dryRun bool) *Transition {
+	actor, ok := ctx.Value(common.CtxKeys.Email).(string)
+	if !ok || actor == "" {
+		actor = SystemActor
+	}
+
+	reason, _ := ctx.Value(utils.StatusChangeReasonContextKey).(string)
+
+	return &Transition{
+		AccountID:    accountID,
+		CustomerID:   customerID,
+		FlexsaveType: flexsaveType,
+		From:         from,
+		To:           to,
+		Trigger:      trigger,
+		Actor:        actor,
+		Reason:       reason,
+		DryRun:       dryRun,
+		Changes:      []Change{},
+		Timestamp:    time.Now().UTC(),
+	}
+}
+
+// FromArgs returns the transition passed to the state machine when it was fired, or nil if there is none
+func FromArgs(args []any) *Transition {
+	for _, arg := range args {
+		if transition, ok := arg.(*Transition); ok {
+			return transition
+		}
+	}
+
+	return nil
+}
+
+// Apply records the change and writes it, unless the transition is a dry run.
+// A nil transition only writes the change.
+func (t *Transition) Apply(change Change, write func() error) error {
+	if t == nil {
+		return write()
+	}
+
+	t.Changes = append(t.Changes, change)
+
+	if t.DryRun {
+		return nil
+	}
+
+	return write()
+}
+
+// SetError records the error that stopped the transition, the changes applied before it are kept
+func (t *Transition) SetError(err error) {
+	if err != nil {
+		t.Error = err.Error()
+	}
+}
+
+// ShouldRecord checks if the transition changed anything worth keeping in the payer history
+func (t *Transition) ShouldRecord() bool {
+	return !t.DryRun &&
+		t.Trigger != payermanagerutils.StayWithinState &&
+		t.Trigger != payermanagerutils.InvalidTrigger
+}
+
+// Record adds the transition to the payer history when it should be recorded.
+// A failure is only logged since the changes of the transition were already applied.
+func Record(ctx context.Context, dal Adder, log logger.ILogger, transition *Transition) {
+	if !transition.ShouldRecord() {
+		return
+	}
+
+	if err := dal.Add(ctx, transition); err != nil {
+		log.Errorf("failed to record status transition (%s -> %s) of payer '%s': %s", transition.From, transition.To, transition.AccountID, err)
+	}
+}
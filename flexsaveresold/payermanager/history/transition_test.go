@@ -0,0 +1,154 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	payermanagerutils "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
+	loggerMocks "github.com/doitintl/hello/scheduled-tasks/logger/mocks"
+)
+
+type testAdder struct {
+	added []*Transition
+	err   error
+}
+
+func (a *testAdder) Add(_ context.Context, transition *Transition) error {
+	a.added = append(a.added, transition)
+	return a.err
+}
+
+func TestNewTransition(t *testing.T) {
+	ctx := context.WithValue(context.Background(), common.CtxKeys.Email, "ops@doit.com") // nolint:staticcheck
+	ctx = context.WithValue(ctx, utils.StatusChangeReasonContextKey, "customer request") // nolint:staticcheck
+	transition := NewTransition(ctx, "123", "customer", utils.RDSFlexsaveType, "active", "pending", "activeToPending", false)
+
+	assert.Equal(t, "ops@doit.com", transition.Actor)
+	assert.Equal(t, "customer request", transition.Reason)
+	assert.Equal(t, utils.RDSFlexsaveType, transition.FlexsaveType)
+	assert.False(t, transition.Timestamp.IsZero())
+
+	transition = NewTransition(context.Background(), "123", "customer", utils.RDSFlexsaveType, "active", "pending", "activeToPending", false)
+	assert.Equal(t, SystemActor, transition.Actor)
+	assert.Empty(t, transition.Reason)
+}
+
+func TestTransition_Apply(t *testing.T) {
+	someErr := errors.New("write failed")
+	change := Change{Type: ChangeTypeCache, Target: "customer", After: map[string]interface{}{"enabled": true}}
+
+	tests := []struct {
+		name        string
+		transition  *Transition
+		writeErr    error
+		wantWritten bool
+		wantChanges int
+		wantErr     error
+	}{
+		{
+			name:        "no transition",
+			wantWritten: true,
+		},
+		{
+			name:        "records and writes",
+			transition:  &Transition{},
+			wantWritten: true,
+			wantChanges: 1,
+		},
+		{
+			name:        "dry run only records",
+			transition:  &Transition{DryRun: true},
+			wantChanges: 1,
+		},
+		{
+			name:        "write error",
+			transition:  &Transition{},
+			writeErr:    someErr,
+			wantWritten: true,
+			wantChanges: 1,
+			wantErr:     someErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := false
+
+			err := tt.transition.Apply(change, func() error {
+				written = true
+				return tt.writeErr
+			})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantWritten, written)
+
+			if tt.transition != nil {
+				assert.Len(t, tt.transition.Changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestTransition_ShouldRecord(t *testing.T) {
+	assert.True(t, (&Transition{Trigger: payermanagerutils.PendingToActive}).ShouldRecord())
+	assert.False(t, (&Transition{Trigger: payermanagerutils.PendingToActive, DryRun: true}).ShouldRecord())
+	assert.False(t, (&Transition{Trigger: payermanagerutils.StayWithinState}).ShouldRecord())
+	assert.False(t, (&Transition{Trigger: payermanagerutils.InvalidTrigger}).ShouldRecord())
+}
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		transition *Transition
+		addErr     error
+		wantAdded  bool
+		wantLogged bool
+	}{
+		{
+			name:       "records the transition",
+			transition: &Transition{Trigger: payermanagerutils.PendingToActive},
+			wantAdded:  true,
+		},
+		{
+			name:       "skips a dry run",
+			transition: &Transition{Trigger: payermanagerutils.PendingToActive, DryRun: true},
+		},
+		{
+			name:       "logs a failure",
+			transition: &Transition{Trigger: payermanagerutils.PendingToActive},
+			addErr:     errors.New("firestore is unavailable"),
+			wantAdded:  true,
+			wantLogged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dal := &testAdder{err: tt.addErr}
+			log := loggerMocks.NewILogger(t)
+
+			if tt.wantLogged {
+				log.On("Errorf", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, tt.addErr).Once()
+			}
+
+			Record(ctx, dal, log, tt.transition)
+
+			assert.Equal(t, tt.wantAdded, len(dal.added) == 1)
+		})
+	}
+}
+
+func TestFromArgs(t *testing.T) {
+	transition := &Transition{}
+
+	assert.Equal(t, transition, FromArgs([]any{"other", transition}))
+	assert.Nil(t, FromArgs(nil))
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	history "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
+
 	payermanager "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager"
 	mock "github.com/stretchr/testify/mock"
 
@@ -18,6 +20,32 @@ type Service struct {
 	mock.Mock
 }
 
+// DryRunPayerStatusTransition provides a mock function with given fields: ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType
+func (_m *Service) DryRunPayerStatusTransition(ctx context.Context, accountID string, customerID string, initialStatus string, targetStatus string, flexsaveType utils.FlexsaveType) (*history.Transition, error) {
+	ret := _m.Called(ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType)
+
+	var r0 *history.Transition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, utils.FlexsaveType) (*history.Transition, error)); ok {
+		return rf(ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, utils.FlexsaveType) *history.Transition); ok {
+		r0 = rf(ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*history.Transition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, utils.FlexsaveType) error); ok {
+		r1 = rf(ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPayer provides a mock function with given fields: ctx, accountID
 func (_m *Service) GetPayer(ctx context.Context, accountID string) (types.PayerConfig, error) {
 	ret := _m.Called(ctx, accountID)
@@ -42,6 +70,32 @@ func (_m *Service) GetPayer(ctx context.Context, accountID string) (types.PayerC
 	return r0, r1
 }
 
+// ListPayerTransitions provides a mock function with given fields: ctx, accountID
+func (_m *Service) ListPayerTransitions(ctx context.Context, accountID string) ([]*history.Transition, error) {
+	ret := _m.Called(ctx, accountID)
+
+	var r0 []*history.Transition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*history.Transition, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*history.Transition); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*history.Transition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProcessPayerStatusTransition provides a mock function with given fields: ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType
 func (_m *Service) ProcessPayerStatusTransition(ctx context.Context, accountID string, customerID string, initialStatus string, targetStatus string, flexsaveType utils.FlexsaveType) error {
 	ret := _m.Called(ctx, accountID, customerID, initialStatus, targetStatus, flexsaveType)
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/flexapi/payers"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	dal "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/rds/dal"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/rds/iface"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
@@ -14,6 +15,7 @@ import (
 )
 
 const (
+	payerStatus  = "rdsStatus"
 	timeDisabled = "timeDisabled"
 )
 
@@ -47,63 +49,98 @@ func NewService(log logger.Provider, conn *connection.Connection) Service {
 
 func (s *service) OnPendingToDisabled(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setDisabled(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setDisabled(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+		return s.disableCacheIfNoMoreActivePayers(ctx, transition, customerID, accountID)
 	}
 }
 
 func (s *service) OnActiveToDisabled(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setDisabled(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setDisabled(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+		return s.disableCacheIfNoMoreActivePayers(ctx, transition, customerID, accountID)
 	}
 }
 
 func (s *service) OnDisabledToPending(ctx context.Context, accountID, _ string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		return s.setPending(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		return s.setPending(ctx, transition, accountID)
 	}
 }
 
 func (s *service) OnActiveToPending(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		if err := s.setPending(ctx, accountID); err != nil {
+		transition := history.FromArgs(args)
+
+		if err := s.setPending(ctx, transition, accountID); err != nil {
 			return err
 		}
 
-		return s.pendingCacheFromActive(ctx, accountID, customerID)
+		return s.pendingCacheFromActive(ctx, transition, accountID, customerID)
 	}
 }
 
 func (s *service) OnToActive(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setActive(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setActive(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.activateCache(ctx, customerID)
+		return s.activateCache(ctx, transition, customerID)
 	}
 }
 
-func (s *service) setPending(ctx context.Context, accountID string) error {
-	return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.RDSFlexsaveType, utils.Pending)
+func (s *service) setPending(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.setStatus(ctx, transition, accountID, utils.Pending)
+}
+
+func (s *service) setActive(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.setStatus(ctx, transition, accountID, utils.Active)
 }
 
-func (s *service) setActive(ctx context.Context, accountID string) error {
-	return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.RDSFlexsaveType, utils.Active)
+func (s *service) setDisabled(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.setStatus(ctx, transition, accountID, utils.Disabled)
 }
 
-func (s *service) setDisabled(ctx context.Context, accountID string) error {
-	return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.RDSFlexsaveType, utils.Disabled)
+func (s *service) setStatus(ctx context.Context, transition *history.Transition, accountID string, status string) error {
+	change := history.Change{
+		Type:   history.ChangeTypePayerConfig,
+		Target: accountID,
+		After:  map[string]interface{}{payerStatus: status},
+	}
+
+	return transition.Apply(change, func() error {
+		return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.RDSFlexsaveType, status)
+	})
+}
+
+func (s *service) updateCache(ctx context.Context, transition *history.Transition, customerID string, before, data map[string]interface{}) error {
+	change := history.Change{
+		Type:   history.ChangeTypeCache,
+		Target: customerID,
+		Before: before,
+		After:  data,
+	}
+
+	return transition.Apply(change, func() error {
+		return s.rdsDAL.Update(ctx, customerID, data)
+	})
 }
 
 func (s *service) getActivePayersExcluding(ctx context.Context, customerID, excludeAccountID string) ([]types.PayerConfig, error) {
@@ -123,7 +160,7 @@ func (s *service) getActivePayersExcluding(ctx context.Context, customerID, excl
 	return filteredPayers, nil
 }
 
-func (s *service) activateCache(ctx context.Context, customerID string) error {
+func (s *service) activateCache(ctx context.Context, transition *history.Transition, customerID string) error {
 	cacheDoc, err := s.rdsDAL.Get(ctx, customerID)
 	if err != nil {
 		return err
@@ -135,10 +172,10 @@ func (s *service) activateCache(ctx context.Context, customerID string) error {
 
 	now := time.Now()
 
-	return s.rdsDAL.Update(ctx, customerID, map[string]interface{}{"timeEnabled": &now, "reasonCantEnable": []string{}})
+	return s.updateCache(ctx, transition, customerID, nil, map[string]interface{}{"timeEnabled": &now, "reasonCantEnable": []string{}})
 }
 
-func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, customerID, accountID string) error {
+func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, transition *history.Transition, customerID, accountID string) error {
 	activePayers, err := s.getActivePayersExcluding(ctx, customerID, accountID)
 	if err != nil {
 		return err
@@ -148,13 +185,13 @@ func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, customer
 		return nil
 	}
 
-	return s.rdsDAL.Update(ctx, customerID, map[string]interface{}{
+	return s.updateCache(ctx, transition, customerID, nil, map[string]interface{}{
 		"timeEnabled":      nil,
 		"reasonCantEnable": []iface.FlexsaveRDSReasonCantEnable{iface.NoActivePayers}},
 	)
 }
 
-func (s *service) pendingCacheFromActive(ctx context.Context, accountID, customerID string) error {
+func (s *service) pendingCacheFromActive(ctx context.Context, transition *history.Transition, accountID, customerID string) error {
 	allPayers, err := s.getActivePayersExcluding(ctx, customerID, accountID)
 	if err != nil {
 		return err
@@ -164,7 +201,7 @@ func (s *service) pendingCacheFromActive(ctx context.Context, accountID, custome
 		return nil
 	}
 
-	return s.rdsDAL.Update(ctx, customerID, map[string]interface{}{
+	return s.updateCache(ctx, transition, customerID, nil, map[string]interface{}{
 		"timeEnabled":      nil,
 		"reasonCantEnable": []iface.FlexsaveRDSReasonCantEnable{iface.NoActivePayers},
 	})
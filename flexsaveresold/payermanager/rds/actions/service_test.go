@@ -769,7 +769,7 @@ func Test_service_activateCache(t *testing.T) {
 				rdsDAL: &fields.rdsDalMock,
 			}
 
-			err := s.activateCache(ctx, customerID)
+			err := s.activateCache(ctx, nil, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -863,7 +863,7 @@ func Test_service_disableCache(t *testing.T) {
 				rdsDAL: &fields.rdsDalMock,
 			}
 
-			err := s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+			err := s.disableCacheIfNoMoreActivePayers(ctx, nil, customerID, accountID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -952,7 +952,7 @@ func Test_service_pendingCacheFromActive(t *testing.T) {
 				rdsDAL: &fields.rdsDalMock,
 			}
 
-			err := s.pendingCacheFromActive(ctx, accountID, customerID)
+			err := s.pendingCacheFromActive(ctx, nil, accountID, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
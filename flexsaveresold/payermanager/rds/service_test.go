@@ -5,9 +5,11 @@ import (
 	"errors"
 	"testing"
 
+	historyMocks "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history/dal/mocks"
 	rdsMockactions "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/rds/actions/mocks"
 	utils "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_defineAction(t *testing.T) {
@@ -107,6 +109,7 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 
 	type fields struct {
 		payerManagement     rdsMockactions.Service
+		transitionsDAL      historyMocks.Service
 		OnDisabledToPending bool
 		OnActiveToPending   bool
 		OnToActive          bool
@@ -134,6 +137,8 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 		for _, method := range methods {
 			f.payerManagement.On(method.name, ctx, accountID, customerID).Return(method.fn)
 		}
+
+		f.transitionsDAL.On("Add", ctx, mock.AnythingOfType("*history.Transition")).Return(nil)
 	}
 
 	tests := []struct {
@@ -304,6 +309,7 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 
 			s := &service{
 				transitionActions: &fields.payerManagement,
+				transitionsDAL:    &fields.transitionsDAL,
 			}
 
 			err := s.ProcessPayerStatusTransition(ctx, accountID, customerID, tt.args.initialStatus, tt.args.targetStatus)
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/flexapi/payers"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	dal "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/sagemaker/dal"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
@@ -13,6 +14,7 @@ import (
 )
 
 const (
+	payerStatus  = "sagemakerStatus"
 	timeDisabled = "timeDisabled"
 	timeEnabled  = "timeEnabled"
 )
@@ -47,68 +49,103 @@ func NewService(log logger.Provider, conn *connection.Connection) Service {
 
 func (s *service) OnPendingToDisabled(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setDisabled(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setDisabled(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+		return s.disableCacheIfNoMoreActivePayers(ctx, transition, customerID, accountID)
 	}
 }
 
 func (s *service) OnActiveToDisabled(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setDisabled(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setDisabled(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+		return s.disableCacheIfNoMoreActivePayers(ctx, transition, customerID, accountID)
 	}
 }
 
 func (s *service) OnDisabledToPending(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setPending(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setPending(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.pendingCacheIfNoMoreActivePayers(ctx, accountID, customerID)
+		return s.pendingCacheIfNoMoreActivePayers(ctx, transition, accountID, customerID)
 	}
 }
 
 func (s *service) OnActiveToPending(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		if err := s.setPending(ctx, accountID); err != nil {
+		transition := history.FromArgs(args)
+
+		if err := s.setPending(ctx, transition, accountID); err != nil {
 			return err
 		}
 
-		return s.pendingCacheIfNoMoreActivePayers(ctx, accountID, customerID)
+		return s.pendingCacheIfNoMoreActivePayers(ctx, transition, accountID, customerID)
 	}
 }
 
 func (s *service) OnToActive(ctx context.Context, accountID, customerID string) func(_ context.Context, args ...any) error {
 	return func(_ context.Context, args ...any) error {
-		err := s.setActive(ctx, accountID)
+		transition := history.FromArgs(args)
+
+		err := s.setActive(ctx, transition, accountID)
 		if err != nil {
 			return err
 		}
 
-		return s.activateCache(ctx, customerID)
+		return s.activateCache(ctx, transition, customerID)
 	}
 }
 
-func (s *service) setPending(ctx context.Context, accountID string) error {
-	return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.SageMakerFlexsaveType, utils.Pending)
+func (s *service) setPending(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.setStatus(ctx, transition, accountID, utils.Pending)
+}
+
+func (s *service) setActive(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.setStatus(ctx, transition, accountID, utils.Active)
 }
 
-func (s *service) setActive(ctx context.Context, accountID string) error {
-	return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.SageMakerFlexsaveType, utils.Active)
+func (s *service) setDisabled(ctx context.Context, transition *history.Transition, accountID string) error {
+	return s.setStatus(ctx, transition, accountID, utils.Disabled)
 }
 
-func (s *service) setDisabled(ctx context.Context, accountID string) error {
-	return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.SageMakerFlexsaveType, utils.Disabled)
+func (s *service) setStatus(ctx context.Context, transition *history.Transition, accountID string, status string) error {
+	change := history.Change{
+		Type:   history.ChangeTypePayerConfig,
+		Target: accountID,
+		After:  map[string]interface{}{payerStatus: status},
+	}
+
+	return transition.Apply(change, func() error {
+		return s.payers.UpdateStatusWithRequired(ctx, accountID, utils.SageMakerFlexsaveType, status)
+	})
+}
+
+func (s *service) updateCache(ctx context.Context, transition *history.Transition, customerID string, before, data map[string]interface{}) error {
+	change := history.Change{
+		Type:   history.ChangeTypeCache,
+		Target: customerID,
+		Before: before,
+		After:  data,
+	}
+
+	return transition.Apply(change, func() error {
+		return s.sagemakerDAL.Update(ctx, customerID, data)
+	})
 }
 
 func (s *service) getActivePayersExcluding(ctx context.Context, customerID, excludeAccountID string) ([]types.PayerConfig, error) {
@@ -128,7 +165,7 @@ func (s *service) getActivePayersExcluding(ctx context.Context, customerID, excl
 	return filteredPayers, nil
 }
 
-func (s *service) activateCache(ctx context.Context, customerID string) error {
+func (s *service) activateCache(ctx context.Context, transition *history.Transition, customerID string) error {
 	cacheDoc, err := s.sagemakerDAL.Get(ctx, customerID)
 	if err != nil {
 		return err
@@ -138,14 +175,14 @@ func (s *service) activateCache(ctx context.Context, customerID string) error {
 		return nil
 	}
 
-	return s.sagemakerDAL.Update(ctx, customerID, map[string]interface{}{
+	return s.updateCache(ctx, transition, customerID, nil, map[string]interface{}{
 		timeEnabled:        time.Now(),
 		timeDisabled:       nil,
 		"reasonCantEnable": []string{}},
 	)
 }
 
-func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, customerID, accountID string) error {
+func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, transition *history.Transition, customerID, accountID string) error {
 	activePayers, err := s.getActivePayersExcluding(ctx, customerID, accountID)
 	if err != nil {
 		return err
@@ -155,13 +192,13 @@ func (s *service) disableCacheIfNoMoreActivePayers(ctx context.Context, customer
 		return nil
 	}
 
-	return s.sagemakerDAL.Update(ctx, customerID, map[string]interface{}{
+	return s.updateCache(ctx, transition, customerID, nil, map[string]interface{}{
 		timeEnabled:  nil,
 		timeDisabled: time.Now(),
 	})
 }
 
-func (s *service) pendingCacheIfNoMoreActivePayers(ctx context.Context, accountID, customerID string) error {
+func (s *service) pendingCacheIfNoMoreActivePayers(ctx context.Context, transition *history.Transition, accountID, customerID string) error {
 	activePayers, err := s.getActivePayersExcluding(ctx, customerID, accountID)
 	if err != nil {
 		return err
@@ -171,7 +208,7 @@ func (s *service) pendingCacheIfNoMoreActivePayers(ctx context.Context, accountI
 		return nil
 	}
 
-	return s.sagemakerDAL.Update(ctx, customerID, map[string]interface{}{
+	return s.updateCache(ctx, transition, customerID, nil, map[string]interface{}{
 		timeEnabled:  nil,
 		timeDisabled: nil,
 	})
@@ -742,7 +742,7 @@ func Test_service_activateCache(t *testing.T) {
 				sagemakerDAL: &fields.sagemakerDalMock,
 			}
 
-			err := s.activateCache(ctx, customerID)
+			err := s.activateCache(ctx, nil, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -837,7 +837,7 @@ func Test_service_disableCacheIfNoMoreActivePayers(t *testing.T) {
 				sagemakerDAL: &fields.sagemakerDalMock,
 			}
 
-			err := s.disableCacheIfNoMoreActivePayers(ctx, customerID, accountID)
+			err := s.disableCacheIfNoMoreActivePayers(ctx, nil, customerID, accountID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -918,7 +918,7 @@ func Test_service_pendingCacheIfNoMoreActivePayers(t *testing.T) {
 				sagemakerDAL: &fields.sagemakerDalMock,
 			}
 
-			err := s.pendingCacheIfNoMoreActivePayers(ctx, accountID, customerID)
+			err := s.pendingCacheIfNoMoreActivePayers(ctx, nil, accountID, customerID)
 			if err != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 			} else {
@@ -5,6 +5,7 @@ package mocksagemakerstate
 import (
 	context "context"
 
+	history "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,6 +14,36 @@ type Service struct {
 	mock.Mock
 }
 
+// DryRunPayerStatusTransition provides a mock function with given fields: ctx, accountID, customerID, initialStatus, targetStatus
+func (_m *Service) DryRunPayerStatusTransition(ctx context.Context, accountID string, customerID string, initialStatus string, targetStatus string) (*history.Transition, error) {
+	ret := _m.Called(ctx, accountID, customerID, initialStatus, targetStatus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DryRunPayerStatusTransition")
+	}
+
+	var r0 *history.Transition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*history.Transition, error)); ok {
+		return rf(ctx, accountID, customerID, initialStatus, targetStatus)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *history.Transition); ok {
+		r0 = rf(ctx, accountID, customerID, initialStatus, targetStatus)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*history.Transition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, accountID, customerID, initialStatus, targetStatus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProcessPayerStatusTransition provides a mock function with given fields: ctx, accountID, customerID, initialStatus, targetStatus
 func (_m *Service) ProcessPayerStatusTransition(ctx context.Context, accountID string, customerID string, initialStatus string, targetStatus string) error {
 	ret := _m.Called(ctx, accountID, customerID, initialStatus, targetStatus)
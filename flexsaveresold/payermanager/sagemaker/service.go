@@ -3,8 +3,11 @@ package sagemakerstate
 import (
 	"context"
 
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
+	historyDal "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history/dal"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/sagemaker/actions"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
+	flexsaveutils "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
 
 	"github.com/qmuntal/stateless"
 
@@ -15,21 +18,37 @@ import (
 //go:generate mockery --name Service --output ./mocks
 type Service interface {
 	ProcessPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) error
+	DryRunPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) (*history.Transition, error)
 }
 
 type service struct {
+	loggerProvider    logger.Provider
 	transitionActions actions.Service
+	transitionsDAL    historyDal.Service
 }
 
 func NewService(log logger.Provider, conn *connection.Connection) Service {
 	transitionActions := actions.NewService(log, conn)
 
 	return &service{
+		loggerProvider:    log,
 		transitionActions: transitionActions,
+		transitionsDAL:    historyDal.NewService(conn.Firestore(context.Background())),
 	}
 }
 
 func (s *service) ProcessPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) error {
+	_, err := s.processPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus, false)
+
+	return err
+}
+
+// DryRunPayerStatusTransition returns the changes the status transition would apply to the payer config and the cache, without applying them
+func (s *service) DryRunPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string) (*history.Transition, error) {
+	return s.processPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus, true)
+}
+
+func (s *service) processPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string, dryRun bool) (*history.Transition, error) {
 	machine := stateless.NewStateMachine(initialStatus)
 
 	machine.Configure(utils.PendingState).
@@ -54,8 +73,13 @@ func (s *service) ProcessPayerStatusTransition(ctx context.Context, accountID, c
 		PermitReentry(utils.StayWithinState)
 
 	action := defineAction(initialStatus, targetStatus)
+	transition := history.NewTransition(ctx, accountID, customerID, flexsaveutils.SageMakerFlexsaveType, initialStatus, targetStatus, action, dryRun)
+
+	err := machine.Fire(action, transition)
+	transition.SetError(err)
+	history.Record(ctx, s.transitionsDAL, s.loggerProvider(ctx), transition)
 
-	return machine.Fire(action)
+	return transition, err
 }
 
 func defineAction(from, to string) string {
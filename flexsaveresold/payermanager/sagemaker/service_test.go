@@ -5,8 +5,10 @@ import (
 	"errors"
 	"testing"
 
+	historyMocks "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history/dal/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	sagemakerMockactions "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/sagemaker/actions/mocks"
 )
@@ -108,6 +110,7 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 
 	type fields struct {
 		payerManagement     sagemakerMockactions.Service
+		transitionsDAL      historyMocks.Service
 		OnDisabledToPending bool
 		OnActiveToPending   bool
 		OnToActive          bool
@@ -135,6 +138,8 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 		for _, method := range methods {
 			f.payerManagement.On(method.name, ctx, accountID, customerID).Return(method.fn)
 		}
+
+		f.transitionsDAL.On("Add", ctx, mock.AnythingOfType("*history.Transition")).Return(nil)
 	}
 
 	tests := []struct {
@@ -305,6 +310,7 @@ func Test_service_ProcessPayerStatusTransition(t *testing.T) {
 
 			s := &service{
 				transitionActions: &fields.payerManagement,
+				transitionsDAL:    &fields.transitionsDAL,
 			}
 
 			err := s.ProcessPayerStatusTransition(ctx, accountID, customerID, tt.args.initialStatus, tt.args.targetStatus)
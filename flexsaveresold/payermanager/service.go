@@ -9,6 +9,8 @@ import (
 	"github.com/doitintl/firestore"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/flexapi/payers"
 	computestate "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/compute"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history"
+	historyDal "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/history/dal"
 	rdsstate "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/rds"
 	sagemakerstate "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/payermanager/sagemaker"
 	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
@@ -22,8 +24,13 @@ type Service interface {
 	ProcessPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string, flexsaveType utils.FlexsaveType) error
 	GetPayer(ctx context.Context, accountID string) (types.PayerConfig, error)
 	UpdateNonStatusPayerConfigFields(ctx context.Context, payer types.PayerConfig, entry FormEntry) error
+	DryRunPayerStatusTransition(ctx context.Context, accountID, customerID string, initialStatus, targetStatus string, flexsaveType utils.FlexsaveType) (*history.Transition, error)
+	ListPayerTransitions(ctx context.Context, accountID string) ([]*history.Transition, error)
 }
 
+// transitionsHistoryLimit is the number of latest status transitions returned for a payer
+const transitionsHistoryLimit = 100
+
 type service struct {
 	loggerProvider logger.Provider
 	payers         payers.Service
@@ -31,6 +38,7 @@ type service struct {
 	compute        computestate.Service
 	rds            rdsstate.Service
 	sagemaker      sagemakerstate.Service
+	transitionsDAL historyDal.Service
 }
 
 func NewService(log logger.Provider, conn *connection.Connection) Service {
@@ -46,6 +54,7 @@ func NewService(log logger.Provider, conn *connection.Connection) Service {
 		compute:        computestate.NewService(log, conn),
 		rds:            rdsstate.NewService(log, conn),
 		sagemaker:      sagemakerstate.NewService(log, conn),
+		transitionsDAL: historyDal.NewService(conn.Firestore(context.Background())),
 	}
 }
 
@@ -102,3 +111,25 @@ func (s *service) ProcessPayerStatusTransition(ctx context.Context, accountID, c
 		return errors.New("unsupported FlexsaveType")
 	}
 }
+
+func (s *service) DryRunPayerStatusTransition(ctx context.Context, accountID, customerID, initialStatus, targetStatus string, flexsaveType utils.FlexsaveType) (*history.Transition, error) {
+	switch flexsaveType {
+	case utils.ComputeFlexsaveType:
+		return s.compute.DryRunPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus)
+	case utils.SageMakerFlexsaveType:
+		return s.sagemaker.DryRunPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus)
+	case utils.RDSFlexsaveType:
+		return s.rds.DryRunPayerStatusTransition(ctx, accountID, customerID, initialStatus, targetStatus)
+	default:
+		return nil, errors.New("unsupported FlexsaveType")
+	}
+}
+
+func (s *service) ListPayerTransitions(ctx context.Context, accountID string) ([]*history.Transition, error) {
+	transitions, err := s.transitionsDAL.List(ctx, accountID, transitionsHistoryLimit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list status transitions of payer '%s'", accountID)
+	}
+
+	return transitions, nil
+}
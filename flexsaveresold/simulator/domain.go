@@ -0,0 +1,99 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Term string
+
+const (
+	Term1Year Term = "1yr"
+	Term3Year Term = "3yr"
+)
+
+type PaymentOption string
+
+const (
+	PaymentOptionNoUpfront      PaymentOption = "No Upfront"
+	PaymentOptionPartialUpfront PaymentOption = "Partial Upfront"
+	PaymentOptionAllUpfront     PaymentOption = "All Upfront"
+)
+
+const (
+	hoursInMonth        = 730
+	defaultLookbackDays = 30
+	maxLookbackDays     = 90
+	maxScenarios        = 10
+)
+
+var (
+	ErrInvalidRequest       = errors.New("invalid simulation request")
+	ErrNoScenarios          = fmt.Errorf("%w: at least one scenario is required", ErrInvalidRequest)
+	ErrTooManyScenarios     = fmt.Errorf("%w: too many scenarios", ErrInvalidRequest)
+	ErrInvalidLookback      = fmt.Errorf("%w: lookback days must be between 1 and %d", ErrInvalidRequest, maxLookbackDays)
+	ErrInvalidCommitment    = fmt.Errorf("%w: hourly commitment must be positive", ErrInvalidRequest)
+	ErrInvalidTerm          = fmt.Errorf("%w: invalid term", ErrInvalidRequest)
+	ErrInvalidPaymentOption = fmt.Errorf("%w: invalid payment option", ErrInvalidRequest)
+	ErrInvalidDiscountRate  = fmt.Errorf("%w: discount rate must be between 0 and 1", ErrInvalidRequest)
+	ErrNoUsageHistory       = errors.New("no usage history found")
+)
+
+var (
+	termMonths           = map[Term]int{Term1Year: 12, Term3Year: 36}
+	upfrontShares        = map[PaymentOption]float64{PaymentOptionNoUpfront: 0, PaymentOptionPartialUpfront: 0.5, PaymentOptionAllUpfront: 1}
+	defaultDiscountRates = map[Term]map[PaymentOption]float64{
+		Term1Year: {
+			PaymentOptionNoUpfront:      0.27,
+			PaymentOptionPartialUpfront: 0.3,
+			PaymentOptionAllUpfront:     0.32,
+		},
+		Term3Year: {
+			PaymentOptionNoUpfront:      0.46,
+			PaymentOptionPartialUpfront: 0.49,
+			PaymentOptionAllUpfront:     0.52,
+		},
+	}
+)
+
+// Scenario is a hypothetical savings plan purchase. The discount rate defaults to the
+// typical compute savings plan discount of the term and payment option when it is not set.
+type Scenario struct {
+	Name             string        `json:"name"`
+	HourlyCommitment float64       `json:"hourlyCommitment"`
+	Term             Term          `json:"term"`
+	PaymentOption    PaymentOption `json:"paymentOption"`
+	DiscountRate     *float64      `json:"discountRate,omitempty"`
+}
+
+type SimulationRequest struct {
+	Scenarios    []Scenario `json:"scenarios"`
+	LookbackDays int        `json:"lookbackDays"`
+}
+
+// ScenarioResult is the outcome of replaying the usage history with the scenario on top
+// of the existing savings plans. Coverage and utilization are percentages, costs are in USD
+// over the replayed period unless stated otherwise.
+type ScenarioResult struct {
+	Scenario       Scenario `json:"scenario"`
+	DiscountRate   float64  `json:"discountRate"`
+	Coverage       float64  `json:"coverage"`
+	Utilization    float64  `json:"utilization"`
+	CoveredUsage   float64  `json:"coveredUsage"`
+	CommitmentCost float64  `json:"commitmentCost"`
+	NetSavings     float64  `json:"netSavings"`
+	MonthlySavings float64  `json:"monthlySavings"`
+	UpfrontPayment float64  `json:"upfrontPayment"`
+	BreakEvenMonth *int     `json:"breakEvenMonth"`
+}
+
+type SimulationResult struct {
+	CustomerID       string           `json:"customerId"`
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	Hours            int              `json:"hours"`
+	OnDemandCost     float64          `json:"onDemandCost"`
+	ExistingCoverage float64          `json:"existingCoverage"`
+	Scenarios        []ScenarioResult `json:"scenarios"`
+}
@@ -0,0 +1,73 @@
+package simulator
+
+import (
+	"context"
+	"time"
+
+	cloudHealth "github.com/doitintl/hello/scheduled-tasks/cloudhealth/dal"
+	customerDal "github.com/doitintl/hello/scheduled-tasks/customer/dal"
+	bq "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/cache/bigquery"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/internal/aws_usage"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
+	consts "github.com/doitintl/hello/scheduled-tasks/flexsaveresold/utils"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+type awsUsageServiceInterface interface {
+	GetHourlyUsage(ctx context.Context, customerID string, from time.Time, to time.Time) ([]types.HourlyComputeUsage, error)
+	GetSavingsPlans(ctx context.Context, customerID string) ([]types.SavingsPlanData, error)
+}
+
+type Service struct {
+	loggerProvider  logger.Provider
+	awsUsageService awsUsageServiceInterface
+}
+
+func NewService(log logger.Provider, conn *connection.Connection) *Service {
+	bigQueryService, err := bq.NewBigQueryService()
+	if err != nil {
+		panic(err)
+	}
+
+	cloudHealthDAL := cloudHealth.NewCloudHealthDAL(conn.Firestore(context.Background()))
+	customerDAL := customerDal.NewCustomersFirestoreWithClient(conn.Firestore)
+
+	return &Service{
+		log,
+		aws_usage.NewAWSUsageService(log, conn, bigQueryService, cloudHealthDAL, customerDAL),
+	}
+}
+
+// Simulate replays the recent hourly EC2 usage of the customer with each of the hypothetical
+// savings plans purchases added to the savings plans the customer already has
+func (s *Service) Simulate(ctx context.Context, customerID string, req SimulationRequest) (*SimulationResult, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -consts.DaysToOffset)
+	from := to.AddDate(0, 0, -req.LookbackDays+1)
+
+	usage, err := s.awsUsageService.GetHourlyUsage(ctx, customerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(usage) == 0 {
+		return nil, ErrNoUsageHistory
+	}
+
+	savingsPlans, err := s.awsUsageService.GetSavingsPlans(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := simulate(usage, req.LookbackDays*24, existingCapacity(savingsPlans, now), req.Scenarios)
+	result.CustomerID = customerID
+	result.From = from
+	result.To = to
+
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package simulator
+
+import (
+	"math"
+	"time"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
+)
+
+func (r *SimulationRequest) validate() error {
+	if r.LookbackDays == 0 {
+		r.LookbackDays = defaultLookbackDays
+	}
+
+	if r.LookbackDays < 0 || r.LookbackDays > maxLookbackDays {
+		return ErrInvalidLookback
+	}
+
+	if len(r.Scenarios) == 0 {
+		return ErrNoScenarios
+	}
+
+	if len(r.Scenarios) > maxScenarios {
+		return ErrTooManyScenarios
+	}
+
+	for _, scenario := range r.Scenarios {
+		if err := scenario.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Scenario) validate() error {
+	if s.HourlyCommitment <= 0 {
+		return ErrInvalidCommitment
+	}
+
+	if _, ok := termMonths[s.Term]; !ok {
+		return ErrInvalidTerm
+	}
+
+	if _, ok := upfrontShares[s.PaymentOption]; !ok {
+		return ErrInvalidPaymentOption
+	}
+
+	if s.DiscountRate != nil && (*s.DiscountRate <= 0 || *s.DiscountRate >= 1) {
+		return ErrInvalidDiscountRate
+	}
+
+	return nil
+}
+
+func (s Scenario) discountRate() float64 {
+	if s.DiscountRate != nil {
+		return *s.DiscountRate
+	}
+
+	return defaultDiscountRates[s.Term][s.PaymentOption]
+}
+
+// existingCapacity returns the on demand usage per hour that the savings plans which are still
+// active can cover. The discount of each plan is taken from its actual savings this month,
+// and from the typical discount of its term and payment option when it had no usage yet.
+func existingCapacity(plans []types.SavingsPlanData, now time.Time) float64 {
+	var capacity float64
+
+	for _, plan := range plans {
+		if !plan.ExpirationDate.IsZero() && !plan.ExpirationDate.After(now) {
+			continue
+		}
+
+		hourlyCommitment := plan.HourlyUpfrontFee + plan.RecurringPayment
+
+		var rate float64
+		if plan.OnDemandCostEquivalent > 0 {
+			rate = plan.Savings / plan.OnDemandCostEquivalent
+		}
+
+		if rate <= 0 || rate >= 1 {
+			rate = defaultDiscountRates[Term(plan.Term)][PaymentOption(plan.PaymentOption)]
+		}
+
+		capacity += hourlyCommitment / (1 - rate)
+	}
+
+	return capacity
+}
+
+// simulate replays the hourly usage with the existing savings plans applied first and each
+// scenario applied to the usage they leave uncovered. Hours without usage rows are replayed
+// as hours without usage, so the commitment of the scenario is still paid for them.
+func simulate(usage []types.HourlyComputeUsage, hours int, existing float64, scenarios []Scenario) *SimulationResult {
+	var onDemandCost, existingCovered float64
+
+	for _, hour := range usage {
+		total := hour.OndemandCost + hour.CoveredCost
+		onDemandCost += total
+		existingCovered += math.Min(total, existing)
+	}
+
+	result := &SimulationResult{
+		Hours:        hours,
+		OnDemandCost: common.Round(onDemandCost),
+		Scenarios:    make([]ScenarioResult, 0, len(scenarios)),
+	}
+
+	if onDemandCost > 0 {
+		result.ExistingCoverage = common.Round(existingCovered / onDemandCost * 100)
+	}
+
+	for _, scenario := range scenarios {
+		result.Scenarios = append(result.Scenarios, replayScenario(usage, hours, existing, onDemandCost, scenario))
+	}
+
+	return result
+}
+
+func replayScenario(usage []types.HourlyComputeUsage, hours int, existing float64, onDemandCost float64, scenario Scenario) ScenarioResult {
+	rate := scenario.discountRate()
+	capacity := scenario.HourlyCommitment / (1 - rate)
+
+	var covered, totalCovered float64
+
+	for _, hour := range usage {
+		total := hour.OndemandCost + hour.CoveredCost
+		covered += math.Min(math.Max(total-existing, 0), capacity)
+		totalCovered += math.Min(total, existing+capacity)
+	}
+
+	commitmentCost := scenario.HourlyCommitment * float64(hours)
+	netSavings := covered - commitmentCost
+	monthlySavings := netSavings / float64(hours) * hoursInMonth
+
+	months := termMonths[scenario.Term]
+	upfrontPayment := scenario.HourlyCommitment * float64(months*hoursInMonth) * upfrontShares[scenario.PaymentOption]
+
+	result := ScenarioResult{
+		Scenario:       scenario,
+		DiscountRate:   rate,
+		CoveredUsage:   common.Round(covered),
+		CommitmentCost: common.Round(commitmentCost),
+		NetSavings:     common.Round(netSavings),
+		MonthlySavings: common.Round(monthlySavings),
+		UpfrontPayment: common.Round(upfrontPayment),
+		BreakEvenMonth: breakEvenMonth(monthlySavings, upfrontPayment, months),
+	}
+
+	if onDemandCost > 0 {
+		result.Coverage = common.Round(totalCovered / onDemandCost * 100)
+	}
+
+	if capacity > 0 && hours > 0 {
+		result.Utilization = common.Round(covered / (capacity * float64(hours)) * 100)
+	}
+
+	return result
+}
+
+// breakEvenMonth returns the month of the term in which the cumulative savings cover the
+// upfront payment, or nil if they never do. The monthly savings already include the amortized
+// upfront payment, so it is added back to get the cash saved every month.
+func breakEvenMonth(monthlySavings float64, upfrontPayment float64, months int) *int {
+	monthlyCashSavings := monthlySavings + upfrontPayment/float64(months)
+	if monthlyCashSavings <= 0 {
+		return nil
+	}
+
+	month := int(math.Ceil(upfrontPayment / monthlyCashSavings))
+	if month < 1 {
+		month = 1
+	}
+
+	if month > months {
+		return nil
+	}
+
+	return &month
+}
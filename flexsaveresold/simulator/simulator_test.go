@@ -0,0 +1,179 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/doitintl/hello/scheduled-tasks/flexsaveresold/types"
+)
+
+func TestSimulationRequest_validate(t *testing.T) {
+	rate := 0.4
+	invalidRate := 1.2
+	validScenario := Scenario{HourlyCommitment: 1, Term: Term1Year, PaymentOption: PaymentOptionNoUpfront, DiscountRate: &rate}
+
+	tests := []struct {
+		name             string
+		req              SimulationRequest
+		wantErr          error
+		wantLookbackDays int
+	}{
+		{
+			name:             "default lookback",
+			req:              SimulationRequest{Scenarios: []Scenario{validScenario}},
+			wantLookbackDays: defaultLookbackDays,
+		},
+		{
+			name:    "lookback too long",
+			req:     SimulationRequest{Scenarios: []Scenario{validScenario}, LookbackDays: maxLookbackDays + 1},
+			wantErr: ErrInvalidLookback,
+		},
+		{
+			name:    "no scenarios",
+			req:     SimulationRequest{},
+			wantErr: ErrNoScenarios,
+		},
+		{
+			name:    "too many scenarios",
+			req:     SimulationRequest{Scenarios: make([]Scenario, maxScenarios+1)},
+			wantErr: ErrTooManyScenarios,
+		},
+		{
+			name:    "no commitment",
+			req:     SimulationRequest{Scenarios: []Scenario{{Term: Term1Year, PaymentOption: PaymentOptionNoUpfront}}},
+			wantErr: ErrInvalidCommitment,
+		},
+		{
+			name:    "invalid term",
+			req:     SimulationRequest{Scenarios: []Scenario{{HourlyCommitment: 1, Term: "2yr", PaymentOption: PaymentOptionNoUpfront}}},
+			wantErr: ErrInvalidTerm,
+		},
+		{
+			name:    "invalid payment option",
+			req:     SimulationRequest{Scenarios: []Scenario{{HourlyCommitment: 1, Term: Term1Year, PaymentOption: "Some Upfront"}}},
+			wantErr: ErrInvalidPaymentOption,
+		},
+		{
+			name:    "invalid discount rate",
+			req:     SimulationRequest{Scenarios: []Scenario{{HourlyCommitment: 1, Term: Term1Year, PaymentOption: PaymentOptionNoUpfront, DiscountRate: &invalidRate}}},
+			wantErr: ErrInvalidDiscountRate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrInvalidRequest)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLookbackDays, tt.req.LookbackDays)
+		})
+	}
+}
+
+func TestExistingCapacity(t *testing.T) {
+	now := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	plans := []types.SavingsPlanData{
+		{
+			HourlyUpfrontFee:       0.5,
+			RecurringPayment:       2.5,
+			Savings:                25,
+			OnDemandCostEquivalent: 100,
+			ExpirationDate:         now.AddDate(1, 0, 0),
+		},
+		{
+			HourlyUpfrontFee: 0.48,
+			Term:             "3yr",
+			PaymentOption:    "All Upfront",
+			ExpirationDate:   now.AddDate(2, 0, 0),
+		},
+		{
+			RecurringPayment:       10,
+			Savings:                50,
+			OnDemandCostEquivalent: 100,
+			ExpirationDate:         now.AddDate(0, 0, -1),
+		},
+	}
+
+	assert.Equal(t, 5.0, existingCapacity(plans, now))
+	assert.Equal(t, 0.0, existingCapacity(nil, now))
+}
+
+func TestSimulate(t *testing.T) {
+	usage := []types.HourlyComputeUsage{
+		{OndemandCost: 6, CoveredCost: 4},
+		{OndemandCost: 10},
+		{OndemandCost: 8},
+	}
+
+	quarterRate := 0.25
+	overrideRate := 0.5
+	scenarios := []Scenario{
+		{Name: "no upfront", HourlyCommitment: 1.5, Term: Term1Year, PaymentOption: PaymentOptionNoUpfront, DiscountRate: &quarterRate},
+		{Name: "all upfront", HourlyCommitment: 1.5, Term: Term1Year, PaymentOption: PaymentOptionAllUpfront},
+		{Name: "over commitment", HourlyCommitment: 10, Term: Term3Year, PaymentOption: PaymentOptionPartialUpfront, DiscountRate: &overrideRate},
+	}
+
+	result := simulate(usage, 3, 4, scenarios)
+
+	assert.Equal(t, 3, result.Hours)
+	assert.Equal(t, 28.0, result.OnDemandCost)
+	assert.Equal(t, 42.86, result.ExistingCoverage)
+
+	firstMonth := 1
+	ninthMonth := 9
+
+	assert.Equal(t, []ScenarioResult{
+		{
+			Scenario:       scenarios[0],
+			DiscountRate:   0.25,
+			Coverage:       64.29,
+			Utilization:    100,
+			CoveredUsage:   6,
+			CommitmentCost: 4.5,
+			NetSavings:     1.5,
+			MonthlySavings: 365,
+			BreakEvenMonth: &firstMonth,
+		},
+		{
+			Scenario:       scenarios[1],
+			DiscountRate:   0.32,
+			Coverage:       66.49,
+			Utilization:    100,
+			CoveredUsage:   6.62,
+			CommitmentCost: 4.5,
+			NetSavings:     2.12,
+			MonthlySavings: 515.29,
+			UpfrontPayment: 13140,
+			BreakEvenMonth: &ninthMonth,
+		},
+		{
+			Scenario:       scenarios[2],
+			DiscountRate:   0.5,
+			Coverage:       100,
+			Utilization:    26.67,
+			CoveredUsage:   16,
+			CommitmentCost: 30,
+			NetSavings:     -14,
+			MonthlySavings: -3406.67,
+			UpfrontPayment: 131400,
+		},
+	}, result.Scenarios)
+}
+
+func TestBreakEvenMonth(t *testing.T) {
+	month := func(m int) *int { return &m }
+
+	assert.Equal(t, month(1), breakEvenMonth(100, 0, 12))
+	assert.Equal(t, month(6), breakEvenMonth(100, 1200, 12))
+	assert.Nil(t, breakEvenMonth(-100, 0, 12))
+	assert.Nil(t, breakEvenMonth(-50, 1200, 12))
+}
@@ -195,6 +195,12 @@ type SharedPayerOndemandMonthlyData struct {
 	MonthYear    string  `bigquery:"month_year"`
 }
 
+type HourlyComputeUsage struct {
+	Hour         time.Time `bigquery:"hour"`
+	OndemandCost float64   `bigquery:"ondemand_cost"`
+	CoveredCost  float64   `bigquery:"covered_cost"`
+}
+
 type WelcomeEmailParams struct {
 	CustomerID  string
 	Cloud       string
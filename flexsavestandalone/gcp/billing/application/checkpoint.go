@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/common"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/dataStructures"
+)
+
+// ResumeOnboarding continues the onboarding of a billing account from the step it failed at.
+// The completed steps are skipped, their outputs are taken from the checkpoint.
+func (o *Onboarding) ResumeOnboarding(ctx context.Context, billingAccountID string) error {
+	checkpoint, err := o.checkpoints.GetOnboardingCheckpoint(ctx, billingAccountID)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint.Status == dataStructures.OnboardingStatusCompleted {
+		return common.ErrOnboardingCompleted
+	}
+
+	o.loggerProvider(ctx).Infof("resuming onboarding for BA %s from step %q", billingAccountID, checkpoint.FailedStep())
+
+	return o.run(ctx, checkpoint)
+}
+
+// GetOnboardingStatus returns the step by step progress of the onboarding of a billing account
+func (o *Onboarding) GetOnboardingStatus(ctx context.Context, billingAccountID string) (*dataStructures.OnboardingCheckpoint, error) {
+	return o.checkpoints.GetOnboardingCheckpoint(ctx, billingAccountID)
+}
+
+func (o *Onboarding) saveCheckpoint(ctx context.Context, checkpoint *dataStructures.OnboardingCheckpoint, step onboardingStep, err error) {
+	if step == onboardingStepCompleted {
+		checkpoint.Complete()
+	} else {
+		checkpoint.FailStep(string(step), err)
+	}
+
+	o.setCheckpoint(ctx, checkpoint)
+}
+
+// setCheckpoint persists the progress of the onboarding. Failing to persist it does not fail the
+// onboarding, it only means a later resume may repeat steps, which are safe to repeat.
+func (o *Onboarding) setCheckpoint(ctx context.Context, checkpoint *dataStructures.OnboardingCheckpoint) {
+	if err := o.checkpoints.SetOnboardingCheckpoint(ctx, checkpoint); err != nil {
+		o.loggerProvider(ctx).Errorf("unable to SetOnboardingCheckpoint for BA %s. Caused by %s", checkpoint.BillingAccountID, err)
+	}
+}
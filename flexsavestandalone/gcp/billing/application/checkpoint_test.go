@@ -0,0 +1,266 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/common"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/service/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/dataStructures"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/shared"
+	sharedDalMocks "github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/shared/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+const testBillingAccountID = "test-billing-account"
+
+// newFailedCheckpoint returns the checkpoint of an onboarding that completed every step before failedStep
+func newFailedCheckpoint(failedStep onboardingStep) *dataStructures.OnboardingCheckpoint {
+	latestTime := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	oldestTime := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	checkpoint := dataStructures.NewOnboardingCheckpoint(&dataStructures.OnboardingRequestBody{
+		CustomerID:       "test-customer",
+		BillingAccountID: testBillingAccountID,
+	}, onboardingStepNames())
+	checkpoint.Location = "US"
+	checkpoint.LatestRecordTime = &latestTime
+	checkpoint.OldestRecordTime = &oldestTime
+
+	for _, step := range onboardingSteps {
+		checkpoint.StartStep(string(step))
+
+		if step == failedStep {
+			checkpoint.FailStep(string(step), errors.New("transient error"))
+			break
+		}
+
+		checkpoint.CompleteStep(string(step))
+	}
+
+	return checkpoint
+}
+
+func TestOnboarding_Onboard_existingCheckpoint(t *testing.T) {
+	completed := newFailedCheckpoint(onboardingStepCompleted)
+	completed.Complete()
+
+	tests := []struct {
+		name       string
+		checkpoint *dataStructures.OnboardingCheckpoint
+		wantErr    error
+	}{
+		{
+			name:       "onboarding failed after creating resources",
+			checkpoint: newFailedCheckpoint(onboardingStepFindOrCreateBucket),
+			wantErr:    common.ErrOnboardingNotResumed,
+		},
+		{
+			name:       "onboarding completed",
+			checkpoint: completed,
+			wantErr:    common.ErrOnboardingCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoints := mocks.NewOnboardingCheckpoint(t)
+			checkpoints.On("GetOnboardingCheckpoint", mock.Anything, testBillingAccountID).Return(tt.checkpoint, nil)
+
+			o := &Onboarding{
+				loggerProvider: logger.FromContext,
+				checkpoints:    checkpoints,
+			}
+
+			err := o.Onboard(context.Background(), tt.checkpoint.Request)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOnboarding_ResumeOnboarding(t *testing.T) {
+	testError := errors.New("test error")
+
+	tests := []struct {
+		name       string
+		checkpoint *dataStructures.OnboardingCheckpoint
+		getErr     error
+		on         func(*mocks.Metadata, *mocks.Table, *sharedDalMocks.BillingImportStatus)
+		wantErr    error
+		wantStatus dataStructures.OnboardingStatus
+		wantFailed string
+	}{
+		{
+			name:       "resumes from the failed step",
+			checkpoint: newFailedCheckpoint(onboardingStepCreateLocalTable),
+			on: func(_ *mocks.Metadata, table *mocks.Table, importStatus *sharedDalMocks.BillingImportStatus) {
+				table.On("CreateLocalTable", mock.Anything, testBillingAccountID).Return(nil).Once()
+				importStatus.
+					On("GetBillingImportStatus", mock.Anything, "test-customer", testBillingAccountID).
+					Return(&shared.GCPBillingImportStatus{Status: shared.BillingImportStatusPending}, nil).Once().
+					On("SetStatusStarted", mock.Anything, "test-customer", testBillingAccountID).
+					Return(nil).Once()
+			},
+			wantStatus: dataStructures.OnboardingStatusCompleted,
+		},
+		{
+			name:       "retried table creation is idempotent",
+			checkpoint: newFailedCheckpoint(onboardingStepCreateLocalTable),
+			on: func(_ *mocks.Metadata, table *mocks.Table, importStatus *sharedDalMocks.BillingImportStatus) {
+				table.On("CreateLocalTable", mock.Anything, testBillingAccountID).Return(common.ErrTableAlreadyExists).Once()
+				importStatus.
+					On("GetBillingImportStatus", mock.Anything, "test-customer", testBillingAccountID).
+					Return(&shared.GCPBillingImportStatus{Status: shared.BillingImportStatusStarted}, nil).Once()
+			},
+			wantStatus: dataStructures.OnboardingStatusCompleted,
+		},
+		{
+			name:       "retried metadata creation cleans up the previous attempt",
+			checkpoint: newFailedCheckpoint(onboardingStepCreateMetadataForNewBillingID),
+			on: func(metadata *mocks.Metadata, table *mocks.Table, importStatus *sharedDalMocks.BillingImportStatus) {
+				metadata.
+					On("DeleteExternalTaskMetadata", mock.Anything, testBillingAccountID).Return(nil).Once().
+					On("DeleteInternalTaskMetadata", mock.Anything, testBillingAccountID).Return(nil).Once().
+					On("CreateMetadataForNewBillingID", mock.Anything, mock.AnythingOfType("*dataStructures.OnboardingRequestBody"), "US", mock.Anything, mock.Anything).
+					Return(testError).Once().
+					On("DeleteInternalTaskMetadata", mock.Anything, testBillingAccountID).Return(nil).Once()
+			},
+			wantErr:    testError,
+			wantStatus: dataStructures.OnboardingStatusFailed,
+			wantFailed: string(onboardingStepCreateMetadataForNewBillingID),
+		},
+		{
+			name:    "no checkpoint",
+			getErr:  doitFirestore.ErrNotFound,
+			wantErr: doitFirestore.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := mocks.NewMetadata(t)
+			table := mocks.NewTable(t)
+			importStatus := &sharedDalMocks.BillingImportStatus{}
+			checkpoints := mocks.NewOnboardingCheckpoint(t)
+
+			checkpoints.On("GetOnboardingCheckpoint", mock.Anything, testBillingAccountID).Return(tt.checkpoint, tt.getErr)
+
+			if tt.checkpoint != nil {
+				checkpoints.On("SetOnboardingCheckpoint", mock.Anything, tt.checkpoint).Return(nil)
+			}
+
+			if tt.on != nil {
+				tt.on(metadata, table, importStatus)
+			}
+
+			o := &Onboarding{
+				loggerProvider: logger.FromContext,
+				metadata:       metadata,
+				table:          table,
+				importStatus:   importStatus,
+				checkpoints:    checkpoints,
+			}
+
+			err := o.ResumeOnboarding(context.Background(), testBillingAccountID)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.checkpoint != nil {
+				assert.Equal(t, tt.wantStatus, tt.checkpoint.Status)
+				assert.Equal(t, tt.wantFailed, tt.checkpoint.FailedStep())
+			}
+
+			importStatus.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOnboarding_RemoveBilling(t *testing.T) {
+	testError := errors.New("test error")
+
+	tests := []struct {
+		name        string
+		onBoarding  bool
+		setErr      error
+		deleteErr   error
+		wantDeleted bool
+		wantErr     error
+	}{
+		{
+			name:        "deletes the checkpoint of the deprecated billing account",
+			wantDeleted: true,
+		},
+		{
+			name:       "keeps the checkpoint while the billing account is onboarding",
+			onBoarding: true,
+		},
+		{
+			name:    "metadata update fails",
+			setErr:  testError,
+			wantErr: testError,
+		},
+		{
+			name:        "checkpoint deletion fails",
+			deleteErr:   testError,
+			wantDeleted: true,
+			wantErr:     testError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := mocks.NewMetadata(t)
+			checkpoints := mocks.NewOnboardingCheckpoint(t)
+
+			metadata.On("SetInternalAndExternalTasksMetadata", mock.Anything, testBillingAccountID, mock.Anything).
+				Run(func(args mock.Arguments) {
+					updateFunc := args.Get(2).(func(context.Context, *dataStructures.ExternalTaskMetadata, *dataStructures.InternalTaskMetadata) error)
+					_ = updateFunc(context.Background(),
+						&dataStructures.ExternalTaskMetadata{OnBoarding: tt.onBoarding},
+						&dataStructures.InternalTaskMetadata{OnBoarding: tt.onBoarding})
+				}).
+				Return(nil, nil, tt.setErr)
+
+			if tt.wantDeleted {
+				checkpoints.On("DeleteOnboardingCheckpoint", mock.Anything, testBillingAccountID).Return(tt.deleteErr)
+			}
+
+			o := &Onboarding{
+				loggerProvider: logger.FromContext,
+				metadata:       metadata,
+				checkpoints:    checkpoints,
+			}
+
+			err := o.RemoveBilling(context.Background(), &dataStructures.DeleteBillingRequestBody{BillingAccountID: testBillingAccountID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOnboarding_RemoveAll_deletesCheckpoints(t *testing.T) {
+	testError := errors.New("test error")
+
+	checkpoints := mocks.NewOnboardingCheckpoint(t)
+	checkpoints.On("DeleteAllOnboardingCheckpoints", mock.Anything).Return(testError).Once()
+
+	o := &Onboarding{
+		loggerProvider: logger.FromContext,
+		checkpoints:    checkpoints,
+	}
+
+	err := o.RemoveAll(context.Background())
+	assert.ErrorIs(t, err, testError)
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"time"
 
+	"cloud.google.com/go/bigquery"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	doitFirestore "github.com/doitintl/firestore"
 	googleCloudConsts "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/googlecloud/consts"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/common"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/service"
@@ -37,6 +39,43 @@ const (
 	onboardingStepCompleted                         onboardingStep = "completed"
 )
 
+// onboardingSteps are the steps of the onboarding in the order they run
+var onboardingSteps = []onboardingStep{
+	onboardingStepInternalTaskMetadata,
+	onboardingStepExternalTaskMetadata,
+	onboardingStepCustomerBQClient,
+	onboardingStepGetTableLocation,
+	onboardingStepGetCustomersTableLatestRecordTime,
+	onboardingStepGetCustomersTableOldestRecordTime,
+	onboardingStepCreateMetadataForNewBillingID,
+	onboardingStepFindOrCreateBucket,
+	onboardingStepCreateLocalTable,
+	onboardingStepNotifyStarted,
+}
+
+func onboardingStepNames() []string {
+	names := make([]string, len(onboardingSteps))
+	for i, step := range onboardingSteps {
+		names[i] = string(step)
+	}
+
+	return names
+}
+
+// onboardingRun holds the state shared by the steps of a single onboarding run
+type onboardingRun struct {
+	requestParams *dataStructures.OnboardingRequestBody
+	checkpoint    *dataStructures.OnboardingCheckpoint
+	table         *dataStructures.BillingTableInfo
+	customerBQ    *bigquery.Client
+}
+
+// isRetry checks if the step was already attempted by a previous run, in which case it may have partially run
+func (r *onboardingRun) isRetry(step onboardingStep) bool {
+	s := r.checkpoint.GetStep(string(step))
+	return s != nil && s.Attempts > 1
+}
+
 type Onboarding struct {
 	loggerProvider logger.Provider
 	*connection.Connection
@@ -51,6 +90,7 @@ type Onboarding struct {
 	dataset          *service.Dataset
 	tQuery           service.TableQuery
 	importStatus     sharedDal.BillingImportStatus
+	checkpoints      service.OnboardingCheckpoint
 }
 
 func NewOnboarding(log logger.Provider, conn *connection.Connection) *Onboarding {
@@ -68,27 +108,139 @@ func NewOnboarding(log logger.Provider, conn *connection.Connection) *Onboarding
 		dataset:          service.NewDataset(log, conn),
 		tQuery:           service.NewTableQuery(log, conn),
 		importStatus:     sharedDal.NewBillingImportStatusWithClient(conn.Firestore(context.Background())),
+		checkpoints:      service.NewOnboardingCheckpoint(log, conn),
 	}
 }
 
+// Onboard starts the onboarding of a new billing account. The progress is checkpointed after every step,
+// an onboarding that failed after creating resources has to be resumed instead of onboarded again.
 func (o *Onboarding) Onboard(ctx context.Context, requestParams *dataStructures.OnboardingRequestBody) error {
+	logger := o.loggerProvider(ctx)
+
+	checkpoint, err := o.checkpoints.GetOnboardingCheckpoint(ctx, requestParams.BillingAccountID)
+	if err != nil && err != doitFirestore.ErrNotFound {
+		err = fmt.Errorf("unable to GetOnboardingCheckpoint for BA %s. Caused by %s", requestParams.BillingAccountID, err)
+		logger.Error(err)
+
+		return err
+	}
+
+	if checkpoint != nil {
+		if checkpoint.Status == dataStructures.OnboardingStatusCompleted {
+			return common.ErrOnboardingCompleted
+		}
+
+		if checkpoint.IsStepStarted(string(onboardingStepCreateMetadataForNewBillingID)) {
+			return common.ErrOnboardingNotResumed
+		}
+	}
+
+	return o.run(ctx, dataStructures.NewOnboardingCheckpoint(requestParams, onboardingStepNames()))
+}
+
+// run executes the steps of the onboarding that were not completed yet
+func (o *Onboarding) run(ctx context.Context, checkpoint *dataStructures.OnboardingCheckpoint) error {
 	var err error
 
 	var step onboardingStep
 
 	logger := o.loggerProvider(ctx)
+	requestParams := checkpoint.Request
+	r := &onboardingRun{
+		requestParams: requestParams,
+		checkpoint:    checkpoint,
+		table: &dataStructures.BillingTableInfo{
+			ProjectID: requestParams.ProjectID,
+			DatasetID: requestParams.Dataset,
+			TableID:   requestParams.TableID,
+		},
+	}
 
 	defer func() {
 		e := err
 		s := step
 		o.rollback(ctx, requestParams, s, e)
+		o.saveCheckpoint(ctx, checkpoint, s, e)
 	}()
 
-	step = onboardingStepInternalTaskMetadata
-
 	logger.Infof("requestParams %+v", requestParams)
 
-	itm, err := o.metadata.GetInternalTaskMetadata(ctx, requestParams.BillingAccountID)
+	for _, step = range onboardingSteps {
+		if checkpoint.IsStepCompleted(string(step)) {
+			continue
+		}
+
+		checkpoint.StartStep(string(step))
+		o.setCheckpoint(ctx, checkpoint)
+
+		if err = o.stepFunc(step)(ctx, r); err != nil {
+			return err
+		}
+
+		checkpoint.CompleteStep(string(step))
+	}
+
+	step = onboardingStepCompleted
+
+	return nil
+}
+
+func (o *Onboarding) stepFunc(step onboardingStep) func(ctx context.Context, r *onboardingRun) error {
+	switch step {
+	case onboardingStepInternalTaskMetadata:
+		return o.checkInternalTaskMetadata
+	case onboardingStepExternalTaskMetadata:
+		return o.checkExternalTaskMetadata
+	case onboardingStepCustomerBQClient:
+		return func(ctx context.Context, r *onboardingRun) error {
+			_, err := o.getCustomerBQ(ctx, r)
+			return err
+		}
+	case onboardingStepGetTableLocation:
+		return o.getTableLocation
+	case onboardingStepGetCustomersTableLatestRecordTime:
+		return o.getLatestRecordTime
+	case onboardingStepGetCustomersTableOldestRecordTime:
+		return o.getOldestRecordTime
+	case onboardingStepCreateMetadataForNewBillingID:
+		return o.createMetadataForNewBillingID
+	case onboardingStepFindOrCreateBucket:
+		return func(ctx context.Context, r *onboardingRun) error {
+			err := o.FindOrCreateBucket(ctx, r.requestParams)
+			if err != nil {
+				err := fmt.Errorf("unable to FindOrCreateBucket for BA %s with location %s. Caused by %s", r.requestParams.BillingAccountID, r.checkpoint.Location, err)
+				o.loggerProvider(ctx).Error(err)
+
+				return err
+			}
+
+			return nil
+		}
+	case onboardingStepCreateLocalTable:
+		return o.createLocalTable
+	case onboardingStepNotifyStarted:
+		return func(ctx context.Context, r *onboardingRun) error {
+			err := o.notifyStarted(ctx, r.requestParams)
+			if err != nil {
+				err := fmt.Errorf("unable to notifyStarted for BA %s. Caused by %s", r.requestParams.BillingAccountID, err)
+				o.loggerProvider(ctx).Error(err)
+
+				return err
+			}
+
+			return nil
+		}
+	default:
+		return func(ctx context.Context, r *onboardingRun) error {
+			return fmt.Errorf("unknown onboarding step %q", step)
+		}
+	}
+}
+
+func (o *Onboarding) checkInternalTaskMetadata(ctx context.Context, r *onboardingRun) error {
+	logger := o.loggerProvider(ctx)
+
+	itm, err := o.metadata.GetInternalTaskMetadata(ctx, r.requestParams.BillingAccountID)
 	if err != nil {
 		if status.Code(err) != codes.NotFound {
 			err = fmt.Errorf("unable to GetInternalTaskMetadata. Caused by %s", err)
@@ -99,15 +251,19 @@ func (o *Onboarding) Onboard(ctx context.Context, requestParams *dataStructures.
 	}
 
 	if itm != nil {
-		err = fmt.Errorf("unable to Onboard BA %s. Caused by external task %s already exists", requestParams.BillingAccountID, requestParams.BillingAccountID)
+		err = fmt.Errorf("unable to Onboard BA %s. Caused by external task %s already exists", r.requestParams.BillingAccountID, r.requestParams.BillingAccountID)
 		logger.Error(err)
 
 		return err
 	}
 
-	step = onboardingStepExternalTaskMetadata
+	return nil
+}
+
+func (o *Onboarding) checkExternalTaskMetadata(ctx context.Context, r *onboardingRun) error {
+	logger := o.loggerProvider(ctx)
 
-	etm, err := o.metadata.GetExternalTaskMetadata(ctx, requestParams.BillingAccountID)
+	etm, err := o.metadata.GetExternalTaskMetadata(ctx, r.requestParams.BillingAccountID)
 	if err != nil {
 		if status.Code(err) != codes.NotFound {
 			err = fmt.Errorf("unable to GetExternalTaskMetadata. Caused by %s", err)
@@ -118,41 +274,63 @@ func (o *Onboarding) Onboard(ctx context.Context, requestParams *dataStructures.
 	}
 
 	if etm != nil {
-		err = fmt.Errorf("unable to Onboard BA %s. Caused by external task %s already exists", requestParams.BillingAccountID, requestParams.BillingAccountID)
+		err = fmt.Errorf("unable to Onboard BA %s. Caused by external task %s already exists", r.requestParams.BillingAccountID, r.requestParams.BillingAccountID)
 		logger.Error(err)
 
 		return err
 	}
 
-	step = onboardingStepCustomerBQClient
+	return nil
+}
+
+// getCustomerBQ returns the client of the customer's BigQuery. The client is created once per run,
+// so a resumed onboarding creates it again for the steps that read the customer's table.
+func (o *Onboarding) getCustomerBQ(ctx context.Context, r *onboardingRun) (*bigquery.Client, error) {
+	if r.customerBQ != nil {
+		return r.customerBQ, nil
+	}
 
-	customerBQ, err := o.customerBQClient.GetCustomerBQClientWithParams(ctx, requestParams.ServiceAccountEmail, requestParams.ProjectID)
+	customerBQ, err := o.customerBQClient.GetCustomerBQClientWithParams(ctx, r.requestParams.ServiceAccountEmail, r.requestParams.ProjectID)
 	if err != nil {
 		err = fmt.Errorf("unable to GetCustomerBQClient. Caused by %s", err)
-		logger.Error(err)
+		o.loggerProvider(ctx).Error(err)
 
-		return err
+		return nil, err
 	}
 
-	t := &dataStructures.BillingTableInfo{
-		ProjectID: requestParams.ProjectID,
-		DatasetID: requestParams.Dataset,
-		TableID:   requestParams.TableID,
-	}
+	r.customerBQ = customerBQ
 
-	step = onboardingStepGetTableLocation
-	location, err := o.bqTable.GetTableLocation(ctx, customerBQ, t)
+	return customerBQ, nil
+}
 
+func (o *Onboarding) getTableLocation(ctx context.Context, r *onboardingRun) error {
+	customerBQ, err := o.getCustomerBQ(ctx, r)
 	if err != nil {
-		err := fmt.Errorf("unable to get location for BA %s. Caused by %s", requestParams.BillingAccountID, err)
-		logger.Error(err)
+		return err
+	}
+
+	location, err := o.bqTable.GetTableLocation(ctx, customerBQ, r.table)
+	if err != nil {
+		err := fmt.Errorf("unable to get location for BA %s. Caused by %s", r.requestParams.BillingAccountID, err)
+		o.loggerProvider(ctx).Error(err)
 
 		return err
 	}
 
-	step = onboardingStepGetCustomersTableLatestRecordTime
+	r.checkpoint.Location = location
 
-	latestTime, err := o.tQuery.GetCustomersTableNewestRecordTime(ctx, customerBQ, t)
+	return nil
+}
+
+func (o *Onboarding) getLatestRecordTime(ctx context.Context, r *onboardingRun) error {
+	logger := o.loggerProvider(ctx)
+
+	customerBQ, err := o.getCustomerBQ(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	latestTime, err := o.tQuery.GetCustomersTableNewestRecordTime(ctx, customerBQ, r.table)
 	if err != nil {
 		switch err.(type) {
 		case *common.EmptyBillingTableError:
@@ -160,16 +338,27 @@ func (o *Onboarding) Onboard(ctx context.Context, requestParams *dataStructures.
 
 			latestTime = time.Now()
 		default:
-			err := fmt.Errorf("unable to GetCustomersTableNewestRecordTime for BA %s. Caused by %s", requestParams.BillingAccountID, err)
+			err := fmt.Errorf("unable to GetCustomersTableNewestRecordTime for BA %s. Caused by %s", r.requestParams.BillingAccountID, err)
 			logger.Error(err)
 
 			return err
 		}
 	}
 
-	step = onboardingStepGetCustomersTableOldestRecordTime
+	r.checkpoint.LatestRecordTime = &latestTime
+
+	return nil
+}
 
-	oldestTime, err := o.tQuery.GetCustomersTableOldestRecordTime(ctx, customerBQ, t)
+func (o *Onboarding) getOldestRecordTime(ctx context.Context, r *onboardingRun) error {
+	logger := o.loggerProvider(ctx)
+
+	customerBQ, err := o.getCustomerBQ(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	oldestTime, err := o.tQuery.GetCustomersTableOldestRecordTime(ctx, customerBQ, r.table)
 	if err != nil {
 		switch err.(type) {
 		case *common.EmptyBillingTableError:
@@ -177,55 +366,64 @@ func (o *Onboarding) Onboard(ctx context.Context, requestParams *dataStructures.
 
 			oldestTime = time.Now()
 		default:
-			err := fmt.Errorf("unable to GetCustomersTableOldestRecordTime for BA %s. Caused by %s", requestParams.BillingAccountID, err)
+			err := fmt.Errorf("unable to GetCustomersTableOldestRecordTime for BA %s. Caused by %s", r.requestParams.BillingAccountID, err)
 			logger.Error(err)
 
 			return err
 		}
 	}
 
-	step = onboardingStepCreateMetadataForNewBillingID
+	r.checkpoint.OldestRecordTime = &oldestTime
 
-	err = o.metadata.CreateMetadataForNewBillingID(ctx, requestParams, location, &latestTime, &oldestTime)
-	if err != nil {
-		err := fmt.Errorf("unable to CreateMetadataForNewBillingID for BA %s. Caused by %s", requestParams.BillingAccountID, err)
-		logger.Error(err)
+	return nil
+}
 
-		return err
-	}
+// createMetadataForNewBillingID creates the tasks metadata of the billing account. A retried step first
+// deletes the metadata that a previous attempt may have partially created.
+func (o *Onboarding) createMetadataForNewBillingID(ctx context.Context, r *onboardingRun) error {
+	logger := o.loggerProvider(ctx)
+	billingAccountID := r.requestParams.BillingAccountID
 
-	step = onboardingStepFindOrCreateBucket
+	if r.isRetry(onboardingStepCreateMetadataForNewBillingID) {
+		if err := o.metadata.DeleteExternalTaskMetadata(ctx, billingAccountID); err != nil && status.Code(err) != codes.NotFound {
+			err = fmt.Errorf("unable to DeleteExternalTaskMetadata for BA %s. Caused by %s", billingAccountID, err)
+			logger.Error(err)
 
-	err = o.FindOrCreateBucket(ctx, requestParams)
-	if err != nil {
-		err := fmt.Errorf("unable to FindOrCreateBucket for BA %s with location %s. Caused by %s", requestParams.BillingAccountID, location, err)
-		logger.Error(err)
+			return err
+		}
 
-		return err
-	}
+		if err := o.metadata.DeleteInternalTaskMetadata(ctx, billingAccountID); err != nil && status.Code(err) != codes.NotFound {
+			err = fmt.Errorf("unable to DeleteInternalTaskMetadata for BA %s. Caused by %s", billingAccountID, err)
+			logger.Error(err)
 
-	step = onboardingStepCreateLocalTable
+			return err
+		}
+	}
 
-	err = o.table.CreateLocalTable(ctx, requestParams.BillingAccountID)
+	err := o.metadata.CreateMetadataForNewBillingID(ctx, r.requestParams, r.checkpoint.Location, r.checkpoint.LatestRecordTime, r.checkpoint.OldestRecordTime)
 	if err != nil {
-		err := fmt.Errorf("unable to CreateLocalTableTable for BA %s with location %s. Caused by %s", requestParams.BillingAccountID, location, err)
+		err := fmt.Errorf("unable to CreateMetadataForNewBillingID for BA %s. Caused by %s", billingAccountID, err)
 		logger.Error(err)
 
 		return err
 	}
 
-	step = onboardingStepNotifyStarted
+	return nil
+}
+
+func (o *Onboarding) createLocalTable(ctx context.Context, r *onboardingRun) error {
+	err := o.table.CreateLocalTable(ctx, r.requestParams.BillingAccountID)
+	if err == common.ErrTableAlreadyExists && r.isRetry(onboardingStepCreateLocalTable) {
+		return nil
+	}
 
-	err = o.notifyStarted(ctx, requestParams)
 	if err != nil {
-		err := fmt.Errorf("unable to notifyStarted for BA %s. Caused by %s", requestParams.BillingAccountID, err)
-		logger.Error(err)
+		err := fmt.Errorf("unable to CreateLocalTableTable for BA %s with location %s. Caused by %s", r.requestParams.BillingAccountID, r.checkpoint.Location, err)
+		o.loggerProvider(ctx).Error(err)
 
 		return err
 	}
 
-	step = onboardingStepCompleted
-
 	return nil
 }
 
@@ -245,6 +443,11 @@ func (o *Onboarding) notifyStarted(ctx context.Context, requestParams *dataStruc
 		return err
 	}
 
+	if is.Status == shared.BillingImportStatusStarted {
+		logger.Infof("billingImportStatus of BA %s already started", requestParams.BillingAccountID)
+		return nil
+	}
+
 	if is.Status != shared.BillingImportStatusPending {
 		err = fmt.Errorf("invalid billingImportStatus found on BA %s. Expected %s but found %s", requestParams.BillingAccountID, shared.BillingImportStatusPending, is.Status)
 		logger.Error(err)
@@ -281,10 +484,13 @@ func (o *Onboarding) RemoveBilling(ctx context.Context, requestParams *dataStruc
 		return errorHandler(err)
 	}
 
+	var deprecated bool
+
 	if billingAccount == googleCloudConsts.MasterBillingAccount {
 		_, err = o.metadata.SetInternalTaskMetadata(ctx, billingAccount, func(ctx context.Context,
 			oitm *dataStructures.InternalTaskMetadata) error {
-			if !oitm.OnBoarding {
+			deprecated = !oitm.OnBoarding
+			if deprecated {
 				oitm.LifeCycleStage = dataStructures.LifeCycleStageDeprecated
 			}
 
@@ -293,7 +499,8 @@ func (o *Onboarding) RemoveBilling(ctx context.Context, requestParams *dataStruc
 	} else {
 		_, _, err = o.metadata.SetInternalAndExternalTasksMetadata(ctx, billingAccount, func(ctx context.Context, oetm *dataStructures.ExternalTaskMetadata,
 			oitm *dataStructures.InternalTaskMetadata) error {
-			if !oetm.OnBoarding && !oitm.OnBoarding {
+			deprecated = !oetm.OnBoarding && !oitm.OnBoarding
+			if deprecated {
 				oetm.LifeCycleStage = dataStructures.LifeCycleStageDeprecated
 				oitm.LifeCycleStage = dataStructures.LifeCycleStageDeprecated
 			}
@@ -302,7 +509,18 @@ func (o *Onboarding) RemoveBilling(ctx context.Context, requestParams *dataStruc
 		})
 	}
 
-	return err
+	if err != nil || !deprecated {
+		return err
+	}
+
+	// without its checkpoint the billing account can be onboarded again
+	err = o.checkpoints.DeleteOnboardingCheckpoint(ctx, billingAccount)
+	if err != nil {
+		logger.Errorf("unable to DeleteOnboardingCheckpoint for BA %s. Caused by %s", billingAccount, err)
+		return err
+	}
+
+	return nil
 }
 
 // Restore the whole system to initial state
@@ -321,6 +539,12 @@ func (o *Onboarding) RemoveAll(ctx context.Context) (err error) {
 		return errorHandler(err)
 	}
 
+	err = o.checkpoints.DeleteAllOnboardingCheckpoints(ctx)
+	if err != nil {
+		logger.Errorf("unable to DeleteAllOnboardingCheckpoints. Caused by %s", err)
+		return err
+	}
+
 	err = o.deleteInternalMetadata(ctx)
 	if err != nil {
 		//TODO handle error
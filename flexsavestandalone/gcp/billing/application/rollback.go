@@ -18,12 +18,13 @@ func (o *Onboarding) rollback(ctx context.Context, requestParams *dataStructures
 
 	logger.Infof("rolling back onboarding for BA %s at step %q. Onboarding failed with error %v", requestParams.BillingAccountID, step, originalError)
 
+	// Only the partial work of the failed step is rolled back, the completed steps are kept
+	// so the onboarding can be resumed from the failed step.
 	switch step {
-	case onboardingStepCreateMetadataForNewBillingID, onboardingStepFindOrCreateBucket:
+	case onboardingStepCreateMetadataForNewBillingID:
 		o.rollbackDeleteMetadataForNewBillingID(ctx, requestParams, step)
-	case onboardingStepCreateLocalTable, onboardingStepNotifyStarted:
+	case onboardingStepCreateLocalTable:
 		o.rollbackDeleteLocalTable(ctx, requestParams, step)
-		o.rollbackDeleteMetadataForNewBillingID(ctx, requestParams, step)
 	default:
 		logger.Infof("rollback for BA %s at step %q performed no action", requestParams.BillingAccountID, step)
 	}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/bigquery"
+	doitFirestore "github.com/doitintl/firestore"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/service/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/dataStructures"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
@@ -26,6 +27,7 @@ func TestRollback(t *testing.T) {
 		CustomerBQClient *mocks.ExternalBigQueryClient
 		TQuery           *mocks.TableQuery
 		ImportStatus     *sharedDalMocks.BillingImportStatus
+		Checkpoints      *mocks.OnboardingCheckpoint
 	}
 
 	testError := errors.New("test error")
@@ -289,8 +291,6 @@ func TestRollback(t *testing.T) {
 					On("GetExternalTaskMetadata", ctx, mock.AnythingOfType("string")).
 					Return(nil, nil).Once().
 					On("CreateMetadataForNewBillingID", ctx, mock.AnythingOfType("*dataStructures.OnboardingRequestBody"), mock.AnythingOfType("string"), mock.Anything, mock.Anything).
-					Return(nil).Once().
-					On("DeleteInternalTaskMetadata", ctx, mock.AnythingOfType("string")).
 					Return(nil).Once()
 				f.Bucket.
 					On("FindOrCreateBucket", ctx, mock.AnythingOfType("*dataStructures.OnboardingRequestBody")).
@@ -331,8 +331,6 @@ func TestRollback(t *testing.T) {
 					On("GetExternalTaskMetadata", ctx, mock.AnythingOfType("string")).
 					Return(nil, nil).Once().
 					On("CreateMetadataForNewBillingID", ctx, mock.AnythingOfType("*dataStructures.OnboardingRequestBody"), mock.AnythingOfType("string"), mock.Anything, mock.Anything).
-					Return(nil).Once().
-					On("DeleteInternalTaskMetadata", ctx, mock.AnythingOfType("string")).
 					Return(nil).Once()
 				f.Config.
 					On("GetRegionBucket", ctx, mock.AnythingOfType("string")).
@@ -380,8 +378,6 @@ func TestRollback(t *testing.T) {
 					On("GetExternalTaskMetadata", ctx, mock.AnythingOfType("string")).
 					Return(nil, nil).Once().
 					On("CreateMetadataForNewBillingID", ctx, mock.AnythingOfType("*dataStructures.OnboardingRequestBody"), mock.AnythingOfType("string"), mock.Anything, mock.Anything).
-					Return(nil).Once().
-					On("DeleteInternalTaskMetadata", ctx, mock.AnythingOfType("string")).
 					Return(nil).Once()
 				f.ImportStatus.
 					On("GetBillingImportStatus", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
@@ -401,8 +397,6 @@ func TestRollback(t *testing.T) {
 					On("GetTableLocation", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("*dataStructures.BillingTableInfo")).
 					Return("test-location", nil).
 					On("CreateLocalTable", ctx, mock.AnythingOfType("string")).
-					Return(nil).Once().
-					On("DeleteLocalTable", ctx, mock.AnythingOfType("string")).
 					Return(nil).Once()
 				f.TQuery.
 					On("GetCustomersTableNewestRecordTime", ctx, mock.AnythingOfType("*bigquery.Client"), mock.AnythingOfType("*dataStructures.BillingTableInfo")).
@@ -433,6 +427,7 @@ func TestRollback(t *testing.T) {
 				Bucket:           &mocks.Bucket{},
 				TQuery:           &mocks.TableQuery{},
 				ImportStatus:     &sharedDalMocks.BillingImportStatus{},
+				Checkpoints:      &mocks.OnboardingCheckpoint{},
 			}
 
 			o := &Onboarding{
@@ -447,8 +442,15 @@ func TestRollback(t *testing.T) {
 				customerBQClient: f.CustomerBQClient,
 				tQuery:           f.TQuery,
 				importStatus:     f.ImportStatus,
+				checkpoints:      f.Checkpoints,
 			}
 
+			f.Checkpoints.
+				On("GetOnboardingCheckpoint", ctx, mock.AnythingOfType("string")).
+				Return(nil, doitFirestore.ErrNotFound).
+				On("SetOnboardingCheckpoint", ctx, mock.AnythingOfType("*dataStructures.OnboardingCheckpoint")).
+				Return(nil)
+
 			if tt.on != nil {
 				tt.on(f)
 			}
@@ -16,6 +16,8 @@ var (
 	ErrDatasetNotFound                  = errors.New("dataset not found")
 	ErrInvalidBillingAccountID          = errors.New("invalid billing account id")
 	ErrBucketEmpy                       = errors.New("no files in bucket")
+	ErrOnboardingNotResumed             = errors.New("onboarding of billing account already started, resume it instead")
+	ErrOnboardingCompleted              = errors.New("onboarding of billing account already completed")
 )
 
 func NewJobScheduledTimeout(msg string) *JobScheduledTimeout {
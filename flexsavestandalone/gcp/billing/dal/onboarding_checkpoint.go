@@ -0,0 +1,84 @@
+package dal
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/firestore/iface"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/consts"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/dataStructures"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+)
+
+type OnboardingCheckpointFirestore struct {
+	firestoreClientFun connection.FirestoreFromContextFun
+	documentsHandler   iface.DocumentsHandler
+}
+
+func NewOnboardingCheckpointWithClient(fun connection.FirestoreFromContextFun) *OnboardingCheckpointFirestore {
+	return &OnboardingCheckpointFirestore{
+		firestoreClientFun: fun,
+		documentsHandler:   doitFirestore.DocumentHandler{},
+	}
+}
+
+func (d *OnboardingCheckpointFirestore) GetCollectionRef(ctx context.Context) *firestore.CollectionRef {
+	return d.firestoreClientFun(ctx).
+		Collection(consts.IntegrationsCollection).
+		Doc(consts.GCPFlexsaveStandaloneDoc).
+		Collection(consts.OnboardingCheckpointsCollection)
+}
+
+func (d *OnboardingCheckpointFirestore) GetDocRef(ctx context.Context, billingAccountID string) *firestore.DocumentRef {
+	return d.GetCollectionRef(ctx).Doc(billingAccountID)
+}
+
+func (d *OnboardingCheckpointFirestore) GetOnboardingCheckpoint(ctx context.Context, billingAccountID string) (*dataStructures.OnboardingCheckpoint, error) {
+	snap, err := d.documentsHandler.Get(ctx, d.GetDocRef(ctx, billingAccountID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, doitFirestore.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	var checkpoint dataStructures.OnboardingCheckpoint
+
+	if err := snap.DataTo(&checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+func (d *OnboardingCheckpointFirestore) SetOnboardingCheckpoint(ctx context.Context, checkpoint *dataStructures.OnboardingCheckpoint) error {
+	_, err := d.documentsHandler.Set(ctx, d.GetDocRef(ctx, checkpoint.BillingAccountID), checkpoint)
+
+	return err
+}
+
+func (d *OnboardingCheckpointFirestore) DeleteOnboardingCheckpoint(ctx context.Context, billingAccountID string) error {
+	_, err := d.GetDocRef(ctx, billingAccountID).Delete(ctx)
+
+	return err
+}
+
+func (d *OnboardingCheckpointFirestore) DeleteAllOnboardingCheckpoints(ctx context.Context) error {
+	docSnaps, err := d.GetCollectionRef(ctx).Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, docSnap := range docSnaps {
+		if _, err := docSnap.Ref.Delete(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"net/http"
 
+	doitFirestore "github.com/doitintl/firestore"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/application"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/application/task"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/common"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/rows_validator"
 	tableanalytics "github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/table_analytics"
 	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/dataStructures"
@@ -148,12 +150,52 @@ func (h *FlexsaveStandaloneGCPBilling) Onboaring(ctx *gin.Context) error {
 	}
 
 	if err := h.onborading.Onboard(ctx, &body); err != nil {
-		return web.NewRequestError(err, http.StatusInternalServerError)
+		return onboardingRequestError(err)
+	}
+
+	return nil
+}
+
+func (h *FlexsaveStandaloneGCPBilling) ResumeOnboarding(ctx *gin.Context) error {
+	billingAccountID := ctx.Param("billingAccountId")
+
+	if billingAccountID == "" {
+		return web.NewRequestError(errors.New("missing billing account id"), http.StatusBadRequest)
+	}
+
+	if err := h.onborading.ResumeOnboarding(ctx, billingAccountID); err != nil {
+		return onboardingRequestError(err)
 	}
 
 	return nil
 }
 
+func (h *FlexsaveStandaloneGCPBilling) GetOnboardingStatus(ctx *gin.Context) error {
+	billingAccountID := ctx.Param("billingAccountId")
+
+	if billingAccountID == "" {
+		return web.NewRequestError(errors.New("missing billing account id"), http.StatusBadRequest)
+	}
+
+	checkpoint, err := h.onborading.GetOnboardingStatus(ctx, billingAccountID)
+	if err != nil {
+		return onboardingRequestError(err)
+	}
+
+	return web.Respond(ctx, checkpoint, http.StatusOK)
+}
+
+func onboardingRequestError(err error) error {
+	switch err {
+	case doitFirestore.ErrNotFound:
+		return web.NewRequestError(err, http.StatusNotFound)
+	case common.ErrOnboardingCompleted, common.ErrOnboardingNotResumed:
+		return web.NewRequestError(err, http.StatusConflict)
+	default:
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+}
+
 func (h *FlexsaveStandaloneGCPBilling) RemoveBilling(ctx *gin.Context) error {
 	var body dataStructures.DeleteBillingRequestBody
 	if err := ctx.ShouldBindJSON(&body); err != nil {
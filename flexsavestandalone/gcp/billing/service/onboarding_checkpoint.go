@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/dal"
+	"github.com/doitintl/hello/scheduled-tasks/flexsavestandalone/gcp/billing/utils/dataStructures"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+)
+
+type OnboardingCheckpoint interface {
+	GetOnboardingCheckpoint(ctx context.Context, billingAccountID string) (*dataStructures.OnboardingCheckpoint, error)
+	SetOnboardingCheckpoint(ctx context.Context, checkpoint *dataStructures.OnboardingCheckpoint) error
+	DeleteOnboardingCheckpoint(ctx context.Context, billingAccountID string) error
+	DeleteAllOnboardingCheckpoints(ctx context.Context) error
+}
+
+type OnboardingCheckpointImpl struct {
+	loggerProvider logger.Provider
+	*connection.Connection
+	checkpointDal *dal.OnboardingCheckpointFirestore
+}
+
+func NewOnboardingCheckpoint(log logger.Provider, conn *connection.Connection) *OnboardingCheckpointImpl {
+	return &OnboardingCheckpointImpl{
+		log,
+		conn,
+		dal.NewOnboardingCheckpointWithClient(conn.Firestore),
+	}
+}
+
+// GetOnboardingCheckpoint returns the onboarding progress of the billing account, or doitFirestore.ErrNotFound if it was never onboarded
+func (c *OnboardingCheckpointImpl) GetOnboardingCheckpoint(ctx context.Context, billingAccountID string) (checkpoint *dataStructures.OnboardingCheckpoint, err error) {
+	err = retryFunctionWrapper(func() error {
+		checkpoint, err = c.checkpointDal.GetOnboardingCheckpoint(ctx, billingAccountID)
+		if err == doitFirestore.ErrNotFound {
+			return nil
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint == nil {
+		return nil, doitFirestore.ErrNotFound
+	}
+
+	return checkpoint, nil
+}
+
+func (c *OnboardingCheckpointImpl) SetOnboardingCheckpoint(ctx context.Context, checkpoint *dataStructures.OnboardingCheckpoint) error {
+	return retryFunctionWrapper(func() error {
+		return c.checkpointDal.SetOnboardingCheckpoint(ctx, checkpoint)
+	})
+}
+
+func (c *OnboardingCheckpointImpl) DeleteOnboardingCheckpoint(ctx context.Context, billingAccountID string) error {
+	return retryFunctionWrapper(func() error {
+		return c.checkpointDal.DeleteOnboardingCheckpoint(ctx, billingAccountID)
+	})
+}
+
+func (c *OnboardingCheckpointImpl) DeleteAllOnboardingCheckpoints(ctx context.Context) error {
+	return retryFunctionWrapper(func() error {
+		return c.checkpointDal.DeleteAllOnboardingCheckpoints(ctx)
+	})
+}
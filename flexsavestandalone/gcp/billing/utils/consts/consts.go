@@ -10,6 +10,7 @@ const (
 	InternalTasksCollection                   string = "internal-update-tasks"
 	ExternalTasksCollection                   string = "external-update-tasks"
 	RowsValidator                             string = "rows-validator"
+	OnboardingCheckpointsCollection           string = "onboarding-checkpoints"
 	InternalUpdateManagerDoc                  string = "internalUpdateManager"
 	ExternalUpdateManagerDoc                  string = "externalUpdateManager"
 	CopyToUnifiedTableJobPrefixTemplate       string = "IN-ToUnified-%d"
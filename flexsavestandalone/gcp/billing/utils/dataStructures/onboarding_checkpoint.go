@@ -0,0 +1,144 @@
+package dataStructures
+
+import (
+	"time"
+)
+
+type OnboardingStatus string
+
+const (
+	OnboardingStatusPending    OnboardingStatus = "pending"
+	OnboardingStatusInProgress OnboardingStatus = "in_progress"
+	OnboardingStatusCompleted  OnboardingStatus = "completed"
+	OnboardingStatusFailed     OnboardingStatus = "failed"
+)
+
+type OnboardingStepCheckpoint struct {
+	Step          string           `json:"step" firestore:"step"`
+	Status        OnboardingStatus `json:"status" firestore:"status"`
+	Attempts      int              `json:"attempts" firestore:"attempts"`
+	Error         string           `json:"error,omitempty" firestore:"error"`
+	TimeStarted   *time.Time       `json:"timeStarted,omitempty" firestore:"timeStarted"`
+	TimeCompleted *time.Time       `json:"timeCompleted,omitempty" firestore:"timeCompleted"`
+}
+
+// OnboardingCheckpoint is the progress of the onboarding of a billing account. It keeps the request
+// and the outputs of the completed steps so a failed onboarding can be resumed from the failed step.
+type OnboardingCheckpoint struct {
+	BillingAccountID string                      `json:"billingAccountId" firestore:"billingAccountId"`
+	Request          *OnboardingRequestBody      `json:"-" firestore:"request"`
+	Status           OnboardingStatus            `json:"status" firestore:"status"`
+	Steps            []*OnboardingStepCheckpoint `json:"steps" firestore:"steps"`
+	Location         string                      `json:"location,omitempty" firestore:"location"`
+	LatestRecordTime *time.Time                  `json:"latestRecordTime,omitempty" firestore:"latestRecordTime"`
+	OldestRecordTime *time.Time                  `json:"oldestRecordTime,omitempty" firestore:"oldestRecordTime"`
+	TimeCreated      time.Time                   `json:"timeCreated" firestore:"timeCreated"`
+	TimeUpdated      time.Time                   `json:"timeUpdated" firestore:"timeUpdated"`
+}
+
+func NewOnboardingCheckpoint(request *OnboardingRequestBody, steps []string) *OnboardingCheckpoint {
+	now := time.Now().UTC()
+
+	checkpoint := &OnboardingCheckpoint{
+		BillingAccountID: request.BillingAccountID,
+		Request:          request,
+		Status:           OnboardingStatusPending,
+		Steps:            make([]*OnboardingStepCheckpoint, len(steps)),
+		TimeCreated:      now,
+		TimeUpdated:      now,
+	}
+
+	for i, step := range steps {
+		checkpoint.Steps[i] = &OnboardingStepCheckpoint{
+			Step:   step,
+			Status: OnboardingStatusPending,
+		}
+	}
+
+	return checkpoint
+}
+
+// GetStep returns the checkpoint of the given step, or nil if the onboarding has no such step
+func (c *OnboardingCheckpoint) GetStep(step string) *OnboardingStepCheckpoint {
+	for _, s := range c.Steps {
+		if s.Step == step {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func (c *OnboardingCheckpoint) IsStepCompleted(step string) bool {
+	s := c.GetStep(step)
+	return s != nil && s.Status == OnboardingStatusCompleted
+}
+
+// IsStepStarted checks if the step was attempted, including the attempts that failed
+func (c *OnboardingCheckpoint) IsStepStarted(step string) bool {
+	s := c.GetStep(step)
+	return s != nil && s.Attempts > 0
+}
+
+func (c *OnboardingCheckpoint) StartStep(step string) {
+	s := c.GetStep(step)
+	if s == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	s.Status = OnboardingStatusInProgress
+	s.Attempts++
+	s.Error = ""
+	s.TimeStarted = &now
+	s.TimeCompleted = nil
+
+	c.Status = OnboardingStatusInProgress
+	c.TimeUpdated = now
+}
+
+func (c *OnboardingCheckpoint) CompleteStep(step string) {
+	s := c.GetStep(step)
+	if s == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	s.Status = OnboardingStatusCompleted
+	s.TimeCompleted = &now
+
+	c.TimeUpdated = now
+}
+
+func (c *OnboardingCheckpoint) FailStep(step string, err error) {
+	now := time.Now().UTC()
+
+	if s := c.GetStep(step); s != nil {
+		s.Status = OnboardingStatusFailed
+
+		if err != nil {
+			s.Error = err.Error()
+		}
+	}
+
+	c.Status = OnboardingStatusFailed
+	c.TimeUpdated = now
+}
+
+func (c *OnboardingCheckpoint) Complete() {
+	c.Status = OnboardingStatusCompleted
+	c.TimeUpdated = time.Now().UTC()
+}
+
+// FailedStep returns the step the onboarding failed at, or an empty string if it did not fail
+func (c *OnboardingCheckpoint) FailedStep() string {
+	for _, s := range c.Steps {
+		if s.Status == OnboardingStatusFailed {
+			return s.Step
+		}
+	}
+
+	return ""
+}
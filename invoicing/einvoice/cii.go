@@ -0,0 +1,369 @@
+package einvoice
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+)
+
+const (
+	ciiRSMNamespace = "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100"
+	ciiRAMNamespace = "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100"
+	ciiUDTNamespace = "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100"
+)
+
+// The CII elements are declared in the order the D16B schema requires
+
+type ciiInvoice struct {
+	XMLName           xml.Name             `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRSM          string               `xml:"xmlns:rsm,attr"`
+	XmlnsRAM          string               `xml:"xmlns:ram,attr"`
+	XmlnsUDT          string               `xml:"xmlns:udt,attr"`
+	DocumentContext   ciiDocumentContext   `xml:"rsm:ExchangedDocumentContext"`
+	ExchangedDocument ciiExchangedDocument `xml:"rsm:ExchangedDocument"`
+	TradeTransaction  ciiTradeTransaction  `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiDocumentContext struct {
+	Guideline ciiID `xml:"ram:GuidelineSpecifiedDocumentContextParameter"`
+}
+
+type ciiID struct {
+	ID string `xml:"ram:ID"`
+}
+
+type ciiExchangedDocument struct {
+	ID            string      `xml:"ram:ID"`
+	TypeCode      string      `xml:"ram:TypeCode"`
+	IssueDateTime ciiDateTime `xml:"ram:IssueDateTime"`
+	IncludedNote  *ciiNote    `xml:"ram:IncludedNote"`
+}
+
+type ciiNote struct {
+	Content string `xml:"ram:Content"`
+}
+
+type ciiDateTime struct {
+	DateTimeString ciiDateTimeString `xml:"udt:DateTimeString"`
+}
+
+type ciiDateTimeString struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiTradeTransaction struct {
+	LineItems  []ciiLineItem       `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  ciiHeaderAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}            `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiHeaderSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLineItem struct {
+	LineDocument ciiLineDocument   `xml:"ram:AssociatedDocumentLineDocument"`
+	Product      ciiProduct        `xml:"ram:SpecifiedTradeProduct"`
+	Agreement    ciiLineAgreement  `xml:"ram:SpecifiedLineTradeAgreement"`
+	Delivery     ciiLineDelivery   `xml:"ram:SpecifiedLineTradeDelivery"`
+	Settlement   ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiLineDocument struct {
+	LineID string `xml:"ram:LineID"`
+}
+
+type ciiProduct struct {
+	SellerAssignedID string `xml:"ram:SellerAssignedID,omitempty"`
+	Name             string `xml:"ram:Name"`
+	Description      string `xml:"ram:Description,omitempty"`
+}
+
+type ciiLineAgreement struct {
+	NetPrice ciiPrice `xml:"ram:NetPriceProductTradePrice"`
+}
+
+type ciiPrice struct {
+	ChargeAmount string `xml:"ram:ChargeAmount"`
+}
+
+type ciiLineDelivery struct {
+	BilledQuantity ciiQuantity `xml:"ram:BilledQuantity"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiLineSettlement struct {
+	TradeTax        ciiTradeTax      `xml:"ram:ApplicableTradeTax"`
+	BillingPeriod   *ciiPeriod       `xml:"ram:BillingSpecifiedPeriod"`
+	MonetarySummary ciiLineSummation `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation"`
+}
+
+type ciiLineSummation struct {
+	LineTotalAmount string `xml:"ram:LineTotalAmount"`
+}
+
+type ciiTradeTax struct {
+	CalculatedAmount      string `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode              string `xml:"ram:TypeCode"`
+	ExemptionReason       string `xml:"ram:ExemptionReason,omitempty"`
+	BasisAmount           string `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode          string `xml:"ram:CategoryCode"`
+	ExemptionReasonCode   string `xml:"ram:ExemptionReasonCode,omitempty"`
+	RateApplicablePercent string `xml:"ram:RateApplicablePercent,omitempty"`
+}
+
+type ciiPeriod struct {
+	StartDateTime *ciiDateTime `xml:"ram:StartDateTime"`
+	EndDateTime   *ciiDateTime `xml:"ram:EndDateTime"`
+}
+
+type ciiHeaderAgreement struct {
+	BuyerReference string        `xml:"ram:BuyerReference"`
+	Seller         ciiTradeParty `xml:"ram:SellerTradeParty"`
+	Buyer          ciiTradeParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiTradeParty struct {
+	Name              string                `xml:"ram:Name"`
+	LegalOrganization *ciiLegalOrganization `xml:"ram:SpecifiedLegalOrganization"`
+	PostalAddress     ciiAddress            `xml:"ram:PostalTradeAddress"`
+	URI               *ciiURI               `xml:"ram:URIUniversalCommunication"`
+	TaxRegistration   *ciiTaxRegistration   `xml:"ram:SpecifiedTaxRegistration"`
+}
+
+type ciiLegalOrganization struct {
+	ID string `xml:"ram:ID"`
+}
+
+type ciiAddress struct {
+	PostcodeCode           string `xml:"ram:PostcodeCode,omitempty"`
+	LineOne                string `xml:"ram:LineOne,omitempty"`
+	LineTwo                string `xml:"ram:LineTwo,omitempty"`
+	LineThree              string `xml:"ram:LineThree,omitempty"`
+	CityName               string `xml:"ram:CityName,omitempty"`
+	CountryID              string `xml:"ram:CountryID"`
+	CountrySubDivisionName string `xml:"ram:CountrySubDivisionName,omitempty"`
+}
+
+type ciiURI struct {
+	URIID ciiSchemedID `xml:"ram:URIID"`
+}
+
+type ciiTaxRegistration struct {
+	ID ciiSchemedID `xml:"ram:ID"`
+}
+
+type ciiSchemedID struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiHeaderSettlement struct {
+	InvoiceCurrencyCode string             `xml:"ram:InvoiceCurrencyCode"`
+	PaymentMeans        *ciiPaymentMeans   `xml:"ram:SpecifiedTradeSettlementPaymentMeans"`
+	TradeTaxes          []ciiTradeTax      `xml:"ram:ApplicableTradeTax"`
+	PaymentTerms        *ciiPaymentTerms   `xml:"ram:SpecifiedTradePaymentTerms"`
+	MonetarySummation   ciiHeaderSummation `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+type ciiPaymentMeans struct {
+	TypeCode     string              `xml:"ram:TypeCode"`
+	PayeeAccount ciiFinancialAccount `xml:"ram:PayeePartyCreditorFinancialAccount"`
+}
+
+type ciiFinancialAccount struct {
+	IBANID string `xml:"ram:IBANID"`
+}
+
+type ciiPaymentTerms struct {
+	DueDateDateTime ciiDateTime `xml:"ram:DueDateDateTime"`
+}
+
+type ciiHeaderSummation struct {
+	LineTotalAmount     string    `xml:"ram:LineTotalAmount"`
+	TaxBasisTotalAmount string    `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotalAmount      ciiAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotalAmount    string    `xml:"ram:GrandTotalAmount"`
+	DuePayableAmount    string    `xml:"ram:DuePayableAmount"`
+}
+
+type ciiAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+func marshalCII(d *Document) ([]byte, error) {
+	totals := d.Totals()
+
+	invoice := ciiInvoice{
+		XmlnsRSM:        ciiRSMNamespace,
+		XmlnsRAM:        ciiRAMNamespace,
+		XmlnsUDT:        ciiUDTNamespace,
+		DocumentContext: ciiDocumentContext{Guideline: ciiID{ID: ciiGuidelineID}},
+		ExchangedDocument: ciiExchangedDocument{
+			ID:            d.ID,
+			TypeCode:      invoiceTypeCode,
+			IssueDateTime: toCIIDateTime(d.IssueDate),
+		},
+		TradeTransaction: ciiTradeTransaction{
+			Agreement: ciiHeaderAgreement{
+				BuyerReference: d.BuyerReference,
+				Seller:         toCIITradeParty(d.Seller),
+				Buyer:          toCIITradeParty(d.Buyer),
+			},
+			Settlement: ciiHeaderSettlement{
+				InvoiceCurrencyCode: d.Currency,
+				MonetarySummation: ciiHeaderSummation{
+					LineTotalAmount:     formatAmount(totals.LineExtensionAmount),
+					TaxBasisTotalAmount: formatAmount(totals.TaxExclusiveAmount),
+					TaxTotalAmount:      ciiAmount{CurrencyID: d.Currency, Value: formatAmount(totals.TaxAmount)},
+					GrandTotalAmount:    formatAmount(totals.TaxInclusiveAmount),
+					DuePayableAmount:    formatAmount(totals.PayableAmount),
+				},
+			},
+		},
+	}
+
+	if d.Note != "" {
+		invoice.ExchangedDocument.IncludedNote = &ciiNote{Content: d.Note}
+	}
+
+	if d.PayeeIBAN != "" {
+		invoice.TradeTransaction.Settlement.PaymentMeans = &ciiPaymentMeans{
+			TypeCode:     creditTransferCode,
+			PayeeAccount: ciiFinancialAccount{IBANID: d.PayeeIBAN},
+		}
+	}
+
+	if !d.DueDate.IsZero() {
+		invoice.TradeTransaction.Settlement.PaymentTerms = &ciiPaymentTerms{DueDateDateTime: toCIIDateTime(d.DueDate)}
+	}
+
+	for _, subtotal := range totals.TaxSubtotals {
+		tradeTax := toCIITradeTax(subtotal.Category, subtotal.Percent)
+		reason := exemptionReasons[subtotal.Category]
+		tradeTax.CalculatedAmount = formatAmount(subtotal.TaxAmount)
+		tradeTax.BasisAmount = formatAmount(subtotal.TaxableAmount)
+		tradeTax.ExemptionReasonCode = reason[0]
+		tradeTax.ExemptionReason = reason[1]
+
+		invoice.TradeTransaction.Settlement.TradeTaxes = append(invoice.TradeTransaction.Settlement.TradeTaxes, tradeTax)
+	}
+
+	for _, line := range d.Lines {
+		lineItem := ciiLineItem{
+			LineDocument: ciiLineDocument{LineID: line.ID},
+			Product: ciiProduct{
+				SellerAssignedID: line.SKU,
+				Name:             line.Name,
+				Description:      line.Description,
+			},
+			Agreement: ciiLineAgreement{NetPrice: ciiPrice{ChargeAmount: formatDecimal(line.UnitPrice)}},
+			Delivery:  ciiLineDelivery{BilledQuantity: ciiQuantity{UnitCode: unitCodeOne, Value: formatDecimal(line.Quantity)}},
+			Settlement: ciiLineSettlement{
+				TradeTax:        toCIITradeTax(line.TaxCategory, line.TaxPercent),
+				MonetarySummary: ciiLineSummation{LineTotalAmount: formatAmount(line.NetAmount)},
+			},
+		}
+
+		if line.PeriodStart != nil || line.PeriodEnd != nil {
+			period := &ciiPeriod{}
+
+			if line.PeriodStart != nil {
+				start := toCIIDateTime(*line.PeriodStart)
+				period.StartDateTime = &start
+			}
+
+			if line.PeriodEnd != nil {
+				end := toCIIDateTime(*line.PeriodEnd)
+				period.EndDateTime = &end
+			}
+
+			lineItem.Settlement.BillingPeriod = period
+		}
+
+		invoice.TradeTransaction.LineItems = append(invoice.TradeTransaction.LineItems, lineItem)
+	}
+
+	return marshalXML(invoice)
+}
+
+func toCIITradeParty(party Party) ciiTradeParty {
+	tradeParty := ciiTradeParty{
+		Name: party.Name,
+		PostalAddress: ciiAddress{
+			PostcodeCode:           party.Address.PostalZone,
+			CityName:               party.Address.City,
+			CountryID:              party.Address.CountryCode,
+			CountrySubDivisionName: party.Address.CountrySubentity,
+		},
+	}
+
+	for i, line := range party.Address.Lines {
+		switch i {
+		case 0:
+			tradeParty.PostalAddress.LineOne = line
+		case 1:
+			tradeParty.PostalAddress.LineTwo = line
+		case 2:
+			tradeParty.PostalAddress.LineThree = line
+		}
+	}
+
+	if party.LegalID != "" {
+		tradeParty.LegalOrganization = &ciiLegalOrganization{ID: party.LegalID}
+	}
+
+	if party.EndpointID != "" {
+		tradeParty.URI = &ciiURI{URIID: ciiSchemedID{SchemeID: party.EndpointSchemeID, Value: party.EndpointID}}
+	}
+
+	if party.VATID != "" {
+		tradeParty.TaxRegistration = &ciiTaxRegistration{ID: ciiSchemedID{SchemeID: ciiVATRegistrationCode, Value: party.VATID}}
+	}
+
+	return tradeParty
+}
+
+func toCIITradeTax(category TaxCategory, percent float64) ciiTradeTax {
+	tradeTax := ciiTradeTax{
+		TypeCode:     vatTaxScheme,
+		CategoryCode: string(category),
+	}
+
+	// the rate is not given for lines not subject to VAT
+	if category != TaxCategoryNotSubject {
+		tradeTax.RateApplicablePercent = formatDecimal(percent)
+	}
+
+	return tradeTax
+}
+
+func toCIIDateTime(t time.Time) ciiDateTime {
+	return ciiDateTime{DateTimeString: ciiDateTimeString{Format: ciiDateFormatCode, Value: t.Format(ciiDateFormat)}}
+}
+
+func formatOptionalDate(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
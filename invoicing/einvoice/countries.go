@@ -0,0 +1,104 @@
+package einvoice
+
+// countryCodes maps the Priority country names to their ISO 3166-1 alpha-2 codes
+var countryCodes = map[string]string{
+	"Australia":      "AU",
+	"Austria":        "AT",
+	"Belgium":        "BE",
+	"Bulgaria":       "BG",
+	"Canada":         "CA",
+	"Croatia":        "HR",
+	"Cyprus":         "CY",
+	"Czech Republic": "CZ",
+	"Denmark":        "DK",
+	"Estonia":        "EE",
+	"Finland":        "FI",
+	"France":         "FR",
+	"Germany":        "DE",
+	"Greece":         "GR",
+	"Hungary":        "HU",
+	"India":          "IN",
+	"Indonesia":      "ID",
+	"Ireland":        "IE",
+	"Israel":         "IL",
+	"Italy":          "IT",
+	"Japan":          "JP",
+	"Latvia":         "LV",
+	"Lithuania":      "LT",
+	"Luxembourg":     "LU",
+	"Malta":          "MT",
+	"Netherlands":    "NL",
+	"Norway":         "NO",
+	"Poland":         "PL",
+	"Portugal":       "PT",
+	"Romania":        "RO",
+	"Singapore":      "SG",
+	"Slovakia":       "SK",
+	"Slovenia":       "SI",
+	"Spain":          "ES",
+	"Sweden":         "SE",
+	"Switzerland":    "CH",
+	"United Kingdom": "GB",
+	"United States":  "US",
+}
+
+var euCountryCodes = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "HR": true, "CY": true, "CZ": true, "DK": true,
+	"EE": true, "FI": true, "FR": true, "DE": true, "GR": true, "HU": true, "IE": true,
+	"IT": true, "LV": true, "LT": true, "LU": true, "MT": true, "NL": true, "PL": true,
+	"PT": true, "RO": true, "SK": true, "SI": true, "ES": true, "SE": true,
+}
+
+// vatEndpointSchemes are the PEPPOL electronic address schemes (EAS) of the VAT numbers of a country
+var vatEndpointSchemes = map[string]string{
+	"AT": "9914",
+	"BE": "9925",
+	"DE": "9930",
+	"EE": "9931",
+	"ES": "9920",
+	"FR": "9957",
+	"GB": "9932",
+	"IE": "9935",
+	"IT": "9906",
+	"NL": "9944",
+	"SE": "9955",
+}
+
+// legalEndpointSchemes are the PEPPOL electronic address schemes (EAS) of the company registration
+// numbers of the countries that do not have a VAT based scheme
+var legalEndpointSchemes = map[string]string{
+	"AU": "0151",
+	"JP": "0188",
+	"SG": "0195",
+	"US": "9959",
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of a country name, or an empty string when unknown
+func CountryCode(countryName string) string {
+	return countryCodes[countryName]
+}
+
+// IsEU reports whether the country code is of an EU member state
+func IsEU(countryCode string) bool {
+	return euCountryCodes[countryCode]
+}
+
+// VATEndpoint returns the PEPPOL electronic address of a party identified by its VAT number
+func VATEndpoint(countryCode, vatID string) (endpointID string, schemeID string) {
+	schemeID, ok := vatEndpointSchemes[countryCode]
+	if !ok || vatID == "" {
+		return "", ""
+	}
+
+	return vatID, schemeID
+}
+
+// LegalEndpoint returns the PEPPOL electronic address of a party identified by its company registration number
+func LegalEndpoint(countryCode, legalID string) (endpointID string, schemeID string) {
+	schemeID, ok := legalEndpointSchemes[countryCode]
+	if !ok || legalID == "" {
+		return "", ""
+	}
+
+	return legalID, schemeID
+}
@@ -0,0 +1,184 @@
+package einvoice
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatUBL Format = "ubl"
+	FormatCII Format = "cii"
+)
+
+// TaxCategory is the UNCL5305 code of the VAT category of a line
+type TaxCategory string
+
+const (
+	TaxCategoryStandard      TaxCategory = "S"
+	TaxCategoryZeroRated     TaxCategory = "Z"
+	TaxCategoryExempt        TaxCategory = "E"
+	TaxCategoryReverseCharge TaxCategory = "AE"
+	TaxCategoryExport        TaxCategory = "G"
+	TaxCategoryNotSubject    TaxCategory = "O"
+)
+
+const (
+	invoiceTypeCode        = "380"
+	creditTransferCode     = "30"
+	unitCodeOne            = "C62"
+	customizationID        = "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0"
+	profileID              = "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+	ciiGuidelineID         = "urn:cen.eu:en16931:2017"
+	vatTaxScheme           = "VAT"
+	dateFormat             = "2006-01-02"
+	ciiDateFormat          = "20060102"
+	ciiDateFormatCode      = "102"
+	ciiVATRegistrationCode = "VA"
+)
+
+// exemptionReasons are the VATEX codes and texts required for the categories that do not charge VAT
+var exemptionReasons = map[TaxCategory][2]string{
+	TaxCategoryExempt:        {"VATEX-EU-132", "Exempt from VAT"},
+	TaxCategoryReverseCharge: {"VATEX-EU-AE", "Reverse charge"},
+	TaxCategoryExport:        {"VATEX-EU-G", "Export outside the EU"},
+	TaxCategoryNotSubject:    {"VATEX-EU-O", "Not subject to VAT"},
+}
+
+type Address struct {
+	Lines            []string
+	City             string
+	PostalZone       string
+	CountrySubentity string
+	CountryCode      string
+}
+
+type Party struct {
+	Name  string
+	VATID string
+	// LegalID is the company registration identifier of the party
+	LegalID string
+	// EndpointID is the electronic address of the party on the PEPPOL network
+	EndpointID       string
+	EndpointSchemeID string
+	Address          Address
+}
+
+// Line is an invoice line. NetAmount is the line total after discounts and before tax, and a
+// PeriodStart and PeriodEnd are set for lines of deferred revenue.
+type Line struct {
+	ID          string
+	SKU         string
+	Name        string
+	Description string
+	Quantity    float64
+	UnitPrice   float64
+	NetAmount   float64
+	TaxCategory TaxCategory
+	TaxPercent  float64
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+}
+
+// Document is an issued invoice in the EN16931 semantic model, which both UBL and CII express
+type Document struct {
+	ID             string
+	IssueDate      time.Time
+	DueDate        time.Time
+	Currency       string
+	BuyerReference string
+	Note           string
+	Seller         Party
+	Buyer          Party
+	PayeeIBAN      string
+	Lines          []Line
+}
+
+// TaxSubtotal is the VAT breakdown of a category and rate
+type TaxSubtotal struct {
+	Category      TaxCategory
+	Percent       float64
+	TaxableAmount float64
+	TaxAmount     float64
+}
+
+// Totals are the document level amounts, all derived from the lines
+type Totals struct {
+	LineExtensionAmount float64
+	TaxExclusiveAmount  float64
+	TaxAmount           float64
+	TaxInclusiveAmount  float64
+	PayableAmount       float64
+	TaxSubtotals        []TaxSubtotal
+}
+
+// Totals computes the VAT breakdown and the monetary totals of the document. The tax of every
+// category and rate is computed once on its taxable amount, as the EN16931 rules expect.
+func (d *Document) Totals() Totals {
+	var totals Totals
+
+	type taxKey struct {
+		category TaxCategory
+		percent  float64
+	}
+
+	subtotals := make(map[taxKey]*TaxSubtotal)
+
+	for _, line := range d.Lines {
+		netAmount := round(line.NetAmount)
+		totals.LineExtensionAmount += netAmount
+
+		key := taxKey{line.TaxCategory, line.TaxPercent}
+		if _, ok := subtotals[key]; !ok {
+			subtotals[key] = &TaxSubtotal{Category: line.TaxCategory, Percent: line.TaxPercent}
+		}
+
+		subtotals[key].TaxableAmount += netAmount
+	}
+
+	for _, subtotal := range subtotals {
+		subtotal.TaxableAmount = round(subtotal.TaxableAmount)
+		subtotal.TaxAmount = round(subtotal.TaxableAmount * subtotal.Percent / 100)
+		totals.TaxAmount += subtotal.TaxAmount
+		totals.TaxSubtotals = append(totals.TaxSubtotals, *subtotal)
+	}
+
+	sort.Slice(totals.TaxSubtotals, func(i, j int) bool {
+		if totals.TaxSubtotals[i].Category != totals.TaxSubtotals[j].Category {
+			return totals.TaxSubtotals[i].Category < totals.TaxSubtotals[j].Category
+		}
+
+		return totals.TaxSubtotals[i].Percent < totals.TaxSubtotals[j].Percent
+	})
+
+	totals.LineExtensionAmount = round(totals.LineExtensionAmount)
+	totals.TaxExclusiveAmount = totals.LineExtensionAmount
+	totals.TaxAmount = round(totals.TaxAmount)
+	totals.TaxInclusiveAmount = round(totals.TaxExclusiveAmount + totals.TaxAmount)
+	totals.PayableAmount = totals.TaxInclusiveAmount
+
+	return totals
+}
+
+// Generate validates the document and returns it in the requested format
+func Generate(d *Document, format Format) ([]byte, error) {
+	if err := Validate(d); err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatUBL:
+		return marshalUBL(d)
+	case FormatCII:
+		return marshalCII(d)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// round rounds an amount to the 2 decimals allowed by the EN16931 rules
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,230 @@
+package einvoice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDocument() *Document {
+	periodStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	return &Document{
+		ID:             "IN240001",
+		IssueDate:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:        time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Currency:       "EUR",
+		BuyerReference: "DE000123",
+		Seller: Party{
+			Name:             "DoiT International, DACH GmbH",
+			VATID:            "DE123456789",
+			EndpointID:       "DE123456789",
+			EndpointSchemeID: "9930",
+			Address:          Address{Lines: []string{"Street 1"}, City: "Berlin", PostalZone: "10115", CountryCode: "DE"},
+		},
+		Buyer: Party{
+			Name:             "Customer GmbH",
+			VATID:            "DE987654321",
+			EndpointID:       "DE987654321",
+			EndpointSchemeID: "9930",
+			Address:          Address{Lines: []string{"Street 2", "Floor 3"}, City: "Munich", CountryCode: "DE"},
+		},
+		PayeeIBAN: "DE89502109000218788068",
+		Lines: []Line{
+			{
+				ID:          "1",
+				SKU:         "GCP-SKU",
+				Name:        "Google Cloud",
+				Description: "Billing account 01",
+				Quantity:    1,
+				UnitPrice:   1000,
+				NetAmount:   1000,
+				TaxCategory: TaxCategoryStandard,
+				TaxPercent:  19,
+			},
+			{
+				ID:          "2",
+				Name:        "Support",
+				Quantity:    3,
+				UnitPrice:   33.335,
+				NetAmount:   100.01,
+				TaxCategory: TaxCategoryStandard,
+				TaxPercent:  19,
+				PeriodStart: &periodStart,
+				PeriodEnd:   &periodEnd,
+			},
+		},
+	}
+}
+
+func TestDocument_Totals(t *testing.T) {
+	document := testDocument()
+	document.Lines = append(document.Lines, Line{
+		ID:          "3",
+		Name:        "Training",
+		Quantity:    1,
+		UnitPrice:   50,
+		NetAmount:   50,
+		TaxCategory: TaxCategoryZeroRated,
+	})
+
+	assert.Equal(t, Totals{
+		LineExtensionAmount: 1150.01,
+		TaxExclusiveAmount:  1150.01,
+		TaxAmount:           209,
+		TaxInclusiveAmount:  1359.01,
+		PayableAmount:       1359.01,
+		TaxSubtotals: []TaxSubtotal{
+			{Category: TaxCategoryStandard, Percent: 19, TaxableAmount: 1100.01, TaxAmount: 209},
+			{Category: TaxCategoryZeroRated, TaxableAmount: 50},
+		},
+	}, document.Totals())
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(d *Document)
+		wantRules []string
+	}{
+		{
+			name:   "valid document",
+			modify: func(d *Document) {},
+		},
+		{
+			name: "missing mandatory fields",
+			modify: func(d *Document) {
+				d.ID = ""
+				d.Currency = ""
+				d.BuyerReference = ""
+				d.Buyer.Address.CountryCode = ""
+				d.Buyer.EndpointID = ""
+			},
+			wantRules: []string{"[BR-02]", "[BR-05]", "[BR-11]", "[PEPPOL-EN16931-R003]", "[PEPPOL-EN16931-R010]"},
+		},
+		{
+			name: "invalid lines",
+			modify: func(d *Document) {
+				d.Lines[0].NetAmount = 900
+				d.Lines[1].PeriodEnd, d.Lines[1].PeriodStart = d.Lines[1].PeriodStart, d.Lines[1].PeriodEnd
+				d.Lines[1].TaxPercent = 0
+			},
+			wantRules: []string{"[PEPPOL-EN16931-R120]", "[BR-30]", "[BR-S-05]"},
+		},
+		{
+			name: "reverse charge without the buyer VAT identifier",
+			modify: func(d *Document) {
+				d.Buyer.VATID = ""
+
+				for i := range d.Lines {
+					d.Lines[i].TaxCategory = TaxCategoryReverseCharge
+					d.Lines[i].TaxPercent = 0
+				}
+			},
+			wantRules: []string{"[BR-AE-02]"},
+		},
+		{
+			name: "standard rated without the seller VAT identifier",
+			modify: func(d *Document) {
+				d.Seller.VATID = ""
+				d.Seller.LegalID = "HRB 12345"
+			},
+			wantRules: []string{"[BR-S-02]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := testDocument()
+			tt.modify(document)
+
+			err := Validate(document)
+			if len(tt.wantRules) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, ErrInvalidDocument))
+
+			for _, rule := range tt.wantRules {
+				assert.Contains(t, err.Error(), rule)
+			}
+
+			assert.Equal(t, len(tt.wantRules), strings.Count(err.Error(), "["))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       Format
+		wantContains []string
+		wantErr      error
+	}{
+		{
+			name:   "UBL",
+			format: FormatUBL,
+			wantContains: []string{
+				`<Invoice xmlns="urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"`,
+				`<cbc:CustomizationID>urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0</cbc:CustomizationID>`,
+				`<cbc:IssueDate>2024-02-01</cbc:IssueDate>`,
+				`<cbc:EndpointID schemeID="9930">DE987654321</cbc:EndpointID>`,
+				`<cbc:TaxAmount currencyID="EUR">209.00</cbc:TaxAmount>`,
+				`<cbc:PayableAmount currencyID="EUR">1309.01</cbc:PayableAmount>`,
+				`<cbc:InvoicedQuantity unitCode="C62">3</cbc:InvoicedQuantity>`,
+				`<cbc:StartDate>2024-01-01</cbc:StartDate>`,
+				`<cbc:PriceAmount currencyID="EUR">33.335</cbc:PriceAmount>`,
+				`<cbc:ID>DE89502109000218788068</cbc:ID>`,
+			},
+		},
+		{
+			name:   "CII",
+			format: FormatCII,
+			wantContains: []string{
+				`<rsm:CrossIndustryInvoice xmlns:rsm="urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100"`,
+				`<ram:ID>urn:cen.eu:en16931:2017</ram:ID>`,
+				`<udt:DateTimeString format="102">20240201</udt:DateTimeString>`,
+				`<ram:ID schemeID="VA">DE987654321</ram:ID>`,
+				`<ram:TaxTotalAmount currencyID="EUR">209.00</ram:TaxTotalAmount>`,
+				`<ram:DuePayableAmount>1309.01</ram:DuePayableAmount>`,
+				`<ram:BilledQuantity unitCode="C62">3</ram:BilledQuantity>`,
+				`<udt:DateTimeString format="102">20240131</udt:DateTimeString>`,
+				`<ram:IBANID>DE89502109000218788068</ram:IBANID>`,
+			},
+		},
+		{
+			name:    "unsupported format",
+			format:  "pdf",
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(testDocument(), tt.format)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			for _, want := range tt.wantContains {
+				assert.Contains(t, string(got), want)
+			}
+		})
+	}
+}
+
+func TestGenerate_invalidDocument(t *testing.T) {
+	document := testDocument()
+	document.Lines = nil
+
+	_, err := Generate(document, FormatUBL)
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+}
@@ -0,0 +1,296 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+const (
+	ublInvoiceNamespace = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	ublCACNamespace     = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	ublCBCNamespace     = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+)
+
+// The UBL elements are declared in the order the UBL 2.1 schema requires
+
+type ublInvoice struct {
+	XMLName              xml.Name          `xml:"Invoice"`
+	Xmlns                string            `xml:"xmlns,attr"`
+	XmlnsCAC             string            `xml:"xmlns:cac,attr"`
+	XmlnsCBC             string            `xml:"xmlns:cbc,attr"`
+	CustomizationID      string            `xml:"cbc:CustomizationID"`
+	ProfileID            string            `xml:"cbc:ProfileID"`
+	ID                   string            `xml:"cbc:ID"`
+	IssueDate            string            `xml:"cbc:IssueDate"`
+	DueDate              string            `xml:"cbc:DueDate,omitempty"`
+	InvoiceTypeCode      string            `xml:"cbc:InvoiceTypeCode"`
+	Note                 string            `xml:"cbc:Note,omitempty"`
+	DocumentCurrencyCode string            `xml:"cbc:DocumentCurrencyCode"`
+	BuyerReference       string            `xml:"cbc:BuyerReference"`
+	SupplierParty        ublPartyContainer `xml:"cac:AccountingSupplierParty"`
+	CustomerParty        ublPartyContainer `xml:"cac:AccountingCustomerParty"`
+	PaymentMeans         *ublPaymentMeans  `xml:"cac:PaymentMeans"`
+	TaxTotal             ublTaxTotal       `xml:"cac:TaxTotal"`
+	LegalMonetaryTotal   ublMonetaryTotal  `xml:"cac:LegalMonetaryTotal"`
+	InvoiceLines         []ublInvoiceLine  `xml:"cac:InvoiceLine"`
+}
+
+type ublPartyContainer struct {
+	Party ublParty `xml:"cac:Party"`
+}
+
+type ublParty struct {
+	EndpointID       ublIdentifier       `xml:"cbc:EndpointID"`
+	PostalAddress    ublAddress          `xml:"cac:PostalAddress"`
+	PartyTaxScheme   *ublPartyTaxScheme  `xml:"cac:PartyTaxScheme"`
+	PartyLegalEntity ublPartyLegalEntity `xml:"cac:PartyLegalEntity"`
+}
+
+type ublIdentifier struct {
+	SchemeID string `xml:"schemeID,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+type ublAddress struct {
+	StreetName           string     `xml:"cbc:StreetName,omitempty"`
+	AdditionalStreetName string     `xml:"cbc:AdditionalStreetName,omitempty"`
+	CityName             string     `xml:"cbc:CityName,omitempty"`
+	PostalZone           string     `xml:"cbc:PostalZone,omitempty"`
+	CountrySubentity     string     `xml:"cbc:CountrySubentity,omitempty"`
+	Country              ublCountry `xml:"cac:Country"`
+}
+
+type ublCountry struct {
+	IdentificationCode string `xml:"cbc:IdentificationCode"`
+}
+
+type ublPartyTaxScheme struct {
+	CompanyID string       `xml:"cbc:CompanyID"`
+	TaxScheme ublTaxScheme `xml:"cac:TaxScheme"`
+}
+
+type ublTaxScheme struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type ublPartyLegalEntity struct {
+	RegistrationName string `xml:"cbc:RegistrationName"`
+	CompanyID        string `xml:"cbc:CompanyID,omitempty"`
+}
+
+type ublPaymentMeans struct {
+	PaymentMeansCode      string              `xml:"cbc:PaymentMeansCode"`
+	PayeeFinancialAccount ublFinancialAccount `xml:"cac:PayeeFinancialAccount"`
+}
+
+type ublFinancialAccount struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type ublAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount    ublAmount        `xml:"cbc:TaxAmount"`
+	TaxSubtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	TaxableAmount ublAmount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     ublAmount      `xml:"cbc:TaxAmount"`
+	TaxCategory   ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxCategory struct {
+	ID                     string       `xml:"cbc:ID"`
+	Percent                string       `xml:"cbc:Percent,omitempty"`
+	TaxExemptionReasonCode string       `xml:"cbc:TaxExemptionReasonCode,omitempty"`
+	TaxExemptionReason     string       `xml:"cbc:TaxExemptionReason,omitempty"`
+	TaxScheme              ublTaxScheme `xml:"cac:TaxScheme"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtensionAmount ublAmount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount  ublAmount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount  ublAmount `xml:"cbc:TaxInclusiveAmount"`
+	PayableAmount       ublAmount `xml:"cbc:PayableAmount"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublInvoiceLine struct {
+	ID                  string      `xml:"cbc:ID"`
+	InvoicedQuantity    ublQuantity `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount ublAmount   `xml:"cbc:LineExtensionAmount"`
+	InvoicePeriod       *ublPeriod  `xml:"cac:InvoicePeriod"`
+	Item                ublItem     `xml:"cac:Item"`
+	Price               ublPrice    `xml:"cac:Price"`
+}
+
+type ublPeriod struct {
+	StartDate string `xml:"cbc:StartDate,omitempty"`
+	EndDate   string `xml:"cbc:EndDate,omitempty"`
+}
+
+type ublItem struct {
+	Description               string         `xml:"cbc:Description,omitempty"`
+	Name                      string         `xml:"cbc:Name"`
+	SellersItemIdentification *ublItemID     `xml:"cac:SellersItemIdentification"`
+	ClassifiedTaxCategory     ublTaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
+
+type ublItemID struct {
+	ID string `xml:"cbc:ID"`
+}
+
+type ublPrice struct {
+	PriceAmount ublAmount `xml:"cbc:PriceAmount"`
+}
+
+func marshalUBL(d *Document) ([]byte, error) {
+	totals := d.Totals()
+
+	invoice := ublInvoice{
+		Xmlns:                ublInvoiceNamespace,
+		XmlnsCAC:             ublCACNamespace,
+		XmlnsCBC:             ublCBCNamespace,
+		CustomizationID:      customizationID,
+		ProfileID:            profileID,
+		ID:                   d.ID,
+		IssueDate:            d.IssueDate.Format(dateFormat),
+		InvoiceTypeCode:      invoiceTypeCode,
+		Note:                 d.Note,
+		DocumentCurrencyCode: d.Currency,
+		BuyerReference:       d.BuyerReference,
+		SupplierParty:        ublPartyContainer{Party: toUBLParty(d.Seller)},
+		CustomerParty:        ublPartyContainer{Party: toUBLParty(d.Buyer)},
+		TaxTotal: ublTaxTotal{
+			TaxAmount: d.ublAmount(totals.TaxAmount),
+		},
+		LegalMonetaryTotal: ublMonetaryTotal{
+			LineExtensionAmount: d.ublAmount(totals.LineExtensionAmount),
+			TaxExclusiveAmount:  d.ublAmount(totals.TaxExclusiveAmount),
+			TaxInclusiveAmount:  d.ublAmount(totals.TaxInclusiveAmount),
+			PayableAmount:       d.ublAmount(totals.PayableAmount),
+		},
+	}
+
+	if !d.DueDate.IsZero() {
+		invoice.DueDate = d.DueDate.Format(dateFormat)
+	}
+
+	if d.PayeeIBAN != "" {
+		invoice.PaymentMeans = &ublPaymentMeans{
+			PaymentMeansCode:      creditTransferCode,
+			PayeeFinancialAccount: ublFinancialAccount{ID: d.PayeeIBAN},
+		}
+	}
+
+	for _, subtotal := range totals.TaxSubtotals {
+		taxCategory := toUBLTaxCategory(subtotal.Category, subtotal.Percent)
+		reason := exemptionReasons[subtotal.Category]
+		taxCategory.TaxExemptionReasonCode = reason[0]
+		taxCategory.TaxExemptionReason = reason[1]
+
+		invoice.TaxTotal.TaxSubtotals = append(invoice.TaxTotal.TaxSubtotals, ublTaxSubtotal{
+			TaxableAmount: d.ublAmount(subtotal.TaxableAmount),
+			TaxAmount:     d.ublAmount(subtotal.TaxAmount),
+			TaxCategory:   taxCategory,
+		})
+	}
+
+	for _, line := range d.Lines {
+		invoiceLine := ublInvoiceLine{
+			ID:                  line.ID,
+			InvoicedQuantity:    ublQuantity{UnitCode: unitCodeOne, Value: formatDecimal(line.Quantity)},
+			LineExtensionAmount: d.ublAmount(line.NetAmount),
+			Item: ublItem{
+				Description:           line.Description,
+				Name:                  line.Name,
+				ClassifiedTaxCategory: toUBLTaxCategory(line.TaxCategory, line.TaxPercent),
+			},
+			Price: ublPrice{
+				PriceAmount: ublAmount{CurrencyID: d.Currency, Value: formatDecimal(line.UnitPrice)},
+			},
+		}
+
+		if line.SKU != "" {
+			invoiceLine.Item.SellersItemIdentification = &ublItemID{ID: line.SKU}
+		}
+
+		if line.PeriodStart != nil || line.PeriodEnd != nil {
+			invoiceLine.InvoicePeriod = &ublPeriod{
+				StartDate: formatOptionalDate(line.PeriodStart, dateFormat),
+				EndDate:   formatOptionalDate(line.PeriodEnd, dateFormat),
+			}
+		}
+
+		invoice.InvoiceLines = append(invoice.InvoiceLines, invoiceLine)
+	}
+
+	return marshalXML(invoice)
+}
+
+func toUBLParty(party Party) ublParty {
+	ublParty := ublParty{
+		EndpointID: ublIdentifier{SchemeID: party.EndpointSchemeID, Value: party.EndpointID},
+		PostalAddress: ublAddress{
+			CityName:         party.Address.City,
+			PostalZone:       party.Address.PostalZone,
+			CountrySubentity: party.Address.CountrySubentity,
+			Country:          ublCountry{IdentificationCode: party.Address.CountryCode},
+		},
+		PartyLegalEntity: ublPartyLegalEntity{
+			RegistrationName: party.Name,
+			CompanyID:        party.LegalID,
+		},
+	}
+
+	if len(party.Address.Lines) > 0 {
+		ublParty.PostalAddress.StreetName = party.Address.Lines[0]
+	}
+
+	if len(party.Address.Lines) > 1 {
+		ublParty.PostalAddress.AdditionalStreetName = party.Address.Lines[1]
+	}
+
+	if party.VATID != "" {
+		ublParty.PartyTaxScheme = &ublPartyTaxScheme{
+			CompanyID: party.VATID,
+			TaxScheme: ublTaxScheme{ID: vatTaxScheme},
+		}
+	}
+
+	return ublParty
+}
+
+func toUBLTaxCategory(category TaxCategory, percent float64) ublTaxCategory {
+	taxCategory := ublTaxCategory{
+		ID:        string(category),
+		TaxScheme: ublTaxScheme{ID: vatTaxScheme},
+	}
+
+	// the rate is not given for lines not subject to VAT
+	if category != TaxCategoryNotSubject {
+		taxCategory.Percent = formatDecimal(percent)
+	}
+
+	return taxCategory
+}
+
+func (d *Document) ublAmount(amount float64) ublAmount {
+	return ublAmount{CurrencyID: d.Currency, Value: formatAmount(amount)}
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(round(amount), 'f', 2, 64)
+}
+
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
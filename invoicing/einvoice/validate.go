@@ -0,0 +1,91 @@
+package einvoice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported e-invoice format")
+	ErrInvalidDocument   = errors.New("e-invoice does not satisfy the EN16931 rules")
+)
+
+// lineAmountTolerance is the rounding tolerance of PEPPOL-EN16931-R120 between the line net
+// amount and the quantity times the unit price
+const lineAmountTolerance = 0.02
+
+// Validate checks the document against the EN16931 business rules and the PEPPOL BIS Billing 3.0
+// rules that can be broken by the invoice data. The rules on the totals always hold as they are
+// derived from the lines.
+func Validate(d *Document) error {
+	var violations []error
+
+	check := func(ok bool, rule, format string, args ...interface{}) {
+		if !ok {
+			violations = append(violations, fmt.Errorf("[%s] "+format, append([]interface{}{rule}, args...)...))
+		}
+	}
+
+	check(d.ID != "", "BR-02", "an invoice shall have an invoice number")
+	check(!d.IssueDate.IsZero(), "BR-03", "an invoice shall have an issue date")
+	check(len(d.Currency) == 3, "BR-05", "an invoice shall have a currency code, got %q", d.Currency)
+	check(d.Seller.Name != "", "BR-06", "an invoice shall contain the seller name")
+	check(d.Buyer.Name != "", "BR-07", "an invoice shall contain the buyer name")
+	check(len(d.Seller.Address.CountryCode) == 2, "BR-09", "the seller postal address shall contain a country code, got %q", d.Seller.Address.CountryCode)
+	check(len(d.Buyer.Address.CountryCode) == 2, "BR-11", "the buyer postal address shall contain a country code, got %q", d.Buyer.Address.CountryCode)
+	check(len(d.Lines) > 0, "BR-16", "an invoice shall have at least one invoice line")
+	check(d.BuyerReference != "", "PEPPOL-EN16931-R003", "a buyer reference or purchase order reference must be provided")
+	check(d.Seller.EndpointID != "" && d.Seller.EndpointSchemeID != "", "PEPPOL-EN16931-R020", "seller electronic address must be provided")
+	check(d.Buyer.EndpointID != "" && d.Buyer.EndpointSchemeID != "", "PEPPOL-EN16931-R010", "buyer electronic address must be provided")
+	check(d.Seller.VATID != "" || d.Seller.LegalID != "", "BR-CO-26", "the seller VAT identifier or legal registration identifier shall be present")
+	check(d.DueDate.IsZero() || !d.DueDate.Before(d.IssueDate), "BR-CO-25", "the payment due date shall not be before the issue date")
+
+	categories := make(map[TaxCategory]bool)
+
+	for i, line := range d.Lines {
+		n := i + 1
+
+		check(line.ID != "", "BR-21", "line %d shall have a line identifier", n)
+		check(line.Quantity != 0, "BR-22", "line %d shall have an invoiced quantity", n)
+		check(line.Name != "", "BR-25", "line %d shall contain the item name", n)
+		check(line.UnitPrice >= 0, "BR-27", "line %d item net price shall not be negative", n)
+		check(math.Abs(line.Quantity*line.UnitPrice-line.NetAmount) <= lineAmountTolerance, "PEPPOL-EN16931-R120",
+			"line %d net amount %.2f must equal the quantity times the price %.2f", n, line.NetAmount, line.Quantity*line.UnitPrice)
+		check(line.PeriodStart == nil || line.PeriodEnd == nil || !line.PeriodEnd.Before(*line.PeriodStart), "BR-30",
+			"line %d period end date shall be later or equal to the start date", n)
+
+		switch line.TaxCategory {
+		case TaxCategoryStandard:
+			check(line.TaxPercent > 0, "BR-S-05", "line %d standard rated VAT rate shall be greater than zero", n)
+		case TaxCategoryZeroRated, TaxCategoryExempt, TaxCategoryReverseCharge, TaxCategoryExport, TaxCategoryNotSubject:
+			check(line.TaxPercent == 0, fmt.Sprintf("BR-%s-05", line.TaxCategory), "line %d VAT rate shall be 0 in category %s", n, line.TaxCategory)
+		default:
+			check(false, "BR-CO-04", "line %d shall be categorized with a VAT category code, got %q", n, line.TaxCategory)
+		}
+
+		categories[line.TaxCategory] = true
+	}
+
+	for _, category := range []TaxCategory{TaxCategoryStandard, TaxCategoryZeroRated, TaxCategoryExempt, TaxCategoryExport} {
+		if categories[category] {
+			check(d.Seller.VATID != "", fmt.Sprintf("BR-%s-02", category), "an invoice with category %s lines shall contain the seller VAT identifier", category)
+		}
+	}
+
+	if categories[TaxCategoryReverseCharge] {
+		check(d.Seller.VATID != "", "BR-AE-02", "a reverse charge invoice shall contain the seller VAT identifier")
+		check(d.Buyer.VATID != "", "BR-AE-02", "a reverse charge invoice shall contain the buyer VAT identifier")
+	}
+
+	if categories[TaxCategoryNotSubject] {
+		check(len(categories) == 1, "BR-O-11", "an invoice with a not subject to VAT line shall not have other VAT categories")
+		check(d.Seller.VATID == "" && d.Buyer.VATID == "", "BR-O-02", "a not subject to VAT invoice shall not contain VAT identifiers")
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidDocument, errors.Join(violations...))
+	}
+
+	return nil
+}
@@ -8,4 +8,5 @@ var (
 
 	ErrUnknownBillingDataItemFieldID = errors.New("unknown billing data item field id")
 	ErrNoSuitablePLPSContractFound   = errors.New("no suitable PLPS contract found")
+	ErrInvalidInvoiceMonth           = errors.New("invoice month format should be YYYY-MM")
 )
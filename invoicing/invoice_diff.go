@@ -0,0 +1,140 @@
+package invoicing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/domain"
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/invoicediff"
+)
+
+// InvoiceDiffSource selects the invoices of an entity in an invoicing month. By default these are
+// the draft invoices of the latest run, Timestamp selects the draft invoices of an earlier run and
+// Issued selects the issued invoices instead.
+type InvoiceDiffSource struct {
+	Month     string     `json:"month" binding:"required"`
+	Timestamp *time.Time `json:"timestamp"`
+	Issued    bool       `json:"issued"`
+}
+
+type InvoiceDiffRequest struct {
+	CustomerID string            `json:"customerId" binding:"required"`
+	EntityID   string            `json:"entityId"`
+	Types      []string          `json:"types"`
+	Base       InvoiceDiffSource `json:"base" binding:"required"`
+	Target     InvoiceDiffSource `json:"target" binding:"required"`
+}
+
+// DiffCustomerInvoices compares the invoice rows of the base and the target sources for the
+// requested entity, or for all the entities of the customer when no entity is given.
+func (s *InvoicingService) DiffCustomerInvoices(ctx context.Context, request *InvoiceDiffRequest) (*invoicediff.Result, error) {
+	logger := s.Logger(ctx)
+	fs := s.Firestore(ctx)
+
+	logger.Infof("DiffCustomerInvoices: request params %+v", request)
+
+	entityIDs := []string{request.EntityID}
+
+	if request.EntityID == "" {
+		customerRef := fs.Collection("customers").Doc(request.CustomerID)
+
+		entityDocSnaps, err := fs.Collection("entities").Where("customer", "==", customerRef).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+
+		entityIDs = make([]string, 0, len(entityDocSnaps))
+		for _, docSnap := range entityDocSnaps {
+			entityIDs = append(entityIDs, docSnap.Ref.ID)
+		}
+	}
+
+	var baseRows, targetRows []*domain.InvoiceRow
+
+	for _, entityID := range entityIDs {
+		rows, err := EntityInvoiceRows(ctx, fs, entityID, request.Base, request.Types)
+		if err != nil {
+			return nil, err
+		}
+
+		baseRows = append(baseRows, rows...)
+
+		rows, err = EntityInvoiceRows(ctx, fs, entityID, request.Target, request.Types)
+		if err != nil {
+			return nil, err
+		}
+
+		targetRows = append(targetRows, rows...)
+	}
+
+	return invoicediff.Diff(baseRows, targetRows), nil
+}
+
+// EntityInvoiceRows returns the rows of the invoices of the entity selected by the source,
+// optionally only of the given asset types. Buckets are not saved with the invoice rows, so the
+// rows only carry their entity and rows of different buckets are matched by SKU and details.
+func EntityInvoiceRows(ctx context.Context, fs *firestore.Client, entityID string, source InvoiceDiffSource, types []string) ([]*domain.InvoiceRow, error) {
+	if _, err := time.Parse(InvoiceMonthPattern, source.Month); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInvoiceMonth, source.Month)
+	}
+
+	entityBillingDescriptorRef := fs.Collection("billing").
+		Doc("invoicing").
+		Collection("invoicingMonths").
+		Doc(source.Month).
+		Collection("monthInvoices").
+		Doc(entityID)
+
+	query := entityBillingDescriptorRef.Collection("entityInvoices").Query
+
+	switch {
+	case source.Issued:
+		query = query.Where("issuedAt", "!=", "")
+	case source.Timestamp != nil:
+		query = query.Where("timestamp", "==", *source.Timestamp)
+	default:
+		docSnap, err := entityBillingDescriptorRef.Get(ctx)
+		if err != nil {
+			// no invoices were generated for the entity in this month
+			if status.Code(err) == codes.NotFound {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		var billingDescriptor EntityBillingDescriptor
+		if err := docSnap.DataTo(&billingDescriptor); err != nil {
+			return nil, err
+		}
+
+		query = query.Where("timestamp", "==", billingDescriptor.Timestamp)
+	}
+
+	if len(types) > 0 {
+		query = query.Where("type", "in", types)
+	}
+
+	invoices, err := entityInvoices(query.Documents(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	entityRef := fs.Collection("entities").Doc(entityID)
+
+	var rows []*domain.InvoiceRow
+
+	for _, invoice := range invoices {
+		for _, row := range invoice.Rows {
+			row.Entity = entityRef
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
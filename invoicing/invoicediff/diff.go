@@ -0,0 +1,234 @@
+package invoicediff
+
+import (
+	"math"
+	"sort"
+
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/domain"
+)
+
+type Status string
+
+const (
+	StatusAdded   Status = "added"
+	StatusRemoved Status = "removed"
+	StatusChanged Status = "changed"
+
+	// epsilon absorbs the rounding noise of recalculated amounts
+	epsilon = 1e-6
+)
+
+// Key identifies the same line item across two sets of invoice rows
+type Key struct {
+	SKU      string `json:"sku"`
+	Details  string `json:"details"`
+	EntityID string `json:"entityId"`
+	BucketID string `json:"bucketId"`
+}
+
+// Line is the aggregation of the invoice rows of a key
+type Line struct {
+	Description string  `json:"description"`
+	Type        string  `json:"type"`
+	Currency    string  `json:"currency"`
+	Quantity    int64   `json:"quantity"`
+	PPU         float64 `json:"ppu"`
+	Discount    float64 `json:"discount"`
+	Total       float64 `json:"total"`
+}
+
+// RowDiff is an added, removed or changed line item. Base is nil for added rows and Target is
+// nil for removed rows, deltas are always target minus base.
+type RowDiff struct {
+	Key
+	Status        Status  `json:"status"`
+	Base          *Line   `json:"base"`
+	Target        *Line   `json:"target"`
+	QuantityDelta int64   `json:"quantityDelta"`
+	PPUDelta      float64 `json:"ppuDelta"`
+	DiscountDelta float64 `json:"discountDelta"`
+	TotalDelta    float64 `json:"totalDelta"`
+}
+
+// Result is the line level difference between a base and a target set of invoice rows
+type Result struct {
+	Added   []*RowDiff `json:"added"`
+	Removed []*RowDiff `json:"removed"`
+	Changed []*RowDiff `json:"changed"`
+
+	// Totals are per currency as the rows of an entity are not always in the same currency
+	BaseTotals       map[string]float64 `json:"baseTotals"`
+	TargetTotals     map[string]float64 `json:"targetTotals"`
+	TotalDeltas      map[string]float64 `json:"totalDeltas"`
+	NumUnchangedRows int                `json:"numUnchangedRows"`
+}
+
+// KeyOf returns the key of an invoice row
+func KeyOf(row *domain.InvoiceRow) Key {
+	key := Key{
+		SKU:     row.SKU,
+		Details: row.Details,
+	}
+
+	if row.Entity != nil {
+		key.EntityID = row.Entity.ID
+	}
+
+	if row.Bucket != nil {
+		key.BucketID = row.Bucket.ID
+	}
+
+	return key
+}
+
+// Diff matches the base and the target rows by key and returns the rows that were added, removed
+// or changed in the target. Rows sharing a key are aggregated before they are compared.
+func Diff(base, target []*domain.InvoiceRow) *Result {
+	baseLines, baseTotals := aggregate(base)
+	targetLines, targetTotals := aggregate(target)
+
+	result := &Result{
+		Added:        make([]*RowDiff, 0),
+		Removed:      make([]*RowDiff, 0),
+		Changed:      make([]*RowDiff, 0),
+		BaseTotals:   baseTotals,
+		TargetTotals: targetTotals,
+		TotalDeltas:  make(map[string]float64),
+	}
+
+	for currency, total := range targetTotals {
+		result.TotalDeltas[currency] = total - baseTotals[currency]
+	}
+
+	for currency, total := range baseTotals {
+		if _, ok := targetTotals[currency]; !ok {
+			result.TotalDeltas[currency] = -total
+		}
+	}
+
+	for key, targetLine := range targetLines {
+		baseLine, ok := baseLines[key]
+		if !ok {
+			result.Added = append(result.Added, newRowDiff(key, StatusAdded, nil, targetLine))
+			continue
+		}
+
+		rowDiff := newRowDiff(key, StatusChanged, baseLine, targetLine)
+		if rowDiff.isZero() {
+			result.NumUnchangedRows++
+			continue
+		}
+
+		result.Changed = append(result.Changed, rowDiff)
+	}
+
+	for key, baseLine := range baseLines {
+		if _, ok := targetLines[key]; !ok {
+			result.Removed = append(result.Removed, newRowDiff(key, StatusRemoved, baseLine, nil))
+		}
+	}
+
+	sortRowDiffs(result.Added)
+	sortRowDiffs(result.Removed)
+	sortRowDiffs(result.Changed)
+
+	return result
+}
+
+// HasChanges reports whether any row was added, removed or changed
+func (r *Result) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// aggregate sums the rows of every key. The unit price and the discount of a key are averaged
+// weighted by the quantity of its rows.
+func aggregate(rows []*domain.InvoiceRow) (map[Key]*Line, map[string]float64) {
+	lines := make(map[Key]*Line)
+	totals := make(map[string]float64)
+
+	for _, row := range rows {
+		if row == nil {
+			continue
+		}
+
+		totals[row.Currency] += row.Total
+
+		key := KeyOf(row)
+
+		line, ok := lines[key]
+		if !ok {
+			lines[key] = &Line{
+				Description: row.Description,
+				Type:        row.Type,
+				Currency:    row.Currency,
+				Quantity:    row.Quantity,
+				PPU:         row.PPU,
+				Discount:    row.Discount,
+				Total:       row.Total,
+			}
+
+			continue
+		}
+
+		quantity := line.Quantity + row.Quantity
+		if quantity != 0 {
+			line.PPU = (line.PPU*float64(line.Quantity) + row.PPU*float64(row.Quantity)) / float64(quantity)
+			line.Discount = (line.Discount*float64(line.Quantity) + row.Discount*float64(row.Quantity)) / float64(quantity)
+		}
+
+		line.Quantity = quantity
+		line.Total += row.Total
+	}
+
+	return lines, totals
+}
+
+func newRowDiff(key Key, status Status, base, target *Line) *RowDiff {
+	rowDiff := &RowDiff{
+		Key:    key,
+		Status: status,
+		Base:   base,
+		Target: target,
+	}
+
+	var baseLine, targetLine Line
+
+	if base != nil {
+		baseLine = *base
+	}
+
+	if target != nil {
+		targetLine = *target
+	}
+
+	rowDiff.QuantityDelta = targetLine.Quantity - baseLine.Quantity
+	rowDiff.PPUDelta = targetLine.PPU - baseLine.PPU
+	rowDiff.DiscountDelta = targetLine.Discount - baseLine.Discount
+	rowDiff.TotalDelta = targetLine.Total - baseLine.Total
+
+	return rowDiff
+}
+
+func (d *RowDiff) isZero() bool {
+	return d.QuantityDelta == 0 &&
+		math.Abs(d.PPUDelta) < epsilon &&
+		math.Abs(d.DiscountDelta) < epsilon &&
+		math.Abs(d.TotalDelta) < epsilon
+}
+
+func sortRowDiffs(rowDiffs []*RowDiff) {
+	sort.Slice(rowDiffs, func(i, j int) bool {
+		a, b := rowDiffs[i].Key, rowDiffs[j].Key
+
+		switch {
+		case a.EntityID != b.EntityID:
+			return a.EntityID < b.EntityID
+		case a.BucketID != b.BucketID:
+			return a.BucketID < b.BucketID
+		case a.SKU != b.SKU:
+			return a.SKU < b.SKU
+		default:
+			return a.Details < b.Details
+		}
+	})
+}
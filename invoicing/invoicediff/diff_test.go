@@ -0,0 +1,182 @@
+package invoicediff
+
+import (
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/doitintl/hello/scheduled-tasks/invoicing/domain"
+)
+
+func TestDiff(t *testing.T) {
+	entity := &firestore.DocumentRef{ID: "entity-1"}
+	bucket := &firestore.DocumentRef{ID: "bucket-1"}
+
+	row := func(sku, details string, bucket *firestore.DocumentRef, quantity int64, ppu, discount, total float64) *domain.InvoiceRow {
+		return &domain.InvoiceRow{
+			Description: "description",
+			Details:     details,
+			SKU:         sku,
+			Quantity:    quantity,
+			PPU:         ppu,
+			Discount:    discount,
+			Total:       total,
+			Currency:    "USD",
+			Type:        "google-cloud",
+			Entity:      entity,
+			Bucket:      bucket,
+		}
+	}
+
+	line := func(quantity int64, ppu, discount, total float64) *Line {
+		return &Line{
+			Description: "description",
+			Type:        "google-cloud",
+			Currency:    "USD",
+			Quantity:    quantity,
+			PPU:         ppu,
+			Discount:    discount,
+			Total:       total,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		base   []*domain.InvoiceRow
+		target []*domain.InvoiceRow
+		want   *Result
+	}{
+		{
+			name: "no rows",
+			want: &Result{
+				Added:        []*RowDiff{},
+				Removed:      []*RowDiff{},
+				Changed:      []*RowDiff{},
+				BaseTotals:   map[string]float64{},
+				TargetTotals: map[string]float64{},
+				TotalDeltas:  map[string]float64{},
+			},
+		},
+		{
+			name: "added, removed, changed and unchanged rows",
+			base: []*domain.InvoiceRow{
+				row("sku-1", "project-1", nil, 10, 2, 0, 20),
+				row("sku-2", "project-1", nil, 5, 1, 0, 5),
+				row("sku-3", "project-1", bucket, 1, 100, 10, 90),
+			},
+			target: []*domain.InvoiceRow{
+				row("sku-1", "project-1", nil, 10, 2, 0, 20),
+				row("sku-3", "project-1", bucket, 2, 100, 5, 190),
+				row("sku-4", "project-2", nil, 3, 1, 0, 3),
+			},
+			want: &Result{
+				Added: []*RowDiff{
+					{
+						Key:           Key{SKU: "sku-4", Details: "project-2", EntityID: "entity-1"},
+						Status:        StatusAdded,
+						Target:        line(3, 1, 0, 3),
+						QuantityDelta: 3,
+						PPUDelta:      1,
+						TotalDelta:    3,
+					},
+				},
+				Removed: []*RowDiff{
+					{
+						Key:           Key{SKU: "sku-2", Details: "project-1", EntityID: "entity-1"},
+						Status:        StatusRemoved,
+						Base:          line(5, 1, 0, 5),
+						QuantityDelta: -5,
+						PPUDelta:      -1,
+						TotalDelta:    -5,
+					},
+				},
+				Changed: []*RowDiff{
+					{
+						Key:           Key{SKU: "sku-3", Details: "project-1", EntityID: "entity-1", BucketID: "bucket-1"},
+						Status:        StatusChanged,
+						Base:          line(1, 100, 10, 90),
+						Target:        line(2, 100, 5, 190),
+						QuantityDelta: 1,
+						DiscountDelta: -5,
+						TotalDelta:    100,
+					},
+				},
+				BaseTotals:       map[string]float64{"USD": 115},
+				TargetTotals:     map[string]float64{"USD": 213},
+				TotalDeltas:      map[string]float64{"USD": 98},
+				NumUnchangedRows: 1,
+			},
+		},
+		{
+			name: "rows sharing a key are aggregated",
+			base: []*domain.InvoiceRow{
+				row("sku-1", "project-1", nil, 10, 2, 0, 20),
+			},
+			target: []*domain.InvoiceRow{
+				row("sku-1", "project-1", nil, 5, 2, 0, 10),
+				row("sku-1", "project-1", nil, 5, 4, 0, 20),
+			},
+			want: &Result{
+				Added:   []*RowDiff{},
+				Removed: []*RowDiff{},
+				Changed: []*RowDiff{
+					{
+						Key:        Key{SKU: "sku-1", Details: "project-1", EntityID: "entity-1"},
+						Status:     StatusChanged,
+						Base:       line(10, 2, 0, 20),
+						Target:     line(10, 3, 0, 30),
+						PPUDelta:   1,
+						TotalDelta: 10,
+					},
+				},
+				BaseTotals:   map[string]float64{"USD": 20},
+				TargetTotals: map[string]float64{"USD": 30},
+				TotalDeltas:  map[string]float64{"USD": 10},
+			},
+		},
+		{
+			name: "rows of different buckets do not match",
+			base: []*domain.InvoiceRow{
+				row("sku-1", "project-1", nil, 1, 1, 0, 1),
+			},
+			target: []*domain.InvoiceRow{
+				row("sku-1", "project-1", bucket, 1, 1, 0, 1),
+			},
+			want: &Result{
+				Added: []*RowDiff{
+					{
+						Key:           Key{SKU: "sku-1", Details: "project-1", EntityID: "entity-1", BucketID: "bucket-1"},
+						Status:        StatusAdded,
+						Target:        line(1, 1, 0, 1),
+						QuantityDelta: 1,
+						PPUDelta:      1,
+						TotalDelta:    1,
+					},
+				},
+				Removed: []*RowDiff{
+					{
+						Key:           Key{SKU: "sku-1", Details: "project-1", EntityID: "entity-1"},
+						Status:        StatusRemoved,
+						Base:          line(1, 1, 0, 1),
+						QuantityDelta: -1,
+						PPUDelta:      -1,
+						TotalDelta:    -1,
+					},
+				},
+				Changed:      []*RowDiff{},
+				BaseTotals:   map[string]float64{"USD": 1},
+				TargetTotals: map[string]float64{"USD": 1},
+				TotalDeltas:  map[string]float64{"USD": 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.base, tt.target)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.want.Added)+len(tt.want.Removed)+len(tt.want.Changed) > 0, got.HasChanges())
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package msteams
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// Adaptive card element and action types
+const (
+	ElementTypeTextBlock = "TextBlock"
+	ElementTypeFactSet   = "FactSet"
+	ElementTypeImage     = "Image"
+	ActionTypeOpenURL    = "Action.OpenUrl"
+)
+
+// Message is the payload of a Teams incoming webhook
+type Message struct {
+	Type        string       `json:"type"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+type Attachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard is the content of a Teams message. See https://adaptivecards.io/explorer/
+type AdaptiveCard struct {
+	Schema  string    `json:"$schema"`
+	Type    string    `json:"type"`
+	Version string    `json:"version"`
+	Body    []Element `json:"body"`
+	Actions []Action  `json:"actions,omitempty"`
+}
+
+// Element is an element of the card body, the fields that are set depend on its type
+type Element struct {
+	Type    string `json:"type"`
+	Text    string `json:"text,omitempty"`
+	Wrap    bool   `json:"wrap,omitempty"`
+	Weight  string `json:"weight,omitempty"`
+	Size    string `json:"size,omitempty"`
+	Facts   []Fact `json:"facts,omitempty"`
+	URL     string `json:"url,omitempty"`
+	AltText string `json:"altText,omitempty"`
+}
+
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type Action struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// NewAdaptiveCard returns an empty card
+func NewAdaptiveCard() *AdaptiveCard {
+	return &AdaptiveCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+		Body:    []Element{},
+	}
+}
+
+// AddTitle adds a bold heading to the card
+func (c *AdaptiveCard) AddTitle(text string) *AdaptiveCard {
+	c.Body = append(c.Body, Element{
+		Type:   ElementTypeTextBlock,
+		Text:   text,
+		Wrap:   true,
+		Weight: "Bolder",
+		Size:   "Medium",
+	})
+
+	return c
+}
+
+// AddText adds a paragraph to the card. The text supports the markdown subset of Teams
+func (c *AdaptiveCard) AddText(text string) *AdaptiveCard {
+	c.Body = append(c.Body, Element{
+		Type: ElementTypeTextBlock,
+		Text: text,
+		Wrap: true,
+	})
+
+	return c
+}
+
+// AddFacts adds a list of title and value pairs to the card
+func (c *AdaptiveCard) AddFacts(facts ...Fact) *AdaptiveCard {
+	if len(facts) == 0 {
+		return c
+	}
+
+	c.Body = append(c.Body, Element{
+		Type:  ElementTypeFactSet,
+		Facts: facts,
+	})
+
+	return c
+}
+
+// AddImage adds an image to the card
+func (c *AdaptiveCard) AddImage(url, altText string) *AdaptiveCard {
+	c.Body = append(c.Body, Element{
+		Type:    ElementTypeImage,
+		URL:     url,
+		AltText: altText,
+	})
+
+	return c
+}
+
+// AddOpenURLAction adds a button that opens the url
+func (c *AdaptiveCard) AddOpenURLAction(title, url string) *AdaptiveCard {
+	c.Actions = append(c.Actions, Action{
+		Type:  ActionTypeOpenURL,
+		Title: title,
+		URL:   url,
+	})
+
+	return c
+}
+
+// NewMessage wraps the card in an incoming webhook message
+func NewMessage(card *AdaptiveCard) *Message {
+	return &Message{
+		Type: "message",
+		Attachments: []Attachment{
+			{
+				ContentType: adaptiveCardContentType,
+				Content:     card,
+			},
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package msteams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidWebhookURL = errors.New("invalid Microsoft Teams webhook url")
+)
+
+// webhookHostSuffixes are the hosts of Teams incoming webhooks and of Power Automate workflows that post to Teams
+var webhookHostSuffixes = []string{
+	".webhook.office.com",
+	".logic.azure.com",
+	".environment.api.powerplatform.com",
+}
+
+//go:generate mockery --name Notifier --output=./mocks
+type Notifier interface {
+	Post(ctx context.Context, webhookURL string, card *AdaptiveCard) error
+}
+
+type WebhookNotifier struct {
+	c *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		c: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Post sends the card to a Teams channel through its incoming webhook
+func (n *WebhookNotifier) Post(ctx context.Context, webhookURL string, card *AdaptiveCard) error {
+	body, err := json.Marshal(NewMessage(card))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("microsoft teams webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ValidateWebhookURL checks that the url is an https url of a Teams incoming webhook or workflow
+func ValidateWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" {
+		return ErrInvalidWebhookURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, suffix := range webhookHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return nil
+		}
+	}
+
+	return ErrInvalidWebhookURL
+}
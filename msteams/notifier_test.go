@@ -0,0 +1,83 @@
+package msteams
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifier_Post(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+		},
+		{
+			name:    "webhook error",
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				var message Message
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+				assert.Equal(t, "message", message.Type)
+				assert.Len(t, message.Attachments, 1)
+				assert.Equal(t, adaptiveCardContentType, message.Attachments[0].ContentType)
+				assert.Equal(t, "Budget Alert", message.Attachments[0].Content.Body[0].Text)
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			n := &WebhookNotifier{c: server.Client()}
+			card := NewAdaptiveCard().AddTitle("Budget Alert").AddOpenURLAction("Open", "https://console.doit.com")
+
+			err := n.Post(context.Background(), server.URL, card)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://doit.webhook.office.com/webhookb2/abc"},
+		{url: "https://prod-01.westus.logic.azure.com:443/workflows/abc"},
+		{url: "http://doit.webhook.office.com/webhookb2/abc", wantErr: true},
+		{url: "https://webhook.office.com.example.com/abc", wantErr: true},
+		{url: "not a url", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateWebhookURL(tt.url)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWebhookURL)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -68,6 +68,10 @@ type CompanyInfo struct {
 	CompanyName  string         `firestore:"companyName"`
 	Countries    []string       `firestore:"countries"`
 	WireTransfer []KeyValuePair `firestore:"wireTransfer"`
+	// VATID is the tax registration of the company, required to issue structured e-invoices
+	VATID string `firestore:"vatId"`
+	// LegalID is the company registration number, identifying the seller of e-invoices not subject to VAT
+	LegalID string `firestore:"legalId"`
 }
 
 type KeyValuePair struct {
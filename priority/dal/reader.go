@@ -39,7 +39,7 @@ func (d *dal) GetCustomers(ctx context.Context, priorityCompany priority.Company
 		URL:          fmt.Sprintf("/%s/CUSTOMERS", priorityCompany),
 		ResponseType: &resp,
 		QueryParams: map[string][]string{
-			QueryParamSelect: {"CUSTNAME,CUSTDES,COUNTRYNAME,PAYDES,INACTIVEFLAG,STATE,STATEA,STATECODE,STATENAME,ADDRESS,ADDRESS2,ADDRESS3,ZIP"},
+			QueryParamSelect: {"CUSTNAME,CUSTDES,COUNTRYNAME,PAYDES,INACTIVEFLAG,STATE,STATEA,STATECODE,STATENAME,ADDRESS,ADDRESS2,ADDRESS3,ZIP,VATNUM"},
 			QueryParamExpand: {"CUSTPERSONNEL_SUBFORM($select=NAME,FIRM,FIRSTNAME,LASTNAME,EMAIL,PHONENUM,CIVFLAG;$filter=CIVFLAG eq 'Y')"},
 		},
 	}
@@ -18,6 +18,7 @@ type Customer struct {
 	StateCode    *string              `json:"STATECODE"`
 	StateName    *string              `json:"STATENAME"`
 	Zip          *string              `json:"ZIP"`
+	VATNum       *string              `json:"VATNUM"`
 	Personnel    []*CustomerPersonnel `json:"CUSTPERSONNEL_SUBFORM"`
 }
 
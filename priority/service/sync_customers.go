@@ -32,6 +32,7 @@ var mergePaths = []firestore.FieldPath{
 	[]string{"active"},
 	[]string{"contact"},
 	[]string{"billingAddress"},
+	[]string{"vatNumber"},
 }
 
 func (s *service) SyncCustomers(ctx context.Context) error {
@@ -117,6 +118,7 @@ func (s *service) SyncCustomers(ctx context.Context) error {
 					entity.LowerName = strings.ToLower(entityDetails.customer.Name)
 					entity.Country = entityDetails.customer.CountryName
 					entity.Active = entityDetails.customer.InactiveFlag == nil
+					entity.VATNumber = entityDetails.customer.VATNum
 					entity.Currency = entityDetails.accountReceivable.Code
 					entity.BillingAddress = common.BillingAddress{
 						Address:     entityDetails.customer.Address,
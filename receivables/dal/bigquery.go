@@ -0,0 +1,322 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"google.golang.org/api/iterator"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/domain"
+)
+
+const (
+	datasetID     = "analytics"
+	agingTableID  = "receivables_aging"
+	kpisTableID   = "receivables_kpis"
+	insertBatch   = 1000
+	moduleLabel   = "receivables"
+	featureLabel  = "receivables-analytics"
+	partitionedBy = "snapshot_date"
+)
+
+// latestSnapshotsCTE selects the insert timestamp of the last run of each snapshot date, a snapshot
+// that is taken more than once a day keeps only the rows of its last run
+const latestSnapshotsCTE = `latest AS (
+	SELECT snapshot_date, MAX(insert_timestamp) AS insert_timestamp
+	FROM %[1]s
+	WHERE snapshot_date BETWEEN @from AND @to
+	GROUP BY snapshot_date
+)`
+
+const averageAccountReceivablesQuery = "SELECT ENTITY_ID, AVG_AR_DAYS, AVG_LATE_AR_DAYS FROM `me-doit-intl-com.stored_queries.average_account_receivables`"
+
+var agingGroupByColumns = map[domain.AgingGroupBy]string{
+	domain.AgingGroupByEntity:   "entity_id",
+	domain.AgingGroupByCustomer: "customer_id",
+	domain.AgingGroupByCurrency: "currency",
+}
+
+type BigQuery struct {
+	BigQueryClientFun connection.BigQueryFromContextFun
+}
+
+func NewReceivablesBigQueryWithClient(fun connection.BigQueryFromContextFun) *BigQuery {
+	return &BigQuery{
+		BigQueryClientFun: fun,
+	}
+}
+
+// InsertAging appends the aging rows of a snapshot to the receivables aging table
+func (d *BigQuery) InsertAging(ctx context.Context, rows []*domain.AgingRow) error {
+	savers := make([]*bigquery.StructSaver, len(rows))
+	for i, row := range rows {
+		savers[i] = &bigquery.StructSaver{Schema: AgingTableSchema, Struct: row}
+	}
+
+	return d.insert(ctx, agingTableID, AgingTableSchema, savers)
+}
+
+// InsertKPIs appends the KPI rows of a snapshot to the receivables KPIs table
+func (d *BigQuery) InsertKPIs(ctx context.Context, rows []*domain.KPIRow) error {
+	savers := make([]*bigquery.StructSaver, len(rows))
+	for i, row := range rows {
+		savers[i] = &bigquery.StructSaver{Schema: KPIsTableSchema, Struct: row}
+	}
+
+	return d.insert(ctx, kpisTableID, KPIsTableSchema, savers)
+}
+
+func (d *BigQuery) insert(ctx context.Context, tableID string, schema bigquery.Schema, savers []*bigquery.StructSaver) error {
+	bq := d.BigQueryClientFun(ctx)
+	tableRef := bq.Dataset(datasetID).Table(tableID)
+
+	exists, _, err := common.BigQueryTableExists(ctx, bq, bq.Project(), datasetID, tableID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := tableRef.Create(ctx, &bigquery.TableMetadata{
+			Schema: schema,
+			TimePartitioning: &bigquery.TimePartitioning{
+				Field: partitionedBy,
+				Type:  bigquery.DayPartitioningType,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	inserter := tableRef.Inserter()
+
+	for start := 0; start < len(savers); start += insertBatch {
+		end := start + insertBatch
+		if end > len(savers) {
+			end = len(savers)
+		}
+
+		if err := inserter.Put(ctx, savers[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetEntitiesReceivables returns the open balance in USD of each entity on the snapshot date,
+// or nil when there is no snapshot of that date
+func (d *BigQuery) GetEntitiesReceivables(ctx context.Context, date civil.Date) (map[string]float64, error) {
+	bq := d.BigQueryClientFun(ctx)
+
+	exists, _, err := common.BigQueryTableExists(ctx, bq, bq.Project(), datasetID, agingTableID)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	queryString := fmt.Sprintf(`WITH `+latestSnapshotsCTE+`
+SELECT entity_id, SUM(debit_usd) AS receivables_usd
+FROM %[1]s
+JOIN latest USING (snapshot_date, insert_timestamp)
+GROUP BY entity_id`, d.tableName(bq, agingTableID))
+
+	type entityReceivables struct {
+		EntityID    string  `bigquery:"entity_id"`
+		Receivables float64 `bigquery:"receivables_usd"`
+	}
+
+	var result map[string]float64
+
+	err = d.read(ctx, queryString, []bigquery.QueryParameter{
+		{Name: "from", Value: date},
+		{Name: "to", Value: date},
+	}, func(iter *bigquery.RowIterator) error {
+		var row entityReceivables
+		if err := iter.Next(&row); err != nil {
+			return err
+		}
+
+		if result == nil {
+			result = make(map[string]float64)
+		}
+
+		result[row.EntityID] = row.Receivables
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetCollectionDays returns the historical average collection days of each entity, from the same
+// stored query the entities account receivables metadata is updated from
+func (d *BigQuery) GetCollectionDays(ctx context.Context) (map[string]*domain.CollectionDays, error) {
+	result := make(map[string]*domain.CollectionDays)
+
+	if err := d.read(ctx, averageAccountReceivablesQuery, nil, func(iter *bigquery.RowIterator) error {
+		var row domain.CollectionDays
+		if err := iter.Next(&row); err != nil {
+			return err
+		}
+
+		result[row.EntityID] = &row
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetAging returns the open balance per aging bucket on the snapshot date, grouped by entity, customer
+// or currency. The customer filter is optional.
+func (d *BigQuery) GetAging(ctx context.Context, date civil.Date, groupBy domain.AgingGroupBy, customerID string) ([]*domain.AgingSummary, error) {
+	column, ok := agingGroupByColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid aging group by %q", groupBy)
+	}
+
+	totalCurrency := "CAST(NULL AS FLOAT64)"
+	if groupBy == domain.AgingGroupByCurrency {
+		totalCurrency = "SUM(debit)"
+	}
+
+	bq := d.BigQueryClientFun(ctx)
+
+	queryString := fmt.Sprintf(`WITH `+latestSnapshotsCTE+`
+SELECT
+	%[2]s AS key,
+	SUM(IF(bucket = '%[4]s', debit_usd, 0)) AS current,
+	SUM(IF(bucket = '%[5]s', debit_usd, 0)) AS days_1_30,
+	SUM(IF(bucket = '%[6]s', debit_usd, 0)) AS days_31_60,
+	SUM(IF(bucket = '%[7]s', debit_usd, 0)) AS days_61_90,
+	SUM(IF(bucket = '%[8]s', debit_usd, 0)) AS over_90,
+	SUM(debit_usd) AS total,
+	%[3]s AS total_currency,
+	SUM(invoices_count) AS invoices_count
+FROM %[1]s
+JOIN latest USING (snapshot_date, insert_timestamp)
+WHERE @customerID = '' OR customer_id = @customerID
+GROUP BY key
+ORDER BY total DESC`,
+		d.tableName(bq, agingTableID),
+		column,
+		totalCurrency,
+		domain.AgingBucketCurrent,
+		domain.AgingBucket1To30,
+		domain.AgingBucket31To60,
+		domain.AgingBucket61To90,
+		domain.AgingBucketOver90,
+	)
+
+	result := make([]*domain.AgingSummary, 0)
+
+	if err := d.read(ctx, queryString, []bigquery.QueryParameter{
+		{Name: "from", Value: date},
+		{Name: "to", Value: date},
+		{Name: "customerID", Value: customerID},
+	}, func(iter *bigquery.RowIterator) error {
+		var row domain.AgingSummary
+		if err := iter.Next(&row); err != nil {
+			return err
+		}
+
+		result = append(result, &row)
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetKPITrend returns the daily days sales outstanding and collection effectiveness index between the
+// given dates, recomputed from the summed components of all the entities that match the optional filters
+func (d *BigQuery) GetKPITrend(ctx context.Context, from, to civil.Date, entityID, customerID string) ([]*domain.KPITrendPoint, error) {
+	bq := d.BigQueryClientFun(ctx)
+
+	queryString := fmt.Sprintf(`WITH `+latestSnapshotsCTE+`
+SELECT
+	snapshot_date,
+	SUM(receivables_usd) AS receivables_usd,
+	SUM(current_receivables_usd) AS current_receivables_usd,
+	SUM(sales_usd) AS sales_usd,
+	IF(SUM(sales_usd) > 0, SUM(receivables_usd) / SUM(sales_usd) * ANY_VALUE(period_days), NULL) AS dso,
+	IF(
+		COUNTIF(beginning_receivables_usd IS NULL) = 0
+			AND SUM(beginning_receivables_usd) + SUM(sales_usd) - SUM(current_receivables_usd) > 0,
+		(SUM(beginning_receivables_usd) + SUM(sales_usd) - SUM(receivables_usd))
+			/ (SUM(beginning_receivables_usd) + SUM(sales_usd) - SUM(current_receivables_usd)) * 100,
+		NULL
+	) AS cei,
+	AVG(avg_ar_days) AS avg_ar_days,
+	AVG(avg_late_ar_days) AS avg_late_ar_days
+FROM %[1]s
+JOIN latest USING (snapshot_date, insert_timestamp)
+WHERE (@entityID = '' OR entity_id = @entityID)
+	AND (@customerID = '' OR customer_id = @customerID)
+GROUP BY snapshot_date
+ORDER BY snapshot_date`, d.tableName(bq, kpisTableID))
+
+	result := make([]*domain.KPITrendPoint, 0)
+
+	if err := d.read(ctx, queryString, []bigquery.QueryParameter{
+		{Name: "from", Value: from},
+		{Name: "to", Value: to},
+		{Name: "entityID", Value: entityID},
+		{Name: "customerID", Value: customerID},
+	}, func(iter *bigquery.RowIterator) error {
+		var row domain.KPITrendPoint
+		if err := iter.Next(&row); err != nil {
+			return err
+		}
+
+		result = append(result, &row)
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// read runs a query and calls next until the rows iterator is done
+func (d *BigQuery) read(ctx context.Context, queryString string, params []bigquery.QueryParameter, next func(iter *bigquery.RowIterator) error) error {
+	query := d.BigQueryClientFun(ctx).Query(queryString)
+	query.Parameters = params
+	query.Labels = map[string]string{
+		common.LabelKeyHouse.String():   common.HouseData.String(),
+		common.LabelKeyEnv.String():     common.GetEnvironmentLabel(),
+		common.LabelKeyModule.String():  moduleLabel,
+		common.LabelKeyFeature.String(): featureLabel,
+	}
+
+	iter, err := query.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := next(iter)
+		if err == iterator.Done {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (d *BigQuery) tableName(bq *bigquery.Client, tableID string) string {
+	return fmt.Sprintf("`%s.%s.%s`", bq.Project(), datasetID, tableID)
+}
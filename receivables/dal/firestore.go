@@ -0,0 +1,56 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/api/iterator"
+
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/domain"
+)
+
+const invoicesCollection = "invoices"
+
+// ReceivablesFirestore reads the issued invoices the receivables KPIs are computed from
+type ReceivablesFirestore struct {
+	firestoreClientFun connection.FirestoreFromContextFun
+}
+
+func NewReceivablesFirestoreWithClient(fun connection.FirestoreFromContextFun) *ReceivablesFirestore {
+	return &ReceivablesFirestore{
+		firestoreClientFun: fun,
+	}
+}
+
+// ListInvoices returns the invoices issued on or after from and before to
+func (d *ReceivablesFirestore) ListInvoices(ctx context.Context, from, to time.Time) ([]*domain.Invoice, error) {
+	iter := d.firestoreClientFun(ctx).Collection(invoicesCollection).
+		Where("IVDATE", ">=", from).
+		Where("IVDATE", "<", to).
+		Select("entity", "TOTPRICE", "USDEXCH", "CANCELED").
+		Documents(ctx)
+	defer iter.Stop()
+
+	invoices := make([]*domain.Invoice, 0)
+
+	for {
+		docSnap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var invoice domain.Invoice
+		if err := docSnap.DataTo(&invoice); err != nil {
+			return nil, err
+		}
+
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, nil
+}
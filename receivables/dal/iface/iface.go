@@ -0,0 +1,23 @@
+package iface
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/civil"
+
+	"github.com/doitintl/hello/scheduled-tasks/receivables/domain"
+)
+
+type BigQuery interface {
+	InsertAging(ctx context.Context, rows []*domain.AgingRow) error
+	InsertKPIs(ctx context.Context, rows []*domain.KPIRow) error
+	GetEntitiesReceivables(ctx context.Context, date civil.Date) (map[string]float64, error)
+	GetCollectionDays(ctx context.Context) (map[string]*domain.CollectionDays, error)
+	GetAging(ctx context.Context, date civil.Date, groupBy domain.AgingGroupBy, customerID string) ([]*domain.AgingSummary, error)
+	GetKPITrend(ctx context.Context, from, to civil.Date, entityID, customerID string) ([]*domain.KPITrendPoint, error)
+}
+
+type ReceivablesFirestore interface {
+	ListInvoices(ctx context.Context, from, to time.Time) ([]*domain.Invoice, error)
+}
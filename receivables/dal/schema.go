@@ -0,0 +1,35 @@
+package dal
+
+import "cloud.google.com/go/bigquery"
+
+// AgingTableSchema : BigQuery schema for the receivables aging table
+var AgingTableSchema = bigquery.Schema{
+	{Name: "snapshot_date", Type: bigquery.DateFieldType, Required: true},
+	{Name: "entity_id", Type: bigquery.StringFieldType, Required: true},
+	{Name: "customer_id", Type: bigquery.StringFieldType},
+	{Name: "priority_company", Type: bigquery.StringFieldType},
+	{Name: "priority_id", Type: bigquery.StringFieldType},
+	{Name: "currency", Type: bigquery.StringFieldType},
+	{Name: "bucket", Type: bigquery.StringFieldType},
+	{Name: "invoices_count", Type: bigquery.IntegerFieldType},
+	{Name: "debit", Type: bigquery.FloatFieldType},
+	{Name: "debit_usd", Type: bigquery.FloatFieldType},
+	{Name: "insert_timestamp", Type: bigquery.TimestampFieldType},
+}
+
+// KPIsTableSchema : BigQuery schema for the receivables KPIs table
+var KPIsTableSchema = bigquery.Schema{
+	{Name: "snapshot_date", Type: bigquery.DateFieldType, Required: true},
+	{Name: "entity_id", Type: bigquery.StringFieldType, Required: true},
+	{Name: "customer_id", Type: bigquery.StringFieldType},
+	{Name: "period_days", Type: bigquery.IntegerFieldType},
+	{Name: "receivables_usd", Type: bigquery.FloatFieldType},
+	{Name: "current_receivables_usd", Type: bigquery.FloatFieldType},
+	{Name: "beginning_receivables_usd", Type: bigquery.FloatFieldType},
+	{Name: "sales_usd", Type: bigquery.FloatFieldType},
+	{Name: "dso", Type: bigquery.FloatFieldType},
+	{Name: "cei", Type: bigquery.FloatFieldType},
+	{Name: "avg_ar_days", Type: bigquery.IntegerFieldType},
+	{Name: "avg_late_ar_days", Type: bigquery.IntegerFieldType},
+	{Name: "insert_timestamp", Type: bigquery.TimestampFieldType},
+}
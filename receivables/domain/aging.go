@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"cloud.google.com/go/civil"
+)
+
+type AgingBucket string
+
+const (
+	AgingBucketCurrent AgingBucket = "current"
+	AgingBucket1To30   AgingBucket = "1-30"
+	AgingBucket31To60  AgingBucket = "31-60"
+	AgingBucket61To90  AgingBucket = "61-90"
+	AgingBucketOver90  AgingBucket = "90+"
+)
+
+const hoursPerDay = 24
+
+// AgingBuckets are the aging buckets ordered from the most recent to the most overdue
+var AgingBuckets = []AgingBucket{
+	AgingBucketCurrent,
+	AgingBucket1To30,
+	AgingBucket31To60,
+	AgingBucket61To90,
+	AgingBucketOver90,
+}
+
+// BucketOf returns the aging bucket of a receivable that is the given number of days past its pay date
+func BucketOf(daysPastDue int) AgingBucket {
+	switch {
+	case daysPastDue <= 0:
+		return AgingBucketCurrent
+	case daysPastDue <= 30:
+		return AgingBucket1To30
+	case daysPastDue <= 60:
+		return AgingBucket31To60
+	case daysPastDue <= 90:
+		return AgingBucket61To90
+	default:
+		return AgingBucketOver90
+	}
+}
+
+// DaysPastDue returns the number of full days between the pay date and the snapshot date
+func DaysPastDue(payDate, snapshotDate time.Time) int {
+	return int(snapshotDate.Sub(payDate).Hours() / hoursPerDay)
+}
+
+// Receivable is the open (unpaid) balance of a single invoice
+type Receivable struct {
+	EntityID        string
+	CustomerID      string
+	PriorityCompany string
+	PriorityID      string
+	InvoiceID       string
+	Currency        string
+	PayDate         time.Time
+	Debit           float64
+	DebitUSD        float64
+}
+
+// AgingRow is a row of the receivables aging table: the open balance of an entity in
+// one currency and one aging bucket on the snapshot date
+type AgingRow struct {
+	SnapshotDate    civil.Date  `bigquery:"snapshot_date"`
+	EntityID        string      `bigquery:"entity_id"`
+	CustomerID      string      `bigquery:"customer_id"`
+	PriorityCompany string      `bigquery:"priority_company"`
+	PriorityID      string      `bigquery:"priority_id"`
+	Currency        string      `bigquery:"currency"`
+	Bucket          AgingBucket `bigquery:"bucket"`
+	InvoicesCount   int64       `bigquery:"invoices_count"`
+	Debit           float64     `bigquery:"debit"`
+	DebitUSD        float64     `bigquery:"debit_usd"`
+	InsertTimestamp time.Time   `bigquery:"insert_timestamp"`
+}
+
+type agingKey struct {
+	entityID string
+	currency string
+	bucket   AgingBucket
+}
+
+// Aging accumulates open receivables into aging buckets per entity and currency
+type Aging struct {
+	snapshotDate time.Time
+	rows         map[agingKey]*AgingRow
+}
+
+func NewAging(snapshotDate time.Time) *Aging {
+	return &Aging{
+		snapshotDate: snapshotDate,
+		rows:         make(map[agingKey]*AgingRow),
+	}
+}
+
+// Add adds an open receivable to its aging bucket
+func (a *Aging) Add(r *Receivable) {
+	key := agingKey{
+		entityID: r.EntityID,
+		currency: r.Currency,
+		bucket:   BucketOf(DaysPastDue(r.PayDate, a.snapshotDate)),
+	}
+
+	row, ok := a.rows[key]
+	if !ok {
+		row = &AgingRow{
+			SnapshotDate:    civil.DateOf(a.snapshotDate),
+			EntityID:        r.EntityID,
+			CustomerID:      r.CustomerID,
+			PriorityCompany: r.PriorityCompany,
+			PriorityID:      r.PriorityID,
+			Currency:        r.Currency,
+			Bucket:          key.bucket,
+		}
+		a.rows[key] = row
+	}
+
+	row.InvoicesCount++
+	row.Debit += r.Debit
+	row.DebitUSD += r.DebitUSD
+}
+
+// Rows returns the aging rows ordered by entity, currency and bucket
+func (a *Aging) Rows() []*AgingRow {
+	rows := make([]*AgingRow, 0, len(a.rows))
+	for _, row := range a.rows {
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].EntityID != rows[j].EntityID {
+			return rows[i].EntityID < rows[j].EntityID
+		}
+
+		if rows[i].Currency != rows[j].Currency {
+			return rows[i].Currency < rows[j].Currency
+		}
+
+		return bucketIndex(rows[i].Bucket) < bucketIndex(rows[j].Bucket)
+	})
+
+	return rows
+}
+
+// Receivables returns the total and current (not yet due) open balance in USD of each entity
+func (a *Aging) Receivables() map[string]*EntityReceivables {
+	receivables := make(map[string]*EntityReceivables)
+
+	for _, row := range a.rows {
+		r, ok := receivables[row.EntityID]
+		if !ok {
+			r = &EntityReceivables{CustomerID: row.CustomerID}
+			receivables[row.EntityID] = r
+		}
+
+		r.Total += row.DebitUSD
+		if row.Bucket == AgingBucketCurrent {
+			r.Current += row.DebitUSD
+		}
+	}
+
+	return receivables
+}
+
+// EntityReceivables is the open balance in USD of an entity
+type EntityReceivables struct {
+	CustomerID string
+	Total      float64
+	Current    float64
+}
+
+func bucketIndex(bucket AgingBucket) int {
+	for i, b := range AgingBuckets {
+		if b == bucket {
+			return i
+		}
+	}
+
+	return len(AgingBuckets)
+}
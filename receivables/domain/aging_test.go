@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketOf(t *testing.T) {
+	tests := []struct {
+		daysPastDue int
+		want        AgingBucket
+	}{
+		{daysPastDue: -10, want: AgingBucketCurrent},
+		{daysPastDue: 0, want: AgingBucketCurrent},
+		{daysPastDue: 1, want: AgingBucket1To30},
+		{daysPastDue: 30, want: AgingBucket1To30},
+		{daysPastDue: 31, want: AgingBucket31To60},
+		{daysPastDue: 60, want: AgingBucket31To60},
+		{daysPastDue: 61, want: AgingBucket61To90},
+		{daysPastDue: 90, want: AgingBucket61To90},
+		{daysPastDue: 91, want: AgingBucketOver90},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, BucketOf(tt.daysPastDue), "days past due %d", tt.daysPastDue)
+	}
+}
+
+func TestAging(t *testing.T) {
+	snapshotDate := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return snapshotDate.AddDate(0, 0, -days)
+	}
+
+	aging := NewAging(snapshotDate)
+
+	for _, r := range []*Receivable{
+		{EntityID: "b", CustomerID: "c1", Currency: "USD", PayDate: daysAgo(-5), Debit: 100, DebitUSD: 100},
+		{EntityID: "b", CustomerID: "c1", Currency: "USD", PayDate: daysAgo(95), Debit: 50, DebitUSD: 50},
+		{EntityID: "b", CustomerID: "c1", Currency: "USD", PayDate: daysAgo(0), Debit: 20, DebitUSD: 20},
+		{EntityID: "a", CustomerID: "c2", Currency: "EUR", PayDate: daysAgo(45), Debit: 10, DebitUSD: 11},
+		{EntityID: "a", CustomerID: "c2", Currency: "EUR", PayDate: daysAgo(15), Debit: 10, DebitUSD: 11},
+	} {
+		aging.Add(r)
+	}
+
+	date := civil.DateOf(snapshotDate)
+
+	assert.Equal(t, []*AgingRow{
+		{SnapshotDate: date, EntityID: "a", CustomerID: "c2", Currency: "EUR", Bucket: AgingBucket1To30, InvoicesCount: 1, Debit: 10, DebitUSD: 11},
+		{SnapshotDate: date, EntityID: "a", CustomerID: "c2", Currency: "EUR", Bucket: AgingBucket31To60, InvoicesCount: 1, Debit: 10, DebitUSD: 11},
+		{SnapshotDate: date, EntityID: "b", CustomerID: "c1", Currency: "USD", Bucket: AgingBucketCurrent, InvoicesCount: 2, Debit: 120, DebitUSD: 120},
+		{SnapshotDate: date, EntityID: "b", CustomerID: "c1", Currency: "USD", Bucket: AgingBucketOver90, InvoicesCount: 1, Debit: 50, DebitUSD: 50},
+	}, aging.Rows())
+
+	assert.Equal(t, map[string]*EntityReceivables{
+		"a": {CustomerID: "c2", Total: 22},
+		"b": {CustomerID: "c1", Total: 170, Current: 120},
+	}, aging.Receivables())
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+type AgingGroupBy string
+
+const (
+	AgingGroupByEntity   AgingGroupBy = "entity"
+	AgingGroupByCustomer AgingGroupBy = "customer"
+	AgingGroupByCurrency AgingGroupBy = "currency"
+)
+
+func (g AgingGroupBy) Valid() bool {
+	switch g {
+	case AgingGroupByEntity, AgingGroupByCustomer, AgingGroupByCurrency:
+		return true
+	default:
+		return false
+	}
+}
+
+// AgingSummary is the open balance in USD of an entity, customer or currency per aging bucket.
+// When grouped by currency the balance in the currency itself is also returned.
+type AgingSummary struct {
+	Key           string               `bigquery:"key" json:"key"`
+	Current       float64              `bigquery:"current" json:"current"`
+	Days1To30     float64              `bigquery:"days_1_30" json:"days1To30"`
+	Days31To60    float64              `bigquery:"days_31_60" json:"days31To60"`
+	Days61To90    float64              `bigquery:"days_61_90" json:"days61To90"`
+	Over90        float64              `bigquery:"over_90" json:"over90"`
+	Total         float64              `bigquery:"total" json:"total"`
+	TotalCurrency bigquery.NullFloat64 `bigquery:"total_currency" json:"totalCurrency"`
+	InvoicesCount int64                `bigquery:"invoices_count" json:"invoicesCount"`
+}
+
+// KPITrendPoint is the days sales outstanding and collection effectiveness index on a snapshot date
+type KPITrendPoint struct {
+	SnapshotDate       civil.Date           `bigquery:"snapshot_date" json:"date"`
+	Receivables        float64              `bigquery:"receivables_usd" json:"receivables"`
+	CurrentReceivables float64              `bigquery:"current_receivables_usd" json:"currentReceivables"`
+	Sales              float64              `bigquery:"sales_usd" json:"sales"`
+	DSO                bigquery.NullFloat64 `bigquery:"dso" json:"dso"`
+	CEI                bigquery.NullFloat64 `bigquery:"cei" json:"cei"`
+	AvgARDays          bigquery.NullFloat64 `bigquery:"avg_ar_days" json:"avgArDays"`
+	AvgLateARDays      bigquery.NullFloat64 `bigquery:"avg_late_ar_days" json:"avgLateArDays"`
+}
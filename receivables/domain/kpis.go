@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+const percentageMultiplier = 100
+
+// KPIRow is a row of the receivables KPIs table: the receivables, sales, days sales outstanding and
+// collection effectiveness index of an entity over the period that ends on the snapshot date.
+// The components are stored with the ratios so that they can be recomputed at any aggregation level.
+type KPIRow struct {
+	SnapshotDate         civil.Date           `bigquery:"snapshot_date"`
+	EntityID             string               `bigquery:"entity_id"`
+	CustomerID           string               `bigquery:"customer_id"`
+	PeriodDays           int64                `bigquery:"period_days"`
+	Receivables          float64              `bigquery:"receivables_usd"`
+	CurrentReceivables   float64              `bigquery:"current_receivables_usd"`
+	BeginningReceivables bigquery.NullFloat64 `bigquery:"beginning_receivables_usd"`
+	Sales                float64              `bigquery:"sales_usd"`
+	DSO                  bigquery.NullFloat64 `bigquery:"dso"`
+	CEI                  bigquery.NullFloat64 `bigquery:"cei"`
+	AvgARDays            bigquery.NullInt64   `bigquery:"avg_ar_days"`
+	AvgLateARDays        bigquery.NullInt64   `bigquery:"avg_late_ar_days"`
+	InsertTimestamp      time.Time            `bigquery:"insert_timestamp"`
+}
+
+// KPIInput are the balances used to compute the receivables KPIs of each entity on the snapshot date
+type KPIInput struct {
+	SnapshotDate time.Time
+	PeriodDays   int
+	// Customers maps entity IDs to their customer ID
+	Customers   map[string]string
+	Receivables map[string]*EntityReceivables
+	// Sales are the invoiced amounts in USD of each entity during the period
+	Sales map[string]float64
+	// BeginningReceivables are the open balances in USD of each entity at the beginning of the period,
+	// nil when there is no snapshot of the beginning of the period
+	BeginningReceivables map[string]float64
+	// CollectionDays are the historical average collection days of each entity
+	CollectionDays map[string]*CollectionDays
+}
+
+// CollectionDays are the average number of days an entity takes to pay its invoices after they are
+// issued, and after they are due
+type CollectionDays struct {
+	EntityID        string `bigquery:"ENTITY_ID"`
+	AverageDays     int64  `bigquery:"AVG_AR_DAYS"`
+	AverageLateDays int64  `bigquery:"AVG_LATE_AR_DAYS"`
+}
+
+// DSO returns the days sales outstanding: the average number of days it takes to collect the
+// receivables, based on the sales of the given period
+func DSO(receivables, sales float64, periodDays int) (float64, bool) {
+	if sales <= 0 || periodDays <= 0 {
+		return 0, false
+	}
+
+	return receivables / sales * float64(periodDays), true
+}
+
+// CEI returns the collection effectiveness index: the percentage of the collectible receivables
+// (beginning receivables plus sales of the period, less the receivables that are not yet due)
+// that were collected during the period
+func CEI(beginningReceivables, sales, endingReceivables, endingCurrentReceivables float64) (float64, bool) {
+	collectible := beginningReceivables + sales - endingCurrentReceivables
+	if collectible <= 0 {
+		return 0, false
+	}
+
+	return (beginningReceivables + sales - endingReceivables) / collectible * percentageMultiplier, true
+}
+
+// BuildKPIRows returns the KPI rows of every entity that has receivables, sales or beginning receivables
+func BuildKPIRows(input *KPIInput) []*KPIRow {
+	entityIDs := make(map[string]bool)
+
+	for entityID := range input.Receivables {
+		entityIDs[entityID] = true
+	}
+
+	for entityID := range input.Sales {
+		entityIDs[entityID] = true
+	}
+
+	for entityID := range input.BeginningReceivables {
+		entityIDs[entityID] = true
+	}
+
+	rows := make([]*KPIRow, 0, len(entityIDs))
+
+	for entityID := range entityIDs {
+		row := &KPIRow{
+			SnapshotDate: civil.DateOf(input.SnapshotDate),
+			EntityID:     entityID,
+			CustomerID:   input.Customers[entityID],
+			PeriodDays:   int64(input.PeriodDays),
+			Sales:        input.Sales[entityID],
+		}
+
+		if r, ok := input.Receivables[entityID]; ok {
+			row.Receivables = r.Total
+			row.CurrentReceivables = r.Current
+
+			if row.CustomerID == "" {
+				row.CustomerID = r.CustomerID
+			}
+		}
+
+		if dso, ok := DSO(row.Receivables, row.Sales, input.PeriodDays); ok {
+			row.DSO = bigquery.NullFloat64{Float64: dso, Valid: true}
+		}
+
+		if input.BeginningReceivables != nil {
+			beginning := input.BeginningReceivables[entityID]
+			row.BeginningReceivables = bigquery.NullFloat64{Float64: beginning, Valid: true}
+
+			if cei, ok := CEI(beginning, row.Sales, row.Receivables, row.CurrentReceivables); ok {
+				row.CEI = bigquery.NullFloat64{Float64: cei, Valid: true}
+			}
+		}
+
+		if days, ok := input.CollectionDays[entityID]; ok {
+			row.AvgARDays = bigquery.NullInt64{Int64: days.AverageDays, Valid: true}
+			row.AvgLateARDays = bigquery.NullInt64{Int64: days.AverageLateDays, Valid: true}
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].EntityID < rows[j].EntityID
+	})
+
+	return rows
+}
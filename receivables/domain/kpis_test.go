@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDSO(t *testing.T) {
+	dso, ok := DSO(50, 100, 30)
+	assert.True(t, ok)
+	assert.InDelta(t, 15, dso, 1e-9)
+
+	_, ok = DSO(50, 0, 30)
+	assert.False(t, ok)
+}
+
+func TestCEI(t *testing.T) {
+	tests := []struct {
+		name                                    string
+		beginning, sales, ending, endingCurrent float64
+		want                                    float64
+		wantOK                                  bool
+	}{
+		{
+			name:          "everything due was collected",
+			beginning:     100,
+			sales:         200,
+			ending:        150,
+			endingCurrent: 150,
+			want:          100,
+			wantOK:        true,
+		},
+		{
+			name:          "half of the collectible receivables were collected",
+			beginning:     100,
+			sales:         200,
+			ending:        200,
+			endingCurrent: 100,
+			want:          50,
+			wantOK:        true,
+		},
+		{
+			name:          "nothing collectible",
+			beginning:     0,
+			sales:         100,
+			ending:        100,
+			endingCurrent: 100,
+			wantOK:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CEI(tt.beginning, tt.sales, tt.ending, tt.endingCurrent)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestSales(t *testing.T) {
+	entity := func(id string) *firestore.DocumentRef {
+		return &firestore.DocumentRef{ID: id}
+	}
+
+	sales := Sales([]*Invoice{
+		{Entity: entity("a"), TotalTax: 110, USDExchangeRate: 1.1},
+		{Entity: entity("a"), TotalTax: 50, USDExchangeRate: 1},
+		{Entity: entity("a"), TotalTax: 500, USDExchangeRate: 1, Canceled: true},
+		{Entity: entity("b"), TotalTax: 500, USDExchangeRate: 0},
+		{TotalTax: 500, USDExchangeRate: 1},
+	})
+
+	assert.Len(t, sales, 1)
+	assert.InDelta(t, 150, sales["a"], 1e-9)
+}
+
+func TestBuildKPIRows(t *testing.T) {
+	snapshotDate := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	date := civil.DateOf(snapshotDate)
+
+	input := &KPIInput{
+		SnapshotDate: snapshotDate,
+		PeriodDays:   30,
+		Customers:    map[string]string{"a": "c1", "b": "c1"},
+		Receivables: map[string]*EntityReceivables{
+			"a": {CustomerID: "c1", Total: 200, Current: 100},
+		},
+		Sales: map[string]float64{"a": 200, "b": 50},
+		CollectionDays: map[string]*CollectionDays{
+			"a": {EntityID: "a", AverageDays: 40, AverageLateDays: 5},
+		},
+	}
+
+	assert.Equal(t, []*KPIRow{
+		{
+			SnapshotDate:       date,
+			EntityID:           "a",
+			CustomerID:         "c1",
+			PeriodDays:         30,
+			Receivables:        200,
+			CurrentReceivables: 100,
+			Sales:              200,
+			DSO:                bigquery.NullFloat64{Float64: 30, Valid: true},
+			AvgARDays:          bigquery.NullInt64{Int64: 40, Valid: true},
+			AvgLateARDays:      bigquery.NullInt64{Int64: 5, Valid: true},
+		},
+		{
+			SnapshotDate: date,
+			EntityID:     "b",
+			CustomerID:   "c1",
+			PeriodDays:   30,
+			Sales:        50,
+			DSO:          bigquery.NullFloat64{Float64: 0, Valid: true},
+		},
+	}, BuildKPIRows(input))
+
+	input.BeginningReceivables = map[string]float64{"a": 100, "c": 10}
+
+	rows := BuildKPIRows(input)
+	assert.Len(t, rows, 3)
+
+	// a: (100 + 200 - 200) / (100 + 200 - 100)
+	assert.Equal(t, bigquery.NullFloat64{Float64: 100, Valid: true}, rows[0].BeginningReceivables)
+	assert.Equal(t, bigquery.NullFloat64{Float64: 50, Valid: true}, rows[0].CEI)
+
+	// b: everything invoiced was collected
+	assert.Equal(t, bigquery.NullFloat64{Float64: 0, Valid: true}, rows[1].BeginningReceivables)
+	assert.Equal(t, bigquery.NullFloat64{Float64: 100, Valid: true}, rows[1].CEI)
+
+	// c: no sales and no receivables left
+	assert.Equal(t, "c", rows[2].EntityID)
+	assert.False(t, rows[2].DSO.Valid)
+	assert.Equal(t, bigquery.NullFloat64{Float64: 100, Valid: true}, rows[2].CEI)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"cloud.google.com/go/firestore"
+)
+
+// Invoice is an issued invoice, only with the fields needed to compute the sales of its entity
+type Invoice struct {
+	Entity          *firestore.DocumentRef `firestore:"entity"`
+	TotalTax        float64                `firestore:"TOTPRICE"`
+	USDExchangeRate float64                `firestore:"USDEXCH"`
+	Canceled        bool                   `firestore:"CANCELED"`
+}
+
+// Sales returns the invoiced amount in USD, including taxes, of each entity. Canceled invoices and
+// invoices without an entity or an exchange rate are skipped.
+func Sales(invoices []*Invoice) map[string]float64 {
+	sales := make(map[string]float64)
+
+	for _, invoice := range invoices {
+		if invoice.Canceled || invoice.Entity == nil || invoice.USDExchangeRate <= 0 {
+			continue
+		}
+
+		sales[invoice.Entity.ID] += invoice.TotalTax / invoice.USDExchangeRate
+	}
+
+	return sales
+}
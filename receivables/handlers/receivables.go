@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/gin-gonic/gin"
+
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/domain"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/service"
+)
+
+const defaultTrendDays = 90
+
+type Receivables struct {
+	loggerProvider logger.Provider
+	service        *service.ReceivablesService
+}
+
+func NewReceivables(log logger.Provider, conn *connection.Connection) *Receivables {
+	return &Receivables{
+		log,
+		service.NewReceivablesService(log, conn),
+	}
+}
+
+// SnapshotHandler stores the receivables aging and KPIs of today. The snapshot is computed from the
+// current open invoices, so a date other than today is rejected rather than stored with today's data.
+func (h *Receivables) SnapshotHandler(ctx *gin.Context) error {
+	day := time.Now().UTC()
+
+	if date := ctx.Query("date"); date != "" {
+		d, err := civil.ParseDate(date)
+		if err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		if d != civil.DateOf(day) {
+			return web.NewRequestError(service.ErrSnapshotDateNotToday, http.StatusBadRequest)
+		}
+	}
+
+	if err := h.service.Snapshot(ctx, day); err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+// GetAgingHandler returns the receivables aging of a date (defaults to today), grouped by entity,
+// customer or currency
+func (h *Receivables) GetAgingHandler(ctx *gin.Context) error {
+	date := civil.DateOf(time.Now().UTC())
+
+	if value := ctx.Query("date"); value != "" {
+		d, err := civil.ParseDate(value)
+		if err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		date = d
+	}
+
+	groupBy := domain.AgingGroupBy(ctx.DefaultQuery("groupBy", string(domain.AgingGroupByEntity)))
+
+	result, err := h.service.GetAging(ctx, date, groupBy, ctx.Query("customerId"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidGroupBy) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, result, http.StatusOK)
+}
+
+// GetKPITrendHandler returns the daily DSO and CEI between two dates (defaults to the last 90 days),
+// optionally of a single entity or customer
+func (h *Receivables) GetKPITrendHandler(ctx *gin.Context) error {
+	to := civil.DateOf(time.Now().UTC())
+
+	if value := ctx.Query("to"); value != "" {
+		d, err := civil.ParseDate(value)
+		if err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		to = d
+	}
+
+	from := to.AddDays(-defaultTrendDays)
+
+	if value := ctx.Query("from"); value != "" {
+		d, err := civil.ParseDate(value)
+		if err != nil {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		from = d
+	}
+
+	result, err := h.service.GetKPITrend(ctx, from, to, ctx.Query("entityId"), ctx.Query("customerId"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, result, http.StatusOK)
+}
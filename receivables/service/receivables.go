@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
+	entityDal "github.com/doitintl/hello/scheduled-tasks/entity/dal"
+	"github.com/doitintl/hello/scheduled-tasks/fixer"
+	"github.com/doitintl/hello/scheduled-tasks/fixer/converter"
+	converterIface "github.com/doitintl/hello/scheduled-tasks/fixer/converter/iface"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/priority"
+	priorityDomain "github.com/doitintl/hello/scheduled-tasks/priority/domain"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/dal"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/dal/iface"
+	"github.com/doitintl/hello/scheduled-tasks/receivables/domain"
+)
+
+const (
+	// kpiPeriodDays is the period over which the sales of the DSO and CEI are summed
+	kpiPeriodDays = 30
+	// maxTrendDays limits the date range of the KPI trend
+	maxTrendDays = 731
+	usdCurrency  = "USD"
+)
+
+var (
+	ErrCurrencyRatesUnavailable = errors.New("currency historical timeseries is not available")
+	ErrInvalidGroupBy           = errors.New("invalid aging group by")
+	ErrInvalidDateRange         = errors.New("invalid date range")
+	ErrSnapshotDateNotToday     = errors.New("receivables snapshots can only be taken of today")
+)
+
+type openInvoicesFun func(ctx context.Context, company, priorityID string) ([]*priorityDomain.OpenInvoice, error)
+
+type ReceivablesService struct {
+	loggerProvider    logger.Provider
+	bigQueryDAL       iface.BigQuery
+	firestoreDAL      iface.ReceivablesFirestore
+	entitiesDAL       entityDal.Entites
+	currencyConverter converterIface.Converter
+	getOpenInvoices   openInvoicesFun
+}
+
+func NewReceivablesService(loggerProvider logger.Provider, conn *connection.Connection) *ReceivablesService {
+	return &ReceivablesService{
+		loggerProvider,
+		dal.NewReceivablesBigQueryWithClient(conn.Bigquery),
+		dal.NewReceivablesFirestoreWithClient(conn.Firestore),
+		entityDal.NewEntitiesFirestoreWithClient(conn.Firestore),
+		converter.NewCurrencyConverterService(),
+		invoices.GetOpenInvoices,
+	}
+}
+
+// Snapshot computes the receivables aging and KPIs of all the entities on the given day from the
+// Priority open invoices, and appends them to the receivables BigQuery tables
+func (s *ReceivablesService) Snapshot(ctx context.Context, day time.Time) error {
+	l := s.loggerProvider(ctx)
+
+	if !fixer.CurrencyHistoricalTimeseriesInitialized {
+		return ErrCurrencyRatesUnavailable
+	}
+
+	snapshotDate := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	entities, err := s.entitiesDAL.GetEntities(ctx)
+	if err != nil {
+		return err
+	}
+
+	priorityEntities := make(map[string]*common.Entity, len(entities))
+	customers := make(map[string]string, len(entities))
+
+	for _, entity := range entities {
+		priorityEntities[priorityKey(entity.PriorityCompany, entity.PriorityID)] = entity
+
+		if entity.Customer != nil {
+			customers[entity.Snapshot.Ref.ID] = entity.Customer.ID
+		}
+	}
+
+	aging := domain.NewAging(snapshotDate)
+
+	for _, company := range priority.Companies {
+		openInvoices, err := s.getOpenInvoices(ctx, string(company), "")
+		if err != nil {
+			return err
+		}
+
+		for _, openInvoice := range openInvoices {
+			// Filter out receipts from the results
+			if openInvoice.ID == "" || strings.HasPrefix(openInvoice.ID, "RC") {
+				continue
+			}
+
+			entity, ok := priorityEntities[priorityKey(string(company), openInvoice.PriorityID)]
+			if !ok {
+				l.Warningf("no entity for open invoice %s of %s-%s", openInvoice.ID, company, openInvoice.PriorityID)
+				continue
+			}
+
+			receivable, err := s.toReceivable(string(company), openInvoice, entity, snapshotDate)
+			if err != nil {
+				l.Warningf("skipping open invoice %s of %s-%s: %s", openInvoice.ID, company, openInvoice.PriorityID, err)
+				continue
+			}
+
+			aging.Add(receivable)
+		}
+	}
+
+	issuedInvoices, err := s.firestoreDAL.ListInvoices(ctx, snapshotDate.AddDate(0, 0, 1-kpiPeriodDays), snapshotDate.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	beginningReceivables, err := s.bigQueryDAL.GetEntitiesReceivables(ctx, civil.DateOf(snapshotDate.AddDate(0, 0, -kpiPeriodDays)))
+	if err != nil {
+		return err
+	}
+
+	collectionDays, err := s.bigQueryDAL.GetCollectionDays(ctx)
+	if err != nil {
+		l.Warningf("failed to get average collection days: %s", err)
+	}
+
+	agingRows := aging.Rows()
+	kpiRows := domain.BuildKPIRows(&domain.KPIInput{
+		SnapshotDate:         snapshotDate,
+		PeriodDays:           kpiPeriodDays,
+		Customers:            customers,
+		Receivables:          aging.Receivables(),
+		Sales:                domain.Sales(issuedInvoices),
+		BeginningReceivables: beginningReceivables,
+		CollectionDays:       collectionDays,
+	})
+
+	insertTimestamp := time.Now().UTC()
+
+	for _, row := range agingRows {
+		row.InsertTimestamp = insertTimestamp
+	}
+
+	for _, row := range kpiRows {
+		row.InsertTimestamp = insertTimestamp
+	}
+
+	if err := s.bigQueryDAL.InsertAging(ctx, agingRows); err != nil {
+		return err
+	}
+
+	if err := s.bigQueryDAL.InsertKPIs(ctx, kpiRows); err != nil {
+		return err
+	}
+
+	l.Infof("receivables snapshot %s: %d aging rows, %d kpi rows", civil.DateOf(snapshotDate), len(agingRows), len(kpiRows))
+
+	return nil
+}
+
+// GetAging returns the receivables aging on the given date, grouped by entity, customer or currency
+func (s *ReceivablesService) GetAging(ctx context.Context, date civil.Date, groupBy domain.AgingGroupBy, customerID string) ([]*domain.AgingSummary, error) {
+	if !groupBy.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGroupBy, groupBy)
+	}
+
+	return s.bigQueryDAL.GetAging(ctx, date, groupBy, customerID)
+}
+
+// GetKPITrend returns the daily DSO and CEI between the given dates, of all the entities or of
+// a single entity or customer
+func (s *ReceivablesService) GetKPITrend(ctx context.Context, from, to civil.Date, entityID, customerID string) ([]*domain.KPITrendPoint, error) {
+	if to.Before(from) || to.DaysSince(from) > maxTrendDays {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidDateRange, from, to)
+	}
+
+	return s.bigQueryDAL.GetKPITrend(ctx, from, to, entityID, customerID)
+}
+
+// toReceivable converts the open balance of a Priority invoice to USD at the rate of the snapshot date,
+// or of the latest date with known rates
+func (s *ReceivablesService) toReceivable(
+	company string,
+	openInvoice *priorityDomain.OpenInvoice,
+	entity *common.Entity,
+	snapshotDate time.Time,
+) (*domain.Receivable, error) {
+	t, err := time.Parse(time.RFC3339, openInvoice.PayDate)
+	if err != nil {
+		return nil, err
+	}
+
+	rateDate := snapshotDate
+	if !fixer.TimeseriesEndDate.IsZero() && rateDate.After(fixer.TimeseriesEndDate) {
+		rateDate = fixer.TimeseriesEndDate
+	}
+
+	debitUSD, err := s.currencyConverter.Convert(openInvoice.Currency, usdCurrency, openInvoice.Debit, rateDate)
+	if err != nil {
+		return nil, err
+	}
+
+	receivable := &domain.Receivable{
+		EntityID:        entity.Snapshot.Ref.ID,
+		PriorityCompany: company,
+		PriorityID:      openInvoice.PriorityID,
+		InvoiceID:       openInvoice.ID,
+		Currency:        openInvoice.Currency,
+		PayDate:         time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC),
+		Debit:           openInvoice.Debit,
+		DebitUSD:        debitUSD,
+	}
+
+	if entity.Customer != nil {
+		receivable.CustomerID = entity.Customer.ID
+	}
+
+	return receivable, nil
+}
+
+func priorityKey(company, priorityID string) string {
+	return fmt.Sprintf("%s-%s", company, priorityID)
+}
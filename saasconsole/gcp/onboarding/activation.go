@@ -0,0 +1,185 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doitintl/firestore/pkg"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared/domain"
+)
+
+type activationStep string
+
+const (
+	activationStepUpdateOnboarding   activationStep = "updateOnboarding"
+	activationStepRunAllTests        activationStep = "runAllTests"
+	activationStepBillingImport      activationStep = "billingImport"
+	activationStepCreateAsset        activationStep = "createAsset"
+	activationStepCreateCloudConnect activationStep = "createCloudConnect"
+	activationStepEnablement         activationStep = "enablement"
+)
+
+// activationSteps are the steps of the activation in the order they run. Every step can run again
+// after it failed or after the steps that follow it failed.
+var activationSteps = []activationStep{
+	activationStepUpdateOnboarding,
+	activationStepRunAllTests,
+	activationStepBillingImport,
+	activationStepCreateAsset,
+	activationStepCreateCloudConnect,
+	activationStepEnablement,
+}
+
+type activationStepFunc func(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, retry bool) error
+
+func activationStepNames() []string {
+	names := make([]string, len(activationSteps))
+
+	for i, step := range activationSteps {
+		names[i] = string(step)
+	}
+
+	return names
+}
+
+func isSameActivation(checkpoint *domain.ActivationCheckpoint, req *GCPStandaloneRequest) bool {
+	return checkpoint.ProjectID == req.ProjectID &&
+		checkpoint.DatasetID == req.DatasetID &&
+		checkpoint.DryRun == req.DryRun
+}
+
+// ResumeActivation runs the activation of the billing account again from the step it failed at
+func (s *GCPSaaSConsoleOnboardService) ResumeActivation(ctx context.Context, customerID, billingAccountID string) (*saasconsole.OnboardingResponse, error) {
+	ctx = context.WithValue(ctx, saasconsole.CustomerIDKey, customerID)
+
+	checkpoint, err := s.activationCheckpoints.GetActivationCheckpoint(ctx, customerID, billingAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint.Status == domain.ActivationStatusCompleted {
+		return nil, ErrActivationCompleted
+	}
+
+	s.getLogger(ctx).Infof("resuming activation of BA %s from step %q", billingAccountID, checkpoint.FailedStep())
+
+	return s.activate(ctx, checkpoint), nil
+}
+
+// GetActivationStatus returns the progress of the activation of the billing account
+func (s *GCPSaaSConsoleOnboardService) GetActivationStatus(ctx context.Context, customerID, billingAccountID string) (*domain.ActivationCheckpoint, error) {
+	return s.activationCheckpoints.GetActivationCheckpoint(ctx, customerID, billingAccountID)
+}
+
+func (s *GCPSaaSConsoleOnboardService) activate(ctx context.Context, checkpoint *domain.ActivationCheckpoint) *saasconsole.OnboardingResponse {
+	onboardingStep := pkg.OnboardingStepActivation
+	logger := s.getLogger(ctx)
+
+	req := &GCPStandaloneRequest{
+		CustomerID:       checkpoint.CustomerID,
+		BillingAccountID: checkpoint.BillingAccountID,
+		ProjectID:        checkpoint.ProjectID,
+		DatasetID:        checkpoint.DatasetID,
+		DryRun:           checkpoint.DryRun,
+	}
+
+	doc, err := s.saasConsoleDAL.GetGCPAccounts(ctx, s.getDocumentID(req.CustomerID))
+	if err != nil {
+		return s.updateAndSlackError(ctx, onboardingStep, req.BillingAccountID, err)
+	}
+
+	for _, step := range activationSteps {
+		name := string(step)
+
+		if checkpoint.IsStepCompleted(name) {
+			continue
+		}
+
+		checkpoint.StartStep(name)
+		s.setActivationCheckpoint(ctx, checkpoint)
+
+		retry := checkpoint.GetStep(name).Attempts > 1
+
+		if err := s.activationStepFunc(step)(ctx, req, doc, retry); err != nil {
+			checkpoint.FailStep(name, err)
+			s.setActivationCheckpoint(ctx, checkpoint)
+
+			return s.updateAndSlackError(ctx, onboardingStep, req.BillingAccountID, err)
+		}
+
+		checkpoint.CompleteStep(name)
+	}
+
+	checkpoint.Complete()
+	s.setActivationCheckpoint(ctx, checkpoint)
+
+	logger.Info(stepMessageActivateCompleted)
+
+	_ = saasconsole.PublishOnboardSuccessSlackNotification(ctx, pkg.GCP, s.customersDAL, req.CustomerID, req.BillingAccountID)
+
+	return saasconsole.Success
+}
+
+func (s *GCPSaaSConsoleOnboardService) activationStepFunc(step activationStep) activationStepFunc {
+	switch step {
+	case activationStepUpdateOnboarding:
+		return s.updateOnboarding
+	case activationStepRunAllTests:
+		return func(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, _ bool) error {
+			return s.runAllTests(ctx, req, doc)
+		}
+	case activationStepBillingImport:
+		return s.runBillingImportOnce
+	case activationStepCreateAsset:
+		return func(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, _ bool) error {
+			return s.CreateAsset(ctx, req.CustomerID, doc.ServiceAccountEmail, req.BillingAccountID)
+		}
+	case activationStepCreateCloudConnect:
+		return func(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, _ bool) error {
+			return s.cloudConnectDAL.CreateGCPCloudConnect(ctx, req.BillingAccountID, doc.ServiceAccountEmail, s.customersDAL.GetRef(ctx, req.CustomerID))
+		}
+	case activationStepEnablement:
+		return func(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, _ bool) error {
+			return s.runEnablement(ctx, req, doc)
+		}
+	default:
+		return func(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, _ bool) error {
+			return fmt.Errorf("unknown activation step %q", step)
+		}
+	}
+}
+
+func (s *GCPSaaSConsoleOnboardService) updateOnboarding(ctx context.Context, req *GCPStandaloneRequest, _ *pkg.GCPSaaSConsoleAccounts, _ bool) error {
+	standaloneID := pkg.ComposeStandaloneID(req.CustomerID, req.BillingAccountID, pkg.GCP)
+
+	return s.saasConsoleDAL.UpdateOnboarding(ctx, standaloneID, &pkg.GCPSaaSConsoleOnboarding{
+		BillingAccountID: req.BillingAccountID,
+		ProjectID:        req.ProjectID,
+		DatasetID:        req.DatasetID,
+	})
+}
+
+// runBillingImportOnce starts the billing import unless this is a dry run, or a previous attempt
+// already got the billing pipeline to start importing the billing data
+func (s *GCPSaaSConsoleOnboardService) runBillingImportOnce(ctx context.Context, req *GCPStandaloneRequest, doc *pkg.GCPSaaSConsoleAccounts, retry bool) error {
+	if req.DryRun {
+		return nil
+	}
+
+	if retry {
+		importStatus, err := s.billingImportStatus.GetBillingImportStatus(ctx, req.CustomerID, req.BillingAccountID)
+		if err == nil && importStatus != nil && importStatus.Status != shared.BillingImportStatusPending && importStatus.Status != shared.BillingImportStatusFailed {
+			return nil
+		}
+	}
+
+	return s.runBillingImport(ctx, req, doc)
+}
+
+func (s *GCPSaaSConsoleOnboardService) setActivationCheckpoint(ctx context.Context, checkpoint *domain.ActivationCheckpoint) {
+	if err := s.activationCheckpoints.SetActivationCheckpoint(ctx, checkpoint); err != nil {
+		s.getLogger(ctx).Errorf("failed to save activation checkpoint of BA %s: %s", checkpoint.BillingAccountID, err)
+	}
+}
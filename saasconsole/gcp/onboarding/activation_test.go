@@ -0,0 +1,217 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/firestore/mocks"
+	"github.com/doitintl/firestore/pkg"
+	customerMocks "github.com/doitintl/hello/scheduled-tasks/customer/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole"
+	billingPipelineMocks "github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/billingpipeline/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared"
+	sharedDalMocks "github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared/domain"
+)
+
+type activationFields struct {
+	SaaSConsoleOnboardDAL *mocks.SaaSConsoleOnboard
+	CustomersDAL          *customerMocks.Customers
+	BillingPipeline       *billingPipelineMocks.ServiceInterface
+	BillingImportStatus   *sharedDalMocks.BillingImportStatus
+	ActivationCheckpoints *sharedDalMocks.ActivationCheckpoint
+}
+
+// newFailedActivationCheckpoint returns the checkpoint of an activation that completed every step before failedStep
+func newFailedActivationCheckpoint(failedStep activationStep) *domain.ActivationCheckpoint {
+	checkpoint := domain.NewActivationCheckpoint(standaloneCustomerID, accountID, "project", "dataset", false, activationStepNames())
+
+	for _, step := range activationSteps {
+		checkpoint.StartStep(string(step))
+
+		if step == failedStep {
+			checkpoint.FailStep(string(step), errors.New("transient error"))
+			break
+		}
+
+		checkpoint.CompleteStep(string(step))
+	}
+
+	return checkpoint
+}
+
+func newActivationService(f *activationFields) *GCPSaaSConsoleOnboardService {
+	return &GCPSaaSConsoleOnboardService{
+		loggerProvider:         logger.FromContext,
+		billingPipelineService: f.BillingPipeline,
+		billingImportStatus:    f.BillingImportStatus,
+		saasConsoleDAL:         f.SaaSConsoleOnboardDAL,
+		customersDAL:           f.CustomersDAL,
+		activationCheckpoints:  f.ActivationCheckpoints,
+	}
+}
+
+func Test_ResumeActivation(t *testing.T) {
+	testError := errors.New("test error")
+	doc := &pkg.GCPSaaSConsoleAccounts{ServiceAccountEmail: "sa@doit.com"}
+
+	completed := newFailedActivationCheckpoint(activationStepEnablement)
+	completed.CompleteStep(string(activationStepEnablement))
+	completed.Complete()
+
+	tests := []struct {
+		name       string
+		checkpoint *domain.ActivationCheckpoint
+		getErr     error
+		on         func(*activationFields)
+		wantErr    error
+		wantRes    *saasconsole.OnboardingResponse
+		wantFailed string
+		wantTries  int
+	}{
+		{
+			name:    "no checkpoint",
+			getErr:  doitFirestore.ErrNotFound,
+			wantErr: doitFirestore.ErrNotFound,
+		},
+		{
+			name:       "activation completed",
+			checkpoint: completed,
+			wantErr:    ErrActivationCompleted,
+		},
+		{
+			name:       "billing import fails again",
+			checkpoint: newFailedActivationCheckpoint(activationStepBillingImport),
+			on: func(f *activationFields) {
+				f.SaaSConsoleOnboardDAL.
+					On("GetGCPAccounts", mock.Anything, documentID).
+					Return(doc, nil).
+					Once().
+					On("UpdateGCPOnboardingError", mock.Anything, standaloneID, mock.AnythingOfType("*pkg.StandaloneOnboardingError"), pkg.OnboardingStepActivation).
+					Return(nil).
+					Once()
+				f.BillingImportStatus.
+					On("GetBillingImportStatus", mock.Anything, standaloneCustomerID, accountID).
+					Return(&shared.GCPBillingImportStatus{Status: shared.BillingImportStatusPending}, nil).
+					Once().
+					On("SetStatusPending", mock.Anything, standaloneCustomerID, accountID).
+					Return(nil).
+					Once().
+					On("UpdateMaxTimesThresholds", mock.Anything, standaloneCustomerID, accountID).
+					Return(nil).
+					Once()
+				f.BillingPipeline.
+					On("Onboard", mock.Anything, standaloneCustomerID, accountID, doc.ServiceAccountEmail, mock.AnythingOfType("*pkg.BillingTablesLocation")).
+					Return(testError).
+					Once()
+				f.CustomersDAL.
+					On("GetCustomer", mock.Anything, standaloneCustomerID).
+					Return(nil, testError)
+			},
+			wantRes:    saasconsole.Failure,
+			wantFailed: string(activationStepBillingImport),
+			wantTries:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &activationFields{
+				SaaSConsoleOnboardDAL: &mocks.SaaSConsoleOnboard{},
+				CustomersDAL:          &customerMocks.Customers{},
+				BillingPipeline:       &billingPipelineMocks.ServiceInterface{},
+				BillingImportStatus:   &sharedDalMocks.BillingImportStatus{},
+				ActivationCheckpoints: sharedDalMocks.NewActivationCheckpoint(t),
+			}
+
+			f.ActivationCheckpoints.
+				On("GetActivationCheckpoint", mock.Anything, standaloneCustomerID, accountID).
+				Return(tt.checkpoint, tt.getErr).
+				Once()
+
+			if tt.on != nil {
+				f.ActivationCheckpoints.
+					On("SetActivationCheckpoint", mock.Anything, tt.checkpoint).
+					Return(nil)
+
+				tt.on(f)
+			}
+
+			res, err := newActivationService(f).ResumeActivation(context.Background(), standaloneCustomerID, accountID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRes, res)
+			assert.Equal(t, tt.wantFailed, tt.checkpoint.FailedStep())
+			assert.Equal(t, tt.wantTries, tt.checkpoint.GetStep(tt.wantFailed).Attempts)
+
+			f.BillingImportStatus.AssertExpectations(t)
+			f.BillingPipeline.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_Activate_restartsChangedRequest(t *testing.T) {
+	testError := errors.New("test error")
+	checkpoint := newFailedActivationCheckpoint(activationStepBillingImport)
+
+	f := &activationFields{
+		SaaSConsoleOnboardDAL: &mocks.SaaSConsoleOnboard{},
+		CustomersDAL:          &customerMocks.Customers{},
+		ActivationCheckpoints: sharedDalMocks.NewActivationCheckpoint(t),
+	}
+
+	var saved *domain.ActivationCheckpoint
+
+	f.ActivationCheckpoints.
+		On("GetActivationCheckpoint", mock.Anything, standaloneCustomerID, accountID).
+		Return(checkpoint, nil).
+		Once().
+		On("SetActivationCheckpoint", mock.Anything, mock.AnythingOfType("*domain.ActivationCheckpoint")).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*domain.ActivationCheckpoint)
+		}).
+		Return(nil)
+	f.SaaSConsoleOnboardDAL.
+		On("GetGCPAccounts", mock.Anything, documentID).
+		Return(&pkg.GCPSaaSConsoleAccounts{}, nil).
+		Once().
+		On("UpdateOnboarding", mock.Anything, standaloneID, mock.AnythingOfType("*pkg.GCPSaaSConsoleOnboarding")).
+		Return(testError).
+		Once().
+		On("UpdateGCPOnboardingError", mock.Anything, standaloneID, mock.AnythingOfType("*pkg.StandaloneOnboardingError"), pkg.OnboardingStepActivation).
+		Return(nil).
+		Once()
+	f.CustomersDAL.
+		On("GetCustomer", mock.Anything, standaloneCustomerID).
+		Return(nil, testError)
+
+	res := newActivationService(f).Activate(context.Background(), &GCPStandaloneRequest{
+		CustomerID:       standaloneCustomerID,
+		BillingAccountID: accountID,
+		ProjectID:        "project",
+		DatasetID:        "other-dataset",
+	})
+
+	assert.Equal(t, saasconsole.Failure, res)
+	assert.NotSame(t, checkpoint, saved)
+	assert.Equal(t, "other-dataset", saved.DatasetID)
+	assert.Equal(t, string(activationStepUpdateOnboarding), saved.FailedStep())
+	f.SaaSConsoleOnboardDAL.AssertExpectations(t)
+}
+
+func Test_activationStepFunc_unknownStep(t *testing.T) {
+	s := &GCPSaaSConsoleOnboardService{}
+
+	err := s.activationStepFunc("unknown")(context.Background(), &GCPStandaloneRequest{}, &pkg.GCPSaaSConsoleAccounts{}, false)
+	assert.EqualError(t, err, `unknown activation step "unknown"`)
+}
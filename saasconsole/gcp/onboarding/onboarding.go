@@ -3,10 +3,12 @@ package onboarding
 import (
 	"context"
 
+	doitFirestore "github.com/doitintl/firestore"
 	"github.com/doitintl/firestore/pkg"
 	assets "github.com/doitintl/hello/scheduled-tasks/assets/pkg"
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/saasconsole"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared/domain"
 )
 
 /**
@@ -88,56 +90,23 @@ func (s *GCPSaaSConsoleOnboardService) AddContract(ctx context.Context, req *saa
 	return saasconsole.Success
 }
 
-// Activate final stage of onboarding
+// Activate final stage of onboarding. An activation that failed with the same request is resumed from its failed step
 func (s *GCPSaaSConsoleOnboardService) Activate(ctx context.Context, req *GCPStandaloneRequest) *saasconsole.OnboardingResponse {
 	step := pkg.OnboardingStepActivation
 	ctx = context.WithValue(ctx, saasconsole.CustomerIDKey, req.CustomerID)
 	logger := s.getLogger(ctx)
 	logger.Info(stepMessageActivateStarted)
 
-	documentID := s.getDocumentID(req.CustomerID)
-
-	doc, err := s.saasConsoleDAL.GetGCPAccounts(ctx, documentID)
-	if err != nil {
-		return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
-	}
-
-	standaloneID := pkg.ComposeStandaloneID(req.CustomerID, req.BillingAccountID, pkg.GCP)
-	if err := s.saasConsoleDAL.UpdateOnboarding(ctx, standaloneID, &pkg.GCPSaaSConsoleOnboarding{
-		BillingAccountID: req.BillingAccountID,
-		ProjectID:        req.ProjectID,
-		DatasetID:        req.DatasetID,
-	}); err != nil {
-		return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
-	}
-
-	if err := s.runAllTests(ctx, req, doc); err != nil {
-		return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
-	}
-
-	if !req.DryRun {
-		if err := s.runBillingImport(ctx, req, doc); err != nil {
-			return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
-		}
-	}
-
-	if err := s.CreateAsset(ctx, req.CustomerID, doc.ServiceAccountEmail, req.BillingAccountID); err != nil {
-		return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
-	}
-
-	if err := s.cloudConnectDAL.CreateGCPCloudConnect(ctx, req.BillingAccountID, doc.ServiceAccountEmail, s.customersDAL.GetRef(ctx, req.CustomerID)); err != nil {
+	checkpoint, err := s.activationCheckpoints.GetActivationCheckpoint(ctx, req.CustomerID, req.BillingAccountID)
+	if err != nil && err != doitFirestore.ErrNotFound {
 		return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
 	}
 
-	if err := s.runEnablement(ctx, req, doc); err != nil {
-		return s.updateAndSlackError(ctx, step, req.BillingAccountID, err)
+	if checkpoint == nil || checkpoint.Status == domain.ActivationStatusCompleted || !isSameActivation(checkpoint, req) {
+		checkpoint = domain.NewActivationCheckpoint(req.CustomerID, req.BillingAccountID, req.ProjectID, req.DatasetID, req.DryRun, activationStepNames())
 	}
 
-	logger.Info(stepMessageActivateCompleted)
-
-	_ = saasconsole.PublishOnboardSuccessSlackNotification(ctx, pkg.GCP, s.customersDAL, req.CustomerID, req.BillingAccountID)
-
-	return saasconsole.Success
+	return s.activate(ctx, checkpoint)
 }
 
 // CreateAsset creates GCP Asset on fs given GCP billing account properties
@@ -97,6 +97,7 @@ func Test_updateError(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			}
 
 			if tt.on != nil {
@@ -162,6 +163,7 @@ func Test_utils(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			}
 
 			resDocumentID := s.getDocumentID(tt.args.CustomerID)
@@ -326,6 +328,7 @@ func Test_AddContract(t *testing.T) {
 				f.AccountManagersDAL,
 				nil,
 				f.CustomersDAL,
+				nil,
 			}
 
 			if tt.on != nil {
@@ -413,6 +416,7 @@ func Test_getLogger(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			}
 
 			if tt.on != nil {
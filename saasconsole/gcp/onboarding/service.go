@@ -25,6 +25,7 @@ type GCPSaaSConsoleOnboardService struct {
 	accountManagersDAL     fsdal.AccountManagers
 	assetsDAL              assetsDal.Assets
 	customersDAL           customerDal.Customers
+	activationCheckpoints  sharedDal.ActivationCheckpoint
 }
 
 func NewGCPSaaSConsoleOnboardService(log logger.Provider, conn *connection.Connection) (*GCPSaaSConsoleOnboardService, error) {
@@ -42,5 +43,6 @@ func NewGCPSaaSConsoleOnboardService(log logger.Provider, conn *connection.Conne
 		fsdal.NewAccountManagersDALWithClient(conn.Firestore(ctx)),
 		assetsDal.NewAssetsFirestoreWithClient(conn.Firestore),
 		customerDal.NewCustomersFirestoreWithClient(conn.Firestore),
+		sharedDal.NewActivationCheckpointWithClient(conn.Firestore),
 	}, nil
 }
@@ -52,6 +52,8 @@ var (
 
 	// errors
 	errorServiceAccount = errors.New("empty service account")
+
+	ErrActivationCompleted = errors.New("activation already completed")
 )
 
 func (s *GCPSaaSConsoleOnboardService) getDocumentID(customerID string) string {
@@ -0,0 +1,71 @@
+package dal
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	doitFirestore "github.com/doitintl/firestore"
+	"github.com/doitintl/firestore/iface"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/saasconsole/gcp/shared/domain"
+)
+
+type ActivationCheckpointDAL struct {
+	firestoreClientFun connection.FirestoreFromContextFun
+	documentsHandler   iface.DocumentsHandler
+}
+
+/*
+DAL ActivationCheckpoint interacts with the progress of a customer's SaaS Console billing account activation:
+	customers/CUSTOMER_ID/billingStandaloneActivation/BILLING_ACCOUNT_ID
+*/
+
+type ActivationCheckpoint interface {
+	GetActivationCheckpoint(ctx context.Context, customerID, billingAccountID string) (*domain.ActivationCheckpoint, error)
+	SetActivationCheckpoint(ctx context.Context, checkpoint *domain.ActivationCheckpoint) error
+}
+
+func NewActivationCheckpointWithClient(fun connection.FirestoreFromContextFun) ActivationCheckpoint {
+	return &ActivationCheckpointDAL{
+		fun,
+		doitFirestore.DocumentHandler{},
+	}
+}
+
+func (d *ActivationCheckpointDAL) activationCheckpointDocRef(ctx context.Context, customerID, billingAccountID string) *firestore.DocumentRef {
+	return d.firestoreClientFun(ctx).
+		Collection(customersCollection).
+		Doc(customerID).
+		Collection(domain.ActivationCheckpointCollection).
+		Doc(billingAccountID)
+}
+
+// GetActivationCheckpoint returns the activation checkpoint for a given customer & billing account id
+func (d *ActivationCheckpointDAL) GetActivationCheckpoint(ctx context.Context, customerID, billingAccountID string) (*domain.ActivationCheckpoint, error) {
+	snap, err := d.documentsHandler.Get(ctx, d.activationCheckpointDocRef(ctx, customerID, billingAccountID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, doitFirestore.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	var checkpoint domain.ActivationCheckpoint
+
+	if err := snap.DataTo(&checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// SetActivationCheckpoint overrides the activation checkpoint of the checkpoint's customer & billing account id
+func (d *ActivationCheckpointDAL) SetActivationCheckpoint(ctx context.Context, checkpoint *domain.ActivationCheckpoint) error {
+	_, err := d.documentsHandler.Set(ctx, d.activationCheckpointDocRef(ctx, checkpoint.CustomerID, checkpoint.BillingAccountID), checkpoint)
+
+	return err
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"time"
+)
+
+type ActivationStatus string
+
+const (
+	ActivationStatusPending    ActivationStatus = "pending"
+	ActivationStatusInProgress ActivationStatus = "in_progress"
+	ActivationStatusCompleted  ActivationStatus = "completed"
+	ActivationStatusFailed     ActivationStatus = "failed"
+)
+
+const (
+	ActivationCheckpointCollection = "billingStandaloneActivation"
+)
+
+type ActivationStepCheckpoint struct {
+	Step          string           `json:"step" firestore:"step"`
+	Status        ActivationStatus `json:"status" firestore:"status"`
+	Attempts      int              `json:"attempts" firestore:"attempts"`
+	Error         string           `json:"error,omitempty" firestore:"error"`
+	TimeStarted   *time.Time       `json:"timeStarted,omitempty" firestore:"timeStarted"`
+	TimeCompleted *time.Time       `json:"timeCompleted,omitempty" firestore:"timeCompleted"`
+}
+
+// ActivationCheckpoint is the progress of the activation of a SaaS Console billing account. It keeps
+// the activation request so a failed activation can be resumed from the failed step.
+type ActivationCheckpoint struct {
+	CustomerID       string                      `json:"customerId" firestore:"customerId"`
+	BillingAccountID string                      `json:"billingAccountId" firestore:"billingAccountId"`
+	ProjectID        string                      `json:"projectId" firestore:"projectId"`
+	DatasetID        string                      `json:"datasetId" firestore:"datasetId"`
+	DryRun           bool                        `json:"dryRun" firestore:"dryRun"`
+	Status           ActivationStatus            `json:"status" firestore:"status"`
+	Steps            []*ActivationStepCheckpoint `json:"steps" firestore:"steps"`
+	TimeCreated      time.Time                   `json:"timeCreated" firestore:"timeCreated"`
+	TimeUpdated      time.Time                   `json:"timeUpdated" firestore:"timeUpdated"`
+}
+
+func NewActivationCheckpoint(customerID, billingAccountID, projectID, datasetID string, dryRun bool, steps []string) *ActivationCheckpoint {
+	now := time.Now().UTC()
+
+	checkpoint := &ActivationCheckpoint{
+		CustomerID:       customerID,
+		BillingAccountID: billingAccountID,
+		ProjectID:        projectID,
+		DatasetID:        datasetID,
+		DryRun:           dryRun,
+		Status:           ActivationStatusPending,
+		Steps:            make([]*ActivationStepCheckpoint, len(steps)),
+		TimeCreated:      now,
+		TimeUpdated:      now,
+	}
+
+	for i, step := range steps {
+		checkpoint.Steps[i] = &ActivationStepCheckpoint{
+			Step:   step,
+			Status: ActivationStatusPending,
+		}
+	}
+
+	return checkpoint
+}
+
+// GetStep returns the checkpoint of the given step, or nil if the activation has no such step
+func (c *ActivationCheckpoint) GetStep(step string) *ActivationStepCheckpoint {
+	for _, s := range c.Steps {
+		if s.Step == step {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func (c *ActivationCheckpoint) IsStepCompleted(step string) bool {
+	s := c.GetStep(step)
+	return s != nil && s.Status == ActivationStatusCompleted
+}
+
+func (c *ActivationCheckpoint) StartStep(step string) {
+	s := c.GetStep(step)
+	if s == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	s.Status = ActivationStatusInProgress
+	s.Attempts++
+	s.Error = ""
+	s.TimeStarted = &now
+	s.TimeCompleted = nil
+
+	c.Status = ActivationStatusInProgress
+	c.TimeUpdated = now
+}
+
+func (c *ActivationCheckpoint) CompleteStep(step string) {
+	s := c.GetStep(step)
+	if s == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	s.Status = ActivationStatusCompleted
+	s.TimeCompleted = &now
+
+	c.TimeUpdated = now
+}
+
+func (c *ActivationCheckpoint) FailStep(step string, err error) {
+	now := time.Now().UTC()
+
+	if s := c.GetStep(step); s != nil {
+		s.Status = ActivationStatusFailed
+
+		if err != nil {
+			s.Error = err.Error()
+		}
+	}
+
+	c.Status = ActivationStatusFailed
+	c.TimeUpdated = now
+}
+
+func (c *ActivationCheckpoint) Complete() {
+	c.Status = ActivationStatusCompleted
+	c.TimeUpdated = time.Now().UTC()
+}
+
+// FailedStep returns the step the activation failed at, or an empty string if it did not fail
+func (c *ActivationCheckpoint) FailedStep() string {
+	for _, s := range c.Steps {
+		if s.Status == ActivationStatusFailed {
+			return s.Step
+		}
+	}
+
+	return ""
+}
@@ -20,4 +20,8 @@ type IStripeFirestore interface {
 	LockInvoice(ctx context.Context, invoiceDocID string) error
 	UnlockInvoice(ctx context.Context, invoiceDocID string) error
 	GetPaymentRef(ctx context.Context, paymentID string) *firestore.DocumentRef
+	GetDunning(ctx context.Context, entityID string) (*domain.Dunning, error)
+	SetDunningPolicy(ctx context.Context, entityID string, policy *domain.DunningPolicy) error
+	RecordPaymentAttempts(ctx context.Context, entityID, invoiceDocID string, attempts []*domain.PaymentAttempt, state *domain.DunningInvoiceState) error
+	ListPaymentAttempts(ctx context.Context, entityID string, limit int) ([]*domain.PaymentAttempt, error)
 }
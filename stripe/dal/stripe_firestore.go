@@ -8,6 +8,8 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"github.com/stripe/stripe-go/v74"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	doitFirestore "github.com/doitintl/firestore"
 	"github.com/doitintl/firestore/iface"
@@ -19,6 +21,8 @@ import (
 
 const (
 	fieldInvoiceStripePaymentIntents = "stripePaymentIntents"
+	fieldDunningPolicy               = "policy"
+	fieldDunningInvoices             = "invoices"
 )
 
 // StripeFirestore is used to interact with stripe data stored on Firestore.
@@ -306,3 +310,97 @@ func (d *StripeFirestore) UnlockInvoice(ctx context.Context, invoiceDocID string
 
 	return err
 }
+
+func (d *StripeFirestore) getDunningRef(ctx context.Context, entityID string) *firestore.DocumentRef {
+	return d.getStripeIntegrationsRef(ctx).Collection("stripeDunning").Doc(entityID)
+}
+
+// GetDunning returns the dunning policy and invoices state of an entity. The policy is nil if
+// the entity has no dunning policy of its own.
+func (d *StripeFirestore) GetDunning(ctx context.Context, entityID string) (*domain.Dunning, error) {
+	dunning := domain.Dunning{
+		Invoices: make(map[string]*domain.DunningInvoiceState),
+	}
+
+	docSnap, err := d.getDunningRef(ctx, entityID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return &dunning, nil
+		}
+
+		return nil, err
+	}
+
+	if err := docSnap.DataTo(&dunning); err != nil {
+		return nil, err
+	}
+
+	if dunning.Invoices == nil {
+		dunning.Invoices = make(map[string]*domain.DunningInvoiceState)
+	}
+
+	return &dunning, nil
+}
+
+// SetDunningPolicy sets the dunning policy of an entity, a nil policy resets it to the default policy.
+func (d *StripeFirestore) SetDunningPolicy(ctx context.Context, entityID string, policy *domain.DunningPolicy) error {
+	_, err := d.getDunningRef(ctx, entityID).Set(ctx, map[string]interface{}{
+		fieldDunningPolicy: policy,
+	}, firestore.Merge([]string{fieldDunningPolicy}))
+
+	return err
+}
+
+// RecordPaymentAttempts saves the payment attempts of an invoice and updates its dunning state.
+// A nil state removes the invoice from the entity dunning state.
+func (d *StripeFirestore) RecordPaymentAttempts(
+	ctx context.Context,
+	entityID, invoiceDocID string,
+	attempts []*domain.PaymentAttempt,
+	state *domain.DunningInvoiceState,
+) error {
+	dunningRef := d.getDunningRef(ctx, entityID)
+
+	var invoiceState interface{} = firestore.Delete
+	if state != nil {
+		invoiceState = state
+	}
+
+	return d.firestoreClientFun(ctx).RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, attempt := range attempts {
+			if err := tx.Create(dunningRef.Collection("attempts").NewDoc(), attempt); err != nil {
+				return err
+			}
+		}
+
+		return tx.Set(dunningRef, map[string]interface{}{
+			fieldDunningInvoices: map[string]interface{}{
+				invoiceDocID: invoiceState,
+			},
+		}, firestore.Merge(firestore.FieldPath{fieldDunningInvoices, invoiceDocID}))
+	})
+}
+
+// ListPaymentAttempts returns the latest payment attempts of an entity.
+func (d *StripeFirestore) ListPaymentAttempts(ctx context.Context, entityID string, limit int) ([]*domain.PaymentAttempt, error) {
+	docSnaps, err := d.getDunningRef(ctx, entityID).Collection("attempts").
+		OrderBy("timestamp", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*domain.PaymentAttempt, 0, len(docSnaps))
+
+	for _, docSnap := range docSnaps {
+		var attempt domain.PaymentAttempt
+		if err := docSnap.DataTo(&attempt); err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"time"
+)
+
+type DunningNotificationLevel string
+
+const (
+	DunningNotificationLevelNone     DunningNotificationLevel = ""
+	DunningNotificationLevelReminder DunningNotificationLevel = "reminder"
+	DunningNotificationLevelWarning  DunningNotificationLevel = "warning"
+	DunningNotificationLevelFinal    DunningNotificationLevel = "final"
+)
+
+// Rank orders the notification levels by escalation, an unknown level ranks as none
+func (l DunningNotificationLevel) Rank() int {
+	switch l {
+	case DunningNotificationLevelReminder:
+		return 1
+	case DunningNotificationLevelWarning:
+		return 2
+	case DunningNotificationLevelFinal:
+		return 3
+	default:
+		return 0
+	}
+}
+
+type DunningInvoiceStatus string
+
+const (
+	DunningInvoiceStatusRetrying  DunningInvoiceStatus = "retrying"
+	DunningInvoiceStatusExhausted DunningInvoiceStatus = "exhausted"
+)
+
+// DunningEscalation notifies the customer with the given level once an invoice failed to be charged
+// the given number of times
+type DunningEscalation struct {
+	AfterFailures int                      `firestore:"afterFailures" json:"afterFailures"`
+	Level         DunningNotificationLevel `firestore:"level" json:"level"`
+}
+
+// DunningPolicy configures how the automatic payments of an entity are retried when they fail
+type DunningPolicy struct {
+	// RetryDays are the days after the invoice pay date on which a payment is attempted
+	RetryDays []int `firestore:"retryDays" json:"retryDays"`
+	// RepeatEveryDays keeps attempting payments at this interval after the last retry day, 0 stops retrying
+	RepeatEveryDays int `firestore:"repeatEveryDays" json:"repeatEveryDays"`
+	// MaxAttempts is the number of failed attempts after which an invoice is no longer charged, 0 is unlimited
+	MaxAttempts int `firestore:"maxAttempts" json:"maxAttempts"`
+	// SmartRetry retries soft declines (e.g. insufficient funds) SmartRetryDelayDays after the failed
+	// attempt, without waiting for the next scheduled retry day
+	SmartRetry          bool `firestore:"smartRetry" json:"smartRetry"`
+	SmartRetryDelayDays int  `firestore:"smartRetryDelayDays" json:"smartRetryDelayDays"`
+	// FallbackPaymentMethodID is a saved payment method of the entity charged when the default one fails
+	FallbackPaymentMethodID string              `firestore:"fallbackPaymentMethodId" json:"fallbackPaymentMethodId"`
+	Escalation              []DunningEscalation `firestore:"escalation" json:"escalation"`
+}
+
+// DunningInvoiceState is the dunning progress of an unpaid invoice
+type DunningInvoiceState struct {
+	InvoiceID         string                   `firestore:"invoiceId" json:"invoiceId"`
+	Status            DunningInvoiceStatus     `firestore:"status" json:"status"`
+	Failures          int                      `firestore:"failures" json:"failures"`
+	SoftDecline       bool                     `firestore:"softDecline" json:"softDecline"`
+	LastAttemptAt     time.Time                `firestore:"lastAttemptAt" json:"lastAttemptAt"`
+	NextAttemptAt     *time.Time               `firestore:"nextAttemptAt" json:"nextAttemptAt"`
+	NotificationLevel DunningNotificationLevel `firestore:"notificationLevel" json:"notificationLevel"`
+}
+
+// Dunning is the dunning policy of an entity and the state of its unpaid invoices, keyed by invoice document ID.
+// Invoices are removed from the state once they are paid.
+type Dunning struct {
+	Policy   *DunningPolicy                  `firestore:"policy" json:"policy"`
+	Invoices map[string]*DunningInvoiceState `firestore:"invoices" json:"invoices"`
+}
+
+// PaymentAttempt is a single automatic charge of an invoice
+type PaymentAttempt struct {
+	InvoiceID         string               `firestore:"invoiceId" json:"invoiceId"`
+	PaymentMethodID   string               `firestore:"paymentMethodId" json:"paymentMethodId"`
+	PaymentMethodType string               `firestore:"paymentMethodType" json:"paymentMethodType"`
+	Fallback          bool                 `firestore:"fallback" json:"fallback"`
+	Succeeded         bool                 `firestore:"succeeded" json:"succeeded"`
+	SoftDecline       bool                 `firestore:"softDecline" json:"softDecline"`
+	Error             *PaymentAttemptError `firestore:"error" json:"error"`
+	Timestamp         time.Time            `firestore:"timestamp" json:"timestamp"`
+}
+
+// PaymentAttemptError is the error a payment attempt failed with, with the Stripe error details when available
+type PaymentAttemptError struct {
+	Type        string `firestore:"type" json:"type"`
+	Code        string `firestore:"code" json:"code"`
+	DeclineCode string `firestore:"declineCode" json:"declineCode"`
+	Message     string `firestore:"message" json:"message"`
+	RequestID   string `firestore:"requestId" json:"requestId"`
+}
+
+// DefaultDunningPolicy charges on the pay date and then every repeatEveryDays, notifying on every failure
+func DefaultDunningPolicy(repeatEveryDays int) *DunningPolicy {
+	return &DunningPolicy{
+		RetryDays:       []int{0},
+		RepeatEveryDays: repeatEveryDays,
+		Escalation: []DunningEscalation{
+			{AfterFailures: 1, Level: DunningNotificationLevelReminder},
+		},
+	}
+}
+
+// ShouldAttempt reports whether an invoice with the given pay date should be charged on the given day
+func (p *DunningPolicy) ShouldAttempt(payDate, day time.Time, state *DunningInvoiceState) bool {
+	if state != nil {
+		if p.MaxAttempts > 0 && state.Failures >= p.MaxAttempts {
+			return false
+		}
+
+		// At most one attempt per day
+		if !state.LastAttemptAt.IsZero() && !state.LastAttemptAt.Before(day) {
+			return false
+		}
+
+		if p.SmartRetry && state.SoftDecline && !day.Before(state.LastAttemptAt.AddDate(0, 0, p.SmartRetryDelayDays)) {
+			return true
+		}
+	}
+
+	daysSincePayDate := int(day.Sub(payDate).Hours() / 24)
+	if daysSincePayDate < 0 {
+		return false
+	}
+
+	lastRetryDay := 0
+
+	for _, retryDay := range p.RetryDays {
+		if retryDay == daysSincePayDate {
+			return true
+		}
+
+		if retryDay > lastRetryDay {
+			lastRetryDay = retryDay
+		}
+	}
+
+	return p.RepeatEveryDays > 0 &&
+		daysSincePayDate > lastRetryDay &&
+		(daysSincePayDate-lastRetryDay)%p.RepeatEveryDays == 0
+}
+
+// NextAttempt returns the next day after the last attempt on which the invoice is charged,
+// or nil when it will not be charged again within a year
+func (p *DunningPolicy) NextAttempt(payDate time.Time, state *DunningInvoiceState) *time.Time {
+	from := payDate
+	if state != nil && state.LastAttemptAt.After(from) {
+		from = state.LastAttemptAt.AddDate(0, 0, 1)
+	}
+
+	for day := from; day.Before(from.AddDate(1, 0, 0)); day = day.AddDate(0, 0, 1) {
+		if p.ShouldAttempt(payDate, day, state) {
+			return &day
+		}
+	}
+
+	return nil
+}
+
+// NotificationLevel returns the highest notification level reached after the given number of failures
+func (p *DunningPolicy) NotificationLevel(failures int) DunningNotificationLevel {
+	level := DunningNotificationLevelNone
+
+	for _, escalation := range p.Escalation {
+		if failures >= escalation.AfterFailures && escalation.Level.Rank() > level.Rank() {
+			level = escalation.Level
+		}
+	}
+
+	return level
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDunningPolicy_ShouldAttempt(t *testing.T) {
+	payDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := func(days int) time.Time {
+		return payDate.AddDate(0, 0, days)
+	}
+
+	policy := &DunningPolicy{
+		RetryDays:           []int{0, 3, 7},
+		RepeatEveryDays:     14,
+		MaxAttempts:         5,
+		SmartRetry:          true,
+		SmartRetryDelayDays: 1,
+	}
+
+	tests := []struct {
+		name   string
+		policy *DunningPolicy
+		day    time.Time
+		state  *DunningInvoiceState
+		want   bool
+	}{
+		{
+			name:   "before pay date",
+			policy: policy,
+			day:    day(-1),
+			want:   false,
+		},
+		{
+			name:   "on pay date",
+			policy: policy,
+			day:    day(0),
+			want:   true,
+		},
+		{
+			name:   "on retry day",
+			policy: policy,
+			day:    day(3),
+			state:  &DunningInvoiceState{Failures: 1, LastAttemptAt: day(0)},
+			want:   true,
+		},
+		{
+			name:   "between retry days",
+			policy: policy,
+			day:    day(5),
+			state:  &DunningInvoiceState{Failures: 2, LastAttemptAt: day(3)},
+			want:   false,
+		},
+		{
+			name:   "repeats after the last retry day",
+			policy: policy,
+			day:    day(21),
+			state:  &DunningInvoiceState{Failures: 3, LastAttemptAt: day(7)},
+			want:   true,
+		},
+		{
+			name:   "smart retry of a soft decline",
+			policy: policy,
+			day:    day(1),
+			state:  &DunningInvoiceState{Failures: 1, SoftDecline: true, LastAttemptAt: day(0)},
+			want:   true,
+		},
+		{
+			name:   "no smart retry of a hard decline",
+			policy: policy,
+			day:    day(1),
+			state:  &DunningInvoiceState{Failures: 1, LastAttemptAt: day(0)},
+			want:   false,
+		},
+		{
+			name:   "already attempted today",
+			policy: policy,
+			day:    day(3),
+			state:  &DunningInvoiceState{Failures: 2, SoftDecline: true, LastAttemptAt: day(3)},
+			want:   false,
+		},
+		{
+			name:   "max attempts reached",
+			policy: policy,
+			day:    day(35),
+			state:  &DunningInvoiceState{Failures: 5, LastAttemptAt: day(21)},
+			want:   false,
+		},
+		{
+			name:   "default policy repeats weekly",
+			policy: DefaultDunningPolicy(7),
+			day:    day(14),
+			state:  &DunningInvoiceState{Failures: 2, LastAttemptAt: day(7)},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.ShouldAttempt(payDate, tt.day, tt.state))
+		})
+	}
+}
+
+func TestDunningPolicy_NextAttempt(t *testing.T) {
+	payDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	policy := &DunningPolicy{RetryDays: []int{0, 3}}
+
+	assert.Equal(t, payDate, *policy.NextAttempt(payDate, nil))
+
+	next := policy.NextAttempt(payDate, &DunningInvoiceState{Failures: 1, LastAttemptAt: payDate})
+	assert.Equal(t, payDate.AddDate(0, 0, 3), *next)
+
+	assert.Nil(t, policy.NextAttempt(payDate, &DunningInvoiceState{Failures: 2, LastAttemptAt: payDate.AddDate(0, 0, 3)}))
+}
+
+func TestDunningPolicy_NotificationLevel(t *testing.T) {
+	policy := &DunningPolicy{
+		Escalation: []DunningEscalation{
+			{AfterFailures: 4, Level: DunningNotificationLevelFinal},
+			{AfterFailures: 1, Level: DunningNotificationLevelReminder},
+			{AfterFailures: 2, Level: DunningNotificationLevelWarning},
+		},
+	}
+
+	assert.Equal(t, DunningNotificationLevelNone, policy.NotificationLevel(0))
+	assert.Equal(t, DunningNotificationLevelReminder, policy.NotificationLevel(1))
+	assert.Equal(t, DunningNotificationLevelWarning, policy.NotificationLevel(3))
+	assert.Equal(t, DunningNotificationLevelFinal, policy.NotificationLevel(7))
+}
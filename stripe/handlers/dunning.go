@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/service"
+)
+
+// GetDunningHandler returns a billing profile dunning policy with the state and history of its automatic payments
+func (h *Stripe) GetDunningHandler(ctx *gin.Context) error {
+	customerID := ctx.Param("customerID")
+	entityID := ctx.Param("entityID")
+
+	entity, err := h.entitiesDAL.GetEntity(ctx, entityID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.validatePaymentMethodRequest(ctx, customerID, entity); err != nil {
+		return err
+	}
+
+	result, err := h.GetStripeAccountByCurrency(entity.Currency).service.GetDunning(ctx, entityID)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, result, http.StatusOK)
+}
+
+// UpdateDunningPolicyHandler sets a billing profile dunning policy
+func (h *Stripe) UpdateDunningPolicyHandler(ctx *gin.Context) error {
+	var policy domain.DunningPolicy
+	if err := ctx.ShouldBindJSON(&policy); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	return h.updateDunningPolicy(ctx, &policy)
+}
+
+// ResetDunningPolicyHandler resets a billing profile to the default dunning policy
+func (h *Stripe) ResetDunningPolicyHandler(ctx *gin.Context) error {
+	return h.updateDunningPolicy(ctx, nil)
+}
+
+func (h *Stripe) updateDunningPolicy(ctx *gin.Context, policy *domain.DunningPolicy) error {
+	l := h.loggerProvider(ctx)
+
+	entityID := ctx.Param("entityID")
+
+	entity, err := h.entitiesDAL.GetEntity(ctx, entityID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.GetStripeAccountByCurrency(entity.Currency).service.UpdateDunningPolicy(ctx, entityID, policy); err != nil {
+		l.Errorf("failed to update dunning policy of entity %s with error: %s", entityID, err)
+
+		if errors.Is(err, service.ErrInvalidDunningPolicy) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return web.NewRequestError(err, http.StatusInternalServerError)
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
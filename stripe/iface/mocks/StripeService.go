@@ -7,6 +7,8 @@ import (
 
 	common "github.com/doitintl/hello/scheduled-tasks/common"
 
+	domain "github.com/doitintl/hello/scheduled-tasks/stripe/domain"
+
 	mock "github.com/stretchr/testify/mock"
 
 	service "github.com/doitintl/hello/scheduled-tasks/stripe/service"
@@ -157,6 +159,36 @@ func (_m *StripeService) GetCreditCardProcessingFee(ctx context.Context, custome
 	return r0, r1
 }
 
+// GetDunning provides a mock function with given fields: ctx, entityID
+func (_m *StripeService) GetDunning(ctx context.Context, entityID string) (*service.DunningDetails, error) {
+	ret := _m.Called(ctx, entityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDunning")
+	}
+
+	var r0 *service.DunningDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*service.DunningDetails, error)); ok {
+		return rf(ctx, entityID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *service.DunningDetails); ok {
+		r0 = rf(ctx, entityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.DunningDetails)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, entityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPaymentMethods provides a mock function with given fields: ctx, entity
 func (_m *StripeService) GetPaymentMethods(ctx context.Context, entity *common.Entity) ([]*service.PaymentMethod, error) {
 	ret := _m.Called(ctx, entity)
@@ -259,6 +291,24 @@ func (_m *StripeService) SyncCustomerData(ctx context.Context, entity *common.En
 	return r0
 }
 
+// UpdateDunningPolicy provides a mock function with given fields: ctx, entityID, policy
+func (_m *StripeService) UpdateDunningPolicy(ctx context.Context, entityID string, policy *domain.DunningPolicy) error {
+	ret := _m.Called(ctx, entityID, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDunningPolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.DunningPolicy) error); ok {
+		r0 = rf(ctx, entityID, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateUserPermissions provides a mock function with given fields: ctx, customerID, userID
 func (_m *StripeService) ValidateUserPermissions(ctx context.Context, customerID string, userID string) (bool, error) {
 	ret := _m.Called(ctx, customerID, userID)
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
 	"github.com/doitintl/hello/scheduled-tasks/stripe/service"
 )
 
@@ -24,4 +25,8 @@ type StripeService interface {
 	CreatePMSetupIntentForEntity(ctx context.Context, entity *common.Entity, newEntity bool) (service.SetupIntentClientSecret, error)
 	CreateSetupSessionForEntity(ctx context.Context, entity *common.Entity, urls service.SetupSessionURLs) (service.SetupSession, error)
 	SyncCustomerData(ctx context.Context, entity *common.Entity) error
+
+	// Dunning
+	GetDunning(ctx context.Context, entityID string) (*service.DunningDetails, error)
+	UpdateDunningPolicy(ctx context.Context, entityID string, policy *domain.DunningPolicy) error
 }
@@ -3,15 +3,16 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
 	"cloud.google.com/go/firestore"
-	"github.com/stripe/stripe-go/v74"
 
 	"github.com/doitintl/hello/scheduled-tasks/common"
 	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
 	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/utils"
 	"github.com/doitintl/hello/scheduled-tasks/times"
 )
 
@@ -28,25 +29,29 @@ type AutomaticPaymentsEntityWorkerInput struct {
 
 const paymentRateLimit = time.Millisecond * 250
 
+// automaticPaymentTypes are the payment method types invoices are charged with automatically
+var automaticPaymentTypes = []common.EntityPaymentType{
+	common.EntityPaymentTypeCard,
+	common.EntityPaymentTypeSEPADebit,
+	common.EntityPaymentTypeBankAccount,
+	common.EntityPaymentTypeUSBankAccount,
+	common.EntityPaymentTypeBACSDebit,
+	common.EntityPaymentTypeACSSDebit,
+}
+
 // AutomaticPayments charges invoices on their due date or when overdue days remainder is reached
 func (s *StripeService) AutomaticPayments(ctx context.Context, input AutomaticPaymentsInput) error {
 	l := s.loggerProvider(ctx)
 
 	// If no payment method types are specified for the job, run for all available types
 	if len(input.PaymentMethodTypes) == 0 {
-		input.PaymentMethodTypes = []common.EntityPaymentType{
-			common.EntityPaymentTypeCard,
-			common.EntityPaymentTypeSEPADebit,
-			common.EntityPaymentTypeBankAccount,
-			common.EntityPaymentTypeUSBankAccount,
-			common.EntityPaymentTypeBACSDebit,
-			common.EntityPaymentTypeACSSDebit,
-		}
+		input.PaymentMethodTypes = automaticPaymentTypes
 	}
 
-	// If overdue days remainder is not specified, charge for overdue invoices every week
+	// If overdue days remainder is not specified, charge for overdue invoices every week.
+	// Entities with a dunning policy are charged according to their policy instead.
 	if input.OverdueDaysRemainder < 0 {
-		input.OverdueDaysRemainder = 7
+		input.OverdueDaysRemainder = defaultOverdueDaysRemainder
 	}
 
 	entities, err := s.entitiesDAL.ListActiveEntitiesForPayments(ctx, s.stripeClient.accountID, input.PaymentMethodTypes)
@@ -108,7 +113,8 @@ func (s *StripeService) AutomaticPayments(ctx context.Context, input AutomaticPa
 	return nil
 }
 
-// AutomaticPaymentsEntityWorker charges an entity's invoices on their due date or when overdue days remainder is reached
+// AutomaticPaymentsEntityWorker charges an entity's invoices according to the entity dunning policy,
+// by default on their due date and whenever overdue days remainder is reached
 func (s *StripeService) AutomaticPaymentsEntityWorker(ctx context.Context, input AutomaticPaymentsEntityWorkerInput) error {
 	l := s.loggerProvider(ctx)
 	fs := s.Connection.Firestore(ctx)
@@ -125,6 +131,21 @@ func (s *StripeService) AutomaticPaymentsEntityWorker(ctx context.Context, input
 
 	l.Infof("processing payments for entity %s priority ID %s", entityID, entity.PriorityID)
 
+	if !slices.Contains(automaticPaymentTypes, entity.Payment.Type) {
+		return fmt.Errorf("invalid payment method type %s for entity %s", entity.Payment.Type, entity.PriorityID)
+	}
+
+	dunning, err := s.stripeDAL.GetDunning(ctx, entityID)
+	if err != nil {
+		l.Errorf("failed to get dunning of entity %s", entity.PriorityID)
+		return err
+	}
+
+	policy := dunning.Policy
+	if policy == nil {
+		policy = domain.DefaultDunningPolicy(overdueDaysRemainder)
+	}
+
 	invoiceDocSnaps, err := fs.Collection("invoices").
 		Where("entity", "==", entityRef).
 		Where("PAID", "==", false).
@@ -139,6 +160,22 @@ func (s *StripeService) AutomaticPaymentsEntityWorker(ctx context.Context, input
 		return err
 	}
 
+	// Invoices that are no longer unpaid, e.g. paid manually, leave the dunning
+	unpaidInvoices := make(map[string]bool, len(invoiceDocSnaps))
+	for _, invoiceDocSnap := range invoiceDocSnaps {
+		unpaidInvoices[invoiceDocSnap.Ref.ID] = true
+	}
+
+	for invoiceDocID := range dunning.Invoices {
+		if unpaidInvoices[invoiceDocID] {
+			continue
+		}
+
+		if err := s.stripeDAL.RecordPaymentAttempts(ctx, entityID, invoiceDocID, nil, nil); err != nil {
+			l.Errorf("failed to remove invoice %s from dunning with error: %s", invoiceDocID, err)
+		}
+	}
+
 	if len(invoiceDocSnaps) <= 0 {
 		l.Infof("no unpaid invoices for %s", entity.PriorityID)
 		return nil
@@ -147,83 +184,47 @@ func (s *StripeService) AutomaticPaymentsEntityWorker(ctx context.Context, input
 	l.Infof("fetched %d unpaid invoices for %s", len(invoiceDocSnaps), entity.PriorityID)
 
 	for _, invoiceDocSnap := range invoiceDocSnaps {
+		invoiceDocID := invoiceDocSnap.Ref.ID
+
 		var invoice invoices.FullInvoice
 
 		if err := invoiceDocSnap.DataTo(&invoice); err != nil {
-			l.Errorf("failed to populate invoice %s data with error: %s", invoiceDocSnap.Ref.ID, err)
+			l.Errorf("failed to populate invoice %s data with error: %s", invoiceDocID, err)
 			continue
 		}
 
 		if invoice.StripeLocked {
-			l.Warningf("invoice %s is locked for payments", invoiceDocSnap.Ref.ID)
+			l.Warningf("invoice %s is locked for payments", invoiceDocID)
 			continue
 		}
 
-		// Proceed with payment if today is the pay date or every 7 days after pay date
 		payDate := invoice.PayDate.UTC().Truncate(common.DayDuration)
-		daysSincePayDate := int(today.Sub(payDate).Hours() / 24)
-		shouldCharge := today.Equal(payDate) || (today.After(payDate) && daysSincePayDate%overdueDaysRemainder == 0)
+		state := dunning.Invoices[invoiceDocID]
 
-		if !shouldCharge {
+		if !policy.ShouldAttempt(payDate, today, state) {
 			continue
 		}
 
-		switch entity.Payment.Type {
-		case common.EntityPaymentTypeCard:
-			if err := s.makeCreditCardPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, entity.Payment.Card.ID, nil); err != nil {
-				if stripeErr, ok := err.(*stripe.Error); ok {
-					// Insufficient funds error can still try to charge other invoices for this entity
-					if stripeErr.Type == stripe.ErrorTypeCard && stripeErr.Code == stripe.ErrorCodeCardDeclined {
-						if err, ok := stripeErr.Err.(*stripe.CardError); ok && err.DeclineCode == stripe.DeclineCodeInsufficientFunds {
-							l.Warningf("CC payment for invoice %s failed with insufficient funds", invoiceDocSnap.Ref.ID)
-							continue
-						}
-					}
-
-					l.Errorf("CC payment for invoice %s failed with stripe error [code: %s]: %s", invoiceDocSnap.Ref.ID, stripeErr.Code, stripeErr.Msg)
-
-					// If payment failed because of stripe error other than insufficient funds,
-					// stop trying to process payments for this entity
-					return nil
-				}
-
-				l.Errorf("CC payment for invoice %s failed with error: %s", invoiceDocSnap.Ref.ID, err)
-
-				continue
-			}
-
-			l.Infof("CC payment for invoice %s succeeded", invoiceDocSnap.Ref.ID)
-		case common.EntityPaymentTypeSEPADebit:
-			if err := s.makeSEPADebitPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, entity.Payment.SEPADebit.ID, nil); err != nil {
-				l.Errorf("SEPA payment for invoice %s failed with error: %s", invoiceDocSnap.Ref.ID, err)
-				break
-			}
+		attempts, err := s.attemptInvoicePayment(ctx, entity, policy, invoiceDocSnap, today)
+		if err == nil {
+			l.Infof("payment for invoice %s succeeded", invoiceDocID)
 
-			l.Infof("SEPA payment for invoice %s succeeded", invoiceDocSnap.Ref.ID)
-		case common.EntityPaymentTypeBACSDebit:
-			if err := s.makeBACSDebitPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, entity.Payment.BACSDebit.ID, nil); err != nil {
-				l.Errorf("BACS payment for invoice %s failed with error: %s", invoiceDocSnap.Ref.ID, err)
-				break
-			}
+			state = nil
+		} else {
+			l.Errorf("payment for invoice %s failed with error: %s", invoiceDocID, err)
 
-			l.Infof("BACS payment for invoice %s succeeded", invoiceDocSnap.Ref.ID)
-		case common.EntityPaymentTypeACSSDebit:
-			if err := s.makeACSSDebitPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, entity.Payment.ACSSDebit.ID, nil); err != nil {
-				l.Errorf("ACSS payment for invoice %s failed with error: %s", invoiceDocSnap.Ref.ID, err)
-				break
-			}
+			state = failedDunningInvoiceState(policy, state, invoiceDocID, payDate, today, isSoftDecline(err))
 
-			l.Infof("ACSS payment for invoice %s succeeded", invoiceDocSnap.Ref.ID)
-		case common.EntityPaymentTypeBankAccount, common.EntityPaymentTypeUSBankAccount:
-			if err := s.makeACHPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, entity.Payment.BankAccount.ID, nil); err != nil {
-				l.Errorf("ACH payment for invoice %s failed with error: %s", invoiceDocSnap.Ref.ID, err)
-				break
+			if level := policy.NotificationLevel(state.Failures); level != domain.DunningNotificationLevelNone && isPaymentDeclined(err) {
+				escalated := level.Rank() > state.NotificationLevel.Rank()
+				if s.notifyPaymentFailed(ctx, entity, invoiceDocSnap, utils.ToCents(invoice.Debit), level, escalated) {
+					state.NotificationLevel = level
+				}
 			}
+		}
 
-			l.Infof("ACH payment for invoice %s succeeded", invoiceDocSnap.Ref.ID)
-
-		default:
-			return fmt.Errorf("invalid payment method type %s for entity %s", entity.Payment.Type, entity.PriorityID)
+		if err := s.stripeDAL.RecordPaymentAttempts(ctx, entityID, invoiceDocID, attempts, state); err != nil {
+			l.Errorf("failed to record payment attempts of invoice %s with error: %s", invoiceDocID, err)
 		}
 
 		// Rate limit payments to avoid hitting priority rate limits
@@ -232,3 +233,76 @@ func (s *StripeService) AutomaticPaymentsEntityWorker(ctx context.Context, input
 
 	return nil
 }
+
+// attemptInvoicePayment charges an invoice with the entity default payment method, and with the policy fallback
+// payment method if the default one is declined. Returns the attempts made and the error of the last one.
+func (s *StripeService) attemptInvoicePayment(
+	ctx context.Context,
+	entity *common.Entity,
+	policy *domain.DunningPolicy,
+	invoiceDocSnap *firestore.DocumentSnapshot,
+	today time.Time,
+) ([]*domain.PaymentAttempt, error) {
+	l := s.loggerProvider(ctx)
+	invoiceDocID := invoiceDocSnap.Ref.ID
+	paymentType, pmID := entity.Payment.Type, entity.Payment.ID()
+
+	err := s.chargeInvoice(ctx, entity, invoiceDocSnap, today, paymentType, pmID)
+	attempts := []*domain.PaymentAttempt{newPaymentAttempt(invoiceDocID, paymentType, pmID, false, err)}
+
+	fallbackID := policy.FallbackPaymentMethodID
+	if err == nil || !isPaymentDeclined(err) || fallbackID == "" || fallbackID == pmID {
+		return attempts, err
+	}
+
+	l.Warningf("%s payment for invoice %s declined with error: %s, charging fallback payment method", paymentType, invoiceDocID, err)
+
+	fallbackType, err := s.paymentMethodType(fallbackID)
+	if err == nil {
+		err = s.chargeInvoice(ctx, entity, invoiceDocSnap, today, fallbackType, fallbackID)
+	}
+
+	attempts = append(attempts, newPaymentAttempt(invoiceDocID, fallbackType, fallbackID, true, err))
+
+	return attempts, err
+}
+
+// chargeInvoice charges the open balance of an invoice with a saved payment method of the entity
+func (s *StripeService) chargeInvoice(
+	ctx context.Context,
+	entity *common.Entity,
+	invoiceDocSnap *firestore.DocumentSnapshot,
+	today time.Time,
+	paymentType common.EntityPaymentType,
+	pmID string,
+) error {
+	switch paymentType {
+	case common.EntityPaymentTypeCard:
+		return s.makeCreditCardPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, pmID, nil)
+	case common.EntityPaymentTypeSEPADebit:
+		return s.makeSEPADebitPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, pmID, nil)
+	case common.EntityPaymentTypeBACSDebit:
+		return s.makeBACSDebitPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, pmID, nil)
+	case common.EntityPaymentTypeACSSDebit:
+		return s.makeACSSDebitPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, pmID, nil)
+	case common.EntityPaymentTypeBankAccount, common.EntityPaymentTypeUSBankAccount:
+		return s.makeACHPayment(ctx, paymentsEmail, entity, invoiceDocSnap, today, pmID, nil)
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidPaymentMethodType, paymentType)
+	}
+}
+
+// paymentMethodType returns the entity payment type of a saved stripe payment method
+func (s *StripeService) paymentMethodType(pmID string) (common.EntityPaymentType, error) {
+	pm, err := s.stripeClient.PaymentMethods.Get(pmID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	paymentMethod := NewPaymentMethod(pm, s.stripeClient.accountID)
+	if paymentMethod == nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPaymentMethodType, pm.Type)
+	}
+
+	return common.EntityPaymentType(paymentMethod.Type), nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-go/v74"
+
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
+)
+
+const (
+	defaultOverdueDaysRemainder = 7
+	paymentAttemptsHistoryLimit = 100
+)
+
+// softDeclineCodes are card declines that may succeed when retried later with the same card
+var softDeclineCodes = map[stripe.DeclineCode]bool{
+	stripe.DeclineCodeApproveWithID:                true,
+	stripe.DeclineCodeCardVelocityExceeded:         true,
+	stripe.DeclineCodeInsufficientFunds:            true,
+	stripe.DeclineCodeIssuerNotAvailable:           true,
+	stripe.DeclineCodeProcessingError:              true,
+	stripe.DeclineCodeReenterTransaction:           true,
+	stripe.DeclineCodeTryAgainLater:                true,
+	stripe.DeclineCodeWithdrawalCountLimitExceeded: true,
+}
+
+// debitDeclineCodes are the errors of bank debits (SEPA, BACS, ACSS and ACH) declined by the bank or
+// the account holder, which stripe reports as invalid request errors rather than card errors
+var debitDeclineCodes = map[stripe.ErrorCode]bool{
+	stripe.ErrorCodeAccountClosed:                     true,
+	stripe.ErrorCodeBankAccountDeclined:               true,
+	stripe.ErrorCodeBankAccountRestricted:             true,
+	stripe.ErrorCodeBankAccountUnusable:               true,
+	stripe.ErrorCodeBankAccountUnverified:             true,
+	stripe.ErrorCodeDebitNotAuthorized:                true,
+	stripe.ErrorCodeInsufficientFunds:                 true,
+	stripe.ErrorCodeNoAccount:                         true,
+	stripe.ErrorCodePaymentIntentPaymentAttemptFailed: true,
+	stripe.ErrorCodePaymentMethodBankAccountBlocked:   true,
+	stripe.ErrorCodePaymentMethodCustomerDecline:      true,
+	stripe.ErrorCodePaymentMethodProviderDecline:      true,
+}
+
+// DunningDetails is the dunning policy of an entity with the state and history of its automatic payments
+type DunningDetails struct {
+	Policy        *domain.DunningPolicy                  `json:"policy"`
+	DefaultPolicy bool                                   `json:"defaultPolicy"`
+	Invoices      map[string]*domain.DunningInvoiceState `json:"invoices"`
+	Attempts      []*domain.PaymentAttempt               `json:"attempts"`
+}
+
+// GetDunning returns the dunning policy, the state of the unpaid invoices and the latest payment attempts of an entity
+func (s *StripeService) GetDunning(ctx context.Context, entityID string) (*DunningDetails, error) {
+	dunning, err := s.stripeDAL.GetDunning(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.stripeDAL.ListPaymentAttempts(ctx, entityID, paymentAttemptsHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &DunningDetails{
+		Policy:   dunning.Policy,
+		Invoices: dunning.Invoices,
+		Attempts: attempts,
+	}
+
+	if details.Policy == nil {
+		details.Policy = domain.DefaultDunningPolicy(defaultOverdueDaysRemainder)
+		details.DefaultPolicy = true
+	}
+
+	return details, nil
+}
+
+// UpdateDunningPolicy sets the dunning policy of an entity, a nil policy resets the entity to the default policy
+func (s *StripeService) UpdateDunningPolicy(ctx context.Context, entityID string, policy *domain.DunningPolicy) error {
+	if policy != nil {
+		if err := validateDunningPolicy(policy); err != nil {
+			return err
+		}
+
+		if policy.FallbackPaymentMethodID != "" {
+			sci, err := s.stripeDAL.GetCustomerInfo(ctx, entityID)
+			if err != nil {
+				return err
+			}
+
+			pm, err := s.stripeClient.PaymentMethods.Get(policy.FallbackPaymentMethodID, nil)
+			if err != nil {
+				return err
+			}
+
+			if pm.Customer == nil || pm.Customer.ID != sci.ID {
+				return fmt.Errorf("%w: payment method %s is not saved for the entity", ErrInvalidDunningPolicy, pm.ID)
+			}
+		}
+	}
+
+	return s.stripeDAL.SetDunningPolicy(ctx, entityID, policy)
+}
+
+func validateDunningPolicy(policy *domain.DunningPolicy) error {
+	for _, retryDay := range policy.RetryDays {
+		if retryDay < 0 {
+			return fmt.Errorf("%w: negative retry day %d", ErrInvalidDunningPolicy, retryDay)
+		}
+	}
+
+	if policy.RepeatEveryDays < 0 || policy.MaxAttempts < 0 {
+		return fmt.Errorf("%w: negative repeat interval or max attempts", ErrInvalidDunningPolicy)
+	}
+
+	if len(policy.RetryDays) == 0 && policy.RepeatEveryDays == 0 {
+		return fmt.Errorf("%w: no retry days", ErrInvalidDunningPolicy)
+	}
+
+	if policy.SmartRetry && policy.SmartRetryDelayDays < 1 {
+		return fmt.Errorf("%w: smart retry delay must be at least one day", ErrInvalidDunningPolicy)
+	}
+
+	for _, escalation := range policy.Escalation {
+		if escalation.AfterFailures < 1 || escalation.Level.Rank() == 0 {
+			return fmt.Errorf("%w: invalid escalation %d %q", ErrInvalidDunningPolicy, escalation.AfterFailures, escalation.Level)
+		}
+	}
+
+	return nil
+}
+
+// isPaymentDeclined reports whether a card or bank debit payment failed because it was declined, rather
+// than because of an internal error
+func isPaymentDeclined(err error) bool {
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return false
+	}
+
+	if isDeclineError(stripeErr) {
+		return true
+	}
+
+	// the payment intent of a failed payment holds the error of its last payment attempt
+	return stripeErr.PaymentIntent != nil && stripeErr.PaymentIntent.LastPaymentError != nil &&
+		isDeclineError(stripeErr.PaymentIntent.LastPaymentError)
+}
+
+func isDeclineError(stripeErr *stripe.Error) bool {
+	return stripeErr.Type == stripe.ErrorTypeCard || debitDeclineCodes[stripeErr.Code]
+}
+
+// isSoftDecline reports whether a failed payment may succeed when retried with the same payment method
+func isSoftDecline(err error) bool {
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return false
+	}
+
+	switch {
+	case stripeErr.Type == stripe.ErrorTypeAPI, stripeErr.Code == stripe.ErrorCodeRateLimit:
+		return true
+	case stripeErr.Type != stripe.ErrorTypeCard:
+		return false
+	case stripeErr.Code == stripe.ErrorCodeProcessingError:
+		return true
+	case stripeErr.Code != stripe.ErrorCodeCardDeclined:
+		return false
+	}
+
+	declineCode := stripeErr.DeclineCode
+
+	var cardErr *stripe.CardError
+	if declineCode == "" && errors.As(stripeErr.Err, &cardErr) {
+		declineCode = cardErr.DeclineCode
+	}
+
+	return softDeclineCodes[declineCode]
+}
+
+func newPaymentAttempt(invoiceDocID string, paymentType common.EntityPaymentType, pmID string, fallback bool, err error) *domain.PaymentAttempt {
+	attempt := &domain.PaymentAttempt{
+		InvoiceID:         invoiceDocID,
+		PaymentMethodID:   pmID,
+		PaymentMethodType: string(paymentType),
+		Fallback:          fallback,
+		Succeeded:         err == nil,
+		Timestamp:         time.Now().UTC(),
+	}
+
+	if err == nil {
+		return attempt
+	}
+
+	attempt.SoftDecline = isSoftDecline(err)
+	attempt.Error = &domain.PaymentAttemptError{
+		Message: err.Error(),
+	}
+
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) {
+		attempt.Error.Type = string(stripeErr.Type)
+		attempt.Error.Code = string(stripeErr.Code)
+		attempt.Error.DeclineCode = string(stripeErr.DeclineCode)
+		attempt.Error.Message = stripeErr.Msg
+		attempt.Error.RequestID = stripeErr.RequestID
+	}
+
+	return attempt
+}
+
+// failedDunningInvoiceState returns the dunning state of an invoice after a failed payment attempt on the given day
+func failedDunningInvoiceState(
+	policy *domain.DunningPolicy,
+	state *domain.DunningInvoiceState,
+	invoiceDocID string,
+	payDate, day time.Time,
+	softDecline bool,
+) *domain.DunningInvoiceState {
+	next := domain.DunningInvoiceState{
+		InvoiceID:     invoiceDocID,
+		Status:        domain.DunningInvoiceStatusRetrying,
+		Failures:      1,
+		SoftDecline:   softDecline,
+		LastAttemptAt: day,
+	}
+
+	if state != nil {
+		next.Failures = state.Failures + 1
+		next.NotificationLevel = state.NotificationLevel
+	}
+
+	next.NextAttemptAt = policy.NextAttempt(payDate, &next)
+	if next.NextAttemptAt == nil {
+		next.Status = domain.DunningInvoiceStatusExhausted
+	}
+
+	return &next
+}
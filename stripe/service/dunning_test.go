@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/stripe-go/v74"
+
+	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
+)
+
+func Test_isSoftDecline(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "not a stripe error",
+			err:  errors.New("priority error"),
+			want: false,
+		},
+		{
+			name: "insufficient funds",
+			err: &stripe.Error{
+				Type:        stripe.ErrorTypeCard,
+				Code:        stripe.ErrorCodeCardDeclined,
+				DeclineCode: stripe.DeclineCodeInsufficientFunds,
+			},
+			want: true,
+		},
+		{
+			name: "soft decline of wrapped card error",
+			err: fmt.Errorf("wrapped: %w", &stripe.Error{
+				Type: stripe.ErrorTypeCard,
+				Code: stripe.ErrorCodeCardDeclined,
+				Err:  &stripe.CardError{DeclineCode: stripe.DeclineCodeTryAgainLater},
+			}),
+			want: true,
+		},
+		{
+			name: "stolen card",
+			err: &stripe.Error{
+				Type:        stripe.ErrorTypeCard,
+				Code:        stripe.ErrorCodeCardDeclined,
+				DeclineCode: stripe.DeclineCodeStolenCard,
+			},
+			want: false,
+		},
+		{
+			name: "expired card",
+			err:  &stripe.Error{Type: stripe.ErrorTypeCard, Code: stripe.ErrorCodeExpiredCard},
+			want: false,
+		},
+		{
+			name: "stripe api error",
+			err:  &stripe.Error{Type: stripe.ErrorTypeAPI},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isSoftDecline(tt.err))
+		})
+	}
+}
+
+func Test_isPaymentDeclined(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "not a stripe error",
+			err:  errors.New("priority error"),
+			want: false,
+		},
+		{
+			name: "card declined",
+			err:  &stripe.Error{Type: stripe.ErrorTypeCard, Code: stripe.ErrorCodeCardDeclined},
+			want: true,
+		},
+		{
+			name: "sepa debit not authorized",
+			err:  &stripe.Error{Type: stripe.ErrorTypeInvalidRequest, Code: stripe.ErrorCodeDebitNotAuthorized},
+			want: true,
+		},
+		{
+			name: "ach debit of a closed account",
+			err: fmt.Errorf("wrapped: %w", &stripe.Error{
+				Type: stripe.ErrorTypeInvalidRequest,
+				Code: stripe.ErrorCodeAccountClosed,
+			}),
+			want: true,
+		},
+		{
+			name: "failed payment attempt of a bacs debit",
+			err: &stripe.Error{
+				Type: stripe.ErrorTypeInvalidRequest,
+				PaymentIntent: &stripe.PaymentIntent{
+					LastPaymentError: &stripe.Error{Code: stripe.ErrorCodeInsufficientFunds},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "invalid request",
+			err:  &stripe.Error{Type: stripe.ErrorTypeInvalidRequest, Code: stripe.ErrorCodeResourceMissing},
+			want: false,
+		},
+		{
+			name: "stripe api error",
+			err:  &stripe.Error{Type: stripe.ErrorTypeAPI},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPaymentDeclined(tt.err))
+		})
+	}
+}
+
+func Test_validateDunningPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *domain.DunningPolicy
+		wantErr bool
+	}{
+		{
+			name:   "default policy",
+			policy: domain.DefaultDunningPolicy(defaultOverdueDaysRemainder),
+		},
+		{
+			name:    "negative retry day",
+			policy:  &domain.DunningPolicy{RetryDays: []int{0, -1}},
+			wantErr: true,
+		},
+		{
+			name:    "no retry days",
+			policy:  &domain.DunningPolicy{},
+			wantErr: true,
+		},
+		{
+			name:    "smart retry without delay",
+			policy:  &domain.DunningPolicy{RetryDays: []int{0}, SmartRetry: true},
+			wantErr: true,
+		},
+		{
+			name: "invalid escalation level",
+			policy: &domain.DunningPolicy{
+				RetryDays:  []int{0},
+				Escalation: []domain.DunningEscalation{{AfterFailures: 1, Level: "urgent"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDunningPolicy(tt.policy)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDunningPolicy)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_failedDunningInvoiceState(t *testing.T) {
+	payDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	policy := &domain.DunningPolicy{
+		RetryDays:   []int{0, 3},
+		MaxAttempts: 2,
+	}
+
+	state := failedDunningInvoiceState(policy, nil, "invoice", payDate, payDate, true)
+	nextAttempt := payDate.AddDate(0, 0, 3)
+
+	assert.Equal(t, &domain.DunningInvoiceState{
+		InvoiceID:     "invoice",
+		Status:        domain.DunningInvoiceStatusRetrying,
+		Failures:      1,
+		SoftDecline:   true,
+		LastAttemptAt: payDate,
+		NextAttemptAt: &nextAttempt,
+	}, state)
+
+	state.NotificationLevel = domain.DunningNotificationLevelReminder
+	state = failedDunningInvoiceState(policy, state, "invoice", payDate, nextAttempt, false)
+
+	assert.Equal(t, &domain.DunningInvoiceState{
+		InvoiceID:         "invoice",
+		Status:            domain.DunningInvoiceStatusExhausted,
+		Failures:          2,
+		LastAttemptAt:     nextAttempt,
+		NotificationLevel: domain.DunningNotificationLevelReminder,
+	}, state)
+}
@@ -28,4 +28,5 @@ var (
 	ErrEntityNotFound              = errors.New("entity not found")
 	ErrDoitCustomerNotFound        = errors.New("doit customer not found")
 	ErrDetachPaymentMethod         = errors.New("failed to detach payment method")
+	ErrInvalidDunningPolicy        = errors.New("invalid dunning policy")
 )
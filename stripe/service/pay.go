@@ -9,6 +9,7 @@ import (
 	"github.com/stripe/stripe-go/v74"
 
 	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
 )
 
 type PayInvoiceInput struct {
@@ -41,6 +42,10 @@ func (s *StripeService) PayInvoice(ctx context.Context, input PayInvoiceInput, e
 	switch input.Type {
 	case common.EntityPaymentTypeCard:
 		if err := s.makeCreditCardPayment(ctx, input.Email, entity, invoiceDocSnap, today, input.PaymentMethodID, amount); err != nil {
+			if isPaymentDeclined(err) {
+				s.notifyPaymentFailed(ctx, entity, invoiceDocSnap, *amount, domain.DunningNotificationLevelReminder, false)
+			}
+
 			return err
 		}
 	case common.EntityPaymentTypeUSBankAccount, common.EntityPaymentTypeBankAccount:
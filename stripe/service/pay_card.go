@@ -287,12 +287,6 @@ func (s *StripeService) makeCreditCardPayment(ctx context.Context, email string,
 
 	pi, err := s.stripeClient.PaymentIntents.New(params)
 	if err != nil {
-		if stripeErr, ok := err.(*stripe.Error); ok {
-			if err := s.sendPaymentFailedNotification(ctx, entity, &invoice, *amount, stripeErr); err != nil {
-				l.Errorf("failed to send payment failed notification: %s", err)
-			}
-		}
-
 		return err
 	}
 
@@ -8,7 +8,6 @@ import (
 	"cloud.google.com/go/firestore"
 	sendgrid "github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"github.com/stripe/stripe-go/v74"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"google.golang.org/grpc/codes"
@@ -18,27 +17,19 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
 	"github.com/doitintl/hello/scheduled-tasks/fixer"
 	"github.com/doitintl/hello/scheduled-tasks/mailer"
+	"github.com/doitintl/hello/scheduled-tasks/stripe/domain"
 )
 
-func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entity *common.Entity, invoice *invoices.FullInvoice, amount int64, stripeErr *stripe.Error) error {
-	if stripeErr.Type == stripe.ErrorTypeCard {
-		switch stripeErr.Code {
-		case stripe.ErrorCodeCardDeclined:
-		case stripe.ErrorCodeExpiredCard:
-		case stripe.ErrorCodeIncorrectCVC:
-		case stripe.ErrorCodeIncorrectZip:
-		case stripe.ErrorCodeIncorrectNumber:
-		case stripe.ErrorCodeInvalidCVC:
-		case stripe.ErrorCodeInvalidExpiryMonth:
-		case stripe.ErrorCodeInvalidExpiryYear:
-		case stripe.ErrorCodeInvalidNumber:
-		default:
-			return nil
-		}
-	} else {
-		return nil
-	}
-
+// sendPaymentFailedNotification emails the entity contacts that a payment of the invoice failed, at most once
+// a week unless the notification escalates to a higher level. Returns whether the notification was sent.
+func (s *StripeService) sendPaymentFailedNotification(
+	ctx context.Context,
+	entity *common.Entity,
+	invoice *invoices.FullInvoice,
+	amount int64,
+	level domain.DunningNotificationLevel,
+	escalated bool,
+) (bool, error) {
 	l := s.loggerProvider(ctx)
 	fs := s.Firestore(ctx)
 
@@ -46,7 +37,7 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 
 	customer, err := common.GetCustomer(ctx, entity.Customer)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	entityRef := invoice.Entity
@@ -69,14 +60,15 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 			}
 			lastNotification := v.(time.Time)
 
-			// If the last notification was sent less then 7 days ago, skip sending
-			if !lastNotification.IsZero() && lastNotification.Add(dayDuration*7).After(now) {
+			// If the last notification was sent less then 7 days ago, skip sending unless escalating
+			if !escalated && !lastNotification.IsZero() && lastNotification.Add(dayDuration*7).After(now) {
 				return nil
 			}
 		}
 
 		if err := tx.Set(ref, map[string]interface{}{
 			"timestamp": now,
+			"level":     level,
 		}); err != nil {
 			return err
 		}
@@ -84,11 +76,11 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 		shouldSendNotification = true
 		return nil
 	}, firestore.MaxAttempts(5)); err != nil {
-		return err
+		return false, err
 	}
 
 	if !shouldSendNotification {
-		return nil
+		return false, nil
 	}
 
 	tos := make([]*mail.Email, 0)
@@ -109,7 +101,7 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 		Where("entities", "array-contains", entityRef).
 		Documents(ctx).GetAll()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	for _, docSnap := range userDocSnaps {
@@ -127,7 +119,7 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 	}
 
 	if len(tos) <= 0 {
-		return nil
+		return false, nil
 	}
 
 	if accountManager, err := common.GetAccountManager(ctx, customer.AccountManager); err != nil {
@@ -171,6 +163,7 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 	p.SetDynamicTemplateData("domain", customer.PrimaryDomain)
 	p.SetDynamicTemplateData("date", now.Format("02 Jan 2006"))
 	p.SetDynamicTemplateData("amount", fixer.FormatCurrencyAmountInt64(msgPrinter, amount, invoice.Symbol))
+	p.SetDynamicTemplateData("level", string(level))
 	m.AddPersonalizations(p)
 
 	request := sendgrid.GetRequest(mailer.Config.APIKey, mailer.Config.MailSendPath, mailer.Config.BaseURL)
@@ -178,10 +171,36 @@ func (s *StripeService) sendPaymentFailedNotification(ctx context.Context, entit
 	request.Body = mail.GetRequestBody(m)
 
 	if _, err := sendgrid.MakeRequestRetry(request); err != nil {
-		return err
+		return false, err
 	}
 
-	l.Println("payment failed notification sent successfully")
+	l.Printf("payment failed notification (%s) sent successfully", level)
+
+	return true, nil
+}
+
+// notifyPaymentFailed sends the payment failed notification of an invoice, logging failures to send it.
+// Returns whether the notification was sent.
+func (s *StripeService) notifyPaymentFailed(
+	ctx context.Context,
+	entity *common.Entity,
+	invoiceDocSnap *firestore.DocumentSnapshot,
+	amount int64,
+	level domain.DunningNotificationLevel,
+	escalated bool,
+) bool {
+	l := s.loggerProvider(ctx)
+
+	var invoice invoices.FullInvoice
+	if err := invoiceDocSnap.DataTo(&invoice); err != nil {
+		l.Errorf("failed to populate invoice %s data with error: %s", invoiceDocSnap.Ref.ID, err)
+		return false
+	}
+
+	sent, err := s.sendPaymentFailedNotification(ctx, entity, &invoice, amount, level, escalated)
+	if err != nil {
+		l.Errorf("failed to send payment failed notification: %s", err)
+	}
 
-	return nil
+	return sent
 }
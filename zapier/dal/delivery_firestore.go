@@ -0,0 +1,161 @@
+package dal
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	doitFirestore "github.com/doitintl/firestore"
+	firestoreIface "github.com/doitintl/firestore/iface"
+	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
+	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/domain"
+)
+
+const webhookDeliveryCollection = "integrations/zapier/webhookDeliveries"
+
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+//go:generate mockery --name WebhookDeliveryDAL --output=./mocks
+type WebhookDeliveryDAL interface {
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) (string, error)
+	Update(ctx context.Context, delivery *domain.WebhookDelivery) error
+	Get(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+	List(
+		ctx context.Context,
+		customer *firestore.DocumentRef,
+		subscriptionID string,
+		limit int,
+	) ([]*domain.WebhookDelivery, error)
+}
+
+// WebhookDeliveryFirestore is used to interact with webhook deliveries stored on Firestore.
+type WebhookDeliveryFirestore struct {
+	firestoreClientFn connection.FirestoreFromContextFun
+	dh                firestoreIface.DocumentsHandler
+	l                 logger.Provider
+}
+
+// NewWebhookDeliveryFirestoreWithClient returns a new WebhookDeliveryFirestore using given client.
+func NewWebhookDeliveryFirestoreWithClient(log logger.Provider, fun connection.FirestoreFromContextFun) *WebhookDeliveryFirestore {
+	return &WebhookDeliveryFirestore{
+		firestoreClientFn: fun,
+		dh:                doitFirestore.DocumentHandler{},
+		l:                 log,
+	}
+}
+
+// Create creates a webhook delivery in firestore
+func (wd *WebhookDeliveryFirestore) Create(
+	ctx context.Context,
+	delivery *domain.WebhookDelivery,
+) (string, error) {
+	if delivery == nil {
+		return "", errors.New("delivery cannot be nil")
+	}
+
+	ref, _, err := wd.
+		firestoreClientFn(ctx).
+		Collection(webhookDeliveryCollection).
+		Add(ctx, delivery)
+	if err != nil {
+		return "", err
+	}
+
+	delivery.ID = ref.ID
+
+	return ref.ID, nil
+}
+
+// Update overwrites a webhook delivery in firestore
+func (wd *WebhookDeliveryFirestore) Update(
+	ctx context.Context,
+	delivery *domain.WebhookDelivery,
+) error {
+	if delivery == nil || delivery.ID == "" {
+		return errors.New("invalid delivery")
+	}
+
+	_, err := wd.
+		firestoreClientFn(ctx).
+		Collection(webhookDeliveryCollection).
+		Doc(delivery.ID).
+		Set(ctx, delivery)
+
+	return err
+}
+
+// Get returns a webhook delivery from firestore
+func (wd *WebhookDeliveryFirestore) Get(
+	ctx context.Context,
+	deliveryID string,
+) (*domain.WebhookDelivery, error) {
+	if deliveryID == "" {
+		return nil, errors.New("invalid delivery ID")
+	}
+
+	doc := wd.
+		firestoreClientFn(ctx).
+		Collection(webhookDeliveryCollection).
+		Doc(deliveryID)
+
+	snap, err := wd.dh.Get(ctx, doc)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrDeliveryNotFound
+		}
+
+		return nil, err
+	}
+
+	var d domain.WebhookDelivery
+	if err := snap.DataTo(&d); err != nil {
+		return nil, err
+	}
+
+	d.ID = snap.ID()
+
+	return &d, nil
+}
+
+// List returns the latest webhook deliveries of the customer, optionally filtered by subscription
+func (wd *WebhookDeliveryFirestore) List(
+	ctx context.Context,
+	customer *firestore.DocumentRef,
+	subscriptionID string,
+	limit int,
+) ([]*domain.WebhookDelivery, error) {
+	query := wd.firestoreClientFn(ctx).Collection(webhookDeliveryCollection).
+		Where("customer", "==", customer)
+
+	if subscriptionID != "" {
+		query = query.Where("subscriptionId", "==", subscriptionID)
+	}
+
+	docs, err := query.
+		OrderBy("timeCreated", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, 0, len(docs))
+
+	for _, doc := range docs {
+		var d domain.WebhookDelivery
+		if err := doc.DataTo(&d); err != nil {
+			wd.l(ctx).Warningf("unable to convert to webhook delivery: %s", err)
+			continue
+		}
+
+		d.ID = doc.Ref.ID
+		deliveries = append(deliveries, &d)
+	}
+
+	return deliveries, nil
+}
@@ -58,6 +58,35 @@ func (_m *WebhookSubscriptionDAL) Delete(ctx context.Context, subscriptionID str
 	return r0
 }
 
+// Get provides a mock function with given fields: ctx, subscriptionID
+func (_m *WebhookSubscriptionDAL) Get(ctx context.Context, subscriptionID string) (*domain.WebhookSubscription, error) {
+	ret := _m.Called(ctx, subscriptionID)
+
+	var r0 *domain.WebhookSubscription
+
+	var r1 error
+
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.WebhookSubscription, error)); ok {
+		return rf(ctx, subscriptionID)
+	}
+
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.WebhookSubscription); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetForDispatch provides a mock function with given fields: ctx, customer, itemID, event
 func (_m *WebhookSubscriptionDAL) GetForDispatch(ctx context.Context, customer *firestore.DocumentRef, itemID string, event domain.EventType) ([]*domain.WebhookSubscription, error) {
 	ret := _m.Called(ctx, customer, itemID, event)
@@ -87,6 +116,33 @@ func (_m *WebhookSubscriptionDAL) GetForDispatch(ctx context.Context, customer *
 	return r0, r1
 }
 
+// SetSecretIfMissing provides a mock function with given fields: ctx, subscriptionID, secret
+func (_m *WebhookSubscriptionDAL) SetSecretIfMissing(ctx context.Context, subscriptionID string, secret string) (string, error) {
+	ret := _m.Called(ctx, subscriptionID, secret)
+
+	var r0 string
+
+	var r1 error
+
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, subscriptionID, secret)
+	}
+
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, subscriptionID, secret)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, subscriptionID, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewWebhookSubscriptionDAL interface {
 	mock.TestingT
 	Cleanup(func())
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	doitFirestore "github.com/doitintl/firestore"
 	firestoreIface "github.com/doitintl/firestore/iface"
@@ -15,10 +17,14 @@ import (
 
 const webhookSubscriptionCollection = "integrations/zapier/webhooks"
 
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
 //go:generate mockery --name WebhookSubscriptionDAL --output=./mocks
 type WebhookSubscriptionDAL interface {
 	Create(ctx context.Context, subscription *domain.WebhookSubscription) (string, error)
+	Get(ctx context.Context, subscriptionID string) (*domain.WebhookSubscription, error)
 	Delete(ctx context.Context, subscriptionID string) error
+	SetSecretIfMissing(ctx context.Context, subscriptionID string, secret string) (string, error)
 	GetForDispatch(
 		ctx context.Context,
 		customer *firestore.DocumentRef,
@@ -80,6 +86,39 @@ func (ws *WebhookSubscriptionFirestore) Create(
 	return ref.ID, nil
 }
 
+// Get returns a webhook subscription from firestore
+func (ws *WebhookSubscriptionFirestore) Get(
+	ctx context.Context,
+	subscriptionID string,
+) (*domain.WebhookSubscription, error) {
+	if subscriptionID == "" {
+		return nil, errors.New("invalid subscription ID")
+	}
+
+	doc := ws.
+		firestoreClientFn(ctx).
+		Collection(webhookSubscriptionCollection).
+		Doc(subscriptionID)
+
+	snap, err := ws.dh.Get(ctx, doc)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrSubscriptionNotFound
+		}
+
+		return nil, err
+	}
+
+	var s domain.WebhookSubscription
+	if err := snap.DataTo(&s); err != nil {
+		return nil, err
+	}
+
+	s.ID = snap.ID()
+
+	return &s, nil
+}
+
 // Delete deletes a webhook subscription from firestore
 func (ws *WebhookSubscriptionFirestore) Delete(
 	ctx context.Context,
@@ -98,6 +137,56 @@ func (ws *WebhookSubscriptionFirestore) Delete(
 	return err
 }
 
+// SetSecretIfMissing sets the secret of a subscription that was created without one and returns the
+// secret the subscription is left with, so concurrent deliveries agree on the secret they sign with
+func (ws *WebhookSubscriptionFirestore) SetSecretIfMissing(
+	ctx context.Context,
+	subscriptionID string,
+	secret string,
+) (string, error) {
+	if subscriptionID == "" {
+		return "", errors.New("invalid subscription ID")
+	}
+
+	fs := ws.firestoreClientFn(ctx)
+	doc := fs.Collection(webhookSubscriptionCollection).Doc(subscriptionID)
+
+	var storedSecret string
+
+	err := fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snap, err := tx.Get(doc)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrSubscriptionNotFound
+			}
+
+			return err
+		}
+
+		var s domain.WebhookSubscription
+		if err := snap.DataTo(&s); err != nil {
+			return err
+		}
+
+		if s.Secret != "" {
+			storedSecret = s.Secret
+			return nil
+		}
+
+		storedSecret = secret
+
+		return tx.Update(doc, []firestore.Update{
+			{Path: "secret", Value: secret},
+			{Path: "timeModified", Value: firestore.ServerTimestamp},
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return storedSecret, nil
+}
+
 func (ws *WebhookSubscriptionFirestore) GetForDispatch(
 	ctx context.Context,
 	customer *firestore.DocumentRef,
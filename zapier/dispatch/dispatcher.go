@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -27,22 +28,32 @@ type Dispatcher interface {
 	Dispatch(ctx context.Context, data any, customer *firestore.DocumentRef, itemID string, event domain.EventType) error
 }
 
+//go:generate mockery --name DeliveryDispatcher --output=./mocks
+type DeliveryDispatcher interface {
+	RetryDelivery(ctx context.Context, deliveryID string) error
+	Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+}
+
 type EventDispatcher struct {
-	dal dal.WebhookSubscriptionDAL
-	c   *http.Client
-	l   logger.Provider
+	dal         dal.WebhookSubscriptionDAL
+	deliveryDAL dal.WebhookDeliveryDAL
+	queue       RetryQueue
+	c           *http.Client
+	l           logger.Provider
 }
 
-func NewEventDispatcher(logger logger.Provider, fun connection.FirestoreFromContextFun) *EventDispatcher {
+func NewEventDispatcher(logger logger.Provider, conn *connection.Connection) *EventDispatcher {
 	return &EventDispatcher{
-		dal: dal.NewWebhookSubscriptionsFirestoreWithClient(logger, fun),
-		c:   &http.Client{Timeout: 10 * time.Second},
-		l:   logger,
+		dal:         dal.NewWebhookSubscriptionsFirestoreWithClient(logger, conn.Firestore),
+		deliveryDAL: dal.NewWebhookDeliveryFirestoreWithClient(logger, conn.Firestore),
+		queue:       NewCloudTasksRetryQueue(conn.CloudTaskClient),
+		c:           &http.Client{Timeout: 10 * time.Second},
+		l:           logger,
 	}
 }
 
 // Dispatch dispatches an event, checking whether there is a matching webhook subscription, then sends the data to the
-// matching target URLs
+// matching target URLs. Every delivery is recorded, and failed deliveries are retried with an exponential backoff
 func (e *EventDispatcher) Dispatch(
 	ctx context.Context,
 	data any,
@@ -78,48 +89,192 @@ func (e *EventDispatcher) Dispatch(
 	return nil
 }
 
-// dispatchToSubscription handles the errors that are returned from sendToTarget
+// RetryDelivery attempts a delivery that is scheduled for a retry. Deliveries that are no longer
+// waiting for a retry are skipped, so a task that runs more than once does not send the event twice
+func (e *EventDispatcher) RetryDelivery(ctx context.Context, deliveryID string) error {
+	d, err := e.deliveryDAL.Get(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	if d.Status != domain.DeliveryStatusRetrying {
+		e.l(ctx).Infof("skipping retry of delivery %s with status %s", d.ID, d.Status)
+		return nil
+	}
+
+	sub, err := e.dal.Get(ctx, d.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, dal.ErrSubscriptionNotFound) {
+			d.Status = domain.DeliveryStatusFailed
+			d.Error = err.Error()
+			d.TimeNextAttempt = nil
+
+			return e.deliveryDAL.Update(ctx, d)
+		}
+
+		return err
+	}
+
+	if err := e.deliver(ctx, d, sub, true); err != nil {
+		e.l(ctx).Warningf("error when retrying delivery %s: %s", d.ID, err)
+	}
+
+	return nil
+}
+
+// Redeliver sends a recorded delivery again. Manual redeliveries are attempted once and are
+// not retried automatically when they fail
+func (e *EventDispatcher) Redeliver(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	d, err := e.deliveryDAL.Get(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := e.dal.Get(ctx, d.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.deliver(ctx, d, sub, false); err != nil {
+		e.l(ctx).Warningf("error when redelivering delivery %s: %s", d.ID, err)
+	}
+
+	return d, nil
+}
+
+// dispatchToSubscription records a new delivery of the data to the subscription and attempts it
 func (e *EventDispatcher) dispatchToSubscription(ctx context.Context, data []byte, sub *domain.WebhookSubscription) error {
-	if err := e.sendToTarget(ctx, data, sub.TargetURL); err != nil {
-		switch err {
-		case errZapGone:
-			err := e.dal.Delete(ctx, sub.ID)
-			if err != nil {
-				return fmt.Errorf("there was an error deleting subscription")
+	d := &domain.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		Customer:       sub.Customer,
+		EventType:      sub.EventType,
+		ItemID:         sub.ItemID,
+		TargetURL:      sub.TargetURL,
+		Payload:        string(data),
+		Status:         domain.DeliveryStatusPending,
+		TimeCreated:    time.Now().UTC(),
+	}
+
+	id, err := e.deliveryDAL.Create(ctx, d)
+	if err != nil {
+		return fmt.Errorf("there was an error recording delivery: %w", err)
+	}
+
+	d.ID = id
+
+	return e.deliver(ctx, d, sub, true)
+}
+
+// deliver attempts the delivery, records the attempt result and handles the errors that are returned
+// from sendToTarget. When retry is set, failed attempts are scheduled again until the attempts run out
+func (e *EventDispatcher) deliver(ctx context.Context, d *domain.WebhookDelivery, sub *domain.WebhookSubscription, retry bool) error {
+	if sub.Secret == "" {
+		if err := e.setSecret(ctx, sub); err != nil {
+			e.l(ctx).Errorf("failed to set secret of subscription %s: %s", sub.ID, err)
+		}
+	}
+
+	start := time.Now().UTC()
+	statusCode, sendErr := e.sendToTarget(ctx, d, sub.Secret)
+
+	d.Attempts++
+	d.ResponseCode = statusCode
+	d.LatencyMs = time.Since(start).Milliseconds()
+	d.TimeLastAttempt = &start
+	d.TimeNextAttempt = nil
+	d.Error = ""
+
+	var err error
+
+	switch sendErr {
+	case nil:
+		d.Status = domain.DeliveryStatusSucceeded
+	case errZapGone:
+		d.Status = domain.DeliveryStatusFailed
+		d.Error = sendErr.Error()
+
+		if dErr := e.dal.Delete(ctx, sub.ID); dErr != nil {
+			err = fmt.Errorf("there was an error deleting subscription")
+		}
+	default:
+		if sendErr == errRateLimitExceeded {
+			err = fmt.Errorf("rate limit exceeded for subscription")
+		} else {
+			err = sendErr
+		}
+
+		d.Status = domain.DeliveryStatusFailed
+		d.Error = err.Error()
+
+		if retry && d.Attempts < maxDeliveryAttempts {
+			next := start.Add(retryBackoff(d.Attempts))
+
+			if qErr := e.queue.Enqueue(ctx, d.ID, next); qErr != nil {
+				e.l(ctx).Errorf("failed to schedule retry of delivery %s: %s", d.ID, qErr)
+			} else {
+				d.Status = domain.DeliveryStatusRetrying
+				d.TimeNextAttempt = &next
 			}
-		case errRateLimitExceeded:
-			return fmt.Errorf("rate limit exceeded for subscription")
-		default:
-			return err
 		}
 	}
 
-	return nil
+	if uErr := e.deliveryDAL.Update(ctx, d); uErr != nil {
+		e.l(ctx).Errorf("failed to record attempt of delivery %s: %s", d.ID, uErr)
+	}
+
+	return err
 }
 
-// sendToTarget attempts to send the data to the targetURL and returns errors depending on the responses status code
-func (e *EventDispatcher) sendToTarget(ctx context.Context, data []byte, targetURL string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(data))
+// setSecret gives a secret to a subscription that was created before the requests were signed
+func (e *EventDispatcher) setSecret(ctx context.Context, sub *domain.WebhookSubscription) error {
+	secret, err := NewSecret()
+	if err != nil {
+		return err
+	}
+
+	secret, err = e.dal.SetSecretIfMissing(ctx, sub.ID, secret)
 	if err != nil {
 		return err
 	}
 
+	sub.Secret = secret
+
+	return nil
+}
+
+// sendToTarget attempts to send the delivery payload to its target URL and returns errors depending on the
+// responses status code. Requests are signed when the subscription has a secret
+func (e *EventDispatcher) sendToTarget(ctx context.Context, d *domain.WebhookDelivery, secret string) (int, error) {
+	data := []byte(d.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TargetURL, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryIDHeader, d.ID)
+
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(SignatureTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(secret, timestamp, data))
+	}
 
 	resp, err := e.c.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
 	switch {
 	case resp.StatusCode == http.StatusOK:
-		return nil
+		return resp.StatusCode, nil
 	case resp.StatusCode == http.StatusGone: // Zap removed from zapier
-		return errZapGone
+		return resp.StatusCode, errZapGone
 	case resp.StatusCode == http.StatusTooManyRequests:
-		return errRateLimitExceeded
+		return resp.StatusCode, errRateLimitExceeded
 	default:
-		return fmt.Errorf("webhook request failed with status code %d", resp.StatusCode)
+		return resp.StatusCode, fmt.Errorf("webhook request failed with status code %d", resp.StatusCode)
 	}
 }
@@ -3,8 +3,10 @@ package dispatch
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -14,12 +16,14 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	loggerMocks "github.com/doitintl/hello/scheduled-tasks/logger/mocks"
 	mocks "github.com/doitintl/hello/scheduled-tasks/zapier/dal/mocks"
+	dispatchMocks "github.com/doitintl/hello/scheduled-tasks/zapier/dispatch/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
 const (
 	customerID = "customer-id"
 	entityID   = "entity-id"
+	deliveryID = "delivery-id"
 )
 
 func TestEventDispatcher(t *testing.T) {
@@ -36,7 +40,7 @@ func TestEventDispatcher(t *testing.T) {
 		args    args
 		handler http.HandlerFunc
 		errStr  string
-		on      func(*mocks.WebhookSubscriptionDAL, *loggerMocks.ILogger, string)
+		on      func(*mocks.WebhookSubscriptionDAL, *mocks.WebhookDeliveryDAL, *dispatchMocks.RetryQueue, *loggerMocks.ILogger, string)
 	}{
 		{
 			name: "Successfully dispatch all events",
@@ -52,7 +56,7 @@ func TestEventDispatcher(t *testing.T) {
 				_, _ = w.Write([]byte(http.StatusText(http.StatusOK)))
 			},
 			errStr: "",
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					[]*domain.WebhookSubscription{
 						{TargetURL: baseURL + "/url-1"},
@@ -61,13 +65,17 @@ func TestEventDispatcher(t *testing.T) {
 					},
 					nil,
 				)
+				wdd.On("Create", mock.Anything, mock.Anything).Return(deliveryID, nil).Times(3)
+				wdd.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+					return d.Status == domain.DeliveryStatusSucceeded && d.Attempts == 1 && d.ResponseCode == http.StatusOK
+				})).Return(nil).Times(3)
 			},
 		},
 		{
 			name:   "GetForDispatch error",
 			args:   args{},
 			errStr: "dal error",
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					nil,
 					errors.New("dal error"),
@@ -78,7 +86,7 @@ func TestEventDispatcher(t *testing.T) {
 			name:   "Invalid JSON marshal error",
 			args:   args{data: make(chan string)},
 			errStr: "json: unsupported type: chan string",
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					[]*domain.WebhookSubscription{
 						{TargetURL: baseURL + "/url-1"},
@@ -95,7 +103,7 @@ func TestEventDispatcher(t *testing.T) {
 				w.WriteHeader(http.StatusGone)
 				_, _ = w.Write([]byte(http.StatusText(http.StatusGone)))
 			},
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					[]*domain.WebhookSubscription{
 						{TargetURL: baseURL + "/url-1"},
@@ -103,6 +111,10 @@ func TestEventDispatcher(t *testing.T) {
 					nil,
 				)
 				wsd.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+				wdd.On("Create", mock.Anything, mock.Anything).Return(deliveryID, nil).Once()
+				wdd.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+					return d.Status == domain.DeliveryStatusFailed && d.ResponseCode == http.StatusGone
+				})).Return(nil).Once()
 			},
 		},
 		{
@@ -113,7 +125,7 @@ func TestEventDispatcher(t *testing.T) {
 				w.WriteHeader(http.StatusGone)
 				_, _ = w.Write([]byte(http.StatusText(http.StatusGone)))
 			},
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					[]*domain.WebhookSubscription{
 						{TargetURL: baseURL + "/url-1"},
@@ -121,6 +133,10 @@ func TestEventDispatcher(t *testing.T) {
 					nil,
 				)
 				wsd.On("Delete", mock.Anything, mock.Anything).Return(errors.New("dal error")).Once()
+				wdd.On("Create", mock.Anything, mock.Anything).Return(deliveryID, nil).Once()
+				wdd.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+					return d.Status == domain.DeliveryStatusFailed && d.ResponseCode == http.StatusGone
+				})).Return(nil).Once()
 				log.On("Warningf", mock.Anything, mock.Anything, mock.Anything).Return()
 			},
 		},
@@ -132,13 +148,18 @@ func TestEventDispatcher(t *testing.T) {
 				w.WriteHeader(http.StatusTooManyRequests)
 				_, _ = w.Write([]byte(http.StatusText(http.StatusTooManyRequests)))
 			},
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					[]*domain.WebhookSubscription{
 						{TargetURL: baseURL + "/url-1"},
 					},
 					nil,
 				)
+				wdd.On("Create", mock.Anything, mock.Anything).Return(deliveryID, nil).Once()
+				q.On("Enqueue", mock.Anything, deliveryID, mock.Anything).Return(nil).Once()
+				wdd.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+					return d.Status == domain.DeliveryStatusRetrying && d.ResponseCode == http.StatusTooManyRequests && d.TimeNextAttempt != nil
+				})).Return(nil).Once()
 				log.On("Warningf", mock.Anything, mock.Anything, errors.New("rate limit exceeded for subscription")).
 					Return()
 			},
@@ -151,13 +172,18 @@ func TestEventDispatcher(t *testing.T) {
 				w.WriteHeader(http.StatusNotFound)
 				_, _ = w.Write([]byte(http.StatusText(http.StatusNotFound)))
 			},
-			on: func(wsd *mocks.WebhookSubscriptionDAL, log *loggerMocks.ILogger, baseURL string) {
+			on: func(wsd *mocks.WebhookSubscriptionDAL, wdd *mocks.WebhookDeliveryDAL, q *dispatchMocks.RetryQueue, log *loggerMocks.ILogger, baseURL string) {
 				wsd.On("GetForDispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 					[]*domain.WebhookSubscription{
 						{TargetURL: baseURL + "/url-1"},
 					},
 					nil,
 				)
+				wdd.On("Create", mock.Anything, mock.Anything).Return(deliveryID, nil).Once()
+				q.On("Enqueue", mock.Anything, deliveryID, mock.Anything).Return(nil).Once()
+				wdd.On("Update", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+					return d.Status == domain.DeliveryStatusRetrying && d.ResponseCode == http.StatusNotFound && d.TimeNextAttempt != nil
+				})).Return(nil).Once()
 				log.On("Warningf", mock.Anything, mock.Anything, errors.New("webhook request failed with status code 404")).Return()
 			},
 		},
@@ -169,15 +195,21 @@ func TestEventDispatcher(t *testing.T) {
 			defer server.Close()
 
 			mockDal := &mocks.WebhookSubscriptionDAL{}
+			mockDeliveryDal := &mocks.WebhookDeliveryDAL{}
+			mockQueue := &dispatchMocks.RetryQueue{}
 			loggerMock := &loggerMocks.ILogger{}
 
 			if test.on != nil {
-				test.on(mockDal, loggerMock, server.URL)
+				test.on(mockDal, mockDeliveryDal, mockQueue, loggerMock, server.URL)
 			}
 
+			mockDal.On("SetSecretIfMissing", mock.Anything, mock.Anything, mock.Anything).Return("secret", nil).Maybe()
+
 			dispatcher := &EventDispatcher{
-				dal: mockDal,
-				c:   server.Client(),
+				dal:         mockDal,
+				deliveryDAL: mockDeliveryDal,
+				queue:       mockQueue,
+				c:           server.Client(),
 				l: func(ctx context.Context) logger.ILogger {
 					return loggerMock
 				},
@@ -190,6 +222,196 @@ func TestEventDispatcher(t *testing.T) {
 			if test.errStr != "" {
 				assert.ErrorContains(t, err, test.errStr)
 			}
+
+			mockDeliveryDal.AssertExpectations(t)
+			mockQueue.AssertExpectations(t)
 		})
 	}
 }
+
+func TestEventDispatcher_SignsRequests(t *testing.T) {
+	const secret = "secret"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+		assert.NoError(t, err)
+
+		assert.Equal(t, Sign(secret, timestamp, body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, deliveryID, r.Header.Get(DeliveryIDHeader))
+	}))
+	defer server.Close()
+
+	deliveryDal := mocks.NewWebhookDeliveryDAL(t)
+	deliveryDal.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+	dispatcher := &EventDispatcher{
+		deliveryDAL: deliveryDal,
+		c:           server.Client(),
+		l:           logger.FromContext,
+	}
+
+	d := &domain.WebhookDelivery{ID: deliveryID, TargetURL: server.URL, Payload: `{"id":"1"}`}
+
+	err := dispatcher.deliver(context.Background(), d, &domain.WebhookSubscription{Secret: secret}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusSucceeded, d.Status)
+}
+
+func TestEventDispatcher_SetsSecretOfSubscriptionWithoutSecret(t *testing.T) {
+	const storedSecret = "stored-secret"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+		assert.NoError(t, err)
+
+		assert.Equal(t, Sign(storedSecret, timestamp, body), r.Header.Get(SignatureHeader))
+	}))
+	defer server.Close()
+
+	subscriptionDal := mocks.NewWebhookSubscriptionDAL(t)
+	subscriptionDal.On("SetSecretIfMissing", mock.Anything, "subscription-id", mock.MatchedBy(func(secret string) bool {
+		return len(secret) == 2*secretLength
+	})).Return(storedSecret, nil).Once()
+
+	deliveryDal := mocks.NewWebhookDeliveryDAL(t)
+	deliveryDal.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+	dispatcher := &EventDispatcher{
+		dal:         subscriptionDal,
+		deliveryDAL: deliveryDal,
+		c:           server.Client(),
+		l:           logger.FromContext,
+	}
+
+	sub := &domain.WebhookSubscription{ID: "subscription-id"}
+	d := &domain.WebhookDelivery{ID: deliveryID, TargetURL: server.URL, Payload: `{"id":"1"}`}
+
+	err := dispatcher.deliver(context.Background(), d, sub, true)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusSucceeded, d.Status)
+	assert.Equal(t, storedSecret, sub.Secret)
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1700000000, []byte(`{}`))
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.Len(t, signature, 7+64)
+	assert.Equal(t, signature, Sign("secret", 1700000000, []byte(`{}`)))
+	assert.NotEqual(t, signature, Sign("other-secret", 1700000000, []byte(`{}`)))
+	assert.NotEqual(t, signature, Sign("secret", 1700000001, []byte(`{}`)))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 5, want: 8 * time.Minute},
+		{attempts: 8, want: time.Hour},
+		{attempts: 20, want: time.Hour},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, retryBackoff(test.attempts), "attempts %d", test.attempts)
+	}
+}
+
+func TestEventDispatcher_RetryDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("skip delivery that is not retrying", func(t *testing.T) {
+		deliveryDal := mocks.NewWebhookDeliveryDAL(t)
+		deliveryDal.On("Get", ctx, deliveryID).Return(&domain.WebhookDelivery{
+			ID:     deliveryID,
+			Status: domain.DeliveryStatusSucceeded,
+		}, nil)
+
+		dispatcher := &EventDispatcher{deliveryDAL: deliveryDal, l: logger.FromContext}
+
+		assert.NoError(t, dispatcher.RetryDelivery(ctx, deliveryID))
+	})
+
+	t.Run("fail delivery after the last attempt", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		deliveryDal := mocks.NewWebhookDeliveryDAL(t)
+		subscriptionDal := mocks.NewWebhookSubscriptionDAL(t)
+		queue := dispatchMocks.NewRetryQueue(t)
+
+		deliveryDal.On("Get", ctx, deliveryID).Return(&domain.WebhookDelivery{
+			ID:             deliveryID,
+			SubscriptionID: "subscription-id",
+			TargetURL:      server.URL,
+			Status:         domain.DeliveryStatusRetrying,
+			Attempts:       maxDeliveryAttempts - 1,
+		}, nil)
+		subscriptionDal.On("Get", ctx, "subscription-id").Return(&domain.WebhookSubscription{ID: "subscription-id"}, nil)
+		subscriptionDal.On("SetSecretIfMissing", ctx, "subscription-id", mock.Anything).Return("secret", nil)
+		deliveryDal.On("Update", ctx, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryStatusFailed &&
+				d.Attempts == maxDeliveryAttempts &&
+				d.ResponseCode == http.StatusInternalServerError &&
+				d.TimeNextAttempt == nil
+		})).Return(nil)
+
+		dispatcher := &EventDispatcher{
+			dal:         subscriptionDal,
+			deliveryDAL: deliveryDal,
+			queue:       queue,
+			c:           server.Client(),
+			l:           logger.FromContext,
+		}
+
+		assert.NoError(t, dispatcher.RetryDelivery(ctx, deliveryID))
+	})
+}
+
+func TestEventDispatcher_Redeliver(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	deliveryDal := mocks.NewWebhookDeliveryDAL(t)
+	subscriptionDal := mocks.NewWebhookSubscriptionDAL(t)
+	queue := dispatchMocks.NewRetryQueue(t)
+
+	deliveryDal.On("Get", ctx, deliveryID).Return(&domain.WebhookDelivery{
+		ID:             deliveryID,
+		SubscriptionID: "subscription-id",
+		TargetURL:      server.URL,
+		Status:         domain.DeliveryStatusFailed,
+		Attempts:       maxDeliveryAttempts,
+	}, nil)
+	subscriptionDal.On("Get", ctx, "subscription-id").Return(&domain.WebhookSubscription{ID: "subscription-id"}, nil)
+	subscriptionDal.On("SetSecretIfMissing", ctx, "subscription-id", mock.Anything).Return("secret", nil)
+	deliveryDal.On("Update", ctx, mock.Anything).Return(nil)
+
+	dispatcher := &EventDispatcher{
+		dal:         subscriptionDal,
+		deliveryDAL: deliveryDal,
+		queue:       queue,
+		c:           server.Client(),
+		l:           logger.FromContext,
+	}
+
+	d, err := dispatcher.Redeliver(ctx, deliveryID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusFailed, d.Status)
+	assert.Equal(t, maxDeliveryAttempts+1, d.Attempts)
+	assert.Equal(t, http.StatusBadGateway, d.ResponseCode)
+}
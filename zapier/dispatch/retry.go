@@ -0,0 +1,64 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
+
+	"github.com/doitintl/cloudtasks/iface"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+)
+
+const (
+	// maxDeliveryAttempts is the number of attempts after which a delivery is marked as failed
+	maxDeliveryAttempts = 6
+
+	initialRetryBackoff = 30 * time.Second
+	maxRetryBackoff     = time.Hour
+
+	retryDeliveryPathTemplate = "/tasks/zapier/deliveries/%s/retry"
+)
+
+//go:generate mockery --name RetryQueue --output=./mocks
+type RetryQueue interface {
+	Enqueue(ctx context.Context, deliveryID string, at time.Time) error
+}
+
+// CloudTasksRetryQueue schedules delivery retries as cloud tasks
+type CloudTasksRetryQueue struct {
+	client iface.CloudTaskClient
+}
+
+func NewCloudTasksRetryQueue(client iface.CloudTaskClient) *CloudTasksRetryQueue {
+	return &CloudTasksRetryQueue{client: client}
+}
+
+// Enqueue creates a task that retries the delivery at the given time
+func (q *CloudTasksRetryQueue) Enqueue(ctx context.Context, deliveryID string, at time.Time) error {
+	config := common.CloudTaskConfig{
+		Method:       cloudtaskspb.HttpMethod_POST,
+		Path:         fmt.Sprintf(retryDeliveryPathTemplate, deliveryID),
+		Queue:        common.TaskQueueZapierWebhooks,
+		ScheduleTime: common.TimeToTimestamp(at),
+	}
+
+	_, err := q.client.CreateTask(ctx, config.Config(nil))
+
+	return err
+}
+
+// retryBackoff returns the delay before the next attempt, doubling after every attempt
+func retryBackoff(attempts int) time.Duration {
+	backoff := initialRetryBackoff
+
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+
+	return backoff
+}
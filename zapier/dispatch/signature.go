@@ -0,0 +1,44 @@
+package dispatch
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	DeliveryIDHeader         = "X-Delivery-ID"
+
+	signaturePrefix = "sha256="
+	secretLength    = 32
+)
+
+// NewSecret returns a random secret used to sign the requests sent to a webhook subscription
+func NewSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature of a webhook request body, an HMAC-SHA256 of "<timestamp>.<body>" keyed
+// by the subscription secret. Receivers verify it by computing the same value from the
+// X-Signature-Timestamp header and the raw body.
+//
+// Subscriptions created before the requests were signed have no secret. They are given one on their
+// next delivery and their requests are signed from then on. The secret is only returned when subscribing,
+// so receivers of those subscriptions that verify the signature have to subscribe again to get it.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
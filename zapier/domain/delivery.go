@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusRetrying  DeliveryStatus = "retrying"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery records the delivery of an event to a webhook subscription target URL.
+type WebhookDelivery struct {
+	ID              string                 `firestore:"-" json:"id"`
+	SubscriptionID  string                 `firestore:"subscriptionId" json:"subscriptionId"`
+	Customer        *firestore.DocumentRef `firestore:"customer" json:"-"`
+	EventType       EventType              `firestore:"eventType" json:"eventType"`
+	ItemID          string                 `firestore:"itemId" json:"itemId"`
+	TargetURL       string                 `firestore:"targetUrl" json:"targetUrl"`
+	Payload         string                 `firestore:"payload" json:"payload"`
+	Status          DeliveryStatus         `firestore:"status" json:"status"`
+	Attempts        int                    `firestore:"attempts" json:"attempts"`
+	ResponseCode    int                    `firestore:"responseCode" json:"responseCode"`
+	LatencyMs       int64                  `firestore:"latencyMs" json:"latencyMs"`
+	Error           string                 `firestore:"error" json:"error,omitempty"`
+	TimeCreated     time.Time              `firestore:"timeCreated" json:"timeCreated"`
+	TimeLastAttempt *time.Time             `firestore:"timeLastAttempt" json:"timeLastAttempt"`
+	TimeNextAttempt *time.Time             `firestore:"timeNextAttempt" json:"timeNextAttempt"`
+}
@@ -9,8 +9,12 @@ import (
 type EventType string
 
 const (
-	AlertConditionSatisfied EventType = "alertConditionSatisfied"
-	BudgetThresholdAchieved EventType = "budgetThresholdAchieved"
+	AlertConditionSatisfied     EventType = "alertConditionSatisfied"
+	BudgetThresholdAchieved     EventType = "budgetThresholdAchieved"
+	BudgetForecastedDateChanged EventType = "budgetForecastedDateChanged"
+	CostAnomalyDetected         EventType = "costAnomalyDetected"
+	InvoiceIssued               EventType = "invoiceIssued"
+	ReportScheduleDelivered     EventType = "reportScheduleDelivered"
 )
 
 type WebhookSubscription struct {
@@ -20,6 +24,7 @@ type WebhookSubscription struct {
 	EventType    EventType              `firestore:"eventType"`
 	ItemID       string                 `firestore:"itemId"`
 	TargetURL    string                 `firestore:"targetUrl"`
+	Secret       string                 `firestore:"secret"`
 	TimeCreated  time.Time              `firestore:"timeCreated,serverTimestamp"`
 	TimeModified time.Time              `firestore:"timeModified,serverTimestamp"`
 }
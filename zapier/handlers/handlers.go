@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,20 +11,24 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dal"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/service"
 )
 
 type WebhookHandler struct {
-	l   logger.Provider
-	svc service.WebhookSubscriptionService
+	l          logger.Provider
+	svc        service.WebhookSubscriptionService
+	dispatcher dispatch.DeliveryDispatcher
 }
 
 func NewWebhookHandler(l logger.Provider, conn *connection.Connection) *WebhookHandler {
 	svc := service.NewWebhookSubscriptionService(l, conn)
 
 	return &WebhookHandler{
-		l:   l,
-		svc: svc,
+		l:          l,
+		svc:        svc,
+		dispatcher: dispatch.NewEventDispatcher(l, conn),
 	}
 }
 
@@ -39,7 +44,7 @@ func (ws *WebhookHandler) Create(ctx *gin.Context) error {
 
 	resp, err := ws.svc.CreateSubscription(ctx, &req)
 	if err != nil {
-		return web.NewRequestError(err, http.StatusInternalServerError)
+		return web.NewRequestError(err, errorStatus(err))
 	}
 
 	return web.Respond(ctx, resp, http.StatusCreated)
@@ -57,6 +62,75 @@ func (ws *WebhookHandler) Delete(ctx *gin.Context) error {
 
 	err := ws.svc.DeleteSubscription(ctx, &req)
 	if err != nil {
+		return web.NewRequestError(err, errorStatus(err))
+	}
+
+	return web.Respond(ctx, nil, http.StatusOK)
+}
+
+func (ws *WebhookHandler) ListDeliveries(ctx *gin.Context) error {
+	var req service.ListDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	req.CustomerID = ctx.GetString(auth.CtxKeyVerifiedCustomerID)
+	req.UserEmail = ctx.GetString(common.CtxKeys.Email)
+	req.UserID = ctx.GetString(common.CtxKeys.UserID)
+
+	deliveries, err := ws.svc.ListDeliveries(ctx, &req)
+	if err != nil {
+		return web.NewRequestError(err, errorStatus(err))
+	}
+
+	return web.Respond(ctx, deliveries, http.StatusOK)
+}
+
+func (ws *WebhookHandler) Redeliver(ctx *gin.Context) error {
+	req := service.RedeliverRequest{
+		CustomerID: ctx.GetString(auth.CtxKeyVerifiedCustomerID),
+		UserEmail:  ctx.GetString(common.CtxKeys.Email),
+		UserID:     ctx.GetString(common.CtxKeys.UserID),
+		DeliveryID: ctx.Param("deliveryID"),
+	}
+
+	delivery, err := ws.svc.RedeliverDelivery(ctx, &req)
+	if err != nil {
+		return web.NewRequestError(err, errorStatus(err))
+	}
+
+	return web.Respond(ctx, delivery, http.StatusOK)
+}
+
+// errorStatus returns the status of the errors of the webhook subscription service, users that cannot
+// manage the webhooks of the customer are forbidden and missing subscriptions or deliveries are not found
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrUserNotInOrganization),
+		errors.Is(err, service.ErrUserNotAuthorized):
+		return http.StatusForbidden
+	case errors.Is(err, dal.ErrSubscriptionNotFound),
+		errors.Is(err, dal.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RetryDelivery is called by the cloud task scheduled for a failed delivery
+func (ws *WebhookHandler) RetryDelivery(ctx *gin.Context) error {
+	deliveryID := ctx.Param("deliveryID")
+	if deliveryID == "" {
+		return web.NewRequestError(errors.New("missing delivery id"), http.StatusBadRequest)
+	}
+
+	if err := ws.dispatcher.RetryDelivery(ctx, deliveryID); err != nil {
+		if errors.Is(err, dal.ErrDeliveryNotFound) {
+			ws.l(ctx).Warningf("delivery %s not found", deliveryID)
+			return web.Respond(ctx, nil, http.StatusOK)
+		}
+
 		return web.NewRequestError(err, http.StatusInternalServerError)
 	}
 
@@ -70,3 +144,19 @@ func (ws *WebhookHandler) GetAlertsMock(ctx *gin.Context) error {
 func (ws *WebhookHandler) GetBudgetsMock(ctx *gin.Context) error {
 	return web.Respond(ctx, ws.svc.GetBudgetsMock(), http.StatusOK)
 }
+
+func (ws *WebhookHandler) GetBudgetForecastsMock(ctx *gin.Context) error {
+	return web.Respond(ctx, ws.svc.GetBudgetForecastsMock(), http.StatusOK)
+}
+
+func (ws *WebhookHandler) GetAnomaliesMock(ctx *gin.Context) error {
+	return web.Respond(ctx, ws.svc.GetAnomaliesMock(), http.StatusOK)
+}
+
+func (ws *WebhookHandler) GetInvoicesMock(ctx *gin.Context) error {
+	return web.Respond(ctx, ws.svc.GetInvoicesMock(), http.StatusOK)
+}
+
+func (ws *WebhookHandler) GetReportDeliveriesMock(ctx *gin.Context) error {
+	return web.Respond(ctx, ws.svc.GetReportDeliveriesMock(), http.StatusOK)
+}
@@ -14,7 +14,10 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/doitintl/auth"
+	"github.com/doitintl/hello/scheduled-tasks/framework/web"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dal"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/service"
 	serviceMock "github.com/doitintl/hello/scheduled-tasks/zapier/service/mocks"
 )
@@ -127,3 +130,85 @@ func TestWebhooks_CreateWebhookSubscription(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhooks_Redeliver(t *testing.T) {
+	ctx := GetContext()
+	ctx.Params = gin.Params{{Key: "deliveryID", Value: "delivery-id"}}
+
+	tests := []struct {
+		name       string
+		fields     fields
+		on         func(*fields)
+		wantStatus int
+	}{
+		{
+			name: "happy path",
+			on: func(f *fields) {
+				f.service.
+					On(
+						"RedeliverDelivery",
+						ctx,
+						&service.RedeliverRequest{
+							CustomerID: customerID,
+							UserID:     userID,
+							UserEmail:  email,
+							DeliveryID: "delivery-id",
+						},
+					).
+					Return(&domain.WebhookDelivery{ID: "delivery-id"}, nil).
+					Once()
+			},
+		},
+		{
+			name:       "delivery not found",
+			wantStatus: http.StatusNotFound,
+			on: func(f *fields) {
+				f.service.
+					On("RedeliverDelivery", ctx, mock.AnythingOfType("*service.RedeliverRequest")).
+					Return(nil, dal.ErrDeliveryNotFound).
+					Once()
+			},
+		},
+		{
+			name:       "user is not authorized",
+			wantStatus: http.StatusForbidden,
+			on: func(f *fields) {
+				f.service.
+					On("RedeliverDelivery", ctx, mock.AnythingOfType("*service.RedeliverRequest")).
+					Return(nil, service.ErrUserNotAuthorized).
+					Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fields = fields{
+				loggerProvider: logger.FromContext,
+				service:        &serviceMock.WebhookSubscriptionService{},
+			}
+
+			h := &WebhookHandler{
+				l:   tt.fields.loggerProvider,
+				svc: tt.fields.service,
+			}
+
+			if tt.on != nil {
+				tt.on(&tt.fields)
+			}
+
+			err := h.Redeliver(ctx)
+
+			if tt.wantStatus != 0 {
+				var webErr *web.Error
+				if assert.ErrorAs(t, err, &webErr) {
+					assert.Equal(t, tt.wantStatus, webErr.Status)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			tt.fields.service.AssertExpectations(t)
+		})
+	}
+}
@@ -3,13 +3,21 @@
 package mocks
 
 import (
+	api "github.com/doitintl/hello/scheduled-tasks/api"
+
 	alertsservice "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/service"
 	budgetsservice "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/service"
 
 	context "context"
 
+	invoices "github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
+
+	domain "github.com/doitintl/hello/scheduled-tasks/zapier/domain"
+
 	mock "github.com/stretchr/testify/mock"
 
+	schedule "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/schedule"
+
 	service "github.com/doitintl/hello/scheduled-tasks/zapier/service"
 )
 
@@ -71,6 +79,38 @@ func (_m *WebhookSubscriptionService) GetAlertsMock() []alertsservice.WebhookAle
 	return r0
 }
 
+// GetAnomaliesMock provides a mock function with given fields:
+func (_m *WebhookSubscriptionService) GetAnomaliesMock() []api.AnomalyItem {
+	ret := _m.Called()
+
+	var r0 []api.AnomalyItem
+	if rf, ok := ret.Get(0).(func() []api.AnomalyItem); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]api.AnomalyItem)
+		}
+	}
+
+	return r0
+}
+
+// GetBudgetForecastsMock provides a mock function with given fields:
+func (_m *WebhookSubscriptionService) GetBudgetForecastsMock() []budgetsservice.WebhookBudgetForecastedDate {
+	ret := _m.Called()
+
+	var r0 []budgetsservice.WebhookBudgetForecastedDate
+	if rf, ok := ret.Get(0).(func() []budgetsservice.WebhookBudgetForecastedDate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]budgetsservice.WebhookBudgetForecastedDate)
+		}
+	}
+
+	return r0
+}
+
 // GetBudgetsMock provides a mock function with given fields:
 func (_m *WebhookSubscriptionService) GetBudgetsMock() []budgetsservice.BudgetAPI {
 	ret := _m.Called()
@@ -87,6 +127,84 @@ func (_m *WebhookSubscriptionService) GetBudgetsMock() []budgetsservice.BudgetAP
 	return r0
 }
 
+// GetInvoicesMock provides a mock function with given fields:
+func (_m *WebhookSubscriptionService) GetInvoicesMock() []invoices.WebhookInvoice {
+	ret := _m.Called()
+
+	var r0 []invoices.WebhookInvoice
+	if rf, ok := ret.Get(0).(func() []invoices.WebhookInvoice); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]invoices.WebhookInvoice)
+		}
+	}
+
+	return r0
+}
+
+// GetReportDeliveriesMock provides a mock function with given fields:
+func (_m *WebhookSubscriptionService) GetReportDeliveriesMock() []schedule.WebhookReportDelivery {
+	ret := _m.Called()
+
+	var r0 []schedule.WebhookReportDelivery
+	if rf, ok := ret.Get(0).(func() []schedule.WebhookReportDelivery); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schedule.WebhookReportDelivery)
+		}
+	}
+
+	return r0
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, req
+func (_m *WebhookSubscriptionService) ListDeliveries(ctx context.Context, req *service.ListDeliveriesRequest) ([]*domain.WebhookDelivery, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 []*domain.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, *service.ListDeliveriesRequest) []*domain.WebhookDelivery); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *service.ListDeliveriesRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeliverDelivery provides a mock function with given fields: ctx, req
+func (_m *WebhookSubscriptionService) RedeliverDelivery(ctx context.Context, req *service.RedeliverRequest) (*domain.WebhookDelivery, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 *domain.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, *service.RedeliverRequest) *domain.WebhookDelivery); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *service.RedeliverRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewWebhookSubscriptionService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -6,30 +6,54 @@ import (
 	"time"
 
 	userDAL "github.com/doitintl/hello/scheduled-tasks/algolia/dal"
+	"github.com/doitintl/hello/scheduled-tasks/api"
 	alertsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal"
 	alertService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/service"
 	budgetsDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/dal"
 	budgetsService "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/service"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/collab"
+	reportDAL "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/schedule"
 	"github.com/doitintl/hello/scheduled-tasks/common"
+	"github.com/doitintl/hello/scheduled-tasks/dashboard/invoices"
+	entityDAL "github.com/doitintl/hello/scheduled-tasks/entity/dal"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/dal"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dispatch"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
+const (
+	maxDeliveriesLimit = 100
+)
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrUserNotInOrganization = errors.New("user does not belong to this organization")
+	ErrUserNotAuthorized     = errors.New("user is not authorized")
+)
+
 //go:generate mockery --name WebhookSubscriptionService --output=./mocks
 type WebhookSubscriptionService interface {
 	CreateSubscription(ctx context.Context, req *CreateWebhookRequest) (*CreateWebhookResponse, error)
 	DeleteSubscription(ctx context.Context, req *DeleteWebhookRequest) error
+	ListDeliveries(ctx context.Context, req *ListDeliveriesRequest) ([]*domain.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, req *RedeliverRequest) (*domain.WebhookDelivery, error)
 	GetAlertsMock() []alertService.WebhookAlertNotification
 	GetBudgetsMock() []budgetsService.BudgetAPI
+	GetBudgetForecastsMock() []budgetsService.WebhookBudgetForecastedDate
+	GetAnomaliesMock() []api.AnomalyItem
+	GetInvoicesMock() []invoices.WebhookInvoice
+	GetReportDeliveriesMock() []schedule.WebhookReportDelivery
 }
 
 type WebhookService struct {
 	loggerProvider logger.Provider
 	conn           *connection.Connection
 	dal            dal.WebhookSubscriptionDAL
+	deliveryDAL    dal.WebhookDeliveryDAL
+	dispatcher     dispatch.DeliveryDispatcher
 	userDAL        userDAL.UserDAL
 	validator      *EventValidator
 }
@@ -39,10 +63,14 @@ func NewWebhookSubscriptionService(log logger.Provider, conn *connection.Connect
 		loggerProvider: log,
 		conn:           conn,
 		dal:            dal.NewWebhookSubscriptionsFirestoreWithClient(log, conn.Firestore),
+		deliveryDAL:    dal.NewWebhookDeliveryFirestoreWithClient(log, conn.Firestore),
+		dispatcher:     dispatch.NewEventDispatcher(log, conn),
 		userDAL:        userDAL.NewUserFirestore(conn),
 		validator: NewEventValidator(
 			alertsDAL.NewAlertsFirestoreWithClient(conn.Firestore),
 			budgetsDAL.NewBudgetsFirestoreWithClient(conn.Firestore),
+			entityDAL.NewEntitiesFirestoreWithClient(conn.Firestore),
+			reportDAL.NewReportsFirestoreWithClient(conn.Firestore),
 		),
 	}
 }
@@ -51,28 +79,34 @@ func (s *WebhookService) CreateSubscription(ctx context.Context, req *CreateWebh
 	// TODO check for doit employees
 	user, err := s.userDAL.GetUser(ctx, req.UserID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if user.Customer.Ref == nil || user.Customer.Ref.ID != req.CustomerID {
-		return nil, errors.New("user does not belong to this organization")
+		return nil, ErrUserNotInOrganization
 	}
 
 	if !s.userDAL.HasCloudAnalyticsPermission(ctx, user) {
-		return nil, errors.New("user is not authorized")
+		return nil, ErrUserNotAuthorized
 	}
 
 	et := domain.EventType(req.EventType)
-	if !s.validator.Validate(ctx, et, req.ItemID, req.UserEmail) {
+	if !s.validator.Validate(ctx, et, req.ItemID, req.UserEmail, user) {
 		return nil, errors.New("invalid entity")
 	}
 
+	secret, err := dispatch.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
 	ws := &domain.WebhookSubscription{
 		Customer:  user.Customer.Ref,
 		UserEmail: req.UserEmail,
 		EventType: et,
 		ItemID:    req.ItemID,
 		TargetURL: req.TargetURL,
+		Secret:    secret,
 	}
 
 	id, err := s.dal.Create(ctx, ws)
@@ -80,27 +114,77 @@ func (s *WebhookService) CreateSubscription(ctx context.Context, req *CreateWebh
 		return nil, err
 	}
 
-	return &CreateWebhookResponse{SubscriptionID: id}, nil
+	return &CreateWebhookResponse{SubscriptionID: id, Secret: secret}, nil
 }
 
 func (s *WebhookService) DeleteSubscription(ctx context.Context, req *DeleteWebhookRequest) error {
 	// TODO implement doit user check
 	user, err := s.userDAL.GetUser(ctx, req.UserID)
 	if err != nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	if user.Customer.Ref == nil || user.Customer.Ref.ID != req.CustomerID {
-		return errors.New("user does not belong to this organization")
+		return ErrUserNotInOrganization
 	}
 
 	if !user.HasCloudAnalyticsPermission(ctx) {
-		return errors.New("user is not authorized")
+		return ErrUserNotAuthorized
 	}
 
 	return s.dal.Delete(ctx, req.SubscriptionID)
 }
 
+// ListDeliveries returns the latest deliveries of the customer webhooks, optionally filtered by subscription
+func (s *WebhookService) ListDeliveries(ctx context.Context, req *ListDeliveriesRequest) ([]*domain.WebhookDelivery, error) {
+	user, err := s.getAuthorizedUser(ctx, req.UserID, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > maxDeliveriesLimit {
+		limit = maxDeliveriesLimit
+	}
+
+	return s.deliveryDAL.List(ctx, user.Customer.Ref, req.SubscriptionID, limit)
+}
+
+// RedeliverDelivery sends a delivery of the customer webhooks again and returns its updated record
+func (s *WebhookService) RedeliverDelivery(ctx context.Context, req *RedeliverRequest) (*domain.WebhookDelivery, error) {
+	if _, err := s.getAuthorizedUser(ctx, req.UserID, req.CustomerID); err != nil {
+		return nil, err
+	}
+
+	d, err := s.deliveryDAL.Get(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Customer == nil || d.Customer.ID != req.CustomerID {
+		return nil, dal.ErrDeliveryNotFound
+	}
+
+	return s.dispatcher.Redeliver(ctx, d.ID)
+}
+
+func (s *WebhookService) getAuthorizedUser(ctx context.Context, userID, customerID string) (*common.User, error) {
+	user, err := s.userDAL.GetUser(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.Customer.Ref == nil || user.Customer.Ref.ID != customerID {
+		return nil, ErrUserNotInOrganization
+	}
+
+	if !user.HasCloudAnalyticsPermission(ctx) {
+		return nil, ErrUserNotAuthorized
+	}
+
+	return user, nil
+}
+
 func (s *WebhookService) GetAlertsMock() []alertService.WebhookAlertNotification {
 	alertConfig := &alertService.AlertConfigAPI{
 		Condition: "value",
@@ -210,3 +294,75 @@ func (s *WebhookService) GetBudgetsMock() []budgetsService.BudgetAPI {
 		},
 	}
 }
+
+func (s *WebhookService) GetBudgetForecastsMock() []budgetsService.WebhookBudgetForecastedDate {
+	budget := s.GetBudgetsMock()[0]
+	forecastedDate := time.Now().AddDate(0, 0, 12).UnixMilli()
+	previousForecastedDate := time.Now().AddDate(0, 0, 20).UnixMilli()
+
+	return []budgetsService.WebhookBudgetForecastedDate{
+		{
+			Budget:                 budget,
+			ForecastedDate:         &forecastedDate,
+			PreviousForecastedDate: &previousForecastedDate,
+		},
+	}
+}
+
+func (s *WebhookService) GetAnomaliesMock() []api.AnomalyItem {
+	return []api.AnomalyItem{
+		{
+			ID: "1Ljd7RwIEoV3CEuzv5Nc",
+			Anomaly: api.Anomaly{
+				BillingAccount: "01A2B3-C4D5E6-F7G8H9",
+				Attribution:    "",
+				CostOfAnomaly:  142.37,
+				CloudProvider:  "google-cloud",
+				Scope:          "doit-project",
+				ServiceName:    "BigQuery",
+				TopSKUs: api.AnomalySKUArray{
+					{
+						SKUName: "Analysis",
+						SKUCost: 151.02,
+					},
+				},
+				SeverityLevel: "warning",
+				TimeFrame:     "DAILY",
+				StartTime:     time.Now().Truncate(24 * time.Hour).UnixMilli(),
+			},
+		},
+	}
+}
+
+func (s *WebhookService) GetInvoicesMock() []invoices.WebhookInvoice {
+	now := time.Now()
+
+	return []invoices.WebhookInvoice{
+		{
+			ID:          "23000145",
+			InvoiceDate: now.UnixMilli(),
+			DueDate:     now.AddDate(0, 1, 0).UnixMilli(),
+			Products:    []string{"google-cloud"},
+			TotalAmount: 1250.5,
+			Currency:    "USD",
+			EntityID:    "cnrZ2kJLXOmOlnJY1tT4",
+			URL:         "https://console.doit.com/customers/customerID/invoices/cnrZ2kJLXOmOlnJY1tT4/23000145",
+		},
+	}
+}
+
+func (s *WebhookService) GetReportDeliveriesMock() []schedule.WebhookReportDelivery {
+	return []schedule.WebhookReportDelivery{
+		{
+			ReportID:   "0NhKmmWd7mAlGWcjaoJq",
+			ReportName: "Monthly costs",
+			Frequency:  "monthly",
+			Recipients: []string{
+				"test@test.com",
+			},
+			ImageURL:        "https://storage.googleapis.com/reports/0NhKmmWd7mAlGWcjaoJq.png",
+			AttachmentLinks: nil,
+			TimeDelivered:   time.Now().UnixMilli(),
+		},
+	}
+}
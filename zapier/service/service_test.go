@@ -14,11 +14,16 @@ import (
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/domain"
 	budgetMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/dal/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/collab"
+	reportMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/common"
+	entityMocks "github.com/doitintl/hello/scheduled-tasks/entity/dal/mocks"
 	"github.com/doitintl/hello/scheduled-tasks/framework/connection"
 	"github.com/doitintl/hello/scheduled-tasks/logger"
 	loggerMocks "github.com/doitintl/hello/scheduled-tasks/logger/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/dal"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/dal/mocks"
+	dispatchMocks "github.com/doitintl/hello/scheduled-tasks/zapier/dispatch/mocks"
+	zapierDomain "github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
 func TestNewWebhookSubscriptionService(t *testing.T) {
@@ -48,6 +53,8 @@ func TestWebhookSubscriptionService_Create(t *testing.T) {
 		userDAL            userMocks.UserDAL
 		alertsDAL          alertMocks.Alerts
 		budgetsDAL         budgetMocks.Budgets
+		entitiesDAL        entityMocks.Entites
+		reportDAL          reportMocks.Reports
 	}
 
 	type args struct {
@@ -157,6 +164,8 @@ func TestWebhookSubscriptionService_Create(t *testing.T) {
 				userDAL:            userMocks.UserDAL{},
 				alertsDAL:          alertMocks.Alerts{},
 				budgetsDAL:         budgetMocks.Budgets{},
+				entitiesDAL:        entityMocks.Entites{},
+				reportDAL:          reportMocks.Reports{},
 			}
 
 			s := &WebhookService{
@@ -166,7 +175,7 @@ func TestWebhookSubscriptionService_Create(t *testing.T) {
 				conn:      conn,
 				dal:       &tt.fields.dal,
 				userDAL:   &tt.fields.userDAL,
-				validator: NewEventValidator(&tt.fields.alertsDAL, &tt.fields.budgetsDAL),
+				validator: NewEventValidator(&tt.fields.alertsDAL, &tt.fields.budgetsDAL, &tt.fields.entitiesDAL, &tt.fields.reportDAL),
 			}
 
 			if tt.on != nil {
@@ -338,3 +347,75 @@ func TestWebhookSubscriptionService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookSubscriptionService_RedeliverDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		customerID = "fake-customer-ID"
+		userID     = "fake-user-ID"
+		deliveryID = "delivery-id"
+	)
+
+	user := &common.User{
+		ID:          userID,
+		Permissions: []string{string(common.PermissionCloudAnalytics)},
+		Customer: common.UserCustomer{
+			Ref: &firestore.DocumentRef{ID: customerID},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		on          func(*mocks.WebhookDeliveryDAL, *dispatchMocks.DeliveryDispatcher)
+		expectedErr error
+	}{
+		{
+			name: "successfully redelivered",
+			on: func(deliveryDAL *mocks.WebhookDeliveryDAL, dispatcher *dispatchMocks.DeliveryDispatcher) {
+				deliveryDAL.On("Get", ctx, deliveryID).Return(&zapierDomain.WebhookDelivery{
+					ID:       deliveryID,
+					Customer: &firestore.DocumentRef{ID: customerID},
+				}, nil)
+				dispatcher.On("Redeliver", ctx, deliveryID).Return(&zapierDomain.WebhookDelivery{ID: deliveryID}, nil)
+			},
+		},
+		{
+			name: "delivery of another customer",
+			on: func(deliveryDAL *mocks.WebhookDeliveryDAL, dispatcher *dispatchMocks.DeliveryDispatcher) {
+				deliveryDAL.On("Get", ctx, deliveryID).Return(&zapierDomain.WebhookDelivery{
+					ID:       deliveryID,
+					Customer: &firestore.DocumentRef{ID: "other-customer-ID"},
+				}, nil)
+			},
+			expectedErr: dal.ErrDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userDAL := userMocks.UserDAL{}
+			userDAL.On("GetUser", ctx, userID).Return(user, nil)
+
+			deliveryDAL := mocks.NewWebhookDeliveryDAL(t)
+			dispatcher := dispatchMocks.NewDeliveryDispatcher(t)
+
+			tt.on(deliveryDAL, dispatcher)
+
+			s := &WebhookService{
+				loggerProvider: logger.FromContext,
+				deliveryDAL:    deliveryDAL,
+				dispatcher:     dispatcher,
+				userDAL:        &userDAL,
+			}
+
+			_, err := s.RedeliverDelivery(ctx, &RedeliverRequest{
+				CustomerID: customerID,
+				UserID:     userID,
+				DeliveryID: deliveryID,
+			})
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -11,6 +11,7 @@ type CreateWebhookRequest struct {
 
 type CreateWebhookResponse struct {
 	SubscriptionID string `json:"id"`
+	Secret         string `json:"secret"`
 }
 
 type DeleteWebhookRequest struct {
@@ -19,3 +20,18 @@ type DeleteWebhookRequest struct {
 	UserEmail      string `json:"-"`
 	SubscriptionID string `json:"id" binding:"required"`
 }
+
+type ListDeliveriesRequest struct {
+	CustomerID     string `json:"-"`
+	UserID         string `json:"-"`
+	UserEmail      string `json:"-"`
+	SubscriptionID string `form:"subscriptionId"`
+	Limit          int    `form:"limit"`
+}
+
+type RedeliverRequest struct {
+	CustomerID string `json:"-"`
+	UserID     string `json:"-"`
+	UserEmail  string `json:"-"`
+	DeliveryID string `json:"-"`
+}
@@ -6,28 +6,46 @@ import (
 	alerts "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal/iface"
 	budgets "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/dal"
 	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/collab"
+	reports "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/iface"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	entities "github.com/doitintl/hello/scheduled-tasks/entity/dal"
 	"github.com/doitintl/hello/scheduled-tasks/zapier/domain"
 )
 
 type EventValidator struct {
-	alertDAL   alerts.Alerts
-	budgetsDAL budgets.Budgets
+	alertDAL    alerts.Alerts
+	budgetsDAL  budgets.Budgets
+	entitiesDAL entities.Entites
+	reportDAL   reports.Reports
 }
 
-func NewEventValidator(alertsDAL alerts.Alerts, budgetsDAL budgets.Budgets) *EventValidator {
+func NewEventValidator(
+	alertsDAL alerts.Alerts,
+	budgetsDAL budgets.Budgets,
+	entitiesDAL entities.Entites,
+	reportDAL reports.Reports,
+) *EventValidator {
 	return &EventValidator{
-		alertDAL:   alertsDAL,
-		budgetsDAL: budgetsDAL,
+		alertDAL:    alertsDAL,
+		budgetsDAL:  budgetsDAL,
+		entitiesDAL: entitiesDAL,
+		reportDAL:   reportDAL,
 	}
 }
 
 // Validate validates the event and it's permissions.
-func (v *EventValidator) Validate(ctx context.Context, event domain.EventType, entityID string, email string) bool {
+func (v *EventValidator) Validate(ctx context.Context, event domain.EventType, entityID string, email string, user *common.User) bool {
 	switch event {
 	case domain.AlertConditionSatisfied:
 		return v.validateAlert(ctx, entityID, email)
-	case domain.BudgetThresholdAchieved:
+	case domain.BudgetThresholdAchieved, domain.BudgetForecastedDateChanged:
 		return v.validateBudget(ctx, entityID, email)
+	case domain.CostAnomalyDetected:
+		return v.validateAnomalies(ctx, entityID, user)
+	case domain.InvoiceIssued:
+		return v.validateInvoices(ctx, entityID, user)
+	case domain.ReportScheduleDelivered:
+		return v.validateReport(ctx, entityID, email)
 	default:
 		return false
 	}
@@ -55,3 +73,39 @@ func (v *EventValidator) validateBudget(ctx context.Context, entityID string, em
 
 	return access.CanView(email)
 }
+
+// validateAnomalies checks that the anomalies subscription is for the user customer, anomalies are dispatched per customer
+func (v *EventValidator) validateAnomalies(ctx context.Context, customerID string, user *common.User) bool {
+	if user == nil || user.Customer.Ref == nil || user.Customer.Ref.ID != customerID {
+		return false
+	}
+
+	return user.HasAnomaliesViewerPermission(ctx)
+}
+
+// validateInvoices checks that the billing profile belongs to the user customer
+func (v *EventValidator) validateInvoices(ctx context.Context, entityID string, user *common.User) bool {
+	if user == nil || user.Customer.Ref == nil {
+		return false
+	}
+
+	e, err := v.entitiesDAL.GetEntity(ctx, entityID)
+	if err != nil {
+		return false
+	}
+
+	if e.Customer == nil || e.Customer.ID != user.Customer.Ref.ID {
+		return false
+	}
+
+	return user.HasInvoicesPermission(ctx)
+}
+
+func (v *EventValidator) validateReport(ctx context.Context, entityID string, email string) bool {
+	r, err := v.reportDAL.Get(ctx, entityID)
+	if err != nil {
+		return false
+	}
+
+	return r.CanView(email)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+
+	alertMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/alerts/dal/mocks"
+	budgetMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/budgets/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/collab"
+	reportMocks "github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/cloudanalytics/reports/domain/report"
+	"github.com/doitintl/hello/scheduled-tasks/common"
+	entityMocks "github.com/doitintl/hello/scheduled-tasks/entity/dal/mocks"
+	"github.com/doitintl/hello/scheduled-tasks/zapier/domain"
+)
+
+func TestEventValidator_Validate(t *testing.T) {
+	type fields struct {
+		alertsDAL   alertMocks.Alerts
+		budgetsDAL  budgetMocks.Budgets
+		entitiesDAL entityMocks.Entites
+		reportDAL   reportMocks.Reports
+	}
+
+	ctx := context.Background()
+
+	var (
+		customerID = "fake-customer-ID"
+		entityID   = "fake-entity-ID"
+		reportID   = "fake-report-ID"
+		email      = "fake@test.com"
+	)
+
+	userWithPermissions := func(permissions ...common.Permission) *common.User {
+		user := &common.User{
+			Customer: common.UserCustomer{
+				Ref: &firestore.DocumentRef{ID: customerID},
+			},
+		}
+
+		for _, p := range permissions {
+			user.Permissions = append(user.Permissions, string(p))
+		}
+
+		return user
+	}
+
+	tests := []struct {
+		name     string
+		event    domain.EventType
+		entityID string
+		user     *common.User
+		on       func(*fields)
+		want     bool
+	}{
+		{
+			name:     "anomalies of the user customer",
+			event:    domain.CostAnomalyDetected,
+			entityID: customerID,
+			user:     userWithPermissions(common.PermissionAnomaliesViewer),
+			want:     true,
+		},
+		{
+			name:     "anomalies of another customer",
+			event:    domain.CostAnomalyDetected,
+			entityID: "other-customer-ID",
+			user:     userWithPermissions(common.PermissionAnomaliesViewer),
+			want:     false,
+		},
+		{
+			name:     "anomalies without anomalies permission",
+			event:    domain.CostAnomalyDetected,
+			entityID: customerID,
+			user:     userWithPermissions(common.PermissionCloudAnalytics),
+			want:     false,
+		},
+		{
+			name:     "invoices of a billing profile of the user customer",
+			event:    domain.InvoiceIssued,
+			entityID: entityID,
+			user:     userWithPermissions(common.PermissionInvoices),
+			on: func(f *fields) {
+				f.entitiesDAL.On("GetEntity", ctx, entityID).Return(&common.Entity{
+					Customer: &firestore.DocumentRef{ID: customerID},
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name:     "invoices of a billing profile of another customer",
+			event:    domain.InvoiceIssued,
+			entityID: entityID,
+			user:     userWithPermissions(common.PermissionInvoices),
+			on: func(f *fields) {
+				f.entitiesDAL.On("GetEntity", ctx, entityID).Return(&common.Entity{
+					Customer: &firestore.DocumentRef{ID: "other-customer-ID"},
+				}, nil)
+			},
+			want: false,
+		},
+		{
+			name:     "invoices of a billing profile that does not exist",
+			event:    domain.InvoiceIssued,
+			entityID: entityID,
+			user:     userWithPermissions(common.PermissionInvoices),
+			on: func(f *fields) {
+				f.entitiesDAL.On("GetEntity", ctx, entityID).Return(nil, errors.New("not found"))
+			},
+			want: false,
+		},
+		{
+			name:     "report shared with the user",
+			event:    domain.ReportScheduleDelivered,
+			entityID: reportID,
+			user:     userWithPermissions(common.PermissionCloudAnalytics),
+			on: func(f *fields) {
+				f.reportDAL.On("Get", ctx, reportID).Return(&report.Report{
+					Access: collab.Access{
+						Collaborators: []collab.Collaborator{
+							{
+								Email: email,
+								Role:  collab.CollaboratorRoleViewer,
+							},
+						},
+					},
+				}, nil)
+			},
+			want: true,
+		},
+		{
+			name:     "report not shared with the user",
+			event:    domain.ReportScheduleDelivered,
+			entityID: reportID,
+			user:     userWithPermissions(common.PermissionCloudAnalytics),
+			on: func(f *fields) {
+				f.reportDAL.On("Get", ctx, reportID).Return(&report.Report{}, nil)
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fields{}
+
+			if tt.on != nil {
+				tt.on(f)
+			}
+
+			v := NewEventValidator(&f.alertsDAL, &f.budgetsDAL, &f.entitiesDAL, &f.reportDAL)
+
+			got := v.Validate(ctx, tt.event, tt.entityID, email, tt.user)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}